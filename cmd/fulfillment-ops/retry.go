@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+)
+
+// runRetryFailed executa o subcomando "retry-failed": reexecuta, via API do serviço,
+// todas as operações FAILED desde -since. Retorna o código de saída.
+func runRetryFailed(args []string) int {
+	fs := flag.NewFlagSet("retry-failed", flag.ContinueOnError)
+	since := fs.String("since", "", "reexecuta falhas desde este instante (RFC3339, obrigatório)")
+	entity := fs.String("entity", "", "limita a inbound|orders|transfers|returns|cycle_counts")
+	actor := fs.String("actor", os.Getenv("USER"), "operador registrado no histórico de status")
+	apiURL := fs.String("api", getEnv("FULFILLMENT_API_URL", "http://localhost:8080"), "URL base do fulfillment-ops")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if _, err := time.Parse(time.RFC3339, *since); err != nil {
+		fmt.Fprintln(os.Stderr, "usage: fulfillment-ops retry-failed -since <RFC3339> [-entity <entity>] [-actor <name>] [-api <url>]")
+		return 2
+	}
+
+	query := url.Values{"since": {*since}}
+	if *entity != "" {
+		query.Set("entity", *entity)
+	}
+	req, err := http.NewRequest(http.MethodPost, *apiURL+"/v1/retry?"+query.Encode(), nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create request: %v\n", err)
+		return 1
+	}
+	req.Header.Set("X-Actor", *actor)
+
+	client := &http.Client{Timeout: getEnvDuration("RETRY_TIMEOUT", 10*time.Minute)}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to call fulfillment-ops: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Retry failed with status %d: %s\n", resp.StatusCode, string(body))
+		return 1
+	}
+
+	var report app.RetryReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode retry report: %v\n", err)
+		return 1
+	}
+
+	for _, result := range report.Results {
+		state := "ok"
+		if !result.Succeeded {
+			state = "failed: " + result.Error
+		}
+		fmt.Printf("%-20s %s  %s\n", result.EntityType, result.EntityID, state)
+	}
+	fmt.Printf("attempted %d, succeeded %d, failed %d\n", report.Attempted, report.Succeeded, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,404 @@
+// Package eventsourcing persiste agregados de fulfillment como streams de eventos de domínio
+// no EventStore de internal/state/events, mantendo o Repository relacional como projeção.
+package eventsourcing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/state/events"
+)
+
+// DefaultSnapshotEvery define a cada quantos eventos um snapshot é gravado
+const DefaultSnapshotEvery = 20
+
+// Chaves de metadata gravadas em cada evento
+const (
+	MetadataEventName  = "event_name"
+	MetadataFromStatus = "from_status"
+	MetadataToStatus   = "to_status"
+)
+
+// EventSourcedRepository implementa fulfillment.Repository e fulfillment.HistoryRepository.
+// FulfillmentOrder, TransferOrder e ReturnOrder têm cada transição gravada como evento
+// (com verificação de versão esperada) antes de atualizar a projeção relacional; o stream é a
+// fonte da verdade e uma projeção que ficou atrás dele é regravada a partir do último evento.
+// As demais entidades são delegadas diretamente à projeção.
+type EventSourcedRepository struct {
+	fulfillment.Repository // Projeção (linhas Postgres)
+
+	store         events.EventStore
+	nodeID        string
+	snapshotEvery int64
+}
+
+// NewEventSourcedRepository cria o repositório event-sourced sobre uma projeção existente
+func NewEventSourcedRepository(projection fulfillment.Repository, store events.EventStore, nodeID string) *EventSourcedRepository {
+	return &EventSourcedRepository{
+		Repository:    projection,
+		store:         store,
+		nodeID:        nodeID,
+		snapshotEvery: DefaultSnapshotEvery,
+	}
+}
+
+// Outbound
+
+func (r *EventSourcedRepository) CreateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	created, err := r.appendCreate(ctx, fulfillment.AggregateFulfillmentOrder, order.ID, order.Status, &order.Version, order)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return restoreProjection(ctx, r.store, order.ID, r.Repository.GetOrderByID, r.Repository.CreateOrder, fulfillment.ErrOrderNotFound)
+	}
+	return r.Repository.CreateOrder(ctx, order)
+}
+
+func (r *EventSourcedRepository) UpdateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	if err := r.appendTransition(ctx, fulfillment.AggregateFulfillmentOrder, order.ID, order.Status, &order.Version, order); err != nil {
+		if errors.Is(err, fulfillment.ErrConcurrencyConflict) {
+			// A linha pode ter ficado atrás do stream; após o reparo a releitura do chamador avança
+			_ = rebuildProjection(ctx, r.store, order.ID, r.Repository.GetOrderByID, r.Repository.UpdateOrder, func(a *fulfillment.FulfillmentOrder) int64 { return a.Version })
+		}
+		return err
+	}
+	if err := r.Repository.UpdateOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update projection (stream at version %d): %w", order.Version, err)
+	}
+	return nil
+}
+
+func (r *EventSourcedRepository) UpdateOrderStatus(ctx context.Context, id string, status fulfillment.Status) error {
+	order, err := r.Repository.GetOrderByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	return r.UpdateOrder(ctx, order)
+}
+
+// GetOrderAt reconstrói a FulfillmentOrder como estava no instante informado
+func (r *EventSourcedRepository) GetOrderAt(ctx context.Context, id string, at time.Time) (*fulfillment.FulfillmentOrder, error) {
+	return loadAt[fulfillment.FulfillmentOrder](ctx, r.store, id, at)
+}
+
+// Transfer
+
+func (r *EventSourcedRepository) CreateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	created, err := r.appendCreate(ctx, fulfillment.AggregateTransferOrder, transfer.ID, transfer.Status, &transfer.Version, transfer)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return restoreProjection(ctx, r.store, transfer.ID, r.Repository.GetTransferByID, r.Repository.CreateTransfer, fulfillment.ErrTransferNotFound)
+	}
+	return r.Repository.CreateTransfer(ctx, transfer)
+}
+
+func (r *EventSourcedRepository) UpdateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	if err := r.appendTransition(ctx, fulfillment.AggregateTransferOrder, transfer.ID, transfer.Status, &transfer.Version, transfer); err != nil {
+		if errors.Is(err, fulfillment.ErrConcurrencyConflict) {
+			// A linha pode ter ficado atrás do stream; após o reparo a releitura do chamador avança
+			_ = rebuildProjection(ctx, r.store, transfer.ID, r.Repository.GetTransferByID, r.Repository.UpdateTransfer, func(a *fulfillment.TransferOrder) int64 { return a.Version })
+		}
+		return err
+	}
+	if err := r.Repository.UpdateTransfer(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to update projection (stream at version %d): %w", transfer.Version, err)
+	}
+	return nil
+}
+
+func (r *EventSourcedRepository) UpdateTransferStatus(ctx context.Context, id string, status fulfillment.Status) error {
+	transfer, err := r.Repository.GetTransferByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	transfer.Status = status
+	transfer.UpdatedAt = time.Now()
+	return r.UpdateTransfer(ctx, transfer)
+}
+
+// GetTransferAt reconstrói a TransferOrder como estava no instante informado
+func (r *EventSourcedRepository) GetTransferAt(ctx context.Context, id string, at time.Time) (*fulfillment.TransferOrder, error) {
+	return loadAt[fulfillment.TransferOrder](ctx, r.store, id, at)
+}
+
+// Return
+
+func (r *EventSourcedRepository) CreateReturn(ctx context.Context, returnOrder *fulfillment.ReturnOrder) error {
+	created, err := r.appendCreate(ctx, fulfillment.AggregateReturnOrder, returnOrder.ID, returnOrder.Status, &returnOrder.Version, returnOrder)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return restoreProjection(ctx, r.store, returnOrder.ID, r.Repository.GetReturnByID, r.Repository.CreateReturn, fulfillment.ErrReturnNotFound)
+	}
+	return r.Repository.CreateReturn(ctx, returnOrder)
+}
+
+func (r *EventSourcedRepository) UpdateReturn(ctx context.Context, returnOrder *fulfillment.ReturnOrder) error {
+	if err := r.appendTransition(ctx, fulfillment.AggregateReturnOrder, returnOrder.ID, returnOrder.Status, &returnOrder.Version, returnOrder); err != nil {
+		if errors.Is(err, fulfillment.ErrConcurrencyConflict) {
+			// A linha pode ter ficado atrás do stream; após o reparo a releitura do chamador avança
+			_ = rebuildProjection(ctx, r.store, returnOrder.ID, r.Repository.GetReturnByID, r.Repository.UpdateReturn, func(a *fulfillment.ReturnOrder) int64 { return a.Version })
+		}
+		return err
+	}
+	if err := r.Repository.UpdateReturn(ctx, returnOrder); err != nil {
+		return fmt.Errorf("failed to update projection (stream at version %d): %w", returnOrder.Version, err)
+	}
+	return nil
+}
+
+func (r *EventSourcedRepository) UpdateReturnStatus(ctx context.Context, id string, status fulfillment.Status) error {
+	returnOrder, err := r.Repository.GetReturnByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	returnOrder.Status = status
+	returnOrder.UpdatedAt = time.Now()
+	return r.UpdateReturn(ctx, returnOrder)
+}
+
+// GetReturnAt reconstrói a ReturnOrder como estava no instante informado
+func (r *EventSourcedRepository) GetReturnAt(ctx context.Context, id string, at time.Time) (*fulfillment.ReturnOrder, error) {
+	return loadAt[fulfillment.ReturnOrder](ctx, r.store, id, at)
+}
+
+// appendCreate grava o evento de criação. Retorna false quando o agregado já existe
+// (reentrega idempotente), caso em que a projeção só é criada se estiver faltando.
+func (r *EventSourcedRepository) appendCreate(ctx context.Context, aggregateType, id string, status fulfillment.Status, version *int64, state interface{}) (bool, error) {
+	current, _, err := r.head(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if current > 0 {
+		return false, nil
+	}
+
+	if err := r.append(ctx, aggregateType, id, 0, "", status, version, state); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// appendTransition grava a transição verificando que o agregado está na versão esperada
+func (r *EventSourcedRepository) appendTransition(ctx context.Context, aggregateType, id string, status fulfillment.Status, version *int64, state interface{}) error {
+	current, from, err := r.head(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if current != *version {
+		return fmt.Errorf("%w: %s %s expected version %d, store has %d", fulfillment.ErrConcurrencyConflict, aggregateType, id, *version, current)
+	}
+
+	return r.append(ctx, aggregateType, id, current, from, status, version, state)
+}
+
+func (r *EventSourcedRepository) append(ctx context.Context, aggregateType, id string, expected int64, from, to fulfillment.Status, version *int64, state interface{}) error {
+	*version = expected + 1
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		*version = expected
+		return fmt.Errorf("failed to marshal aggregate state: %w", err)
+	}
+
+	eventType := events.EventTypeUpdate
+	if expected == 0 {
+		eventType = events.EventTypeCreate
+	}
+
+	event := &events.Event{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		AggregateID:   id,
+		AggregateType: aggregateType,
+		Version:       *version,
+		Data:          json.RawMessage(data),
+		Metadata: map[string]interface{}{
+			MetadataEventName:  fulfillment.TransitionEventName(aggregateType, from, to),
+			MetadataFromStatus: string(from),
+			MetadataToStatus:   string(to),
+		},
+		Timestamp:     time.Now().UTC(),
+		NodeID:        r.nodeID,
+		CorrelationID: fulfillment.CorrelationIDFromContext(ctx),
+	}
+
+	if err := r.store.SaveEvents(ctx, []*events.Event{event}); err != nil {
+		*version = expected
+		if errors.Is(err, events.ErrVersionConflict) {
+			return fmt.Errorf("%w: %w", fulfillment.ErrConcurrencyConflict, err)
+		}
+		return fmt.Errorf("failed to append %s event: %w", aggregateType, err)
+	}
+
+	if *version%r.snapshotEvery == 0 {
+		// Snapshot é otimização de leitura: falha não invalida a transição
+		_ = r.store.CreateSnapshot(ctx, id, *version, json.RawMessage(data))
+	}
+
+	return nil
+}
+
+// head retorna a versão e o último status gravados para o agregado (0 se não há eventos)
+func (r *EventSourcedRepository) head(ctx context.Context, id string) (int64, fulfillment.Status, error) {
+	info, err := r.store.GetAggregateInfo(ctx, id)
+	if errors.Is(err, events.ErrAggregateNotFound) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read aggregate info: %w", err)
+	}
+	if info == nil || info.Version == 0 {
+		return 0, "", nil
+	}
+
+	last, err := r.store.GetEvents(ctx, id, info.Version, info.Version)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read aggregate head: %w", err)
+	}
+	if len(last) == 0 {
+		return info.Version, "", nil
+	}
+
+	status, _ := last[0].Metadata[MetadataToStatus].(string)
+	return info.Version, fulfillment.Status(status), nil
+}
+
+// rebuildProjection regrava a projeção com o estado do último evento do stream quando a linha ficou
+// atrás dele (escrita da projeção falhou depois do append). Sem o reparo, toda atualização seguinte
+// do agregado seria rejeitada com ErrConcurrencyConflict, pois a linha nunca alcançaria o stream.
+func rebuildProjection[T any](ctx context.Context, store events.EventStore, id string,
+	get func(context.Context, string) (*T, error), update func(context.Context, *T) error, version func(*T) int64) error {
+	projected, err := get(ctx, id)
+	if err != nil {
+		return err
+	}
+	latest, err := load[T](ctx, store, id, nil)
+	if err != nil {
+		return err
+	}
+	if version(projected) >= version(latest) {
+		return nil
+	}
+	return update(ctx, latest)
+}
+
+// restoreProjection cria a linha da projeção a partir do stream quando o agregado já tem eventos, mas a
+// linha não existe (a criação na projeção falhou depois do append). Sem o reparo, toda reentrega da
+// criação retornaria sucesso sem que a linha jamais fosse criada.
+func restoreProjection[T any](ctx context.Context, store events.EventStore, id string,
+	get func(context.Context, string) (*T, error), create func(context.Context, *T) error, notFound error) error {
+	_, err := get(ctx, id)
+	if !errors.Is(err, notFound) {
+		return err
+	}
+	latest, err := load[T](ctx, store, id, nil)
+	if err != nil {
+		return err
+	}
+	return create(ctx, latest)
+}
+
+// loadAt reconstrói o agregado a partir do último snapshot anterior a "at" mais os eventos seguintes
+func loadAt[T any](ctx context.Context, store events.EventStore, id string, at time.Time) (*T, error) {
+	return load[T](ctx, store, id, &at)
+}
+
+// load reconstrói o agregado até "at" (nil = último evento do stream)
+func load[T any](ctx context.Context, store events.EventStore, id string, at *time.Time) (*T, error) {
+	var state *T
+	fromVersion := int64(1)
+
+	if snapshot, err := store.GetSnapshot(ctx, id); err == nil && snapshot != nil && (at == nil || !snapshot.CreatedAt.After(*at)) {
+		state = new(T)
+		if err := decodeEventData(snapshot.Data, state); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+		fromVersion = snapshot.Version + 1
+	}
+
+	stream, err := store.GetEvents(ctx, id, fromVersion, math.MaxInt64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	// Cada evento carrega o estado completo: com eventos removidos por compactação ou retenção,
+	// basta que o primeiro evento retido seja anterior a "at"
+	if len(stream) == 0 || (stream[0].Version > fromVersion && at != nil && stream[0].Timestamp.After(*at)) {
+		if err := checkRetained(ctx, store, id, fromVersion, at); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, event := range stream {
+		if at != nil && event.Timestamp.After(*at) {
+			break
+		}
+		next := new(T)
+		if err := decodeEventData(event.Data, next); err != nil {
+			return nil, fmt.Errorf("failed to decode event %s: %w", event.ID, err)
+		}
+		state = next
+	}
+
+	if state == nil {
+		return nil, fulfillment.ErrAggregateNotFound
+	}
+	return state, nil
+}
+
+// checkRetained retorna ErrHistoryPruned quando o estado pedido estava em eventos já removidos do
+// stream (a versão do agregado passa de fromVersion e "at" não é anterior à criação)
+func checkRetained(ctx context.Context, store events.EventStore, id string, fromVersion int64, at *time.Time) error {
+	info, err := store.GetAggregateInfo(ctx, id)
+	if errors.Is(err, events.ErrAggregateNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read aggregate info: %w", err)
+	}
+	if info.Version < fromVersion {
+		return nil
+	}
+	if at != nil && info.FirstEvent != nil && at.Before(*info.FirstEvent) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", fulfillment.ErrHistoryPruned, id)
+}
+
+// decodeEventData aceita tanto o payload original (json.RawMessage) quanto o
+// decodificado por stores persistentes (map/[]byte/string)
+func decodeEventData(data interface{}, into interface{}) error {
+	var raw []byte
+	switch v := data.(type) {
+	case json.RawMessage:
+		raw = v
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	case nil:
+		return errors.New("empty event data")
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		raw = encoded
+	}
+	return json.Unmarshal(raw, into)
+}
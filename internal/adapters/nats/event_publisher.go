@@ -153,6 +153,86 @@ func (p *EventPublisher) PublishCycleCountCompleted(ctx context.Context, task *f
 	return p.publishEvent(ctx, "fulfillment.cycle_count.completed.v1", event)
 }
 
+// PublishBlockEscalated publica alerta de operação bloqueada além do limite de escalonamento
+func (p *EventPublisher) PublishBlockEscalated(ctx context.Context, entity fulfillment.BlockedEntity) error {
+	event := map[string]interface{}{
+		"entity_type":   entity.EntityType,
+		"entity_id":     entity.EntityID,
+		"reason_code":   entity.Block.Reason,
+		"note":          entity.Block.Note,
+		"blocked_by":    entity.Block.BlockedBy,
+		"blocked_at":    entity.Block.BlockedAt,
+		"blocked_for":   time.Since(entity.Block.BlockedAt).String(),
+		"timestamp":     time.Now().UTC(),
+		"event_version": "v1",
+	}
+
+	return p.publishEvent(ctx, "fulfillment.block.escalated.v1", event)
+}
+
+// PublishAppointmentScheduled publica evento de agendamento de doca criado
+func (p *EventPublisher) PublishAppointmentScheduled(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.publishEvent(ctx, "fulfillment.dock.appointment.scheduled.v1", appointmentEvent(appointment))
+}
+
+// PublishAppointmentArrived publica evento de chegada à doca (late_minutes > 0 indica atraso)
+func (p *EventPublisher) PublishAppointmentArrived(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.publishEvent(ctx, "fulfillment.dock.appointment.arrived.v1", appointmentEvent(appointment))
+}
+
+// PublishAppointmentCompleted publica evento de atendimento na doca encerrado
+func (p *EventPublisher) PublishAppointmentCompleted(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.publishEvent(ctx, "fulfillment.dock.appointment.completed.v1", appointmentEvent(appointment))
+}
+
+// PublishAppointmentCancelled publica evento de agendamento de doca cancelado
+func (p *EventPublisher) PublishAppointmentCancelled(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.publishEvent(ctx, "fulfillment.dock.appointment.cancelled.v1", appointmentEvent(appointment))
+}
+
+// PublishAppointmentNoShow publica evento de não comparecimento ao agendamento
+func (p *EventPublisher) PublishAppointmentNoShow(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.publishEvent(ctx, "fulfillment.dock.appointment.no_show.v1", appointmentEvent(appointment))
+}
+
+func appointmentEvent(appointment *fulfillment.DockAppointment) map[string]interface{} {
+	return map[string]interface{}{
+		"appointment_id":  appointment.ID,
+		"door_id":         appointment.DoorID,
+		"direction":       appointment.Direction,
+		"shipment_type":   appointment.ShipmentType,
+		"shipment_id":     appointment.ShipmentID,
+		"supplier":        appointment.Supplier,
+		"carrier":         appointment.Carrier,
+		"scheduled_start": appointment.ScheduledStart,
+		"scheduled_end":   appointment.ScheduledEnd,
+		"status":          appointment.Status,
+		"arrived_at":      appointment.ArrivedAt,
+		"late_minutes":    appointment.LateMinutes,
+		"timestamp":       time.Now().UTC(),
+		"event_version":   "v1",
+	}
+}
+
+// PublishManifestClosed publica evento de manifesto de transportadora fechado para a entrega
+func (p *EventPublisher) PublishManifestClosed(ctx context.Context, manifest *fulfillment.CarrierManifest, trackingNumbers []string) error {
+	event := map[string]interface{}{
+		"manifest_id":      manifest.ID,
+		"carrier":          manifest.Carrier,
+		"date":             manifest.Date.Format(time.DateOnly),
+		"shipments":        manifest.Shipments,
+		"pieces":           manifest.Pieces,
+		"weight":           manifest.Weight,
+		"tracking_numbers": trackingNumbers,
+		"closed_at":        manifest.ClosedAt,
+		"closed_by":        manifest.ClosedBy,
+		"timestamp":        time.Now().UTC(),
+		"event_version":    "v1",
+	}
+
+	return p.publishEvent(ctx, "fulfillment.manifest.closed.v1", event)
+}
+
 // publishEvent publica um evento no NATS JetStream
 func (p *EventPublisher) publishEvent(ctx context.Context, subject string, payload interface{}) error {
 	data, err := json.Marshal(payload)
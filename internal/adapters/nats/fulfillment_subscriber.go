@@ -37,7 +37,13 @@ type OrderReadyToPickEvent struct {
 	Customer    string `json:"customer_name"`
 	Destination string `json:"shipping_address"`
 	Priority    int    `json:"priority"`
-	Items       []struct {
+	// Prazos prometidos (opcionais)
+	ShipBy       *time.Time               `json:"ship_by"`
+	DeliverBy    *time.Time               `json:"deliver_by"`
+	Carrier      string                   `json:"carrier"`
+	Service      string                   `json:"service"`
+	CustomerTier fulfillment.CustomerTier `json:"customer_tier"`
+	Items        []struct {
 		SKU      string `json:"sku"`
 		Quantity int    `json:"quantity"`
 	} `json:"items"`
@@ -122,6 +128,12 @@ func (s *FulfillmentSubscriber) handleOrderEvent(ctx context.Context, msg jetstr
 	}
 
 	s.logger.Info("Receiving Order", zap.String("order_id", event.OrderID))
+	ctx = fulfillment.WithCorrelationID(ctx, event.Metadata.TraceID)
+	actor := event.Metadata.Source
+	if actor == "" {
+		actor = "oms"
+	}
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceNATS), actor)
 
 	// Mapear para domínio
 	domainItems := make([]fulfillment.Item, len(event.Items))
@@ -133,7 +145,14 @@ func (s *FulfillmentSubscriber) handleOrderEvent(ctx context.Context, msg jetstr
 	}
 
 	// Criar FulfillmentOrder via caso de uso
-	_, err := s.useCase.CreateOrder(ctx, event.OrderID, event.Customer, event.Destination, domainItems, event.Priority)
+	promise := fulfillment.ShipPromise{
+		ShipBy:       event.ShipBy,
+		DeliverBy:    event.DeliverBy,
+		Carrier:      event.Carrier,
+		Service:      event.Service,
+		CustomerTier: event.CustomerTier,
+	}
+	_, err := s.useCase.CreateOrderWithPromise(ctx, event.OrderID, event.Customer, event.Destination, domainItems, event.Priority, promise)
 	if err != nil {
 		return fmt.Errorf("failed to create fulfillment order: %w", err)
 	}
@@ -64,7 +64,7 @@ func (c *InventoryCommandClient) AdjustStock(ctx context.Context, location strin
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("core inventory returned status %d: %s", resp.StatusCode, string(body))
+		return statusError(resp.StatusCode, body)
 	}
 
 	c.logger.Info("Stock adjusted successfully", zap.String("location", location), zap.String("sku", sku), zap.Int("quantity", quantity))
@@ -98,13 +98,44 @@ func (c *InventoryCommandClient) ConfirmReservation(ctx context.Context, orderID
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("core inventory returned status %d: %s", resp.StatusCode, string(body))
+		return statusError(resp.StatusCode, body)
 	}
 
 	c.logger.Info("Reservation confirmed successfully", zap.String("order_id", orderID))
 	return nil
 }
 
+// ReserveStock reserva os itens da ordem no Core Inventory até expiresAt
+func (c *InventoryCommandClient) ReserveStock(ctx context.Context, orderID string, items []fulfillment.Item, expiresAt time.Time) error {
+	reqBody := map[string]interface{}{
+		"order_id":   orderID,
+		"items":      items,
+		"expires_at": expiresAt.UTC(),
+	}
+
+	if err := c.postJSON(ctx, "/v1/reserve", reqBody); err != nil {
+		return err
+	}
+
+	c.logger.Info("Stock reserved successfully", zap.String("order_id", orderID), zap.Time("expires_at", expiresAt))
+	return nil
+}
+
+// ReleaseReservation libera a reserva da ordem no Core Inventory
+func (c *InventoryCommandClient) ReleaseReservation(ctx context.Context, orderID string, reason string) error {
+	reqBody := map[string]interface{}{
+		"order_id": orderID,
+		"reason":   reason,
+	}
+
+	if err := c.postJSON(ctx, "/v1/reserve/release", reqBody); err != nil {
+		return err
+	}
+
+	c.logger.Info("Reservation released successfully", zap.String("order_id", orderID), zap.String("reason", reason))
+	return nil
+}
+
 // GetAvailableStock obtém o estoque disponível do Core Inventory
 func (c *InventoryCommandClient) GetAvailableStock(ctx context.Context, location string, sku string) (int, error) {
 	url := fmt.Sprintf("%s/v1/available?location=%s&sku=%s", c.baseURL, location, sku)
@@ -122,7 +153,7 @@ func (c *InventoryCommandClient) GetAvailableStock(ctx context.Context, location
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("core inventory returned status %d: %s", resp.StatusCode, string(body))
+		return 0, statusError(resp.StatusCode, body)
 	}
 
 	var result struct {
@@ -135,3 +166,47 @@ func (c *InventoryCommandClient) GetAvailableStock(ctx context.Context, location
 
 	return result.Available, nil
 }
+
+// postJSON envia um comando JSON ao Core Inventory; 409 indica saldo insuficiente
+func (c *InventoryCommandClient) postJSON(ctx context.Context, path string, reqBody interface{}) error {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if correlationID := fulfillment.CorrelationIDFromContext(ctx); correlationID != "" {
+		req.Header.Set(HeaderCorrelationID, correlationID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call core inventory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", fulfillment.ErrInsufficientStock, string(body))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// statusError converte uma resposta HTTP de erro; 5xx e 429 são falhas transitórias
+func statusError(statusCode int, body []byte) error {
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: core inventory returned status %d: %s", fulfillment.ErrTransientFailure, statusCode, string(body))
+	}
+	return fmt.Errorf("core inventory returned status %d: %s", statusCode, string(body))
+}
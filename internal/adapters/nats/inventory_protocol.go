@@ -0,0 +1,54 @@
+package nats
+
+import (
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// Subjects de comando/consulta do mcp-core-inventory
+const (
+	SubjectInventoryAdjust             = "inventory.command.adjust.v1"
+	SubjectInventoryReserve            = "inventory.command.reservation.create.v1"
+	SubjectInventoryConfirmReservation = "inventory.command.reservation.confirm.v1"
+	SubjectInventoryReleaseReservation = "inventory.command.reservation.release.v1"
+	SubjectInventoryAvailable          = "inventory.query.available.v1"
+
+	// StreamInventoryCommands é o stream JetStream dos comandos de escrita (o reserve é sempre request-reply)
+	StreamInventoryCommands = "INVENTORY_COMMANDS"
+
+	// HeaderCorrelationID propaga o ID de correlação entre serviços
+	HeaderCorrelationID = "X-Correlation-ID"
+)
+
+// Status de resposta dos comandos de inventário
+const (
+	replyStatusOK    = "ok"
+	replyStatusError = "error"
+
+	// Código de erro para saldo insuficiente
+	replyCodeInsufficientStock = "insufficient_stock"
+)
+
+// InventoryCommand é o envelope enviado ao Core Inventory via NATS
+type InventoryCommand struct {
+	CommandID     string             `json:"command_id,omitempty"` // Estável entre reexecuções da mesma etapa (deduplicação)
+	CorrelationID string             `json:"correlation_id"`
+	Location      string             `json:"location,omitempty"`
+	SKU           string             `json:"sku,omitempty"`
+	Quantity      int                `json:"quantity,omitempty"`
+	Batch         string             `json:"batch,omitempty"`
+	Reason        string             `json:"reason,omitempty"`
+	OrderID       string             `json:"order_id,omitempty"`
+	Items         []fulfillment.Item `json:"items,omitempty"`
+	ExpiresAt     *time.Time         `json:"expires_at,omitempty"`
+}
+
+// InventoryReply é a resposta do Core Inventory a um comando ou consulta
+type InventoryReply struct {
+	CorrelationID string `json:"correlation_id"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+	Available     int    `json:"available,omitempty"`
+}
@@ -0,0 +1,233 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// Requester abstrai o transporte request-reply (nats.Conn ou responder local)
+type Requester interface {
+	RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error)
+}
+
+// InventoryRequestClient implementa o contrato InventoryClient sobre NATS request-reply.
+// Quando um JetStream é informado, comandos de escrita (adjust/confirm/release) são publicados
+// de forma durável. Nos dois transportes cada comando de escrita leva um command_id, derivado da
+// etapa da operação (fulfillment.WithCommandID) para que a reexecução seja deduplicada pelo Core;
+// o ID de correlação, comum a todos os comandos de uma operação, não identifica o comando.
+type InventoryRequestClient struct {
+	requester Requester
+	js        jetstream.JetStream
+	timeout   time.Duration
+	logger    Logger
+}
+
+// NewInventoryRequestClient cria um cliente de inventário via NATS request-reply
+func NewInventoryRequestClient(requester Requester, timeout time.Duration, logger Logger) *InventoryRequestClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &InventoryRequestClient{
+		requester: requester,
+		timeout:   timeout,
+		logger:    logger,
+	}
+}
+
+// NewInventoryJetStreamClient cria um cliente que envia comandos via JetStream
+// e consultas via request-reply
+func NewInventoryJetStreamClient(requester Requester, js jetstream.JetStream, timeout time.Duration, logger Logger) *InventoryRequestClient {
+	c := NewInventoryRequestClient(requester, timeout, logger)
+	c.js = js
+	return c
+}
+
+// AdjustStock ajusta o estoque no Core Inventory
+func (c *InventoryRequestClient) AdjustStock(ctx context.Context, location string, sku string, quantity int, batch string) error {
+	cmd := &InventoryCommand{
+		Location: location,
+		SKU:      sku,
+		Quantity: quantity,
+		Batch:    batch,
+		Reason:   "fulfillment_operation",
+	}
+
+	if err := c.sendCommand(ctx, SubjectInventoryAdjust, cmd); err != nil {
+		return fmt.Errorf("failed to adjust stock: %w", err)
+	}
+
+	c.logger.Info("Stock adjusted successfully", zap.String("location", location), zap.String("sku", sku), zap.Int("quantity", quantity), zap.String("correlation_id", cmd.CorrelationID))
+	return nil
+}
+
+// ReserveStock reserva os itens da ordem no Core Inventory até expiresAt.
+// Sempre usa request-reply, pois o chamador precisa saber se houve saldo.
+func (c *InventoryRequestClient) ReserveStock(ctx context.Context, orderID string, items []fulfillment.Item, expiresAt time.Time) error {
+	cmd := &InventoryCommand{
+		OrderID:   orderID,
+		Items:     items,
+		ExpiresAt: &expiresAt,
+	}
+
+	if _, err := c.request(ctx, SubjectInventoryReserve, cmd); err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	c.logger.Info("Stock reserved successfully", zap.String("order_id", orderID), zap.Time("expires_at", expiresAt), zap.String("correlation_id", cmd.CorrelationID))
+	return nil
+}
+
+// ConfirmReservation confirma uma reserva no Core Inventory
+func (c *InventoryRequestClient) ConfirmReservation(ctx context.Context, orderID string, items []fulfillment.Item) error {
+	cmd := &InventoryCommand{
+		OrderID: orderID,
+		Items:   items,
+	}
+
+	if err := c.sendCommand(ctx, SubjectInventoryConfirmReservation, cmd); err != nil {
+		return fmt.Errorf("failed to confirm reservation: %w", err)
+	}
+
+	c.logger.Info("Reservation confirmed successfully", zap.String("order_id", orderID), zap.String("correlation_id", cmd.CorrelationID))
+	return nil
+}
+
+// ReleaseReservation libera a reserva da ordem no Core Inventory
+func (c *InventoryRequestClient) ReleaseReservation(ctx context.Context, orderID string, reason string) error {
+	cmd := &InventoryCommand{
+		OrderID: orderID,
+		Reason:  reason,
+	}
+
+	if err := c.sendCommand(ctx, SubjectInventoryReleaseReservation, cmd); err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	c.logger.Info("Reservation released successfully", zap.String("order_id", orderID), zap.String("reason", reason), zap.String("correlation_id", cmd.CorrelationID))
+	return nil
+}
+
+// GetAvailableStock obtém o estoque disponível do Core Inventory
+func (c *InventoryRequestClient) GetAvailableStock(ctx context.Context, location string, sku string) (int, error) {
+	reply, err := c.request(ctx, SubjectInventoryAvailable, &InventoryCommand{
+		Location: location,
+		SKU:      sku,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query available stock: %w", err)
+	}
+
+	return reply.Available, nil
+}
+
+// EnsureStream cria (ou atualiza) o stream JetStream dos comandos de escrita
+func (c *InventoryRequestClient) EnsureStream(ctx context.Context) error {
+	if c.js == nil {
+		return nil
+	}
+	_, err := c.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     StreamInventoryCommands,
+		Subjects: []string{SubjectInventoryAdjust, SubjectInventoryConfirmReservation, SubjectInventoryReleaseReservation},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", StreamInventoryCommands, err)
+	}
+	return nil
+}
+
+// sendCommand envia um comando de escrita via JetStream (se configurado) ou request-reply
+func (c *InventoryRequestClient) sendCommand(ctx context.Context, subject string, cmd *InventoryCommand) error {
+	cmd.CommandID = commandID(ctx, subject)
+	if c.js == nil {
+		_, err := c.request(ctx, subject, cmd)
+		return err
+	}
+
+	ctx, cmd.CorrelationID = fulfillment.EnsureCorrelationID(ctx)
+	msg, err := newCommandMsg(subject, cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := c.js.PublishMsg(ctx, msg, jetstream.WithMsgID(cmd.CommandID)); err != nil {
+		return fmt.Errorf("failed to publish command to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// commandID combina o ID da etapa com o subject; sem etapa no contexto, cada envio recebe um ID novo
+func commandID(ctx context.Context, subject string) string {
+	if id := fulfillment.CommandIDFromContext(ctx); id != "" {
+		return id + ":" + subject
+	}
+	return uuid.New().String()
+}
+
+// request executa um request-reply e valida a resposta
+func (c *InventoryRequestClient) request(ctx context.Context, subject string, cmd *InventoryCommand) (*InventoryReply, error) {
+	ctx, cmd.CorrelationID = fulfillment.EnsureCorrelationID(ctx)
+	msg, err := newCommandMsg(subject, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.requester.RequestMsgWithContext(ctx, msg)
+	if err != nil {
+		// Timeout/sem responders: falha transitória, elegível para retry automático
+		return nil, fmt.Errorf("%w: core inventory request on %s failed: %w", fulfillment.ErrTransientFailure, subject, err)
+	}
+
+	var reply InventoryReply
+	if err := json.Unmarshal(resp.Data, &reply); err != nil {
+		return nil, fmt.Errorf("failed to decode reply: %w", err)
+	}
+
+	if reply.CorrelationID != "" && reply.CorrelationID != cmd.CorrelationID {
+		return nil, fmt.Errorf("correlation id mismatch: sent %s, got %s", cmd.CorrelationID, reply.CorrelationID)
+	}
+
+	if reply.Code == replyCodeInsufficientStock {
+		return nil, fmt.Errorf("%w: %s", fulfillment.ErrInsufficientStock, reply.Error)
+	}
+
+	if reply.Status != replyStatusOK {
+		return nil, fmt.Errorf("core inventory returned %s: %s", reply.Status, reply.Error)
+	}
+
+	return &reply, nil
+}
+
+// withTimeout aplica o timeout do cliente sem estender um deadline menor já existente
+func (c *InventoryRequestClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < c.timeout {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func newCommandMsg(subject string, cmd *InventoryCommand) (*nats.Msg, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set(HeaderCorrelationID, cmd.CorrelationID)
+	return msg, nil
+}
@@ -0,0 +1,214 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// LocalInventoryResponder é um stub do mcp-core-inventory que responde aos comandos
+// NATS a partir de um ledger em memória. Permite rodar o fluxo completo offline
+// (edge/testes) sem depender do Core.
+type LocalInventoryResponder struct {
+	mu            sync.Mutex
+	stock         map[string]int                // location|sku -> quantidade
+	reserved      map[string]int                // location|sku -> quantidade reservada
+	reservations  map[string][]fulfillment.Item // orderID -> itens reservados
+	applied       map[string]bool               // command_id dos comandos de escrita já aplicados
+	subscriptions []*nats.Subscription
+	logger        Logger
+}
+
+// NewLocalInventoryResponder cria um novo responder local
+func NewLocalInventoryResponder(logger Logger) *LocalInventoryResponder {
+	return &LocalInventoryResponder{
+		stock:        make(map[string]int),
+		reserved:     make(map[string]int),
+		reservations: make(map[string][]fulfillment.Item),
+		applied:      make(map[string]bool),
+		logger:       logger,
+	}
+}
+
+// Start assina os subjects de inventário em uma conexão NATS real
+func (r *LocalInventoryResponder) Start(nc *nats.Conn) error {
+	subjects := []string{
+		SubjectInventoryAdjust,
+		SubjectInventoryReserve,
+		SubjectInventoryConfirmReservation,
+		SubjectInventoryReleaseReservation,
+		SubjectInventoryAvailable,
+	}
+
+	for _, subject := range subjects {
+		sub, err := nc.QueueSubscribe(subject, "inventory-responder", func(m *nats.Msg) {
+			if err := m.RespondMsg(r.Handle(m)); err != nil {
+				r.logger.Error("Failed to respond inventory command", zap.String("subject", m.Subject), zap.Error(err))
+			}
+		})
+		if err != nil {
+			r.Stop()
+			return fmt.Errorf("failed to subscribe %s: %w", subject, err)
+		}
+		r.subscriptions = append(r.subscriptions, sub)
+	}
+
+	r.logger.Info("Local inventory responder started")
+	return nil
+}
+
+// Stop cancela as assinaturas ativas
+func (r *LocalInventoryResponder) Stop() {
+	for _, sub := range r.subscriptions {
+		sub.Unsubscribe()
+	}
+	r.subscriptions = nil
+}
+
+// SetStock define o saldo de um SKU em uma localização (setup de testes)
+func (r *LocalInventoryResponder) SetStock(location, sku string, quantity int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stock[stockKey(location, sku)] = quantity
+}
+
+// HasReservation indica se a ordem possui reserva ativa
+func (r *LocalInventoryResponder) HasReservation(orderID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.reservations[orderID]
+	return ok
+}
+
+// Stock retorna o saldo atual de um SKU em uma localização
+func (r *LocalInventoryResponder) Stock(location, sku string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stock[stockKey(location, sku)]
+}
+
+// Handle processa uma mensagem de comando e devolve a resposta
+func (r *LocalInventoryResponder) Handle(msg *nats.Msg) *nats.Msg {
+	var cmd InventoryCommand
+	reply := InventoryReply{Status: replyStatusOK}
+
+	if err := json.Unmarshal(msg.Data, &cmd); err != nil {
+		reply.Status = replyStatusError
+		reply.Error = fmt.Sprintf("invalid command: %v", err)
+	} else {
+		reply.CorrelationID = cmd.CorrelationID
+		if err := r.apply(msg.Subject, &cmd, &reply); err != nil {
+			reply.Status = replyStatusError
+			reply.Error = err.Error()
+			if errors.Is(err, fulfillment.ErrInsufficientStock) {
+				reply.Code = replyCodeInsufficientStock
+			}
+		}
+	}
+
+	data, _ := json.Marshal(reply)
+	resp := nats.NewMsg(msg.Reply)
+	resp.Data = data
+	resp.Header.Set(HeaderCorrelationID, reply.CorrelationID)
+	return resp
+}
+
+func (r *LocalInventoryResponder) apply(subject string, cmd *InventoryCommand, reply *InventoryReply) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Reenvio de um comando já aplicado (ex: retry após timeout) é confirmado sem reaplicar
+	if cmd.CommandID != "" && r.applied[cmd.CommandID] {
+		return nil
+	}
+	if err := r.applyLocked(subject, cmd, reply); err != nil {
+		return err
+	}
+	if cmd.CommandID != "" {
+		r.applied[cmd.CommandID] = true
+	}
+	return nil
+}
+
+func (r *LocalInventoryResponder) applyLocked(subject string, cmd *InventoryCommand, reply *InventoryReply) error {
+	switch subject {
+	case SubjectInventoryAdjust:
+		key := stockKey(cmd.Location, cmd.SKU)
+		if r.stock[key]-r.reserved[key]+cmd.Quantity < 0 {
+			return fmt.Errorf("%w for %s at %s", fulfillment.ErrInsufficientStock, cmd.SKU, cmd.Location)
+		}
+		r.stock[key] += cmd.Quantity
+	case SubjectInventoryReserve:
+		if _, exists := r.reservations[cmd.OrderID]; exists {
+			return nil // Idempotente
+		}
+		for _, item := range cmd.Items {
+			key := stockKey(item.Location, item.SKU)
+			if r.stock[key]-r.reserved[key] < item.Quantity {
+				return fmt.Errorf("%w for %s at %s", fulfillment.ErrInsufficientStock, item.SKU, item.Location)
+			}
+		}
+		for _, item := range cmd.Items {
+			r.reserved[stockKey(item.Location, item.SKU)] += item.Quantity
+		}
+		r.reservations[cmd.OrderID] = cmd.Items
+	case SubjectInventoryConfirmReservation:
+		items, exists := r.reservations[cmd.OrderID]
+		if !exists {
+			return fmt.Errorf("reservation not found for order %s", cmd.OrderID)
+		}
+		// Baixa os itens confirmados (todos, se omitidos) e libera o restante da reserva
+		confirmed := cmd.Items
+		if len(confirmed) == 0 {
+			confirmed = items
+		}
+		for _, item := range items {
+			r.reserved[stockKey(item.Location, item.SKU)] -= item.Quantity
+		}
+		for _, item := range confirmed {
+			r.stock[stockKey(item.Location, item.SKU)] -= item.Quantity
+		}
+		delete(r.reservations, cmd.OrderID)
+	case SubjectInventoryReleaseReservation:
+		for _, item := range r.reservations[cmd.OrderID] {
+			r.reserved[stockKey(item.Location, item.SKU)] -= item.Quantity
+		}
+		delete(r.reservations, cmd.OrderID)
+	case SubjectInventoryAvailable:
+		key := stockKey(cmd.Location, cmd.SKU)
+		reply.Available = r.stock[key] - r.reserved[key]
+	default:
+		return fmt.Errorf("unsupported subject: %s", subject)
+	}
+	return nil
+}
+
+// LocalRequester implementa Requester despachando diretamente para o responder local,
+// sem conexão NATS
+type LocalRequester struct {
+	responder *LocalInventoryResponder
+}
+
+// NewLocalRequester cria um requester em processo
+func NewLocalRequester(responder *LocalInventoryResponder) *LocalRequester {
+	return &LocalRequester{responder: responder}
+}
+
+// RequestMsgWithContext entrega a mensagem ao responder e devolve a resposta
+func (l *LocalRequester) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.responder.Handle(msg), nil
+}
+
+func stockKey(location, sku string) string {
+	return location + "|" + sku
+}
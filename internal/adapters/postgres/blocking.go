@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// ListBlocked lista operações em BLOCKED desde antes de blockedBefore, das mais antigas para as mais recentes.
+// entityType vazio consulta todas as entidades.
+func (r *FulfillmentRepository) ListBlocked(ctx context.Context, entityType string, blockedBefore time.Time, limit int) ([]fulfillment.BlockedEntity, error) {
+	return r.listBlocked(ctx, entityType, blockedBefore, limit, "")
+}
+
+// ListUnescalatedBlocked filtra os bloqueios já escalonados na consulta, para que não ocupem o lote do escalonador
+func (r *FulfillmentRepository) ListUnescalatedBlocked(ctx context.Context, blockedBefore time.Time, limit int) ([]fulfillment.BlockedEntity, error) {
+	return r.listBlocked(ctx, "", blockedBefore, limit, ` AND block->>'escalated_at' IS NULL`)
+}
+
+func (r *FulfillmentRepository) listBlocked(ctx context.Context, entityType string, blockedBefore time.Time, limit int, filter string) ([]fulfillment.BlockedEntity, error) {
+	var selects []string
+	for _, entity := range entityTypes {
+		if entityType != "" && entityType != entity {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf(
+			`SELECT '%s' AS entity_type, id, block, COALESCE((block->>'blocked_at')::timestamptz, updated_at) AS blocked_at
+			 FROM %s WHERE status = 'BLOCKED'%s`,
+			entity, entityTables[entity], filter,
+		))
+	}
+	if len(selects) == 0 {
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	query := `SELECT entity_type, id, block FROM (` + strings.Join(selects, " UNION ALL ") + `) blocked
+		WHERE blocked_at <= $1 ORDER BY blocked_at LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, blockedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocked entities: %w", err)
+	}
+	defer rows.Close()
+
+	var blocked []fulfillment.BlockedEntity
+	for rows.Next() {
+		var entity fulfillment.BlockedEntity
+		var blockJSON []byte
+		if err := rows.Scan(&entity.EntityType, &entity.EntityID, &blockJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked entity: %w", err)
+		}
+		if entity.Block, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, entity)
+	}
+	return blocked, rows.Err()
+}
+
+// MarkBlockEscalated registra o escalonamento sem alterar status (não gera transição no histórico)
+func (r *FulfillmentRepository) MarkBlockEscalated(ctx context.Context, entityType, id string, at time.Time) error {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE `+table+` SET block = jsonb_set(block, '{escalated_at}', to_jsonb($2::timestamptz))
+		 WHERE id = $1 AND status = 'BLOCKED' AND block IS NOT NULL`,
+		id, at,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark block escalated: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// SaveCountProgram insere ou substitui o programa de contagem cíclica (linha única)
+func (r *FulfillmentRepository) SaveCountProgram(ctx context.Context, program *fulfillment.CountProgram) error {
+	frequenciesJSON, err := json.Marshal(program.Frequencies)
+	if err != nil {
+		return fmt.Errorf("failed to marshal count frequencies: %w", err)
+	}
+
+	query := `
+		INSERT INTO count_program (id, frequencies, lookback_days, daily_capacity, updated_at)
+		VALUES (1, $1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			frequencies = EXCLUDED.frequencies, lookback_days = EXCLUDED.lookback_days,
+			daily_capacity = EXCLUDED.daily_capacity, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, frequenciesJSON, program.LookbackDays, program.DailyCapacity, program.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save count program: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetCountProgram(ctx context.Context) (*fulfillment.CountProgram, error) {
+	var program fulfillment.CountProgram
+	var frequenciesJSON []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT frequencies, lookback_days, daily_capacity, updated_at FROM count_program WHERE id = 1`,
+	).Scan(&frequenciesJSON, &program.LookbackDays, &program.DailyCapacity, &program.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrCountProgramNotFound
+		}
+		return nil, fmt.Errorf("failed to get count program: %w", err)
+	}
+
+	if err := json.Unmarshal(frequenciesJSON, &program.Frequencies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal count frequencies: %w", err)
+	}
+	return &program, nil
+}
+
+// ListLocationCountHistory agrega as contagens cíclicas por endereço
+func (r *FulfillmentRepository) ListLocationCountHistory(ctx context.Context) ([]fulfillment.LocationCountHistory, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT location,
+			MAX(completed_at) FILTER (WHERE status = $1),
+			MAX(created_at),
+			BOOL_OR(status NOT IN ($1, $2))
+		FROM cycle_count_tasks
+		GROUP BY location
+		ORDER BY location
+	`, fulfillment.StatusCompleted, fulfillment.StatusCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query location count history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []fulfillment.LocationCountHistory
+	for rows.Next() {
+		var entry fulfillment.LocationCountHistory
+		var lastCounted, lastOpened sql.NullTime
+		if err := rows.Scan(&entry.Location, &lastCounted, &lastOpened, &entry.Open); err != nil {
+			return nil, fmt.Errorf("failed to scan location count history: %w", err)
+		}
+		if lastCounted.Valid {
+			entry.LastCountedAt = &lastCounted.Time
+		}
+		if lastOpened.Valid {
+			entry.LastOpenedAt = &lastOpened.Time
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+func (r *FulfillmentRepository) CreateCountTrigger(ctx context.Context, trigger *fulfillment.CountTrigger) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO count_triggers (id, location, sku, reason, quantity, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, trigger.ID, trigger.Location, trigger.SKU, trigger.Reason, trigger.Quantity, trigger.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert count trigger: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) ListPendingCountTriggers(ctx context.Context) ([]*fulfillment.CountTrigger, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, location, sku, reason, quantity, created_at
+		FROM count_triggers WHERE task_id IS NULL
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query count triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []*fulfillment.CountTrigger
+	for rows.Next() {
+		var trigger fulfillment.CountTrigger
+		if err := rows.Scan(&trigger.ID, &trigger.Location, &trigger.SKU, &trigger.Reason, &trigger.Quantity, &trigger.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan count trigger: %w", err)
+		}
+		triggers = append(triggers, &trigger)
+	}
+	return triggers, rows.Err()
+}
+
+// MarkCountTriggersPlanned vincula os gatilhos à contagem gerada para eles
+func (r *FulfillmentRepository) MarkCountTriggersPlanned(ctx context.Context, ids []string, taskID string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE count_triggers SET task_id = $1 WHERE id = ANY($2)`, taskID, pq.Array(ids),
+	); err != nil {
+		return fmt.Errorf("failed to mark count triggers planned: %w", err)
+	}
+	return nil
+}
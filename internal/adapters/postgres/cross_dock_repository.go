@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const crossDockPolicyColumns = `id, customer, sku, enabled, staging_location, include_pending, created_at, updated_at`
+
+const crossDockAllocationColumns = `id, shipment_id, order_id, line, sku, COALESCE(batch, ''), quantity,
+	from_location, to_location, COALESCE(transfer_id, ''), created_at`
+
+// SaveCrossDockPolicy insere ou substitui a política do cliente e SKU (mantendo o ID existente)
+func (r *FulfillmentRepository) SaveCrossDockPolicy(ctx context.Context, policy *fulfillment.CrossDockPolicy) error {
+	query := `
+		INSERT INTO cross_dock_policies (
+			id, customer, sku, enabled, staging_location, include_pending, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (customer, sku) DO UPDATE SET
+			enabled = EXCLUDED.enabled, staging_location = EXCLUDED.staging_location,
+			include_pending = EXCLUDED.include_pending, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		policy.ID, policy.Customer, policy.SKU, policy.Enabled, policy.StagingLocation, policy.IncludePending,
+		policy.CreatedAt, policy.UpdatedAt,
+	).Scan(&policy.ID, &policy.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save cross-dock policy: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) ListCrossDockPolicies(ctx context.Context) ([]*fulfillment.CrossDockPolicy, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+crossDockPolicyColumns+` FROM cross_dock_policies ORDER BY customer, sku`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cross-dock policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*fulfillment.CrossDockPolicy
+	for rows.Next() {
+		var policy fulfillment.CrossDockPolicy
+		if err := rows.Scan(
+			&policy.ID, &policy.Customer, &policy.SKU, &policy.Enabled, &policy.StagingLocation,
+			&policy.IncludePending, &policy.CreatedAt, &policy.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan cross-dock policy: %w", err)
+		}
+		policies = append(policies, &policy)
+	}
+	return policies, rows.Err()
+}
+
+func (r *FulfillmentRepository) DeleteCrossDockPolicy(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cross_dock_policies WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete cross-dock policy: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fulfillment.ErrCrossDockPolicyNotFound
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) CreateCrossDockAllocation(ctx context.Context, allocation *fulfillment.CrossDockAllocation) error {
+	query := `
+		INSERT INTO cross_dock_allocations (
+			id, shipment_id, order_id, line, sku, batch, quantity, from_location, to_location, transfer_id, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		allocation.ID, allocation.ShipmentID, allocation.OrderID, allocation.Line, allocation.SKU,
+		nullableString(allocation.Batch), allocation.Quantity, allocation.From, allocation.To,
+		nullableString(allocation.TransferID), allocation.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert cross-dock allocation: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetCrossDockAllocationByTransfer(ctx context.Context, transferID string) (*fulfillment.CrossDockAllocation, error) {
+	return scanCrossDockAllocation(r.db.QueryRowContext(ctx,
+		`SELECT `+crossDockAllocationColumns+` FROM cross_dock_allocations WHERE transfer_id = $1 LIMIT 1`,
+		transferID,
+	))
+}
+
+func (r *FulfillmentRepository) ListCrossDockAllocations(ctx context.Context, shipmentID string, limit int) ([]*fulfillment.CrossDockAllocation, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+crossDockAllocationColumns+` FROM cross_dock_allocations
+		 WHERE $1 = '' OR shipment_id = $1 ORDER BY created_at DESC LIMIT $2`,
+		shipmentID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cross-dock allocations: %w", err)
+	}
+	defer rows.Close()
+
+	var allocations []*fulfillment.CrossDockAllocation
+	for rows.Next() {
+		allocation, err := scanCrossDockAllocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, allocation)
+	}
+	return allocations, rows.Err()
+}
+
+func scanCrossDockAllocation(row rowScanner) (*fulfillment.CrossDockAllocation, error) {
+	var allocation fulfillment.CrossDockAllocation
+	err := row.Scan(
+		&allocation.ID, &allocation.ShipmentID, &allocation.OrderID, &allocation.Line, &allocation.SKU,
+		&allocation.Batch, &allocation.Quantity, &allocation.From, &allocation.To, &allocation.TransferID,
+		&allocation.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrCrossDockAllocationNotFound
+		}
+		return nil, fmt.Errorf("failed to scan cross-dock allocation: %w", err)
+	}
+	return &allocation, nil
+}
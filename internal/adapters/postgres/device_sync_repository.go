@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const syncConflictColumns = `id, key, kind, server_state, device_operation, COALESCE(fields, '[]'), reason, status,
+	COALESCE(choice, ''), COALESCE(resolved_by, ''), resolved_at, created_at`
+
+func (r *FulfillmentRepository) GetSyncState(ctx context.Context, key string) (*fulfillment.SyncState, error) {
+	var state fulfillment.SyncState
+	var valueJSON, clockJSON []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT key, kind, value, clock, device_id, updated_at, version
+		FROM device_sync_states WHERE key = $1
+	`, key).Scan(&state.Key, &state.Kind, &valueJSON, &clockJSON, &state.DeviceID, &state.UpdatedAt, &state.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrSyncStateNotFound
+		}
+		return nil, fmt.Errorf("failed to scan sync state: %w", err)
+	}
+	if err := json.Unmarshal(valueJSON, &state.Value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync value: %w", err)
+	}
+	if err := json.Unmarshal(clockJSON, &state.Clock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync clock: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveSyncState insere o estado novo ou o atualiza se a versão não mudou desde a leitura
+func (r *FulfillmentRepository) SaveSyncState(ctx context.Context, state *fulfillment.SyncState) error {
+	valueJSON, err := json.Marshal(state.Value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync value: %w", err)
+	}
+	clockJSON, err := json.Marshal(state.Clock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync clock: %w", err)
+	}
+
+	var result sql.Result
+	if state.Version == 0 {
+		result, err = r.db.ExecContext(ctx, `
+			INSERT INTO device_sync_states (key, kind, value, clock, device_id, updated_at, version)
+			VALUES ($1, $2, $3, $4, $5, $6, 1)
+			ON CONFLICT (key) DO NOTHING
+		`, state.Key, state.Kind, valueJSON, clockJSON, state.DeviceID, state.UpdatedAt)
+	} else {
+		result, err = r.db.ExecContext(ctx, `
+			UPDATE device_sync_states
+			SET value = $2, clock = $3, device_id = $4, updated_at = $5, version = version + 1
+			WHERE key = $1 AND version = $6
+		`, state.Key, valueJSON, clockJSON, state.DeviceID, state.UpdatedAt, state.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save sync state: %w", err)
+	}
+	saved, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return fmt.Errorf("%w: %s", fulfillment.ErrSyncStateConflict, state.Key)
+	}
+	state.Version++
+	return nil
+}
+
+// DeleteSyncState remove o estado da chave se a versão não mudou desde a leitura
+func (r *FulfillmentRepository) DeleteSyncState(ctx context.Context, key string, version int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM device_sync_states WHERE key = $1 AND version = $2`, key, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete sync state: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", fulfillment.ErrSyncStateConflict, key)
+	}
+	return nil
+}
+
+// CreateSyncConflict insere o conflito; o reenvio da mesma operação devolve o ID já registrado
+func (r *FulfillmentRepository) CreateSyncConflict(ctx context.Context, conflict *fulfillment.SyncConflictRecord) error {
+	serverJSON, err := json.Marshal(conflict.Server)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync server state: %w", err)
+	}
+	deviceJSON, err := json.Marshal(conflict.Device)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync device operation: %w", err)
+	}
+	fieldsJSON, err := json.Marshal(conflict.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync conflict fields: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO device_sync_conflicts (
+			id, key, kind, device_id, operation_id, server_state, device_operation, fields, reason, status, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (device_id, operation_id) DO UPDATE SET device_id = EXCLUDED.device_id
+		RETURNING id
+	`, conflict.ID, conflict.Key, conflict.Kind, conflict.Device.DeviceID, conflict.Device.ID, serverJSON, deviceJSON, fieldsJSON,
+		conflict.Reason, conflict.Status, conflict.CreatedAt,
+	).Scan(&conflict.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert sync conflict: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetSyncConflict(ctx context.Context, id string) (*fulfillment.SyncConflictRecord, error) {
+	return scanSyncConflict(r.db.QueryRowContext(ctx, `SELECT `+syncConflictColumns+` FROM device_sync_conflicts WHERE id = $1`, id))
+}
+
+func (r *FulfillmentRepository) ListSyncConflicts(ctx context.Context, status fulfillment.Status) ([]*fulfillment.SyncConflictRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+syncConflictColumns+` FROM device_sync_conflicts
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at, id
+	`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []*fulfillment.SyncConflictRecord
+	for rows.Next() {
+		conflict, err := scanSyncConflict(rows)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, rows.Err()
+}
+
+func (r *FulfillmentRepository) UpdateSyncConflict(ctx context.Context, conflict *fulfillment.SyncConflictRecord) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE device_sync_conflicts SET status = $2, choice = $3, resolved_by = $4, resolved_at = $5
+		WHERE id = $1
+	`, conflict.ID, conflict.Status, nullableString(string(conflict.Choice)), nullableString(conflict.ResolvedBy), nullableTime(conflict.ResolvedAt))
+	if err != nil {
+		return fmt.Errorf("failed to update sync conflict: %w", err)
+	}
+	updated, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fulfillment.ErrSyncConflictNotFound
+	}
+	return nil
+}
+
+func scanSyncConflict(row rowScanner) (*fulfillment.SyncConflictRecord, error) {
+	var conflict fulfillment.SyncConflictRecord
+	var serverJSON, deviceJSON, fieldsJSON []byte
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&conflict.ID, &conflict.Key, &conflict.Kind, &serverJSON, &deviceJSON, &fieldsJSON, &conflict.Reason, &conflict.Status,
+		&conflict.Choice, &conflict.ResolvedBy, &resolvedAt, &conflict.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrSyncConflictNotFound
+		}
+		return nil, fmt.Errorf("failed to scan sync conflict: %w", err)
+	}
+	if err := json.Unmarshal(serverJSON, &conflict.Server); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync server state: %w", err)
+	}
+	if err := json.Unmarshal(deviceJSON, &conflict.Device); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync device operation: %w", err)
+	}
+	if err := json.Unmarshal(fieldsJSON, &conflict.Fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync conflict fields: %w", err)
+	}
+	conflict.ResolvedAt = timePtr(resolvedAt)
+	return &conflict, nil
+}
@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const dockDoorColumns = `id, name, direction, capabilities, hours, slot_minutes, timezone, active, created_at, updated_at`
+
+const dockAppointmentColumns = `id, door_id, direction, shipment_type, shipment_id, supplier, COALESCE(carrier, ''),
+	scheduled_start, scheduled_end, status, arrived_at, completed_at, late_minutes, created_at, updated_at`
+
+const dockAppointmentColumnsInsert = `id, door_id, direction, shipment_type, shipment_id, supplier, carrier,
+	scheduled_start, scheduled_end, status, arrived_at, completed_at, late_minutes, created_at, updated_at`
+
+// SaveDockDoor insere ou substitui a porta pelo nome (mantendo o ID existente)
+func (r *FulfillmentRepository) SaveDockDoor(ctx context.Context, door *fulfillment.DockDoor) error {
+	capabilitiesJSON, err := json.Marshal(door.Capabilities)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dock capabilities: %w", err)
+	}
+	hoursJSON, err := json.Marshal(door.Hours)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dock hours: %w", err)
+	}
+
+	query := `
+		INSERT INTO dock_doors (` + dockDoorColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (name) DO UPDATE SET
+			direction = EXCLUDED.direction, capabilities = EXCLUDED.capabilities, hours = EXCLUDED.hours,
+			slot_minutes = EXCLUDED.slot_minutes, timezone = EXCLUDED.timezone, active = EXCLUDED.active,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		door.ID, door.Name, door.Direction, capabilitiesJSON, hoursJSON, door.SlotMinutes, door.Timezone,
+		door.Active, door.CreatedAt, door.UpdatedAt,
+	).Scan(&door.ID, &door.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save dock door: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetDockDoor(ctx context.Context, id string) (*fulfillment.DockDoor, error) {
+	return scanDockDoor(r.db.QueryRowContext(ctx, `SELECT `+dockDoorColumns+` FROM dock_doors WHERE id = $1`, id))
+}
+
+func (r *FulfillmentRepository) ListDockDoors(ctx context.Context) ([]*fulfillment.DockDoor, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+dockDoorColumns+` FROM dock_doors ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dock doors: %w", err)
+	}
+	defer rows.Close()
+
+	var doors []*fulfillment.DockDoor
+	for rows.Next() {
+		door, err := scanDockDoor(rows)
+		if err != nil {
+			return nil, err
+		}
+		doors = append(doors, door)
+	}
+	return doors, rows.Err()
+}
+
+// CreateAppointment bloqueia a porta durante a verificação de sobreposição, evitando reservas
+// concorrentes do mesmo intervalo
+func (r *FulfillmentRepository) CreateAppointment(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		var doorID string
+		err := tx.QueryRowContext(ctx, `SELECT id FROM dock_doors WHERE id = $1 FOR UPDATE`, appointment.DoorID).Scan(&doorID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fulfillment.ErrDockDoorNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock dock door: %w", err)
+		}
+
+		var conflicts int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM dock_appointments
+			WHERE door_id = $1 AND status IN ($2, $3) AND scheduled_start < $5 AND $4 < scheduled_end
+		`, appointment.DoorID, fulfillment.AppointmentScheduled, fulfillment.AppointmentArrived,
+			appointment.ScheduledStart, appointment.ScheduledEnd,
+		).Scan(&conflicts)
+		if err != nil {
+			return fmt.Errorf("failed to check dock conflicts: %w", err)
+		}
+		if conflicts > 0 {
+			return fulfillment.ErrAppointmentConflict
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dock_appointments (`+dockAppointmentColumnsInsert+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`,
+			appointment.ID, appointment.DoorID, appointment.Direction, appointment.ShipmentType, appointment.ShipmentID,
+			appointment.Supplier, nullableString(appointment.Carrier), appointment.ScheduledStart, appointment.ScheduledEnd,
+			appointment.Status, nullableTime(appointment.ArrivedAt), nullableTime(appointment.CompletedAt),
+			appointment.LateMinutes, appointment.CreatedAt, appointment.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert dock appointment: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *FulfillmentRepository) GetAppointment(ctx context.Context, id string) (*fulfillment.DockAppointment, error) {
+	return scanDockAppointment(r.db.QueryRowContext(ctx,
+		`SELECT `+dockAppointmentColumns+` FROM dock_appointments WHERE id = $1`, id,
+	))
+}
+
+// UpdateAppointment grava o agendamento se o status não mudou desde a leitura, para que a detecção de
+// não comparecimento não sobrescreva uma chegada registrada ao mesmo tempo
+func (r *FulfillmentRepository) UpdateAppointment(ctx context.Context, appointment *fulfillment.DockAppointment, from fulfillment.AppointmentStatus) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE dock_appointments
+		SET status = $2, arrived_at = $3, completed_at = $4, late_minutes = $5, updated_at = $6
+		WHERE id = $1 AND status = $7
+	`, appointment.ID, appointment.Status, nullableTime(appointment.ArrivedAt), nullableTime(appointment.CompletedAt),
+		appointment.LateMinutes, appointment.UpdatedAt, from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update dock appointment: %w", err)
+	}
+	updated, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return r.appointmentNotUpdated(ctx, appointment.ID, from)
+	}
+	return nil
+}
+
+// appointmentNotUpdated distingue o agendamento inexistente do que mudou de status
+func (r *FulfillmentRepository) appointmentNotUpdated(ctx context.Context, id string, from fulfillment.AppointmentStatus) error {
+	var status fulfillment.AppointmentStatus
+	err := r.db.QueryRowContext(ctx, `SELECT status FROM dock_appointments WHERE id = $1`, id).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fulfillment.ErrAppointmentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get dock appointment status: %w", err)
+	}
+	return fmt.Errorf("%w: %s, expected %s", fulfillment.ErrAppointmentChanged, status, from)
+}
+
+func (r *FulfillmentRepository) ListAppointments(ctx context.Context, doorID string, from, to time.Time) ([]*fulfillment.DockAppointment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+dockAppointmentColumns+` FROM dock_appointments
+		 WHERE ($1 = '' OR door_id = $1) AND scheduled_start >= $2 AND scheduled_start < $3
+		 ORDER BY scheduled_start`,
+		doorID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dock appointments: %w", err)
+	}
+	defer rows.Close()
+
+	var appointments []*fulfillment.DockAppointment
+	for rows.Next() {
+		appointment, err := scanDockAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, appointment)
+	}
+	return appointments, rows.Err()
+}
+
+func scanDockDoor(row rowScanner) (*fulfillment.DockDoor, error) {
+	var door fulfillment.DockDoor
+	var capabilitiesJSON, hoursJSON []byte
+	err := row.Scan(
+		&door.ID, &door.Name, &door.Direction, &capabilitiesJSON, &hoursJSON, &door.SlotMinutes, &door.Timezone,
+		&door.Active, &door.CreatedAt, &door.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrDockDoorNotFound
+		}
+		return nil, fmt.Errorf("failed to scan dock door: %w", err)
+	}
+	if err := json.Unmarshal(capabilitiesJSON, &door.Capabilities); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dock capabilities: %w", err)
+	}
+	if err := json.Unmarshal(hoursJSON, &door.Hours); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dock hours: %w", err)
+	}
+	return &door, nil
+}
+
+func scanDockAppointment(row rowScanner) (*fulfillment.DockAppointment, error) {
+	var appointment fulfillment.DockAppointment
+	var arrivedAt, completedAt sql.NullTime
+	err := row.Scan(
+		&appointment.ID, &appointment.DoorID, &appointment.Direction, &appointment.ShipmentType, &appointment.ShipmentID,
+		&appointment.Supplier, &appointment.Carrier, &appointment.ScheduledStart, &appointment.ScheduledEnd,
+		&appointment.Status, &arrivedAt, &completedAt, &appointment.LateMinutes, &appointment.CreatedAt,
+		&appointment.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrAppointmentNotFound
+		}
+		return nil, fmt.Errorf("failed to scan dock appointment: %w", err)
+	}
+	appointment.ArrivedAt = timePtr(arrivedAt)
+	appointment.CompletedAt = timePtr(completedAt)
+	return &appointment, nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// SaveDocumentTemplate grava o modelo; um modelo existente do cliente para o tipo e formato é substituído
+func (r *FulfillmentRepository) SaveDocumentTemplate(ctx context.Context, tmpl *fulfillment.DocumentTemplate) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO document_templates (id, customer, document_type, format, body, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (customer, document_type, format)
+		DO UPDATE SET body = EXCLUDED.body, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`, tmpl.ID, tmpl.Customer, tmpl.Type, tmpl.Format, tmpl.Body, tmpl.UpdatedAt).Scan(&tmpl.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save document template: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetDocumentTemplate(ctx context.Context, customer string, docType fulfillment.DocumentType, format fulfillment.DocumentFormat) (*fulfillment.DocumentTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, customer, document_type, format, body, updated_at
+		FROM document_templates WHERE customer = $1 AND document_type = $2 AND format = $3
+	`, customer, docType, format)
+	return scanDocumentTemplate(row)
+}
+
+func (r *FulfillmentRepository) ListDocumentTemplates(ctx context.Context) ([]*fulfillment.DocumentTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, customer, document_type, format, body, updated_at
+		FROM document_templates ORDER BY customer, document_type, format
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query document templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*fulfillment.DocumentTemplate
+	for rows.Next() {
+		tmpl, err := scanDocumentTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates, rows.Err()
+}
+
+func (r *FulfillmentRepository) DeleteDocumentTemplate(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM document_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete document template: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fulfillment.ErrDocumentTemplateNotFound
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) CreateRenderedDocument(ctx context.Context, document *fulfillment.RenderedDocument) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO rendered_documents (id, fulfillment_order_id, document_type, format, template_id, content, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, document.ID, document.FulfillmentOrderID, document.Type, document.Format, nullableString(document.TemplateID),
+		document.Content, document.CreatedAt, nullableString(document.CreatedBy))
+	if err != nil {
+		return fmt.Errorf("failed to insert rendered document: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetRenderedDocument(ctx context.Context, id string) (*fulfillment.RenderedDocument, error) {
+	var document fulfillment.RenderedDocument
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, fulfillment_order_id, document_type, format, COALESCE(template_id, ''), content, created_at, COALESCE(created_by, '')
+		FROM rendered_documents WHERE id = $1
+	`, id).Scan(
+		&document.ID, &document.FulfillmentOrderID, &document.Type, &document.Format, &document.TemplateID,
+		&document.Content, &document.CreatedAt, &document.CreatedBy,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to get rendered document: %w", err)
+	}
+	document.Size = len(document.Content)
+	return &document, nil
+}
+
+func (r *FulfillmentRepository) ListRenderedDocuments(ctx context.Context, orderID string) ([]*fulfillment.RenderedDocument, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, fulfillment_order_id, document_type, format, COALESCE(template_id, ''), octet_length(content), created_at, COALESCE(created_by, '')
+		FROM rendered_documents WHERE fulfillment_order_id = $1
+		ORDER BY created_at DESC, id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rendered documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []*fulfillment.RenderedDocument
+	for rows.Next() {
+		var document fulfillment.RenderedDocument
+		if err := rows.Scan(
+			&document.ID, &document.FulfillmentOrderID, &document.Type, &document.Format, &document.TemplateID,
+			&document.Size, &document.CreatedAt, &document.CreatedBy,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rendered document: %w", err)
+		}
+		documents = append(documents, &document)
+	}
+	return documents, rows.Err()
+}
+
+func scanDocumentTemplate(row rowScanner) (*fulfillment.DocumentTemplate, error) {
+	var tmpl fulfillment.DocumentTemplate
+	if err := row.Scan(&tmpl.ID, &tmpl.Customer, &tmpl.Type, &tmpl.Format, &tmpl.Body, &tmpl.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrDocumentTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to scan document template: %w", err)
+	}
+	return &tmpl, nil
+}
@@ -0,0 +1,667 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/state/events"
+)
+
+const (
+	// eventStoreChannel é o canal LISTEN/NOTIFY sinalizado a cada append
+	eventStoreChannel = "event_store_appended"
+
+	// eventStoreAppendLock serializa appends para que a posição global seja
+	// visível na mesma ordem em que é atribuída (pollers nunca pulam posições)
+	eventStoreAppendLock = 7_340_029
+
+	defaultEventStorePollInterval = time.Second
+)
+
+// EventStore implementa events.EventStore sobre Postgres.
+// Eventos recebem uma posição global (BIGSERIAL) e a versão do stream é verificada
+// contra event_store_aggregates dentro da mesma transação.
+type EventStore struct {
+	db           *sql.DB
+	config       *events.EventStoreConfig
+	pollInterval time.Duration
+	startedAt    time.Time
+
+	mu       sync.Mutex
+	wake     chan struct{}
+	listener *pq.Listener
+	done     chan struct{}
+}
+
+var _ events.EventStore = (*EventStore)(nil)
+
+// NewEventStore cria um event store durável sobre o banco informado
+func NewEventStore(db *sql.DB, config *events.EventStoreConfig) *EventStore {
+	if config == nil {
+		config = events.DefaultEventStoreConfig()
+	}
+	cfg := *config
+	cfg.StoreType = "postgres"
+
+	return &EventStore{
+		db:           db,
+		config:       &cfg,
+		pollInterval: defaultEventStorePollInterval,
+		startedAt:    time.Now(),
+		wake:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Listen habilita LISTEN/NOTIFY para acordar streams imediatamente após um append.
+// Sem Listen, os streams fazem polling a cada pollInterval.
+func (s *EventStore) Listen(dsn string) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, nil)
+	if err := listener.Listen(eventStoreChannel); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to listen on %s: %w", eventStoreChannel, err)
+	}
+
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case _, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+				s.broadcast()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close encerra o listener e todos os streams abertos
+func (s *EventStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+	close(s.done)
+
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+// SaveEvent grava um único evento
+func (s *EventStore) SaveEvent(ctx context.Context, event *events.Event) error {
+	return s.SaveEvents(ctx, []*events.Event{event})
+}
+
+// SaveEvents grava os eventos atomicamente. Cada evento deve continuar o stream do
+// seu agregado (versão atual + 1), caso contrário retorna events.ErrVersionConflict.
+func (s *EventStore) SaveEvents(ctx context.Context, batch []*events.Event) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	for _, event := range batch {
+		if err := events.ValidateEvent(event, s.config.MaxEventSize); err != nil {
+			return fmt.Errorf("event validation failed: %w", err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, eventStoreAppendLock); err != nil {
+		return fmt.Errorf("failed to acquire append lock: %w", err)
+	}
+
+	for _, event := range batch {
+		if err := s.appendEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, '')`, eventStoreChannel); err != nil {
+		return fmt.Errorf("failed to notify listeners: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit events: %w", err)
+	}
+
+	s.broadcast()
+	return nil
+}
+
+func (s *EventStore) appendEvent(ctx context.Context, tx *sql.Tx, event *events.Event) error {
+	var current int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT version FROM event_store_aggregates WHERE aggregate_id = $1 FOR UPDATE`,
+		event.AggregateID,
+	).Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read stream version: %w", err)
+	}
+
+	if event.Version != current+1 {
+		return fmt.Errorf("%w: aggregate %s expected version %d, got %d",
+			events.ErrVersionConflict, event.AggregateID, current+1, event.Version)
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+
+	size := int64(len(data) + len(metadata))
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_store_events (
+			id, aggregate_id, aggregate_type, version, type, data, metadata,
+			occurred_at, node_id, causation_id, correlation_id, size_bytes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		event.ID, event.AggregateID, event.AggregateType, event.Version, string(event.Type),
+		data, metadata, event.Timestamp, event.NodeID, event.CausationID, event.CorrelationID, size,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("%w: %s", events.ErrVersionConflict, pqErr.Detail)
+		}
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_store_aggregates (
+			aggregate_id, aggregate_type, version, event_count, size_bytes, first_event, last_event
+		) VALUES ($1, $2, $3, 1, $4, $5, $5)
+		ON CONFLICT (aggregate_id) DO UPDATE SET
+			version = EXCLUDED.version,
+			event_count = event_store_aggregates.event_count + 1,
+			size_bytes = event_store_aggregates.size_bytes + EXCLUDED.size_bytes,
+			last_event = EXCLUDED.last_event
+	`, event.AggregateID, event.AggregateType, event.Version, size, event.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to update aggregate info: %w", err)
+	}
+
+	return nil
+}
+
+const eventColumns = `
+	position, id, aggregate_id, aggregate_type, version, type, data, metadata,
+	occurred_at, node_id, causation_id, correlation_id
+`
+
+// GetEvents retorna os eventos do agregado no intervalo de versões
+func (s *EventStore) GetEvents(ctx context.Context, aggregateID string, fromVersion int64, toVersion int64) ([]*events.Event, error) {
+	stream, _, err := s.queryEvents(ctx,
+		`SELECT `+eventColumns+` FROM event_store_events
+		 WHERE aggregate_id = $1 AND version >= $2 AND version <= $3 ORDER BY version`,
+		aggregateID, fromVersion, toVersion,
+	)
+	return stream, err
+}
+
+// GetAllEvents retorna todos os eventos retidos do agregado
+func (s *EventStore) GetAllEvents(ctx context.Context, aggregateID string) ([]*events.Event, error) {
+	stream, _, err := s.queryEvents(ctx,
+		`SELECT `+eventColumns+` FROM event_store_events WHERE aggregate_id = $1 ORDER BY version`,
+		aggregateID,
+	)
+	return stream, err
+}
+
+// GetEventsByType retorna eventos do tipo informado em ordem global
+func (s *EventStore) GetEventsByType(ctx context.Context, eventType events.EventType, limit int) ([]*events.Event, error) {
+	stream, _, err := s.queryEvents(ctx,
+		`SELECT `+eventColumns+` FROM event_store_events WHERE type = $1 ORDER BY position`+limitClause(limit),
+		string(eventType),
+	)
+	return stream, err
+}
+
+// GetEventsByTimeRange retorna eventos entre startTime e endTime em ordem global
+func (s *EventStore) GetEventsByTimeRange(ctx context.Context, startTime, endTime time.Time, limit int) ([]*events.Event, error) {
+	stream, _, err := s.queryEvents(ctx,
+		`SELECT `+eventColumns+` FROM event_store_events
+		 WHERE occurred_at > $1 AND occurred_at < $2 ORDER BY position`+limitClause(limit),
+		startTime, endTime,
+	)
+	return stream, err
+}
+
+// StreamEvents emite os eventos do agregado a partir de fromVersion e segue os novos
+func (s *EventStore) StreamEvents(ctx context.Context, aggregateID string, fromVersion int64) (<-chan *events.Event, error) {
+	next := fromVersion
+	return s.stream(ctx, func() ([]*events.Event, error) {
+		batch, _, err := s.queryEvents(ctx,
+			`SELECT `+eventColumns+` FROM event_store_events
+			 WHERE aggregate_id = $1 AND version >= $2 ORDER BY version LIMIT 500`,
+			aggregateID, next,
+		)
+		if len(batch) > 0 {
+			next = batch[len(batch)-1].Version + 1
+		}
+		return batch, err
+	}), nil
+}
+
+// StreamAllEvents emite todos os eventos a partir de fromTime em ordem global e segue os novos
+func (s *EventStore) StreamAllEvents(ctx context.Context, fromTime time.Time) (<-chan *events.Event, error) {
+	var position int64 = -1
+	return s.stream(ctx, func() ([]*events.Event, error) {
+		var (
+			batch     []*events.Event
+			positions []int64
+			err       error
+		)
+		if position < 0 {
+			batch, positions, err = s.queryEvents(ctx,
+				`SELECT `+eventColumns+` FROM event_store_events
+				 WHERE occurred_at >= $1 ORDER BY position LIMIT 500`,
+				fromTime,
+			)
+		} else {
+			batch, positions, err = s.queryEvents(ctx,
+				`SELECT `+eventColumns+` FROM event_store_events
+				 WHERE position > $1 ORDER BY position LIMIT 500`,
+				position,
+			)
+		}
+		if len(positions) > 0 {
+			position = positions[len(positions)-1]
+		} else if position < 0 && err == nil {
+			position, err = s.headPosition(ctx)
+		}
+		return batch, err
+	}), nil
+}
+
+// GetAggregateInfo retorna versão e estatísticas do agregado
+func (s *EventStore) GetAggregateInfo(ctx context.Context, aggregateID string) (*events.AggregateInfo, error) {
+	var info events.AggregateInfo
+	var firstEvent, lastEvent, lastSnapshot sql.NullTime
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT aggregate_id, aggregate_type, version, event_count, size_bytes,
+		       first_event, last_event, last_snapshot
+		FROM event_store_aggregates WHERE aggregate_id = $1
+	`, aggregateID).Scan(
+		&info.AggregateID, &info.AggregateType, &info.Version, &info.EventCount, &info.Size,
+		&firstEvent, &lastEvent, &lastSnapshot,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", events.ErrAggregateNotFound, aggregateID)
+		}
+		return nil, fmt.Errorf("failed to read aggregate info: %w", err)
+	}
+
+	info.FirstEvent = timePtr(firstEvent)
+	info.LastEvent = timePtr(lastEvent)
+	info.LastSnapshot = timePtr(lastSnapshot)
+	return &info, nil
+}
+
+// GetEventStats retorna estatísticas agregadas do store
+func (s *EventStore) GetEventStats(ctx context.Context) (*events.EventStoreStats, error) {
+	stats := &events.EventStoreStats{
+		EventsByType:    make(map[string]int64),
+		CompactionStats: &events.CompactionStats{},
+	}
+
+	var lastEvent sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(LENGTH(data::text) + LENGTH(metadata::text)), 0), MAX(occurred_at)
+		FROM event_store_events
+	`).Scan(&stats.TotalEvents, &stats.StoreSize, &lastEvent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event stats: %w", err)
+	}
+	stats.LastEvent = timePtr(lastEvent)
+	if stats.TotalEvents > 0 {
+		stats.AverageEventSize = float64(stats.StoreSize) / float64(stats.TotalEvents)
+	}
+
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_store_aggregates`).Scan(&stats.TotalAggregates); err != nil {
+		return nil, fmt.Errorf("failed to count aggregates: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_store_snapshots`).Scan(&stats.SnapshotCount); err != nil {
+		return nil, fmt.Errorf("failed to count snapshots: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT type, COUNT(*) FROM event_store_events GROUP BY type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events by type: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventType string
+		var count int64
+		if err := rows.Scan(&eventType, &count); err != nil {
+			return nil, err
+		}
+		stats.EventsByType[eventType] = count
+	}
+
+	return stats, rows.Err()
+}
+
+// GetStoreInfo retorna informações sobre o store
+func (s *EventStore) GetStoreInfo(ctx context.Context) (*events.EventStoreInfo, error) {
+	return &events.EventStoreInfo{
+		StoreType: s.config.StoreType,
+		Version:   "1.0.0",
+		NodeID:    s.config.NodeID,
+		StartTime: s.startedAt,
+		SupportedFeatures: []string{
+			"save_events",
+			"get_events",
+			"stream_events",
+			"create_snapshots",
+			"compaction",
+			"global_ordering",
+			"optimistic_concurrency",
+		},
+		Configuration: map[string]interface{}{
+			"max_event_size": s.config.MaxEventSize,
+			"event_ttl":      s.config.EventTTL,
+			"poll_interval":  s.pollInterval,
+			"listen_notify":  s.listener != nil,
+		},
+	}, nil
+}
+
+// CreateSnapshot grava (ou substitui) o snapshot do agregado na versão informada
+func (s *EventStore) CreateSnapshot(ctx context.Context, aggregateID string, version int64, snapshotData interface{}) error {
+	data, err := json.Marshal(snapshotData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	var aggregateType string
+	var current int64
+	err = s.db.QueryRowContext(ctx,
+		`SELECT aggregate_type, version FROM event_store_aggregates WHERE aggregate_id = $1`,
+		aggregateID,
+	).Scan(&aggregateType, &current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", events.ErrAggregateNotFound, aggregateID)
+		}
+		return fmt.Errorf("failed to read aggregate info: %w", err)
+	}
+	if version <= 0 || version > current {
+		return fmt.Errorf("version %d not found for aggregate %s", version, aggregateID)
+	}
+
+	now := time.Now().UTC()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO event_store_snapshots (aggregate_id, aggregate_type, version, data, created_at, created_by, size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (aggregate_id) DO UPDATE SET
+			version = EXCLUDED.version, data = EXCLUDED.data, created_at = EXCLUDED.created_at,
+			created_by = EXCLUDED.created_by, size_bytes = EXCLUDED.size_bytes
+		WHERE event_store_snapshots.version <= EXCLUDED.version
+	`, aggregateID, aggregateType, version, data, now, s.config.NodeID, len(data))
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE event_store_aggregates SET last_snapshot = $1 WHERE aggregate_id = $2`, now, aggregateID,
+	); err != nil {
+		return fmt.Errorf("failed to update aggregate info: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetSnapshot retorna o snapshot mais recente do agregado
+func (s *EventStore) GetSnapshot(ctx context.Context, aggregateID string) (*events.Snapshot, error) {
+	var snapshot events.Snapshot
+	var data []byte
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT aggregate_id, aggregate_type, version, data, created_at, created_by, size_bytes
+		FROM event_store_snapshots WHERE aggregate_id = $1
+	`, aggregateID).Scan(
+		&snapshot.AggregateID, &snapshot.AggregateType, &snapshot.Version, &data,
+		&snapshot.CreatedAt, &snapshot.CreatedBy, &snapshot.Size,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("snapshot not found for aggregate: %s", aggregateID)
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	snapshot.Data = json.RawMessage(data)
+	return &snapshot, nil
+}
+
+// Health verifica a conectividade com o banco
+func (s *EventStore) Health(ctx context.Context) (events.EventStoreHealth, error) {
+	health := events.EventStoreHealth{
+		Status:    "healthy",
+		StoreType: s.config.StoreType,
+		NodeID:    s.config.NodeID,
+		Timestamp: time.Now(),
+		Metadata:  map[string]interface{}{},
+	}
+
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_store_events`).Scan(&health.EventCount); err != nil {
+		msg := err.Error()
+		health.Status = "unhealthy"
+		health.LastError = &msg
+		return health, fmt.Errorf("event store unhealthy: %w", err)
+	}
+
+	return health, nil
+}
+
+// CompactEvents remove eventos até targetVersion. Exige um snapshot cobrindo
+// targetVersion para que o agregado continue reconstruível.
+func (s *EventStore) CompactEvents(ctx context.Context, aggregateID string, targetVersion int64) error {
+	var snapshotVersion int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version FROM event_store_snapshots WHERE aggregate_id = $1`, aggregateID,
+	).Scan(&snapshotVersion)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if snapshotVersion < targetVersion {
+		return fmt.Errorf("cannot compact aggregate %s to version %d: latest snapshot is at version %d",
+			aggregateID, targetVersion, snapshotVersion)
+	}
+
+	if err := s.deleteEvents(ctx, `aggregate_id = $1 AND version <= $2`, aggregateID, targetVersion); err != nil {
+		return fmt.Errorf("failed to compact events: %w", err)
+	}
+
+	return nil
+}
+
+// PruneEvents remove eventos anteriores a beforeTime (retenção).
+// A versão de cada stream é preservada em event_store_aggregates.
+func (s *EventStore) PruneEvents(ctx context.Context, beforeTime time.Time) error {
+	if err := s.deleteEvents(ctx, `occurred_at <= $1`, beforeTime); err != nil {
+		return fmt.Errorf("failed to prune events: %w", err)
+	}
+	return nil
+}
+
+// deleteEvents remove os eventos que atendem a condição e desconta a contagem e o tamanho
+// dos agregados na mesma instrução
+func (s *EventStore) deleteEvents(ctx context.Context, condition string, args ...interface{}) error {
+	_, err := s.db.ExecContext(ctx, `
+		WITH removed AS (
+			DELETE FROM event_store_events WHERE `+condition+`
+			RETURNING aggregate_id, size_bytes
+		), totals AS (
+			SELECT aggregate_id, COUNT(*) AS event_count, SUM(size_bytes) AS size_bytes
+			FROM removed GROUP BY aggregate_id
+		)
+		UPDATE event_store_aggregates AS a
+		SET event_count = a.event_count - totals.event_count,
+		    size_bytes = a.size_bytes - totals.size_bytes
+		FROM totals WHERE a.aggregate_id = totals.aggregate_id
+	`, args...)
+	return err
+}
+
+// queryEvents executa a consulta e retorna os eventos com suas posições globais
+func (s *EventStore) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*events.Event, []int64, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	stream := []*events.Event{}
+	var positions []int64
+	for rows.Next() {
+		var event events.Event
+		var position int64
+		var eventType string
+		var data, metadata []byte
+		var causationID, correlationID sql.NullString
+
+		if err := rows.Scan(
+			&position, &event.ID, &event.AggregateID, &event.AggregateType, &event.Version, &eventType,
+			&data, &metadata, &event.Timestamp, &event.NodeID, &causationID, &correlationID,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		event.Type = events.EventType(eventType)
+		event.Data = json.RawMessage(data)
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+				return nil, nil, fmt.Errorf("failed to unmarshal event metadata: %w", err)
+			}
+		}
+		event.CausationID = causationID.String
+		event.CorrelationID = correlationID.String
+
+		stream = append(stream, &event)
+		positions = append(positions, position)
+	}
+
+	return stream, positions, rows.Err()
+}
+
+func (s *EventStore) headPosition(ctx context.Context) (int64, error) {
+	var position int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) FROM event_store_events`).Scan(&position); err != nil {
+		return 0, fmt.Errorf("failed to read head position: %w", err)
+	}
+	return position, nil
+}
+
+// stream executa fetch até esgotar, depois aguarda notificação ou polling
+func (s *EventStore) stream(ctx context.Context, fetch func() ([]*events.Event, error)) <-chan *events.Event {
+	out := make(chan *events.Event, s.config.StreamBufferSize)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			wake := s.waitChannel()
+
+			batch, err := fetch()
+			if err != nil {
+				// Erro transitório: tenta novamente no próximo ciclo
+				batch = nil
+			}
+
+			for _, event := range batch {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				case <-s.done:
+					return
+				}
+			}
+
+			if len(batch) > 0 {
+				continue
+			}
+
+			select {
+			case <-wake:
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (s *EventStore) waitChannel() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.wake
+}
+
+// broadcast acorda todos os streams aguardando novos eventos
+func (s *EventStore) broadcast() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
+
+func limitClause(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/state/events"
+)
+
+// newTestEventStore abre o banco de TEST_DATABASE_URL e aplica as migrações; sem a variável o teste é ignorado
+func newTestEventStore(t *testing.T) *EventStore {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrator.Up() error = %v", err)
+	}
+
+	store := NewEventStore(db, nil)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func testEvent(aggregateID string, version int64, payload string) *events.Event {
+	return &events.Event{
+		ID:            uuid.New().String(),
+		Type:          events.EventTypeUpdate,
+		AggregateID:   aggregateID,
+		AggregateType: "test",
+		Version:       version,
+		Data:          map[string]string{"value": payload},
+		Timestamp:     time.Now(),
+		NodeID:        "node-1",
+	}
+}
+
+func TestEventStore_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	id := uuid.New().String()
+
+	if err := store.SaveEvent(ctx, testEvent(id, 1, "a")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+	if err := store.SaveEvent(ctx, testEvent(id, 1, "stale")); !errors.Is(err, events.ErrVersionConflict) {
+		t.Errorf("SaveEvent() error = %v, want ErrVersionConflict", err)
+	}
+
+	// Um lote com falha não é aplicado parcialmente
+	err := store.SaveEvents(ctx, []*events.Event{testEvent(id, 2, "b"), testEvent(id, 4, "gap")})
+	if !errors.Is(err, events.ErrVersionConflict) {
+		t.Errorf("SaveEvents() error = %v, want ErrVersionConflict", err)
+	}
+	stream, err := store.GetAllEvents(ctx, id)
+	if err != nil || len(stream) != 1 {
+		t.Errorf("GetAllEvents() = %d events, %v; want 1", len(stream), err)
+	}
+
+	info, err := store.GetAggregateInfo(ctx, id)
+	if err != nil || info.Version != 1 {
+		t.Errorf("GetAggregateInfo() = %+v, %v; want version 1", info, err)
+	}
+	if _, err := store.GetAggregateInfo(ctx, uuid.New().String()); !errors.Is(err, events.ErrAggregateNotFound) {
+		t.Errorf("GetAggregateInfo() error = %v, want ErrAggregateNotFound", err)
+	}
+}
+
+func TestEventStore_CompactRequiresSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	id := uuid.New().String()
+
+	for v := int64(1); v <= 3; v++ {
+		if err := store.SaveEvent(ctx, testEvent(id, v, "x")); err != nil {
+			t.Fatalf("SaveEvent() error = %v", err)
+		}
+	}
+
+	if err := store.CompactEvents(ctx, id, 2); err == nil {
+		t.Error("CompactEvents() should fail without a covering snapshot")
+	}
+	if err := store.CreateSnapshot(ctx, id, 2, map[string]string{"value": "x"}); err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if err := store.CompactEvents(ctx, id, 2); err != nil {
+		t.Fatalf("CompactEvents() error = %v", err)
+	}
+
+	stream, _ := store.GetAllEvents(ctx, id)
+	if len(stream) != 1 || stream[0].Version != 3 {
+		t.Errorf("expected only version 3 after compaction, got %d events", len(stream))
+	}
+	if err := store.SaveEvent(ctx, testEvent(id, 4, "y")); err != nil {
+		t.Errorf("SaveEvent() after compaction error = %v", err)
+	}
+}
+
+func TestEventStore_PruneKeepsStreamVersion(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	id := uuid.New().String()
+
+	old := testEvent(id, 1, "a")
+	old.Timestamp = time.Now().Add(-time.Hour)
+	if err := store.SaveEvents(ctx, []*events.Event{old, testEvent(id, 2, "b")}); err != nil {
+		t.Fatalf("SaveEvents() error = %v", err)
+	}
+
+	if err := store.PruneEvents(ctx, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("PruneEvents() error = %v", err)
+	}
+
+	stream, _ := store.GetAllEvents(ctx, id)
+	if len(stream) != 1 || stream[0].Version != 2 {
+		t.Errorf("expected only version 2 after pruning, got %d events", len(stream))
+	}
+	info, err := store.GetAggregateInfo(ctx, id)
+	if err != nil || info.Version != 2 || info.FirstEvent == nil {
+		t.Errorf("GetAggregateInfo() = %+v, %v; want version 2 with the first event time", info, err)
+	}
+	if err == nil && info.EventCount != 1 {
+		t.Errorf("GetAggregateInfo().EventCount = %d after pruning, want 1", info.EventCount)
+	}
+	if err := store.SaveEvent(ctx, testEvent(id, 3, "c")); err != nil {
+		t.Errorf("SaveEvent() after pruning error = %v", err)
+	}
+}
+
+func TestEventStore_StreamAllEventsInAppendOrder(t *testing.T) {
+	store := newTestEventStore(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, second := uuid.New().String(), uuid.New().String()
+	from := time.Now()
+	if err := store.SaveEvent(ctx, testEvent(first, 1, "a")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+
+	stream, err := store.StreamAllEvents(ctx, from)
+	if err != nil {
+		t.Fatalf("StreamAllEvents() error = %v", err)
+	}
+	if err := store.SaveEvent(ctx, testEvent(second, 1, "b")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+
+	// Outros testes podem gravar no mesmo banco: considera apenas os agregados deste teste
+	var seen []string
+	for len(seen) < 2 {
+		select {
+		case event := <-stream:
+			if event == nil {
+				t.Fatal("stream closed before both events")
+			}
+			if event.AggregateID == first || event.AggregateID == second {
+				seen = append(seen, event.AggregateID)
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for streamed events")
+		}
+	}
+	if seen[0] != first || seen[1] != second {
+		t.Errorf("expected append order, got %v", seen)
+	}
+}
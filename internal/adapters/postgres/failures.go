@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// entityOperations mapeia o tipo de entidade para o tipo de operação (SLA, máquina de estados)
+var entityOperations = map[string]fulfillment.OperationType{
+	fulfillment.EntityInboundShipment:  fulfillment.OpInbound,
+	fulfillment.EntityFulfillmentOrder: fulfillment.OpOutbound,
+	fulfillment.EntityTransferOrder:    fulfillment.OpTransfer,
+	fulfillment.EntityReturnOrder:      fulfillment.OpReturn,
+	fulfillment.EntityCycleCountTask:   fulfillment.OpCycleCount,
+	fulfillment.EntityAssemblyOrder:    fulfillment.OpAssembly,
+}
+
+// ListFailedOperations lista operações em FAILED desde since, da falha mais antiga para a mais recente.
+// entityType vazio consulta todas as entidades.
+func (r *FulfillmentRepository) ListFailedOperations(ctx context.Context, entityType string, since time.Time, limit int) ([]fulfillment.FailedOperation, error) {
+	return r.listFailed(ctx, entityType, nil, since, limit)
+}
+
+// ListDueFailures filtra a classe, o backoff e o corte de SLA na consulta, para que falhas não elegíveis
+// não ocupem o lote do agendador
+func (r *FulfillmentRepository) ListDueFailures(ctx context.Context, since, now time.Time, createdAfter map[fulfillment.OperationType]time.Time, limit int) ([]fulfillment.FailedOperation, error) {
+	args := []any{now}
+	filters := make(map[string]string, len(failedEntityTypes))
+	for _, entity := range failedEntityTypes {
+		filter := fmt.Sprintf(` AND failure->>'class' = '%s' AND (failure->>'next_retry_at')::timestamptz <= $3`, fulfillment.FailureTransient)
+		if cutoff, ok := createdAfter[entityOperations[entity]]; ok {
+			args = append(args, cutoff)
+			filter += fmt.Sprintf(` AND created_at >= $%d`, len(args)+2)
+		}
+		filters[entity] = filter
+	}
+	return r.listFailed(ctx, "", filters, since, limit, args...)
+}
+
+// listFailed monta a consulta UNION das entidades; filters acrescenta condições por entidade,
+// com argumentos a partir de $3
+func (r *FulfillmentRepository) listFailed(ctx context.Context, entityType string, filters map[string]string, since time.Time, limit int, args ...any) ([]fulfillment.FailedOperation, error) {
+	var selects []string
+	for _, entity := range failedEntityTypes {
+		if entityType != "" && entityType != entity {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf(
+			`SELECT '%s' AS entity_type, id, created_at, updated_at, failure FROM %s WHERE status = 'FAILED' AND updated_at >= $1%s`,
+			entity, entityTables[entity], filters[entity],
+		))
+	}
+	if len(selects) == 0 {
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	query := strings.Join(selects, " UNION ALL ") + ` ORDER BY updated_at LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, append([]any{since, limit}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failed operations: %w", err)
+	}
+	defer rows.Close()
+
+	var operations []fulfillment.FailedOperation
+	for rows.Next() {
+		var op fulfillment.FailedOperation
+		var updatedAt time.Time
+		var failureJSON []byte
+		if err := rows.Scan(&op.EntityType, &op.EntityID, &op.CreatedAt, &updatedAt, &failureJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan failed operation: %w", err)
+		}
+		if op.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+			return nil, err
+		}
+		op.Operation = entityOperations[op.EntityType]
+		operations = append(operations, op)
+	}
+	return operations, rows.Err()
+}
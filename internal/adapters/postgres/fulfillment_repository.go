@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/lib/pq"
 	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
 
@@ -35,40 +34,37 @@ func (r *FulfillmentRepository) CreateInbound(ctx context.Context, shipment *ful
 			id, reference_id, origin, destination, status, 
 			items, idempotency_key, created_at, updated_at
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
 	`
 
-	_, err = r.db.ExecContext(ctx, query,
-		shipment.ID, shipment.ReferenceID, shipment.Origin, shipment.Destination,
-		shipment.Status, itemsJSON, shipment.IdempotencyKey,
-		shipment.CreatedAt, shipment.UpdatedAt,
-	)
-
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			// Duplicate key - idempotency
-			return nil
+	return r.createWithHistory(ctx, fulfillment.EntityInboundShipment, shipment.ID, shipment.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			shipment.ID, shipment.ReferenceID, shipment.Origin, shipment.Destination,
+			shipment.Status, itemsJSON, shipment.IdempotencyKey,
+			shipment.CreatedAt, shipment.UpdatedAt,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert inbound shipment: %w", err)
 		}
-		return fmt.Errorf("failed to insert inbound shipment: %w", err)
-	}
-
-	return nil
+		return insertedRow(result)
+	})
 }
 
 func (r *FulfillmentRepository) GetInboundByID(ctx context.Context, id string) (*fulfillment.InboundShipment, error) {
 	query := `
 		SELECT id, reference_id, origin, destination, status, items, 
-		       idempotency_key, created_at, updated_at, completed_at
+		       idempotency_key, created_at, updated_at, completed_at, failure, block
 		FROM inbound_shipments WHERE id = $1
 	`
 
 	var shipment fulfillment.InboundShipment
-	var itemsJSON []byte
+	var itemsJSON, failureJSON, blockJSON []byte
 	var completedAt sql.NullTime
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&shipment.ID, &shipment.ReferenceID, &shipment.Origin, &shipment.Destination,
 		&shipment.Status, &itemsJSON, &shipment.IdempotencyKey,
-		&shipment.CreatedAt, &shipment.UpdatedAt, &completedAt,
+		&shipment.CreatedAt, &shipment.UpdatedAt, &completedAt, &failureJSON, &blockJSON,
 	)
 
 	if err != nil {
@@ -86,24 +82,31 @@ func (r *FulfillmentRepository) GetInboundByID(ctx context.Context, id string) (
 		shipment.CompletedAt = &completedAt.Time
 	}
 
+	if shipment.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	if shipment.Blocked, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+		return nil, err
+	}
+
 	return &shipment, nil
 }
 
 func (r *FulfillmentRepository) GetInboundByReferenceID(ctx context.Context, referenceID string) (*fulfillment.InboundShipment, error) {
 	query := `
 		SELECT id, reference_id, origin, destination, status, items, 
-		       idempotency_key, created_at, updated_at, completed_at
+		       idempotency_key, created_at, updated_at, completed_at, failure, block
 		FROM inbound_shipments WHERE reference_id = $1 LIMIT 1
 	`
 
 	var shipment fulfillment.InboundShipment
-	var itemsJSON []byte
+	var itemsJSON, failureJSON, blockJSON []byte
 	var completedAt sql.NullTime
 
 	err := r.db.QueryRowContext(ctx, query, referenceID).Scan(
 		&shipment.ID, &shipment.ReferenceID, &shipment.Origin, &shipment.Destination,
 		&shipment.Status, &itemsJSON, &shipment.IdempotencyKey,
-		&shipment.CreatedAt, &shipment.UpdatedAt, &completedAt,
+		&shipment.CreatedAt, &shipment.UpdatedAt, &completedAt, &failureJSON, &blockJSON,
 	)
 
 	if err != nil {
@@ -121,15 +124,18 @@ func (r *FulfillmentRepository) GetInboundByReferenceID(ctx context.Context, ref
 		shipment.CompletedAt = &completedAt.Time
 	}
 
+	if shipment.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	if shipment.Blocked, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+		return nil, err
+	}
+
 	return &shipment, nil
 }
 
 func (r *FulfillmentRepository) UpdateInboundStatus(ctx context.Context, id string, status fulfillment.Status) error {
-	return r.UpdateInbound(ctx, &fulfillment.InboundShipment{
-		ID:        id,
-		Status:    status,
-		UpdatedAt: time.Now(),
-	})
+	return r.updateStatus(ctx, fulfillment.EntityInboundShipment, id, status, fulfillment.ErrShipmentNotFound)
 }
 
 func (r *FulfillmentRepository) UpdateInbound(ctx context.Context, shipment *fulfillment.InboundShipment) error {
@@ -138,10 +144,19 @@ func (r *FulfillmentRepository) UpdateInbound(ctx context.Context, shipment *ful
 		return fmt.Errorf("failed to marshal items: %w", err)
 	}
 
+	failureJSON, err := marshalOptional(shipment.Failure, "failure")
+	if err != nil {
+		return err
+	}
+	blockJSON, err := marshalOptional(shipment.Blocked, "block")
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE inbound_shipments
-		SET status = $1, items = $2, updated_at = $3, completed_at = $4
-		WHERE id = $5
+		SET status = $1, items = $2, updated_at = $3, completed_at = $4, failure = $5, block = $6
+		WHERE id = $7
 	`
 
 	var completedAt interface{}
@@ -151,24 +166,14 @@ func (r *FulfillmentRepository) UpdateInbound(ctx context.Context, shipment *ful
 		completedAt = nil
 	}
 
-	result, err := r.db.ExecContext(ctx, query,
-		shipment.Status, itemsJSON, time.Now(), completedAt, shipment.ID,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to update inbound shipment: %w", err)
-	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-
-	if rows == 0 {
-		return fulfillment.ErrShipmentNotFound
-	}
-
-	return nil
+	return r.updateWithHistory(ctx, fulfillment.EntityInboundShipment, shipment.ID, shipment.Status, fulfillment.ErrShipmentNotFound, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			shipment.Status, itemsJSON, time.Now(), completedAt, failureJSON, blockJSON, shipment.ID,
+		); err != nil {
+			return fmt.Errorf("failed to update inbound shipment: %w", err)
+		}
+		return nil
+	})
 }
 
 // Outbound methods (similar pattern - implementação completa seria muito longa, mas segue o mesmo padrão)
@@ -182,81 +187,124 @@ func (r *FulfillmentRepository) CreateOrder(ctx context.Context, order *fulfillm
 	query := `
 		INSERT INTO fulfillment_orders (
 			id, order_id, customer, destination, status, 
-			items, priority, idempotency_key, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			items, priority, reservation_status, reservation_expires_at,
+			idempotency_key, created_at, updated_at, version,
+			ship_by, deliver_by, carrier, service, customer_tier
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (id) DO NOTHING
 	`
 
-	_, err = r.db.ExecContext(ctx, query,
-		order.ID, order.OrderID, order.Customer, order.Destination,
-		order.Status, itemsJSON, order.Priority, order.IdempotencyKey,
-		order.CreatedAt, order.UpdatedAt,
-	)
+	return r.createWithHistory(ctx, fulfillment.EntityFulfillmentOrder, order.ID, order.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			order.ID, order.OrderID, order.Customer, order.Destination,
+			order.Status, itemsJSON, order.Priority, reservationStatusOrNone(order.ReservationStatus),
+			nullableTime(order.ReservationExpiresAt), order.IdempotencyKey,
+			order.CreatedAt, order.UpdatedAt, order.Version,
+			nullableTime(order.ShipBy), nullableTime(order.DeliverBy), nullableString(order.Carrier),
+			nullableString(order.Service), nullableString(string(order.CustomerTier)),
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert fulfillment order: %w", err)
+		}
+		return insertedRow(result)
+	})
+}
+
+const orderColumns = `
+		id, order_id, customer, destination, status, items, 
+		priority, reservation_status, reservation_expires_at,
+		idempotency_key, created_at, updated_at, shipped_at, version, failure, block,
+		ship_by, deliver_by, COALESCE(carrier, ''), COALESCE(service, ''), COALESCE(customer_tier, '')
+`
+
+func (r *FulfillmentRepository) GetOrderByID(ctx context.Context, id string) (*fulfillment.FulfillmentOrder, error) {
+	query := `SELECT ` + orderColumns + ` FROM fulfillment_orders WHERE id = $1`
 
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return nil // Idempotency
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrOrderNotFound
 		}
-		return fmt.Errorf("failed to insert fulfillment order: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return order, nil
 }
 
-func (r *FulfillmentRepository) GetOrderByID(ctx context.Context, id string) (*fulfillment.FulfillmentOrder, error) {
-	query := `
-		SELECT id, order_id, customer, destination, status, items, 
-		       priority, idempotency_key, created_at, updated_at, shipped_at
-		FROM fulfillment_orders WHERE id = $1
-	`
-
-	var order fulfillment.FulfillmentOrder
-	var itemsJSON []byte
-	var shippedAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&order.ID, &order.OrderID, &order.Customer, &order.Destination,
-		&order.Status, &itemsJSON, &order.Priority, &order.IdempotencyKey,
-		&order.CreatedAt, &order.UpdatedAt, &shippedAt,
-	)
+func (r *FulfillmentRepository) GetOrderByOrderID(ctx context.Context, orderID string) (*fulfillment.FulfillmentOrder, error) {
+	query := `SELECT ` + orderColumns + ` FROM fulfillment_orders WHERE order_id = $1 LIMIT 1`
 
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, orderID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fulfillment.ErrOrderNotFound
 		}
-		return nil, fmt.Errorf("failed to scan fulfillment order: %w", err)
+		return nil, err
 	}
 
-	if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	return order, nil
+}
+
+func (r *FulfillmentRepository) ListOrdersByReservationStatus(ctx context.Context, status fulfillment.ReservationStatus, afterID string, limit int) ([]*fulfillment.FulfillmentOrder, error) {
+	query := `SELECT ` + orderColumns + `
+		FROM fulfillment_orders WHERE reservation_status = $1 AND id > $2
+		ORDER BY id LIMIT $3`
+
+	return r.listOrders(ctx, query, status, afterID, limit)
+}
+
+// ListOrphanedReservations filtra as reservas órfãs na consulta, para que ordens em separação com a
+// reserva vencida não ocupem o lote do reconciliador
+func (r *FulfillmentRepository) ListOrphanedReservations(ctx context.Context, now time.Time, limit int) ([]*fulfillment.FulfillmentOrder, error) {
+	query := `SELECT ` + orderColumns + `
+		FROM fulfillment_orders WHERE reservation_status = $1
+		AND (status IN ($2, $3) OR (status = $4 AND reservation_expires_at < $5))
+		ORDER BY reservation_expires_at NULLS LAST LIMIT $6`
+
+	return r.listOrders(ctx, query, fulfillment.ReservationReserved,
+		fulfillment.StatusCancelled, fulfillment.StatusFailed, fulfillment.StatusPending, now, limit)
+}
+
+func (r *FulfillmentRepository) listOrders(ctx context.Context, query string, args ...interface{}) ([]*fulfillment.FulfillmentOrder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fulfillment orders: %w", err)
 	}
+	defer rows.Close()
 
-	if shippedAt.Valid {
-		order.ShippedAt = &shippedAt.Time
+	var orders []*fulfillment.FulfillmentOrder
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
 	}
 
-	return &order, nil
+	return orders, rows.Err()
 }
 
-func (r *FulfillmentRepository) GetOrderByOrderID(ctx context.Context, orderID string) (*fulfillment.FulfillmentOrder, error) {
-	query := `
-		SELECT id, order_id, customer, destination, status, items, 
-		       priority, idempotency_key, created_at, updated_at, shipped_at
-		FROM fulfillment_orders WHERE order_id = $1 LIMIT 1
-	`
+// rowScanner abstrai *sql.Row e *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+func scanOrder(row rowScanner) (*fulfillment.FulfillmentOrder, error) {
 	var order fulfillment.FulfillmentOrder
-	var itemsJSON []byte
-	var shippedAt sql.NullTime
+	var itemsJSON, failureJSON, blockJSON []byte
+	var shippedAt, reservationExpiresAt, shipBy, deliverBy sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
+	err := row.Scan(
 		&order.ID, &order.OrderID, &order.Customer, &order.Destination,
-		&order.Status, &itemsJSON, &order.Priority, &order.IdempotencyKey,
-		&order.CreatedAt, &order.UpdatedAt, &shippedAt,
+		&order.Status, &itemsJSON, &order.Priority, &order.ReservationStatus,
+		&reservationExpiresAt, &order.IdempotencyKey,
+		&order.CreatedAt, &order.UpdatedAt, &shippedAt, &order.Version, &failureJSON, &blockJSON,
+		&shipBy, &deliverBy, &order.Carrier, &order.Service, &order.CustomerTier,
 	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fulfillment.ErrOrderNotFound
+			return nil, err
 		}
 		return nil, fmt.Errorf("failed to scan fulfillment order: %w", err)
 	}
@@ -269,15 +317,24 @@ func (r *FulfillmentRepository) GetOrderByOrderID(ctx context.Context, orderID s
 		order.ShippedAt = &shippedAt.Time
 	}
 
+	if reservationExpiresAt.Valid {
+		order.ReservationExpiresAt = &reservationExpiresAt.Time
+	}
+
+	order.ShipBy, order.DeliverBy = timePtr(shipBy), timePtr(deliverBy)
+
+	if order.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	if order.Blocked, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+		return nil, err
+	}
+
 	return &order, nil
 }
 
 func (r *FulfillmentRepository) UpdateOrderStatus(ctx context.Context, id string, status fulfillment.Status) error {
-	return r.UpdateOrder(ctx, &fulfillment.FulfillmentOrder{
-		ID:        id,
-		Status:    status,
-		UpdatedAt: time.Now(),
-	})
+	return r.updateStatus(ctx, fulfillment.EntityFulfillmentOrder, id, status, fulfillment.ErrOrderNotFound)
 }
 
 func (r *FulfillmentRepository) UpdateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
@@ -286,107 +343,393 @@ func (r *FulfillmentRepository) UpdateOrder(ctx context.Context, order *fulfillm
 		return fmt.Errorf("failed to marshal items: %w", err)
 	}
 
+	failureJSON, err := marshalOptional(order.Failure, "failure")
+	if err != nil {
+		return err
+	}
+	blockJSON, err := marshalOptional(order.Blocked, "block")
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE fulfillment_orders
-		SET status = $1, items = $2, updated_at = $3, shipped_at = $4
-		WHERE id = $5
+		SET status = $1, items = $2, updated_at = $3, shipped_at = $4,
+		    reservation_status = $5, reservation_expires_at = $6, version = $7, failure = $8, block = $9
+		WHERE id = $10
 	`
 
-	var shippedAt interface{}
-	if order.ShippedAt != nil {
-		shippedAt = *order.ShippedAt
-	} else {
-		shippedAt = nil
-	}
+	return r.updateWithHistory(ctx, fulfillment.EntityFulfillmentOrder, order.ID, order.Status, fulfillment.ErrOrderNotFound, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			order.Status, itemsJSON, time.Now(), nullableTime(order.ShippedAt),
+			reservationStatusOrNone(order.ReservationStatus), nullableTime(order.ReservationExpiresAt),
+			order.Version, failureJSON, blockJSON, order.ID,
+		); err != nil {
+			return fmt.Errorf("failed to update fulfillment order: %w", err)
+		}
+		return nil
+	})
+}
 
-	result, err := r.db.ExecContext(ctx, query,
-		order.Status, itemsJSON, time.Now(), shippedAt, order.ID,
-	)
+// Transfer methods
 
+func (r *FulfillmentRepository) CreateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	itemsJSON, err := json.Marshal(transfer.Items)
 	if err != nil {
-		return fmt.Errorf("failed to update fulfillment order: %w", err)
+		return fmt.Errorf("failed to marshal items: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
+	query := `
+		INSERT INTO transfer_orders (
+			id, location_from, location_to, status, items,
+			idempotency_key, created_at, updated_at, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	return r.createWithHistory(ctx, fulfillment.EntityTransferOrder, transfer.ID, transfer.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			transfer.ID, transfer.LocationFrom, transfer.LocationTo, transfer.Status,
+			itemsJSON, transfer.IdempotencyKey, transfer.CreatedAt, transfer.UpdatedAt, transfer.Version,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert transfer order: %w", err)
+		}
+		return insertedRow(result)
+	})
+}
+
+func (r *FulfillmentRepository) GetTransferByID(ctx context.Context, id string) (*fulfillment.TransferOrder, error) {
+	query := `
+		SELECT id, location_from, location_to, status, items,
+		       idempotency_key, created_at, updated_at, completed_at, version, failure, block
+		FROM transfer_orders WHERE id = $1
+	`
+
+	var transfer fulfillment.TransferOrder
+	var itemsJSON, failureJSON, blockJSON []byte
+	var completedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&transfer.ID, &transfer.LocationFrom, &transfer.LocationTo, &transfer.Status,
+		&itemsJSON, &transfer.IdempotencyKey, &transfer.CreatedAt, &transfer.UpdatedAt,
+		&completedAt, &transfer.Version, &failureJSON, &blockJSON,
+	)
+
 	if err != nil {
-		return err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to scan transfer order: %w", err)
 	}
 
-	if rows == 0 {
-		return fulfillment.ErrOrderNotFound
+	if err := json.Unmarshal(itemsJSON, &transfer.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
 	}
 
-	return nil
-}
-
-// Transfer, Return, CycleCount methods - implementação similar (omitted for brevity, but same pattern)
-// Implementação completa seguiria o mesmo padrão acima
+	if completedAt.Valid {
+		transfer.CompletedAt = &completedAt.Time
+	}
 
-func (r *FulfillmentRepository) CreateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
-	// Similar to CreateInbound
-	return nil
-}
+	if transfer.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	if transfer.Blocked, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+		return nil, err
+	}
 
-func (r *FulfillmentRepository) GetTransferByID(ctx context.Context, id string) (*fulfillment.TransferOrder, error) {
-	// Similar to GetInboundByID
-	return nil, nil
+	return &transfer, nil
 }
 
 func (r *FulfillmentRepository) UpdateTransferStatus(ctx context.Context, id string, status fulfillment.Status) error {
-	return r.UpdateTransfer(ctx, &fulfillment.TransferOrder{
-		ID:        id,
-		Status:    status,
-		UpdatedAt: time.Now(),
-	})
+	return r.updateStatus(ctx, fulfillment.EntityTransferOrder, id, status, fulfillment.ErrTransferNotFound)
 }
 
 func (r *FulfillmentRepository) UpdateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
-	// Similar to UpdateInbound
-	return nil
+	itemsJSON, err := json.Marshal(transfer.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal items: %w", err)
+	}
+
+	failureJSON, err := marshalOptional(transfer.Failure, "failure")
+	if err != nil {
+		return err
+	}
+	blockJSON, err := marshalOptional(transfer.Blocked, "block")
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE transfer_orders
+		SET status = $1, items = $2, updated_at = $3, completed_at = $4, version = $5, failure = $6, block = $7
+		WHERE id = $8
+	`
+
+	return r.updateWithHistory(ctx, fulfillment.EntityTransferOrder, transfer.ID, transfer.Status, fulfillment.ErrTransferNotFound, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			transfer.Status, itemsJSON, time.Now(), nullableTime(transfer.CompletedAt), transfer.Version, failureJSON, blockJSON, transfer.ID,
+		); err != nil {
+			return fmt.Errorf("failed to update transfer order: %w", err)
+		}
+		return nil
+	})
 }
 
+// Return methods
+
 func (r *FulfillmentRepository) CreateReturn(ctx context.Context, returnOrder *fulfillment.ReturnOrder) error {
-	// Similar pattern
-	return nil
+	itemsJSON, err := json.Marshal(returnOrder.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal items: %w", err)
+	}
+
+	query := `
+		INSERT INTO return_orders (
+			id, original_order_id, reason, status, items,
+			idempotency_key, created_at, updated_at, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	return r.createWithHistory(ctx, fulfillment.EntityReturnOrder, returnOrder.ID, returnOrder.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			returnOrder.ID, returnOrder.OriginalOrderID, returnOrder.Reason, returnOrder.Status,
+			itemsJSON, returnOrder.IdempotencyKey, returnOrder.CreatedAt, returnOrder.UpdatedAt, returnOrder.Version,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert return order: %w", err)
+		}
+		return insertedRow(result)
+	})
 }
 
 func (r *FulfillmentRepository) GetReturnByID(ctx context.Context, id string) (*fulfillment.ReturnOrder, error) {
-	// Similar pattern
-	return nil, nil
+	query := `
+		SELECT id, original_order_id, reason, status, items,
+		       idempotency_key, created_at, updated_at, completed_at, version,
+		       location, failure, block
+		FROM return_orders WHERE id = $1
+	`
+
+	var returnOrder fulfillment.ReturnOrder
+	var itemsJSON, failureJSON, blockJSON []byte
+	var reason, location sql.NullString
+	var completedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&returnOrder.ID, &returnOrder.OriginalOrderID, &reason, &returnOrder.Status,
+		&itemsJSON, &returnOrder.IdempotencyKey, &returnOrder.CreatedAt, &returnOrder.UpdatedAt,
+		&completedAt, &returnOrder.Version, &location, &failureJSON, &blockJSON,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrReturnNotFound
+		}
+		return nil, fmt.Errorf("failed to scan return order: %w", err)
+	}
+
+	if err := json.Unmarshal(itemsJSON, &returnOrder.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	returnOrder.Reason = reason.String
+	returnOrder.Location = location.String
+	if completedAt.Valid {
+		returnOrder.CompletedAt = &completedAt.Time
+	}
+
+	if returnOrder.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	if returnOrder.Blocked, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+		return nil, err
+	}
+
+	return &returnOrder, nil
 }
 
 func (r *FulfillmentRepository) UpdateReturnStatus(ctx context.Context, id string, status fulfillment.Status) error {
-	return r.UpdateReturn(ctx, &fulfillment.ReturnOrder{
-		ID:        id,
-		Status:    status,
-		UpdatedAt: time.Now(),
-	})
+	return r.updateStatus(ctx, fulfillment.EntityReturnOrder, id, status, fulfillment.ErrReturnNotFound)
 }
 
 func (r *FulfillmentRepository) UpdateReturn(ctx context.Context, returnOrder *fulfillment.ReturnOrder) error {
-	// Similar pattern
-	return nil
+	itemsJSON, err := json.Marshal(returnOrder.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal items: %w", err)
+	}
+
+	failureJSON, err := marshalOptional(returnOrder.Failure, "failure")
+	if err != nil {
+		return err
+	}
+	blockJSON, err := marshalOptional(returnOrder.Blocked, "block")
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE return_orders
+		SET status = $1, items = $2, updated_at = $3, completed_at = $4, version = $5,
+		    location = $6, failure = $7, block = $8
+		WHERE id = $9
+	`
+
+	return r.updateWithHistory(ctx, fulfillment.EntityReturnOrder, returnOrder.ID, returnOrder.Status, fulfillment.ErrReturnNotFound, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			returnOrder.Status, itemsJSON, time.Now(), nullableTime(returnOrder.CompletedAt), returnOrder.Version,
+			nullableString(returnOrder.Location), failureJSON, blockJSON, returnOrder.ID,
+		); err != nil {
+			return fmt.Errorf("failed to update return order: %w", err)
+		}
+		return nil
+	})
 }
 
+// CycleCount methods
+
 func (r *FulfillmentRepository) CreateCycleCount(ctx context.Context, task *fulfillment.CycleCountTask) error {
-	// Similar pattern
-	return nil
+	skusJSON, err := json.Marshal(task.SKUs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal skus: %w", err)
+	}
+	countedJSON, err := json.Marshal(task.CountedItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal counted items: %w", err)
+	}
+
+	query := `
+		INSERT INTO cycle_count_tasks (
+			id, location, skus, status, counted_items,
+			idempotency_key, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	return r.createWithHistory(ctx, fulfillment.EntityCycleCountTask, task.ID, task.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			task.ID, task.Location, skusJSON, task.Status, countedJSON,
+			task.IdempotencyKey, task.CreatedAt, task.UpdatedAt,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert cycle count task: %w", err)
+		}
+		return insertedRow(result)
+	})
 }
 
 func (r *FulfillmentRepository) GetCycleCountByID(ctx context.Context, id string) (*fulfillment.CycleCountTask, error) {
-	// Similar pattern
-	return nil, nil
+	query := `
+		SELECT id, location, skus, status, counted_items,
+		       idempotency_key, created_at, updated_at, completed_at, failure, block
+		FROM cycle_count_tasks WHERE id = $1
+	`
+
+	var task fulfillment.CycleCountTask
+	var skusJSON, countedJSON, failureJSON, blockJSON []byte
+	var completedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&task.ID, &task.Location, &skusJSON, &task.Status, &countedJSON,
+		&task.IdempotencyKey, &task.CreatedAt, &task.UpdatedAt, &completedAt, &failureJSON, &blockJSON,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrCycleCountNotFound
+		}
+		return nil, fmt.Errorf("failed to scan cycle count task: %w", err)
+	}
+
+	if err := json.Unmarshal(skusJSON, &task.SKUs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal skus: %w", err)
+	}
+	if err := json.Unmarshal(countedJSON, &task.CountedItems); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal counted items: %w", err)
+	}
+
+	task.CompletedAt = timePtr(completedAt)
+
+	if task.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	if task.Blocked, err = unmarshalOptional[fulfillment.Block](blockJSON, "block"); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
 }
 
 func (r *FulfillmentRepository) UpdateCycleCountStatus(ctx context.Context, id string, status fulfillment.Status) error {
-	return r.UpdateCycleCount(ctx, &fulfillment.CycleCountTask{
-		ID:        id,
-		Status:    status,
-		UpdatedAt: time.Now(),
-	})
+	return r.updateStatus(ctx, fulfillment.EntityCycleCountTask, id, status, fulfillment.ErrCycleCountNotFound)
 }
 
 func (r *FulfillmentRepository) UpdateCycleCount(ctx context.Context, task *fulfillment.CycleCountTask) error {
-	// Similar pattern
-	return nil
+	countedJSON, err := json.Marshal(task.CountedItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal counted items: %w", err)
+	}
+
+	failureJSON, err := marshalOptional(task.Failure, "failure")
+	if err != nil {
+		return err
+	}
+	blockJSON, err := marshalOptional(task.Blocked, "block")
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE cycle_count_tasks
+		SET status = $1, counted_items = $2, updated_at = $3, completed_at = $4, failure = $5, block = $6
+		WHERE id = $7
+	`
+
+	return r.updateWithHistory(ctx, fulfillment.EntityCycleCountTask, task.ID, task.Status, fulfillment.ErrCycleCountNotFound, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			task.Status, countedJSON, time.Now(), nullableTime(task.CompletedAt), failureJSON, blockJSON, task.ID,
+		); err != nil {
+			return fmt.Errorf("failed to update cycle count task: %w", err)
+		}
+		return nil
+	})
+}
+
+// nullableTime converte *time.Time em valor aceito pelo driver (NULL quando nil)
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// marshalOptional converte um valor opcional em JSONB (NULL quando nil)
+func marshalOptional[T any](v *T, what string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", what, err)
+	}
+	return data, nil
+}
+
+func unmarshalOptional[T any](data []byte, what string) (*T, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", what, err)
+	}
+	return &v, nil
+}
+
+func reservationStatusOrNone(status fulfillment.ReservationStatus) fulfillment.ReservationStatus {
+	if status == "" {
+		return fulfillment.ReservationNone
+	}
+	return status
 }
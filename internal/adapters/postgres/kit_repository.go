@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const assemblyOrderColumns = `id, kit_sku, direction, quantity, location, components,
+	COALESCE(source_order_id, ''), status, unreconciled, idempotency_key,
+	created_at, updated_at, completed_at, failure`
+
+// SaveKit insere ou substitui a definição do kit
+func (r *FulfillmentRepository) SaveKit(ctx context.Context, kit *fulfillment.KitDefinition) error {
+	componentsJSON, err := json.Marshal(kit.Components)
+	if err != nil {
+		return fmt.Errorf("failed to marshal kit components: %w", err)
+	}
+
+	query := `
+		INSERT INTO kit_definitions (sku, name, components, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (sku) DO UPDATE SET
+			name = EXCLUDED.name, components = EXCLUDED.components, updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		kit.SKU, kit.Name, componentsJSON, kit.CreatedAt, kit.UpdatedAt,
+	).Scan(&kit.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save kit definition: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetKit(ctx context.Context, sku string) (*fulfillment.KitDefinition, error) {
+	return scanKit(r.db.QueryRowContext(ctx,
+		`SELECT sku, name, components, created_at, updated_at FROM kit_definitions WHERE sku = $1`, sku,
+	))
+}
+
+func (r *FulfillmentRepository) ListKits(ctx context.Context) ([]*fulfillment.KitDefinition, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT sku, name, components, created_at, updated_at FROM kit_definitions ORDER BY sku`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kit definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var kits []*fulfillment.KitDefinition
+	for rows.Next() {
+		kit, err := scanKit(rows)
+		if err != nil {
+			return nil, err
+		}
+		kits = append(kits, kit)
+	}
+	return kits, rows.Err()
+}
+
+func (r *FulfillmentRepository) DeleteKit(ctx context.Context, sku string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM kit_definitions WHERE sku = $1`, sku)
+	if err != nil {
+		return fmt.Errorf("failed to delete kit definition: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fulfillment.ErrKitNotFound
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) CreateAssemblyOrder(ctx context.Context, order *fulfillment.AssemblyOrder) error {
+	componentsJSON, err := json.Marshal(order.Components)
+	if err != nil {
+		return fmt.Errorf("failed to marshal assembly components: %w", err)
+	}
+
+	query := `
+		INSERT INTO assembly_orders (
+			id, kit_sku, direction, quantity, location, components, source_order_id,
+			status, idempotency_key, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+
+	return r.createWithHistory(ctx, fulfillment.EntityAssemblyOrder, order.ID, order.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			order.ID, order.KitSKU, order.Direction, order.Quantity, order.Location, componentsJSON,
+			nullableString(order.SourceOrderID), order.Status, order.IdempotencyKey, order.CreatedAt, order.UpdatedAt,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert assembly order: %w", err)
+		}
+		return insertedRow(result)
+	})
+}
+
+func (r *FulfillmentRepository) GetAssemblyOrderByID(ctx context.Context, id string) (*fulfillment.AssemblyOrder, error) {
+	return scanAssemblyOrder(r.db.QueryRowContext(ctx,
+		`SELECT `+assemblyOrderColumns+` FROM assembly_orders WHERE id = $1`, id,
+	))
+}
+
+func (r *FulfillmentRepository) UpdateAssemblyOrder(ctx context.Context, order *fulfillment.AssemblyOrder) error {
+	var unreconciledJSON interface{}
+	if len(order.Unreconciled) > 0 {
+		data, err := json.Marshal(order.Unreconciled)
+		if err != nil {
+			return fmt.Errorf("failed to marshal unreconciled movements: %w", err)
+		}
+		unreconciledJSON = data
+	}
+	failureJSON, err := marshalOptional(order.Failure, "failure")
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE assembly_orders
+		SET status = $1, unreconciled = $2, updated_at = $3, completed_at = $4, failure = $5
+		WHERE id = $6
+	`
+
+	return r.updateWithHistory(ctx, fulfillment.EntityAssemblyOrder, order.ID, order.Status, fulfillment.ErrAssemblyOrderNotFound, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			order.Status, unreconciledJSON, time.Now(), nullableTime(order.CompletedAt), failureJSON, order.ID,
+		); err != nil {
+			return fmt.Errorf("failed to update assembly order: %w", err)
+		}
+		return nil
+	})
+}
+
+func scanKit(row rowScanner) (*fulfillment.KitDefinition, error) {
+	var kit fulfillment.KitDefinition
+	var componentsJSON []byte
+	err := row.Scan(&kit.SKU, &kit.Name, &componentsJSON, &kit.CreatedAt, &kit.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrKitNotFound
+		}
+		return nil, fmt.Errorf("failed to scan kit definition: %w", err)
+	}
+	if err := json.Unmarshal(componentsJSON, &kit.Components); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal kit components: %w", err)
+	}
+	return &kit, nil
+}
+
+func scanAssemblyOrder(row rowScanner) (*fulfillment.AssemblyOrder, error) {
+	var order fulfillment.AssemblyOrder
+	var componentsJSON, unreconciledJSON, failureJSON []byte
+	var completedAt sql.NullTime
+
+	err := row.Scan(
+		&order.ID, &order.KitSKU, &order.Direction, &order.Quantity, &order.Location, &componentsJSON,
+		&order.SourceOrderID, &order.Status, &unreconciledJSON, &order.IdempotencyKey,
+		&order.CreatedAt, &order.UpdatedAt, &completedAt, &failureJSON,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrAssemblyOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to scan assembly order: %w", err)
+	}
+
+	if err := json.Unmarshal(componentsJSON, &order.Components); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal assembly components: %w", err)
+	}
+	if len(unreconciledJSON) > 0 {
+		if err := json.Unmarshal(unreconciledJSON, &order.Unreconciled); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal unreconciled movements: %w", err)
+		}
+	}
+	order.CompletedAt = timePtr(completedAt)
+	if order.Failure, err = unmarshalOptional[fulfillment.Failure](failureJSON, "failure"); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
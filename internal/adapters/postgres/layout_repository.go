@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// SaveLayout insere ou substitui o layout do armazém (linha única)
+func (r *FulfillmentRepository) SaveLayout(ctx context.Context, layout *fulfillment.WarehouseLayout) error {
+	aislesJSON, err := json.Marshal(layout.Aisles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aisles: %w", err)
+	}
+	crossAislesJSON, err := json.Marshal(layout.CrossAisles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cross-aisles: %w", err)
+	}
+	depotJSON, err := json.Marshal(layout.Depot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal depot: %w", err)
+	}
+	binsJSON, err := json.Marshal(layout.Bins)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bins: %w", err)
+	}
+
+	query := `
+		INSERT INTO warehouse_layout (id, aisles, cross_aisles, depot, bins, updated_at)
+		VALUES (1, $1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			aisles = EXCLUDED.aisles, cross_aisles = EXCLUDED.cross_aisles, depot = EXCLUDED.depot,
+			bins = EXCLUDED.bins, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, aislesJSON, crossAislesJSON, depotJSON, binsJSON, layout.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save warehouse layout: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetLayout(ctx context.Context) (*fulfillment.WarehouseLayout, error) {
+	var layout fulfillment.WarehouseLayout
+	var aislesJSON, crossAislesJSON, depotJSON, binsJSON []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT aisles, cross_aisles, depot, bins, updated_at FROM warehouse_layout WHERE id = 1`,
+	).Scan(&aislesJSON, &crossAislesJSON, &depotJSON, &binsJSON, &layout.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrLayoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get warehouse layout: %w", err)
+	}
+
+	if err := json.Unmarshal(aislesJSON, &layout.Aisles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal aisles: %w", err)
+	}
+	if err := json.Unmarshal(crossAislesJSON, &layout.CrossAisles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cross-aisles: %w", err)
+	}
+	if err := json.Unmarshal(depotJSON, &layout.Depot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal depot: %w", err)
+	}
+	if err := json.Unmarshal(binsJSON, &layout.Bins); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bins: %w", err)
+	}
+	return &layout, nil
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const lpnColumns = `code, type, COALESCE(parent_code, ''), COALESCE(location, ''), status, contents,
+	COALESCE(pending_transfer_id, ''), created_at, updated_at`
+
+// CreateLPN insere o contêiner e o registro de criação no histórico
+func (r *FulfillmentRepository) CreateLPN(ctx context.Context, lpn *fulfillment.LPN, event fulfillment.LPNEvent) error {
+	contentsJSON, err := json.Marshal(lpn.Contents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lpn contents: %w", err)
+	}
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO lpns (code, type, parent_code, location, status, contents, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (code) DO NOTHING
+		`, lpn.Code, lpn.Type, nullableString(lpn.Parent), nullableString(lpn.Location), lpn.Status, contentsJSON,
+			lpn.CreatedAt, lpn.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert lpn: %w", err)
+		}
+		inserted, err := insertedRow(result)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return fulfillment.ErrLPNExists
+		}
+		return insertLPNEvents(ctx, tx, []fulfillment.LPNEvent{event})
+	})
+}
+
+func (r *FulfillmentRepository) GetLPN(ctx context.Context, code string) (*fulfillment.LPN, error) {
+	return scanLPN(r.db.QueryRowContext(ctx, `SELECT `+lpnColumns+` FROM lpns WHERE code = $1`, code))
+}
+
+func (r *FulfillmentRepository) ListLPNChildren(ctx context.Context, parentCode string) ([]*fulfillment.LPN, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+lpnColumns+` FROM lpns WHERE parent_code = $1 ORDER BY code`, parentCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lpn children: %w", err)
+	}
+	defer rows.Close()
+
+	var children []*fulfillment.LPN
+	for rows.Next() {
+		lpn, err := scanLPN(rows)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, lpn)
+	}
+	return children, rows.Err()
+}
+
+// SaveLPNs atualiza os contêineres e acrescenta o histórico atomicamente
+func (r *FulfillmentRepository) SaveLPNs(ctx context.Context, lpns []*fulfillment.LPN, events []fulfillment.LPNEvent) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		for _, lpn := range lpns {
+			contentsJSON, err := json.Marshal(lpn.Contents)
+			if err != nil {
+				return fmt.Errorf("failed to marshal lpn contents: %w", err)
+			}
+			result, err := tx.ExecContext(ctx, `
+				UPDATE lpns SET parent_code = $2, location = $3, status = $4, contents = $5, pending_transfer_id = $6, updated_at = $7
+				WHERE code = $1
+			`, lpn.Code, nullableString(lpn.Parent), nullableString(lpn.Location), lpn.Status, contentsJSON,
+				nullableString(lpn.PendingTransfer), lpn.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to update lpn: %w", err)
+			}
+			updated, err := insertedRow(result)
+			if err != nil {
+				return err
+			}
+			if !updated {
+				return fulfillment.ErrLPNNotFound
+			}
+		}
+		return insertLPNEvents(ctx, tx, events)
+	})
+}
+
+func (r *FulfillmentRepository) ListLPNEvents(ctx context.Context, code string) ([]fulfillment.LPNEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT lpn_code, action, COALESCE(from_location, ''), COALESCE(to_location, ''), items,
+			COALESCE(reference_type, ''), COALESCE(reference_id, ''), COALESCE(actor, ''), occurred_at
+		FROM lpn_events WHERE lpn_code = $1 ORDER BY occurred_at, id
+	`, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lpn events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []fulfillment.LPNEvent
+	for rows.Next() {
+		var event fulfillment.LPNEvent
+		var itemsJSON []byte
+		if err := rows.Scan(
+			&event.LPNCode, &event.Action, &event.FromLocation, &event.ToLocation, &itemsJSON,
+			&event.ReferenceType, &event.ReferenceID, &event.Actor, &event.OccurredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan lpn event: %w", err)
+		}
+		if len(itemsJSON) > 0 {
+			if err := json.Unmarshal(itemsJSON, &event.Items); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal lpn event items: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func insertLPNEvents(ctx context.Context, tx *sql.Tx, events []fulfillment.LPNEvent) error {
+	for _, event := range events {
+		var itemsJSON interface{}
+		if len(event.Items) > 0 {
+			data, err := json.Marshal(event.Items)
+			if err != nil {
+				return fmt.Errorf("failed to marshal lpn event items: %w", err)
+			}
+			itemsJSON = data
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO lpn_events (
+				lpn_code, action, from_location, to_location, items, reference_type, reference_id, actor, occurred_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, event.LPNCode, event.Action, nullableString(event.FromLocation), nullableString(event.ToLocation), itemsJSON,
+			nullableString(event.ReferenceType), nullableString(event.ReferenceID), nullableString(event.Actor), event.OccurredAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert lpn event: %w", err)
+		}
+	}
+	return nil
+}
+
+func scanLPN(row rowScanner) (*fulfillment.LPN, error) {
+	var lpn fulfillment.LPN
+	var contentsJSON []byte
+	err := row.Scan(
+		&lpn.Code, &lpn.Type, &lpn.Parent, &lpn.Location, &lpn.Status, &contentsJSON, &lpn.PendingTransfer,
+		&lpn.CreatedAt, &lpn.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrLPNNotFound
+		}
+		return nil, fmt.Errorf("failed to scan lpn: %w", err)
+	}
+	if err := json.Unmarshal(contentsJSON, &lpn.Contents); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lpn contents: %w", err)
+	}
+	return &lpn, nil
+}
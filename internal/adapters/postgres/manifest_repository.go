@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const manifestColumns = `
+		id, carrier, manifest_date, status, shipments, pieces, weight,
+		created_at, updated_at, closed_at, COALESCE(closed_by, '')
+`
+
+const outboundShipmentColumns = `
+		id, fulfillment_order_id, COALESCE(tracking_number, ''), carrier, status, pieces, weight,
+		manifest_id, created_at, updated_at, shipped_at
+`
+
+// AddManifestShipment grava a expedição e soma seus volumes ao manifesto da transportadora no dia.
+// Os totais são somados no banco, de modo que inclusões concorrentes não se perdem.
+func (r *FulfillmentRepository) AddManifestShipment(ctx context.Context, manifest *fulfillment.CarrierManifest, shipment *fulfillment.OutboundShipment) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO carrier_manifests (id, carrier, manifest_date, status, shipments, pieces, weight, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (carrier, manifest_date) DO UPDATE SET
+				shipments = carrier_manifests.shipments + 1,
+				pieces = carrier_manifests.pieces + $10,
+				weight = carrier_manifests.weight + $11,
+				updated_at = EXCLUDED.updated_at
+			WHERE carrier_manifests.status = $4
+			RETURNING id, shipments, pieces, weight
+		`, manifest.ID, manifest.Carrier, manifest.Date, fulfillment.ManifestOpen, manifest.Shipments, manifest.Pieces, manifest.Weight,
+			manifest.CreatedAt, manifest.UpdatedAt, shipment.Pieces, shipment.Weight,
+		).Scan(&manifest.ID, &manifest.Shipments, &manifest.Pieces, &manifest.Weight)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s %s", fulfillment.ErrManifestClosed, manifest.Carrier, manifest.Date.Format(time.DateOnly))
+			}
+			return fmt.Errorf("failed to save carrier manifest: %w", err)
+		}
+		shipment.ManifestID = manifest.ID
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO outbound_shipments (
+				id, fulfillment_order_id, tracking_number, carrier, status, pieces, weight,
+				manifest_id, created_at, updated_at, shipped_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (fulfillment_order_id) DO NOTHING
+		`, shipment.ID, shipment.FulfillmentOrderID, nullableString(shipment.TrackingNumber), shipment.Carrier, shipment.Status,
+			shipment.Pieces, shipment.Weight, shipment.ManifestID, shipment.CreatedAt, shipment.UpdatedAt, nullableTime(shipment.ShippedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert outbound shipment: %w", err)
+		}
+		inserted, err := insertedRow(result)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return fmt.Errorf("%w: %s", fulfillment.ErrOutboundShipmentExists, shipment.FulfillmentOrderID)
+		}
+		return nil
+	})
+}
+
+func (r *FulfillmentRepository) GetManifest(ctx context.Context, id string) (*fulfillment.CarrierManifest, error) {
+	return scanManifest(r.db.QueryRowContext(ctx, `SELECT `+manifestColumns+` FROM carrier_manifests WHERE id = $1`, id))
+}
+
+func (r *FulfillmentRepository) GetManifestFor(ctx context.Context, carrier string, date time.Time) (*fulfillment.CarrierManifest, error) {
+	return scanManifest(r.db.QueryRowContext(ctx,
+		`SELECT `+manifestColumns+` FROM carrier_manifests WHERE carrier = $1 AND manifest_date = $2`, carrier, date,
+	))
+}
+
+func (r *FulfillmentRepository) ListManifests(ctx context.Context, carrier string, date *time.Time) ([]*fulfillment.CarrierManifest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+manifestColumns+` FROM carrier_manifests
+		WHERE ($1 = '' OR carrier = $1) AND ($2::date IS NULL OR manifest_date = $2)
+		ORDER BY manifest_date DESC, carrier
+	`, carrier, nullableTime(date))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query carrier manifests: %w", err)
+	}
+	defer rows.Close()
+
+	var manifests []*fulfillment.CarrierManifest
+	for rows.Next() {
+		manifest, err := scanManifest(rows)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, rows.Err()
+}
+
+func (r *FulfillmentRepository) ListManifestShipments(ctx context.Context, manifestID string) ([]*fulfillment.OutboundShipment, error) {
+	return listManifestShipments(ctx, r.db, manifestID)
+}
+
+// listManifestShipments lê as expedições do manifesto no banco ou na transação informada
+func listManifestShipments(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}, manifestID string) ([]*fulfillment.OutboundShipment, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT `+outboundShipmentColumns+`
+		FROM outbound_shipments WHERE manifest_id = $1
+		ORDER BY shipped_at, id
+	`, manifestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbound shipments: %w", err)
+	}
+	defer rows.Close()
+
+	var shipments []*fulfillment.OutboundShipment
+	for rows.Next() {
+		shipment, err := scanOutboundShipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, shipment)
+	}
+	return shipments, rows.Err()
+}
+
+func (r *FulfillmentRepository) GetOutboundShipmentByOrder(ctx context.Context, orderID string) (*fulfillment.OutboundShipment, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+outboundShipmentColumns+`
+		FROM outbound_shipments WHERE fulfillment_order_id = $1
+	`, orderID)
+	return scanOutboundShipment(row)
+}
+
+func scanOutboundShipment(row rowScanner) (*fulfillment.OutboundShipment, error) {
+	var shipment fulfillment.OutboundShipment
+	var shippedAt sql.NullTime
+	if err := row.Scan(
+		&shipment.ID, &shipment.FulfillmentOrderID, &shipment.TrackingNumber, &shipment.Carrier, &shipment.Status,
+		&shipment.Pieces, &shipment.Weight, &shipment.ManifestID, &shipment.CreatedAt, &shipment.UpdatedAt, &shippedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrOutboundShipmentNotFound
+		}
+		return nil, fmt.Errorf("failed to scan outbound shipment: %w", err)
+	}
+	if shippedAt.Valid {
+		shipment.ShippedAt = &shippedAt.Time
+	}
+	return &shipment, nil
+}
+
+// CloseManifest trava a linha do manifesto (SELECT ... FOR UPDATE) e lê as expedições na mesma
+// transação do fechamento: AddManifestShipment espera o commit e então encontra o manifesto fechado.
+func (r *FulfillmentRepository) CloseManifest(ctx context.Context, id string, close fulfillment.ManifestCloser) (*fulfillment.CarrierManifest, []*fulfillment.OutboundShipment, error) {
+	var manifest *fulfillment.CarrierManifest
+	var shipments []*fulfillment.OutboundShipment
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		manifest, err = scanManifest(tx.QueryRowContext(ctx, `SELECT `+manifestColumns+` FROM carrier_manifests WHERE id = $1 FOR UPDATE`, id))
+		if err != nil {
+			return err
+		}
+		if shipments, err = listManifestShipments(ctx, tx, id); err != nil {
+			return err
+		}
+		documents, err := close(manifest, shipments)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE carrier_manifests
+			SET status = $2, closed_at = $3, closed_by = $4, updated_at = $5, document_csv = $6, document_html = $7
+			WHERE id = $1
+		`, manifest.ID, manifest.Status, nullableTime(manifest.ClosedAt), nullableString(manifest.ClosedBy), manifest.UpdatedAt,
+			documents[fulfillment.ManifestCSV], documents[fulfillment.ManifestHTML],
+		); err != nil {
+			return fmt.Errorf("failed to close carrier manifest: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifest, shipments, nil
+}
+
+func (r *FulfillmentRepository) GetManifestDocument(ctx context.Context, id string, format fulfillment.ManifestFormat) ([]byte, error) {
+	column := map[fulfillment.ManifestFormat]string{
+		fulfillment.ManifestCSV:  "document_csv",
+		fulfillment.ManifestHTML: "document_html",
+	}[format]
+	if column == "" {
+		return nil, fmt.Errorf("%w: %s", fulfillment.ErrUnsupportedManifestFormat, format)
+	}
+
+	var document []byte
+	err := r.db.QueryRowContext(ctx, `SELECT `+column+` FROM carrier_manifests WHERE id = $1`, id).Scan(&document)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrManifestNotFound
+		}
+		return nil, fmt.Errorf("failed to get manifest document: %w", err)
+	}
+	if document == nil {
+		return nil, fulfillment.ErrManifestNotClosed
+	}
+	return document, nil
+}
+
+func scanManifest(row rowScanner) (*fulfillment.CarrierManifest, error) {
+	var manifest fulfillment.CarrierManifest
+	var closedAt sql.NullTime
+	err := row.Scan(
+		&manifest.ID, &manifest.Carrier, &manifest.Date, &manifest.Status, &manifest.Shipments, &manifest.Pieces, &manifest.Weight,
+		&manifest.CreatedAt, &manifest.UpdatedAt, &closedAt, &manifest.ClosedBy,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrManifestNotFound
+		}
+		return nil, fmt.Errorf("failed to scan carrier manifest: %w", err)
+	}
+	if closedAt.Valid {
+		manifest.ClosedAt = &closedAt.Time
+	}
+	return &manifest, nil
+}
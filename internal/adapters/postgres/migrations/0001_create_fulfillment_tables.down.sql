@@ -0,0 +1,7 @@
+-- Migration: Create fulfillment tables (down)
+
+DROP TABLE IF EXISTS cycle_count_tasks;
+DROP TABLE IF EXISTS return_orders;
+DROP TABLE IF EXISTS transfer_orders;
+DROP TABLE IF EXISTS fulfillment_orders;
+DROP TABLE IF EXISTS inbound_shipments;
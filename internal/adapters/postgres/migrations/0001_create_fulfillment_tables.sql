@@ -15,9 +15,9 @@ CREATE TABLE IF NOT EXISTS inbound_shipments (
     completed_at TIMESTAMP
 );
 
-CREATE INDEX idx_inbound_reference_id ON inbound_shipments(reference_id);
-CREATE INDEX idx_inbound_status ON inbound_shipments(status);
-CREATE INDEX idx_inbound_idempotency_key ON inbound_shipments(idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_inbound_reference_id ON inbound_shipments(reference_id);
+CREATE INDEX IF NOT EXISTS idx_inbound_status ON inbound_shipments(status);
+CREATE INDEX IF NOT EXISTS idx_inbound_idempotency_key ON inbound_shipments(idempotency_key);
 
 -- Tabela de Fulfillment Orders (Expedições)
 CREATE TABLE IF NOT EXISTS fulfillment_orders (
@@ -34,9 +34,9 @@ CREATE TABLE IF NOT EXISTS fulfillment_orders (
     shipped_at TIMESTAMP
 );
 
-CREATE INDEX idx_fulfillment_order_id ON fulfillment_orders(order_id);
-CREATE INDEX idx_fulfillment_status ON fulfillment_orders(status);
-CREATE INDEX idx_fulfillment_idempotency_key ON fulfillment_orders(idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_order_id ON fulfillment_orders(order_id);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_status ON fulfillment_orders(status);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_idempotency_key ON fulfillment_orders(idempotency_key);
 
 -- Tabela de Transfer Orders (Transferências)
 CREATE TABLE IF NOT EXISTS transfer_orders (
@@ -51,8 +51,8 @@ CREATE TABLE IF NOT EXISTS transfer_orders (
     completed_at TIMESTAMP
 );
 
-CREATE INDEX idx_transfer_status ON transfer_orders(status);
-CREATE INDEX idx_transfer_idempotency_key ON transfer_orders(idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_transfer_status ON transfer_orders(status);
+CREATE INDEX IF NOT EXISTS idx_transfer_idempotency_key ON transfer_orders(idempotency_key);
 
 -- Tabela de Return Orders (Devoluções)
 CREATE TABLE IF NOT EXISTS return_orders (
@@ -67,9 +67,9 @@ CREATE TABLE IF NOT EXISTS return_orders (
     completed_at TIMESTAMP
 );
 
-CREATE INDEX idx_return_original_order_id ON return_orders(original_order_id);
-CREATE INDEX idx_return_status ON return_orders(status);
-CREATE INDEX idx_return_idempotency_key ON return_orders(idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_return_original_order_id ON return_orders(original_order_id);
+CREATE INDEX IF NOT EXISTS idx_return_status ON return_orders(status);
+CREATE INDEX IF NOT EXISTS idx_return_idempotency_key ON return_orders(idempotency_key);
 
 -- Tabela de Cycle Count Tasks (Contagens Cíclicas)
 CREATE TABLE IF NOT EXISTS cycle_count_tasks (
@@ -84,7 +84,7 @@ CREATE TABLE IF NOT EXISTS cycle_count_tasks (
     completed_at TIMESTAMP
 );
 
-CREATE INDEX idx_cycle_count_location ON cycle_count_tasks(location);
-CREATE INDEX idx_cycle_count_status ON cycle_count_tasks(status);
-CREATE INDEX idx_cycle_count_idempotency_key ON cycle_count_tasks(idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_cycle_count_location ON cycle_count_tasks(location);
+CREATE INDEX IF NOT EXISTS idx_cycle_count_status ON cycle_count_tasks(status);
+CREATE INDEX IF NOT EXISTS idx_cycle_count_idempotency_key ON cycle_count_tasks(idempotency_key);
 
@@ -0,0 +1,7 @@
+-- Migration: Add order reservations (down)
+
+DROP INDEX IF EXISTS idx_fulfillment_reservation_status;
+
+ALTER TABLE fulfillment_orders
+    DROP COLUMN IF EXISTS reservation_expires_at,
+    DROP COLUMN IF EXISTS reservation_status;
@@ -0,0 +1,8 @@
+-- Migration: Add order reservations
+-- Description: Ciclo de vida explícito da reserva de estoque em fulfillment_orders
+
+ALTER TABLE fulfillment_orders
+    ADD COLUMN IF NOT EXISTS reservation_status VARCHAR(50) NOT NULL DEFAULT 'NONE',
+    ADD COLUMN IF NOT EXISTS reservation_expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_fulfillment_reservation_status ON fulfillment_orders(reservation_status, reservation_expires_at);
@@ -0,0 +1,5 @@
+-- Migration: Add aggregate versions (down)
+
+ALTER TABLE return_orders DROP COLUMN IF EXISTS version;
+ALTER TABLE transfer_orders DROP COLUMN IF EXISTS version;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS version;
@@ -0,0 +1,6 @@
+-- Migration: Add aggregate versions
+-- Description: Versão do agregado no event store; as linhas passam a ser projeções
+
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transfer_orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE return_orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
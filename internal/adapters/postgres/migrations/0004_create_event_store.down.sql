@@ -0,0 +1,5 @@
+-- Migration: Create event store (down)
+
+DROP TABLE IF EXISTS event_store_snapshots;
+DROP TABLE IF EXISTS event_store_events;
+DROP TABLE IF EXISTS event_store_aggregates;
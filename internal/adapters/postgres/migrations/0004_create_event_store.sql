@@ -0,0 +1,43 @@
+-- Migration: Create event store
+-- Description: Armazenamento durável de eventos (events.EventStore) com ordenação global
+
+CREATE TABLE IF NOT EXISTS event_store_aggregates (
+    aggregate_id VARCHAR(255) PRIMARY KEY,
+    aggregate_type VARCHAR(100) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 0,
+    event_count BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    first_event TIMESTAMPTZ,
+    last_event TIMESTAMPTZ,
+    last_snapshot TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS event_store_events (
+    position BIGSERIAL PRIMARY KEY,
+    id VARCHAR(255) NOT NULL UNIQUE,
+    aggregate_id VARCHAR(255) NOT NULL,
+    aggregate_type VARCHAR(100) NOT NULL,
+    version BIGINT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    data JSONB,
+    metadata JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    node_id VARCHAR(255),
+    causation_id VARCHAR(255),
+    correlation_id VARCHAR(255),
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    UNIQUE (aggregate_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_store_events_type ON event_store_events(type, position);
+CREATE INDEX IF NOT EXISTS idx_event_store_events_occurred_at ON event_store_events(occurred_at);
+
+CREATE TABLE IF NOT EXISTS event_store_snapshots (
+    aggregate_id VARCHAR(255) PRIMARY KEY,
+    aggregate_type VARCHAR(100) NOT NULL,
+    version BIGINT NOT NULL,
+    data JSONB,
+    created_at TIMESTAMPTZ NOT NULL,
+    created_by VARCHAR(255),
+    size_bytes BIGINT NOT NULL DEFAULT 0
+);
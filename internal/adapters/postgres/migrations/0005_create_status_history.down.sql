@@ -0,0 +1,4 @@
+-- Migration: Create status history (down)
+
+DROP TABLE IF EXISTS status_history;
+DROP FUNCTION IF EXISTS status_history_append_only();
@@ -0,0 +1,30 @@
+-- Migration: Create status history
+-- Description: Histórico append-only de transições de status de todas as entidades de fulfillment
+
+CREATE TABLE IF NOT EXISTS status_history (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    actor VARCHAR(255),
+    source VARCHAR(50),
+    reason VARCHAR(500),
+    correlation_id VARCHAR(255),
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_history_entity ON status_history(entity_type, entity_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_status_history_occurred_at ON status_history(entity_type, occurred_at);
+
+-- Append-only: impede UPDATE e DELETE
+CREATE OR REPLACE FUNCTION status_history_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'status_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_status_history_append_only ON status_history;
+CREATE TRIGGER trg_status_history_append_only
+    BEFORE UPDATE OR DELETE ON status_history
+    FOR EACH ROW EXECUTE FUNCTION status_history_append_only();
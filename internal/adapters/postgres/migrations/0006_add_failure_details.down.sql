@@ -0,0 +1,15 @@
+-- Migration: Add failure details (down)
+
+DROP INDEX IF EXISTS idx_cycle_count_tasks_failed;
+DROP INDEX IF EXISTS idx_return_orders_failed;
+DROP INDEX IF EXISTS idx_transfer_orders_failed;
+DROP INDEX IF EXISTS idx_fulfillment_orders_failed;
+DROP INDEX IF EXISTS idx_inbound_shipments_failed;
+
+ALTER TABLE return_orders DROP COLUMN IF EXISTS location;
+
+ALTER TABLE cycle_count_tasks DROP COLUMN IF EXISTS failure;
+ALTER TABLE return_orders DROP COLUMN IF EXISTS failure;
+ALTER TABLE transfer_orders DROP COLUMN IF EXISTS failure;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS failure;
+ALTER TABLE inbound_shipments DROP COLUMN IF EXISTS failure;
@@ -0,0 +1,17 @@
+-- Migration: Add failure details
+-- Description: Última falha de cada operação (classe, etapa, item, tentativa) para retry e retomada
+
+ALTER TABLE inbound_shipments ADD COLUMN IF NOT EXISTS failure JSONB;
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS failure JSONB;
+ALTER TABLE transfer_orders ADD COLUMN IF NOT EXISTS failure JSONB;
+ALTER TABLE return_orders ADD COLUMN IF NOT EXISTS failure JSONB;
+ALTER TABLE cycle_count_tasks ADD COLUMN IF NOT EXISTS failure JSONB;
+
+-- Local de entrada das devoluções, necessário para retomar o processamento
+ALTER TABLE return_orders ADD COLUMN IF NOT EXISTS location VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_shipments_failed ON inbound_shipments(updated_at) WHERE status = 'FAILED';
+CREATE INDEX IF NOT EXISTS idx_fulfillment_orders_failed ON fulfillment_orders(updated_at) WHERE status = 'FAILED';
+CREATE INDEX IF NOT EXISTS idx_transfer_orders_failed ON transfer_orders(updated_at) WHERE status = 'FAILED';
+CREATE INDEX IF NOT EXISTS idx_return_orders_failed ON return_orders(updated_at) WHERE status = 'FAILED';
+CREATE INDEX IF NOT EXISTS idx_cycle_count_tasks_failed ON cycle_count_tasks(updated_at) WHERE status = 'FAILED';
@@ -0,0 +1,13 @@
+-- Migration: Add block details (down)
+
+DROP INDEX IF EXISTS idx_cycle_count_tasks_blocked;
+DROP INDEX IF EXISTS idx_return_orders_blocked;
+DROP INDEX IF EXISTS idx_transfer_orders_blocked;
+DROP INDEX IF EXISTS idx_fulfillment_orders_blocked;
+DROP INDEX IF EXISTS idx_inbound_shipments_blocked;
+
+ALTER TABLE cycle_count_tasks DROP COLUMN IF EXISTS block;
+ALTER TABLE return_orders DROP COLUMN IF EXISTS block;
+ALTER TABLE transfer_orders DROP COLUMN IF EXISTS block;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS block;
+ALTER TABLE inbound_shipments DROP COLUMN IF EXISTS block;
@@ -0,0 +1,14 @@
+-- Migration: Add block details
+-- Description: Bloqueio ativo (código de motivo, autor, status anterior, escalonamento)
+
+ALTER TABLE inbound_shipments ADD COLUMN IF NOT EXISTS block JSONB;
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS block JSONB;
+ALTER TABLE transfer_orders ADD COLUMN IF NOT EXISTS block JSONB;
+ALTER TABLE return_orders ADD COLUMN IF NOT EXISTS block JSONB;
+ALTER TABLE cycle_count_tasks ADD COLUMN IF NOT EXISTS block JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_inbound_shipments_blocked ON inbound_shipments(updated_at) WHERE status = 'BLOCKED';
+CREATE INDEX IF NOT EXISTS idx_fulfillment_orders_blocked ON fulfillment_orders(updated_at) WHERE status = 'BLOCKED';
+CREATE INDEX IF NOT EXISTS idx_transfer_orders_blocked ON transfer_orders(updated_at) WHERE status = 'BLOCKED';
+CREATE INDEX IF NOT EXISTS idx_return_orders_blocked ON return_orders(updated_at) WHERE status = 'BLOCKED';
+CREATE INDEX IF NOT EXISTS idx_cycle_count_tasks_blocked ON cycle_count_tasks(updated_at) WHERE status = 'BLOCKED';
@@ -0,0 +1,3 @@
+-- Migration: Create warehouse tasks (down)
+
+DROP TABLE IF EXISTS warehouse_tasks;
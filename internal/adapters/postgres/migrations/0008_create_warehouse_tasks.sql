@@ -0,0 +1,29 @@
+-- Migration: Create warehouse tasks
+-- Description: Fila unificada de tarefas de armazém (separação, armazenagem, contagem, reabastecimento)
+
+CREATE TABLE IF NOT EXISTS warehouse_tasks (
+    id VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(50) NOT NULL,
+    source_location VARCHAR(255) NOT NULL DEFAULT '',
+    destination_location VARCHAR(255) NOT NULL DEFAULT '',
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    assigned_to VARCHAR(255),
+    device_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_warehouse_tasks_open ON warehouse_tasks(priority DESC, created_at)
+    WHERE status IN ('PENDING', 'ASSIGNED', 'IN_PROGRESS');
+CREATE INDEX IF NOT EXISTS idx_warehouse_tasks_entity ON warehouse_tasks(entity_type, entity_id);
+-- No máximo uma tarefa aberta por operação (enqueues concorrentes)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouse_tasks_open_entity ON warehouse_tasks(entity_type, entity_id)
+    WHERE status IN ('PENDING', 'ASSIGNED', 'IN_PROGRESS');
+CREATE INDEX IF NOT EXISTS idx_warehouse_tasks_assignee ON warehouse_tasks(assigned_to, updated_at DESC);
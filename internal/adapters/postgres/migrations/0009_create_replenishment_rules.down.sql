@@ -0,0 +1,3 @@
+-- Migration: Create replenishment rules (down)
+
+DROP TABLE IF EXISTS replenishment_rules;
@@ -0,0 +1,20 @@
+-- Migration: Create replenishment rules
+-- Description: Regras de reabastecimento (mín/máx ou por demanda) por SKU e endereço de picking
+
+CREATE TABLE IF NOT EXISTS replenishment_rules (
+    id VARCHAR(255) PRIMARY KEY,
+    sku VARCHAR(255) NOT NULL,
+    pick_location VARCHAR(255) NOT NULL,
+    reserve_location VARCHAR(255) NOT NULL,
+    mode VARCHAR(20) NOT NULL,
+    min_quantity INTEGER NOT NULL DEFAULT 0,
+    max_quantity INTEGER NOT NULL DEFAULT 0,
+    case_quantity INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    open_transfer_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (sku, pick_location)
+);
+
+CREATE INDEX IF NOT EXISTS idx_replenishment_rules_pick_location ON replenishment_rules(pick_location);
@@ -0,0 +1,4 @@
+-- Migration: Create kitting (down)
+
+DROP TABLE IF EXISTS assembly_orders;
+DROP TABLE IF EXISTS kit_definitions;
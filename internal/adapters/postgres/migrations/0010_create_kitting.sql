@@ -0,0 +1,31 @@
+-- Migration: Create kitting
+-- Description: Definições de kit (lista de materiais) e ordens de montagem/desmontagem
+
+CREATE TABLE IF NOT EXISTS kit_definitions (
+    sku VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    components JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS assembly_orders (
+    id VARCHAR(255) PRIMARY KEY,
+    kit_sku VARCHAR(255) NOT NULL,
+    direction VARCHAR(20) NOT NULL,
+    quantity INTEGER NOT NULL,
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    components JSONB NOT NULL,
+    source_order_id VARCHAR(255),
+    status VARCHAR(50) NOT NULL,
+    unreconciled JSONB,
+    idempotency_key VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    failure JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_assembly_orders_kit_sku ON assembly_orders(kit_sku);
+CREATE INDEX IF NOT EXISTS idx_assembly_orders_source_order ON assembly_orders(source_order_id);
+CREATE INDEX IF NOT EXISTS idx_assembly_orders_status ON assembly_orders(status);
@@ -0,0 +1,4 @@
+-- Migration: Create cross-dock (down)
+
+DROP TABLE IF EXISTS cross_dock_allocations;
+DROP TABLE IF EXISTS cross_dock_policies;
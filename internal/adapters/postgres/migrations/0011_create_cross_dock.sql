@@ -0,0 +1,31 @@
+-- Migration: Create cross-dock
+-- Description: Políticas de cross-dock por cliente e SKU e alocações de recebimentos a ordens de saída
+
+CREATE TABLE IF NOT EXISTS cross_dock_policies (
+    id VARCHAR(255) PRIMARY KEY,
+    customer VARCHAR(255) NOT NULL DEFAULT '',
+    sku VARCHAR(255) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    staging_location VARCHAR(255) NOT NULL DEFAULT '',
+    include_pending BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (customer, sku)
+);
+
+CREATE TABLE IF NOT EXISTS cross_dock_allocations (
+    id VARCHAR(255) PRIMARY KEY,
+    shipment_id VARCHAR(255) NOT NULL,
+    order_id VARCHAR(255) NOT NULL,
+    line INTEGER NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    batch VARCHAR(255),
+    quantity INTEGER NOT NULL,
+    from_location VARCHAR(255) NOT NULL,
+    to_location VARCHAR(255) NOT NULL,
+    transfer_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cross_dock_allocations_shipment ON cross_dock_allocations(shipment_id);
+CREATE INDEX IF NOT EXISTS idx_cross_dock_allocations_transfer ON cross_dock_allocations(transfer_id);
@@ -0,0 +1,4 @@
+-- Migration: Create dock scheduling (down)
+
+DROP TABLE IF EXISTS dock_appointments;
+DROP TABLE IF EXISTS dock_doors;
@@ -0,0 +1,37 @@
+-- Migration: Create dock scheduling
+-- Description: Portas de doca (capacidades e horário de funcionamento) e agendamentos de recebimento/expedição
+
+CREATE TABLE IF NOT EXISTS dock_doors (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    direction VARCHAR(20) NOT NULL,
+    capabilities JSONB NOT NULL DEFAULT '[]',
+    hours JSONB NOT NULL DEFAULT '[]',
+    slot_minutes INTEGER NOT NULL DEFAULT 60,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS dock_appointments (
+    id VARCHAR(255) PRIMARY KEY,
+    door_id VARCHAR(255) NOT NULL,
+    direction VARCHAR(20) NOT NULL,
+    shipment_type VARCHAR(50) NOT NULL,
+    shipment_id VARCHAR(255) NOT NULL,
+    supplier VARCHAR(255) NOT NULL DEFAULT '',
+    carrier VARCHAR(255),
+    scheduled_start TIMESTAMPTZ NOT NULL,
+    scheduled_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    arrived_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    late_minutes INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dock_appointments_door_start ON dock_appointments(door_id, scheduled_start);
+CREATE INDEX IF NOT EXISTS idx_dock_appointments_status_start ON dock_appointments(status, scheduled_start);
+CREATE INDEX IF NOT EXISTS idx_dock_appointments_shipment ON dock_appointments(shipment_id);
@@ -0,0 +1,3 @@
+-- Migration: Create UoM hierarchies (down)
+
+DROP TABLE IF EXISTS uom_hierarchies;
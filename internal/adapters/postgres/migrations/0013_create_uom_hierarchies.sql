@@ -0,0 +1,9 @@
+-- Migration: Create UoM hierarchies
+-- Description: Hierarquia de embalagens por SKU (fatores de conversão para a unidade base EA)
+
+CREATE TABLE IF NOT EXISTS uom_hierarchies (
+    sku VARCHAR(255) PRIMARY KEY,
+    levels JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- Migration: Create LPNs (down)
+
+DROP TABLE IF EXISTS lpn_events;
+DROP TABLE IF EXISTS lpns;
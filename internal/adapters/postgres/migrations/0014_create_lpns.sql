@@ -0,0 +1,32 @@
+-- Migration: Create LPNs
+-- Description: Contêineres identificados por license plate (palete, caixa, tote), aninháveis, e seu histórico
+
+CREATE TABLE IF NOT EXISTS lpns (
+    code VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(20) NOT NULL,
+    parent_code VARCHAR(255),
+    location VARCHAR(255),
+    status VARCHAR(20) NOT NULL,
+    contents JSONB NOT NULL DEFAULT '[]',
+    pending_transfer_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lpns_parent ON lpns(parent_code);
+CREATE INDEX IF NOT EXISTS idx_lpns_location ON lpns(location);
+
+CREATE TABLE IF NOT EXISTS lpn_events (
+    id BIGSERIAL PRIMARY KEY,
+    lpn_code VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    from_location VARCHAR(255),
+    to_location VARCHAR(255),
+    items JSONB,
+    reference_type VARCHAR(50),
+    reference_id VARCHAR(255),
+    actor VARCHAR(255),
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lpn_events_lpn ON lpn_events(lpn_code, occurred_at);
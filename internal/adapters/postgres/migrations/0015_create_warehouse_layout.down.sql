@@ -0,0 +1,3 @@
+-- Migration: Create warehouse layout (down)
+
+DROP TABLE IF EXISTS warehouse_layout;
@@ -0,0 +1,11 @@
+-- Migration: Create warehouse layout
+-- Description: Layout do armazém (corredores, corredores transversais, depósito e coordenadas dos endereços) para roteamento da separação
+
+CREATE TABLE IF NOT EXISTS warehouse_layout (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    aisles JSONB NOT NULL DEFAULT '[]',
+    cross_aisles JSONB NOT NULL DEFAULT '[]',
+    depot JSONB NOT NULL,
+    bins JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
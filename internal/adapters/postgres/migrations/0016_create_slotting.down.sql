@@ -0,0 +1,5 @@
+-- Migration: Create slotting (down)
+
+DROP INDEX IF EXISTS idx_fulfillment_orders_shipped_at;
+DROP TABLE IF EXISTS slotting_recommendations;
+ALTER TABLE uom_hierarchies DROP COLUMN IF EXISTS unit_cube;
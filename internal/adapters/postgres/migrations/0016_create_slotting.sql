@@ -0,0 +1,22 @@
+-- Migration: Create slotting
+-- Description: Volume unitário por SKU e recomendações de realocação de SKUs de giro rápido para a zona dourada
+
+ALTER TABLE uom_hierarchies ADD COLUMN IF NOT EXISTS unit_cube DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS slotting_recommendations (
+    id VARCHAR(255) PRIMARY KEY,
+    sku VARCHAR(255) NOT NULL,
+    class VARCHAR(1) NOT NULL,
+    from_location VARCHAR(255) NOT NULL,
+    to_location VARCHAR(255) NOT NULL,
+    displaced_sku VARCHAR(255),
+    picks INTEGER NOT NULL,
+    savings DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    transfer_ids JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_slotting_recommendations_status ON slotting_recommendations(status);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_orders_shipped_at ON fulfillment_orders(shipped_at) WHERE shipped_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Migration: Create count program (down)
+
+DROP TABLE IF EXISTS count_triggers;
+DROP TABLE IF EXISTS count_program;
+ALTER TABLE uom_hierarchies DROP COLUMN IF EXISTS unit_value;
@@ -0,0 +1,24 @@
+-- Migration: Create count program
+-- Description: Valor unitário por SKU, frequências de contagem cíclica por classe ABC e gatilhos de contagem extra
+
+ALTER TABLE uom_hierarchies ADD COLUMN IF NOT EXISTS unit_value DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS count_program (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    frequencies JSONB NOT NULL,
+    lookback_days INTEGER NOT NULL,
+    daily_capacity INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS count_triggers (
+    id VARCHAR(255) PRIMARY KEY,
+    location VARCHAR(255) NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    task_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_count_triggers_pending ON count_triggers(created_at) WHERE task_id IS NULL;
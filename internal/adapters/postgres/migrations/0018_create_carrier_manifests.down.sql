@@ -0,0 +1,4 @@
+-- Migration: Create carrier manifests (down)
+
+DROP TABLE IF EXISTS outbound_shipments;
+DROP TABLE IF EXISTS carrier_manifests;
@@ -0,0 +1,36 @@
+-- Migration: Create carrier manifests
+-- Description: Expedições entregues às transportadoras e manifestos diários por transportadora com os documentos gerados no fechamento
+
+CREATE TABLE IF NOT EXISTS carrier_manifests (
+    id VARCHAR(255) PRIMARY KEY,
+    carrier VARCHAR(255) NOT NULL,
+    manifest_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    shipments INTEGER NOT NULL DEFAULT 0,
+    pieces INTEGER NOT NULL DEFAULT 0,
+    weight DOUBLE PRECISION NOT NULL DEFAULT 0,
+    document_csv BYTEA,
+    document_html BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    closed_by VARCHAR(255),
+    UNIQUE (carrier, manifest_date)
+);
+
+CREATE TABLE IF NOT EXISTS outbound_shipments (
+    id VARCHAR(255) PRIMARY KEY,
+    fulfillment_order_id VARCHAR(255) NOT NULL UNIQUE,
+    tracking_number VARCHAR(255),
+    carrier VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    pieces INTEGER NOT NULL,
+    weight DOUBLE PRECISION NOT NULL,
+    manifest_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    shipped_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_carrier_manifests_date ON carrier_manifests(manifest_date);
+CREATE INDEX IF NOT EXISTS idx_outbound_shipments_manifest ON outbound_shipments(manifest_id);
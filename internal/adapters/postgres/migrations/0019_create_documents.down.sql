@@ -0,0 +1,4 @@
+-- Migration: Create documents (down)
+
+DROP TABLE IF EXISTS rendered_documents;
+DROP TABLE IF EXISTS document_templates;
@@ -0,0 +1,25 @@
+-- Migration: Create documents
+-- Description: Modelos de documento por cliente, tipo e formato e documentos gerados guardados para reimpressão
+
+CREATE TABLE IF NOT EXISTS document_templates (
+    id VARCHAR(255) PRIMARY KEY,
+    customer VARCHAR(255) NOT NULL DEFAULT '',
+    document_type VARCHAR(50) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    body TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (customer, document_type, format)
+);
+
+CREATE TABLE IF NOT EXISTS rendered_documents (
+    id VARCHAR(255) PRIMARY KEY,
+    fulfillment_order_id VARCHAR(255) NOT NULL,
+    document_type VARCHAR(50) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    template_id VARCHAR(255),
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rendered_documents_order ON rendered_documents(fulfillment_order_id, created_at DESC);
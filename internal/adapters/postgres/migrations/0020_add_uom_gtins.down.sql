@@ -0,0 +1,5 @@
+-- Migration: Add UoM GTINs (down)
+
+DROP INDEX IF EXISTS idx_uom_hierarchies_levels;
+DROP INDEX IF EXISTS idx_uom_hierarchies_gtin;
+ALTER TABLE uom_hierarchies DROP COLUMN IF EXISTS gtin;
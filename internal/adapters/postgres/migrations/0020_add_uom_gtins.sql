@@ -0,0 +1,7 @@
+-- Migration: Add UoM GTINs
+-- Description: GTIN da unidade base e índice dos GTINs das embalagens para resolver leituras de códigos GS1
+
+ALTER TABLE uom_hierarchies ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uom_hierarchies_gtin ON uom_hierarchies(gtin) WHERE gtin IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_uom_hierarchies_levels ON uom_hierarchies USING GIN (levels jsonb_path_ops);
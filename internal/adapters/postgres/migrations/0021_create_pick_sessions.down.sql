@@ -0,0 +1,3 @@
+-- Migration: Create pick sessions (down)
+
+DROP TABLE IF EXISTS pick_sessions;
@@ -0,0 +1,20 @@
+-- Migration: Create pick sessions
+-- Description: Sessões de separação guiada dos coletores (endereço, item e quantidade por linha da ordem)
+
+CREATE TABLE IF NOT EXISTS pick_sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    fulfillment_order_id VARCHAR(255) NOT NULL,
+    task_id VARCHAR(255) NOT NULL,
+    operator VARCHAR(255) NOT NULL,
+    device_id VARCHAR(255),
+    status VARCHAR(50) NOT NULL,
+    steps JSONB NOT NULL,
+    current_step INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pick_sessions_active_order ON pick_sessions(fulfillment_order_id) WHERE status = 'IN_PROGRESS';
+CREATE INDEX IF NOT EXISTS idx_pick_sessions_order ON pick_sessions(fulfillment_order_id);
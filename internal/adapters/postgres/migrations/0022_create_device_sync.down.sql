@@ -0,0 +1,4 @@
+-- Migration: Create device sync (down)
+
+DROP TABLE IF EXISTS device_sync_conflicts;
+DROP TABLE IF EXISTS device_sync_states;
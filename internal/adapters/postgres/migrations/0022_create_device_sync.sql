@@ -0,0 +1,32 @@
+-- Migration: Create device sync
+-- Description: Estado sincronizado das chaves alteradas offline pelos coletores (relógio vetorial) e fila de conflitos para revisão do supervisor
+
+CREATE TABLE IF NOT EXISTS device_sync_states (
+    key VARCHAR(512) PRIMARY KEY,
+    kind VARCHAR(100) NOT NULL,
+    value JSONB NOT NULL,
+    clock JSONB NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    version BIGINT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS device_sync_conflicts (
+    id VARCHAR(255) PRIMARY KEY,
+    key VARCHAR(512) NOT NULL,
+    kind VARCHAR(100) NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    operation_id VARCHAR(255) NOT NULL,
+    server_state JSONB NOT NULL,
+    device_operation JSONB NOT NULL,
+    fields JSONB,
+    reason TEXT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    choice VARCHAR(20),
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (device_id, operation_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_sync_conflicts_status ON device_sync_conflicts(status, created_at);
@@ -0,0 +1,11 @@
+-- Migration: Add ship promises (down)
+
+DROP TABLE IF EXISTS carrier_cutoff_calendars;
+
+DROP INDEX IF EXISTS idx_fulfillment_orders_open;
+
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS customer_tier;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS service;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS carrier;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS deliver_by;
+ALTER TABLE fulfillment_orders DROP COLUMN IF EXISTS ship_by;
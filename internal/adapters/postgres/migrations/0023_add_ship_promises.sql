@@ -0,0 +1,21 @@
+-- Migration: Add ship promises
+-- Description: Prazos prometidos da ordem (ship-by, deliver-by), transportadora e serviço, nível do cliente e calendários de corte das transportadoras
+
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS ship_by TIMESTAMPTZ;
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS deliver_by TIMESTAMPTZ;
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS carrier VARCHAR(100);
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS service VARCHAR(100);
+ALTER TABLE fulfillment_orders ADD COLUMN IF NOT EXISTS customer_tier VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_fulfillment_orders_open ON fulfillment_orders(id) WHERE status IN ('PENDING', 'IN_PROGRESS');
+
+CREATE TABLE IF NOT EXISTS carrier_cutoff_calendars (
+    id VARCHAR(255) PRIMARY KEY,
+    carrier VARCHAR(100) NOT NULL,
+    service VARCHAR(100) NOT NULL DEFAULT '',
+    cutoffs JSONB NOT NULL,
+    holidays JSONB,
+    timezone VARCHAR(100),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (carrier, service)
+);
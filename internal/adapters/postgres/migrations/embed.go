@@ -0,0 +1,10 @@
+// Package migrations embute os arquivos SQL de schema do fulfillment-ops.
+// Convenção: NNNN_nome.sql (up) e NNNN_nome.down.sql (down).
+package migrations
+
+import "embed"
+
+// FS contém todos os arquivos de migração
+//
+//go:embed *.sql
+var FS embed.FS
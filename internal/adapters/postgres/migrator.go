@@ -0,0 +1,377 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/adapters/postgres/migrations"
+)
+
+const (
+	// migrationLockKey identifica o advisory lock que serializa migrações entre pods
+	migrationLockKey = 7_340_030
+
+	schemaMigrationsTable = "schema_migrations"
+)
+
+var (
+	ErrChecksumMismatch  = errors.New("applied migration checksum mismatch")
+	ErrMissingDown       = errors.New("migration has no down script")
+	ErrUnknownMigration  = errors.New("unknown migration version")
+	ErrMigrationNotFound = errors.New("applied migration not found in embedded set")
+)
+
+// Migration é um par up/down de scripts SQL versionados
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 do script up
+}
+
+// MigrationStatus descreve o estado de uma migração no banco
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Checksum  string     `json:"checksum"`
+	Modified  bool       `json:"modified"` // script mudou depois de aplicado
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator aplica as migrações embutidas registrando versão e checksum em schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator cria um migrator com as migrações embutidas no binário
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	list, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: list}, nil
+}
+
+// LoadMigrations lê NNNN_nome.sql (up) e NNNN_nome.down.sql (down) de fsys
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, file := range files {
+		base := path.Base(file)
+		down := strings.HasSuffix(base, ".down.sql")
+		stem := strings.TrimSuffix(strings.TrimSuffix(base, ".sql"), ".down")
+
+		prefix, name, ok := strings.Cut(stem, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", base)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", base)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", base, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %d used by %q and %q", version, m.Name, name)
+		}
+
+		if down {
+			m.Down = string(content)
+		} else {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+
+	return list, nil
+}
+
+// Latest retorna a maior versão embutida
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up aplica todas as migrações pendentes e retorna as versões aplicadas
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverte as últimas "steps" migrações aplicadas
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	var reverted []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		versions := sortedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && len(reverted) < steps; i-- {
+			if err := m.revert(ctx, conn, versions[i]); err != nil {
+				return err
+			}
+			reverted = append(reverted, versions[i])
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// To leva o schema até target, aplicando ou revertendo conforme necessário.
+// Retorna as versões aplicadas (up) ou revertidas (down), na ordem executada.
+func (m *Migrator) To(ctx context.Context, target int) ([]int, error) {
+	if target != 0 && m.find(target) == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownMigration, target)
+	}
+
+	var changed []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		// Revertendo versões acima do alvo (da mais nova para a mais antiga)
+		versions := sortedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && versions[i] > target; i-- {
+			if err := m.revert(ctx, conn, versions[i]); err != nil {
+				return err
+			}
+			changed = append(changed, versions[i])
+		}
+
+		// Aplicando versões pendentes até o alvo
+		for _, migration := range m.migrations {
+			if migration.Version > target {
+				break
+			}
+			if _, done := applied[migration.Version]; done {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed = append(changed, migration.Version)
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// Status lista as migrações embutidas e seu estado no banco, sem alterá-lo:
+// sem a tabela schema_migrations nenhuma migração foi aplicada
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	var table sql.NullString
+	if err := conn.QueryRowContext(ctx, `SELECT to_regclass($1)::text`, schemaMigrationsTable).Scan(&table); err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", schemaMigrationsTable, err)
+	}
+
+	applied := make(map[int]appliedMigration)
+	if table.Valid {
+		if applied, err = m.applied(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := MigrationStatus{Version: migration.Version, Name: migration.Name, Checksum: migration.Checksum}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			s.Applied = true
+			s.AppliedAt = &appliedAt
+			s.Modified = record.checksum != migration.Checksum
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// withLock executa fn numa conexão dedicada segurando o advisory lock de migração
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if err := ensureSchemaTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func ensureSchemaTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+schemaMigrationsTable+` (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", schemaMigrationsTable, err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM `+schemaMigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = record
+	}
+	return applied, rows.Err()
+}
+
+// verify garante que scripts já aplicados não foram alterados nem removidos
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	for version, record := range applied {
+		migration := m.find(version)
+		if migration == nil {
+			return fmt.Errorf("%w: %04d_%s", ErrMigrationNotFound, version, record.name)
+		}
+		if migration.Checksum != record.checksum {
+			return fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO `+schemaMigrationsTable+` (version, name, checksum) VALUES ($1, $2, $3)`,
+		migration.Version, migration.Name, migration.Checksum,
+	); err != nil {
+		return fmt.Errorf("failed to record migration %04d: %w", migration.Version, err)
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, version int) error {
+	migration := m.find(version)
+	if migration == nil {
+		return fmt.Errorf("%w: %d", ErrMigrationNotFound, version)
+	}
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %04d_%s", ErrMissingDown, migration.Version, migration.Name)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+schemaMigrationsTable+` WHERE version = $1`, version); err != nil {
+		return fmt.Errorf("failed to unrecord migration %04d: %w", version, err)
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func sortedVersions(applied map[int]appliedMigration) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
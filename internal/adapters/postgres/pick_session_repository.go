@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const pickSessionColumns = `id, fulfillment_order_id, task_id, operator, COALESCE(device_id, ''), status, steps,
+	current_step, created_at, updated_at, completed_at, version`
+
+// CreatePickSession insere a sessão; o índice parcial garante uma única sessão em andamento por ordem
+func (r *FulfillmentRepository) CreatePickSession(ctx context.Context, session *fulfillment.PickSession) error {
+	stepsJSON, err := json.Marshal(session.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pick steps: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO pick_sessions (
+			id, fulfillment_order_id, task_id, operator, device_id, status, steps,
+			current_step, created_at, updated_at, completed_at, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (fulfillment_order_id) WHERE status = 'IN_PROGRESS' DO NOTHING
+	`, session.ID, session.OrderID, session.TaskID, session.Operator, nullableString(session.DeviceID), session.Status, stepsJSON,
+		session.Current, session.CreatedAt, session.UpdatedAt, nullableTime(session.CompletedAt), session.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert pick session: %w", err)
+	}
+	inserted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return fmt.Errorf("%w: %s", fulfillment.ErrPickSessionExists, session.OrderID)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetPickSession(ctx context.Context, id string) (*fulfillment.PickSession, error) {
+	return scanPickSession(r.db.QueryRowContext(ctx, `SELECT `+pickSessionColumns+` FROM pick_sessions WHERE id = $1`, id))
+}
+
+func (r *FulfillmentRepository) GetActivePickSession(ctx context.Context, orderID string) (*fulfillment.PickSession, error) {
+	return scanPickSession(r.db.QueryRowContext(ctx,
+		`SELECT `+pickSessionColumns+` FROM pick_sessions WHERE fulfillment_order_id = $1 AND status = 'IN_PROGRESS'`, orderID,
+	))
+}
+
+// UpdatePickSession grava a sessão se a versão não mudou desde a leitura e incrementa a versão
+func (r *FulfillmentRepository) UpdatePickSession(ctx context.Context, session *fulfillment.PickSession) error {
+	stepsJSON, err := json.Marshal(session.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pick steps: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE pick_sessions
+		SET device_id = $1, status = $2, steps = $3, current_step = $4, updated_at = $5, completed_at = $6,
+		    version = version + 1
+		WHERE id = $7 AND version = $8
+	`, nullableString(session.DeviceID), session.Status, stepsJSON, session.Current, session.UpdatedAt,
+		nullableTime(session.CompletedAt), session.ID, session.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pick session: %w", err)
+	}
+	updated, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		if _, err := r.GetPickSession(ctx, session.ID); err != nil {
+			return err
+		}
+		return fulfillment.ErrPickSessionConflict
+	}
+	session.Version++
+	return nil
+}
+
+func scanPickSession(row rowScanner) (*fulfillment.PickSession, error) {
+	var session fulfillment.PickSession
+	var stepsJSON []byte
+	var completedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID, &session.OrderID, &session.TaskID, &session.Operator, &session.DeviceID, &session.Status, &stepsJSON,
+		&session.Current, &session.CreatedAt, &session.UpdatedAt, &completedAt, &session.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrPickSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to scan pick session: %w", err)
+	}
+	if err := json.Unmarshal(stepsJSON, &session.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pick steps: %w", err)
+	}
+	session.CompletedAt = timePtr(completedAt)
+	return &session, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const cutoffCalendarColumns = `id, carrier, service, cutoffs, COALESCE(holidays, '[]'), COALESCE(timezone, ''), updated_at`
+
+// SaveCutoffCalendar insere ou substitui o calendário da transportadora e serviço (mantendo o ID existente)
+func (r *FulfillmentRepository) SaveCutoffCalendar(ctx context.Context, calendar *fulfillment.CutoffCalendar) error {
+	cutoffsJSON, err := json.Marshal(calendar.Cutoffs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cutoffs: %w", err)
+	}
+	holidaysJSON, err := json.Marshal(calendar.Holidays)
+	if err != nil {
+		return fmt.Errorf("failed to marshal holidays: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO carrier_cutoff_calendars (id, carrier, service, cutoffs, holidays, timezone, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (carrier, service) DO UPDATE SET
+			cutoffs = EXCLUDED.cutoffs, holidays = EXCLUDED.holidays,
+			timezone = EXCLUDED.timezone, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`, calendar.ID, calendar.Carrier, calendar.Service, cutoffsJSON, holidaysJSON, nullableString(calendar.Timezone), calendar.UpdatedAt,
+	).Scan(&calendar.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save cutoff calendar: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) ListCutoffCalendars(ctx context.Context, carrier string) ([]*fulfillment.CutoffCalendar, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+cutoffCalendarColumns+` FROM carrier_cutoff_calendars
+		 WHERE $1 = '' OR carrier = $1 ORDER BY carrier, service`,
+		carrier,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cutoff calendars: %w", err)
+	}
+	defer rows.Close()
+
+	var calendars []*fulfillment.CutoffCalendar
+	for rows.Next() {
+		var calendar fulfillment.CutoffCalendar
+		var cutoffsJSON, holidaysJSON []byte
+		if err := rows.Scan(
+			&calendar.ID, &calendar.Carrier, &calendar.Service, &cutoffsJSON, &holidaysJSON, &calendar.Timezone, &calendar.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan cutoff calendar: %w", err)
+		}
+		if err := json.Unmarshal(cutoffsJSON, &calendar.Cutoffs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cutoffs: %w", err)
+		}
+		if err := json.Unmarshal(holidaysJSON, &calendar.Holidays); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal holidays: %w", err)
+		}
+		calendars = append(calendars, &calendar)
+	}
+	return calendars, rows.Err()
+}
+
+func (r *FulfillmentRepository) DeleteCutoffCalendar(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM carrier_cutoff_calendars WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete cutoff calendar: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fulfillment.ErrCutoffCalendarNotFound
+	}
+	return nil
+}
+
+// ListOpenOrders implementa fulfillment.PriorityRepository (ordens ainda não expedidas nem bloqueadas)
+func (r *FulfillmentRepository) ListOpenOrders(ctx context.Context, afterID string, limit int) ([]*fulfillment.FulfillmentOrder, error) {
+	query := `SELECT ` + orderColumns + `
+		FROM fulfillment_orders WHERE status IN ($1, $2) AND id > $3
+		ORDER BY id LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, fulfillment.StatusPending, fulfillment.StatusInProgress, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*fulfillment.FulfillmentOrder
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const replenishmentRuleColumns = `id, sku, pick_location, reserve_location, mode,
+	min_quantity, max_quantity, case_quantity, enabled, COALESCE(open_transfer_id, ''), created_at, updated_at`
+
+// SaveReplenishmentRule insere ou substitui a regra do SKU no endereço de picking (mantendo o ID e a
+// movimentação em andamento existentes)
+func (r *FulfillmentRepository) SaveReplenishmentRule(ctx context.Context, rule *fulfillment.ReplenishmentRule) error {
+	query := `
+		INSERT INTO replenishment_rules (
+			id, sku, pick_location, reserve_location, mode,
+			min_quantity, max_quantity, case_quantity, enabled, open_transfer_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (sku, pick_location) DO UPDATE SET
+			reserve_location = EXCLUDED.reserve_location, mode = EXCLUDED.mode,
+			min_quantity = EXCLUDED.min_quantity, max_quantity = EXCLUDED.max_quantity,
+			case_quantity = EXCLUDED.case_quantity, enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, COALESCE(open_transfer_id, '')
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rule.ID, rule.SKU, rule.PickLocation, rule.ReserveLocation, rule.Mode,
+		rule.Min, rule.Max, rule.CaseQuantity, rule.Enabled, nullableString(rule.OpenTransferID),
+		rule.CreatedAt, rule.UpdatedAt,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.OpenTransferID)
+	if err != nil {
+		return fmt.Errorf("failed to save replenishment rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateReplenishmentTransfer reivindica ou libera a regra com um update condicional, para que o
+// agendador e o gatilho da separação não criem duas movimentações para a mesma regra
+func (r *FulfillmentRepository) UpdateReplenishmentTransfer(ctx context.Context, id, from, to string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE replenishment_rules SET open_transfer_id = $1, updated_at = $2
+		WHERE id = $3 AND COALESCE(open_transfer_id, '') = $4`,
+		nullableString(to), time.Now(), id, from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update replenishment rule transfer: %w", err)
+	}
+	updated, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("%w: rule %s", fulfillment.ErrReplenishmentMoveChanged, id)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) ListReplenishmentRules(ctx context.Context, pickLocation string) ([]*fulfillment.ReplenishmentRule, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+replenishmentRuleColumns+` FROM replenishment_rules
+		 WHERE $1 = '' OR pick_location = $1 ORDER BY pick_location, sku`,
+		pickLocation,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replenishment rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*fulfillment.ReplenishmentRule
+	for rows.Next() {
+		rule, err := scanReplenishmentRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *FulfillmentRepository) DeleteReplenishmentRule(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM replenishment_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete replenishment rule: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fulfillment.ErrReplenishmentRuleNotFound
+	}
+	return nil
+}
+
+func scanReplenishmentRule(row rowScanner) (*fulfillment.ReplenishmentRule, error) {
+	var rule fulfillment.ReplenishmentRule
+	err := row.Scan(
+		&rule.ID, &rule.SKU, &rule.PickLocation, &rule.ReserveLocation, &rule.Mode,
+		&rule.Min, &rule.Max, &rule.CaseQuantity, &rule.Enabled, &rule.OpenTransferID,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrReplenishmentRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to scan replenishment rule: %w", err)
+	}
+	return &rule, nil
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const slottingColumns = `
+		id, sku, class, from_location, to_location, COALESCE(displaced_sku, ''),
+		picks, savings, status, transfer_ids, created_at, updated_at
+`
+
+// ListShippedOrders implementa fulfillment.SlottingRepository (histórico de expedição para o giro)
+func (r *FulfillmentRepository) ListShippedOrders(ctx context.Context, since time.Time) ([]*fulfillment.FulfillmentOrder, error) {
+	query := `SELECT ` + orderColumns + `
+		FROM fulfillment_orders WHERE status = $1 AND shipped_at >= $2
+		ORDER BY shipped_at`
+
+	rows, err := r.db.QueryContext(ctx, query, fulfillment.StatusCompleted, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shipped orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*fulfillment.FulfillmentOrder
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// ReplaceSlottingProposals descarta as propostas pendentes e grava as da nova análise na mesma transação
+func (r *FulfillmentRepository) ReplaceSlottingProposals(ctx context.Context, recommendations []*fulfillment.SlottingRecommendation) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM slotting_recommendations WHERE status = $1`, fulfillment.SlottingProposed); err != nil {
+			return fmt.Errorf("failed to delete slotting proposals: %w", err)
+		}
+		for _, rec := range recommendations {
+			transferIDs, err := json.Marshal(rec.TransferIDs)
+			if err != nil {
+				return fmt.Errorf("failed to marshal transfer ids: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO slotting_recommendations (
+					id, sku, class, from_location, to_location, displaced_sku,
+					picks, savings, status, transfer_ids, created_at, updated_at
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			`, rec.ID, rec.SKU, rec.Class, rec.FromLocation, rec.ToLocation, nullableString(rec.DisplacedSKU),
+				rec.Picks, rec.Savings, rec.Status, transferIDs, rec.CreatedAt, rec.UpdatedAt,
+			); err != nil {
+				return fmt.Errorf("failed to insert slotting recommendation: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *FulfillmentRepository) GetSlottingRecommendation(ctx context.Context, id string) (*fulfillment.SlottingRecommendation, error) {
+	return scanSlottingRecommendation(r.db.QueryRowContext(ctx,
+		`SELECT `+slottingColumns+` FROM slotting_recommendations WHERE id = $1`, id,
+	))
+}
+
+func (r *FulfillmentRepository) UpdateSlottingRecommendation(ctx context.Context, rec *fulfillment.SlottingRecommendation, from fulfillment.SlottingStatus) error {
+	transferIDs, err := json.Marshal(rec.TransferIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transfer ids: %w", err)
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE slotting_recommendations SET status = $2, transfer_ids = $3, updated_at = $4 WHERE id = $1 AND status = $5
+	`, rec.ID, rec.Status, transferIDs, rec.UpdatedAt, from)
+	if err != nil {
+		return fmt.Errorf("failed to update slotting recommendation: %w", err)
+	}
+	updated, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return r.slottingNotUpdated(ctx, rec.ID, from)
+	}
+	return nil
+}
+
+// slottingNotUpdated distingue a recomendação inexistente da que mudou de status
+func (r *FulfillmentRepository) slottingNotUpdated(ctx context.Context, id string, from fulfillment.SlottingStatus) error {
+	var status fulfillment.SlottingStatus
+	err := r.db.QueryRowContext(ctx, `SELECT status FROM slotting_recommendations WHERE id = $1`, id).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fulfillment.ErrSlottingRecommendationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get slotting recommendation status: %w", err)
+	}
+	return fmt.Errorf("%w: %s, expected %s", fulfillment.ErrSlottingNotProposed, status, from)
+}
+
+func (r *FulfillmentRepository) ListSlottingRecommendations(ctx context.Context, status fulfillment.SlottingStatus) ([]*fulfillment.SlottingRecommendation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+slottingColumns+` FROM slotting_recommendations
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY savings DESC, id
+	`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slotting recommendations: %w", err)
+	}
+	defer rows.Close()
+
+	var recommendations []*fulfillment.SlottingRecommendation
+	for rows.Next() {
+		rec, err := scanSlottingRecommendation(rows)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+	return recommendations, rows.Err()
+}
+
+func scanSlottingRecommendation(row rowScanner) (*fulfillment.SlottingRecommendation, error) {
+	var rec fulfillment.SlottingRecommendation
+	var transferIDs []byte
+	err := row.Scan(
+		&rec.ID, &rec.SKU, &rec.Class, &rec.FromLocation, &rec.ToLocation, &rec.DisplacedSKU,
+		&rec.Picks, &rec.Savings, &rec.Status, &transferIDs, &rec.CreatedAt, &rec.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrSlottingRecommendationNotFound
+		}
+		return nil, fmt.Errorf("failed to scan slotting recommendation: %w", err)
+	}
+	if err := json.Unmarshal(transferIDs, &rec.TransferIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transfer ids: %w", err)
+	}
+	return &rec, nil
+}
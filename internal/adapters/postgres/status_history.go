@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// entityTables mapeia o tipo de entidade do histórico para sua tabela
+var entityTables = map[string]string{
+	fulfillment.EntityInboundShipment:  "inbound_shipments",
+	fulfillment.EntityFulfillmentOrder: "fulfillment_orders",
+	fulfillment.EntityTransferOrder:    "transfer_orders",
+	fulfillment.EntityReturnOrder:      "return_orders",
+	fulfillment.EntityCycleCountTask:   "cycle_count_tasks",
+	fulfillment.EntityWarehouseTask:    "warehouse_tasks",
+	fulfillment.EntityAssemblyOrder:    "assembly_orders",
+}
+
+// entityTypes lista os tipos de entidade de operação em ordem estável (consultas UNION de bloqueios)
+var entityTypes = []string{
+	fulfillment.EntityInboundShipment,
+	fulfillment.EntityFulfillmentOrder,
+	fulfillment.EntityTransferOrder,
+	fulfillment.EntityReturnOrder,
+	fulfillment.EntityCycleCountTask,
+}
+
+// failedEntityTypes acrescenta as entidades que falham mas não são bloqueadas (consultas UNION de falhas)
+var failedEntityTypes = append(entityTypes[:len(entityTypes):len(entityTypes)], fulfillment.EntityAssemblyOrder)
+
+// inTx executa fn numa transação, com rollback em caso de erro
+func (r *FulfillmentRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// createWithHistory executa o insert e registra a transição inicial quando a linha foi criada.
+// insert retorna false para reentregas idempotentes (linha já existente).
+func (r *FulfillmentRepository) createWithHistory(ctx context.Context, entityType, id string, status fulfillment.Status, insert func(tx *sql.Tx) (bool, error)) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		created, err := insert(tx)
+		if err != nil || !created {
+			return err
+		}
+		return recordTransition(ctx, tx, fulfillment.NewStatusTransition(ctx, entityType, id, "", status))
+	})
+}
+
+// updateWithHistory trava a linha, executa o update e registra a transição se o status mudou
+func (r *FulfillmentRepository) updateWithHistory(ctx context.Context, entityType, id string, status fulfillment.Status, notFound error, update func(tx *sql.Tx) error) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		var from fulfillment.Status
+		err := tx.QueryRowContext(ctx,
+			`SELECT status FROM `+entityTables[entityType]+` WHERE id = $1 FOR UPDATE`, id,
+		).Scan(&from)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return notFound
+			}
+			return fmt.Errorf("failed to lock %s: %w", entityType, err)
+		}
+
+		if err := update(tx); err != nil {
+			return err
+		}
+
+		if from == status {
+			return nil
+		}
+		return recordTransition(ctx, tx, fulfillment.NewStatusTransition(ctx, entityType, id, from, status))
+	})
+}
+
+// updateStatus altera apenas o status (e updated_at) registrando a transição
+func (r *FulfillmentRepository) updateStatus(ctx context.Context, entityType, id string, status fulfillment.Status, notFound error) error {
+	return r.updateWithHistory(ctx, entityType, id, status, notFound, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE `+entityTables[entityType]+` SET status = $1, updated_at = $2 WHERE id = $3`,
+			status, time.Now(), id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update %s status: %w", entityType, err)
+		}
+		return nil
+	})
+}
+
+// insertedRow indica se um INSERT ... ON CONFLICT DO NOTHING criou a linha
+func insertedRow(result sql.Result) (bool, error) {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func recordTransition(ctx context.Context, tx *sql.Tx, t fulfillment.StatusTransition) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO status_history (
+			entity_type, entity_id, from_status, to_status,
+			actor, source, reason, correlation_id, occurred_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		t.EntityType, t.EntityID, nullableString(string(t.FromStatus)), t.ToStatus,
+		nullableString(t.Actor), nullableString(t.Source), nullableString(t.Reason),
+		nullableString(t.CorrelationID), t.OccurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record status transition: %w", err)
+	}
+	return nil
+}
+
+// ListStatusHistory retorna a linha do tempo de status da entidade em ordem cronológica
+func (r *FulfillmentRepository) ListStatusHistory(ctx context.Context, entityType, entityID string) ([]fulfillment.StatusTransition, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT entity_type, entity_id, COALESCE(from_status, ''), to_status,
+		       COALESCE(actor, ''), COALESCE(source, ''), COALESCE(reason, ''),
+		       COALESCE(correlation_id, ''), occurred_at
+		FROM status_history
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY occurred_at, id
+	`, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []fulfillment.StatusTransition
+	for rows.Next() {
+		var t fulfillment.StatusTransition
+		if err := rows.Scan(
+			&t.EntityType, &t.EntityID, &t.FromStatus, &t.ToStatus,
+			&t.Actor, &t.Source, &t.Reason, &t.CorrelationID, &t.OccurredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status transition: %w", err)
+		}
+		history = append(history, t)
+	}
+
+	return history, rows.Err()
+}
+
+// StatusDwellStats calcula permanência média, p95 e máxima por status a partir do histórico.
+// Status correntes não terminais contam até agora.
+func (r *FulfillmentRepository) StatusDwellStats(ctx context.Context, entityType string, since time.Time) ([]fulfillment.DwellStat, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH spans AS (
+			SELECT to_status, occurred_at,
+			       LEAD(occurred_at) OVER (PARTITION BY entity_id ORDER BY occurred_at, id) AS next_at
+			FROM status_history
+			WHERE entity_type = $1 AND occurred_at >= $2
+		), dwell AS (
+			SELECT to_status, EXTRACT(EPOCH FROM COALESCE(next_at, NOW()) - occurred_at) AS seconds
+			FROM spans
+			WHERE next_at IS NOT NULL OR to_status NOT IN ($3, $4)
+		)
+		SELECT to_status, COUNT(*), AVG(seconds),
+		       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY seconds), MAX(seconds)
+		FROM dwell
+		GROUP BY to_status
+		ORDER BY to_status
+	`, entityType, since, fulfillment.StatusCompleted, fulfillment.StatusCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dwell stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []fulfillment.DwellStat
+	for rows.Next() {
+		var stat fulfillment.DwellStat
+		var avg, p95, max float64
+		if err := rows.Scan(&stat.Status, &stat.Count, &avg, &p95, &max); err != nil {
+			return nil, fmt.Errorf("failed to scan dwell stats: %w", err)
+		}
+		stat.EntityType = entityType
+		stat.Average = secondsToDuration(avg)
+		stat.P95 = secondsToDuration(p95)
+		stat.Max = secondsToDuration(max)
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
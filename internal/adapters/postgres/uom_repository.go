@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const uomColumns = `sku, levels, unit_cube, unit_value, COALESCE(gtin, ''), created_at, updated_at`
+
+// SaveUoMHierarchy insere ou substitui a hierarquia de embalagens do SKU
+func (r *FulfillmentRepository) SaveUoMHierarchy(ctx context.Context, hierarchy *fulfillment.UoMHierarchy) error {
+	levelsJSON, err := json.Marshal(hierarchy.Levels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal uom levels: %w", err)
+	}
+
+	query := `
+		INSERT INTO uom_hierarchies (sku, levels, unit_cube, unit_value, gtin, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (sku) DO UPDATE SET
+			levels = EXCLUDED.levels, unit_cube = EXCLUDED.unit_cube, unit_value = EXCLUDED.unit_value,
+			gtin = EXCLUDED.gtin, updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		hierarchy.SKU, levelsJSON, hierarchy.UnitCube, hierarchy.UnitValue, nullableString(hierarchy.GTIN), hierarchy.CreatedAt, hierarchy.UpdatedAt,
+	).Scan(&hierarchy.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save uom hierarchy: %w", err)
+	}
+	return nil
+}
+
+func (r *FulfillmentRepository) GetUoMHierarchy(ctx context.Context, sku string) (*fulfillment.UoMHierarchy, error) {
+	return scanUoMHierarchy(r.db.QueryRowContext(ctx,
+		`SELECT `+uomColumns+` FROM uom_hierarchies WHERE sku = $1`, sku,
+	))
+}
+
+// FindUoMHierarchyByGTIN busca pelo GTIN da unidade base ou de qualquer nível de embalagem
+func (r *FulfillmentRepository) FindUoMHierarchyByGTIN(ctx context.Context, gtin string) (*fulfillment.UoMHierarchy, error) {
+	return scanUoMHierarchy(r.db.QueryRowContext(ctx, `
+		SELECT `+uomColumns+` FROM uom_hierarchies
+		WHERE gtin = $1 OR levels @> jsonb_build_array(jsonb_build_object('gtin', $1::text))
+		LIMIT 1
+	`, gtin))
+}
+
+func (r *FulfillmentRepository) ListUoMHierarchies(ctx context.Context) ([]*fulfillment.UoMHierarchy, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+uomColumns+` FROM uom_hierarchies ORDER BY sku`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query uom hierarchies: %w", err)
+	}
+	defer rows.Close()
+
+	var hierarchies []*fulfillment.UoMHierarchy
+	for rows.Next() {
+		hierarchy, err := scanUoMHierarchy(rows)
+		if err != nil {
+			return nil, err
+		}
+		hierarchies = append(hierarchies, hierarchy)
+	}
+	return hierarchies, rows.Err()
+}
+
+func (r *FulfillmentRepository) DeleteUoMHierarchy(ctx context.Context, sku string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM uom_hierarchies WHERE sku = $1`, sku)
+	if err != nil {
+		return fmt.Errorf("failed to delete uom hierarchy: %w", err)
+	}
+	deleted, err := insertedRow(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fulfillment.ErrUoMHierarchyNotFound
+	}
+	return nil
+}
+
+func scanUoMHierarchy(row rowScanner) (*fulfillment.UoMHierarchy, error) {
+	var hierarchy fulfillment.UoMHierarchy
+	var levelsJSON []byte
+	err := row.Scan(&hierarchy.SKU, &levelsJSON, &hierarchy.UnitCube, &hierarchy.UnitValue, &hierarchy.GTIN, &hierarchy.CreatedAt, &hierarchy.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrUoMHierarchyNotFound
+		}
+		return nil, fmt.Errorf("failed to scan uom hierarchy: %w", err)
+	}
+	if err := json.Unmarshal(levelsJSON, &hierarchy.Levels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal uom levels: %w", err)
+	}
+	return &hierarchy, nil
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+const taskColumns = `id, type, priority, status, source_location, destination_location,
+	entity_type, entity_id, COALESCE(assigned_to, ''), COALESCE(device_id, ''),
+	created_at, updated_at, accepted_at, started_at, completed_at, version`
+
+// CreateTask persiste uma nova tarefa de armazém
+func (r *FulfillmentRepository) CreateTask(ctx context.Context, task *fulfillment.WarehouseTask) error {
+	query := `
+		INSERT INTO warehouse_tasks (
+			id, type, priority, status, source_location, destination_location,
+			entity_type, entity_id, created_at, updated_at, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	return r.createWithHistory(ctx, fulfillment.EntityWarehouseTask, task.ID, task.Status, func(tx *sql.Tx) (bool, error) {
+		result, err := tx.ExecContext(ctx, query,
+			task.ID, task.Type, task.Priority, task.Status, task.SourceLocation, task.DestinationLocation,
+			task.EntityType, task.EntityID, task.CreatedAt, task.UpdatedAt, task.Version,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return false, fmt.Errorf("%w: open task already exists for %s %s", fulfillment.ErrTaskConflict, task.EntityType, task.EntityID)
+			}
+			return false, fmt.Errorf("failed to insert warehouse task: %w", err)
+		}
+		return insertedRow(result)
+	})
+}
+
+func (r *FulfillmentRepository) GetTaskByID(ctx context.Context, id string) (*fulfillment.WarehouseTask, error) {
+	return scanTask(r.db.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM warehouse_tasks WHERE id = $1`, id))
+}
+
+// GetTaskByEntity retorna a tarefa mais recente da operação
+func (r *FulfillmentRepository) GetTaskByEntity(ctx context.Context, entityType, entityID string) (*fulfillment.WarehouseTask, error) {
+	return scanTask(r.db.QueryRowContext(ctx,
+		`SELECT `+taskColumns+` FROM warehouse_tasks WHERE entity_type = $1 AND entity_id = $2
+		 ORDER BY created_at DESC LIMIT 1`,
+		entityType, entityID,
+	))
+}
+
+// UpdateTask persiste a tarefa se a versão não mudou desde a leitura e incrementa a versão
+func (r *FulfillmentRepository) UpdateTask(ctx context.Context, task *fulfillment.WarehouseTask) error {
+	query := `
+		UPDATE warehouse_tasks
+		SET status = $1, priority = $2, assigned_to = $3, device_id = $4, updated_at = $5,
+		    accepted_at = $6, started_at = $7, completed_at = $8, version = version + 1
+		WHERE id = $9 AND version = $10
+	`
+
+	err := r.updateWithHistory(ctx, fulfillment.EntityWarehouseTask, task.ID, task.Status, fulfillment.ErrTaskNotFound, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			task.Status, task.Priority, nullableString(task.AssignedTo), nullableString(task.DeviceID), time.Now(),
+			nullableTime(task.AcceptedAt), nullableTime(task.StartedAt), nullableTime(task.CompletedAt),
+			task.ID, task.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update warehouse task: %w", err)
+		}
+		updated, err := insertedRow(result)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return fulfillment.ErrTaskConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	task.Version++
+	return nil
+}
+
+func (r *FulfillmentRepository) ListOpenTasks(ctx context.Context, limit int) ([]*fulfillment.WarehouseTask, error) {
+	return r.queryTasks(ctx,
+		`SELECT `+taskColumns+` FROM warehouse_tasks
+		 WHERE status IN ('PENDING', 'ASSIGNED', 'IN_PROGRESS')
+		 ORDER BY priority DESC, created_at LIMIT $1`,
+		limit,
+	)
+}
+
+func (r *FulfillmentRepository) ListTasksByAssignee(ctx context.Context, assignee string, limit int) ([]*fulfillment.WarehouseTask, error) {
+	return r.queryTasks(ctx,
+		`SELECT `+taskColumns+` FROM warehouse_tasks WHERE assigned_to = $1
+		 ORDER BY updated_at DESC LIMIT $2`,
+		assignee, limit,
+	)
+}
+
+func (r *FulfillmentRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*fulfillment.WarehouseTask, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query warehouse tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*fulfillment.WarehouseTask
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+func scanTask(row rowScanner) (*fulfillment.WarehouseTask, error) {
+	var task fulfillment.WarehouseTask
+	var acceptedAt, startedAt, completedAt sql.NullTime
+
+	err := row.Scan(
+		&task.ID, &task.Type, &task.Priority, &task.Status, &task.SourceLocation, &task.DestinationLocation,
+		&task.EntityType, &task.EntityID, &task.AssignedTo, &task.DeviceID,
+		&task.CreatedAt, &task.UpdatedAt, &acceptedAt, &startedAt, &completedAt, &task.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fulfillment.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to scan warehouse task: %w", err)
+	}
+
+	task.AcceptedAt = timePtr(acceptedAt)
+	task.StartedAt = timePtr(startedAt)
+	task.CompletedAt = timePtr(completedAt)
+	return &task, nil
+}
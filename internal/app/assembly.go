@@ -0,0 +1,255 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// AssemblyUseCase gerencia definições de kit e executa ordens de montagem/desmontagem.
+// Os ajustes no Core Inventory são aplicados em sequência; se um falha, os já aplicados
+// são revertidos (compensação) para que a ordem seja atômica do ponto de vista do estoque.
+type AssemblyUseCase struct {
+	kits            fulfillment.KitRepository
+	repo            fulfillment.Repository
+	inventoryClient InventoryClient
+	shipOrder       *ShipOrderUseCase
+	logger          Logger
+}
+
+// NewAssemblyUseCase cria uma nova instância do caso de uso
+func NewAssemblyUseCase(
+	kits fulfillment.KitRepository,
+	repo fulfillment.Repository,
+	inventoryClient InventoryClient,
+	shipOrder *ShipOrderUseCase,
+	logger Logger,
+) *AssemblyUseCase {
+	return &AssemblyUseCase{
+		kits:            kits,
+		repo:            repo,
+		inventoryClient: inventoryClient,
+		shipOrder:       shipOrder,
+		logger:          logger,
+	}
+}
+
+// DefineKit cria ou substitui a lista de materiais do kit
+func (uc *AssemblyUseCase) DefineKit(ctx context.Context, sku, name string, components []fulfillment.KitComponent) (*fulfillment.KitDefinition, error) {
+	kit, err := fulfillment.NewKitDefinition(sku, name, components)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		// Kits aninhados não são suportados: a montagem consome apenas componentes simples
+		if _, err := uc.kits.GetKit(ctx, component.SKU); err == nil {
+			return nil, fmt.Errorf("%w: component %s is itself a kit", fulfillment.ErrInvalidKit, component.SKU)
+		} else if !errors.Is(err, fulfillment.ErrKitNotFound) {
+			return nil, fmt.Errorf("failed to get kit definition: %w", err)
+		}
+	}
+
+	if err := uc.kits.SaveKit(ctx, kit); err != nil {
+		return nil, fmt.Errorf("failed to save kit definition: %w", err)
+	}
+	uc.logger.Info("Kit defined", "sku", sku, "components", len(components))
+	return kit, nil
+}
+
+func (uc *AssemblyUseCase) GetKit(ctx context.Context, sku string) (*fulfillment.KitDefinition, error) {
+	kit, err := uc.kits.GetKit(ctx, sku)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kit definition: %w", err)
+	}
+	return kit, nil
+}
+
+func (uc *AssemblyUseCase) ListKits(ctx context.Context) ([]*fulfillment.KitDefinition, error) {
+	kits, err := uc.kits.ListKits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kit definitions: %w", err)
+	}
+	return kits, nil
+}
+
+func (uc *AssemblyUseCase) DeleteKit(ctx context.Context, sku string) error {
+	if err := uc.kits.DeleteKit(ctx, sku); err != nil {
+		return fmt.Errorf("failed to delete kit definition: %w", err)
+	}
+	return nil
+}
+
+func (uc *AssemblyUseCase) GetOrder(ctx context.Context, id string) (*fulfillment.AssemblyOrder, error) {
+	order, err := uc.kits.GetAssemblyOrderByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assembly order: %w", err)
+	}
+	return order, nil
+}
+
+// CreateOrder cria e executa uma ordem de montagem ou desmontagem do kit no endereço
+func (uc *AssemblyUseCase) CreateOrder(ctx context.Context, kitSKU string, direction fulfillment.AssemblyDirection, quantity int, location, sourceOrderID string) (*fulfillment.AssemblyOrder, error) {
+	kit, err := uc.GetKit(ctx, kitSKU)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := fulfillment.NewAssemblyOrder(kit, direction, quantity, location, sourceOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.kits.CreateAssemblyOrder(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to persist assembly order: %w", err)
+	}
+
+	uc.logger.Info("Assembly order created", "id", order.ID, "kit_sku", kitSKU, "direction", direction, "quantity", quantity)
+	return order, uc.execute(ctx, order)
+}
+
+// Execute executa uma ordem pendente ou reexecuta uma ordem que falhou
+func (uc *AssemblyUseCase) Execute(ctx context.Context, id string) (*fulfillment.AssemblyOrder, error) {
+	order, err := uc.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == fulfillment.StatusFailed {
+		// Antes de reexecutar, tenta desfazer os ajustes cuja compensação falhou
+		if err := uc.reconcile(ctx, order); err != nil {
+			return order, err
+		}
+		if err := reopen(ctx, order); err != nil {
+			return order, fmt.Errorf("invalid state transition: %w", err)
+		}
+	}
+	return order, uc.execute(ctx, order)
+}
+
+func (uc *AssemblyUseCase) execute(ctx context.Context, order *fulfillment.AssemblyOrder) error {
+	if err := order.Start(); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+	if err := uc.kits.UpdateAssemblyOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update assembly order status: %w", err)
+	}
+
+	movements := order.Movements()
+	for idx, movement := range movements {
+		if err := uc.inventoryClient.AdjustStock(ctx, movement.Location, movement.SKU, movement.Quantity, ""); err != nil {
+			uc.logger.Error("Failed to adjust stock for assembly, compensating", "error", err, "id", order.ID, "sku", movement.SKU)
+			order.Unreconciled = uc.compensate(ctx, movements[:idx])
+			order.Fail(fulfillment.StepAdjustStock, idx, err)
+			uc.kits.UpdateAssemblyOrder(ctx, order)
+			return fmt.Errorf("failed to adjust stock for SKU %s: %w", movement.SKU, err)
+		}
+	}
+
+	if err := order.Complete(); err != nil {
+		return fmt.Errorf("failed to complete assembly order: %w", err)
+	}
+	if err := uc.kits.UpdateAssemblyOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update assembly order status: %w", err)
+	}
+
+	uc.logger.Info("Assembly order completed", "id", order.ID, "kit_sku", order.KitSKU, "direction", order.Direction)
+	return nil
+}
+
+// compensate reverte os ajustes aplicados em ordem inversa e retorna os que não puderam ser revertidos
+func (uc *AssemblyUseCase) compensate(ctx context.Context, applied []fulfillment.Item) []fulfillment.Item {
+	var unreconciled []fulfillment.Item
+	for idx := len(applied) - 1; idx >= 0; idx-- {
+		movement := applied[idx]
+		if err := uc.inventoryClient.AdjustStock(ctx, movement.Location, movement.SKU, -movement.Quantity, ""); err != nil {
+			uc.logger.Error("Failed to compensate assembly movement", "error", err, "sku", movement.SKU, "quantity", movement.Quantity)
+			unreconciled = append(unreconciled, movement)
+		}
+	}
+	return unreconciled
+}
+
+// reconcile tenta novamente reverter os ajustes pendentes de compensação da ordem
+func (uc *AssemblyUseCase) reconcile(ctx context.Context, order *fulfillment.AssemblyOrder) error {
+	if len(order.Unreconciled) == 0 {
+		return nil
+	}
+	order.Unreconciled = uc.compensate(ctx, order.Unreconciled)
+	if err := uc.kits.UpdateAssemblyOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update assembly order: %w", err)
+	}
+	if len(order.Unreconciled) > 0 {
+		return fulfillment.ErrAssemblyUnreconciled
+	}
+	return nil
+}
+
+// AssembleForOrder monta os kits que faltam para a ordem de fulfillment e, se algo foi montado,
+// tenta liberar o backorder
+func (uc *AssemblyUseCase) AssembleForOrder(ctx context.Context, orderID string) ([]*fulfillment.AssemblyOrder, error) {
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+	return uc.assembleForOrder(ctx, order)
+}
+
+// OnBackordered é o OrderHook que dispara a montagem sob demanda de ordens em backorder
+func (uc *AssemblyUseCase) OnBackordered(ctx context.Context, order *fulfillment.FulfillmentOrder) {
+	if _, err := uc.assembleForOrder(ctx, order); err != nil {
+		uc.logger.Error("On-demand kit assembly failed", "error", err, "order_id", order.OrderID)
+	}
+}
+
+func (uc *AssemblyUseCase) assembleForOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) ([]*fulfillment.AssemblyOrder, error) {
+	// Quantidade pedida por endereço e SKU (a ordem pode repetir o SKU em várias linhas)
+	required := make(map[string]fulfillment.Item)
+	var keys []string
+	for _, item := range order.Items {
+		key := stockKey(item.Location, item.SKU)
+		current, ok := required[key]
+		if !ok {
+			keys = append(keys, key)
+			current = fulfillment.Item{SKU: item.SKU, Location: item.Location}
+		}
+		current.Quantity += item.Quantity
+		required[key] = current
+	}
+
+	assembled := []*fulfillment.AssemblyOrder{}
+	for _, key := range keys {
+		item := required[key]
+		if _, err := uc.kits.GetKit(ctx, item.SKU); err != nil {
+			if errors.Is(err, fulfillment.ErrKitNotFound) {
+				continue
+			}
+			return assembled, fmt.Errorf("failed to get kit definition: %w", err)
+		}
+
+		available, err := uc.inventoryClient.GetAvailableStock(ctx, item.Location, item.SKU)
+		if err != nil {
+			return assembled, fmt.Errorf("failed to get kit stock: %w", err)
+		}
+		shortfall := item.Quantity - available
+		if shortfall <= 0 {
+			continue
+		}
+
+		assembly, err := uc.CreateOrder(ctx, item.SKU, fulfillment.AssemblyAssemble, shortfall, item.Location, order.ID)
+		if err != nil {
+			return assembled, err
+		}
+		assembled = append(assembled, assembly)
+	}
+
+	if len(assembled) == 0 || order.ReservationStatus != fulfillment.ReservationBackordered {
+		return assembled, nil
+	}
+
+	// Outros itens podem continuar sem saldo: nesse caso a ordem permanece em backorder
+	if err := uc.shipOrder.ReleaseBackorder(ctx, order.ID); err != nil && !errors.Is(err, fulfillment.ErrOrderBackordered) {
+		return assembled, fmt.Errorf("failed to release backorder: %w", err)
+	}
+	return assembled, nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// BlockEscalator notifica supervisores sobre operações bloqueadas há mais tempo que o limite
+type BlockEscalator struct {
+	blocks    fulfillment.BlockRepository
+	notifier  EscalationNotifier
+	threshold time.Duration
+	batchSize int
+	logger    Logger
+}
+
+// NewBlockEscalator cria uma nova instância do escalonador
+func NewBlockEscalator(blocks fulfillment.BlockRepository, notifier EscalationNotifier, threshold time.Duration, logger Logger) *BlockEscalator {
+	return &BlockEscalator{
+		blocks:    blocks,
+		notifier:  notifier,
+		threshold: threshold,
+		batchSize: 500,
+		logger:    logger,
+	}
+}
+
+// Run executa o escalonamento periodicamente até o contexto ser cancelado
+func (e *BlockEscalator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Block escalator stopped")
+			return
+		case <-ticker.C:
+			if _, err := e.Escalate(ctx); err != nil {
+				e.logger.Error("Block escalation failed", "error", err)
+			}
+		}
+	}
+}
+
+// Escalate notifica cada operação bloqueada além do limite uma única vez e retorna quantas foram escalonadas
+func (e *BlockEscalator) Escalate(ctx context.Context) (int, error) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "block-escalator")
+	now := time.Now()
+	blocked, err := e.blocks.ListUnescalatedBlocked(ctx, now.Add(-e.threshold), e.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list blocked operations: %w", err)
+	}
+
+	escalated := 0
+	for _, entity := range blocked {
+		if entity.Block == nil {
+			continue
+		}
+
+		if err := e.notifier.PublishBlockEscalated(ctx, entity); err != nil {
+			e.logger.Error("Failed to notify block escalation", "error", err, "entity_type", entity.EntityType, "id", entity.EntityID)
+			continue
+		}
+		if err := e.blocks.MarkBlockEscalated(ctx, entity.EntityType, entity.EntityID, now); err != nil {
+			e.logger.Error("Failed to persist block escalation", "error", err, "entity_type", entity.EntityType, "id", entity.EntityID)
+			continue
+		}
+		escalated++
+	}
+
+	if escalated > 0 {
+		e.logger.Warn("Blocked operations escalated", "count", escalated, "threshold", e.threshold)
+	}
+	return escalated, nil
+}
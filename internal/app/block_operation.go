@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// ErrNotBlocked indica desbloqueio de uma operação que não está em BLOCKED
+var ErrNotBlocked = errors.New("operation is not blocked")
+
+// blockable é a parte comum das entidades que podem ser bloqueadas
+type blockable interface {
+	fulfillment.Stateful
+	Block(reason fulfillment.BlockReason, note, actor string) error
+	Unblock() error
+	ReleaseHold(actor string) error
+}
+
+// BlockUseCase bloqueia e desbloqueia operações de fulfillment com código de motivo
+type BlockUseCase struct {
+	repo      fulfillment.Repository
+	blocks    fulfillment.BlockRepository
+	batchSize int
+	logger    Logger
+}
+
+// NewBlockUseCase cria uma nova instância do caso de uso
+func NewBlockUseCase(repo fulfillment.Repository, blocks fulfillment.BlockRepository, logger Logger) *BlockUseCase {
+	return &BlockUseCase{
+		repo:      repo,
+		blocks:    blocks,
+		batchSize: 500,
+		logger:    logger,
+	}
+}
+
+// Block move a operação para BLOCKED registrando motivo, nota e operador
+func (uc *BlockUseCase) Block(ctx context.Context, entityType, id string, reason fulfillment.BlockReason, note string) error {
+	entity, save, err := loadBlockable(ctx, uc.repo, entityType, id)
+	if err != nil {
+		return err
+	}
+	if err := entity.Block(reason, note, fulfillment.ActorFromContext(ctx)); err != nil {
+		return fmt.Errorf("failed to block %s: %w", entityType, err)
+	}
+
+	historyReason := string(reason)
+	if note != "" {
+		historyReason += ": " + note
+	}
+	if err := save(fulfillment.WithReason(ctx, historyReason)); err != nil {
+		return fmt.Errorf("failed to update %s: %w", entityType, err)
+	}
+
+	uc.logger.Info("Operation blocked", "entity_type", entityType, "id", id, "reason", reason)
+	return nil
+}
+
+// Unblock devolve a operação bloqueada ao status anterior ao bloqueio
+func (uc *BlockUseCase) Unblock(ctx context.Context, entityType, id, note string) error {
+	entity, save, err := loadBlockable(ctx, uc.repo, entityType, id)
+	if err != nil {
+		return err
+	}
+	if entity.CurrentStatus() != fulfillment.StatusBlocked {
+		return fmt.Errorf("%w: %s %s is %s", ErrNotBlocked, entityType, id, entity.CurrentStatus())
+	}
+	if err := entity.Unblock(); err != nil {
+		return fmt.Errorf("failed to unblock %s: %w", entityType, err)
+	}
+
+	if note != "" {
+		ctx = fulfillment.WithReason(ctx, note)
+	}
+	if err := save(ctx); err != nil {
+		return fmt.Errorf("failed to update %s: %w", entityType, err)
+	}
+
+	uc.logger.Info("Operation unblocked", "entity_type", entityType, "id", id)
+	return nil
+}
+
+// ReleaseHold registra a liberação da retenção (alfândega, qualidade); a operação segue bloqueada até o Unblock
+func (uc *BlockUseCase) ReleaseHold(ctx context.Context, entityType, id string) error {
+	entity, save, err := loadBlockable(ctx, uc.repo, entityType, id)
+	if err != nil {
+		return err
+	}
+	if err := entity.ReleaseHold(fulfillment.ActorFromContext(ctx)); err != nil {
+		return fmt.Errorf("failed to release hold on %s %s: %w", entityType, id, err)
+	}
+	if err := save(ctx); err != nil {
+		return fmt.Errorf("failed to update %s: %w", entityType, err)
+	}
+
+	uc.logger.Info("Operation hold released", "entity_type", entityType, "id", id)
+	return nil
+}
+
+// ListBlocked lista as operações bloqueadas (entityType vazio = todas), das mais antigas para as mais recentes
+func (uc *BlockUseCase) ListBlocked(ctx context.Context, entityType string) ([]fulfillment.BlockedEntity, error) {
+	if entityType != "" && !knownEntityTypes[entityType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+	blocked, err := uc.blocks.ListBlocked(ctx, entityType, time.Now(), uc.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocked operations: %w", err)
+	}
+	return blocked, nil
+}
+
+// loadBlockable carrega a entidade e devolve a função que a persiste
+func loadBlockable(ctx context.Context, repo fulfillment.Repository, entityType, id string) (blockable, func(context.Context) error, error) {
+	switch entityType {
+	case fulfillment.EntityInboundShipment:
+		shipment, err := repo.GetInboundByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get inbound shipment: %w", err)
+		}
+		return shipment, func(ctx context.Context) error { return repo.UpdateInbound(ctx, shipment) }, nil
+	case fulfillment.EntityFulfillmentOrder:
+		order, err := repo.GetOrderByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		return order, func(ctx context.Context) error { return repo.UpdateOrder(ctx, order) }, nil
+	case fulfillment.EntityTransferOrder:
+		transfer, err := repo.GetTransferByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get transfer: %w", err)
+		}
+		return transfer, func(ctx context.Context) error { return repo.UpdateTransfer(ctx, transfer) }, nil
+	case fulfillment.EntityReturnOrder:
+		returnOrder, err := repo.GetReturnByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get return order: %w", err)
+		}
+		return returnOrder, func(ctx context.Context) error { return repo.UpdateReturn(ctx, returnOrder) }, nil
+	case fulfillment.EntityCycleCountTask:
+		task, err := repo.GetCycleCountByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get cycle count task: %w", err)
+		}
+		return task, func(ctx context.Context) error { return repo.UpdateCycleCount(ctx, task) }, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+}
@@ -13,6 +13,7 @@ type CompleteTransferUseCase struct {
 	inventoryClient InventoryClient
 	eventPublisher  EventPublisher
 	logger          Logger
+	unitNormalizer
 }
 
 // NewCompleteTransferUseCase cria uma nova instância do caso de uso
@@ -27,6 +28,11 @@ func NewCompleteTransferUseCase(repo fulfillment.Repository, inventoryClient Inv
 
 // CreateTransfer cria uma nova TransferOrder
 func (uc *CompleteTransferUseCase) CreateTransfer(ctx context.Context, locationFrom, locationTo string, items []fulfillment.Item) (*fulfillment.TransferOrder, error) {
+	items, err := uc.normalize(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	transfer, err := fulfillment.NewTransferOrder(locationFrom, locationTo, items)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transfer order: %w", err)
@@ -61,20 +67,32 @@ func (uc *CompleteTransferUseCase) CompleteTransfer(ctx context.Context, transfe
 		return fmt.Errorf("failed to update transfer status: %w", err)
 	}
 
+	// No retry, retoma do item que falhou; se a falha foi na entrada, a saída desse item já foi aplicada
+	start, skipOut := transfer.Failure.ResumeFrom(fulfillment.StepAdjustStockOut), false
+	if transfer.Failure != nil && transfer.Failure.Step == fulfillment.StepAdjustStockIn {
+		start, skipOut = transfer.Failure.Item, true
+	}
+
 	// Chama Core para saída de origem
-	for _, item := range transfer.Items {
+	for idx := start; idx < len(transfer.Items); idx++ {
+		item := transfer.Items[idx]
+
 		// Saída (quantidade negativa)
-		if err := uc.inventoryClient.AdjustStock(ctx, transfer.LocationFrom, item.SKU, -item.Quantity, item.Batch); err != nil {
-			uc.logger.Error("Failed to adjust stock (outbound) in core inventory", "error", err, "sku", item.SKU)
-			transfer.Status = fulfillment.StatusFailed
-			uc.repo.UpdateTransfer(ctx, transfer)
-			return fmt.Errorf("failed to adjust stock (outbound) for SKU %s: %w", item.SKU, err)
+		if !skipOut || idx != start {
+			outCtx := fulfillment.WithCommandID(ctx, fulfillment.CommandID(transfer.ID, fulfillment.StepAdjustStockOut, idx))
+			if err := uc.inventoryClient.AdjustStock(outCtx, transfer.LocationFrom, item.SKU, -item.Quantity, item.Batch); err != nil {
+				uc.logger.Error("Failed to adjust stock (outbound) in core inventory", "error", err, "sku", item.SKU)
+				transfer.Fail(fulfillment.StepAdjustStockOut, idx, err)
+				uc.repo.UpdateTransfer(ctx, transfer)
+				return fmt.Errorf("failed to adjust stock (outbound) for SKU %s: %w", item.SKU, err)
+			}
 		}
 
 		// Entrada no destino
-		if err := uc.inventoryClient.AdjustStock(ctx, transfer.LocationTo, item.SKU, item.Quantity, item.Batch); err != nil {
+		inCtx := fulfillment.WithCommandID(ctx, fulfillment.CommandID(transfer.ID, fulfillment.StepAdjustStockIn, idx))
+		if err := uc.inventoryClient.AdjustStock(inCtx, transfer.LocationTo, item.SKU, item.Quantity, item.Batch); err != nil {
 			uc.logger.Error("Failed to adjust stock (inbound) in core inventory", "error", err, "sku", item.SKU)
-			transfer.Status = fulfillment.StatusFailed
+			transfer.Fail(fulfillment.StepAdjustStockIn, idx, err)
 			uc.repo.UpdateTransfer(ctx, transfer)
 			return fmt.Errorf("failed to adjust stock (inbound) for SKU %s: %w", item.SKU, err)
 		}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// CountPlannerUseCase mantém o programa de contagem cíclica: classifica os endereços pela curva ABC
+// de giro e valor, gera diariamente as contagens vencidas dentro da cota e atende os gatilhos de
+// contagem extra (separação que zerou o saldo, saldo negativo e divergência recente)
+type CountPlannerUseCase struct {
+	programs        fulfillment.CountProgramRepository
+	history         fulfillment.ShipmentHistoryRepository
+	uoms            fulfillment.UoMRepository
+	repo            fulfillment.Repository
+	inventoryClient InventoryClient
+	openCycleCount  *OpenCycleCountUseCase
+	logger          Logger
+}
+
+// NewCountPlannerUseCase cria uma nova instância do caso de uso
+func NewCountPlannerUseCase(
+	programs fulfillment.CountProgramRepository,
+	history fulfillment.ShipmentHistoryRepository,
+	uoms fulfillment.UoMRepository,
+	repo fulfillment.Repository,
+	inventoryClient InventoryClient,
+	openCycleCount *OpenCycleCountUseCase,
+	logger Logger,
+) *CountPlannerUseCase {
+	return &CountPlannerUseCase{
+		programs:        programs,
+		history:         history,
+		uoms:            uoms,
+		repo:            repo,
+		inventoryClient: inventoryClient,
+		openCycleCount:  openCycleCount,
+		logger:          logger,
+	}
+}
+
+// GetProgram retorna o programa configurado ou o padrão (A mensal, B trimestral, C anual)
+func (uc *CountPlannerUseCase) GetProgram(ctx context.Context) (*fulfillment.CountProgram, error) {
+	program, err := uc.programs.GetCountProgram(ctx)
+	if errors.Is(err, fulfillment.ErrCountProgramNotFound) {
+		return fulfillment.DefaultCountProgram(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get count program: %w", err)
+	}
+	return program, nil
+}
+
+// DefineProgram substitui o programa de contagem
+func (uc *CountPlannerUseCase) DefineProgram(ctx context.Context, program *fulfillment.CountProgram) error {
+	if err := program.Validate(); err != nil {
+		return err
+	}
+	program.UpdatedAt = time.Now()
+	if err := uc.programs.SaveCountProgram(ctx, program); err != nil {
+		return fmt.Errorf("failed to save count program: %w", err)
+	}
+	return nil
+}
+
+// Classify classifica os endereços separados na janela do programa
+func (uc *CountPlannerUseCase) Classify(ctx context.Context, now time.Time) ([]fulfillment.CountCandidate, error) {
+	program, err := uc.GetProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.classify(ctx, program, now)
+}
+
+func (uc *CountPlannerUseCase) classify(ctx context.Context, program *fulfillment.CountProgram, now time.Time) ([]fulfillment.CountCandidate, error) {
+	orders, err := uc.history.ListShippedOrders(ctx, now.AddDate(0, 0, -program.LookbackDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipped orders: %w", err)
+	}
+	hierarchies, err := uc.uoms.ListUoMHierarchies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uom hierarchies: %w", err)
+	}
+	unitValues := make(map[string]float64, len(hierarchies))
+	for _, hierarchy := range hierarchies {
+		unitValues[hierarchy.SKU] = hierarchy.UnitValue
+	}
+	return fulfillment.ClassifyCountLocations(orders, unitValues, float64(program.LookbackDays)), nil
+}
+
+// Run gera o plano de contagens periodicamente até o contexto ser cancelado
+func (uc *CountPlannerUseCase) Run(ctx context.Context, interval time.Duration) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "count-planner")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Count planner stopped")
+			return
+		case <-ticker.C:
+			if _, err := uc.PlanDay(ctx, time.Now()); err != nil {
+				uc.logger.Error("Cycle count planning failed", "error", err)
+			}
+		}
+	}
+}
+
+// PlanDay abre as contagens do dia (gatilhos pendentes e endereços vencidos dentro da cota).
+// Pode ser repetido no mesmo dia: endereços com contagem aberta são ignorados e as abertas no dia consomem a cota.
+func (uc *CountPlannerUseCase) PlanDay(ctx context.Context, now time.Time) (*fulfillment.CountPlan, error) {
+	program, err := uc.GetProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := uc.classify(ctx, program, now)
+	if err != nil {
+		return nil, err
+	}
+	history, err := uc.programs.ListLocationCountHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list location count history: %w", err)
+	}
+	triggers, err := uc.programs.ListPendingCountTriggers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list count triggers: %w", err)
+	}
+
+	plan := program.Plan(candidates, history, triggers, now)
+	for idx := range plan.Entries {
+		entry := &plan.Entries[idx]
+		task, err := uc.openCycleCount.OpenCycleCount(ctx, entry.Location, entry.SKUs)
+		if err != nil {
+			return nil, err
+		}
+		entry.TaskID = task.ID
+		if len(entry.TriggerIDs) > 0 {
+			if err := uc.programs.MarkCountTriggersPlanned(ctx, entry.TriggerIDs, task.ID); err != nil {
+				return nil, fmt.Errorf("failed to mark count triggers planned: %w", err)
+			}
+		}
+	}
+
+	uc.logger.Info("Cycle counts planned", "date", plan.Date.Format(time.DateOnly), "quota", plan.Quota, "counts", len(plan.Entries))
+	return plan, nil
+}
+
+// ListTriggers lista os gatilhos de contagem extra ainda sem contagem
+func (uc *CountPlannerUseCase) ListTriggers(ctx context.Context) ([]*fulfillment.CountTrigger, error) {
+	triggers, err := uc.programs.ListPendingCountTriggers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list count triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// OnTaskCompleted é o TaskHook que verifica o saldo dos endereços de uma separação concluída:
+// saldo zerado ou negativo pede contagem extra
+func (uc *CountPlannerUseCase) OnTaskCompleted(ctx context.Context, task *fulfillment.WarehouseTask) {
+	if task.Type != fulfillment.TaskPick {
+		return
+	}
+	order, err := uc.repo.GetOrderByID(ctx, task.EntityID)
+	if err != nil {
+		uc.logger.Error("Failed to load picked order for count triggers", "error", err, "task_id", task.ID)
+		return
+	}
+
+	checked := make(map[string]bool)
+	for _, item := range order.Items {
+		key := stockKey(item.Location, item.SKU)
+		if item.Location == "" || checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		available, err := uc.inventoryClient.GetAvailableStock(ctx, item.Location, item.SKU)
+		if err != nil {
+			uc.logger.Error("Failed to check stock after pick", "error", err, "location", item.Location, "sku", item.SKU)
+			continue
+		}
+		switch {
+		case available < 0:
+			uc.trigger(ctx, item.Location, item.SKU, fulfillment.CountNegativeBalance, available)
+		case available == 0:
+			uc.trigger(ctx, item.Location, item.SKU, fulfillment.CountZeroStockPick, available)
+		}
+	}
+}
+
+// OnVariance é o VarianceHook que pede a recontagem de endereços com divergência recente
+func (uc *CountPlannerUseCase) OnVariance(ctx context.Context, location, sku string, difference int) {
+	uc.trigger(ctx, location, sku, fulfillment.CountRecentVariance, difference)
+}
+
+func (uc *CountPlannerUseCase) trigger(ctx context.Context, location, sku string, reason fulfillment.CountReason, quantity int) {
+	trigger, err := fulfillment.NewCountTrigger(location, sku, reason, quantity)
+	if err != nil {
+		uc.logger.Error("Invalid count trigger", "error", err, "location", location, "sku", sku)
+		return
+	}
+	if err := uc.programs.CreateCountTrigger(ctx, trigger); err != nil {
+		uc.logger.Error("Failed to create count trigger", "error", err, "location", location, "sku", sku)
+		return
+	}
+	uc.logger.Info("Cycle count triggered", "location", location, "sku", sku, "reason", reason, "quantity", quantity)
+}
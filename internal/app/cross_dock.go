@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// CrossDockUseCase destina mercadoria recebida diretamente às ordens de saída que aguardam os mesmos SKUs.
+// A quantidade alocada vai da doca para a área de expedição por uma transferência com tarefa CROSS_DOCK;
+// apenas o restante segue para armazenagem.
+type CrossDockUseCase struct {
+	crossDock        fulfillment.CrossDockRepository
+	repo             fulfillment.Repository
+	completeTransfer *CompleteTransferUseCase
+	shipOrder        *ShipOrderUseCase
+	tasks            *WarehouseTaskUseCase
+	batchSize        int
+	logger           Logger
+}
+
+// NewCrossDockUseCase cria uma nova instância do caso de uso
+func NewCrossDockUseCase(
+	crossDock fulfillment.CrossDockRepository,
+	repo fulfillment.Repository,
+	completeTransfer *CompleteTransferUseCase,
+	shipOrder *ShipOrderUseCase,
+	tasks *WarehouseTaskUseCase,
+	logger Logger,
+) *CrossDockUseCase {
+	return &CrossDockUseCase{
+		crossDock:        crossDock,
+		repo:             repo,
+		completeTransfer: completeTransfer,
+		shipOrder:        shipOrder,
+		tasks:            tasks,
+		batchSize:        500,
+		logger:           logger,
+	}
+}
+
+// DefinePolicy cria ou substitui a política do cliente e SKU
+func (uc *CrossDockUseCase) DefinePolicy(ctx context.Context, policy *fulfillment.CrossDockPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	policy.UpdatedAt = time.Now()
+	if err := uc.crossDock.SaveCrossDockPolicy(ctx, policy); err != nil {
+		return fmt.Errorf("failed to save cross-dock policy: %w", err)
+	}
+	return nil
+}
+
+func (uc *CrossDockUseCase) ListPolicies(ctx context.Context) ([]*fulfillment.CrossDockPolicy, error) {
+	policies, err := uc.crossDock.ListCrossDockPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cross-dock policies: %w", err)
+	}
+	return policies, nil
+}
+
+func (uc *CrossDockUseCase) DeletePolicy(ctx context.Context, id string) error {
+	if err := uc.crossDock.DeleteCrossDockPolicy(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete cross-dock policy: %w", err)
+	}
+	return nil
+}
+
+// ListAllocations lista as alocações do recebimento (vazio = mais recentes)
+func (uc *CrossDockUseCase) ListAllocations(ctx context.Context, shipmentID string) ([]*fulfillment.CrossDockAllocation, error) {
+	allocations, err := uc.crossDock.ListCrossDockAllocations(ctx, shipmentID, uc.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cross-dock allocations: %w", err)
+	}
+	return allocations, nil
+}
+
+// OnReceived é o InboundHook que aplica o cross-dock ao recebimento confirmado
+func (uc *CrossDockUseCase) OnReceived(ctx context.Context, shipment *fulfillment.InboundShipment) {
+	if _, err := uc.Allocate(ctx, shipment); err != nil {
+		uc.logger.Error("Cross-dock allocation failed", "error", err, "shipment_id", shipment.ID)
+	}
+}
+
+// Allocate distribui o recebimento entre as ordens aguardando os SKUs, gerando uma transferência
+// doca -> expedição (com tarefa CROSS_DOCK) por ordem e área de expedição
+func (uc *CrossDockUseCase) Allocate(ctx context.Context, shipment *fulfillment.InboundShipment) ([]fulfillment.CrossDockAllocation, error) {
+	policies, err := uc.ListPolicies(ctx)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	orders, err := uc.waitingOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := fulfillment.PlanCrossDock(shipment, orders, policies)
+	if len(plan) == 0 {
+		return nil, nil
+	}
+
+	byID := make(map[string]*fulfillment.FulfillmentOrder, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+	}
+
+	// Agrupa por ordem e área de expedição, preservando a ordem de prioridade do plano
+	type group struct {
+		order       *fulfillment.FulfillmentOrder
+		to          string
+		allocations []fulfillment.CrossDockAllocation
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	for _, allocation := range plan {
+		key := allocation.OrderID + "|" + allocation.To
+		g, ok := index[key]
+		if !ok {
+			g = &group{order: byID[allocation.OrderID], to: allocation.To}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.allocations = append(g.allocations, allocation)
+	}
+
+	var allocated []fulfillment.CrossDockAllocation
+	for _, g := range groups {
+		done, err := uc.allocateToOrder(ctx, shipment, g.order, g.to, g.allocations)
+		if err != nil {
+			uc.logger.Error("Failed to cross-dock to order", "error", err, "order_id", g.order.OrderID, "shipment_id", shipment.ID)
+			continue
+		}
+		allocated = append(allocated, done...)
+	}
+
+	if len(allocated) > 0 {
+		uc.logger.Info("Inbound cross-docked", "shipment_id", shipment.ID, "allocations", len(allocated))
+	}
+	return allocated, nil
+}
+
+func (uc *CrossDockUseCase) allocateToOrder(ctx context.Context, shipment *fulfillment.InboundShipment, order *fulfillment.FulfillmentOrder, to string, allocations []fulfillment.CrossDockAllocation) ([]fulfillment.CrossDockAllocation, error) {
+	items := make([]fulfillment.Item, 0, len(allocations))
+	for _, allocation := range allocations {
+		if err := order.StageCrossDock(allocation.Line, allocation.Quantity, to, allocation.Batch); err != nil {
+			return nil, err
+		}
+		items = append(items, fulfillment.Item{SKU: allocation.SKU, Quantity: allocation.Quantity, Batch: allocation.Batch})
+	}
+
+	transfer, err := uc.completeTransfer.CreateTransfer(ctx, shipment.Destination, to, items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cross-dock transfer: %w", err)
+	}
+	if err := uc.repo.UpdateOrder(ctx, order); err != nil {
+		// Sem as linhas preparadas na ordem, a transferência não teria destino: é cancelada
+		uc.discardTransfer(ctx, transfer)
+		return nil, fmt.Errorf("failed to update order lines: %w", err)
+	}
+
+	for idx := range allocations {
+		allocations[idx].TransferID = transfer.ID
+		if err := uc.crossDock.CreateCrossDockAllocation(ctx, &allocations[idx]); err != nil {
+			return nil, fmt.Errorf("failed to persist cross-dock allocation: %w", err)
+		}
+	}
+
+	if _, err := uc.tasks.EnqueueAs(ctx, fulfillment.TaskCrossDock, fulfillment.EntityTransferOrder, transfer.ID,
+		order.Priority+fulfillment.CrossDockPriorityBoost); err != nil {
+		uc.logger.Error("Failed to enqueue cross-dock task", "error", err, "transfer_id", transfer.ID)
+	}
+	return allocations, nil
+}
+
+// discardTransfer cancela a transferência de cross-dock que não chegou a ser alocada
+func (uc *CrossDockUseCase) discardTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) {
+	err := transfer.Cancel()
+	if err == nil {
+		err = uc.repo.UpdateTransfer(ctx, transfer)
+	}
+	if err != nil {
+		uc.logger.Error("Failed to cancel cross-dock transfer", "error", err, "transfer_id", transfer.ID)
+	}
+}
+
+// OnTaskCompleted é o TaskHook que reserva a ordem quando a mercadoria chega à área de expedição
+func (uc *CrossDockUseCase) OnTaskCompleted(ctx context.Context, task *fulfillment.WarehouseTask) {
+	if task.Type != fulfillment.TaskCrossDock {
+		return
+	}
+	allocation, err := uc.crossDock.GetCrossDockAllocationByTransfer(ctx, task.EntityID)
+	if err != nil {
+		uc.logger.Error("Failed to load cross-dock allocation", "error", err, "transfer_id", task.EntityID)
+		return
+	}
+	order, err := uc.repo.GetOrderByID(ctx, allocation.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to load cross-docked order", "error", err, "order_id", allocation.OrderID)
+		return
+	}
+	if order.ReservationStatus != fulfillment.ReservationBackordered {
+		return
+	}
+
+	// Outras linhas podem continuar sem saldo: a ordem permanece em backorder
+	err = uc.shipOrder.ReleaseBackorder(ctx, order.ID)
+	switch {
+	case err == nil:
+		uc.logger.Info("Cross-docked order reserved", "order_id", order.OrderID)
+	case errors.Is(err, fulfillment.ErrOrderBackordered):
+		uc.logger.Info("Cross-docked order still backordered", "order_id", order.OrderID)
+	default:
+		uc.logger.Error("Failed to release cross-docked order", "error", err, "order_id", order.OrderID)
+	}
+}
+
+// waitingOrders lista as ordens pendentes sem reserva ativa (backorder, sem reserva ou expirada)
+func (uc *CrossDockUseCase) waitingOrders(ctx context.Context) ([]*fulfillment.FulfillmentOrder, error) {
+	var waiting []*fulfillment.FulfillmentOrder
+	for _, status := range []fulfillment.ReservationStatus{
+		fulfillment.ReservationBackordered, fulfillment.ReservationNone, fulfillment.ReservationExpired,
+	} {
+		orders, err := ordersByReservationStatus(ctx, uc.repo, status, uc.batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list waiting orders: %w", err)
+		}
+		for _, order := range orders {
+			if order.CrossDockAllowed() == nil {
+				waiting = append(waiting, order)
+			}
+		}
+	}
+	return waiting, nil
+}
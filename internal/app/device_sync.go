@@ -0,0 +1,266 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/state/store"
+)
+
+// SyncHandler aplica à operação de fulfillment o valor sincronizado de uma chave (ex: leitura de separação)
+type SyncHandler func(ctx context.Context, value map[string]interface{}) error
+
+// DeviceSyncUseCase recebe as operações que os coletores registraram sem rede. Cada operação traz o
+// relógio vetorial da chave alterada e é comparada com o estado já sincronizado pelo resolvedor de
+// conflitos: a mais nova é aplicada, a superada é descartada e alterações concorrentes são combinadas
+// (CRDT) quando não divergem. As demais vão para a fila de revisão do supervisor.
+type DeviceSyncUseCase struct {
+	syncs    fulfillment.SyncRepository
+	resolver store.ConflictResolver
+	handlers map[string]SyncHandler
+	logger   Logger
+}
+
+// NewDeviceSyncUseCase cria uma nova instância do caso de uso
+func NewDeviceSyncUseCase(syncs fulfillment.SyncRepository, resolver store.ConflictResolver, logger Logger) *DeviceSyncUseCase {
+	return &DeviceSyncUseCase{
+		syncs:    syncs,
+		resolver: resolver,
+		handlers: make(map[string]SyncHandler),
+		logger:   logger,
+	}
+}
+
+// Handle registra o caso de uso que aplica as operações do tipo kind
+func (uc *DeviceSyncUseCase) Handle(kind string, handler SyncHandler) {
+	uc.handlers[kind] = handler
+}
+
+// Push sincroniza as operações do coletor, na ordem em que foram registradas. O envio é idempotente:
+// operações já sincronizadas retornam DUPLICATE ou STALE, e um conflito reenviado mantém o mesmo ID.
+func (uc *DeviceSyncUseCase) Push(ctx context.Context, device string, ops []fulfillment.SyncOperation) ([]fulfillment.SyncResult, error) {
+	for idx := range ops {
+		op := &ops[idx]
+		if op.DeviceID == "" {
+			op.DeviceID = device
+		}
+		if op.DeviceID != device {
+			return nil, fmt.Errorf("%w: operation %s belongs to device %s", fulfillment.ErrInvalidSyncOperation, op.ID, op.DeviceID)
+		}
+		if err := op.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := uc.handlers[op.Kind]; !ok {
+			return nil, fmt.Errorf("%w: %s", fulfillment.ErrUnsupportedSyncKind, op.Kind)
+		}
+	}
+
+	results := make([]fulfillment.SyncResult, 0, len(ops))
+	counts := make(map[fulfillment.SyncOutcome]int)
+	for idx := range ops {
+		result, err := uc.sync(ctx, &ops[idx])
+		if err != nil {
+			return nil, err
+		}
+		counts[result.Outcome]++
+		results = append(results, result)
+	}
+
+	uc.logger.Info("Device operations synced", "device_id", device, "operations", len(ops),
+		"applied", counts[fulfillment.SyncApplied]+counts[fulfillment.SyncMerged], "conflicts", counts[fulfillment.SyncConflict], "rejected", counts[fulfillment.SyncRejected])
+	return results, nil
+}
+
+// sync compara a operação com o estado da chave e a aplica, descarta ou envia para revisão
+func (uc *DeviceSyncUseCase) sync(ctx context.Context, op *fulfillment.SyncOperation) (fulfillment.SyncResult, error) {
+	result := fulfillment.SyncResult{OperationID: op.ID, Key: op.Key}
+
+	state, err := uc.syncs.GetSyncState(ctx, op.Key)
+	if errors.Is(err, fulfillment.ErrSyncStateNotFound) {
+		return uc.advance(ctx, fulfillment.NewSyncState(op), op.Payload, op, fulfillment.SyncApplied, true)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	resolution, err := uc.resolver.Resolve(ctx, &store.Conflict{
+		Key:         op.Key,
+		LocalState:  versionedState(state.Value, state.Clock, state.UpdatedAt, state.DeviceID, state.Version),
+		RemoteState: versionedState(op.Payload, op.Clock, op.RecordedAt, op.DeviceID, state.Version),
+		Strategy:    store.VectorClock,
+		Timestamp:   time.Now(),
+		NodeID:      op.DeviceID,
+	})
+	if err != nil {
+		return uc.conflict(ctx, state, op, nil, fmt.Sprintf("conflict resolver failed: %v", err))
+	}
+
+	switch resolution.Meta["resolution_reason"] {
+	case "vector-clock-remote":
+		return uc.advance(ctx, state, op.Payload, op, fulfillment.SyncApplied, true)
+	case "vector-clock-local":
+		result.Outcome, result.Clock = fulfillment.SyncStale, state.Clock
+		return result, nil
+	}
+	switch resolution.Meta["crdt_strategy"] {
+	case "merge":
+		// Concorrentes: a combinação campo a campo só vale se nenhum campo diverge
+		if fields := fulfillment.DivergentFields(state.Value, op.Payload); len(fields) > 0 {
+			return uc.conflict(ctx, state, op, fields, "concurrent operations changed the same fields")
+		}
+		merged, _ := resolution.Value.(map[string]interface{})
+		return uc.advance(ctx, state, merged, op, fulfillment.SyncMerged, !reflect.DeepEqual(merged, state.Value))
+	case nil:
+		// Mesmo relógio: reenvio de uma operação já sincronizada
+		result.Outcome, result.Clock = fulfillment.SyncDuplicate, state.Clock
+		return result, nil
+	default:
+		return uc.conflict(ctx, state, op, nil, "concurrent values could not be merged")
+	}
+}
+
+// advance grava o novo estado da chave e aplica o valor (se mudou) pelo caso de uso do tipo da operação.
+// Recusada pela operação de fulfillment, o estado da chave não muda.
+func (uc *DeviceSyncUseCase) advance(ctx context.Context, state *fulfillment.SyncState, value map[string]interface{}, op *fulfillment.SyncOperation, outcome fulfillment.SyncOutcome, changed bool) (fulfillment.SyncResult, error) {
+	result := fulfillment.SyncResult{OperationID: op.ID, Key: op.Key, Outcome: outcome}
+	var apply func() error
+	if changed {
+		apply = func() error { return uc.handlers[op.Kind](ctx, value) }
+	}
+
+	rejected, err := uc.claim(ctx, state, value, op, apply)
+	if err != nil {
+		return result, err
+	}
+	if rejected != nil {
+		uc.logger.Warn("Synced operation rejected", "operation_id", op.ID, "device_id", op.DeviceID, "key", op.Key, "error", rejected)
+		result.Outcome, result.Error = fulfillment.SyncRejected, rejected.Error()
+		if state.Version > 0 {
+			result.Clock = state.Clock
+		}
+		return result, nil
+	}
+	result.Clock = state.Clock
+	return result, nil
+}
+
+// claim grava o novo estado da chave antes de aplicar a operação: entre sincronizações concorrentes da
+// mesma chave, só a que grava primeiro executa o efeito (as demais recebem ErrSyncStateConflict).
+// Se apply recusar a operação, o estado anterior é restaurado e o erro de apply é retornado em rejected.
+func (uc *DeviceSyncUseCase) claim(ctx context.Context, state *fulfillment.SyncState, value map[string]interface{}, op *fulfillment.SyncOperation, apply func() error) (rejected error, err error) {
+	previous := *state
+	state.Advance(value, op)
+	if err := uc.syncs.SaveSyncState(ctx, state); err != nil {
+		*state = previous
+		return nil, fmt.Errorf("failed to save sync state: %w", err)
+	}
+	if apply == nil {
+		return nil, nil
+	}
+	if rejected = apply(); rejected == nil {
+		return nil, nil
+	}
+
+	if previous.Version == 0 {
+		err = uc.syncs.DeleteSyncState(ctx, state.Key, state.Version)
+	} else {
+		restored := previous
+		restored.Version = state.Version
+		if err = uc.syncs.SaveSyncState(ctx, &restored); err == nil {
+			previous.Version = restored.Version
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore sync state after rejected operation %s: %w", op.ID, err)
+	}
+	*state = previous
+	return rejected, nil
+}
+
+// conflict envia a operação para a fila de revisão do supervisor
+func (uc *DeviceSyncUseCase) conflict(ctx context.Context, state *fulfillment.SyncState, op *fulfillment.SyncOperation, fields []string, reason string) (fulfillment.SyncResult, error) {
+	record := fulfillment.NewSyncConflictRecord(state, op, fields, reason)
+	if err := uc.syncs.CreateSyncConflict(ctx, record); err != nil {
+		return fulfillment.SyncResult{}, fmt.Errorf("failed to create sync conflict: %w", err)
+	}
+
+	uc.logger.Warn("Sync conflict queued for review", "conflict_id", record.ID, "key", op.Key, "device_id", op.DeviceID, "server_device_id", state.DeviceID, "reason", reason)
+	return fulfillment.SyncResult{
+		OperationID: op.ID,
+		Key:         op.Key,
+		Outcome:     fulfillment.SyncConflict,
+		ConflictID:  record.ID,
+		Clock:       state.Clock,
+	}, nil
+}
+
+// ListConflicts lista a fila de revisão (status vazio = todos)
+func (uc *DeviceSyncUseCase) ListConflicts(ctx context.Context, status fulfillment.Status) ([]*fulfillment.SyncConflictRecord, error) {
+	conflicts, err := uc.syncs.ListSyncConflicts(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+// GetConflict retorna o conflito com os dois valores
+func (uc *DeviceSyncUseCase) GetConflict(ctx context.Context, id string) (*fulfillment.SyncConflictRecord, error) {
+	return uc.syncs.GetSyncConflict(ctx, id)
+}
+
+// ResolveConflict registra a decisão do supervisor (operador do contexto). DEVICE aplica a operação do
+// coletor; SERVER a descarta. Nos dois casos o relógio da chave passa a incluir o da operação.
+func (uc *DeviceSyncUseCase) ResolveConflict(ctx context.Context, id string, choice fulfillment.SyncChoice) (*fulfillment.SyncConflictRecord, error) {
+	record, err := uc.syncs.GetSyncConflict(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := record.Resolve(choice, fulfillment.ActorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	state, err := uc.syncs.GetSyncState(ctx, record.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync state: %w", err)
+	}
+	value := state.Value
+	var apply func() error
+	if choice == fulfillment.SyncTakeDevice {
+		handler, ok := uc.handlers[record.Kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", fulfillment.ErrUnsupportedSyncKind, record.Kind)
+		}
+		value = record.Device.Payload
+		apply = func() error { return handler(ctx, value) }
+	}
+	rejected, err := uc.claim(ctx, state, value, &record.Device, apply)
+	if err != nil {
+		return nil, err
+	}
+	if rejected != nil {
+		return nil, rejected
+	}
+	if err := uc.syncs.UpdateSyncConflict(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to update sync conflict: %w", err)
+	}
+
+	uc.logger.Info("Sync conflict resolved", "conflict_id", record.ID, "key", record.Key, "choice", choice, "resolved_by", record.ResolvedBy)
+	return record, nil
+}
+
+// versionedState descreve um valor no formato do resolvedor (relógio vetorial e instante em Meta)
+func versionedState(value map[string]interface{}, clock fulfillment.VectorClock, at time.Time, device string, version int64) *store.VersionedState {
+	return &store.VersionedState{
+		Value:   value,
+		Version: uint64(version),
+		Meta: map[string]interface{}{
+			"vector_clock": map[string]uint64(clock),
+			"timestamp":    at,
+			"device_id":    device,
+		},
+	}
+}
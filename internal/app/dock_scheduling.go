@@ -0,0 +1,280 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// noShowLookback limita a busca de agendamentos pendentes pelo detector de não comparecimento
+const noShowLookback = 7 * 24 * time.Hour
+
+// DockSchedulingUseCase agenda portas de doca para recebimentos e expedições, registra chegadas
+// (com atraso além da tolerância) e não comparecimentos, que alimentam os scorecards de fornecedores
+type DockSchedulingUseCase struct {
+	docks       fulfillment.DockRepository
+	repo        fulfillment.Repository
+	publisher   DockEventPublisher
+	lateGrace   time.Duration
+	noShowAfter time.Duration
+	logger      Logger
+}
+
+// BookingRequest descreve um pedido de agendamento; End vazio usa a duração do slot da porta
+type BookingRequest struct {
+	DoorID       string
+	Direction    fulfillment.DockDirection
+	ShipmentID   string
+	Supplier     string // Padrão: origem do recebimento ou transportadora da expedição
+	Carrier      string
+	Start        time.Time
+	End          time.Time
+	Requirements []string // Capacidades exigidas da porta (ex: REFRIGERATED)
+}
+
+// NewDockSchedulingUseCase cria uma nova instância do caso de uso
+func NewDockSchedulingUseCase(
+	docks fulfillment.DockRepository,
+	repo fulfillment.Repository,
+	publisher DockEventPublisher,
+	lateGrace time.Duration,
+	noShowAfter time.Duration,
+	logger Logger,
+) *DockSchedulingUseCase {
+	return &DockSchedulingUseCase{
+		docks:       docks,
+		repo:        repo,
+		publisher:   publisher,
+		lateGrace:   lateGrace,
+		noShowAfter: noShowAfter,
+		logger:      logger,
+	}
+}
+
+// DefineDoor cria ou substitui a porta pelo nome
+func (uc *DockSchedulingUseCase) DefineDoor(ctx context.Context, door *fulfillment.DockDoor) error {
+	if err := door.Validate(); err != nil {
+		return err
+	}
+	door.UpdatedAt = time.Now()
+	if err := uc.docks.SaveDockDoor(ctx, door); err != nil {
+		return fmt.Errorf("failed to save dock door: %w", err)
+	}
+	return nil
+}
+
+func (uc *DockSchedulingUseCase) ListDoors(ctx context.Context) ([]*fulfillment.DockDoor, error) {
+	doors, err := uc.docks.ListDockDoors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dock doors: %w", err)
+	}
+	return doors, nil
+}
+
+// AvailableSlots retorna os slots livres da porta no dia informado
+func (uc *DockSchedulingUseCase) AvailableSlots(ctx context.Context, doorID string, day time.Time) ([]fulfillment.TimeSlot, error) {
+	door, err := uc.docks.GetDockDoor(ctx, doorID)
+	if err != nil {
+		return nil, err
+	}
+	if !door.Active {
+		return nil, nil
+	}
+
+	// Agendamentos iniciados na véspera podem ocupar o começo do dia
+	booked, err := uc.docks.ListAppointments(ctx, doorID, day.Add(-24*time.Hour), day.Add(48*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dock appointments: %w", err)
+	}
+	return door.Slots(day, booked), nil
+}
+
+// Book valida porta, horário e remessa e grava o agendamento; a verificação de conflito é atômica no repositório
+func (uc *DockSchedulingUseCase) Book(ctx context.Context, req BookingRequest) (*fulfillment.DockAppointment, error) {
+	door, err := uc.docks.GetDockDoor(ctx, req.DoorID)
+	if err != nil {
+		return nil, err
+	}
+	if !door.Active {
+		return nil, fmt.Errorf("%w: door %s is inactive", fulfillment.ErrInvalidAppointment, door.Name)
+	}
+	if err := door.Supports(req.Direction, req.Requirements); err != nil {
+		return nil, err
+	}
+
+	end := req.End
+	if end.IsZero() {
+		end = req.Start.Add(time.Duration(door.SlotMinutes) * time.Minute)
+	}
+	if !door.IsOpen(req.Start, end) {
+		return nil, fulfillment.ErrDockClosed
+	}
+
+	shipmentType, supplier := fulfillment.EntityOutboundShipment, req.Supplier
+	if req.Direction == fulfillment.DockInbound {
+		shipment, err := uc.repo.GetInboundByID(ctx, req.ShipmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inbound shipment: %w", err)
+		}
+		shipmentType = fulfillment.EntityInboundShipment
+		if supplier == "" {
+			supplier = shipment.Origin
+		}
+	} else if supplier == "" {
+		supplier = req.Carrier
+	}
+
+	appointment, err := fulfillment.NewDockAppointment(door.ID, req.Direction, shipmentType, req.ShipmentID, supplier, req.Carrier, req.Start, end)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.docks.CreateAppointment(ctx, appointment); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Dock appointment scheduled", "appointment_id", appointment.ID, "door", door.Name, "start", appointment.ScheduledStart)
+	if err := uc.publisher.PublishAppointmentScheduled(ctx, appointment); err != nil {
+		uc.logger.Error("Failed to publish dock appointment scheduled event", "error", err)
+	}
+	return appointment, nil
+}
+
+func (uc *DockSchedulingUseCase) GetAppointment(ctx context.Context, id string) (*fulfillment.DockAppointment, error) {
+	return uc.docks.GetAppointment(ctx, id)
+}
+
+// ListAppointments lista os agendamentos iniciados em [from, to) (doorID vazio = todas as portas)
+func (uc *DockSchedulingUseCase) ListAppointments(ctx context.Context, doorID string, from, to time.Time) ([]*fulfillment.DockAppointment, error) {
+	appointments, err := uc.docks.ListAppointments(ctx, doorID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dock appointments: %w", err)
+	}
+	return appointments, nil
+}
+
+// CheckIn registra a chegada do veículo à doca
+func (uc *DockSchedulingUseCase) CheckIn(ctx context.Context, id string) (*fulfillment.DockAppointment, error) {
+	appointment, err := uc.update(ctx, id, func(a *fulfillment.DockAppointment) error {
+		return a.CheckIn(time.Now(), uc.lateGrace)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.LateMinutes > 0 {
+		uc.logger.Warn("Late dock arrival", "appointment_id", appointment.ID, "supplier", appointment.Supplier, "late_minutes", appointment.LateMinutes)
+	}
+	if err := uc.publisher.PublishAppointmentArrived(ctx, appointment); err != nil {
+		uc.logger.Error("Failed to publish dock appointment arrived event", "error", err)
+	}
+	return appointment, nil
+}
+
+// Complete encerra o atendimento e libera a porta
+func (uc *DockSchedulingUseCase) Complete(ctx context.Context, id string) (*fulfillment.DockAppointment, error) {
+	appointment, err := uc.update(ctx, id, func(a *fulfillment.DockAppointment) error {
+		return a.Complete(time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.publisher.PublishAppointmentCompleted(ctx, appointment); err != nil {
+		uc.logger.Error("Failed to publish dock appointment completed event", "error", err)
+	}
+	return appointment, nil
+}
+
+// Cancel cancela o agendamento e libera a porta
+func (uc *DockSchedulingUseCase) Cancel(ctx context.Context, id string) (*fulfillment.DockAppointment, error) {
+	appointment, err := uc.update(ctx, id, func(a *fulfillment.DockAppointment) error {
+		return a.Cancel(time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.publisher.PublishAppointmentCancelled(ctx, appointment); err != nil {
+		uc.logger.Error("Failed to publish dock appointment cancelled event", "error", err)
+	}
+	return appointment, nil
+}
+
+func (uc *DockSchedulingUseCase) update(ctx context.Context, id string, apply func(*fulfillment.DockAppointment) error) (*fulfillment.DockAppointment, error) {
+	appointment, err := uc.docks.GetAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from := appointment.Status
+	if err := apply(appointment); err != nil {
+		return nil, err
+	}
+	if err := uc.docks.UpdateAppointment(ctx, appointment, from); err != nil {
+		return nil, fmt.Errorf("failed to update dock appointment: %w", err)
+	}
+	return appointment, nil
+}
+
+// Run executa a detecção de não comparecimento periodicamente até o contexto ser cancelado
+func (uc *DockSchedulingUseCase) Run(ctx context.Context, interval time.Duration) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "dock-no-show-detector")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Dock no-show detector stopped")
+			return
+		case <-ticker.C:
+			if _, err := uc.DetectNoShows(ctx); err != nil {
+				uc.logger.Error("Dock no-show detection failed", "error", err)
+			}
+		}
+	}
+}
+
+// DetectNoShows marca como NO_SHOW os agendamentos sem chegada após o prazo e retorna quantos foram marcados
+func (uc *DockSchedulingUseCase) DetectNoShows(ctx context.Context) (int, error) {
+	now := time.Now()
+	appointments, err := uc.docks.ListAppointments(ctx, "", now.Add(-noShowLookback), now.Add(-uc.noShowAfter))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dock appointments: %w", err)
+	}
+
+	marked := 0
+	for _, appointment := range appointments {
+		if !appointment.MarkNoShow(now, uc.noShowAfter) {
+			continue
+		}
+		if err := uc.docks.UpdateAppointment(ctx, appointment, fulfillment.AppointmentScheduled); err != nil {
+			if errors.Is(err, fulfillment.ErrAppointmentChanged) {
+				// Chegada ou cancelamento registrado depois da listagem
+				continue
+			}
+			uc.logger.Error("Failed to persist dock no-show", "error", err, "appointment_id", appointment.ID)
+			continue
+		}
+		if err := uc.publisher.PublishAppointmentNoShow(ctx, appointment); err != nil {
+			uc.logger.Error("Failed to publish dock no-show event", "error", err, "appointment_id", appointment.ID)
+		}
+		marked++
+	}
+
+	if marked > 0 {
+		uc.logger.Warn("Dock appointments marked as no-show", "count", marked)
+	}
+	return marked, nil
+}
+
+// Scorecards calcula a pontualidade por fornecedor nos agendamentos iniciados desde since
+func (uc *DockSchedulingUseCase) Scorecards(ctx context.Context, since time.Time) ([]fulfillment.SupplierScorecard, error) {
+	appointments, err := uc.docks.ListAppointments(ctx, "", since, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dock appointments: %w", err)
+	}
+	return fulfillment.BuildScorecards(appointments), nil
+}
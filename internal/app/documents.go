@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// DocumentUseCase renderiza romaneios, etiquetas de devolução e faturas comerciais das ordens
+// com o modelo do cliente (ou o padrão) e guarda cada documento gerado para reimpressão
+type DocumentUseCase struct {
+	documents fulfillment.DocumentRepository
+	repo      fulfillment.Repository
+	shipments fulfillment.ManifestRepository
+	uoms      fulfillment.UoMRepository
+	logger    Logger
+}
+
+// NewDocumentUseCase cria uma nova instância do caso de uso
+func NewDocumentUseCase(documents fulfillment.DocumentRepository, repo fulfillment.Repository, shipments fulfillment.ManifestRepository, uoms fulfillment.UoMRepository, logger Logger) *DocumentUseCase {
+	return &DocumentUseCase{
+		documents: documents,
+		repo:      repo,
+		shipments: shipments,
+		uoms:      uoms,
+		logger:    logger,
+	}
+}
+
+// DefineTemplate cria ou substitui o modelo do cliente (vazio = padrão da operação) para o tipo e formato
+func (uc *DocumentUseCase) DefineTemplate(ctx context.Context, customer string, docType fulfillment.DocumentType, format fulfillment.DocumentFormat, body string) (*fulfillment.DocumentTemplate, error) {
+	tmpl, err := fulfillment.NewDocumentTemplate(customer, docType, format, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.documents.SaveDocumentTemplate(ctx, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to save document template: %w", err)
+	}
+	uc.logger.Info("Document template defined", "template_id", tmpl.ID, "customer", customer, "type", docType, "format", format)
+	return tmpl, nil
+}
+
+// ListTemplates lista os modelos cadastrados
+func (uc *DocumentUseCase) ListTemplates(ctx context.Context) ([]*fulfillment.DocumentTemplate, error) {
+	templates, err := uc.documents.ListDocumentTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document templates: %w", err)
+	}
+	return templates, nil
+}
+
+// DeleteTemplate remove um modelo; o cliente volta a usar o modelo padrão
+func (uc *DocumentUseCase) DeleteTemplate(ctx context.Context, id string) error {
+	return uc.documents.DeleteDocumentTemplate(ctx, id)
+}
+
+// Render gera o documento da ordem e o guarda para reimpressão
+func (uc *DocumentUseCase) Render(ctx context.Context, orderID string, docType fulfillment.DocumentType, format fulfillment.DocumentFormat) (*fulfillment.RenderedDocument, error) {
+	if !docType.IsValid() || !format.IsValid() {
+		return nil, fmt.Errorf("%w: %s %s", fulfillment.ErrInvalidDocumentTemplate, docType, format)
+	}
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+	tmpl, err := uc.template(ctx, order.Customer, docType, format)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := uc.shipments.GetOutboundShipmentByOrder(ctx, order.ID)
+	if err != nil && !errors.Is(err, fulfillment.ErrOutboundShipmentNotFound) {
+		return nil, fmt.Errorf("failed to get outbound shipment: %w", err)
+	}
+	unitValues := make(map[string]float64, len(order.Items))
+	for _, item := range order.Items {
+		hierarchy, err := uc.uoms.GetUoMHierarchy(ctx, item.SKU)
+		if errors.Is(err, fulfillment.ErrUoMHierarchyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get uom hierarchy: %w", err)
+		}
+		unitValues[item.SKU] = hierarchy.UnitValue
+	}
+
+	document, err := fulfillment.NewRenderedDocument(tmpl, fulfillment.NewDocumentData(order, shipment, unitValues), fulfillment.ActorFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.documents.CreateRenderedDocument(ctx, document); err != nil {
+		return nil, fmt.Errorf("failed to store rendered document: %w", err)
+	}
+
+	uc.logger.Info("Document rendered", "document_id", document.ID, "order_id", order.ID, "type", docType, "format", format, "template_id", tmpl.ID)
+	return document, nil
+}
+
+// template escolhe o modelo do cliente, depois o padrão cadastrado e por fim o embutido
+func (uc *DocumentUseCase) template(ctx context.Context, customer string, docType fulfillment.DocumentType, format fulfillment.DocumentFormat) (*fulfillment.DocumentTemplate, error) {
+	for _, owner := range []string{customer, ""} {
+		tmpl, err := uc.documents.GetDocumentTemplate(ctx, owner, docType, format)
+		if err == nil {
+			return tmpl, nil
+		}
+		if !errors.Is(err, fulfillment.ErrDocumentTemplateNotFound) {
+			return nil, fmt.Errorf("failed to get document template: %w", err)
+		}
+		if customer == "" {
+			break
+		}
+	}
+	return fulfillment.DefaultDocumentTemplate(docType, format)
+}
+
+// GetDocument retorna o documento guardado (reimpressão)
+func (uc *DocumentUseCase) GetDocument(ctx context.Context, id string) (*fulfillment.RenderedDocument, error) {
+	return uc.documents.GetRenderedDocument(ctx, id)
+}
+
+// ListDocuments lista os documentos gerados para a ordem
+func (uc *DocumentUseCase) ListDocuments(ctx context.Context, orderID string) ([]*fulfillment.RenderedDocument, error) {
+	documents, err := uc.documents.ListRenderedDocuments(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rendered documents: %w", err)
+	}
+	return documents, nil
+}
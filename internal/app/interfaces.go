@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
@@ -9,7 +10,11 @@ import (
 // InventoryClient define o contrato para comunicação com mcp-core-inventory
 type InventoryClient interface {
 	AdjustStock(ctx context.Context, location string, sku string, quantity int, batch string) error
+	// ReserveStock reserva os itens da ordem até expiresAt; retorna fulfillment.ErrInsufficientStock sem saldo
+	ReserveStock(ctx context.Context, orderID string, items []fulfillment.Item, expiresAt time.Time) error
+	// ConfirmReservation baixa os itens informados e libera o restante da reserva da ordem
 	ConfirmReservation(ctx context.Context, orderID string, items []fulfillment.Item) error
+	ReleaseReservation(ctx context.Context, orderID string, reason string) error
 	GetAvailableStock(ctx context.Context, location string, sku string) (int, error)
 }
 
@@ -32,3 +37,22 @@ type Logger interface {
 	Error(msg string, args ...interface{})
 	Warn(msg string, args ...interface{})
 }
+
+// EscalationNotifier notifica supervisores sobre operações bloqueadas além do limite
+type EscalationNotifier interface {
+	PublishBlockEscalated(ctx context.Context, entity fulfillment.BlockedEntity) error
+}
+
+// DockEventPublisher publica o ciclo de vida dos agendamentos de doca
+type DockEventPublisher interface {
+	PublishAppointmentScheduled(ctx context.Context, appointment *fulfillment.DockAppointment) error
+	PublishAppointmentArrived(ctx context.Context, appointment *fulfillment.DockAppointment) error
+	PublishAppointmentCompleted(ctx context.Context, appointment *fulfillment.DockAppointment) error
+	PublishAppointmentCancelled(ctx context.Context, appointment *fulfillment.DockAppointment) error
+	PublishAppointmentNoShow(ctx context.Context, appointment *fulfillment.DockAppointment) error
+}
+
+// ManifestEventPublisher publica o fechamento dos manifestos de transportadora
+type ManifestEventPublisher interface {
+	PublishManifestClosed(ctx context.Context, manifest *fulfillment.CarrierManifest, trackingNumbers []string) error
+}
@@ -0,0 +1,440 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// LPNUseCase mantém contêineres aninhados (palete → caixa → itens) e executa recebimento,
+// armazenagem, transferência e expedição do contêiner inteiro em um único comando.
+// O estoque continua sendo movimentado pelos casos de uso existentes; o contêiner registra onde está e o que contém.
+type LPNUseCase struct {
+	lpns             fulfillment.LPNRepository
+	repo             fulfillment.Repository
+	receiveGoods     *ReceiveGoodsUseCase
+	completeTransfer *CompleteTransferUseCase
+	shipOrder        *ShipOrderUseCase
+	logger           Logger
+	unitNormalizer
+}
+
+// LPNInquiry é a consulta completa do contêiner: filhos, totais por SKU e histórico
+type LPNInquiry struct {
+	LPN      *fulfillment.LPN       `json:"lpn"`
+	Children []*fulfillment.LPN     `json:"children"` // Todos os contêineres aninhados, em profundidade
+	Totals   []fulfillment.Item     `json:"totals"`   // Conteúdo do contêiner somado ao dos filhos
+	History  []fulfillment.LPNEvent `json:"history"`
+}
+
+// NewLPNUseCase cria uma nova instância do caso de uso
+func NewLPNUseCase(
+	lpns fulfillment.LPNRepository,
+	repo fulfillment.Repository,
+	receiveGoods *ReceiveGoodsUseCase,
+	completeTransfer *CompleteTransferUseCase,
+	shipOrder *ShipOrderUseCase,
+	logger Logger,
+) *LPNUseCase {
+	return &LPNUseCase{
+		lpns:             lpns,
+		repo:             repo,
+		receiveGoods:     receiveGoods,
+		completeTransfer: completeTransfer,
+		shipOrder:        shipOrder,
+		logger:           logger,
+	}
+}
+
+// Create registra um contêiner; com parentCode ele nasce aninhado e herda localização e status do pai
+func (uc *LPNUseCase) Create(ctx context.Context, code string, lpnType fulfillment.LPNType, location, parentCode string, contents []fulfillment.Item) (*fulfillment.LPN, error) {
+	contents, err := uc.normalize(ctx, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *fulfillment.LPN
+	if parentCode != "" {
+		if parent, err = uc.lpns.GetLPN(ctx, parentCode); err != nil {
+			return nil, err
+		}
+		location = parent.Location
+	}
+
+	lpn, err := fulfillment.NewLPN(code, lpnType, location, contents)
+	if err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		lpn.Status = parent.Status
+		if err := parent.CanNest(lpn); err != nil {
+			return nil, err
+		}
+		lpn.Parent = parent.Code
+	}
+
+	event := uc.event(ctx, lpn.Code, fulfillment.LPNCreated)
+	event.ToLocation = lpn.Location
+	event.Items = lpn.Contents
+	if err := uc.lpns.CreateLPN(ctx, lpn, event); err != nil {
+		if errors.Is(err, fulfillment.ErrLPNExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create lpn: %w", err)
+	}
+
+	uc.logger.Info("LPN created", "code", lpn.Code, "type", lpn.Type, "parent", lpn.Parent, "location", lpn.Location)
+	return lpn, nil
+}
+
+// Pack acrescenta itens soltos ao contêiner
+func (uc *LPNUseCase) Pack(ctx context.Context, code string, items []fulfillment.Item) (*fulfillment.LPN, error) {
+	return uc.changeContents(ctx, code, items, fulfillment.LPNPacked)
+}
+
+// Unpack retira itens soltos do contêiner
+func (uc *LPNUseCase) Unpack(ctx context.Context, code string, items []fulfillment.Item) (*fulfillment.LPN, error) {
+	return uc.changeContents(ctx, code, items, fulfillment.LPNUnpacked)
+}
+
+func (uc *LPNUseCase) changeContents(ctx context.Context, code string, items []fulfillment.Item, action fulfillment.LPNAction) (*fulfillment.LPN, error) {
+	items, err := uc.normalize(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	lpn, err := uc.lpns.GetLPN(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if action == fulfillment.LPNPacked {
+		err = lpn.Pack(items)
+	} else {
+		err = lpn.Unpack(items)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event := uc.event(ctx, lpn.Code, action)
+	event.Items = items
+	if err := uc.lpns.SaveLPNs(ctx, []*fulfillment.LPN{lpn}, []fulfillment.LPNEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+	return lpn, nil
+}
+
+// Nest coloca o contêiner childCode dentro de parentCode (ambos no mesmo lugar)
+func (uc *LPNUseCase) Nest(ctx context.Context, parentCode, childCode string) (*fulfillment.LPN, error) {
+	parent, err := uc.lpns.GetLPN(ctx, parentCode)
+	if err != nil {
+		return nil, err
+	}
+	child, err := uc.lpns.GetLPN(ctx, childCode)
+	if err != nil {
+		return nil, err
+	}
+	if child.Parent != "" {
+		return nil, fmt.Errorf("%w: %s is already inside %s", fulfillment.ErrLPNNesting, child.Code, child.Parent)
+	}
+	if err := parent.CanNest(child); err != nil {
+		return nil, err
+	}
+
+	child.Parent = parent.Code
+	child.UpdatedAt = time.Now()
+	event := uc.event(ctx, child.Code, fulfillment.LPNNested)
+	event.ReferenceType, event.ReferenceID = "lpn", parent.Code
+	if err := uc.lpns.SaveLPNs(ctx, []*fulfillment.LPN{child}, []fulfillment.LPNEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+	return child, nil
+}
+
+// Unnest retira o contêiner do pai; ele permanece no mesmo lugar
+func (uc *LPNUseCase) Unnest(ctx context.Context, code string) (*fulfillment.LPN, error) {
+	lpn, err := uc.lpns.GetLPN(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if lpn.Parent == "" {
+		return nil, fmt.Errorf("%w: %s is not nested", fulfillment.ErrLPNNesting, lpn.Code)
+	}
+
+	event := uc.unnest(ctx, lpn)
+	if err := uc.lpns.SaveLPNs(ctx, []*fulfillment.LPN{lpn}, []fulfillment.LPNEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+	return lpn, nil
+}
+
+// Receive recebe o contêiner anunciado (e tudo que ele contém) na doca com um único InboundShipment.
+// refID vazio usa "LPN-<código>", o que torna o comando idempotente na reentrega.
+func (uc *LPNUseCase) Receive(ctx context.Context, code, refID, origin, dock string) (*fulfillment.LPN, error) {
+	if dock == "" {
+		return nil, fmt.Errorf("%w: dock location is required", fulfillment.ErrInvalidLPN)
+	}
+	root, tree, totals, err := uc.rootTree(ctx, code, fulfillment.LPNExpected)
+	if err != nil {
+		return nil, err
+	}
+	if refID == "" {
+		refID = "LPN-" + root.Code
+	}
+
+	shipment, err := uc.receiveGoods.StartInbound(ctx, refID, origin, dock, totals)
+	if err != nil {
+		return nil, err
+	}
+	// Na repetição após falha ao salvar o LPN o recebimento já foi concluído: só falta o estado do contêiner
+	if shipment.Status != fulfillment.StatusCompleted {
+		if err := uc.receiveGoods.ConfirmReceipt(ctx, shipment.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	events := uc.relocate(ctx, tree, dock, fulfillment.LPNActive, fulfillment.LPNReceived)
+	for idx := range events {
+		events[idx].ReferenceType, events[idx].ReferenceID = fulfillment.EntityInboundShipment, shipment.ID
+	}
+	events[0].Items = totals
+	if err := uc.lpns.SaveLPNs(ctx, tree, events); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+
+	uc.logger.Info("LPN received", "code", root.Code, "shipment_id", shipment.ID, "dock", dock)
+	return root, nil
+}
+
+// Move armazena ou transfere o contêiner inteiro com uma única TransferOrder.
+// Um contêiner aninhado é retirado do pai antes de seguir sozinho.
+func (uc *LPNUseCase) Move(ctx context.Context, code, to string) (*fulfillment.LPN, error) {
+	if to == "" {
+		return nil, fmt.Errorf("%w: destination location is required", fulfillment.ErrInvalidLPN)
+	}
+	lpn, err := uc.lpns.GetLPN(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if lpn.Status != fulfillment.LPNActive {
+		return nil, fmt.Errorf("%w: %s is %s", fulfillment.ErrLPNNotHandleable, lpn.Code, lpn.Status)
+	}
+	if lpn.Location == to {
+		return lpn, nil
+	}
+
+	tree, err := uc.subtree(ctx, lpn)
+	if err != nil {
+		return nil, err
+	}
+	totals := flatten(tree)
+	if len(totals) == 0 {
+		return nil, fmt.Errorf("%w: %s is empty", fulfillment.ErrInvalidLPN, lpn.Code)
+	}
+
+	from := lpn.Location
+	transfer, err := uc.moveTransfer(ctx, lpn, to, totals)
+	if err != nil {
+		return nil, err
+	}
+	// Na repetição após falha ao salvar o LPN a transferência já foi concluída: só falta o estado do contêiner
+	if transfer.Status != fulfillment.StatusCompleted {
+		if err := uc.completeTransfer.CompleteTransfer(ctx, transfer.ID); err != nil {
+			return nil, err
+		}
+	}
+	lpn.PendingTransfer = ""
+
+	var events []fulfillment.LPNEvent
+	if lpn.Parent != "" {
+		events = append(events, uc.unnest(ctx, lpn))
+	}
+	moved := uc.relocate(ctx, tree, to, fulfillment.LPNActive, fulfillment.LPNMoved)
+	for idx := range moved {
+		moved[idx].ReferenceType, moved[idx].ReferenceID = fulfillment.EntityTransferOrder, transfer.ID
+	}
+	moved[0].Items = totals
+	if err := uc.lpns.SaveLPNs(ctx, tree, append(events, moved...)); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+
+	uc.logger.Info("LPN moved", "code", lpn.Code, "from", from, "to", to, "transfer_id", transfer.ID)
+	return lpn, nil
+}
+
+// moveTransfer retorna a transferência pendente do contêiner ou cria uma nova, registrando-a no LPN
+// antes da execução para que a repetição não crie uma segunda transferência a partir da origem antiga
+func (uc *LPNUseCase) moveTransfer(ctx context.Context, lpn *fulfillment.LPN, to string, totals []fulfillment.Item) (*fulfillment.TransferOrder, error) {
+	if lpn.PendingTransfer != "" {
+		transfer, err := uc.repo.GetTransferByID(ctx, lpn.PendingTransfer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lpn transfer: %w", err)
+		}
+		if transfer.Status != fulfillment.StatusCancelled {
+			if transfer.LocationTo != to {
+				return nil, fmt.Errorf("%w: %s has a pending move to %s", fulfillment.ErrLPNNotHandleable, lpn.Code, transfer.LocationTo)
+			}
+			return transfer, nil
+		}
+	}
+
+	transfer, err := uc.completeTransfer.CreateTransfer(ctx, lpn.Location, to, totals)
+	if err != nil {
+		return nil, err
+	}
+	lpn.PendingTransfer = transfer.ID
+	if err := uc.lpns.SaveLPNs(ctx, []*fulfillment.LPN{lpn}, nil); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+	return transfer, nil
+}
+
+// Ship expede o contêiner inteiro contra a ordem; o conteúdo deve bater com os itens da ordem por SKU
+func (uc *LPNUseCase) Ship(ctx context.Context, code, orderID string) (*fulfillment.LPN, error) {
+	root, tree, totals, err := uc.rootTree(ctx, code, fulfillment.LPNActive)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+	if !fulfillment.SameSKUTotals(totals, order.Items) {
+		return nil, fmt.Errorf("%w: %s for order %s", fulfillment.ErrLPNContentsMismatch, root.Code, order.ID)
+	}
+	// A baixa no Core usa a localização das linhas da ordem: ela precisa ser a do contêiner
+	for _, item := range order.Items {
+		if item.Location != "" && item.Location != root.Location {
+			return nil, fmt.Errorf("%w: order line %s is at %s, lpn %s is at %s", fulfillment.ErrLPNContentsMismatch, item.SKU, item.Location, root.Code, root.Location)
+		}
+	}
+
+	// Na repetição após falha ao salvar o LPN a ordem já foi expedida: só falta o estado do contêiner
+	if order.Status != fulfillment.StatusCompleted {
+		if order.Status == fulfillment.StatusPending {
+			if err := uc.shipOrder.StartPicking(ctx, order.ID); err != nil {
+				return nil, err
+			}
+		}
+		if err := uc.shipOrder.Ship(ctx, order.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	events := uc.relocate(ctx, tree, root.Location, fulfillment.LPNShipped, fulfillment.LPNShip)
+	for idx := range events {
+		events[idx].ReferenceType, events[idx].ReferenceID = fulfillment.EntityFulfillmentOrder, order.ID
+		events[idx].ToLocation = order.Destination
+	}
+	events[0].Items = totals
+	if err := uc.lpns.SaveLPNs(ctx, tree, events); err != nil {
+		return nil, fmt.Errorf("failed to save lpn: %w", err)
+	}
+
+	uc.logger.Info("LPN shipped", "code", root.Code, "order_id", order.ID)
+	return root, nil
+}
+
+// Inquiry consulta conteúdo, localização e histórico do contêiner
+func (uc *LPNUseCase) Inquiry(ctx context.Context, code string) (*LPNInquiry, error) {
+	lpn, err := uc.lpns.GetLPN(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := uc.subtree(ctx, lpn)
+	if err != nil {
+		return nil, err
+	}
+	history, err := uc.lpns.ListLPNEvents(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lpn events: %w", err)
+	}
+
+	return &LPNInquiry{
+		LPN:      lpn,
+		Children: append([]*fulfillment.LPN{}, tree[1:]...),
+		Totals:   flatten(tree),
+		History:  append([]fulfillment.LPNEvent{}, history...),
+	}, nil
+}
+
+// rootTree carrega um contêiner raiz (não aninhado) no status esperado, seus filhos e o conteúdo somado
+func (uc *LPNUseCase) rootTree(ctx context.Context, code string, status fulfillment.LPNStatus) (*fulfillment.LPN, []*fulfillment.LPN, []fulfillment.Item, error) {
+	root, err := uc.lpns.GetLPN(ctx, code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if root.Parent != "" {
+		return nil, nil, nil, fmt.Errorf("%w: %s is inside %s", fulfillment.ErrLPNNotHandleable, root.Code, root.Parent)
+	}
+	if root.Status != status {
+		return nil, nil, nil, fmt.Errorf("%w: %s is %s", fulfillment.ErrLPNNotHandleable, root.Code, root.Status)
+	}
+
+	tree, err := uc.subtree(ctx, root)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	totals := flatten(tree)
+	if len(totals) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: %s is empty", fulfillment.ErrInvalidLPN, root.Code)
+	}
+	return root, tree, totals, nil
+}
+
+// subtree retorna o contêiner seguido de todos os descendentes
+func (uc *LPNUseCase) subtree(ctx context.Context, lpn *fulfillment.LPN) ([]*fulfillment.LPN, error) {
+	tree := []*fulfillment.LPN{lpn}
+	for idx := 0; idx < len(tree); idx++ {
+		children, err := uc.lpns.ListLPNChildren(ctx, tree[idx].Code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lpn children: %w", err)
+		}
+		tree = append(tree, children...)
+	}
+	return tree, nil
+}
+
+// relocate aplica localização e status a todos os contêineres da árvore e gera um registro por contêiner
+func (uc *LPNUseCase) relocate(ctx context.Context, tree []*fulfillment.LPN, location string, status fulfillment.LPNStatus, action fulfillment.LPNAction) []fulfillment.LPNEvent {
+	now := time.Now()
+	events := make([]fulfillment.LPNEvent, 0, len(tree))
+	for _, lpn := range tree {
+		event := uc.event(ctx, lpn.Code, action)
+		event.FromLocation, event.ToLocation = lpn.Location, location
+		events = append(events, event)
+
+		lpn.Location, lpn.Status, lpn.UpdatedAt = location, status, now
+	}
+	return events
+}
+
+func (uc *LPNUseCase) unnest(ctx context.Context, lpn *fulfillment.LPN) fulfillment.LPNEvent {
+	event := uc.event(ctx, lpn.Code, fulfillment.LPNUnnested)
+	event.ReferenceType, event.ReferenceID = "lpn", lpn.Parent
+	lpn.Parent = ""
+	lpn.UpdatedAt = time.Now()
+	return event
+}
+
+func (uc *LPNUseCase) event(ctx context.Context, code string, action fulfillment.LPNAction) fulfillment.LPNEvent {
+	return fulfillment.LPNEvent{
+		LPNCode:    code,
+		Action:     action,
+		Actor:      fulfillment.ActorFromContext(ctx),
+		OccurredAt: time.Now(),
+	}
+}
+
+// flatten soma o conteúdo de todos os contêineres da árvore
+func flatten(tree []*fulfillment.LPN) []fulfillment.Item {
+	var totals []fulfillment.Item
+	for _, lpn := range tree {
+		totals = fulfillment.MergeItems(totals, lpn.Contents)
+	}
+	return totals
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// ManifestView é o manifesto com as expedições incluídas
+type ManifestView struct {
+	Manifest  *fulfillment.CarrierManifest    `json:"manifest"`
+	Shipments []*fulfillment.OutboundShipment `json:"shipments"`
+}
+
+// ManifestUseCase registra a entrega das ordens expedidas às transportadoras e fecha o manifesto
+// diário de cada transportadora, gerando os documentos para o motorista
+type ManifestUseCase struct {
+	manifests fulfillment.ManifestRepository
+	repo      fulfillment.Repository
+	publisher ManifestEventPublisher
+	logger    Logger
+}
+
+// NewManifestUseCase cria uma nova instância do caso de uso
+func NewManifestUseCase(manifests fulfillment.ManifestRepository, repo fulfillment.Repository, publisher ManifestEventPublisher, logger Logger) *ManifestUseCase {
+	return &ManifestUseCase{
+		manifests: manifests,
+		repo:      repo,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// HandOver registra os volumes de uma ordem expedida entregues à transportadora e os inclui
+// no manifesto aberto da transportadora no dia
+func (uc *ManifestUseCase) HandOver(ctx context.Context, orderID, carrier, trackingNumber string, pieces int, weight float64) (*fulfillment.OutboundShipment, error) {
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+	if order.Status != fulfillment.StatusCompleted {
+		return nil, fmt.Errorf("%w: order %s is %s", fulfillment.ErrOrderNotShipped, order.ID, order.Status)
+	}
+
+	shipment := fulfillment.NewOutboundShipment(order.ID, trackingNumber, carrier)
+	shipment.Pieces, shipment.Weight = pieces, weight
+	if err := shipment.Validate(); err != nil {
+		return nil, err
+	}
+	if err := shipment.Ship(); err != nil {
+		return nil, err
+	}
+
+	date := fulfillment.ManifestDate(*shipment.ShippedAt)
+	manifest, err := uc.manifests.GetManifestFor(ctx, carrier, date)
+	if errors.Is(err, fulfillment.ErrManifestNotFound) {
+		manifest, err = fulfillment.NewCarrierManifest(carrier, date)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.Add(shipment); err != nil {
+		return nil, err
+	}
+	if err := uc.manifests.AddManifestShipment(ctx, manifest, shipment); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Shipment handed over to carrier", "order_id", order.ID, "carrier", carrier, "manifest_id", manifest.ID, "pieces", pieces)
+	return shipment, nil
+}
+
+// GetManifest retorna o manifesto com suas expedições
+func (uc *ManifestUseCase) GetManifest(ctx context.Context, id string) (*ManifestView, error) {
+	manifest, err := uc.manifests.GetManifest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	shipments, err := uc.manifests.ListManifestShipments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifest shipments: %w", err)
+	}
+	return &ManifestView{Manifest: manifest, Shipments: shipments}, nil
+}
+
+// ListManifests lista os manifestos por transportadora e dia (vazios = todos)
+func (uc *ManifestUseCase) ListManifests(ctx context.Context, carrier string, date *time.Time) ([]*fulfillment.CarrierManifest, error) {
+	manifests, err := uc.manifests.ListManifests(ctx, carrier, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list carrier manifests: %w", err)
+	}
+	return manifests, nil
+}
+
+// Close fecha o manifesto, bloqueando novas inclusões, gera os documentos CSV e HTML e publica o fechamento
+func (uc *ManifestUseCase) Close(ctx context.Context, id string) (*fulfillment.CarrierManifest, error) {
+	actor := fulfillment.ActorFromContext(ctx)
+	manifest, shipments, err := uc.manifests.CloseManifest(ctx, id, func(manifest *fulfillment.CarrierManifest, shipments []*fulfillment.OutboundShipment) (map[fulfillment.ManifestFormat][]byte, error) {
+		if err := manifest.Close(actor); err != nil {
+			return nil, err
+		}
+		csvDocument, err := manifest.CSV(shipments)
+		if err != nil {
+			return nil, err
+		}
+		htmlDocument, err := manifest.HTML(shipments)
+		if err != nil {
+			return nil, err
+		}
+		return map[fulfillment.ManifestFormat][]byte{
+			fulfillment.ManifestCSV:  csvDocument,
+			fulfillment.ManifestHTML: htmlDocument,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trackingNumbers := make([]string, 0, len(shipments))
+	for _, shipment := range shipments {
+		if shipment.TrackingNumber != "" {
+			trackingNumbers = append(trackingNumbers, shipment.TrackingNumber)
+		}
+	}
+	if err := uc.publisher.PublishManifestClosed(ctx, manifest, trackingNumbers); err != nil {
+		uc.logger.Error("Failed to publish manifest closed event", "error", err, "manifest_id", manifest.ID)
+	}
+
+	uc.logger.Info("Carrier manifest closed", "manifest_id", manifest.ID, "carrier", manifest.Carrier, "shipments", manifest.Shipments, "pieces", manifest.Pieces)
+	return manifest, nil
+}
+
+// Document retorna o documento gerado no fechamento do manifesto
+func (uc *ManifestUseCase) Document(ctx context.Context, id string, format fulfillment.ManifestFormat) ([]byte, error) {
+	if format != fulfillment.ManifestCSV && format != fulfillment.ManifestHTML {
+		return nil, fmt.Errorf("%w: %s", fulfillment.ErrUnsupportedManifestFormat, format)
+	}
+	return uc.manifests.GetManifestDocument(ctx, id, format)
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// PickPathUseCase mantém o layout do armazém e sequencia listas de separação pelas coordenadas
+// dos endereços, informando a distância estimada de cada estratégia
+type PickPathUseCase struct {
+	layouts fulfillment.LayoutRepository
+	repo    fulfillment.Repository
+	logger  Logger
+}
+
+// NewPickPathUseCase cria uma nova instância do caso de uso
+func NewPickPathUseCase(layouts fulfillment.LayoutRepository, repo fulfillment.Repository, logger Logger) *PickPathUseCase {
+	return &PickPathUseCase{
+		layouts: layouts,
+		repo:    repo,
+		logger:  logger,
+	}
+}
+
+// DefineLayout substitui o layout do armazém
+func (uc *PickPathUseCase) DefineLayout(ctx context.Context, layout *fulfillment.WarehouseLayout) error {
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+	layout.UpdatedAt = time.Now()
+	if err := uc.layouts.SaveLayout(ctx, layout); err != nil {
+		return fmt.Errorf("failed to save warehouse layout: %w", err)
+	}
+	uc.logger.Info("Warehouse layout defined", "aisles", len(layout.Aisles), "bins", len(layout.Bins))
+	return nil
+}
+
+func (uc *PickPathUseCase) GetLayout(ctx context.Context) (*fulfillment.WarehouseLayout, error) {
+	return uc.layouts.GetLayout(ctx)
+}
+
+// SaveBins inclui ou atualiza coordenadas de endereços no layout existente
+func (uc *PickPathUseCase) SaveBins(ctx context.Context, bins []fulfillment.BinCoordinate) (*fulfillment.WarehouseLayout, error) {
+	layout, err := uc.layouts.GetLayout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := layout.MergeBins(bins); err != nil {
+		return nil, err
+	}
+	if err := uc.layouts.SaveLayout(ctx, layout); err != nil {
+		return nil, fmt.Errorf("failed to save warehouse layout: %w", err)
+	}
+	return layout, nil
+}
+
+// PlanRoute sequencia as linhas das ordens informadas (lista de separação em lote) e as linhas avulsas
+func (uc *PickPathUseCase) PlanRoute(ctx context.Context, orderIDs []string, items []fulfillment.Item) (*fulfillment.PickPlan, error) {
+	layout, err := uc.layouts.GetLayout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := append([]fulfillment.Item(nil), items...)
+	for _, id := range orderIDs {
+		order, err := uc.repo.GetOrderByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fulfillment order: %w", err)
+		}
+		lines = append(lines, order.Items...)
+	}
+
+	plan, err := layout.PlanPickRoute(lines)
+	if err != nil {
+		return nil, err
+	}
+	uc.logger.Info("Pick route planned", "stops", len(plan.Route.Stops), "strategy", plan.Route.Strategy, "distance", plan.Route.Distance)
+	return plan, nil
+}
@@ -0,0 +1,295 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// SyncKindPickScan é o tipo das leituras de separação registradas offline pelos coletores
+const SyncKindPickScan = "PICK_SCAN"
+
+// PickSessionUseCase conduz a separação guiada no coletor: a sessão assume a tarefa PICK da ordem,
+// confere cada leitura de endereço, item e quantidade e, na última linha, conclui a tarefa (expedição)
+type PickSessionUseCase struct {
+	sessions fulfillment.PickSessionRepository
+	repo     fulfillment.Repository
+	tasks    *WarehouseTaskUseCase
+	paths    *PickPathUseCase
+	scan     *ScanUseCase
+	logger   Logger
+}
+
+// NewPickSessionUseCase cria uma nova instância do caso de uso
+func NewPickSessionUseCase(
+	sessions fulfillment.PickSessionRepository,
+	repo fulfillment.Repository,
+	tasks *WarehouseTaskUseCase,
+	paths *PickPathUseCase,
+	scan *ScanUseCase,
+	logger Logger,
+) *PickSessionUseCase {
+	return &PickSessionUseCase{
+		sessions: sessions,
+		repo:     repo,
+		tasks:    tasks,
+		paths:    paths,
+		scan:     scan,
+		logger:   logger,
+	}
+}
+
+// Start abre a sessão do operador do contexto para a ordem, aceitando e iniciando a tarefa PICK
+// (o que inicia o picking da ordem). A sessão em andamento do mesmo operador é retomada.
+func (uc *PickSessionUseCase) Start(ctx context.Context, orderID, device string) (*fulfillment.PickSession, error) {
+	operator := fulfillment.ActorFromContext(ctx)
+	if operator == "" {
+		return nil, fulfillment.ErrTaskAssigneeRequired
+	}
+
+	active, err := uc.sessions.GetActivePickSession(ctx, orderID)
+	if err == nil {
+		if active.Operator != operator {
+			return nil, fmt.Errorf("%w: %s", fulfillment.ErrPickSessionExists, active.Operator)
+		}
+		return active, nil
+	}
+	if !errors.Is(err, fulfillment.ErrPickSessionNotFound) {
+		return nil, fmt.Errorf("failed to get pick session: %w", err)
+	}
+
+	task, err := uc.startTask(ctx, orderID, device)
+	if err != nil {
+		return nil, err
+	}
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+
+	session, err := fulfillment.NewPickSession(order, task.ID, operator, device, uc.route(ctx, orderID))
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.sessions.CreatePickSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Pick session started", "session_id", session.ID, "order_id", orderID, "task_id", task.ID, "operator", operator, "steps", len(session.Steps))
+	return session, nil
+}
+
+// startTask gera (se preciso), aceita e inicia a tarefa PICK da ordem para o operador
+func (uc *PickSessionUseCase) startTask(ctx context.Context, orderID, device string) (*fulfillment.WarehouseTask, error) {
+	task, err := uc.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, orderID, -1)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status == fulfillment.StatusPending {
+		if task, err = uc.tasks.Accept(ctx, task.ID, device); err != nil {
+			return nil, err
+		}
+	}
+	if task.Status == fulfillment.StatusAssigned {
+		return uc.tasks.Start(ctx, task.ID)
+	}
+	if task.AssignedTo != fulfillment.ActorFromContext(ctx) {
+		return nil, fulfillment.ErrTaskAssignedToOther
+	}
+	return task, nil
+}
+
+// route retorna os endereços na sequência da rota planejada; sem layout, a sessão segue a ordem alfabética
+func (uc *PickSessionUseCase) route(ctx context.Context, orderID string) []string {
+	plan, err := uc.paths.PlanRoute(ctx, []string{orderID}, nil)
+	if err != nil {
+		if !errors.Is(err, fulfillment.ErrLayoutNotFound) {
+			uc.logger.Warn("Pick route not planned, using location order", "order_id", orderID, "error", err)
+		}
+		return nil
+	}
+	route := make([]string, 0, len(plan.Route.Stops))
+	for _, stop := range plan.Route.Stops {
+		route = append(route, stop.Location)
+	}
+	return route
+}
+
+// Get retorna a sessão
+func (uc *PickSessionUseCase) Get(ctx context.Context, id string) (*fulfillment.PickSession, error) {
+	return uc.sessions.GetPickSession(ctx, id)
+}
+
+// ScanLocation confere o endereço lido com o do passo atual
+func (uc *PickSessionUseCase) ScanLocation(ctx context.Context, id, location string) (*fulfillment.PickSession, error) {
+	return uc.apply(ctx, id, func(session *fulfillment.PickSession) error {
+		return session.ScanLocation(location)
+	})
+}
+
+// ScanItem confere o item do passo atual, lido como código GS1 (barcode) ou informado por SKU e lote
+func (uc *PickSessionUseCase) ScanItem(ctx context.Context, id, barcode, sku, batch string) (*fulfillment.PickSession, error) {
+	item, err := uc.scannedItem(ctx, barcode, sku, batch)
+	if err != nil {
+		return nil, err
+	}
+	return uc.apply(ctx, id, func(session *fulfillment.PickSession) error {
+		return session.ScanItem(item)
+	})
+}
+
+// scannedItem resolve o código GS1 no SKU e lote da embalagem
+func (uc *PickSessionUseCase) scannedItem(ctx context.Context, barcode, sku, batch string) (fulfillment.Item, error) {
+	if barcode == "" {
+		return fulfillment.Item{SKU: sku, Batch: batch}, nil
+	}
+	result, err := uc.scan.Resolve(ctx, barcode)
+	if err != nil {
+		return fulfillment.Item{}, err
+	}
+	if result.Item == nil {
+		return fulfillment.Item{}, fulfillment.ErrNoProductCode
+	}
+	return *result.Item, nil
+}
+
+// ConfirmQuantity registra a quantidade separada no passo atual (reason obrigatório se menor que a da
+// linha). Na última linha a ordem recebe as quantidades separadas e a tarefa PICK é concluída.
+func (uc *PickSessionUseCase) ConfirmQuantity(ctx context.Context, id string, quantity int, reason string) (*fulfillment.PickSession, error) {
+	return uc.apply(ctx, id, func(session *fulfillment.PickSession) error {
+		return uc.confirm(ctx, session, quantity, reason)
+	})
+}
+
+func (uc *PickSessionUseCase) confirm(ctx context.Context, session *fulfillment.PickSession, quantity int, reason string) error {
+	if err := session.ConfirmQuantity(quantity, reason); err != nil {
+		return err
+	}
+	if !session.Done() {
+		return nil
+	}
+	return uc.finish(ctx, session)
+}
+
+// ApplySyncedScan aplica uma leitura feita offline e sincronizada pelo coletor (SyncKindPickScan).
+// O valor traz session_id, sequence (passo da leitura), stage (location, item ou quantity) e os campos
+// da leitura; a leitura de um passo que não é mais o atual é recusada.
+func (uc *PickSessionUseCase) ApplySyncedScan(ctx context.Context, value map[string]interface{}) error {
+	text := func(field string) string {
+		s, _ := value[field].(string)
+		return s
+	}
+
+	var scan func(session *fulfillment.PickSession) error
+	switch stage := text("stage"); stage {
+	case "location":
+		scan = func(session *fulfillment.PickSession) error { return session.ScanLocation(text("location")) }
+	case "item":
+		item, err := uc.scannedItem(ctx, text("barcode"), text("sku"), text("batch"))
+		if err != nil {
+			return err
+		}
+		scan = func(session *fulfillment.PickSession) error { return session.ScanItem(item) }
+	case "quantity":
+		quantity, ok := value["quantity"].(float64)
+		if !ok {
+			return fmt.Errorf("%w: quantity is required", fulfillment.ErrInvalidPickQuantity)
+		}
+		scan = func(session *fulfillment.PickSession) error {
+			return uc.confirm(ctx, session, int(quantity), text("reason"))
+		}
+	default:
+		return fmt.Errorf("%w: pick scan stage %q", fulfillment.ErrUnsupportedSyncKind, stage)
+	}
+
+	sequence, _ := value["sequence"].(float64)
+	_, err := uc.apply(ctx, text("session_id"), func(session *fulfillment.PickSession) error {
+		if step := session.CurrentStep(); step != nil && sequence > 0 && step.Sequence != int(sequence) {
+			return fmt.Errorf("%w: scan for step %d, current step is %d", fulfillment.ErrPickStepOutOfSequence, int(sequence), step.Sequence)
+		}
+		return scan(session)
+	})
+	return err
+}
+
+// finish conclui a tarefa PICK, que expede a ordem com as quantidades separadas.
+// Sem nenhuma unidade separada, a ordem é bloqueada por falta de estoque e a tarefa volta à fila.
+func (uc *PickSessionUseCase) finish(ctx context.Context, session *fulfillment.PickSession) error {
+	order, err := uc.repo.GetOrderByID(ctx, session.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+	task, err := uc.tasks.Get(ctx, session.TaskID)
+	if err != nil {
+		return err
+	}
+	// Repetição após falha ao salvar a sessão: a tarefa já foi concluída (ou devolvida à fila com a
+	// ordem bloqueada) e só falta persistir a sessão
+	if task.Status == fulfillment.StatusCompleted || (task.Status == fulfillment.StatusPending && order.Blocked != nil) {
+		uc.logger.Info("Pick session already finished", "session_id", session.ID, "task_id", task.ID)
+		return nil
+	}
+
+	short := session.ShortSteps()
+	reasons := make([]string, 0, len(short))
+	for _, step := range short {
+		reasons = append(reasons, fmt.Sprintf("%s@%s %d/%d: %s", step.SKU, step.Location, step.Picked, step.Quantity, step.ShortReason))
+	}
+
+	lines := session.PickedLines(order.Items)
+	if len(lines) == 0 {
+		note := "short pick: " + strings.Join(reasons, "; ")
+		if err := order.Block(fulfillment.BlockMissingStock, note, session.Operator); err != nil {
+			return fmt.Errorf("failed to block order: %w", err)
+		}
+		if err := uc.repo.UpdateOrder(fulfillment.WithReason(ctx, string(fulfillment.BlockMissingStock)+": "+note), order); err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		if _, err := uc.tasks.Abandon(ctx, session.TaskID, note); err != nil {
+			return err
+		}
+		uc.logger.Warn("Pick session finished without picked units", "session_id", session.ID, "order_id", order.ID)
+		return nil
+	}
+
+	var picked []fulfillment.Item
+	if len(short) > 0 {
+		picked = lines
+		uc.logger.Warn("Order short picked", "order_id", order.ID, "session_id", session.ID, "short", strings.Join(reasons, "; "))
+	}
+	if _, err := uc.tasks.CompletePick(ctx, session.TaskID, picked); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Pick session completed", "session_id", session.ID, "order_id", order.ID, "short_lines", len(short))
+	return nil
+}
+
+// apply carrega a sessão do operador do contexto, aplica a leitura e persiste. Leituras rejeitadas
+// não alteram a sessão; se a conclusão da ordem falhar, a última confirmação pode ser repetida.
+func (uc *PickSessionUseCase) apply(ctx context.Context, id string, scan func(session *fulfillment.PickSession) error) (*fulfillment.PickSession, error) {
+	operator := fulfillment.ActorFromContext(ctx)
+	if operator == "" {
+		return nil, fulfillment.ErrTaskAssigneeRequired
+	}
+
+	session, err := uc.sessions.GetPickSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Operator != operator {
+		return nil, fulfillment.ErrTaskAssignedToOther
+	}
+	step := session.Current
+	if err := scan(session); err != nil {
+		uc.logger.Warn("Pick scan rejected", "session_id", id, "step", step+1, "operator", operator, "error", err)
+		return nil, err
+	}
+	if err := uc.sessions.UpdatePickSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update pick session: %w", err)
+	}
+	return session, nil
+}
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// PrioritizationUseCase pontua as ordens abertas pelo risco de perder o prazo de expedição (ship-by e
+// cortes de coleta da transportadora) e pelo nível do cliente. A pontuação define a prioridade das
+// tarefas de separação, a sequência das ondas e o relatório de ordens que perderão o corte.
+type PrioritizationUseCase struct {
+	priorities fulfillment.PriorityRepository
+	tasks      *WarehouseTaskUseCase
+	leadTime   time.Duration
+	batchSize  int
+	logger     Logger
+}
+
+// scoredOrder associa a ordem à sua pontuação
+type scoredOrder struct {
+	order    *fulfillment.FulfillmentOrder
+	priority fulfillment.OrderPriority
+}
+
+// NewPrioritizationUseCase cria uma nova instância do caso de uso. leadTime é o tempo de processamento
+// de uma ordem (separação a expedição) descontado da folga até o corte.
+func NewPrioritizationUseCase(priorities fulfillment.PriorityRepository, tasks *WarehouseTaskUseCase, leadTime time.Duration, logger Logger) *PrioritizationUseCase {
+	return &PrioritizationUseCase{
+		priorities: priorities,
+		tasks:      tasks,
+		leadTime:   leadTime,
+		batchSize:  1000,
+		logger:     logger,
+	}
+}
+
+// SaveCalendar valida e persiste o calendário (substitui o existente da mesma transportadora e serviço)
+func (uc *PrioritizationUseCase) SaveCalendar(ctx context.Context, calendar *fulfillment.CutoffCalendar) error {
+	if err := calendar.Validate(); err != nil {
+		return err
+	}
+	calendar.UpdatedAt = time.Now()
+	if err := uc.priorities.SaveCutoffCalendar(ctx, calendar); err != nil {
+		return fmt.Errorf("failed to save cutoff calendar: %w", err)
+	}
+
+	uc.logger.Info("Cutoff calendar saved", "id", calendar.ID, "carrier", calendar.Carrier, "service", calendar.Service, "cutoffs", len(calendar.Cutoffs))
+	return nil
+}
+
+// ListCalendars lista os calendários da transportadora (vazio = todas)
+func (uc *PrioritizationUseCase) ListCalendars(ctx context.Context, carrier string) ([]*fulfillment.CutoffCalendar, error) {
+	calendars, err := uc.priorities.ListCutoffCalendars(ctx, carrier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cutoff calendars: %w", err)
+	}
+	return calendars, nil
+}
+
+// DeleteCalendar remove o calendário
+func (uc *PrioritizationUseCase) DeleteCalendar(ctx context.Context, id string) error {
+	return uc.priorities.DeleteCutoffCalendar(ctx, id)
+}
+
+// TaskPriority retorna a prioridade da tarefa de separação da ordem. Sem os calendários, a ordem é
+// pontuada apenas pelo ship-by.
+func (uc *PrioritizationUseCase) TaskPriority(ctx context.Context, order *fulfillment.FulfillmentOrder) int {
+	var calendars []*fulfillment.CutoffCalendar
+	if order.Carrier != "" {
+		var err error
+		if calendars, err = uc.priorities.ListCutoffCalendars(ctx, order.Carrier); err != nil {
+			uc.logger.Warn("Cutoff calendars unavailable, scoring by ship-by only", "order_id", order.ID, "error", err)
+		}
+	}
+	calendar := fulfillment.SelectCutoffCalendar(calendars, order.Carrier, order.Service)
+	return fulfillment.ScoreOrder(order, calendar, uc.leadTime, time.Now()).TaskPriority()
+}
+
+// Rank pontua as ordens abertas em now, da mais urgente para a menos urgente
+func (uc *PrioritizationUseCase) Rank(ctx context.Context, now time.Time) ([]fulfillment.OrderPriority, error) {
+	scored, err := uc.score(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	priorities := make([]fulfillment.OrderPriority, 0, len(scored))
+	for _, s := range scored {
+		priorities = append(priorities, s.priority)
+	}
+	return priorities, nil
+}
+
+// score carrega as ordens abertas e os calendários e ordena as ordens pela pontuação
+func (uc *PrioritizationUseCase) score(ctx context.Context, now time.Time) ([]scoredOrder, error) {
+	orders, err := uc.openOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	calendars, err := uc.ListCalendars(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byOrder := make(map[string]*fulfillment.FulfillmentOrder, len(orders))
+	priorities := make([]fulfillment.OrderPriority, 0, len(orders))
+	for _, order := range orders {
+		calendar := fulfillment.SelectCutoffCalendar(calendars, order.Carrier, order.Service)
+		byOrder[order.ID] = order
+		priorities = append(priorities, fulfillment.ScoreOrder(order, calendar, uc.leadTime, now))
+	}
+	fulfillment.SortOrderPriorities(priorities)
+
+	scored := make([]scoredOrder, 0, len(priorities))
+	for _, priority := range priorities {
+		scored = append(scored, scoredOrder{order: byOrder[priority.FulfillmentOrderID], priority: priority})
+	}
+	return scored, nil
+}
+
+// openOrders carrega todas as ordens abertas em páginas de batchSize
+func (uc *PrioritizationUseCase) openOrders(ctx context.Context) ([]*fulfillment.FulfillmentOrder, error) {
+	var orders []*fulfillment.FulfillmentOrder
+	afterID := ""
+	for {
+		page, err := uc.priorities.ListOpenOrders(ctx, afterID, uc.batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open orders: %w", err)
+		}
+		orders = append(orders, page...)
+		if len(page) < uc.batchSize {
+			return orders, nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
+
+// PlanWave libera para separação até size ordens pendentes, na sequência da pontuação, gerando (ou
+// repriorizando) a tarefa PICK de cada uma. Ordens em backorder ou com a tarefa já em execução ficam de fora.
+func (uc *PrioritizationUseCase) PlanWave(ctx context.Context, size int, now time.Time) (*fulfillment.Wave, error) {
+	if size <= 0 {
+		return nil, fulfillment.ErrInvalidWaveSize
+	}
+	scored, err := uc.score(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	wave := &fulfillment.Wave{PlannedAt: now, Orders: []fulfillment.WaveOrder{}}
+	for _, s := range scored {
+		if len(wave.Orders) == size {
+			break
+		}
+		if s.order.Status != fulfillment.StatusPending || s.order.ReservationStatus == fulfillment.ReservationBackordered {
+			continue
+		}
+
+		priority := s.priority.TaskPriority()
+		task, err := uc.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, s.order.ID, priority)
+		if err != nil {
+			uc.logger.Error("Failed to release order to wave", "order_id", s.order.ID, "error", err)
+			continue
+		}
+		if task.Status != fulfillment.StatusPending {
+			continue
+		}
+		if err := uc.tasks.Reprioritize(ctx, fulfillment.EntityFulfillmentOrder, s.order.ID, priority); err != nil {
+			return nil, err
+		}
+		wave.Orders = append(wave.Orders, fulfillment.WaveOrder{OrderPriority: s.priority, TaskID: task.ID, TaskPriority: priority})
+	}
+
+	uc.logger.Info("Wave planned", "orders", len(wave.Orders), "size", size)
+	return wave, nil
+}
+
+// Refresh atualiza a prioridade das tarefas de separação pendentes com a pontuação em now e
+// alerta sobre as ordens que perderão o prazo, retornando-as
+func (uc *PrioritizationUseCase) Refresh(ctx context.Context, now time.Time) ([]fulfillment.OrderPriority, error) {
+	scored, err := uc.score(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range scored {
+		if s.order.Status != fulfillment.StatusPending {
+			continue
+		}
+		if err := uc.tasks.Reprioritize(ctx, fulfillment.EntityFulfillmentOrder, s.order.ID, s.priority.TaskPriority()); err != nil {
+			uc.logger.Error("Failed to reprioritize pick task", "order_id", s.order.ID, "error", err)
+		}
+	}
+
+	missing := willMiss(scored)
+	if len(missing) > 0 {
+		ids := make([]string, 0, len(missing))
+		for _, p := range missing {
+			ids = append(ids, p.OrderID)
+		}
+		uc.logger.Warn("Orders will miss the carrier cutoff", "count", len(missing), "orders", strings.Join(ids, ","))
+	}
+	return missing, nil
+}
+
+// WillMissReport lista as ordens abertas que não cumprem mais o prazo prometido, pelo prazo mais próximo
+func (uc *PrioritizationUseCase) WillMissReport(ctx context.Context, now time.Time) ([]fulfillment.OrderPriority, error) {
+	scored, err := uc.score(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	return willMiss(scored), nil
+}
+
+func willMiss(scored []scoredOrder) []fulfillment.OrderPriority {
+	missing := []fulfillment.OrderPriority{}
+	for _, s := range scored {
+		if s.priority.WillMiss() {
+			missing = append(missing, s.priority)
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool { return missing[i].Deadline.Before(*missing[j].Deadline) })
+	return missing
+}
+
+// Run atualiza as prioridades periodicamente até o contexto ser cancelado
+func (uc *PrioritizationUseCase) Run(ctx context.Context, interval time.Duration) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "prioritization")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Prioritization stopped")
+			return
+		case <-ticker.C:
+			if _, err := uc.Refresh(ctx, time.Now()); err != nil {
+				uc.logger.Error("Priority refresh failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// ErrHistoryUnavailable indica que o serviço não está rodando em modo event-sourced
+var ErrHistoryUnavailable = errors.New("aggregate history requires event sourcing")
+
+// QueryHistoryUseCase consulta o estado de agregados em um instante passado
+type QueryHistoryUseCase struct {
+	history fulfillment.HistoryRepository
+	logger  Logger
+}
+
+// NewQueryHistoryUseCase cria uma nova instância do caso de uso.
+// history pode ser nil quando o modo event-sourced está desabilitado.
+func NewQueryHistoryUseCase(history fulfillment.HistoryRepository, logger Logger) *QueryHistoryUseCase {
+	return &QueryHistoryUseCase{
+		history: history,
+		logger:  logger,
+	}
+}
+
+// GetOrderAt retorna a FulfillmentOrder como estava em "at"
+func (uc *QueryHistoryUseCase) GetOrderAt(ctx context.Context, id string, at time.Time) (*fulfillment.FulfillmentOrder, error) {
+	if uc.history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+	order, err := uc.history.GetOrderAt(ctx, id, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild fulfillment order: %w", err)
+	}
+	return order, nil
+}
+
+// GetTransferAt retorna a TransferOrder como estava em "at"
+func (uc *QueryHistoryUseCase) GetTransferAt(ctx context.Context, id string, at time.Time) (*fulfillment.TransferOrder, error) {
+	if uc.history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+	transfer, err := uc.history.GetTransferAt(ctx, id, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild transfer order: %w", err)
+	}
+	return transfer, nil
+}
+
+// GetReturnAt retorna a ReturnOrder como estava em "at"
+func (uc *QueryHistoryUseCase) GetReturnAt(ctx context.Context, id string, at time.Time) (*fulfillment.ReturnOrder, error) {
+	if uc.history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+	returnOrder, err := uc.history.GetReturnAt(ctx, id, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild return order: %w", err)
+	}
+	return returnOrder, nil
+}
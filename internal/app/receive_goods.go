@@ -7,12 +7,17 @@ import (
 	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
 
+// InboundHook é executado após o recebimento confirmado (ex: cross-dock)
+type InboundHook func(ctx context.Context, shipment *fulfillment.InboundShipment)
+
 // ReceiveGoodsUseCase orquestra o recebimento físico de mercadorias
 type ReceiveGoodsUseCase struct {
 	repo            fulfillment.Repository
 	inventoryClient InventoryClient
 	eventPublisher  EventPublisher
+	afterReceipt    []InboundHook
 	logger          Logger
+	unitNormalizer
 }
 
 // NewReceiveGoodsUseCase cria uma nova instância do caso de uso
@@ -25,8 +30,18 @@ func NewReceiveGoodsUseCase(repo fulfillment.Repository, inventoryClient Invento
 	}
 }
 
+// AfterReceipt registra um hook executado após cada recebimento confirmado
+func (uc *ReceiveGoodsUseCase) AfterReceipt(hook InboundHook) {
+	uc.afterReceipt = append(uc.afterReceipt, hook)
+}
+
 // StartInbound cria um novo InboundShipment (previsto)
 func (uc *ReceiveGoodsUseCase) StartInbound(ctx context.Context, refID, origin, dest string, items []fulfillment.Item) (*fulfillment.InboundShipment, error) {
+	items, err := uc.normalize(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	shipment, err := fulfillment.NewInboundShipment(refID, origin, dest, items)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create inbound shipment: %w", err)
@@ -63,12 +78,14 @@ func (uc *ReceiveGoodsUseCase) ConfirmReceipt(ctx context.Context, shipmentID st
 		return fmt.Errorf("failed to update inbound status: %w", err)
 	}
 
-	// Chama mcp-core-inventory para entrada de estoque
-	for _, item := range shipment.Items {
-		if err := uc.inventoryClient.AdjustStock(ctx, shipment.Destination, item.SKU, item.Quantity, item.Batch); err != nil {
+	// Chama mcp-core-inventory para entrada de estoque (retomando do item que falhou no retry)
+	for idx := shipment.Failure.ResumeFrom(fulfillment.StepAdjustStock); idx < len(shipment.Items); idx++ {
+		item := shipment.Items[idx]
+		cmdCtx := fulfillment.WithCommandID(ctx, fulfillment.CommandID(shipment.ID, fulfillment.StepAdjustStock, idx))
+		if err := uc.inventoryClient.AdjustStock(cmdCtx, shipment.Destination, item.SKU, item.Quantity, item.Batch); err != nil {
 			uc.logger.Error("Failed to adjust stock in core inventory", "error", err, "sku", item.SKU)
 			// Marca como failed
-			shipment.Status = fulfillment.StatusFailed
+			shipment.Fail(fulfillment.StepAdjustStock, idx, err)
 			uc.repo.UpdateInbound(ctx, shipment)
 			return fmt.Errorf("failed to adjust stock for SKU %s: %w", item.SKU, err)
 		}
@@ -83,6 +100,10 @@ func (uc *ReceiveGoodsUseCase) ConfirmReceipt(ctx context.Context, shipmentID st
 		return fmt.Errorf("failed to update inbound status: %w", err)
 	}
 
+	for _, hook := range uc.afterReceipt {
+		hook(ctx, shipment)
+	}
+
 	// Publica evento
 	if err := uc.eventPublisher.PublishInboundReceived(ctx, shipment); err != nil {
 		uc.logger.Error("Failed to publish inbound received event", "error", err)
@@ -13,6 +13,7 @@ type RegisterReturnUseCase struct {
 	inventoryClient InventoryClient
 	eventPublisher  EventPublisher
 	logger          Logger
+	unitNormalizer
 }
 
 // NewRegisterReturnUseCase cria uma nova instância do caso de uso
@@ -27,6 +28,11 @@ func NewRegisterReturnUseCase(repo fulfillment.Repository, inventoryClient Inven
 
 // RegisterReturn registra uma devolução física
 func (uc *RegisterReturnUseCase) RegisterReturn(ctx context.Context, originalOrderID, reason, location string, items []fulfillment.Item) (*fulfillment.ReturnOrder, error) {
+	items, err := uc.normalize(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	returnOrder, err := fulfillment.NewReturnOrder(originalOrderID, reason, items)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create return order: %w", err)
@@ -56,6 +62,7 @@ func (uc *RegisterReturnUseCase) CompleteReturn(ctx context.Context, returnID, l
 	if err := returnOrder.StartProcessing(); err != nil {
 		return fmt.Errorf("invalid state transition: %w", err)
 	}
+	returnOrder.Location = location
 
 	if err := uc.repo.UpdateReturn(ctx, returnOrder); err != nil {
 		return fmt.Errorf("failed to update return status: %w", err)
@@ -63,10 +70,12 @@ func (uc *RegisterReturnUseCase) CompleteReturn(ctx context.Context, returnID, l
 
 	// Aplica lógica de reaproveitamento e chama Core para entrada/ajuste
 	// Por padrão, devoluções voltam para estoque vendável
-	for _, item := range returnOrder.Items {
-		if err := uc.inventoryClient.AdjustStock(ctx, location, item.SKU, item.Quantity, item.Batch); err != nil {
+	for idx := returnOrder.Failure.ResumeFrom(fulfillment.StepAdjustStock); idx < len(returnOrder.Items); idx++ {
+		item := returnOrder.Items[idx]
+		cmdCtx := fulfillment.WithCommandID(ctx, fulfillment.CommandID(returnOrder.ID, fulfillment.StepAdjustStock, idx))
+		if err := uc.inventoryClient.AdjustStock(cmdCtx, location, item.SKU, item.Quantity, item.Batch); err != nil {
 			uc.logger.Error("Failed to adjust stock in core inventory", "error", err, "sku", item.SKU)
-			returnOrder.Status = fulfillment.StatusFailed
+			returnOrder.Fail(fulfillment.StepAdjustStock, idx, err)
 			uc.repo.UpdateReturn(ctx, returnOrder)
 			return fmt.Errorf("failed to adjust stock for SKU %s: %w", item.SKU, err)
 		}
@@ -0,0 +1,346 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// ReplenishmentMove é uma movimentação de reabastecimento gerada pela avaliação
+type ReplenishmentMove struct {
+	RuleID     string `json:"rule_id"`
+	SKU        string `json:"sku"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Quantity   int    `json:"quantity"`
+	Priority   int    `json:"priority"`
+	TransferID string `json:"transfer_id"`
+	TaskID     string `json:"task_id,omitempty"`
+}
+
+// ReplenishmentReport resume uma avaliação das regras de reabastecimento
+type ReplenishmentReport struct {
+	Evaluated int                 `json:"evaluated"`
+	Moves     []ReplenishmentMove `json:"moves"`
+}
+
+// ReplenishmentUseCase avalia as regras de reabastecimento dos endereços de picking e gera
+// transferências internas (pulmão -> picking) priorizadas pela demanda liberada
+type ReplenishmentUseCase struct {
+	rules            fulfillment.ReplenishmentRuleRepository
+	repo             fulfillment.Repository
+	inventoryClient  InventoryClient
+	completeTransfer *CompleteTransferUseCase
+	tasks            *WarehouseTaskUseCase
+	batchSize        int
+	logger           Logger
+}
+
+// NewReplenishmentUseCase cria uma nova instância do caso de uso
+func NewReplenishmentUseCase(
+	rules fulfillment.ReplenishmentRuleRepository,
+	repo fulfillment.Repository,
+	inventoryClient InventoryClient,
+	completeTransfer *CompleteTransferUseCase,
+	tasks *WarehouseTaskUseCase,
+	logger Logger,
+) *ReplenishmentUseCase {
+	return &ReplenishmentUseCase{
+		rules:            rules,
+		repo:             repo,
+		inventoryClient:  inventoryClient,
+		completeTransfer: completeTransfer,
+		tasks:            tasks,
+		batchSize:        500,
+		logger:           logger,
+	}
+}
+
+// SaveRule valida e persiste a regra (substitui a regra existente do mesmo SKU e endereço)
+func (uc *ReplenishmentUseCase) SaveRule(ctx context.Context, rule *fulfillment.ReplenishmentRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	rule.UpdatedAt = time.Now()
+	if err := uc.rules.SaveReplenishmentRule(ctx, rule); err != nil {
+		return fmt.Errorf("failed to save replenishment rule: %w", err)
+	}
+	return nil
+}
+
+// DefineRule cria ou substitui a regra do SKU no endereço, preservando a movimentação em andamento
+func (uc *ReplenishmentUseCase) DefineRule(ctx context.Context, rule *fulfillment.ReplenishmentRule) error {
+	existing, err := uc.ListRules(ctx, rule.PickLocation)
+	if err != nil {
+		return err
+	}
+	for _, current := range existing {
+		if current.SKU == rule.SKU {
+			rule.ID, rule.CreatedAt, rule.OpenTransferID = current.ID, current.CreatedAt, current.OpenTransferID
+		}
+	}
+	return uc.SaveRule(ctx, rule)
+}
+
+// ListRules lista as regras do endereço de picking (vazio = todas)
+func (uc *ReplenishmentUseCase) ListRules(ctx context.Context, pickLocation string) ([]*fulfillment.ReplenishmentRule, error) {
+	rules, err := uc.rules.ListReplenishmentRules(ctx, pickLocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replenishment rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteRule remove a regra
+func (uc *ReplenishmentUseCase) DeleteRule(ctx context.Context, id string) error {
+	if err := uc.rules.DeleteReplenishmentRule(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete replenishment rule: %w", err)
+	}
+	return nil
+}
+
+// Run avalia todas as regras periodicamente até o contexto ser cancelado
+func (uc *ReplenishmentUseCase) Run(ctx context.Context, interval time.Duration) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "replenishment-evaluator")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("Replenishment evaluator stopped")
+			return
+		case <-ticker.C:
+			if _, err := uc.EvaluateAll(ctx); err != nil {
+				uc.logger.Error("Replenishment evaluation failed", "error", err)
+			}
+		}
+	}
+}
+
+// EvaluateAll avalia todas as regras habilitadas
+func (uc *ReplenishmentUseCase) EvaluateAll(ctx context.Context) (*ReplenishmentReport, error) {
+	rules, err := uc.ListRules(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return uc.evaluate(ctx, rules)
+}
+
+// EvaluateItems avalia as regras dos endereços de picking dos itens (gatilho na conclusão da separação)
+func (uc *ReplenishmentUseCase) EvaluateItems(ctx context.Context, items []fulfillment.Item) (*ReplenishmentReport, error) {
+	skusByLocation := make(map[string]map[string]bool)
+	for _, item := range items {
+		if item.Location == "" {
+			continue
+		}
+		if skusByLocation[item.Location] == nil {
+			skusByLocation[item.Location] = make(map[string]bool)
+		}
+		skusByLocation[item.Location][item.SKU] = true
+	}
+
+	var rules []*fulfillment.ReplenishmentRule
+	for location, skus := range skusByLocation {
+		locationRules, err := uc.ListRules(ctx, location)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range locationRules {
+			if skus[rule.SKU] {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return uc.evaluate(ctx, rules)
+}
+
+// OnTaskCompleted é o TaskHook que reavalia os endereços de picking de uma separação concluída
+func (uc *ReplenishmentUseCase) OnTaskCompleted(ctx context.Context, task *fulfillment.WarehouseTask) {
+	if task.Type != fulfillment.TaskPick {
+		return
+	}
+	order, err := uc.repo.GetOrderByID(ctx, task.EntityID)
+	if err != nil {
+		uc.logger.Error("Failed to load picked order for replenishment", "error", err, "task_id", task.ID)
+		return
+	}
+	if _, err := uc.EvaluateItems(ctx, order.Items); err != nil {
+		uc.logger.Error("Replenishment evaluation after pick failed", "error", err, "task_id", task.ID)
+	}
+}
+
+func (uc *ReplenishmentUseCase) evaluate(ctx context.Context, rules []*fulfillment.ReplenishmentRule) (*ReplenishmentReport, error) {
+	report := &ReplenishmentReport{Moves: []ReplenishmentMove{}}
+	if len(rules) == 0 {
+		return report, nil
+	}
+
+	demand, err := uc.releasedDemand(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		report.Evaluated++
+		move, err := uc.evaluateRule(ctx, rule, demand[stockKey(rule.PickLocation, rule.SKU)])
+		if err != nil {
+			uc.logger.Error("Failed to evaluate replenishment rule", "error", err, "rule_id", rule.ID, "sku", rule.SKU)
+			continue
+		}
+		if move != nil {
+			report.Moves = append(report.Moves, *move)
+		}
+	}
+
+	if len(report.Moves) > 0 {
+		uc.logger.Info("Replenishment moves generated", "count", len(report.Moves))
+	}
+	return report, nil
+}
+
+// evaluateRule gera a movimentação da regra, se necessária. Com uma movimentação já em andamento,
+// apenas reprioriza a tarefa dela frente à demanda atual.
+func (uc *ReplenishmentUseCase) evaluateRule(ctx context.Context, rule *fulfillment.ReplenishmentRule, released pickFaceDemand) (*ReplenishmentMove, error) {
+	available, err := uc.inventoryClient.GetAvailableStock(ctx, rule.PickLocation, rule.SKU)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pick face stock: %w", err)
+	}
+	// Saldo físico no endereço = disponível + reservado; demanda inclui o que não foi possível reservar
+	onHand := available + released.reserved
+	demand := released.reserved + released.backordered
+
+	finished := rule.OpenTransferID
+	inTransit, open, err := uc.openTransfer(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, uc.tasks.Reprioritize(ctx, fulfillment.EntityTransferOrder, rule.OpenTransferID,
+			fulfillment.ReplenishmentPriority(onHand, demand))
+	}
+	if finished != "" {
+		// Libera a regra da movimentação já concluída
+		if err := uc.rules.UpdateReplenishmentTransfer(ctx, rule.ID, finished, ""); err != nil {
+			return nil, uc.claimError(err)
+		}
+	}
+
+	quantity, priority := rule.Evaluate(onHand, inTransit, demand)
+	if quantity == 0 {
+		return nil, nil
+	}
+
+	items := []fulfillment.Item{{SKU: rule.SKU, Quantity: quantity}}
+	transfer, err := uc.completeTransfer.CreateTransfer(ctx, rule.ReserveLocation, rule.PickLocation, items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replenishment transfer: %w", err)
+	}
+	// A regra só aceita a movimentação se continuar livre: o agendador e o gatilho da separação podem
+	// avaliar a mesma regra ao mesmo tempo
+	if err := uc.rules.UpdateReplenishmentTransfer(ctx, rule.ID, "", transfer.ID); err != nil {
+		uc.cancelTransfer(ctx, transfer)
+		return nil, uc.claimError(err)
+	}
+	rule.OpenTransferID = transfer.ID
+
+	move := &ReplenishmentMove{
+		RuleID: rule.ID, SKU: rule.SKU, From: rule.ReserveLocation, To: rule.PickLocation,
+		Quantity: quantity, Priority: priority, TransferID: transfer.ID,
+	}
+	task, err := uc.tasks.Enqueue(ctx, fulfillment.EntityTransferOrder, transfer.ID, priority)
+	if err != nil {
+		uc.logger.Error("Failed to enqueue replenishment task", "error", err, "transfer_id", transfer.ID)
+	} else {
+		move.TaskID = task.ID
+	}
+
+	uc.logger.Info("Replenishment move created", "sku", rule.SKU, "to", rule.PickLocation, "quantity", quantity, "priority", priority)
+	return move, nil
+}
+
+// claimError ignora a regra reivindicada por outra avaliação (ela já gerou ou liberou a movimentação)
+func (uc *ReplenishmentUseCase) claimError(err error) error {
+	if errors.Is(err, fulfillment.ErrReplenishmentMoveChanged) {
+		return nil
+	}
+	return fmt.Errorf("failed to update replenishment rule: %w", err)
+}
+
+// cancelTransfer cancela a transferência que a regra não aceitou
+func (uc *ReplenishmentUseCase) cancelTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) {
+	if err := transfer.Cancel(); err != nil {
+		uc.logger.Error("Failed to cancel unclaimed replenishment transfer", "error", err, "transfer_id", transfer.ID)
+		return
+	}
+	if err := uc.repo.UpdateTransfer(ctx, transfer); err != nil {
+		uc.logger.Error("Failed to cancel unclaimed replenishment transfer", "error", err, "transfer_id", transfer.ID)
+	}
+}
+
+// openTransfer retorna a quantidade em trânsito da movimentação aberta da regra, liberando-a se já terminou
+func (uc *ReplenishmentUseCase) openTransfer(ctx context.Context, rule *fulfillment.ReplenishmentRule) (int, bool, error) {
+	if rule.OpenTransferID == "" {
+		return 0, false, nil
+	}
+
+	transfer, err := uc.repo.GetTransferByID(ctx, rule.OpenTransferID)
+	if err != nil && !errors.Is(err, fulfillment.ErrTransferNotFound) {
+		return 0, false, fmt.Errorf("failed to get replenishment transfer: %w", err)
+	}
+	if err == nil && transfer.Status != fulfillment.StatusCompleted && transfer.Status != fulfillment.StatusCancelled {
+		inTransit := 0
+		for _, item := range transfer.Items {
+			if item.SKU == rule.SKU {
+				inTransit += item.Quantity
+			}
+		}
+		return inTransit, true, nil
+	}
+
+	rule.OpenTransferID = ""
+	return 0, false, nil
+}
+
+// pickFaceDemand é a demanda liberada de um SKU em um endereço de picking
+type pickFaceDemand struct {
+	reserved    int // Já descontada do saldo disponível no Core Inventory
+	backordered int // Sem saldo para reservar: separação ficaria incompleta
+}
+
+// releasedDemand soma, por endereço e SKU, os itens das ordens ainda não expedidas (reservadas ou em backorder)
+func (uc *ReplenishmentUseCase) releasedDemand(ctx context.Context) (map[string]pickFaceDemand, error) {
+	demand := make(map[string]pickFaceDemand)
+	for _, status := range []fulfillment.ReservationStatus{fulfillment.ReservationReserved, fulfillment.ReservationBackordered} {
+		orders, err := ordersByReservationStatus(ctx, uc.repo, status, uc.batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list released orders: %w", err)
+		}
+		for _, order := range orders {
+			if !order.Status.IsWorkable() {
+				continue
+			}
+			for _, item := range order.Items {
+				key := stockKey(item.Location, item.SKU)
+				d := demand[key]
+				if status == fulfillment.ReservationReserved {
+					d.reserved += item.Quantity
+				} else {
+					d.backordered += item.Quantity
+				}
+				demand[key] = d
+			}
+		}
+	}
+	return demand, nil
+}
+
+func stockKey(location, sku string) string {
+	return location + "|" + sku
+}
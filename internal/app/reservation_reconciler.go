@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// ReservationReconciler localiza e libera reservas órfãs: reservas expiradas ou
+// mantidas por ordens que já estão CANCELLED/FAILED
+type ReservationReconciler struct {
+	repo            fulfillment.Repository
+	inventoryClient InventoryClient
+	batchSize       int
+	logger          Logger
+}
+
+// NewReservationReconciler cria uma nova instância do reconciliador
+func NewReservationReconciler(repo fulfillment.Repository, inventoryClient InventoryClient, logger Logger) *ReservationReconciler {
+	return &ReservationReconciler{
+		repo:            repo,
+		inventoryClient: inventoryClient,
+		batchSize:       500,
+		logger:          logger,
+	}
+}
+
+// Run executa a reconciliação periodicamente até o contexto ser cancelado
+func (r *ReservationReconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Reservation reconciler stopped")
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil {
+				r.logger.Error("Reservation reconciliation failed", "error", err)
+			}
+		}
+	}
+}
+
+// Reconcile libera as reservas órfãs e retorna quantas foram liberadas
+func (r *ReservationReconciler) Reconcile(ctx context.Context) (int, error) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "reservation-reconciler")
+	now := time.Now()
+	orders, err := r.repo.ListOrphanedReservations(ctx, now, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list orphaned reservations: %w", err)
+	}
+
+	released := 0
+	for _, order := range orders {
+		status, reason, orphan := classifyReservation(order, now)
+		if !orphan {
+			continue
+		}
+
+		if err := r.inventoryClient.ReleaseReservation(ctx, order.OrderID, reason); err != nil {
+			r.logger.Error("Failed to release orphaned reservation", "error", err, "order_id", order.OrderID)
+			continue
+		}
+
+		order.MarkReservationReleased(status)
+		if err := r.repo.UpdateOrder(ctx, order); err != nil {
+			r.logger.Error("Failed to persist released reservation", "error", err, "order_id", order.OrderID)
+			continue
+		}
+		released++
+	}
+
+	if released > 0 {
+		r.logger.Info("Orphaned reservations released", "count", released)
+	}
+	return released, nil
+}
+
+// classifyReservation decide se a reserva de uma ordem é órfã e como deve ser encerrada
+func classifyReservation(order *fulfillment.FulfillmentOrder, now time.Time) (fulfillment.ReservationStatus, string, bool) {
+	switch {
+	case order.Status == fulfillment.StatusCancelled:
+		return fulfillment.ReservationReleased, "reconcile_order_cancelled", true
+	case order.Status == fulfillment.StatusFailed:
+		return fulfillment.ReservationReleased, "reconcile_order_failed", true
+	case order.Status == fulfillment.StatusPending && order.ReservationExpiredAt(now):
+		// Ordens em separação mantêm a reserva até a expedição
+		return fulfillment.ReservationExpired, "reservation_expired", true
+	default:
+		return "", "", false
+	}
+}
+
+// ordersByReservationStatus carrega todas as ordens no status de reserva em páginas de batchSize
+func ordersByReservationStatus(ctx context.Context, repo fulfillment.Repository, status fulfillment.ReservationStatus, batchSize int) ([]*fulfillment.FulfillmentOrder, error) {
+	var orders []*fulfillment.FulfillmentOrder
+	afterID := ""
+	for {
+		page, err := repo.ListOrdersByReservationStatus(ctx, status, afterID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page...)
+		if len(page) < batchSize {
+			return orders, nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
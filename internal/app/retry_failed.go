@@ -0,0 +1,242 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// RetryResult é o resultado da reexecução de uma operação
+type RetryResult struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Succeeded  bool   `json:"succeeded"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RetryReport resume uma reexecução em lote
+type RetryReport struct {
+	Attempted int           `json:"attempted"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []RetryResult `json:"results"`
+}
+
+func (r *RetryReport) add(op fulfillment.FailedOperation, err error) {
+	result := RetryResult{EntityType: op.EntityType, EntityID: op.EntityID, Succeeded: err == nil}
+	r.Attempted++
+	if err != nil {
+		result.Error = err.Error()
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// RetryFailedUseCase devolve operações FAILED para PENDING e reexecuta o caso de uso
+// a partir da etapa registrada na falha
+type RetryFailedUseCase struct {
+	failures         fulfillment.FailureRepository
+	repo             fulfillment.Repository
+	receiveGoods     *ReceiveGoodsUseCase
+	shipOrder        *ShipOrderUseCase
+	registerReturn   *RegisterReturnUseCase
+	completeTransfer *CompleteTransferUseCase
+	submitCycleCount *SubmitCycleCountUseCase
+	assembly         *AssemblyUseCase
+	batchSize        int
+	logger           Logger
+}
+
+// NewRetryFailedUseCase cria uma nova instância do caso de uso
+func NewRetryFailedUseCase(
+	failures fulfillment.FailureRepository,
+	repo fulfillment.Repository,
+	receiveGoods *ReceiveGoodsUseCase,
+	shipOrder *ShipOrderUseCase,
+	registerReturn *RegisterReturnUseCase,
+	completeTransfer *CompleteTransferUseCase,
+	submitCycleCount *SubmitCycleCountUseCase,
+	logger Logger,
+) *RetryFailedUseCase {
+	return &RetryFailedUseCase{
+		failures:         failures,
+		repo:             repo,
+		receiveGoods:     receiveGoods,
+		shipOrder:        shipOrder,
+		registerReturn:   registerReturn,
+		completeTransfer: completeTransfer,
+		submitCycleCount: submitCycleCount,
+		batchSize:        500,
+		logger:           logger,
+	}
+}
+
+// UseAssembly habilita a reexecução de ordens de montagem que falharam
+func (uc *RetryFailedUseCase) UseAssembly(assembly *AssemblyUseCase) {
+	uc.assembly = assembly
+}
+
+// ListFailed lista as operações em FAILED desde since (entityType vazio = todas)
+func (uc *RetryFailedUseCase) ListFailed(ctx context.Context, entityType string, since time.Time) ([]fulfillment.FailedOperation, error) {
+	if entityType != "" && !knownEntityTypes[entityType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+	operations, err := uc.failures.ListFailedOperations(ctx, entityType, since, uc.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed operations: %w", err)
+	}
+	return operations, nil
+}
+
+// ListDue lista as falhas transitórias desde since cujo backoff expirou em now,
+// criadas a partir do corte de SLA de cada operação
+func (uc *RetryFailedUseCase) ListDue(ctx context.Context, since, now time.Time, createdAfter map[fulfillment.OperationType]time.Time) ([]fulfillment.FailedOperation, error) {
+	operations, err := uc.failures.ListDueFailures(ctx, since, now, createdAfter, uc.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due failures: %w", err)
+	}
+	return operations, nil
+}
+
+// RetryAllSince reexecuta todas as operações que falharam desde since
+func (uc *RetryFailedUseCase) RetryAllSince(ctx context.Context, entityType string, since time.Time) (*RetryReport, error) {
+	operations, err := uc.ListFailed(ctx, entityType, since)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RetryReport{Results: []RetryResult{}}
+	for _, op := range operations {
+		report.add(op, uc.Retry(ctx, op.EntityType, op.EntityID))
+	}
+
+	uc.logger.Info("Bulk retry finished", "since", since, "attempted", report.Attempted, "succeeded", report.Succeeded, "failed", report.Failed)
+	return report, nil
+}
+
+// Retry devolve a operação para PENDING e reexecuta o caso de uso a partir da etapa que falhou
+func (uc *RetryFailedUseCase) Retry(ctx context.Context, entityType, id string) error {
+	var err error
+	switch entityType {
+	case fulfillment.EntityInboundShipment:
+		err = uc.retryInbound(ctx, id)
+	case fulfillment.EntityFulfillmentOrder:
+		err = uc.retryOrder(ctx, id)
+	case fulfillment.EntityTransferOrder:
+		err = uc.retryTransfer(ctx, id)
+	case fulfillment.EntityReturnOrder:
+		err = uc.retryReturn(ctx, id)
+	case fulfillment.EntityCycleCountTask:
+		err = uc.retryCycleCount(ctx, id)
+	case fulfillment.EntityAssemblyOrder:
+		if uc.assembly == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+		}
+		_, err = uc.assembly.Execute(ctx, id)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+
+	if err != nil {
+		uc.logger.Warn("Retry failed", "entity_type", entityType, "id", id, "error", err)
+		return err
+	}
+	uc.logger.Info("Retry succeeded", "entity_type", entityType, "id", id)
+	return nil
+}
+
+// withRetryReason registra no histórico de status o motivo da reexecução
+func withRetryReason(ctx context.Context, failure *fulfillment.Failure) context.Context {
+	if fulfillment.ReasonFromContext(ctx) != "" || failure == nil {
+		return ctx
+	}
+	return fulfillment.WithReason(ctx, fmt.Sprintf("retry after %s failure at %s (attempt %d)",
+		failure.Class, failure.Step, failure.Attempt))
+}
+
+// reopen devolve a operação FAILED para PENDING; pelo agendador automático, só dentro do SLA
+func reopen(ctx context.Context, subject fulfillment.Stateful) error {
+	event := fulfillment.EventRetry
+	if fulfillment.SourceFromContext(ctx) == fulfillment.SourceScheduler {
+		event = fulfillment.EventAutoRetry
+	}
+	return fulfillment.Fire(subject, event)
+}
+
+func (uc *RetryFailedUseCase) retryInbound(ctx context.Context, id string) error {
+	shipment, err := uc.repo.GetInboundByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get inbound shipment: %w", err)
+	}
+	if err := reopen(ctx, shipment); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+	if err := uc.repo.UpdateInbound(withRetryReason(ctx, shipment.Failure), shipment); err != nil {
+		return fmt.Errorf("failed to update inbound status: %w", err)
+	}
+	return uc.receiveGoods.ConfirmReceipt(ctx, id)
+}
+
+// retryOrder reserva novamente o estoque (liberado na falha) e confirma a expedição
+func (uc *RetryFailedUseCase) retryOrder(ctx context.Context, id string) error {
+	order, err := uc.repo.GetOrderByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+	if err := reopen(ctx, order); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+	if err := uc.repo.UpdateOrder(withRetryReason(ctx, order.Failure), order); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	if err := uc.shipOrder.StartPicking(ctx, id); err != nil {
+		return err
+	}
+	return uc.shipOrder.Ship(ctx, id)
+}
+
+func (uc *RetryFailedUseCase) retryTransfer(ctx context.Context, id string) error {
+	transfer, err := uc.repo.GetTransferByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer order: %w", err)
+	}
+	if err := reopen(ctx, transfer); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+	if err := uc.repo.UpdateTransfer(withRetryReason(ctx, transfer.Failure), transfer); err != nil {
+		return fmt.Errorf("failed to update transfer status: %w", err)
+	}
+	return uc.completeTransfer.CompleteTransfer(ctx, id)
+}
+
+func (uc *RetryFailedUseCase) retryReturn(ctx context.Context, id string) error {
+	returnOrder, err := uc.repo.GetReturnByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get return order: %w", err)
+	}
+	if err := reopen(ctx, returnOrder); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+	if err := uc.repo.UpdateReturn(withRetryReason(ctx, returnOrder.Failure), returnOrder); err != nil {
+		return fmt.Errorf("failed to update return status: %w", err)
+	}
+	return uc.registerReturn.CompleteReturn(ctx, id, returnOrder.Location)
+}
+
+func (uc *RetryFailedUseCase) retryCycleCount(ctx context.Context, id string) error {
+	task, err := uc.repo.GetCycleCountByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get cycle count task: %w", err)
+	}
+	if err := reopen(ctx, task); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+	if err := uc.repo.UpdateCycleCount(withRetryReason(ctx, task.Failure), task); err != nil {
+		return fmt.Errorf("failed to update cycle count task: %w", err)
+	}
+	return uc.submitCycleCount.SubmitCycleCount(ctx, id, task.CountedItems)
+}
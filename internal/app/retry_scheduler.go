@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// RetryScheduler reexecuta automaticamente falhas transitórias cujo backoff expirou,
+// enquanto a operação estiver dentro do SLA
+type RetryScheduler struct {
+	retry    *RetryFailedUseCase
+	policy   *fulfillment.Policy
+	lookback time.Duration
+	logger   Logger
+}
+
+// NewRetryScheduler cria uma nova instância do agendador
+func NewRetryScheduler(retry *RetryFailedUseCase, logger Logger) *RetryScheduler {
+	return &RetryScheduler{
+		retry:    retry,
+		policy:   fulfillment.DefaultPolicy(),
+		lookback: 24 * time.Hour,
+		logger:   logger,
+	}
+}
+
+// Run executa o agendador periodicamente até o contexto ser cancelado
+func (s *RetryScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Retry scheduler stopped")
+			return
+		case <-ticker.C:
+			if _, err := s.RetryDue(ctx); err != nil {
+				s.logger.Error("Automatic retry failed", "error", err)
+			}
+		}
+	}
+}
+
+// RetryDue reexecuta as falhas elegíveis e retorna o relatório
+func (s *RetryScheduler) RetryDue(ctx context.Context) (*RetryReport, error) {
+	ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceScheduler), "retry-scheduler")
+
+	now := time.Now()
+	operations, err := s.retry.ListDue(ctx, now.Add(-s.lookback), now, s.slaCutoffs(now))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RetryReport{Results: []RetryResult{}}
+	for _, op := range operations {
+		if !s.eligible(op, now) {
+			continue
+		}
+		report.add(op, s.retry.Retry(ctx, op.EntityType, op.EntityID))
+	}
+
+	if report.Attempted > 0 {
+		s.logger.Info("Automatic retry finished", "attempted", report.Attempted, "succeeded", report.Succeeded, "failed", report.Failed)
+	}
+	return report, nil
+}
+
+// slaCutoffs calcula, para cada operação com SLA, a data de criação mais antiga ainda dentro do prazo.
+// O corte vai na consulta, para que operações fora do SLA não ocupem o lote do agendador.
+func (s *RetryScheduler) slaCutoffs(now time.Time) map[fulfillment.OperationType]time.Time {
+	cutoffs := make(map[fulfillment.OperationType]time.Time)
+	for _, op := range []fulfillment.OperationType{
+		fulfillment.OpInbound, fulfillment.OpOutbound, fulfillment.OpTransfer, fulfillment.OpReturn, fulfillment.OpCycleCount,
+	} {
+		if minutes := s.policy.MaxDurationMinutes(op); minutes > 0 {
+			cutoffs[op] = now.Add(-time.Duration(minutes) * time.Minute)
+		}
+	}
+	return cutoffs
+}
+
+// eligible aplica os limites do retry automático: falha transitória, backoff expirado e SLA
+// (operações sem SLA configurado não têm limite, como no guard do auto_retry)
+func (s *RetryScheduler) eligible(op fulfillment.FailedOperation, now time.Time) bool {
+	if op.Failure == nil || op.Failure.Class != fulfillment.FailureTransient || !op.Failure.DueForRetry(now) {
+		return false
+	}
+	maxDuration := s.policy.MaxDurationMinutes(op.Operation)
+	return maxDuration <= 0 || !op.CreatedAt.Before(now.Add(-time.Duration(maxDuration)*time.Minute))
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+// ScanResult é a leitura interpretada: o código GS1 e, quando há GTIN, a linha de item do SKU
+type ScanResult struct {
+	Barcode *gs1.Barcode      `json:"barcode"`
+	Item    *fulfillment.Item `json:"item,omitempty"`
+	SSCC    string            `json:"sscc,omitempty"` // Unidade logística (ex: código de LPN)
+	Expired bool              `json:"expired,omitempty"`
+}
+
+// ScanUseCase interpreta as leituras dos coletores (GS1-128 e GS1 DataMatrix) e as resolve em itens
+// pelos GTINs cadastrados nas hierarquias de embalagem
+type ScanUseCase struct {
+	uoms   fulfillment.UoMRepository
+	logger Logger
+}
+
+// NewScanUseCase cria uma nova instância do caso de uso
+func NewScanUseCase(uoms fulfillment.UoMRepository, logger Logger) *ScanUseCase {
+	return &ScanUseCase{uoms: uoms, logger: logger}
+}
+
+// Parse interpreta o código sem consultar o cadastro
+func (uc *ScanUseCase) Parse(raw string) (*gs1.Barcode, error) {
+	barcode, err := gs1.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", fulfillment.ErrInvalidBarcode, err)
+	}
+	return barcode, nil
+}
+
+// Resolve interpreta o código e converte o GTIN no SKU e na unidade da embalagem lida.
+// Um código só com SSCC é devolvido sem item, para uso nas operações de LPN.
+func (uc *ScanUseCase) Resolve(ctx context.Context, raw string) (*ScanResult, error) {
+	barcode, err := uc.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := &ScanResult{Barcode: barcode, SSCC: barcode.SSCC}
+	if barcode.GTIN == "" {
+		if barcode.SSCC == "" {
+			return nil, fulfillment.ErrNoProductCode
+		}
+		return result, nil
+	}
+
+	hierarchy, err := uc.uoms.FindUoMHierarchyByGTIN(ctx, barcode.GTIN)
+	if errors.Is(err, fulfillment.ErrUoMHierarchyNotFound) {
+		return nil, fmt.Errorf("%w: %s", fulfillment.ErrUnknownGTIN, barcode.GTIN)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find gtin: %w", err)
+	}
+	item, err := fulfillment.ItemFromBarcode(barcode, hierarchy)
+	if err != nil {
+		return nil, err
+	}
+	result.Item = &item
+	result.Expired = item.ExpiresAt != nil && item.ExpiresAt.Before(time.Now())
+	if result.Expired {
+		uc.logger.Warn("Expired product scanned", "gtin", barcode.GTIN, "sku", item.SKU, "batch", item.Batch, "expires_at", item.ExpiresAt)
+	}
+	return result, nil
+}
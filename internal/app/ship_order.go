@@ -2,17 +2,25 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
 
+// OrderHook é executado após eventos de ciclo de vida da ordem (ex: backorder)
+type OrderHook func(ctx context.Context, order *fulfillment.FulfillmentOrder)
+
 // ShipOrderUseCase orquestra a expedição física de pedidos
 type ShipOrderUseCase struct {
 	repo            fulfillment.Repository
 	inventoryClient InventoryClient
 	eventPublisher  EventPublisher
+	policy          *fulfillment.Policy
+	onBackordered   []OrderHook
 	logger          Logger
+	unitNormalizer
 }
 
 // NewShipOrderUseCase cria uma nova instância do caso de uso
@@ -21,21 +29,48 @@ func NewShipOrderUseCase(repo fulfillment.Repository, inventoryClient InventoryC
 		repo:            repo,
 		inventoryClient: inventoryClient,
 		eventPublisher:  eventPublisher,
+		policy:          fulfillment.DefaultPolicy(),
 		logger:          logger,
 	}
 }
 
+// OnBackordered registra um hook executado quando a reserva da ordem fica em backorder
+// (ex: montagem de kits sob demanda)
+func (uc *ShipOrderUseCase) OnBackordered(hook OrderHook) {
+	uc.onBackordered = append(uc.onBackordered, hook)
+}
+
 // CreateOrder cria uma nova FulfillmentOrder a partir de um evento OMS
 func (uc *ShipOrderUseCase) CreateOrder(ctx context.Context, orderID, customer, destination string, items []fulfillment.Item, priority int) (*fulfillment.FulfillmentOrder, error) {
+	return uc.CreateOrderWithPromise(ctx, orderID, customer, destination, items, priority, fulfillment.ShipPromise{})
+}
+
+// CreateOrderWithPromise cria a ordem com os prazos prometidos, a transportadora e o nível do cliente
+func (uc *ShipOrderUseCase) CreateOrderWithPromise(ctx context.Context, orderID, customer, destination string, items []fulfillment.Item, priority int, promise fulfillment.ShipPromise) (*fulfillment.FulfillmentOrder, error) {
+	if err := promise.Validate(); err != nil {
+		return nil, err
+	}
+	items, err := uc.normalize(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	order, err := fulfillment.NewFulfillmentOrder(orderID, customer, destination, items, priority)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fulfillment order: %w", err)
 	}
+	order.ShipPromise = promise
 
 	// Verifica idempotência
 	existing, err := uc.repo.GetOrderByOrderID(ctx, orderID)
 	if err == nil && existing != nil {
 		uc.logger.Warn("Fulfillment order already exists (idempotency)", "order_id", orderID)
+		// Reentrega após falha na reserva: tenta reservar novamente
+		if existing.Status == fulfillment.StatusPending && existing.ReservationStatus == fulfillment.ReservationNone {
+			if err := uc.reserve(ctx, existing); err != nil {
+				return nil, err
+			}
+		}
 		return existing, nil
 	}
 
@@ -44,6 +79,12 @@ func (uc *ShipOrderUseCase) CreateOrder(ctx context.Context, orderID, customer,
 	}
 
 	uc.logger.Info("Fulfillment order created", "id", order.ID, "order_id", orderID)
+
+	// Reserva na criação (reserve-on-create)
+	if err := uc.reserve(ctx, order); err != nil {
+		return nil, err
+	}
+
 	return order, nil
 }
 
@@ -54,6 +95,24 @@ func (uc *ShipOrderUseCase) StartPicking(ctx context.Context, orderID string) er
 		return fmt.Errorf("failed to get fulfillment order: %w", err)
 	}
 
+	// Verifica a transição antes de reservar: ordens finalizadas não podem prender estoque no Core
+	if !fulfillment.Can(order, fulfillment.EventStart) {
+		return fmt.Errorf("invalid state transition: %w: cannot start picking from %s", fulfillment.ErrInvalidStateTransition, order.Status)
+	}
+	if order.ReservationStatus == fulfillment.ReservationBackordered {
+		return fulfillment.ErrOrderBackordered
+	}
+
+	// Reserva ausente ou expirada: reserva novamente antes de separar
+	if !order.HoldsReservation() || order.ReservationExpiredAt(time.Now()) {
+		if err := uc.reserve(ctx, order); err != nil {
+			return err
+		}
+		if !order.HoldsReservation() {
+			return fulfillment.ErrOrderBackordered
+		}
+	}
+
 	if err := order.StartPicking(); err != nil {
 		return fmt.Errorf("invalid state transition: %w", err)
 	}
@@ -73,6 +132,13 @@ func (uc *ShipOrderUseCase) StartPicking(ctx context.Context, orderID string) er
 
 // Ship confirma a expedição física e chama o Core Inventory
 func (uc *ShipOrderUseCase) Ship(ctx context.Context, orderID string) error {
+	return uc.ShipPicked(ctx, orderID, nil)
+}
+
+// ShipPicked expede a ordem com as quantidades separadas (nil = todos os itens). Numa separação parcial
+// o Core baixa apenas o separado e libera o restante da reserva, e os itens da ordem são ajustados na
+// mesma gravação da expedição.
+func (uc *ShipOrderUseCase) ShipPicked(ctx context.Context, orderID string, picked []fulfillment.Item) error {
 	order, err := uc.repo.GetOrderByID(ctx, orderID)
 	if err != nil {
 		return fmt.Errorf("failed to get fulfillment order: %w", err)
@@ -83,13 +149,22 @@ func (uc *ShipOrderUseCase) Ship(ctx context.Context, orderID string) error {
 		return fmt.Errorf("order must be in progress to ship, current status: %s", order.Status)
 	}
 
+	items := order.Items
+	if picked != nil {
+		items = picked
+	}
+
 	// Chama mcp-core-inventory para confirmar reservas e aplicar baixa definitiva
-	if err := uc.inventoryClient.ConfirmReservation(ctx, order.OrderID, order.Items); err != nil {
+	cmdCtx := fulfillment.WithCommandID(ctx, fulfillment.CommandID(order.ID, fulfillment.StepConfirmReservation, 0))
+	if err := uc.inventoryClient.ConfirmReservation(cmdCtx, order.OrderID, items); err != nil {
 		uc.logger.Error("Failed to confirm reservation in core inventory", "error", err)
-		order.Status = fulfillment.StatusFailed
+		order.Fail(fulfillment.StepConfirmReservation, 0, err)
+		uc.releaseReservation(ctx, order, fulfillment.ReservationReleased, "order_failed")
 		uc.repo.UpdateOrder(ctx, order)
 		return fmt.Errorf("failed to confirm reservation: %w", err)
 	}
+	order.Items = items
+	order.MarkReservationConfirmed()
 
 	// Completa a expedição
 	if err := order.Ship(); err != nil {
@@ -109,3 +184,101 @@ func (uc *ShipOrderUseCase) Ship(ctx context.Context, orderID string) error {
 	uc.logger.Info("Order shipped", "order_id", orderID)
 	return nil
 }
+
+// CancelOrder cancela a ordem e libera a reserva de estoque
+func (uc *ShipOrderUseCase) CancelOrder(ctx context.Context, orderID, reason string) error {
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+
+	if err := order.Cancel(); err != nil {
+		return fmt.Errorf("invalid state transition: %w", err)
+	}
+
+	ctx = fulfillment.WithReason(ctx, reason)
+	if err := uc.releaseReservation(ctx, order, fulfillment.ReservationReleased, "order_cancelled: "+reason); err != nil {
+		// O ReservationReconciler libera reservas órfãs de ordens canceladas
+		uc.logger.Warn("Reservation left for reconciliation", "order_id", orderID, "error", err)
+	}
+
+	if err := uc.repo.UpdateOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	uc.logger.Info("Order cancelled", "order_id", orderID, "reason", reason)
+	return nil
+}
+
+// ReleaseBackorder tenta reservar novamente uma ordem em backorder (ex: após reposição)
+func (uc *ShipOrderUseCase) ReleaseBackorder(ctx context.Context, orderID string) error {
+	order, err := uc.repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get fulfillment order: %w", err)
+	}
+
+	if order.ReservationStatus != fulfillment.ReservationBackordered {
+		return fulfillment.ErrNotBackordered
+	}
+
+	if err := uc.reserve(ctx, order); err != nil {
+		return err
+	}
+
+	if !order.HoldsReservation() {
+		return fulfillment.ErrOrderBackordered
+	}
+
+	uc.logger.Info("Backorder released", "order_id", orderID)
+	return nil
+}
+
+// reserve solicita a reserva ao Core e persiste o resultado (RESERVED ou BACKORDERED)
+func (uc *ShipOrderUseCase) reserve(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	expiresAt := time.Now().Add(uc.policy.ReservationTTL())
+
+	err := uc.inventoryClient.ReserveStock(ctx, order.OrderID, order.Items, expiresAt)
+	switch {
+	case err == nil:
+		order.MarkReserved(expiresAt)
+	case errors.Is(err, fulfillment.ErrInsufficientStock):
+		uc.logger.Warn("Insufficient stock, order backordered", "order_id", order.OrderID)
+		order.MarkBackordered()
+	default:
+		uc.logger.Error("Failed to reserve stock in core inventory", "error", err, "order_id", order.OrderID)
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	if err := uc.repo.UpdateOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update order reservation: %w", err)
+	}
+
+	if order.ReservationStatus == fulfillment.ReservationBackordered && len(uc.onBackordered) > 0 {
+		for _, hook := range uc.onBackordered {
+			hook(ctx, order)
+		}
+		// Os hooks podem ter liberado o backorder: recarrega o estado persistido
+		if refreshed, err := uc.repo.GetOrderByID(ctx, order.ID); err == nil {
+			*order = *refreshed
+		}
+	}
+	return nil
+}
+
+// releaseReservation libera a reserva no Core quando a ordem ainda a detém
+func (uc *ShipOrderUseCase) releaseReservation(ctx context.Context, order *fulfillment.FulfillmentOrder, status fulfillment.ReservationStatus, reason string) error {
+	if !order.HoldsReservation() {
+		if order.ReservationStatus == fulfillment.ReservationBackordered {
+			order.MarkReservationReleased(status)
+		}
+		return nil
+	}
+
+	if err := uc.inventoryClient.ReleaseReservation(ctx, order.OrderID, reason); err != nil {
+		uc.logger.Error("Failed to release reservation in core inventory", "error", err, "order_id", order.OrderID)
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	order.MarkReservationReleased(status)
+	return nil
+}
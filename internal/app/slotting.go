@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// SlottingAnalysis é o resultado de uma análise de giro com as recomendações propostas
+type SlottingAnalysis struct {
+	Since           time.Time                             `json:"since"`
+	Days            float64                               `json:"days"`
+	Velocities      []fulfillment.SKUVelocity             `json:"velocities"`
+	Recommendations []*fulfillment.SlottingRecommendation `json:"recommendations"`
+}
+
+// SlottingUseCase analisa o giro dos SKUs no histórico de expedição e propõe realocações para a
+// zona dourada; recomendações aprovadas viram transferências internas com tarefa de movimentação
+type SlottingUseCase struct {
+	slotting         fulfillment.SlottingRepository
+	layouts          fulfillment.LayoutRepository
+	uoms             fulfillment.UoMRepository
+	inventoryClient  InventoryClient
+	completeTransfer *CompleteTransferUseCase
+	tasks            *WarehouseTaskUseCase
+	logger           Logger
+}
+
+// NewSlottingUseCase cria uma nova instância do caso de uso
+func NewSlottingUseCase(
+	slotting fulfillment.SlottingRepository,
+	layouts fulfillment.LayoutRepository,
+	uoms fulfillment.UoMRepository,
+	inventoryClient InventoryClient,
+	completeTransfer *CompleteTransferUseCase,
+	tasks *WarehouseTaskUseCase,
+	logger Logger,
+) *SlottingUseCase {
+	return &SlottingUseCase{
+		slotting:         slotting,
+		layouts:          layouts,
+		uoms:             uoms,
+		inventoryClient:  inventoryClient,
+		completeTransfer: completeTransfer,
+		tasks:            tasks,
+		logger:           logger,
+	}
+}
+
+// Analyze calcula o giro das ordens expedidas desde since e substitui as recomendações pendentes
+func (uc *SlottingUseCase) Analyze(ctx context.Context, since time.Time) (*SlottingAnalysis, error) {
+	orders, err := uc.slotting.ListShippedOrders(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipped orders: %w", err)
+	}
+	hierarchies, err := uc.uoms.ListUoMHierarchies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uom hierarchies: %w", err)
+	}
+	unitCubes := make(map[string]float64, len(hierarchies))
+	for _, hierarchy := range hierarchies {
+		unitCubes[hierarchy.SKU] = hierarchy.UnitCube
+	}
+	layout, err := uc.layouts.GetLayout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	days := time.Since(since).Hours() / 24
+	velocities := fulfillment.AnalyzeVelocity(orders, unitCubes, days)
+	recommendations, err := fulfillment.RecommendSlotting(velocities, layout)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.slotting.ReplaceSlottingProposals(ctx, recommendations); err != nil {
+		return nil, fmt.Errorf("failed to save slotting recommendations: %w", err)
+	}
+
+	uc.logger.Info("Slotting analysis completed", "skus", len(velocities), "recommendations", len(recommendations))
+	return &SlottingAnalysis{Since: since, Days: days, Velocities: velocities, Recommendations: recommendations}, nil
+}
+
+// ListRecommendations lista as recomendações pelo status (vazio = todas), maior economia primeiro
+func (uc *SlottingUseCase) ListRecommendations(ctx context.Context, status fulfillment.SlottingStatus) ([]*fulfillment.SlottingRecommendation, error) {
+	recommendations, err := uc.slotting.ListSlottingRecommendations(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slotting recommendations: %w", err)
+	}
+	return recommendations, nil
+}
+
+// Approve gera as transferências da realocação (e da troca, quando houver SKU deslocado)
+// com uma tarefa de movimentação para cada uma
+func (uc *SlottingUseCase) Approve(ctx context.Context, id string) (*fulfillment.SlottingRecommendation, error) {
+	rec, err := uc.slotting.GetSlottingRecommendation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from := rec.Status
+
+	// A aprovação é gravada antes das transferências: uma aprovação concorrente falha sem duplicá-las
+	if err := rec.Approve(rec.TransferIDs); err != nil {
+		return nil, err
+	}
+	if err := uc.slotting.UpdateSlottingRecommendation(ctx, rec, from); err != nil {
+		return nil, fmt.Errorf("failed to update slotting recommendation: %w", err)
+	}
+
+	// Uma aprovação PARTIAL já realocou o SKU: retoma apenas a troca do SKU deslocado
+	transferIDs := rec.TransferIDs
+	if from == fulfillment.SlottingProposed {
+		if transferIDs, err = uc.relocate(ctx, transferIDs, rec.SKU, rec.FromLocation, rec.ToLocation); err != nil {
+			return nil, uc.revertApproval(ctx, rec, fulfillment.SlottingProposed, err)
+		}
+	}
+	if rec.DisplacedSKU != "" {
+		if transferIDs, err = uc.relocate(ctx, transferIDs, rec.DisplacedSKU, rec.ToLocation, rec.FromLocation); err != nil {
+			rec.TransferIDs = transferIDs
+			return nil, uc.revertApproval(ctx, rec, fulfillment.SlottingPartial, err)
+		}
+	}
+	rec.TransferIDs = transferIDs
+	if err := uc.slotting.UpdateSlottingRecommendation(ctx, rec, fulfillment.SlottingApproved); err != nil {
+		return nil, fmt.Errorf("failed to update slotting recommendation: %w", err)
+	}
+	uc.logger.Info("Slotting recommendation approved", "id", rec.ID, "sku", rec.SKU, "to", rec.ToLocation, "transfers", len(transferIDs))
+	return rec, nil
+}
+
+// revertApproval grava a recomendação no status de onde a aprovação pode ser retomada e devolve o erro da transferência
+func (uc *SlottingUseCase) revertApproval(ctx context.Context, rec *fulfillment.SlottingRecommendation, status fulfillment.SlottingStatus, cause error) error {
+	rec.Status = status
+	if err := uc.slotting.UpdateSlottingRecommendation(ctx, rec, fulfillment.SlottingApproved); err != nil {
+		uc.logger.Error("Failed to save slotting recommendation after transfer failure", "error", err, "id", rec.ID)
+	}
+	return cause
+}
+
+// relocate transfere todo o saldo disponível do SKU entre os endereços (sem saldo, nada a mover).
+// Em caso de erro retorna as transferências criadas até então.
+func (uc *SlottingUseCase) relocate(ctx context.Context, transferIDs []string, sku, from, to string) ([]string, error) {
+	available, err := uc.inventoryClient.GetAvailableStock(ctx, from, sku)
+	if err != nil {
+		return transferIDs, fmt.Errorf("failed to get available stock: %w", err)
+	}
+	if available <= 0 {
+		return transferIDs, nil
+	}
+
+	transfer, err := uc.completeTransfer.CreateTransfer(ctx, from, to, []fulfillment.Item{{SKU: sku, Quantity: available}})
+	if err != nil {
+		return transferIDs, fmt.Errorf("failed to create slotting transfer: %w", err)
+	}
+	if _, err := uc.tasks.EnqueueAs(ctx, fulfillment.TaskSlotMove, fulfillment.EntityTransferOrder, transfer.ID, 0); err != nil {
+		uc.logger.Error("Failed to enqueue slotting move task", "error", err, "transfer_id", transfer.ID)
+	}
+	return append(transferIDs, transfer.ID), nil
+}
+
+// Reject descarta a recomendação
+func (uc *SlottingUseCase) Reject(ctx context.Context, id string) (*fulfillment.SlottingRecommendation, error) {
+	rec, err := uc.slotting.GetSlottingRecommendation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := rec.Reject(); err != nil {
+		return nil, err
+	}
+	if err := uc.slotting.UpdateSlottingRecommendation(ctx, rec, fulfillment.SlottingProposed); err != nil {
+		return nil, fmt.Errorf("failed to update slotting recommendation: %w", err)
+	}
+	return rec, nil
+}
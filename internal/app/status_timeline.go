@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+var (
+	ErrTimelineNotFound  = errors.New("no status history for entity")
+	ErrUnknownEntityType = errors.New("unknown entity type")
+)
+
+var knownEntityTypes = map[string]bool{
+	fulfillment.EntityInboundShipment:  true,
+	fulfillment.EntityFulfillmentOrder: true,
+	fulfillment.EntityTransferOrder:    true,
+	fulfillment.EntityReturnOrder:      true,
+	fulfillment.EntityCycleCountTask:   true,
+	fulfillment.EntityWarehouseTask:    true,
+	fulfillment.EntityAssemblyOrder:    true,
+}
+
+// Timeline é a linha do tempo de status de uma entidade com a permanência em cada status
+type Timeline struct {
+	EntityType    string                               `json:"entity_type"`
+	EntityID      string                               `json:"entity_id"`
+	CurrentStatus fulfillment.Status                   `json:"current_status"`
+	Transitions   []fulfillment.StatusTransition       `json:"transitions"`
+	Dwell         map[fulfillment.Status]time.Duration `json:"dwell_ns"`
+}
+
+// StatusTimelineUseCase consulta o histórico de status e métricas de permanência
+type StatusTimelineUseCase struct {
+	history fulfillment.StatusHistoryRepository
+	logger  Logger
+}
+
+// NewStatusTimelineUseCase cria uma nova instância do caso de uso
+func NewStatusTimelineUseCase(history fulfillment.StatusHistoryRepository, logger Logger) *StatusTimelineUseCase {
+	return &StatusTimelineUseCase{
+		history: history,
+		logger:  logger,
+	}
+}
+
+// GetTimeline retorna as transições da entidade e o tempo gasto em cada status
+func (uc *StatusTimelineUseCase) GetTimeline(ctx context.Context, entityType, entityID string) (*Timeline, error) {
+	if !knownEntityTypes[entityType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+
+	transitions, err := uc.history.ListStatusHistory(ctx, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status history: %w", err)
+	}
+	if len(transitions) == 0 {
+		return nil, ErrTimelineNotFound
+	}
+
+	return &Timeline{
+		EntityType:    entityType,
+		EntityID:      entityID,
+		CurrentStatus: transitions[len(transitions)-1].ToStatus,
+		Transitions:   transitions,
+		Dwell:         fulfillment.StatusDwell(transitions, time.Now()),
+	}, nil
+}
+
+// DwellStats retorna a permanência média/p95/máxima por status desde "since"
+func (uc *StatusTimelineUseCase) DwellStats(ctx context.Context, entityType string, since time.Time) ([]fulfillment.DwellStat, error) {
+	if !knownEntityTypes[entityType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEntityType, entityType)
+	}
+
+	stats, err := uc.history.StatusDwellStats(ctx, entityType, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute dwell stats: %w", err)
+	}
+	return stats, nil
+}
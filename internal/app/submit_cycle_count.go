@@ -7,12 +7,17 @@ import (
 	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
 
+// VarianceHook é executado para cada divergência ajustada em uma contagem cíclica
+type VarianceHook func(ctx context.Context, location, sku string, difference int)
+
 // SubmitCycleCountUseCase orquestra o envio e processamento de contagens cíclicas
 type SubmitCycleCountUseCase struct {
 	repo            fulfillment.Repository
 	inventoryClient InventoryClient
 	eventPublisher  EventPublisher
+	afterVariance   []VarianceHook
 	logger          Logger
+	unitNormalizer
 }
 
 // NewSubmitCycleCountUseCase cria uma nova instância do caso de uso
@@ -25,8 +30,18 @@ func NewSubmitCycleCountUseCase(repo fulfillment.Repository, inventoryClient Inv
 	}
 }
 
+// AfterVariance registra um hook executado após o ajuste de cada divergência (ex: recontagem)
+func (uc *SubmitCycleCountUseCase) AfterVariance(hook VarianceHook) {
+	uc.afterVariance = append(uc.afterVariance, hook)
+}
+
 // SubmitCycleCount processa a contagem física e gera ajustes
 func (uc *SubmitCycleCountUseCase) SubmitCycleCount(ctx context.Context, taskID string, countedItems []fulfillment.Item) error {
+	countedItems, err := uc.normalize(ctx, countedItems)
+	if err != nil {
+		return err
+	}
+
 	task, err := uc.repo.GetCycleCountByID(ctx, taskID)
 	if err != nil {
 		return fmt.Errorf("failed to get cycle count task: %w", err)
@@ -47,7 +62,8 @@ func (uc *SubmitCycleCountUseCase) SubmitCycleCount(ctx context.Context, taskID
 	}
 
 	// Compara contagem física vs ledger (via Core) e calcula diferenças
-	for _, countedItem := range countedItems {
+	for idx := task.Failure.ResumeFrom(fulfillment.StepAdjustStock); idx < len(countedItems); idx++ {
+		countedItem := countedItems[idx]
 		ledgerQuantity, err := uc.inventoryClient.GetAvailableStock(ctx, task.Location, countedItem.SKU)
 		if err != nil {
 			uc.logger.Error("Failed to get available stock from core inventory", "error", err, "sku", countedItem.SKU)
@@ -61,12 +77,16 @@ func (uc *SubmitCycleCountUseCase) SubmitCycleCount(ctx context.Context, taskID
 			uc.logger.Warn("Stock discrepancy found", "sku", countedItem.SKU, "ledger", ledgerQuantity, "physical", physicalQuantity, "difference", difference)
 
 			// Gera ajuste via mcp-core-inventory
-			if err := uc.inventoryClient.AdjustStock(ctx, task.Location, countedItem.SKU, difference, countedItem.Batch); err != nil {
+			cmdCtx := fulfillment.WithCommandID(ctx, fulfillment.CommandID(task.ID, fulfillment.StepAdjustStock, idx))
+			if err := uc.inventoryClient.AdjustStock(cmdCtx, task.Location, countedItem.SKU, difference, countedItem.Batch); err != nil {
 				uc.logger.Error("Failed to adjust stock in core inventory", "error", err, "sku", countedItem.SKU)
-				task.Status = fulfillment.StatusFailed
+				task.Fail(fulfillment.StepAdjustStock, idx, err)
 				uc.repo.UpdateCycleCount(ctx, task)
 				return fmt.Errorf("failed to adjust stock for SKU %s: %w", countedItem.SKU, err)
 			}
+			for _, hook := range uc.afterVariance {
+				hook(ctx, task.Location, countedItem.SKU, difference)
+			}
 		}
 	}
 
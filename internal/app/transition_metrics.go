@@ -0,0 +1,80 @@
+package app
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// TransitionCount é o total de transições observadas para uma aresta da máquina de estados
+type TransitionCount struct {
+	Operation fulfillment.OperationType `json:"operation"`
+	Event     string                    `json:"event"`
+	From      fulfillment.Status        `json:"from"`
+	To        fulfillment.Status        `json:"to"`
+	Count     int64                     `json:"count"`
+}
+
+type transitionKey struct {
+	operation fulfillment.OperationType
+	event     string
+	from      fulfillment.Status
+	to        fulfillment.Status
+}
+
+// TransitionMetrics contabiliza as transições aplicadas pela máquina de estados. O after hook roda
+// antes da persistência: o contador inclui tentativas cuja gravação falhou ou foi repetida, e não
+// deve ser conciliado com o histórico de status.
+type TransitionMetrics struct {
+	mu     sync.Mutex
+	counts map[transitionKey]int64
+	remove func()
+}
+
+// NewTransitionMetrics cria o contador e o registra como after hook em todas as máquinas
+func NewTransitionMetrics() *TransitionMetrics {
+	m := &TransitionMetrics{counts: make(map[transitionKey]int64)}
+	m.remove = fulfillment.OnTransition(m.Observe)
+	return m
+}
+
+// Close remove o hook das máquinas de estados; os contadores continuam disponíveis
+func (m *TransitionMetrics) Close() {
+	m.remove()
+}
+
+// Observe é o after hook que incrementa o contador da transição
+func (m *TransitionMetrics) Observe(tc fulfillment.TransitionContext) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[transitionKey{tc.Operation, tc.Event, tc.From, tc.To}]++
+}
+
+// Snapshot retorna os contadores ordenados por operação, origem e destino
+func (m *TransitionMetrics) Snapshot() []TransitionCount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]TransitionCount, 0, len(m.counts))
+	for key, count := range m.counts {
+		list = append(list, TransitionCount{
+			Operation: key.operation,
+			Event:     key.event,
+			From:      key.from,
+			To:        key.to,
+			Count:     count,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return list
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// UnitsUseCase mantém as hierarquias de embalagem por SKU e normaliza linhas de itens
+// para a unidade base antes que cheguem ao Core Inventory
+type UnitsUseCase struct {
+	uoms   fulfillment.UoMRepository
+	logger Logger
+}
+
+// NewUnitsUseCase cria uma nova instância do caso de uso
+func NewUnitsUseCase(uoms fulfillment.UoMRepository, logger Logger) *UnitsUseCase {
+	return &UnitsUseCase{
+		uoms:   uoms,
+		logger: logger,
+	}
+}
+
+// DefineHierarchy cria ou substitui a hierarquia do SKU
+func (uc *UnitsUseCase) DefineHierarchy(ctx context.Context, hierarchy *fulfillment.UoMHierarchy) error {
+	if err := hierarchy.Validate(); err != nil {
+		return err
+	}
+	for _, gtin := range hierarchy.GTINs() {
+		owner, err := uc.uoms.FindUoMHierarchyByGTIN(ctx, gtin)
+		if errors.Is(err, fulfillment.ErrUoMHierarchyNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check gtin: %w", err)
+		}
+		if owner.SKU != hierarchy.SKU {
+			return fmt.Errorf("%w: %s belongs to %s", fulfillment.ErrGTINInUse, gtin, owner.SKU)
+		}
+	}
+	hierarchy.UpdatedAt = time.Now()
+	if err := uc.uoms.SaveUoMHierarchy(ctx, hierarchy); err != nil {
+		return fmt.Errorf("failed to save uom hierarchy: %w", err)
+	}
+	uc.logger.Info("UoM hierarchy defined", "sku", hierarchy.SKU, "levels", len(hierarchy.Levels))
+	return nil
+}
+
+func (uc *UnitsUseCase) GetHierarchy(ctx context.Context, sku string) (*fulfillment.UoMHierarchy, error) {
+	return uc.uoms.GetUoMHierarchy(ctx, sku)
+}
+
+func (uc *UnitsUseCase) ListHierarchies(ctx context.Context) ([]*fulfillment.UoMHierarchy, error) {
+	hierarchies, err := uc.uoms.ListUoMHierarchies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uom hierarchies: %w", err)
+	}
+	return hierarchies, nil
+}
+
+func (uc *UnitsUseCase) DeleteHierarchy(ctx context.Context, sku string) error {
+	if err := uc.uoms.DeleteUoMHierarchy(ctx, sku); err != nil {
+		return fmt.Errorf("failed to delete uom hierarchy: %w", err)
+	}
+	return nil
+}
+
+// Convert converte a quantidade do SKU entre duas unidades (via unidade base)
+func (uc *UnitsUseCase) Convert(ctx context.Context, sku string, quantity int, from, to string) (int, error) {
+	hierarchy, err := uc.hierarchy(ctx, sku)
+	if err != nil {
+		return 0, err
+	}
+	base, err := hierarchy.ToBase(from, quantity)
+	if err != nil {
+		return 0, err
+	}
+	return hierarchy.FromBase(to, base)
+}
+
+// Normalize expressa as linhas em unidades base (EA); linhas sem unidade declarada não consultam o repositório
+func (uc *UnitsUseCase) Normalize(ctx context.Context, items []fulfillment.Item) ([]fulfillment.Item, error) {
+	hierarchies := make(map[string]*fulfillment.UoMHierarchy)
+	normalized := make([]fulfillment.Item, 0, len(items))
+	for _, item := range items {
+		var hierarchy *fulfillment.UoMHierarchy
+		if item.NeedsUoM() {
+			cached, ok := hierarchies[item.SKU]
+			if !ok {
+				var err error
+				if cached, err = uc.hierarchy(ctx, item.SKU); err != nil {
+					return nil, err
+				}
+				hierarchies[item.SKU] = cached
+			}
+			hierarchy = cached
+		}
+
+		line, err := fulfillment.NormalizeItem(item, hierarchy)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, line)
+	}
+	return normalized, nil
+}
+
+// hierarchy carrega a hierarquia do SKU; sem hierarquia (nil) apenas a unidade base é aceita
+func (uc *UnitsUseCase) hierarchy(ctx context.Context, sku string) (*fulfillment.UoMHierarchy, error) {
+	hierarchy, err := uc.uoms.GetUoMHierarchy(ctx, sku)
+	if errors.Is(err, fulfillment.ErrUoMHierarchyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uom hierarchy: %w", err)
+	}
+	return hierarchy, nil
+}
+
+// unitNormalizer é embutido nos casos de uso que recebem linhas de itens de fora do serviço
+type unitNormalizer struct {
+	units *UnitsUseCase
+}
+
+// UseUnits habilita a conversão pelas hierarquias de embalagem; sem ela apenas a unidade base é aceita
+func (n *unitNormalizer) UseUnits(units *UnitsUseCase) {
+	n.units = units
+}
+
+func (n *unitNormalizer) normalize(ctx context.Context, items []fulfillment.Item) ([]fulfillment.Item, error) {
+	if n.units != nil {
+		return n.units.Normalize(ctx, items)
+	}
+	normalized := make([]fulfillment.Item, 0, len(items))
+	for _, item := range items {
+		line, err := fulfillment.NormalizeItem(item, nil)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, line)
+	}
+	return normalized, nil
+}
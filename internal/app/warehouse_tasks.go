@@ -0,0 +1,399 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// WarehouseTaskUseCase mantém a fila unificada de tarefas de armazém. O ciclo de vida
+// da tarefa (aceite, início, conclusão, abandono) aciona os casos de uso da operação.
+type WarehouseTaskUseCase struct {
+	repo             fulfillment.Repository
+	tasks            fulfillment.TaskRepository
+	receiveGoods     *ReceiveGoodsUseCase
+	shipOrder        *ShipOrderUseCase
+	completeTransfer *CompleteTransferUseCase
+	submitCycleCount *SubmitCycleCountUseCase
+	afterComplete    []TaskHook
+	priorities       *PrioritizationUseCase
+	batchSize        int
+	logger           Logger
+}
+
+// TaskHook é chamado depois que uma tarefa concluída foi persistida (ex: gatilho de reabastecimento)
+type TaskHook func(ctx context.Context, task *fulfillment.WarehouseTask)
+
+// NewWarehouseTaskUseCase cria uma nova instância do caso de uso
+func NewWarehouseTaskUseCase(
+	repo fulfillment.Repository,
+	tasks fulfillment.TaskRepository,
+	receiveGoods *ReceiveGoodsUseCase,
+	shipOrder *ShipOrderUseCase,
+	completeTransfer *CompleteTransferUseCase,
+	submitCycleCount *SubmitCycleCountUseCase,
+	logger Logger,
+) *WarehouseTaskUseCase {
+	return &WarehouseTaskUseCase{
+		repo:             repo,
+		tasks:            tasks,
+		receiveGoods:     receiveGoods,
+		shipOrder:        shipOrder,
+		completeTransfer: completeTransfer,
+		submitCycleCount: submitCycleCount,
+		batchSize:        500,
+		logger:           logger,
+	}
+}
+
+// AfterComplete registra um hook executado após cada conclusão de tarefa
+func (uc *WarehouseTaskUseCase) AfterComplete(hook TaskHook) {
+	uc.afterComplete = append(uc.afterComplete, hook)
+}
+
+// UsePriorities prioriza as tarefas de separação pela pontuação dinâmica da ordem (prazo e nível do
+// cliente); sem ela vale a prioridade informada pelo OMS
+func (uc *WarehouseTaskUseCase) UsePriorities(priorities *PrioritizationUseCase) {
+	uc.priorities = priorities
+}
+
+// Enqueue gera a tarefa da operação (idempotente: devolve a tarefa aberta existente).
+// priority < 0 usa a prioridade da própria operação, quando houver.
+func (uc *WarehouseTaskUseCase) Enqueue(ctx context.Context, entityType, entityID string, priority int) (*fulfillment.WarehouseTask, error) {
+	taskType, err := fulfillment.TaskTypeFor(entityType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, entityType)
+	}
+	return uc.EnqueueAs(ctx, taskType, entityType, entityID, priority)
+}
+
+// EnqueueAs gera a tarefa da operação com um tipo específico (ex: transferência de cross-dock)
+func (uc *WarehouseTaskUseCase) EnqueueAs(ctx context.Context, taskType fulfillment.TaskType, entityType, entityID string, priority int) (*fulfillment.WarehouseTask, error) {
+	existing, err := uc.tasks.GetTaskByEntity(ctx, entityType, entityID)
+	if err == nil && existing.Status != fulfillment.StatusCompleted && existing.Status != fulfillment.StatusCancelled {
+		return existing, nil
+	}
+	if err != nil && !errors.Is(err, fulfillment.ErrTaskNotFound) {
+		return nil, fmt.Errorf("failed to get warehouse task: %w", err)
+	}
+
+	source, destination, entityPriority, err := uc.taskLocations(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if priority < 0 {
+		priority = entityPriority
+	}
+
+	task, err := fulfillment.NewWarehouseTask(taskType, entityType, entityID, source, destination, priority)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tasks.CreateTask(ctx, task); err != nil {
+		if errors.Is(err, fulfillment.ErrTaskConflict) {
+			// Outro enqueue criou a tarefa aberta entre a leitura e a inserção
+			if existing, getErr := uc.tasks.GetTaskByEntity(ctx, entityType, entityID); getErr == nil {
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create warehouse task: %w", err)
+	}
+
+	uc.logger.Info("Warehouse task created", "id", task.ID, "type", task.Type, "entity_type", entityType, "entity_id", entityID)
+	return task, nil
+}
+
+// taskLocations deriva origem, destino e prioridade da operação
+func (uc *WarehouseTaskUseCase) taskLocations(ctx context.Context, entityType, id string) (string, string, int, error) {
+	switch entityType {
+	case fulfillment.EntityFulfillmentOrder:
+		order, err := uc.repo.GetOrderByID(ctx, id)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to get order: %w", err)
+		}
+		priority := order.Priority
+		if uc.priorities != nil {
+			priority = uc.priorities.TaskPriority(ctx, order)
+		}
+		return firstItemLocation(order.Items), "", priority, nil
+	case fulfillment.EntityInboundShipment:
+		shipment, err := uc.repo.GetInboundByID(ctx, id)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to get inbound shipment: %w", err)
+		}
+		return shipment.Destination, firstItemLocation(shipment.Items), 0, nil
+	case fulfillment.EntityTransferOrder:
+		transfer, err := uc.repo.GetTransferByID(ctx, id)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to get transfer: %w", err)
+		}
+		return transfer.LocationFrom, transfer.LocationTo, 0, nil
+	case fulfillment.EntityCycleCountTask:
+		task, err := uc.repo.GetCycleCountByID(ctx, id)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to get cycle count task: %w", err)
+		}
+		return task.Location, "", 0, nil
+	default:
+		return "", "", 0, fmt.Errorf("%w: %s", fulfillment.ErrTaskEntityUnsupported, entityType)
+	}
+}
+
+func firstItemLocation(items []fulfillment.Item) string {
+	for _, item := range items {
+		if item.Location != "" {
+			return item.Location
+		}
+	}
+	return ""
+}
+
+// Get retorna a tarefa
+func (uc *WarehouseTaskUseCase) Get(ctx context.Context, id string) (*fulfillment.WarehouseTask, error) {
+	task, err := uc.tasks.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse task: %w", err)
+	}
+	return task, nil
+}
+
+// ListOpen lista as tarefas em aberto (pendentes e com operador)
+func (uc *WarehouseTaskUseCase) ListOpen(ctx context.Context) ([]*fulfillment.WarehouseTask, error) {
+	tasks, err := uc.tasks.ListOpenTasks(ctx, uc.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouse tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// NextTask devolve a tarefa ativa do operador ou aceita a melhor tarefa pendente para quem está em
+// location, intercalando tipos de trabalho para reduzir deslocamento vazio. types vazio aceita todos.
+// Tarefas cuja operação está bloqueada (ou não está mais em execução) são ignoradas.
+func (uc *WarehouseTaskUseCase) NextTask(ctx context.Context, location, device string, types []fulfillment.TaskType) (*fulfillment.WarehouseTask, error) {
+	operator := fulfillment.ActorFromContext(ctx)
+	if operator == "" {
+		return nil, fulfillment.ErrTaskAssigneeRequired
+	}
+
+	recent, err := uc.tasks.ListTasksByAssignee(ctx, operator, 20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operator tasks: %w", err)
+	}
+	var lastType fulfillment.TaskType
+	for _, task := range recent {
+		if task.IsActive() {
+			return task, nil
+		}
+		if lastType == "" && task.Status == fulfillment.StatusCompleted {
+			lastType = task.Type
+			if location == "" {
+				location = task.DestinationLocation
+			}
+		}
+	}
+
+	open, err := uc.tasks.ListOpenTasks(ctx, uc.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouse tasks: %w", err)
+	}
+
+	for _, candidate := range fulfillment.RankTasks(filterTaskTypes(open, types), location, lastType, time.Now()) {
+		task := candidate.Task
+		workable, err := uc.entityWorkable(ctx, task)
+		if err != nil {
+			uc.logger.Warn("Skipping warehouse task", "id", task.ID, "error", err)
+			continue
+		}
+		if !workable {
+			continue
+		}
+
+		if err := task.Accept(operator, device); err != nil {
+			continue
+		}
+		if err := uc.tasks.UpdateTask(ctx, task); err != nil {
+			if errors.Is(err, fulfillment.ErrTaskConflict) {
+				// Aceita por outro operador entre a listagem e o aceite
+				continue
+			}
+			return nil, fmt.Errorf("failed to update warehouse task: %w", err)
+		}
+
+		uc.logger.Info("Warehouse task assigned", "id", task.ID, "type", task.Type, "operator", operator, "distance", candidate.Distance, "score", candidate.Score)
+		return task, nil
+	}
+	return nil, fulfillment.ErrNoTaskAvailable
+}
+
+func filterTaskTypes(tasks []*fulfillment.WarehouseTask, types []fulfillment.TaskType) []*fulfillment.WarehouseTask {
+	if len(types) == 0 {
+		return tasks
+	}
+	allowed := make(map[fulfillment.TaskType]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+	filtered := make([]*fulfillment.WarehouseTask, 0, len(tasks))
+	for _, task := range tasks {
+		if allowed[task.Type] {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+// entityWorkable indica se a operação da tarefa pode ser trabalhada (não bloqueada nem finalizada)
+func (uc *WarehouseTaskUseCase) entityWorkable(ctx context.Context, task *fulfillment.WarehouseTask) (bool, error) {
+	entity, _, err := loadBlockable(ctx, uc.repo, task.EntityType, task.EntityID)
+	if err != nil {
+		return false, err
+	}
+	return entity.CurrentStatus().IsWorkable(), nil
+}
+
+// entityCompleted indica se a operação da tarefa já foi concluída
+func (uc *WarehouseTaskUseCase) entityCompleted(ctx context.Context, task *fulfillment.WarehouseTask) (bool, error) {
+	entity, _, err := loadBlockable(ctx, uc.repo, task.EntityType, task.EntityID)
+	if err != nil {
+		return false, err
+	}
+	return entity.CurrentStatus() == fulfillment.StatusCompleted, nil
+}
+
+// Accept atribui a tarefa ao operador do contexto
+func (uc *WarehouseTaskUseCase) Accept(ctx context.Context, id, device string) (*fulfillment.WarehouseTask, error) {
+	return uc.apply(ctx, id, func(task *fulfillment.WarehouseTask, operator string) error {
+		workable, err := uc.entityWorkable(ctx, task)
+		if err != nil {
+			return err
+		}
+		if !workable {
+			return fmt.Errorf("%w: %s %s is not workable", fulfillment.ErrInvalidStateTransition, task.EntityType, task.EntityID)
+		}
+		return task.Accept(operator, device)
+	})
+}
+
+// Start inicia a tarefa; a separação (PICK) reserva e inicia o picking da ordem
+func (uc *WarehouseTaskUseCase) Start(ctx context.Context, id string) (*fulfillment.WarehouseTask, error) {
+	return uc.apply(ctx, id, func(task *fulfillment.WarehouseTask, operator string) error {
+		if err := task.Start(operator); err != nil {
+			return err
+		}
+		if task.Type != fulfillment.TaskPick {
+			return nil
+		}
+		order, err := uc.repo.GetOrderByID(ctx, task.EntityID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if order.Status != fulfillment.StatusPending {
+			return nil
+		}
+		return uc.shipOrder.StartPicking(ctx, task.EntityID)
+	})
+}
+
+// Complete conclui a tarefa executando a operação (expedição, recebimento, transferência ou contagem).
+// counted é obrigatório apenas para tarefas de contagem.
+func (uc *WarehouseTaskUseCase) Complete(ctx context.Context, id string, counted []fulfillment.Item) (*fulfillment.WarehouseTask, error) {
+	return uc.complete(ctx, id, nil, counted)
+}
+
+// CompletePick conclui a tarefa PICK expedindo apenas as quantidades separadas (separação parcial)
+func (uc *WarehouseTaskUseCase) CompletePick(ctx context.Context, id string, picked []fulfillment.Item) (*fulfillment.WarehouseTask, error) {
+	return uc.complete(ctx, id, picked, nil)
+}
+
+func (uc *WarehouseTaskUseCase) complete(ctx context.Context, id string, picked, counted []fulfillment.Item) (*fulfillment.WarehouseTask, error) {
+	task, err := uc.apply(ctx, id, func(task *fulfillment.WarehouseTask, operator string) error {
+		if err := task.Complete(operator); err != nil {
+			return err
+		}
+		done, err := uc.entityCompleted(ctx, task)
+		if err != nil {
+			return err
+		}
+		if done {
+			// Retry depois de a operação concluir sem a tarefa ser salva: só conclui a tarefa
+			return nil
+		}
+		switch task.Type {
+		case fulfillment.TaskPick:
+			return uc.shipOrder.ShipPicked(ctx, task.EntityID, picked)
+		case fulfillment.TaskPutaway:
+			return uc.receiveGoods.ConfirmReceipt(ctx, task.EntityID)
+		case fulfillment.TaskReplenish, fulfillment.TaskCrossDock, fulfillment.TaskSlotMove:
+			return uc.completeTransfer.CompleteTransfer(ctx, task.EntityID)
+		case fulfillment.TaskCount:
+			return uc.submitCycleCount.SubmitCycleCount(ctx, task.EntityID, counted)
+		default:
+			return fulfillment.ErrInvalidTaskType
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hook := range uc.afterComplete {
+		hook(ctx, task)
+	}
+	return task, nil
+}
+
+// Reprioritize altera a prioridade da tarefa pendente da operação (sem efeito se já aceita ou inexistente)
+func (uc *WarehouseTaskUseCase) Reprioritize(ctx context.Context, entityType, entityID string, priority int) error {
+	task, err := uc.tasks.GetTaskByEntity(ctx, entityType, entityID)
+	if errors.Is(err, fulfillment.ErrTaskNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get warehouse task: %w", err)
+	}
+	if task.Status != fulfillment.StatusPending || task.Priority == priority {
+		return nil
+	}
+
+	task.Priority = priority
+	if err := uc.tasks.UpdateTask(ctx, task); err != nil {
+		return fmt.Errorf("failed to update warehouse task: %w", err)
+	}
+	return nil
+}
+
+// Abandon devolve a tarefa à fila (reason é registrado no histórico de status)
+func (uc *WarehouseTaskUseCase) Abandon(ctx context.Context, id, reason string) (*fulfillment.WarehouseTask, error) {
+	if reason != "" {
+		ctx = fulfillment.WithReason(ctx, reason)
+	}
+	return uc.apply(ctx, id, func(task *fulfillment.WarehouseTask, operator string) error {
+		return task.Abandon(operator)
+	})
+}
+
+// apply carrega a tarefa, aplica a transição (e a operação associada) e persiste.
+// Se a operação falhar, a tarefa não é alterada.
+func (uc *WarehouseTaskUseCase) apply(ctx context.Context, id string, transition func(task *fulfillment.WarehouseTask, operator string) error) (*fulfillment.WarehouseTask, error) {
+	operator := fulfillment.ActorFromContext(ctx)
+	if operator == "" {
+		return nil, fulfillment.ErrTaskAssigneeRequired
+	}
+
+	task, err := uc.tasks.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse task: %w", err)
+	}
+	from := task.Status
+	if err := transition(task, operator); err != nil {
+		return nil, err
+	}
+	if err := uc.tasks.UpdateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update warehouse task: %w", err)
+	}
+
+	uc.logger.Info("Warehouse task updated", "id", task.ID, "from", from, "to", task.Status, "operator", operator)
+	return task, nil
+}
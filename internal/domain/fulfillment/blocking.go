@@ -0,0 +1,111 @@
+package fulfillment
+
+import (
+	"errors"
+	"time"
+)
+
+// BlockReason é o código estruturado do motivo de bloqueio
+type BlockReason string
+
+const (
+	BlockMissingStock    BlockReason = "MISSING_STOCK"    // Falta física de estoque no endereço
+	BlockDamagedLocation BlockReason = "DAMAGED_LOCATION" // Endereço/estrutura avariada
+	BlockCustomsHold     BlockReason = "CUSTOMS_HOLD"     // Retenção alfandegária
+	BlockQualityHold     BlockReason = "QUALITY_HOLD"     // Retenção pela qualidade
+	BlockOther           BlockReason = "OTHER"            // Exige nota explicativa
+)
+
+var (
+	ErrInvalidBlockReason = errors.New("invalid block reason code")
+	ErrBlockNoteRequired  = errors.New("block note is required for reason OTHER")
+	ErrNoActiveHold       = errors.New("operation has no active hold")
+)
+
+// IsValid indica se o código de motivo é conhecido
+func (r BlockReason) IsValid() bool {
+	switch r {
+	case BlockMissingStock, BlockDamagedLocation, BlockCustomsHold, BlockQualityHold, BlockOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsHold indica se o motivo é uma retenção (alfândega, qualidade), que só pode ser desbloqueada
+// depois de liberada
+func (r BlockReason) IsHold() bool {
+	return r == BlockCustomsHold || r == BlockQualityHold
+}
+
+// Block registra o bloqueio ativo de uma operação
+type Block struct {
+	Reason         BlockReason `json:"reason"`
+	Note           string      `json:"note,omitempty"`
+	BlockedBy      string      `json:"blocked_by,omitempty"`
+	BlockedAt      time.Time   `json:"blocked_at"`
+	PreviousStatus Status      `json:"previous_status"` // Status restaurado no desbloqueio
+	EscalatedAt    *time.Time  `json:"escalated_at,omitempty"`
+	ReleasedBy     string      `json:"released_by,omitempty"` // Liberação da retenção
+	ReleasedAt     *time.Time  `json:"released_at,omitempty"`
+}
+
+// NewBlock valida o motivo e cria o registro de bloqueio
+func NewBlock(previous Status, reason BlockReason, note, actor string) (*Block, error) {
+	if !reason.IsValid() {
+		return nil, ErrInvalidBlockReason
+	}
+	if reason == BlockOther && note == "" {
+		return nil, ErrBlockNoteRequired
+	}
+	return &Block{
+		Reason:         reason,
+		Note:           note,
+		BlockedBy:      actor,
+		BlockedAt:      time.Now(),
+		PreviousStatus: previous,
+	}, nil
+}
+
+// ActiveHold indica se o bloqueio é uma retenção ainda não liberada
+func (b *Block) ActiveHold() bool {
+	return b != nil && b.Reason.IsHold() && b.ReleasedAt == nil
+}
+
+// releaseHold libera a retenção, permitindo o desbloqueio
+func (b *Block) releaseHold(actor string) error {
+	if !b.ActiveHold() {
+		return ErrNoActiveHold
+	}
+	now := time.Now()
+	b.ReleasedBy, b.ReleasedAt = actor, &now
+	return nil
+}
+
+// unblockEvent escolhe o evento que devolve a operação ao status anterior ao bloqueio
+func (b *Block) unblockEvent() string {
+	if b != nil && b.PreviousStatus == StatusPending {
+		return EventRequeue
+	}
+	return EventUnblock
+}
+
+// BlockedFor retorna há quanto tempo a operação está bloqueada
+func (b *Block) BlockedFor(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	return now.Sub(b.BlockedAt)
+}
+
+// IsWorkable indica se a operação pode entrar em filas de trabalho (não bloqueada nem finalizada)
+func (s Status) IsWorkable() bool {
+	return s == StatusPending || s == StatusInProgress
+}
+
+// BlockedEntity identifica uma operação bloqueada (consulta e escalonamento)
+type BlockedEntity struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Block      *Block `json:"block"`
+}
@@ -0,0 +1,112 @@
+package fulfillment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const (
+	correlationIDKey contextKey = "fulfillment.correlation_id"
+	actorKey         contextKey = "fulfillment.actor"
+	sourceKey        contextKey = "fulfillment.source"
+	reasonKey        contextKey = "fulfillment.reason"
+	commandIDKey     contextKey = "fulfillment.command_id"
+)
+
+// Origens de uma operação, registradas no histórico de status
+const (
+	SourceHTTP      = "http"
+	SourceNATS      = "nats"
+	SourceScheduler = "scheduler"
+)
+
+// WithCorrelationID anexa um ID de correlação ao contexto da operação
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	if correlationID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIDKey, correlationID)
+}
+
+// CorrelationIDFromContext retorna o ID de correlação do contexto (vazio se ausente)
+func CorrelationIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// EnsureCorrelationID garante que o contexto carregue um ID de correlação, gerando um se necessário
+func EnsureCorrelationID(ctx context.Context) (context.Context, string) {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := uuid.New().String()
+	return WithCorrelationID(ctx, id), id
+}
+
+// WithActor anexa ao contexto quem executa a operação (usuário, serviço ou job)
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext retorna o ator da operação (vazio se ausente)
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithSource anexa ao contexto a origem da operação (SourceHTTP, SourceNATS, SourceScheduler)
+func WithSource(ctx context.Context, source string) context.Context {
+	if source == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, sourceKey, source)
+}
+
+// SourceFromContext retorna a origem da operação (vazio se ausente)
+func SourceFromContext(ctx context.Context) string {
+	source, _ := ctx.Value(sourceKey).(string)
+	return source
+}
+
+// WithReason anexa ao contexto o motivo da transição (ex: motivo de cancelamento)
+func WithReason(ctx context.Context, reason string) context.Context {
+	if reason == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, reasonKey, reason)
+}
+
+// ReasonFromContext retorna o motivo da transição (vazio se ausente)
+func ReasonFromContext(ctx context.Context) string {
+	reason, _ := ctx.Value(reasonKey).(string)
+	return reason
+}
+
+// CommandID monta o ID determinístico do comando de uma etapa no Core Inventory (entidade, etapa e item).
+// A reexecução da etapa envia o mesmo ID, e o Core descarta o comando já aplicado.
+func CommandID(entityID, step string, item int) string {
+	return fmt.Sprintf("%s:%s:%d", entityID, step, item)
+}
+
+// WithCommandID anexa ao contexto o ID do próximo comando enviado ao Core Inventory
+func WithCommandID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, commandIDKey, id)
+}
+
+// CommandIDFromContext retorna o ID do comando (vazio se ausente)
+func CommandIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(commandIDKey).(string)
+	return id
+}
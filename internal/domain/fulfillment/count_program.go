@@ -0,0 +1,306 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CountReason é o motivo de uma contagem planejada
+type CountReason string
+
+const (
+	CountScheduled       CountReason = "SCHEDULED"        // Frequência da classe ABC vencida
+	CountZeroStockPick   CountReason = "ZERO_STOCK_PICK"  // Separação zerou o saldo do endereço
+	CountNegativeBalance CountReason = "NEGATIVE_BALANCE" // Saldo negativo no Core Inventory
+	CountRecentVariance  CountReason = "RECENT_VARIANCE"  // Contagem anterior com divergência
+)
+
+var (
+	ErrInvalidCountProgram  = errors.New("invalid count program")
+	ErrCountProgramNotFound = errors.New("count program not found")
+	ErrInvalidCountTrigger  = errors.New("invalid count trigger")
+)
+
+// triggerRank ordena os gatilhos do mais grave para o menos grave
+var triggerRank = map[CountReason]int{
+	CountNegativeBalance: 0,
+	CountRecentVariance:  1,
+	CountZeroStockPick:   2,
+}
+
+// CountProgram define a frequência de contagem de cada classe ABC
+type CountProgram struct {
+	Frequencies   map[VelocityClass]int `json:"frequencies"`              // Dias entre contagens do endereço
+	LookbackDays  int                   `json:"lookback_days"`            // Janela do histórico de expedição para a classificação
+	DailyCapacity int                   `json:"daily_capacity,omitempty"` // Contagens programadas por dia (0 = derivada das frequências)
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// DefaultCountProgram conta endereços A mensalmente, B trimestralmente e C anualmente
+func DefaultCountProgram() *CountProgram {
+	return &CountProgram{
+		Frequencies:  map[VelocityClass]int{VelocityA: 30, VelocityB: 90, VelocityC: 365},
+		LookbackDays: 90,
+	}
+}
+
+// Validate exige frequência positiva para as três classes
+func (p *CountProgram) Validate() error {
+	for _, class := range []VelocityClass{VelocityA, VelocityB, VelocityC} {
+		if p.Frequencies[class] <= 0 {
+			return fmt.Errorf("%w: frequency for class %s must be positive", ErrInvalidCountProgram, class)
+		}
+	}
+	if p.LookbackDays <= 0 {
+		return fmt.Errorf("%w: lookback days must be positive", ErrInvalidCountProgram)
+	}
+	if p.DailyCapacity < 0 {
+		return fmt.Errorf("%w: daily capacity must not be negative", ErrInvalidCountProgram)
+	}
+	return nil
+}
+
+// CountTrigger pede uma contagem extra do SKU no endereço; TaskID é preenchido quando a contagem é gerada
+type CountTrigger struct {
+	ID        string      `json:"id"`
+	Location  string      `json:"location"`
+	SKU       string      `json:"sku"`
+	Reason    CountReason `json:"reason"`
+	Quantity  int         `json:"quantity"` // Saldo ou divergência observada
+	TaskID    string      `json:"task_id,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// NewCountTrigger cria um gatilho de contagem extra
+func NewCountTrigger(location, sku string, reason CountReason, quantity int) (*CountTrigger, error) {
+	if location == "" || sku == "" {
+		return nil, fmt.Errorf("%w: location and sku are required", ErrInvalidCountTrigger)
+	}
+	if _, ok := triggerRank[reason]; !ok {
+		return nil, fmt.Errorf("%w: reason %s", ErrInvalidCountTrigger, reason)
+	}
+	return &CountTrigger{
+		ID:        uuid.New().String(),
+		Location:  location,
+		SKU:       sku,
+		Reason:    reason,
+		Quantity:  quantity,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// LocationCountHistory resume as contagens de um endereço
+type LocationCountHistory struct {
+	Location      string     `json:"location"`
+	LastCountedAt *time.Time `json:"last_counted_at,omitempty"` // Última contagem concluída
+	LastOpenedAt  *time.Time `json:"last_opened_at,omitempty"`  // Última contagem aberta
+	Open          bool       `json:"open"`                      // Há contagem pendente ou em andamento
+}
+
+// CountCandidate é um endereço classificado pelo giro e pelo valor dos SKUs separados nele
+type CountCandidate struct {
+	Location      string        `json:"location"`
+	SKUs          []string      `json:"skus"`
+	Class         VelocityClass `json:"class"` // Melhor classe entre os SKUs do endereço
+	Picks         int           `json:"picks"`
+	Value         float64       `json:"value"` // Valor expedido a partir do endereço na janela
+	LastCountedAt *time.Time    `json:"last_counted_at,omitempty"`
+	DueAt         *time.Time    `json:"due_at,omitempty"` // Vazio = nunca contado
+}
+
+// CountPlanEntry é uma contagem do plano do dia
+type CountPlanEntry struct {
+	Location   string        `json:"location"`
+	SKUs       []string      `json:"skus"`
+	Class      VelocityClass `json:"class,omitempty"`
+	Reason     CountReason   `json:"reason"`
+	TriggerIDs []string      `json:"trigger_ids,omitempty"`
+	TaskID     string        `json:"task_id,omitempty"`
+}
+
+// CountPlan é o conjunto de contagens gerado para um dia
+type CountPlan struct {
+	Date    time.Time        `json:"date"`
+	Quota   int              `json:"quota"` // Contagens programadas por dia
+	Entries []CountPlanEntry `json:"entries"`
+}
+
+// ClassifyCountLocations classifica cada endereço do histórico pela melhor classe de seus SKUs.
+// A classe do SKU é a melhor entre a curva de separações e a curva de valor expedido (unidades x valor unitário).
+func ClassifyCountLocations(orders []*FulfillmentOrder, unitValues map[string]float64, days float64) []CountCandidate {
+	velocities := AnalyzeVelocity(orders, nil, days)
+	skuClass := make(map[string]VelocityClass, len(velocities))
+	skuValue := make(map[string]float64, len(velocities))
+	totalValue := 0.0
+	for _, velocity := range velocities {
+		skuClass[velocity.SKU] = velocity.Class
+		skuValue[velocity.SKU] = float64(velocity.Units) * unitValues[velocity.SKU]
+		totalValue += skuValue[velocity.SKU]
+	}
+
+	if totalValue > 0 {
+		byValue := make([]string, 0, len(skuValue))
+		for sku := range skuValue {
+			byValue = append(byValue, sku)
+		}
+		sort.Slice(byValue, func(i, j int) bool {
+			if skuValue[byValue[i]] != skuValue[byValue[j]] {
+				return skuValue[byValue[i]] > skuValue[byValue[j]]
+			}
+			return byValue[i] < byValue[j]
+		})
+		cumulative := 0.0
+		for _, sku := range byValue {
+			if class := abcClass(cumulative / totalValue); class < skuClass[sku] {
+				skuClass[sku] = class
+			}
+			cumulative += skuValue[sku]
+		}
+	}
+
+	byLocation := make(map[string]*CountCandidate)
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if item.Location == "" {
+				continue
+			}
+			candidate, ok := byLocation[item.Location]
+			if !ok {
+				candidate = &CountCandidate{Location: item.Location, Class: VelocityC}
+				byLocation[item.Location] = candidate
+			}
+			if !containsString(candidate.SKUs, item.SKU) {
+				candidate.SKUs = append(candidate.SKUs, item.SKU)
+			}
+			if skuClass[item.SKU] < candidate.Class {
+				candidate.Class = skuClass[item.SKU]
+			}
+			candidate.Picks++
+			candidate.Value += float64(item.Quantity) * unitValues[item.SKU]
+		}
+	}
+
+	candidates := make([]CountCandidate, 0, len(byLocation))
+	for _, candidate := range byLocation {
+		sort.Strings(candidate.SKUs)
+		candidates = append(candidates, *candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Location < candidates[j].Location })
+	return candidates
+}
+
+// DailyQuota é a capacidade configurada ou, sem ela, a média diária de contagens exigida pelas frequências
+func (p *CountProgram) DailyQuota(candidates []CountCandidate) int {
+	if p.DailyCapacity > 0 {
+		return p.DailyCapacity
+	}
+	perDay := 0.0
+	for _, candidate := range candidates {
+		perDay += 1 / float64(p.Frequencies[candidate.Class])
+	}
+	return int(math.Ceil(perDay))
+}
+
+// Plan monta as contagens do dia: todos os gatilhos pendentes e, dentro da cota, os endereços com
+// frequência vencida (nunca contados e mais atrasados primeiro). Endereços com contagem aberta ficam de fora
+// e as contagens já abertas no dia consomem a cota.
+func (p *CountProgram) Plan(candidates []CountCandidate, history []LocationCountHistory, triggers []*CountTrigger, now time.Time) *CountPlan {
+	byLocation := make(map[string]LocationCountHistory, len(history))
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	plan := &CountPlan{Date: startOfDay, Quota: p.DailyQuota(candidates), Entries: []CountPlanEntry{}}
+	remaining := plan.Quota
+	for _, entry := range history {
+		byLocation[entry.Location] = entry
+		if entry.LastOpenedAt != nil && !entry.LastOpenedAt.Before(startOfDay) {
+			remaining--
+		}
+	}
+
+	classes := make(map[string]CountCandidate, len(candidates))
+	for _, candidate := range candidates {
+		classes[candidate.Location] = candidate
+	}
+
+	planned := make(map[string]int) // Endereço -> índice da entrada no plano
+	for _, trigger := range triggers {
+		if byLocation[trigger.Location].Open {
+			continue
+		}
+		idx, ok := planned[trigger.Location]
+		if !ok {
+			candidate := classes[trigger.Location]
+			plan.Entries = append(plan.Entries, CountPlanEntry{
+				Location: trigger.Location,
+				SKUs:     append([]string(nil), candidate.SKUs...),
+				Class:    candidate.Class,
+				Reason:   trigger.Reason,
+			})
+			idx = len(plan.Entries) - 1
+			planned[trigger.Location] = idx
+		}
+		entry := &plan.Entries[idx]
+		if triggerRank[trigger.Reason] < triggerRank[entry.Reason] {
+			entry.Reason = trigger.Reason
+		}
+		if !containsString(entry.SKUs, trigger.SKU) {
+			entry.SKUs = append(entry.SKUs, trigger.SKU)
+		}
+		entry.TriggerIDs = append(entry.TriggerIDs, trigger.ID)
+	}
+
+	var due []CountCandidate
+	for _, candidate := range candidates {
+		counted := byLocation[candidate.Location]
+		if _, ok := planned[candidate.Location]; ok || counted.Open {
+			continue
+		}
+		candidate.LastCountedAt = counted.LastCountedAt
+		if counted.LastCountedAt != nil {
+			dueAt := counted.LastCountedAt.AddDate(0, 0, p.Frequencies[candidate.Class])
+			if dueAt.After(now) {
+				continue
+			}
+			candidate.DueAt = &dueAt
+		}
+		due = append(due, candidate)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		if (due[i].DueAt == nil) != (due[j].DueAt == nil) {
+			return due[i].DueAt == nil
+		}
+		if due[i].DueAt != nil && !due[i].DueAt.Equal(*due[j].DueAt) {
+			return due[i].DueAt.Before(*due[j].DueAt)
+		}
+		if due[i].Class != due[j].Class {
+			return due[i].Class < due[j].Class
+		}
+		return due[i].Value > due[j].Value
+	})
+	for _, candidate := range due {
+		if remaining <= 0 {
+			break
+		}
+		plan.Entries = append(plan.Entries, CountPlanEntry{
+			Location: candidate.Location,
+			SKUs:     candidate.SKUs,
+			Class:    candidate.Class,
+			Reason:   CountScheduled,
+		})
+		remaining--
+	}
+	return plan
+}
+
+func containsString(values []string, value string) bool {
+	for _, current := range values {
+		if current == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CrossDockPriorityBoost eleva a prioridade da tarefa de cross-dock sobre a da ordem:
+// a mercadoria ocupa a doca até ser levada à área de expedição
+const CrossDockPriorityBoost = 2
+
+var (
+	ErrCrossDockPolicyNotFound     = errors.New("cross-dock policy not found")
+	ErrCrossDockAllocationNotFound = errors.New("cross-dock allocation not found")
+	ErrInvalidCrossDockPolicy      = errors.New("invalid cross-dock policy")
+	ErrCrossDockNotAllowed         = errors.New("order cannot receive cross-dock allocation")
+)
+
+// CrossDockPolicy define se o recebimento de um SKU pode ser destinado diretamente às ordens
+// de um cliente. Customer ou SKU vazios valem para todos; a política mais específica prevalece.
+type CrossDockPolicy struct {
+	ID              string    `json:"id"`
+	Customer        string    `json:"customer,omitempty"`
+	SKU             string    `json:"sku,omitempty"`
+	Enabled         bool      `json:"enabled"`
+	StagingLocation string    `json:"staging_location"` // Área de expedição de destino
+	IncludePending  bool      `json:"include_pending"`  // Também atende ordens pendentes sem reserva (além de backorders)
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewCrossDockPolicy cria e valida uma política de cross-dock
+func NewCrossDockPolicy(customer, sku, stagingLocation string, enabled, includePending bool) (*CrossDockPolicy, error) {
+	now := time.Now()
+	policy := &CrossDockPolicy{
+		ID:              uuid.New().String(),
+		Customer:        customer,
+		SKU:             sku,
+		Enabled:         enabled,
+		StagingLocation: stagingLocation,
+		IncludePending:  includePending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate verifica a consistência da política
+func (p *CrossDockPolicy) Validate() error {
+	if p.Enabled && p.StagingLocation == "" {
+		return fmt.Errorf("%w: staging_location is required when enabled", ErrInvalidCrossDockPolicy)
+	}
+	return nil
+}
+
+// Matches indica se a política se aplica ao cliente e SKU
+func (p *CrossDockPolicy) Matches(customer, sku string) bool {
+	return (p.Customer == "" || p.Customer == customer) && (p.SKU == "" || p.SKU == sku)
+}
+
+// specificity ordena as políticas: cliente+SKU > cliente > SKU > padrão
+func (p *CrossDockPolicy) specificity() int {
+	score := 0
+	if p.Customer != "" {
+		score += 2
+	}
+	if p.SKU != "" {
+		score++
+	}
+	return score
+}
+
+// ResolveCrossDockPolicy retorna a política mais específica para o cliente e SKU (nil = sem cross-dock)
+func ResolveCrossDockPolicy(policies []*CrossDockPolicy, customer, sku string) *CrossDockPolicy {
+	var resolved *CrossDockPolicy
+	for _, policy := range policies {
+		if policy.Matches(customer, sku) && (resolved == nil || policy.specificity() > resolved.specificity()) {
+			resolved = policy
+		}
+	}
+	return resolved
+}
+
+// CrossDockAllocation destina parte de um recebimento a uma linha de ordem de saída,
+// movimentada da doca para a área de expedição por uma transferência
+type CrossDockAllocation struct {
+	ID         string    `json:"id"`
+	ShipmentID string    `json:"shipment_id"`
+	OrderID    string    `json:"order_id"` // ID interno da FulfillmentOrder
+	Line       int       `json:"line"`     // Índice do item da ordem no planejamento
+	SKU        string    `json:"sku"`
+	Batch      string    `json:"batch,omitempty"`
+	Quantity   int       `json:"quantity"`
+	From       string    `json:"from"` // Doca (destino do recebimento)
+	To         string    `json:"to"`   // Área de expedição
+	TransferID string    `json:"transfer_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PlanCrossDock distribui os itens recebidos entre as ordens aguardando os mesmos SKUs, por prioridade
+// e antiguidade, conforme a política de cada cliente e SKU. O que não for alocado segue para armazenagem.
+func PlanCrossDock(shipment *InboundShipment, orders []*FulfillmentOrder, policies []*CrossDockPolicy) []CrossDockAllocation {
+	remaining := append([]Item(nil), shipment.Items...)
+
+	candidates := append([]*FulfillmentOrder(nil), orders...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+
+	now := time.Now()
+	var allocations []CrossDockAllocation
+	for _, order := range candidates {
+		if order.CrossDockAllowed() != nil {
+			continue
+		}
+		for line, item := range order.Items {
+			policy := ResolveCrossDockPolicy(policies, order.Customer, item.SKU)
+			if policy == nil || !policy.Enabled || item.Location == policy.StagingLocation {
+				continue
+			}
+			if order.ReservationStatus != ReservationBackordered && !policy.IncludePending {
+				continue
+			}
+
+			needed := item.Quantity
+			for idx := range remaining {
+				received := &remaining[idx]
+				if needed == 0 {
+					break
+				}
+				if received.SKU != item.SKU || received.Quantity == 0 || (item.Batch != "" && item.Batch != received.Batch) {
+					continue
+				}
+				quantity := needed
+				if received.Quantity < quantity {
+					quantity = received.Quantity
+				}
+				received.Quantity -= quantity
+				needed -= quantity
+				allocations = append(allocations, CrossDockAllocation{
+					ID:         uuid.New().String(),
+					ShipmentID: shipment.ID,
+					OrderID:    order.ID,
+					Line:       line,
+					SKU:        item.SKU,
+					Batch:      received.Batch,
+					Quantity:   quantity,
+					From:       shipment.Destination,
+					To:         policy.StagingLocation,
+					CreatedAt:  now,
+				})
+			}
+		}
+	}
+	return allocations
+}
+
+// CrossDockAllowed indica se a ordem ainda pode receber cross-dock (pendente e sem reserva ativa)
+func (f *FulfillmentOrder) CrossDockAllowed() error {
+	if f.Status != StatusPending || f.HoldsReservation() || f.ReservationStatus == ReservationConfirmed {
+		return ErrCrossDockNotAllowed
+	}
+	return nil
+}
+
+// StageCrossDock passa a quantidade alocada da linha para a área de expedição. Alocações parciais
+// dividem a linha: a nova linha é acrescentada ao final para manter os índices das demais.
+func (f *FulfillmentOrder) StageCrossDock(line, quantity int, location, batch string) error {
+	if err := f.CrossDockAllowed(); err != nil {
+		return err
+	}
+	if line < 0 || line >= len(f.Items) || quantity <= 0 || quantity > f.Items[line].Quantity {
+		return fmt.Errorf("%w: invalid line %d or quantity %d", ErrCrossDockNotAllowed, line, quantity)
+	}
+
+	item := &f.Items[line]
+	if quantity == item.Quantity {
+		item.Location, item.Batch = location, batch
+	} else {
+		// A divisão em unidades base desfaz a embalagem declarada da linha
+		item.Quantity -= quantity
+		item.PackUoM, item.PackQuantity = "", 0
+		f.Items = append(f.Items, Item{SKU: item.SKU, Quantity: quantity, Batch: batch, Location: location, UoM: item.UoM})
+	}
+	f.UpdatedAt = time.Now()
+	return nil
+}
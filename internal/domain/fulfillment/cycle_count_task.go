@@ -18,6 +18,8 @@ type CycleCountTask struct {
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	Failure        *Failure   `json:"failure,omitempty"` // Última falha (etapa, causa, tentativa)
+	Blocked        *Block     `json:"blocked,omitempty"` // Bloqueio ativo (status BLOCKED)
 }
 
 // NewCycleCountTask cria uma nova instância de CycleCountTask
@@ -41,12 +43,7 @@ func NewCycleCountTask(location string, skus []string) (*CycleCountTask, error)
 
 // StartCounting inicia a contagem física
 func (c *CycleCountTask) StartCounting() error {
-	if c.Status != StatusPending {
-		return ErrInvalidStateTransition
-	}
-	c.Status = StatusInProgress
-	c.UpdatedAt = time.Now()
-	return nil
+	return Fire(c, EventStart)
 }
 
 // SubmitCount registra os itens contados
@@ -61,22 +58,74 @@ func (c *CycleCountTask) SubmitCount(items []Item) error {
 
 // Complete finaliza a contagem
 func (c *CycleCountTask) Complete() error {
-	if c.Status != StatusInProgress {
-		return ErrInvalidStateTransition
-	}
-	now := time.Now()
-	c.Status = StatusCompleted
-	c.UpdatedAt = now
-	c.CompletedAt = &now
-	return nil
+	return Fire(c, EventComplete)
 }
 
 // Cancel cancela a tarefa de contagem
 func (c *CycleCountTask) Cancel() error {
-	if c.Status == StatusCompleted {
-		return ErrInvalidStateTransition
+	return Fire(c, EventCancel)
+}
+
+// Fail marca a tarefa de contagem como falha, registrando etapa, item e causa
+func (c *CycleCountTask) Fail(step string, item int, cause error) error {
+	previous := c.Failure
+	c.Failure = NewFailure(step, item, cause, previous)
+	if err := Fire(c, EventFail); err != nil {
+		c.Failure = previous
+		return err
+	}
+	return nil
+}
+
+// Retry devolve a tarefa de contagem para PENDING mantendo a falha como ponto de retomada
+func (c *CycleCountTask) Retry() error {
+	return Fire(c, EventRetry)
+}
+
+// Block bloqueia a tarefa de contagem com um código de motivo (retirando-a das filas de trabalho)
+func (c *CycleCountTask) Block(reason BlockReason, note, actor string) error {
+	block, err := NewBlock(c.Status, reason, note, actor)
+	if err != nil {
+		return err
+	}
+	previous := c.Blocked
+	c.Blocked = block
+	if err := Fire(c, EventBlock); err != nil {
+		c.Blocked = previous
+		return err
 	}
-	c.Status = StatusCancelled
-	c.UpdatedAt = time.Now()
 	return nil
 }
+
+// Unblock devolve a tarefa de contagem ao status anterior ao bloqueio
+func (c *CycleCountTask) Unblock() error {
+	return Fire(c, c.Blocked.unblockEvent())
+}
+
+// ReleaseHold libera a retenção (alfândega, qualidade) que mantém a tarefa de contagem bloqueada
+func (c *CycleCountTask) ReleaseHold(actor string) error {
+	return c.Blocked.releaseHold(actor)
+}
+
+// HasActiveHolds indica retenção ainda não liberada (guard da máquina de estados)
+func (c *CycleCountTask) HasActiveHolds() bool { return c.Blocked.ActiveHold() }
+
+// CurrentStatus retorna o status atual (máquina de estados)
+func (c *CycleCountTask) CurrentStatus() Status { return c.Status }
+
+// Operation retorna o tipo de operação que governa as transições
+func (c *CycleCountTask) Operation() OperationType { return OpCycleCount }
+
+// OpenedAt retorna o início do SLA da operação
+func (c *CycleCountTask) OpenedAt() time.Time { return c.CreatedAt }
+
+func (c *CycleCountTask) applyStatus(to Status, at time.Time) {
+	c.Status = to
+	c.UpdatedAt = at
+	if to != StatusBlocked {
+		c.Blocked = nil
+	}
+	if to == StatusCompleted {
+		c.CompletedAt = &at
+	}
+}
@@ -0,0 +1,186 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncOutcome é o resultado de uma operação enviada por um coletor na sincronização
+type SyncOutcome string
+
+const (
+	SyncApplied   SyncOutcome = "APPLIED"   // Mais nova que o estado do servidor: aplicada
+	SyncMerged    SyncOutcome = "MERGED"    // Concorrente, combinada sem divergência com o estado do servidor
+	SyncStale     SyncOutcome = "STALE"     // Superada por uma operação já sincronizada
+	SyncDuplicate SyncOutcome = "DUPLICATE" // Já sincronizada (reenvio)
+	SyncRejected  SyncOutcome = "REJECTED"  // Recusada pela operação de fulfillment (ex: endereço errado)
+	SyncConflict  SyncOutcome = "CONFLICT"  // Concorrente e divergente: aguarda revisão do supervisor
+)
+
+// SyncChoice é a decisão do supervisor sobre um conflito de sincronização
+type SyncChoice string
+
+const (
+	SyncKeepServer SyncChoice = "SERVER" // Mantém o estado já sincronizado e descarta a operação do coletor
+	SyncTakeDevice SyncChoice = "DEVICE" // Aplica a operação do coletor
+)
+
+var (
+	ErrInvalidSyncOperation = errors.New("invalid sync operation")
+	ErrUnsupportedSyncKind  = errors.New("unsupported sync operation kind")
+	ErrSyncStateNotFound    = errors.New("sync state not found")
+	ErrSyncStateConflict    = errors.New("sync state was modified concurrently")
+	ErrSyncConflictNotFound = errors.New("sync conflict not found")
+	ErrSyncConflictResolved = errors.New("sync conflict is already resolved")
+	ErrInvalidSyncChoice    = errors.New("invalid sync conflict choice")
+)
+
+// VectorClock conta as alterações de cada coletor sobre uma chave
+type VectorClock map[string]uint64
+
+// Merge retorna o relógio com o maior contador de cada coletor
+func (c VectorClock) Merge(other VectorClock) VectorClock {
+	merged := make(VectorClock, len(c)+len(other))
+	for node, counter := range c {
+		merged[node] = counter
+	}
+	for node, counter := range other {
+		if counter > merged[node] {
+			merged[node] = counter
+		}
+	}
+	return merged
+}
+
+// SyncOperation é uma alteração registrada offline pelo coletor. Key identifica o registro alterado
+// (ex: pick_session/<id>/<passo>/location) e Kind a operação de fulfillment que a aplica.
+type SyncOperation struct {
+	ID         string                 `json:"id"`
+	DeviceID   string                 `json:"device_id"`
+	Key        string                 `json:"key"`
+	Kind       string                 `json:"kind"`
+	Payload    map[string]interface{} `json:"payload"`
+	Clock      VectorClock            `json:"clock"`
+	RecordedAt time.Time              `json:"recorded_at"` // Instante da leitura no coletor
+}
+
+// Validate verifica os campos obrigatórios; o relógio deve conter o contador do próprio coletor
+func (o *SyncOperation) Validate() error {
+	switch {
+	case o.ID == "":
+		return fmt.Errorf("%w: id is required", ErrInvalidSyncOperation)
+	case o.DeviceID == "":
+		return fmt.Errorf("%w: device_id is required", ErrInvalidSyncOperation)
+	case o.Key == "":
+		return fmt.Errorf("%w: key is required", ErrInvalidSyncOperation)
+	case o.Kind == "":
+		return fmt.Errorf("%w: kind is required", ErrInvalidSyncOperation)
+	case o.Clock[o.DeviceID] == 0:
+		return fmt.Errorf("%w: clock has no counter for device %s", ErrInvalidSyncOperation, o.DeviceID)
+	}
+	return nil
+}
+
+// SyncState é o último valor sincronizado de uma chave e o relógio que o produziu
+type SyncState struct {
+	Key       string                 `json:"key"`
+	Kind      string                 `json:"kind"`
+	Value     map[string]interface{} `json:"value"`
+	Clock     VectorClock            `json:"clock"`
+	DeviceID  string                 `json:"device_id"` // Último coletor a alterar a chave
+	UpdatedAt time.Time              `json:"updated_at"`
+	Version   int64                  `json:"version"` // Controle de concorrência otimista (0 = nova)
+}
+
+// NewSyncState cria o estado da chave a partir da primeira operação sincronizada
+func NewSyncState(op *SyncOperation) *SyncState {
+	return &SyncState{
+		Key:       op.Key,
+		Kind:      op.Kind,
+		Value:     op.Payload,
+		Clock:     VectorClock{}.Merge(op.Clock),
+		DeviceID:  op.DeviceID,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Advance registra o novo valor da chave, combinando o relógio da operação
+func (s *SyncState) Advance(value map[string]interface{}, op *SyncOperation) {
+	s.Value = value
+	s.Clock = s.Clock.Merge(op.Clock)
+	s.DeviceID = op.DeviceID
+	s.UpdatedAt = time.Now()
+}
+
+// SyncResult informa ao coletor o destino de cada operação enviada
+type SyncResult struct {
+	OperationID string      `json:"operation_id"`
+	Key         string      `json:"key"`
+	Outcome     SyncOutcome `json:"outcome"`
+	Error       string      `json:"error,omitempty"`
+	ConflictID  string      `json:"conflict_id,omitempty"`
+	Clock       VectorClock `json:"clock,omitempty"` // Relógio da chave no servidor após a operação
+}
+
+// SyncConflictRecord é uma operação concorrente que o resolvedor não conseguiu combinar,
+// na fila de revisão do supervisor
+type SyncConflictRecord struct {
+	ID         string        `json:"id"`
+	Key        string        `json:"key"`
+	Kind       string        `json:"kind"`
+	Server     SyncState     `json:"server"` // Estado sincronizado no momento do conflito
+	Device     SyncOperation `json:"device"` // Operação recebida do coletor
+	Fields     []string      `json:"fields,omitempty"`
+	Reason     string        `json:"reason"`
+	Status     Status        `json:"status"` // PENDING até a decisão, depois COMPLETED
+	Choice     SyncChoice    `json:"choice,omitempty"`
+	ResolvedBy string        `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time    `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// NewSyncConflictRecord registra o conflito entre o estado do servidor e a operação do coletor
+func NewSyncConflictRecord(server *SyncState, op *SyncOperation, fields []string, reason string) *SyncConflictRecord {
+	return &SyncConflictRecord{
+		ID:        uuid.New().String(),
+		Key:       op.Key,
+		Kind:      op.Kind,
+		Server:    *server,
+		Device:    *op,
+		Fields:    fields,
+		Reason:    reason,
+		Status:    StatusPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Resolve registra a decisão do supervisor
+func (c *SyncConflictRecord) Resolve(choice SyncChoice, actor string) error {
+	if c.Status != StatusPending {
+		return ErrSyncConflictResolved
+	}
+	if choice != SyncKeepServer && choice != SyncTakeDevice {
+		return fmt.Errorf("%w: %s", ErrInvalidSyncChoice, choice)
+	}
+	now := time.Now()
+	c.Status, c.Choice, c.ResolvedBy, c.ResolvedAt = StatusCompleted, choice, actor, &now
+	return nil
+}
+
+// DivergentFields lista os campos presentes nos dois valores com conteúdos diferentes,
+// que uma combinação campo a campo sobrescreveria
+func DivergentFields(server, device map[string]interface{}) []string {
+	var fields []string
+	for field, value := range device {
+		if current, ok := server[field]; ok && !reflect.DeepEqual(current, value) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
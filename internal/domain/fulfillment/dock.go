@@ -0,0 +1,366 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DockDirection indica o tipo de operação atendida pela porta de doca
+type DockDirection string
+
+const (
+	DockInbound  DockDirection = "INBOUND"
+	DockOutbound DockDirection = "OUTBOUND"
+	DockBoth     DockDirection = "BOTH"
+)
+
+// AppointmentStatus é o ciclo de vida de um agendamento de doca
+type AppointmentStatus string
+
+const (
+	AppointmentScheduled AppointmentStatus = "SCHEDULED"
+	AppointmentArrived   AppointmentStatus = "ARRIVED"
+	AppointmentCompleted AppointmentStatus = "COMPLETED"
+	AppointmentCancelled AppointmentStatus = "CANCELLED"
+	AppointmentNoShow    AppointmentStatus = "NO_SHOW"
+)
+
+// Tipos de remessa vinculáveis a um agendamento
+const (
+	EntityOutboundShipment = "outbound_shipment"
+)
+
+var (
+	ErrDockDoorNotFound      = errors.New("dock door not found")
+	ErrInvalidDockDoor       = errors.New("invalid dock door")
+	ErrAppointmentNotFound   = errors.New("dock appointment not found")
+	ErrInvalidAppointment    = errors.New("invalid dock appointment")
+	ErrAppointmentConflict   = errors.New("dock appointment conflicts with another booking")
+	ErrAppointmentChanged    = errors.New("dock appointment was modified concurrently")
+	ErrDockClosed            = errors.New("dock door is closed at the requested time")
+	ErrDockCapabilityMissing = errors.New("dock door lacks a required capability")
+)
+
+// DockHours é a janela de funcionamento da porta em um dia da semana ("HH:MM", fuso da porta)
+type DockHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Open    string       `json:"open"`
+	Close   string       `json:"close"`
+}
+
+// DockDoor é uma porta de doca com capacidades (ex: REFRIGERATED, LIFTGATE) e horário de funcionamento
+type DockDoor struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Direction    DockDirection `json:"direction"`
+	Capabilities []string      `json:"capabilities,omitempty"`
+	Hours        []DockHours   `json:"hours"`
+	SlotMinutes  int           `json:"slot_minutes"`       // Duração padrão de um slot
+	Timezone     string        `json:"timezone,omitempty"` // IANA; vazio = UTC
+	Active       bool          `json:"active"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// NewDockDoor cria e valida uma porta de doca
+func NewDockDoor(name string, direction DockDirection, capabilities []string, hours []DockHours, slotMinutes int, timezone string) (*DockDoor, error) {
+	if slotMinutes == 0 {
+		slotMinutes = 60
+	}
+	now := time.Now()
+	door := &DockDoor{
+		ID:           uuid.New().String(),
+		Name:         name,
+		Direction:    direction,
+		Capabilities: capabilities,
+		Hours:        hours,
+		SlotMinutes:  slotMinutes,
+		Timezone:     timezone,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := door.Validate(); err != nil {
+		return nil, err
+	}
+	return door, nil
+}
+
+// Validate verifica a consistência da porta
+func (d *DockDoor) Validate() error {
+	switch {
+	case d.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidDockDoor)
+	case d.Direction != DockInbound && d.Direction != DockOutbound && d.Direction != DockBoth:
+		return fmt.Errorf("%w: direction must be INBOUND, OUTBOUND or BOTH", ErrInvalidDockDoor)
+	case d.SlotMinutes <= 0:
+		return fmt.Errorf("%w: slot_minutes must be positive", ErrInvalidDockDoor)
+	}
+	if _, err := d.location(); err != nil {
+		return fmt.Errorf("%w: unknown timezone %s", ErrInvalidDockDoor, d.Timezone)
+	}
+	for _, h := range d.Hours {
+		open, errOpen := parseClock(h.Open)
+		closing, errClose := parseClock(h.Close)
+		if errOpen != nil || errClose != nil || h.Weekday < time.Sunday || h.Weekday > time.Saturday || closing <= open {
+			return fmt.Errorf("%w: invalid hours %v %s-%s", ErrInvalidDockDoor, h.Weekday, h.Open, h.Close)
+		}
+	}
+	return nil
+}
+
+// Supports indica se a porta atende a direção e possui todas as capacidades exigidas
+func (d *DockDoor) Supports(direction DockDirection, required []string) error {
+	if d.Direction != DockBoth && d.Direction != direction {
+		return fmt.Errorf("%w: door %s does not handle %s", ErrInvalidAppointment, d.Name, direction)
+	}
+	for _, capability := range required {
+		found := false
+		for _, own := range d.Capabilities {
+			if own == capability {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s", ErrDockCapabilityMissing, capability)
+		}
+	}
+	return nil
+}
+
+// IsOpen indica se o intervalo cabe inteiro em uma janela de funcionamento da porta
+func (d *DockDoor) IsOpen(start, end time.Time) bool {
+	loc, err := d.location()
+	if err != nil {
+		return false
+	}
+	start, end = start.In(loc), end.In(loc)
+	for _, window := range d.windows(start) {
+		if !start.Before(window[0]) && !end.After(window[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Slots retorna os slots livres da porta na data de day (interpretada no fuso da porta),
+// descontando os agendamentos ativos
+func (d *DockDoor) Slots(day time.Time, booked []*DockAppointment) []TimeSlot {
+	loc, err := d.location()
+	if err != nil {
+		return nil
+	}
+	step := time.Duration(d.SlotMinutes) * time.Minute
+
+	var slots []TimeSlot
+	for _, window := range d.windows(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)) {
+		for start := window[0]; !start.Add(step).After(window[1]); start = start.Add(step) {
+			slot := TimeSlot{Start: start, End: start.Add(step)}
+			free := true
+			for _, appointment := range booked {
+				if appointment.DoorID == d.ID && appointment.IsActive() && appointment.Overlaps(slot.Start, slot.End) {
+					free = false
+					break
+				}
+			}
+			if free {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	return slots
+}
+
+// windows retorna as janelas [abertura, fechamento] do dia da semana de day
+func (d *DockDoor) windows(day time.Time) [][2]time.Time {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	var windows [][2]time.Time
+	for _, h := range d.Hours {
+		if h.Weekday != day.Weekday() {
+			continue
+		}
+		open, _ := parseClock(h.Open)
+		closing, _ := parseClock(h.Close)
+		windows = append(windows, [2]time.Time{midnight.Add(open), midnight.Add(closing)})
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i][0].Before(windows[j][0]) })
+	return windows
+}
+
+func (d *DockDoor) location() (*time.Location, error) {
+	if d.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(d.Timezone)
+}
+
+// parseClock converte "HH:MM" em duração desde a meia-noite (aceita "24:00")
+func parseClock(clock string) (time.Duration, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil {
+		return 0, err
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("invalid clock %s", clock)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// TimeSlot é um intervalo reservável de uma porta
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// DockAppointment é a reserva de uma porta de doca para uma remessa de entrada ou saída
+type DockAppointment struct {
+	ID             string            `json:"id"`
+	DoorID         string            `json:"door_id"`
+	Direction      DockDirection     `json:"direction"`
+	ShipmentType   string            `json:"shipment_type"` // inbound_shipment ou outbound_shipment
+	ShipmentID     string            `json:"shipment_id"`
+	Supplier       string            `json:"supplier"` // Parceiro avaliado no scorecard (fornecedor ou transportadora)
+	Carrier        string            `json:"carrier,omitempty"`
+	ScheduledStart time.Time         `json:"scheduled_start"`
+	ScheduledEnd   time.Time         `json:"scheduled_end"`
+	Status         AppointmentStatus `json:"status"`
+	ArrivedAt      *time.Time        `json:"arrived_at,omitempty"`
+	CompletedAt    *time.Time        `json:"completed_at,omitempty"`
+	LateMinutes    int               `json:"late_minutes"` // Atraso na chegada além da tolerância
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// NewDockAppointment cria um agendamento para a remessa
+func NewDockAppointment(doorID string, direction DockDirection, shipmentType, shipmentID, supplier, carrier string, start, end time.Time) (*DockAppointment, error) {
+	switch {
+	case direction != DockInbound && direction != DockOutbound:
+		return nil, fmt.Errorf("%w: direction must be INBOUND or OUTBOUND", ErrInvalidAppointment)
+	case shipmentID == "":
+		return nil, fmt.Errorf("%w: shipment_id is required", ErrInvalidAppointment)
+	case !end.After(start):
+		return nil, fmt.Errorf("%w: end must be after start", ErrInvalidAppointment)
+	}
+
+	now := time.Now()
+	return &DockAppointment{
+		ID:             uuid.New().String(),
+		DoorID:         doorID,
+		Direction:      direction,
+		ShipmentType:   shipmentType,
+		ShipmentID:     shipmentID,
+		Supplier:       supplier,
+		Carrier:        carrier,
+		ScheduledStart: start,
+		ScheduledEnd:   end,
+		Status:         AppointmentScheduled,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// IsActive indica se o agendamento ainda ocupa a porta
+func (a *DockAppointment) IsActive() bool {
+	return a.Status == AppointmentScheduled || a.Status == AppointmentArrived
+}
+
+// Overlaps indica se o agendamento se sobrepõe ao intervalo
+func (a *DockAppointment) Overlaps(start, end time.Time) bool {
+	return a.ScheduledStart.Before(end) && start.Before(a.ScheduledEnd)
+}
+
+// CheckIn registra a chegada do veículo; chegadas além da tolerância contam como atraso
+func (a *DockAppointment) CheckIn(at time.Time, grace time.Duration) error {
+	if a.Status != AppointmentScheduled {
+		return fmt.Errorf("%w: appointment is %s", ErrInvalidStateTransition, a.Status)
+	}
+	a.Status, a.ArrivedAt, a.UpdatedAt = AppointmentArrived, &at, at
+	if late := at.Sub(a.ScheduledStart.Add(grace)); late > 0 {
+		a.LateMinutes = int(late.Minutes() + 0.5)
+	}
+	return nil
+}
+
+// Complete encerra o atendimento na doca, liberando a porta
+func (a *DockAppointment) Complete(at time.Time) error {
+	if a.Status != AppointmentArrived {
+		return fmt.Errorf("%w: appointment is %s", ErrInvalidStateTransition, a.Status)
+	}
+	a.Status, a.CompletedAt, a.UpdatedAt = AppointmentCompleted, &at, at
+	return nil
+}
+
+// Cancel cancela um agendamento ainda não atendido
+func (a *DockAppointment) Cancel(at time.Time) error {
+	if a.Status != AppointmentScheduled {
+		return fmt.Errorf("%w: appointment is %s", ErrInvalidStateTransition, a.Status)
+	}
+	a.Status, a.UpdatedAt = AppointmentCancelled, at
+	return nil
+}
+
+// MarkNoShow registra o não comparecimento se o prazo após o início agendado já passou
+func (a *DockAppointment) MarkNoShow(now time.Time, after time.Duration) bool {
+	if a.Status != AppointmentScheduled || now.Before(a.ScheduledStart.Add(after)) {
+		return false
+	}
+	a.Status, a.UpdatedAt = AppointmentNoShow, now
+	return true
+}
+
+// SupplierScorecard resume a pontualidade de um parceiro nos agendamentos de doca
+type SupplierScorecard struct {
+	Supplier           string  `json:"supplier"`
+	Appointments       int     `json:"appointments"` // Agendamentos encerrados (compareceu ou não)
+	OnTime             int     `json:"on_time"`
+	Late               int     `json:"late"`
+	NoShows            int     `json:"no_shows"`
+	AverageLateMinutes float64 `json:"average_late_minutes"` // Média entre as chegadas atrasadas
+	OnTimeRate         float64 `json:"on_time_rate"`         // OnTime / Appointments
+}
+
+// BuildScorecards calcula os scorecards por parceiro a partir dos agendamentos (ignora futuros e cancelados)
+func BuildScorecards(appointments []*DockAppointment) []SupplierScorecard {
+	bySupplier := make(map[string]*SupplierScorecard)
+	lateTotals := make(map[string]int)
+	for _, appointment := range appointments {
+		if appointment.Supplier == "" {
+			continue
+		}
+		score, ok := bySupplier[appointment.Supplier]
+		if !ok {
+			score = &SupplierScorecard{Supplier: appointment.Supplier}
+			bySupplier[appointment.Supplier] = score
+		}
+		switch {
+		case appointment.Status == AppointmentNoShow:
+			score.NoShows++
+		case appointment.ArrivedAt == nil:
+			continue
+		case appointment.LateMinutes > 0:
+			score.Late++
+			lateTotals[appointment.Supplier] += appointment.LateMinutes
+		default:
+			score.OnTime++
+		}
+		score.Appointments++
+	}
+
+	scorecards := make([]SupplierScorecard, 0, len(bySupplier))
+	for supplier, score := range bySupplier {
+		if score.Late > 0 {
+			score.AverageLateMinutes = float64(lateTotals[supplier]) / float64(score.Late)
+		}
+		if score.Appointments > 0 {
+			score.OnTimeRate = float64(score.OnTime) / float64(score.Appointments)
+		}
+		scorecards = append(scorecards, *score)
+	}
+	sort.Slice(scorecards, func(i, j int) bool { return scorecards[i].Supplier < scorecards[j].Supplier })
+	return scorecards
+}
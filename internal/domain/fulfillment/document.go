@@ -0,0 +1,362 @@
+package fulfillment
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentType é o tipo de documento impresso para uma ordem
+type DocumentType string
+
+const (
+	DocumentPackingSlip       DocumentType = "PACKING_SLIP"
+	DocumentReturnLabel       DocumentType = "RETURN_LABEL"
+	DocumentCommercialInvoice DocumentType = "COMMERCIAL_INVOICE"
+)
+
+// DocumentFormat é o formato de saída do documento
+type DocumentFormat string
+
+const (
+	DocumentHTML DocumentFormat = "html"
+	DocumentPDF  DocumentFormat = "pdf"
+	DocumentZPL  DocumentFormat = "zpl" // Etiquetas para impressoras térmicas Zebra
+)
+
+var (
+	ErrInvalidDocumentTemplate  = errors.New("invalid document template")
+	ErrDocumentTemplateNotFound = errors.New("document template not found")
+	ErrDocumentNotFound         = errors.New("document not found")
+)
+
+// IsValid verifica se o tipo de documento é conhecido
+func (t DocumentType) IsValid() bool {
+	switch t {
+	case DocumentPackingSlip, DocumentReturnLabel, DocumentCommercialInvoice:
+		return true
+	}
+	return false
+}
+
+// IsValid verifica se o formato é suportado
+func (f DocumentFormat) IsValid() bool {
+	switch f {
+	case DocumentHTML, DocumentPDF, DocumentZPL:
+		return true
+	}
+	return false
+}
+
+// ContentType é o tipo MIME do documento renderizado
+func (f DocumentFormat) ContentType() string {
+	switch f {
+	case DocumentPDF:
+		return "application/pdf"
+	case DocumentZPL:
+		return "application/zpl"
+	default:
+		return "text/html; charset=utf-8"
+	}
+}
+
+// DocumentTemplate é o modelo (text/template do Go) de um tipo de documento em um formato.
+// Customer vazio é o modelo padrão da operação. Modelos PDF produzem texto, paginado pelo renderizador.
+type DocumentTemplate struct {
+	ID        string         `json:"id"`
+	Customer  string         `json:"customer,omitempty"`
+	Type      DocumentType   `json:"type"`
+	Format    DocumentFormat `json:"format"`
+	Body      string         `json:"body"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// NewDocumentTemplate cria um modelo validando o tipo, o formato e a sintaxe do corpo
+func NewDocumentTemplate(customer string, docType DocumentType, format DocumentFormat, body string) (*DocumentTemplate, error) {
+	tmpl := &DocumentTemplate{
+		ID:        uuid.New().String(),
+		Customer:  customer,
+		Type:      docType,
+		Format:    format,
+		Body:      body,
+		UpdatedAt: time.Now(),
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Validate exige tipo e formato conhecidos e um corpo que compile
+func (t *DocumentTemplate) Validate() error {
+	if !t.Type.IsValid() {
+		return fmt.Errorf("%w: unknown document type %q", ErrInvalidDocumentTemplate, t.Type)
+	}
+	if !t.Format.IsValid() {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidDocumentTemplate, t.Format)
+	}
+	if strings.TrimSpace(t.Body) == "" {
+		return fmt.Errorf("%w: body is required", ErrInvalidDocumentTemplate)
+	}
+	if _, err := t.parse(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDocumentTemplate, err)
+	}
+	return nil
+}
+
+// parse compila o corpo: HTML com escape automático, ZPL e PDF como texto
+func (t *DocumentTemplate) parse() (func(*bytes.Buffer, *DocumentData) error, error) {
+	if t.Format == DocumentHTML {
+		tmpl, err := htmltemplate.New(string(t.Type)).Funcs(documentFuncs).Parse(t.Body)
+		if err != nil {
+			return nil, err
+		}
+		return func(buf *bytes.Buffer, data *DocumentData) error { return tmpl.Execute(buf, data) }, nil
+	}
+	tmpl, err := template.New(string(t.Type)).Funcs(documentFuncs).Parse(t.Body)
+	if err != nil {
+		return nil, err
+	}
+	return func(buf *bytes.Buffer, data *DocumentData) error { return tmpl.Execute(buf, data) }, nil
+}
+
+// Render executa o modelo com os dados da ordem
+func (t *DocumentTemplate) Render(data *DocumentData) ([]byte, error) {
+	execute, err := t.parse()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocumentTemplate, err)
+	}
+	var buf bytes.Buffer
+	if err := execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s %s: %w", t.Type, t.Format, err)
+	}
+	if t.Format == DocumentPDF {
+		return renderTextPDF(strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")), nil
+	}
+	return buf.Bytes(), nil
+}
+
+var documentFuncs = map[string]any{
+	"money": func(value float64) string { return fmt.Sprintf("%.2f", value) },
+	"date":  func(at time.Time) string { return at.Format("02/01/2006") },
+	// zpl remove os caracteres de controle ^ e ~ de um campo de etiqueta
+	"zpl": func(value string) string { return strings.NewReplacer("^", " ", "~", " ").Replace(value) },
+}
+
+// DocumentLine é uma linha de item do documento
+type DocumentLine struct {
+	SKU       string  `json:"sku"`
+	Batch     string  `json:"batch,omitempty"`
+	Quantity  int     `json:"quantity"`
+	UnitValue float64 `json:"unit_value"`
+	Value     float64 `json:"value"`
+}
+
+// DocumentOrder é a visão somente leitura da ordem exposta aos modelos (sem métodos de transição)
+type DocumentOrder struct {
+	ID          string
+	OrderID     string
+	Customer    string
+	Destination string
+	Status      Status
+	Priority    int
+	CreatedAt   time.Time
+	ShippedAt   *time.Time
+}
+
+// DocumentShipment é a visão somente leitura dos volumes entregues à transportadora
+type DocumentShipment struct {
+	TrackingNumber string
+	Carrier        string
+	Pieces         int
+	Weight         float64
+	ManifestID     string
+	ShippedAt      *time.Time
+}
+
+// DocumentData são os dados disponíveis aos modelos
+type DocumentData struct {
+	Order       DocumentOrder
+	Shipment    *DocumentShipment // Volumes entregues à transportadora (nil antes da entrega)
+	Lines       []DocumentLine
+	TotalUnits  int
+	TotalValue  float64
+	GeneratedAt time.Time
+}
+
+// NewDocumentData monta os dados do documento com o valor unitário de cada SKU
+func NewDocumentData(order *FulfillmentOrder, shipment *OutboundShipment, unitValues map[string]float64) *DocumentData {
+	data := &DocumentData{
+		Order: DocumentOrder{
+			ID:          order.ID,
+			OrderID:     order.OrderID,
+			Customer:    order.Customer,
+			Destination: order.Destination,
+			Status:      order.Status,
+			Priority:    order.Priority,
+			CreatedAt:   order.CreatedAt,
+			ShippedAt:   order.ShippedAt,
+		},
+		GeneratedAt: time.Now(),
+	}
+	if shipment != nil {
+		data.Shipment = &DocumentShipment{
+			TrackingNumber: shipment.TrackingNumber,
+			Carrier:        shipment.Carrier,
+			Pieces:         shipment.Pieces,
+			Weight:         shipment.Weight,
+			ManifestID:     shipment.ManifestID,
+			ShippedAt:      shipment.ShippedAt,
+		}
+	}
+	for _, item := range order.Items {
+		line := DocumentLine{
+			SKU:       item.SKU,
+			Batch:     item.Batch,
+			Quantity:  item.Quantity,
+			UnitValue: unitValues[item.SKU],
+			Value:     float64(item.Quantity) * unitValues[item.SKU],
+		}
+		data.Lines = append(data.Lines, line)
+		data.TotalUnits += line.Quantity
+		data.TotalValue += line.Value
+	}
+	return data
+}
+
+// RenderedDocument é um documento gerado e guardado para reimpressão
+type RenderedDocument struct {
+	ID                 string         `json:"id"`
+	FulfillmentOrderID string         `json:"fulfillment_order_id"`
+	Type               DocumentType   `json:"type"`
+	Format             DocumentFormat `json:"format"`
+	TemplateID         string         `json:"template_id"` // Vazio = modelo embutido
+	Content            []byte         `json:"-"`
+	Size               int            `json:"size"`
+	CreatedAt          time.Time      `json:"created_at"`
+	CreatedBy          string         `json:"created_by,omitempty"`
+}
+
+// NewRenderedDocument renderiza o modelo para a ordem
+func NewRenderedDocument(tmpl *DocumentTemplate, data *DocumentData, actor string) (*RenderedDocument, error) {
+	content, err := tmpl.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedDocument{
+		ID:                 uuid.New().String(),
+		FulfillmentOrderID: data.Order.ID,
+		Type:               tmpl.Type,
+		Format:             tmpl.Format,
+		TemplateID:         tmpl.ID,
+		Content:            content,
+		Size:               len(content),
+		CreatedAt:          data.GeneratedAt,
+		CreatedBy:          actor,
+	}, nil
+}
+
+// DefaultDocumentTemplate retorna o modelo embutido do tipo e formato (sem ID)
+func DefaultDocumentTemplate(docType DocumentType, format DocumentFormat) (*DocumentTemplate, error) {
+	body, ok := defaultDocumentBodies[docType][format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrDocumentTemplateNotFound, docType, format)
+	}
+	return &DocumentTemplate{Type: docType, Format: format, Body: body}, nil
+}
+
+var documentTitles = map[DocumentType]string{
+	DocumentPackingSlip:       "Romaneio de Embalagem",
+	DocumentReturnLabel:       "Etiqueta de Devolução",
+	DocumentCommercialInvoice: "Fatura Comercial",
+}
+
+var defaultDocumentBodies = func() map[DocumentType]map[DocumentFormat]string {
+	bodies := make(map[DocumentType]map[DocumentFormat]string)
+	for docType, title := range documentTitles {
+		valued := docType == DocumentCommercialInvoice
+		bodies[docType] = map[DocumentFormat]string{
+			DocumentHTML: defaultHTMLBody(title, valued),
+			DocumentPDF:  defaultTextBody(title, valued),
+			DocumentZPL:  defaultZPLBody(title),
+		}
+	}
+	return bodies
+}()
+
+func defaultHTMLBody(title string, valued bool) string {
+	valueHeader, valueCells, valueTotal := "", "", ""
+	if valued {
+		valueHeader = `<th class="num">Valor unit.</th><th class="num">Valor</th>`
+		valueCells = `<td class="num">{{money .UnitValue}}</td><td class="num">{{money .Value}}</td>`
+		valueTotal = `<td class="num"></td><td class="num">{{money .TotalValue}}</td>`
+	}
+	return `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>` + title + ` {{.Order.OrderID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #333; padding: 4px 8px; text-align: left; }
+td.num, th.num { text-align: right; }
+tfoot td { font-weight: bold; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>` + title + `</h1>
+<p>Pedido: <strong>{{.Order.OrderID}}</strong> &middot; Ordem: {{.Order.ID}} &middot; Data: {{date .GeneratedAt}}</p>
+<p>Cliente: <strong>{{.Order.Customer}}</strong><br>{{.Order.Destination}}</p>
+{{with .Shipment}}<p>Transportadora: {{.Carrier}} &middot; Rastreio: {{.TrackingNumber}} &middot; Volumes: {{.Pieces}} &middot; Peso: {{printf "%.2f" .Weight}} kg</p>{{end}}
+<table>
+<thead><tr><th>SKU</th><th>Lote</th><th class="num">Quantidade</th>` + valueHeader + `</tr></thead>
+<tbody>
+{{range .Lines}}<tr><td>{{.SKU}}</td><td>{{.Batch}}</td><td class="num">{{.Quantity}}</td>` + valueCells + `</tr>
+{{end}}</tbody>
+<tfoot><tr><td colspan="2">Total</td><td class="num">{{.TotalUnits}}</td>` + valueTotal + `</tr></tfoot>
+</table>
+</body>
+</html>
+`
+}
+
+func defaultTextBody(title string, valued bool) string {
+	line := `{{printf "%-24s %-12s %8d" .SKU .Batch .Quantity}}`
+	total := `{{printf "%-37s %8d" "TOTAL" .TotalUnits}}`
+	if valued {
+		line = `{{printf "%-24s %-12s %8d %12s %12s" .SKU .Batch .Quantity (money .UnitValue) (money .Value)}}`
+		total = `{{printf "%-37s %8d %12s %12s" "TOTAL" .TotalUnits "" (money .TotalValue)}}`
+	}
+	return strings.ToUpper(title) + `
+Pedido: {{.Order.OrderID}}   Ordem: {{.Order.ID}}
+Data: {{date .GeneratedAt}}
+Cliente: {{.Order.Customer}}
+Destino: {{.Order.Destination}}
+{{with .Shipment}}Transportadora: {{.Carrier}}   Rastreio: {{.TrackingNumber}}   Volumes: {{.Pieces}}   Peso: {{printf "%.2f" .Weight}} kg
+{{end}}
+{{range .Lines}}` + line + `
+{{end}}` + total + `
+`
+}
+
+func defaultZPLBody(title string) string {
+	return `^XA
+^CI28
+^FO40,40^A0N,40,40^FD` + title + `^FS
+^FO40,100^A0N,30,30^FDPedido: {{zpl .Order.OrderID}}^FS
+^FO40,150^A0N,30,30^FD{{zpl .Order.Customer}}^FS
+^FO40,190^A0N,25,25^FB700,3,0,L^FD{{zpl .Order.Destination}}^FS
+{{with .Shipment}}^FO40,290^A0N,25,25^FD{{zpl .Carrier}} - {{.Pieces}} vol - {{printf "%.2f" .Weight}} kg^FS
+{{if .TrackingNumber}}^FO40,340^BY3^BCN,100,Y,N,N^FD{{zpl .TrackingNumber}}^FS
+{{end}}{{end}}^FO40,500^A0N,25,25^FD{{.TotalUnits}} unidades em {{len .Lines}} linhas^FS
+^XZ
+`
+}
@@ -0,0 +1,50 @@
+package fulfillment
+
+import (
+	"errors"
+	"strings"
+)
+
+// Tipos de agregado persistidos no modo event-sourced
+const (
+	AggregateFulfillmentOrder = "fulfillment_order"
+	AggregateTransferOrder    = "transfer_order"
+	AggregateReturnOrder      = "return_order"
+)
+
+var (
+	ErrConcurrencyConflict = errors.New("aggregate version conflict")
+	ErrAggregateNotFound   = errors.New("aggregate has no events at the requested time")
+	ErrHistoryPruned       = errors.New("aggregate events at the requested time were compacted or pruned")
+)
+
+// TransitionEventName nomeia o evento de domínio gerado por uma transição de estado.
+// Ex: fulfillment_order.created, fulfillment_order.started, transfer_order.completed
+func TransitionEventName(aggregateType string, from, to Status) string {
+	if from == "" {
+		return aggregateType + ".created"
+	}
+	if from == to {
+		return aggregateType + ".updated"
+	}
+
+	switch to {
+	case StatusPending:
+		return aggregateType + ".reset"
+	case StatusInProgress:
+		if from == StatusBlocked {
+			return aggregateType + ".unblocked"
+		}
+		return aggregateType + ".started"
+	case StatusCompleted:
+		return aggregateType + ".completed"
+	case StatusCancelled:
+		return aggregateType + ".cancelled"
+	case StatusFailed:
+		return aggregateType + ".failed"
+	case StatusBlocked:
+		return aggregateType + ".blocked"
+	default:
+		return aggregateType + "." + strings.ToLower(string(to))
+	}
+}
@@ -0,0 +1,103 @@
+package fulfillment
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// FailureClass classifica a falha para decidir se pode ser reexecutada automaticamente
+type FailureClass string
+
+const (
+	FailureTransient FailureClass = "TRANSIENT" // Timeout, indisponibilidade: retry automático com backoff
+	FailurePermanent FailureClass = "PERMANENT" // Regra de negócio/dados: exige intervenção do operador
+)
+
+// Etapas em que uma operação pode falhar (ponto de retomada)
+const (
+	StepAdjustStock        = "adjust_stock"
+	StepAdjustStockOut     = "adjust_stock_out" // Transferência: baixa na origem
+	StepAdjustStockIn      = "adjust_stock_in"  // Transferência: entrada no destino
+	StepConfirmReservation = "confirm_reservation"
+)
+
+var (
+	// ErrTransientFailure marca erros de infraestrutura que podem ser reexecutados
+	ErrTransientFailure = errors.New("transient failure")
+	ErrNotFailed        = errors.New("operation is not in FAILED status")
+)
+
+// Failure registra os detalhes da última falha de uma operação
+type Failure struct {
+	Class       FailureClass `json:"class"`
+	Message     string       `json:"message"`
+	Step        string       `json:"step"`
+	Item        int          `json:"item"`    // Índice do item em que a etapa falhou; itens anteriores já foram aplicados
+	Attempt     int          `json:"attempt"` // Número de tentativas que falharam
+	FailedAt    time.Time    `json:"failed_at"`
+	NextRetryAt *time.Time   `json:"next_retry_at,omitempty"` // Apenas falhas transitórias dentro do limite
+}
+
+// NewFailure cria o registro da falha, incrementando as tentativas da falha anterior
+func NewFailure(step string, item int, cause error, previous *Failure) *Failure {
+	now := time.Now()
+	f := &Failure{
+		Class:    ClassifyFailure(cause),
+		Step:     step,
+		Item:     item,
+		Attempt:  1,
+		FailedAt: now,
+	}
+	if cause != nil {
+		f.Message = cause.Error()
+	}
+	if previous != nil {
+		f.Attempt = previous.Attempt + 1
+	}
+
+	policy := DefaultPolicy()
+	if f.Class == FailureTransient && f.Attempt < policy.RetryMaxAttempts {
+		next := now.Add(policy.RetryBackoff(f.Attempt))
+		f.NextRetryAt = &next
+	}
+	return f
+}
+
+// ClassifyFailure identifica falhas transitórias (timeouts, rede, ErrTransientFailure)
+func ClassifyFailure(err error) FailureClass {
+	if err == nil {
+		return FailurePermanent
+	}
+	if errors.Is(err, ErrTransientFailure) || errors.Is(err, context.DeadlineExceeded) {
+		return FailureTransient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return FailureTransient
+	}
+	return FailurePermanent
+}
+
+// ResumeFrom retorna o índice do item a partir do qual a etapa deve ser reexecutada
+func (f *Failure) ResumeFrom(step string) int {
+	if f == nil || f.Step != step {
+		return 0
+	}
+	return f.Item
+}
+
+// DueForRetry indica se a falha pode ser reexecutada automaticamente em now
+func (f *Failure) DueForRetry(now time.Time) bool {
+	return f != nil && f.NextRetryAt != nil && !now.Before(*f.NextRetryAt)
+}
+
+// FailedOperation identifica uma operação em FAILED para reexecução
+type FailedOperation struct {
+	EntityType string        `json:"entity_type"`
+	EntityID   string        `json:"entity_id"`
+	Operation  OperationType `json:"operation"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Failure    *Failure      `json:"failure,omitempty"`
+}
@@ -8,17 +8,23 @@ import (
 
 // FulfillmentOrder: Expedição de Venda (Outbound)
 type FulfillmentOrder struct {
-	ID             string     `json:"id"`
-	OrderID        string     `json:"order_id"` // Ex: ID do Pedido OMS (B10)
-	Customer       string     `json:"customer"`
-	Destination    string     `json:"destination"` // Endereço
-	Status         Status     `json:"status"`
-	Items          []Item     `json:"items"`
-	Priority       int        `json:"priority"` // 0-Normal, 1-Express
-	IdempotencyKey string     `json:"idempotency_key"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
+	ID                   string            `json:"id"`
+	OrderID              string            `json:"order_id"` // Ex: ID do Pedido OMS (B10)
+	Customer             string            `json:"customer"`
+	Destination          string            `json:"destination"` // Endereço
+	Status               Status            `json:"status"`
+	Items                []Item            `json:"items"`
+	Priority             int               `json:"priority"` // 0-Normal, 1-Express
+	ReservationStatus    ReservationStatus `json:"reservation_status"`
+	ReservationExpiresAt *time.Time        `json:"reservation_expires_at,omitempty"`
+	IdempotencyKey       string            `json:"idempotency_key"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+	ShippedAt            *time.Time        `json:"shipped_at,omitempty"`
+	Version              int64             `json:"version"`           // Versão do agregado no event store
+	Failure              *Failure          `json:"failure,omitempty"` // Última falha (etapa, causa, tentativa)
+	Blocked              *Block            `json:"blocked,omitempty"` // Bloqueio ativo (status BLOCKED)
+	ShipPromise                            // Prazos prometidos, transportadora e nível do cliente
 }
 
 // NewFulfillmentOrder cria uma nova instância de FulfillmentOrder
@@ -28,47 +34,95 @@ func NewFulfillmentOrder(orderID, customer, destination string, items []Item, pr
 	}
 	now := time.Now()
 	return &FulfillmentOrder{
-		ID:             uuid.New().String(),
-		OrderID:        orderID,
-		Customer:       customer,
-		Destination:    destination,
-		Status:         StatusPending,
-		Items:          items,
-		Priority:       priority,
-		IdempotencyKey: orderID, // Usa OrderID como chave de idempotência
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		ID:                uuid.New().String(),
+		OrderID:           orderID,
+		Customer:          customer,
+		Destination:       destination,
+		Status:            StatusPending,
+		Items:             items,
+		Priority:          priority,
+		ReservationStatus: ReservationNone,
+		IdempotencyKey:    orderID, // Usa OrderID como chave de idempotência
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}, nil
 }
 
 // StartPicking inicia o processo de separação (picking)
 func (f *FulfillmentOrder) StartPicking() error {
-	if f.Status != StatusPending {
-		return ErrInvalidStateTransition
-	}
-	f.Status = StatusInProgress
-	f.UpdatedAt = time.Now()
-	return nil
+	return Fire(f, EventStart)
 }
 
 // Ship confirma a expedição física
 func (f *FulfillmentOrder) Ship() error {
-	if f.Status != StatusInProgress {
-		return ErrInvalidStateTransition
-	}
-	now := time.Now()
-	f.Status = StatusCompleted
-	f.UpdatedAt = now
-	f.ShippedAt = &now
-	return nil
+	return Fire(f, EventComplete)
 }
 
 // Cancel cancela a ordem de fulfillment
 func (f *FulfillmentOrder) Cancel() error {
-	if f.Status == StatusCompleted {
-		return ErrInvalidStateTransition
+	return Fire(f, EventCancel)
+}
+
+// Fail marca a ordem de fulfillment como falha, registrando etapa, item e causa
+func (f *FulfillmentOrder) Fail(step string, item int, cause error) error {
+	previous := f.Failure
+	f.Failure = NewFailure(step, item, cause, previous)
+	if err := Fire(f, EventFail); err != nil {
+		f.Failure = previous
+		return err
+	}
+	return nil
+}
+
+// Retry devolve a ordem de fulfillment para PENDING mantendo a falha como ponto de retomada
+func (f *FulfillmentOrder) Retry() error {
+	return Fire(f, EventRetry)
+}
+
+// Block bloqueia a ordem de fulfillment com um código de motivo (retirando-a das filas de trabalho)
+func (f *FulfillmentOrder) Block(reason BlockReason, note, actor string) error {
+	block, err := NewBlock(f.Status, reason, note, actor)
+	if err != nil {
+		return err
+	}
+	previous := f.Blocked
+	f.Blocked = block
+	if err := Fire(f, EventBlock); err != nil {
+		f.Blocked = previous
+		return err
 	}
-	f.Status = StatusCancelled
-	f.UpdatedAt = time.Now()
 	return nil
 }
+
+// Unblock devolve a ordem de fulfillment ao status anterior ao bloqueio
+func (f *FulfillmentOrder) Unblock() error {
+	return Fire(f, f.Blocked.unblockEvent())
+}
+
+// ReleaseHold libera a retenção (alfândega, qualidade) que mantém a ordem de fulfillment bloqueada
+func (f *FulfillmentOrder) ReleaseHold(actor string) error {
+	return f.Blocked.releaseHold(actor)
+}
+
+// HasActiveHolds indica retenção ainda não liberada (guard da máquina de estados)
+func (f *FulfillmentOrder) HasActiveHolds() bool { return f.Blocked.ActiveHold() }
+
+// CurrentStatus retorna o status atual (máquina de estados)
+func (f *FulfillmentOrder) CurrentStatus() Status { return f.Status }
+
+// Operation retorna o tipo de operação que governa as transições
+func (f *FulfillmentOrder) Operation() OperationType { return OpOutbound }
+
+// OpenedAt retorna o início do SLA da operação
+func (f *FulfillmentOrder) OpenedAt() time.Time { return f.CreatedAt }
+
+func (f *FulfillmentOrder) applyStatus(to Status, at time.Time) {
+	f.Status = to
+	f.UpdatedAt = at
+	if to != StatusBlocked {
+		f.Blocked = nil
+	}
+	if to == StatusCompleted {
+		f.ShippedAt = &at
+	}
+}
@@ -18,6 +18,8 @@ type InboundShipment struct {
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	Failure        *Failure   `json:"failure,omitempty"` // Última falha (etapa, causa, tentativa)
+	Blocked        *Block     `json:"blocked,omitempty"` // Bloqueio ativo (status BLOCKED)
 }
 
 // NewInboundShipment cria uma nova instância de InboundShipment
@@ -41,32 +43,79 @@ func NewInboundShipment(refID, origin, dest string, items []Item) (*InboundShipm
 
 // StartReceiving inicia o processo de recebimento físico
 func (i *InboundShipment) StartReceiving() error {
-	if i.Status != StatusPending {
-		return ErrInvalidStateTransition
-	}
-	i.Status = StatusInProgress
-	i.UpdatedAt = time.Now()
-	return nil
+	return Fire(i, EventStart)
 }
 
 // Complete finaliza o recebimento físico
 func (i *InboundShipment) Complete() error {
-	if i.Status != StatusInProgress {
-		return ErrInvalidStateTransition
-	}
-	now := time.Now()
-	i.Status = StatusCompleted
-	i.UpdatedAt = now
-	i.CompletedAt = &now
-	return nil
+	return Fire(i, EventComplete)
 }
 
 // Cancel cancela o recebimento
 func (i *InboundShipment) Cancel() error {
-	if i.Status == StatusCompleted {
-		return ErrInvalidStateTransition
+	return Fire(i, EventCancel)
+}
+
+// Fail marca o recebimento como falha, registrando etapa, item e causa
+func (i *InboundShipment) Fail(step string, item int, cause error) error {
+	previous := i.Failure
+	i.Failure = NewFailure(step, item, cause, previous)
+	if err := Fire(i, EventFail); err != nil {
+		i.Failure = previous
+		return err
+	}
+	return nil
+}
+
+// Retry devolve o recebimento para PENDING mantendo a falha como ponto de retomada
+func (i *InboundShipment) Retry() error {
+	return Fire(i, EventRetry)
+}
+
+// Block bloqueia o recebimento com um código de motivo (retirando-a das filas de trabalho)
+func (i *InboundShipment) Block(reason BlockReason, note, actor string) error {
+	block, err := NewBlock(i.Status, reason, note, actor)
+	if err != nil {
+		return err
+	}
+	previous := i.Blocked
+	i.Blocked = block
+	if err := Fire(i, EventBlock); err != nil {
+		i.Blocked = previous
+		return err
 	}
-	i.Status = StatusCancelled
-	i.UpdatedAt = time.Now()
 	return nil
 }
+
+// Unblock devolve o recebimento ao status anterior ao bloqueio
+func (i *InboundShipment) Unblock() error {
+	return Fire(i, i.Blocked.unblockEvent())
+}
+
+// ReleaseHold libera a retenção (alfândega, qualidade) que mantém o recebimento bloqueada
+func (i *InboundShipment) ReleaseHold(actor string) error {
+	return i.Blocked.releaseHold(actor)
+}
+
+// HasActiveHolds indica retenção ainda não liberada (guard da máquina de estados)
+func (i *InboundShipment) HasActiveHolds() bool { return i.Blocked.ActiveHold() }
+
+// CurrentStatus retorna o status atual (máquina de estados)
+func (i *InboundShipment) CurrentStatus() Status { return i.Status }
+
+// Operation retorna o tipo de operação que governa as transições
+func (i *InboundShipment) Operation() OperationType { return OpInbound }
+
+// OpenedAt retorna o início do SLA da operação
+func (i *InboundShipment) OpenedAt() time.Time { return i.CreatedAt }
+
+func (i *InboundShipment) applyStatus(to Status, at time.Time) {
+	i.Status = to
+	i.UpdatedAt = at
+	if to != StatusBlocked {
+		i.Blocked = nil
+	}
+	if to == StatusCompleted {
+		i.CompletedAt = &at
+	}
+}
@@ -1,9 +1,18 @@
 package fulfillment
 
+import "time"
+
 // Item representa uma linha de produto em qualquer operação
 type Item struct {
 	SKU      string `json:"sku"`
 	Quantity int    `json:"quantity"`
 	Batch    string `json:"batch,omitempty"`    // Opcional na entrada, obrigatório na saída se controlado
 	Location string `json:"location,omitempty"` // Localização física (opcional)
+	UoM      string `json:"uom,omitempty"`      // Unidade de Quantity; vazio = unidade base (EA). Normalizada para EA pelos casos de uso
+	// Embalagem declarada na linha (ex: 10 CS); PackQuantity é recalculada na normalização
+	PackUoM      string `json:"pack_uom,omitempty"`
+	PackQuantity int    `json:"pack_quantity,omitempty"`
+	// Identificação lida do código GS1 da embalagem (opcionais)
+	Serial    string     `json:"serial,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
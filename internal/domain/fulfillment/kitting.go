@@ -0,0 +1,185 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrKitNotFound           = errors.New("kit definition not found")
+	ErrInvalidKit            = errors.New("invalid kit definition")
+	ErrAssemblyOrderNotFound = errors.New("assembly order not found")
+	ErrInvalidAssembly       = errors.New("invalid assembly order")
+	ErrAssemblyUnreconciled  = errors.New("assembly order has unreconciled stock movements")
+)
+
+// KitComponent é uma linha da lista de materiais do kit
+type KitComponent struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"` // Por unidade de kit
+}
+
+// KitDefinition: lista de materiais (BOM) de um SKU montado a partir de componentes
+type KitDefinition struct {
+	SKU        string         `json:"sku"`
+	Name       string         `json:"name,omitempty"`
+	Components []KitComponent `json:"components"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// NewKitDefinition cria e valida a definição do kit
+func NewKitDefinition(sku, name string, components []KitComponent) (*KitDefinition, error) {
+	if sku == "" {
+		return nil, fmt.Errorf("%w: sku is required", ErrInvalidKit)
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("%w: at least one component is required", ErrInvalidKit)
+	}
+	seen := make(map[string]bool, len(components))
+	for _, component := range components {
+		switch {
+		case component.SKU == "" || component.Quantity <= 0:
+			return nil, fmt.Errorf("%w: components need a sku and a positive quantity", ErrInvalidKit)
+		case component.SKU == sku:
+			return nil, fmt.Errorf("%w: kit %s cannot contain itself", ErrInvalidKit, sku)
+		case seen[component.SKU]:
+			return nil, fmt.Errorf("%w: duplicated component %s", ErrInvalidKit, component.SKU)
+		}
+		seen[component.SKU] = true
+	}
+
+	now := time.Now()
+	return &KitDefinition{
+		SKU:        sku,
+		Name:       name,
+		Components: components,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// AssemblyDirection indica montagem (componentes -> kit) ou desmontagem (kit -> componentes)
+type AssemblyDirection string
+
+const (
+	AssemblyAssemble    AssemblyDirection = "ASSEMBLE"
+	AssemblyDisassemble AssemblyDirection = "DISASSEMBLE"
+)
+
+// AssemblyOrder: ordem de montagem/desmontagem de kits em um endereço
+type AssemblyOrder struct {
+	ID             string            `json:"id"`
+	KitSKU         string            `json:"kit_sku"`
+	Direction      AssemblyDirection `json:"direction"`
+	Quantity       int               `json:"quantity"`
+	Location       string            `json:"location"`
+	Components     []KitComponent    `json:"components"`                // BOM vigente na criação
+	SourceOrderID  string            `json:"source_order_id,omitempty"` // Ordem de fulfillment que disparou a montagem
+	Status         Status            `json:"status"`
+	Unreconciled   []Item            `json:"unreconciled,omitempty"` // Ajustes aplicados cuja compensação falhou
+	IdempotencyKey string            `json:"idempotency_key"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	CompletedAt    *time.Time        `json:"completed_at,omitempty"`
+	Failure        *Failure          `json:"failure,omitempty"`
+}
+
+// NewAssemblyOrder cria uma ordem de montagem ou desmontagem a partir da definição do kit
+func NewAssemblyOrder(kit *KitDefinition, direction AssemblyDirection, quantity int, location, sourceOrderID string) (*AssemblyOrder, error) {
+	if direction != AssemblyAssemble && direction != AssemblyDisassemble {
+		return nil, fmt.Errorf("%w: direction must be ASSEMBLE or DISASSEMBLE", ErrInvalidAssembly)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidAssembly)
+	}
+
+	now := time.Now()
+	id := uuid.New().String()
+	return &AssemblyOrder{
+		ID:             id,
+		KitSKU:         kit.SKU,
+		Direction:      direction,
+		Quantity:       quantity,
+		Location:       location,
+		Components:     append([]KitComponent(nil), kit.Components...),
+		SourceOrderID:  sourceOrderID,
+		Status:         StatusPending,
+		IdempotencyKey: id,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Movements retorna os ajustes de estoque da ordem: primeiro as baixas, depois as entradas,
+// para que uma falha no consumo não gere saldo de produto que não foi montado
+func (o *AssemblyOrder) Movements() []Item {
+	kit := Item{SKU: o.KitSKU, Quantity: o.Quantity, Location: o.Location}
+	parts := make([]Item, 0, len(o.Components))
+	for _, component := range o.Components {
+		parts = append(parts, Item{SKU: component.SKU, Quantity: component.Quantity * o.Quantity, Location: o.Location})
+	}
+
+	movements := make([]Item, 0, len(parts)+1)
+	if o.Direction == AssemblyAssemble {
+		for _, part := range parts {
+			part.Quantity = -part.Quantity
+			movements = append(movements, part)
+		}
+		return append(movements, kit)
+	}
+
+	kit.Quantity = -kit.Quantity
+	return append(append(movements, kit), parts...)
+}
+
+// Start inicia a execução da ordem
+func (o *AssemblyOrder) Start() error {
+	return Fire(o, EventStart)
+}
+
+// Complete finaliza a ordem
+func (o *AssemblyOrder) Complete() error {
+	return Fire(o, EventComplete)
+}
+
+// Cancel cancela a ordem
+func (o *AssemblyOrder) Cancel() error {
+	return Fire(o, EventCancel)
+}
+
+// Fail marca a ordem como falha, registrando etapa, item e causa
+func (o *AssemblyOrder) Fail(step string, item int, cause error) error {
+	previous := o.Failure
+	o.Failure = NewFailure(step, item, cause, previous)
+	if err := Fire(o, EventFail); err != nil {
+		o.Failure = previous
+		return err
+	}
+	return nil
+}
+
+// Retry devolve a ordem que falhou para PENDING
+func (o *AssemblyOrder) Retry() error {
+	return Fire(o, EventRetry)
+}
+
+// CurrentStatus implementa Stateful
+func (o *AssemblyOrder) CurrentStatus() Status { return o.Status }
+
+// Operation implementa Stateful
+func (o *AssemblyOrder) Operation() OperationType { return OpAssembly }
+
+// OpenedAt retorna o início do SLA da operação
+func (o *AssemblyOrder) OpenedAt() time.Time { return o.CreatedAt }
+
+func (o *AssemblyOrder) applyStatus(to Status, at time.Time) {
+	o.Status = to
+	o.UpdatedAt = at
+	if to == StatusCompleted {
+		o.CompletedAt = &at
+	}
+}
@@ -0,0 +1,219 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LPNType é o tipo de contêiner identificado por license plate
+type LPNType string
+
+const (
+	LPNPallet LPNType = "PALLET"
+	LPNCase   LPNType = "CASE"
+	LPNTote   LPNType = "TOTE"
+)
+
+// LPNStatus indica se o conteúdo do contêiner já está no estoque
+type LPNStatus string
+
+const (
+	LPNExpected LPNStatus = "EXPECTED" // Anunciado (ASN), ainda não recebido
+	LPNActive   LPNStatus = "ACTIVE"   // Em estoque na Location
+	LPNShipped  LPNStatus = "SHIPPED"
+)
+
+// LPNAction é o tipo de registro do histórico do contêiner
+type LPNAction string
+
+const (
+	LPNCreated  LPNAction = "CREATED"
+	LPNPacked   LPNAction = "PACKED"
+	LPNUnpacked LPNAction = "UNPACKED"
+	LPNNested   LPNAction = "NESTED"
+	LPNUnnested LPNAction = "UNNESTED"
+	LPNReceived LPNAction = "RECEIVED"
+	LPNMoved    LPNAction = "MOVED"
+	LPNShip     LPNAction = "SHIPPED"
+)
+
+var (
+	ErrLPNNotFound         = errors.New("lpn not found")
+	ErrLPNExists           = errors.New("lpn already exists")
+	ErrInvalidLPN          = errors.New("invalid lpn")
+	ErrLPNNesting          = errors.New("invalid lpn nesting")
+	ErrLPNNotHandleable    = errors.New("lpn cannot be handled in its current state")
+	ErrLPNContentsMismatch = errors.New("lpn contents do not match the order")
+)
+
+// LPN é um contêiner (palete, caixa, tote) com conteúdo próprio e contêineres aninhados.
+// Os filhos acompanham a localização e o status do contêiner raiz.
+type LPN struct {
+	Code            string    `json:"code"`
+	Type            LPNType   `json:"type"`
+	Parent          string    `json:"parent,omitempty"` // Código do contêiner pai
+	Location        string    `json:"location,omitempty"`
+	Status          LPNStatus `json:"status"`
+	Contents        []Item    `json:"contents,omitempty"`         // Itens soltos diretamente no contêiner (unidades base)
+	PendingTransfer string    `json:"pending_transfer,omitempty"` // Transferência do Move em andamento, retomada na repetição
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// LPNEvent é um registro do histórico do contêiner
+type LPNEvent struct {
+	LPNCode       string    `json:"lpn_code"`
+	Action        LPNAction `json:"action"`
+	FromLocation  string    `json:"from_location,omitempty"`
+	ToLocation    string    `json:"to_location,omitempty"`
+	Items         []Item    `json:"items,omitempty"`
+	ReferenceType string    `json:"reference_type,omitempty"` // Operação que movimentou o contêiner
+	ReferenceID   string    `json:"reference_id,omitempty"`
+	Actor         string    `json:"actor,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// NewLPN cria um contêiner; sem localização ele fica EXPECTED até o recebimento
+func NewLPN(code string, lpnType LPNType, location string, contents []Item) (*LPN, error) {
+	if code == "" {
+		return nil, fmt.Errorf("%w: code is required", ErrInvalidLPN)
+	}
+	if lpnType.rank() == 0 {
+		return nil, fmt.Errorf("%w: type must be PALLET, CASE or TOTE", ErrInvalidLPN)
+	}
+	for _, item := range contents {
+		if item.SKU == "" || item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: contents need sku and positive quantity", ErrInvalidLPN)
+		}
+	}
+
+	status := LPNActive
+	if location == "" {
+		status = LPNExpected
+	}
+	now := time.Now()
+	return &LPN{
+		Code:      code,
+		Type:      lpnType,
+		Location:  location,
+		Status:    status,
+		Contents:  MergeItems(nil, contents),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// rank ordena os tipos para o aninhamento: palete contém caixas e totes, que contêm itens
+func (t LPNType) rank() int {
+	switch t {
+	case LPNPallet:
+		return 2
+	case LPNCase, LPNTote:
+		return 1
+	}
+	return 0
+}
+
+// CanNest valida a inclusão do contêiner child neste contêiner
+func (l *LPN) CanNest(child *LPN) error {
+	switch {
+	case child.Code == l.Code:
+		return fmt.Errorf("%w: %s cannot contain itself", ErrLPNNesting, l.Code)
+	case child.Type.rank() >= l.Type.rank():
+		return fmt.Errorf("%w: %s cannot hold %s", ErrLPNNesting, l.Type, child.Type)
+	case l.Status == LPNShipped || child.Status == LPNShipped:
+		return fmt.Errorf("%w: shipped lpn", ErrLPNNotHandleable)
+	case child.Status != l.Status || child.Location != l.Location:
+		return fmt.Errorf("%w: %s and %s are not at the same place", ErrLPNNesting, child.Code, l.Code)
+	}
+	return nil
+}
+
+// Pack acrescenta itens ao conteúdo do contêiner
+func (l *LPN) Pack(items []Item) error {
+	if l.Status == LPNShipped {
+		return ErrLPNNotHandleable
+	}
+	for _, item := range items {
+		if item.SKU == "" || item.Quantity <= 0 {
+			return fmt.Errorf("%w: items need sku and positive quantity", ErrInvalidLPN)
+		}
+	}
+	l.Contents = MergeItems(l.Contents, items)
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+// Unpack retira itens do conteúdo do contêiner (mesmo SKU e lote)
+func (l *LPN) Unpack(items []Item) error {
+	if l.Status == LPNShipped {
+		return ErrLPNNotHandleable
+	}
+	contents := append([]Item(nil), l.Contents...)
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: items need positive quantity", ErrInvalidLPN)
+		}
+		remaining := item.Quantity
+		for idx := range contents {
+			if remaining == 0 {
+				break
+			}
+			if contents[idx].SKU != item.SKU || (item.Batch != "" && contents[idx].Batch != item.Batch) {
+				continue
+			}
+			taken := min(remaining, contents[idx].Quantity)
+			contents[idx].Quantity -= taken
+			remaining -= taken
+		}
+		if remaining > 0 {
+			return fmt.Errorf("%w: %s has not %d of %s", ErrInvalidLPN, l.Code, item.Quantity, item.SKU)
+		}
+	}
+
+	l.Contents = MergeItems(nil, contents)
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+// MergeItems soma as linhas por SKU e lote (descarta quantidades zeradas), ordenadas por SKU
+func MergeItems(base []Item, extra []Item) []Item {
+	type key struct{ sku, batch string }
+	totals := make(map[key]int)
+	var order []key
+	for _, item := range append(append([]Item(nil), base...), extra...) {
+		k := key{item.SKU, item.Batch}
+		if _, ok := totals[k]; !ok {
+			order = append(order, k)
+		}
+		totals[k] += item.Quantity
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].sku < order[j].sku })
+
+	merged := make([]Item, 0, len(order))
+	for _, k := range order {
+		if totals[k] > 0 {
+			merged = append(merged, Item{SKU: k.sku, Batch: k.batch, Quantity: totals[k]})
+		}
+	}
+	return merged
+}
+
+// SameSKUTotals indica se as duas listas têm as mesmas quantidades por SKU (lotes somados)
+func SameSKUTotals(a, b []Item) bool {
+	totals := make(map[string]int)
+	for _, item := range a {
+		totals[item.SKU] += item.Quantity
+	}
+	for _, item := range b {
+		totals[item.SKU] -= item.Quantity
+	}
+	for _, diff := range totals {
+		if diff != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package fulfillment
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ManifestStatus é o ciclo de vida do manifesto de transportadora
+type ManifestStatus string
+
+const (
+	ManifestOpen   ManifestStatus = "OPEN"
+	ManifestClosed ManifestStatus = "CLOSED"
+)
+
+// ManifestFormat é o formato do documento do manifesto
+type ManifestFormat string
+
+const (
+	ManifestCSV  ManifestFormat = "csv"
+	ManifestHTML ManifestFormat = "html"
+)
+
+var (
+	ErrManifestNotFound          = errors.New("manifest not found")
+	ErrManifestClosed            = errors.New("manifest is closed")
+	ErrManifestNotClosed         = errors.New("manifest is not closed yet")
+	ErrInvalidOutboundShipment   = errors.New("invalid outbound shipment")
+	ErrOutboundShipmentExists    = errors.New("outbound shipment already exists for order")
+	ErrOutboundShipmentNotFound  = errors.New("outbound shipment not found")
+	ErrOrderNotShipped           = errors.New("order has not been shipped")
+	ErrUnsupportedManifestFormat = errors.New("unsupported manifest format")
+)
+
+// CarrierManifest agrupa as expedições entregues a uma transportadora em um dia.
+// Depois de fechado não aceita novas expedições.
+type CarrierManifest struct {
+	ID        string         `json:"id"`
+	Carrier   string         `json:"carrier"`
+	Date      time.Time      `json:"date"` // Dia (UTC) da expedição
+	Status    ManifestStatus `json:"status"`
+	Shipments int            `json:"shipments"`
+	Pieces    int            `json:"pieces"`
+	Weight    float64        `json:"weight"` // kg
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	ClosedAt  *time.Time     `json:"closed_at,omitempty"`
+	ClosedBy  string         `json:"closed_by,omitempty"`
+}
+
+// ManifestDate é o dia do manifesto de uma expedição
+func ManifestDate(at time.Time) time.Time {
+	at = at.UTC()
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NewCarrierManifest abre o manifesto da transportadora no dia
+func NewCarrierManifest(carrier string, date time.Time) (*CarrierManifest, error) {
+	if carrier == "" {
+		return nil, fmt.Errorf("%w: carrier is required", ErrInvalidOutboundShipment)
+	}
+	now := time.Now()
+	return &CarrierManifest{
+		ID:        uuid.New().String(),
+		Carrier:   carrier,
+		Date:      ManifestDate(date),
+		Status:    ManifestOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Add inclui uma expedição já despachada da mesma transportadora e dia
+func (m *CarrierManifest) Add(shipment *OutboundShipment) error {
+	if m.Status != ManifestOpen {
+		return fmt.Errorf("%w: %s %s", ErrManifestClosed, m.Carrier, m.Date.Format(time.DateOnly))
+	}
+	if shipment.Status != StatusCompleted || shipment.ShippedAt == nil {
+		return fmt.Errorf("%w: shipment %s is %s", ErrInvalidOutboundShipment, shipment.ID, shipment.Status)
+	}
+	if shipment.Carrier != m.Carrier || !ManifestDate(*shipment.ShippedAt).Equal(m.Date) {
+		return fmt.Errorf("%w: shipment %s does not belong to manifest %s", ErrInvalidOutboundShipment, shipment.ID, m.ID)
+	}
+	shipment.ManifestID = m.ID
+	m.Shipments++
+	m.Pieces += shipment.Pieces
+	m.Weight += shipment.Weight
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+// Close fecha o manifesto para a entrega ao motorista
+func (m *CarrierManifest) Close(actor string) error {
+	if m.Status != ManifestOpen {
+		return ErrManifestClosed
+	}
+	now := time.Now()
+	m.Status = ManifestClosed
+	m.ClosedAt = &now
+	m.ClosedBy = actor
+	m.UpdatedAt = now
+	return nil
+}
+
+// CSV gera o manifesto com uma linha por expedição e a linha de totais
+func (m *CarrierManifest) CSV(shipments []*OutboundShipment) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"manifest_id", "carrier", "date", "shipment_id", "fulfillment_order_id", "tracking_number", "pieces", "weight_kg"})
+	for _, shipment := range shipments {
+		w.Write([]string{
+			m.ID, m.Carrier, m.Date.Format(time.DateOnly), shipment.ID, shipment.FulfillmentOrderID, shipment.TrackingNumber,
+			strconv.Itoa(shipment.Pieces), strconv.FormatFloat(shipment.Weight, 'f', 2, 64),
+		})
+	}
+	w.Write([]string{m.ID, m.Carrier, m.Date.Format(time.DateOnly), "TOTAL", strconv.Itoa(m.Shipments), "",
+		strconv.Itoa(m.Pieces), strconv.FormatFloat(m.Weight, 'f', 2, 64)})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write manifest csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var manifestHTML = template.Must(template.New("manifest").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Manifesto {{.Manifest.Carrier}} {{.Date}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #333; padding: 4px 8px; text-align: left; }
+td.num, th.num { text-align: right; }
+tfoot td { font-weight: bold; }
+.signatures { display: flex; justify-content: space-between; margin-top: 4em; }
+.signatures div { border-top: 1px solid #333; width: 40%; padding-top: 4px; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Manifesto de Expedição</h1>
+<p>Transportadora: <strong>{{.Manifest.Carrier}}</strong> &middot; Data: <strong>{{.Date}}</strong> &middot; Manifesto: {{.Manifest.ID}}</p>
+<table>
+<thead><tr><th>Ordem</th><th>Rastreio</th><th class="num">Volumes</th><th class="num">Peso (kg)</th></tr></thead>
+<tbody>
+{{range .Shipments}}<tr><td>{{.FulfillmentOrderID}}</td><td>{{.TrackingNumber}}</td><td class="num">{{.Pieces}}</td><td class="num">{{printf "%.2f" .Weight}}</td></tr>
+{{end}}</tbody>
+<tfoot><tr><td colspan="2">Total: {{.Manifest.Shipments}} expedições</td><td class="num">{{.Manifest.Pieces}}</td><td class="num">{{printf "%.2f" .Manifest.Weight}}</td></tr></tfoot>
+</table>
+<div class="signatures"><div>Expedição{{with .Manifest.ClosedBy}}: {{.}}{{end}}</div><div>Motorista</div></div>
+</body>
+</html>
+`))
+
+// HTML gera o manifesto imprimível com espaço para as assinaturas da entrega
+func (m *CarrierManifest) HTML(shipments []*OutboundShipment) ([]byte, error) {
+	var buf bytes.Buffer
+	err := manifestHTML.Execute(&buf, struct {
+		Manifest  *CarrierManifest
+		Date      string
+		Shipments []*OutboundShipment
+	}{m, m.Date.Format("02/01/2006"), shipments})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifest html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -1,6 +1,7 @@
 package fulfillment
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,9 @@ type OutboundShipment struct {
 	TrackingNumber     string     `json:"tracking_number,omitempty"`
 	Carrier            string     `json:"carrier,omitempty"`
 	Status             Status     `json:"status"`
+	Pieces             int        `json:"pieces"`                // Volumes entregues à transportadora
+	Weight             float64    `json:"weight"`                // Peso total em kg
+	ManifestID         string     `json:"manifest_id,omitempty"` // Manifesto da transportadora no dia
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 	ShippedAt          *time.Time `json:"shipped_at,omitempty"`
@@ -32,14 +36,45 @@ func NewOutboundShipment(fulfillmentOrderID, trackingNumber, carrier string) *Ou
 	}
 }
 
-// Ship confirma a expedição
-func (o *OutboundShipment) Ship() error {
-	if o.Status != StatusPending && o.Status != StatusInProgress {
-		return ErrInvalidStateTransition
+// Validate exige transportadora e ao menos um volume com peso não negativo
+func (o *OutboundShipment) Validate() error {
+	switch {
+	case o.Carrier == "":
+		return fmt.Errorf("%w: carrier is required", ErrInvalidOutboundShipment)
+	case o.Pieces <= 0:
+		return fmt.Errorf("%w: pieces must be positive", ErrInvalidOutboundShipment)
+	case o.Weight < 0:
+		return fmt.Errorf("%w: weight must not be negative", ErrInvalidOutboundShipment)
 	}
-	now := time.Now()
-	o.Status = StatusCompleted
-	o.UpdatedAt = now
-	o.ShippedAt = &now
 	return nil
 }
+
+// Ship confirma a expedição (iniciando-a antes, se ainda pendente)
+func (o *OutboundShipment) Ship() error {
+	if o.Status == StatusPending {
+		if err := Fire(o, EventStart); err != nil {
+			return err
+		}
+	}
+	return Fire(o, EventComplete)
+}
+
+// CurrentStatus retorna o status atual (máquina de estados)
+func (o *OutboundShipment) CurrentStatus() Status { return o.Status }
+
+// Operation retorna o tipo de operação que governa as transições
+func (o *OutboundShipment) Operation() OperationType { return OpOutbound }
+
+// OpenedAt retorna o início do SLA da operação
+func (o *OutboundShipment) OpenedAt() time.Time { return o.CreatedAt }
+
+// PackingComplete indica se os volumes foram embalados (guard da expedição)
+func (o *OutboundShipment) PackingComplete() bool { return o.Pieces > 0 }
+
+func (o *OutboundShipment) applyStatus(to Status, at time.Time) {
+	o.Status = to
+	o.UpdatedAt = at
+	if to == StatusCompleted {
+		o.ShippedAt = &at
+	}
+}
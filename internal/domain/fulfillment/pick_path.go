@@ -0,0 +1,540 @@
+package fulfillment
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// AisleDirection restringe o sentido de circulação no corredor
+type AisleDirection string
+
+const (
+	AisleTwoWay AisleDirection = "BOTH"
+	AisleUp     AisleDirection = "UP"   // Somente de Start para End
+	AisleDown   AisleDirection = "DOWN" // Somente de End para Start
+)
+
+// RouteStrategy identifica a forma de sequenciar uma lista de separação
+type RouteStrategy string
+
+const (
+	RouteByLocation RouteStrategy = "LOCATION"     // Ordem alfabética do endereço (comportamento anterior)
+	RouteSShape     RouteStrategy = "S_SHAPE"      // Serpentina: corredor a corredor, alternando o sentido
+	RouteTwoOpt     RouteStrategy = "S_SHAPE_2OPT" // Serpentina refinada por trocas 2-opt
+)
+
+var (
+	ErrLayoutNotFound        = errors.New("warehouse layout not found")
+	ErrInvalidLayout         = errors.New("invalid warehouse layout")
+	ErrPickLocationUnreached = errors.New("pick location cannot be reached from the depot")
+)
+
+// Aisle é um corredor de separação paralelo ao eixo Y, com coordenadas em metros
+type Aisle struct {
+	ID        string         `json:"id"`
+	X         float64        `json:"x"`     // Eixo do corredor
+	Start     float64        `json:"start"` // Y da cabeceira frontal
+	End       float64        `json:"end"`   // Y da cabeceira de fundo
+	Direction AisleDirection `json:"direction"`
+}
+
+// BinCoordinate posiciona um endereço (Item.Location) ao longo de um corredor
+type BinCoordinate struct {
+	Location string  `json:"location"`
+	Aisle    string  `json:"aisle"`
+	Position float64 `json:"position"`         // Y entre Start e End do corredor
+	Golden   bool    `json:"golden,omitempty"` // Zona dourada: altura ergonômica, reservada a itens de giro rápido
+}
+
+// Point é uma coordenada no plano do armazém
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// WarehouseLayout descreve a topologia usada para estimar deslocamentos: corredores, corredores
+// transversais (em Y fixo, ligando todos os corredores que cruzam) e o ponto de partida das rotas
+type WarehouseLayout struct {
+	Aisles      []Aisle         `json:"aisles"`
+	CrossAisles []float64       `json:"cross_aisles"` // Padrão: cabeceiras frontal e de fundo
+	Depot       Point           `json:"depot"`        // Início e fim das rotas; sobre um corredor transversal
+	Bins        []BinCoordinate `json:"bins"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// PickStop é uma parada da rota com as linhas separadas no endereço
+type PickStop struct {
+	Location string  `json:"location"`
+	Items    []Item  `json:"items"`
+	Distance float64 `json:"distance"` // Desde a parada anterior (ou depósito)
+}
+
+// PickRoute é uma lista de separação sequenciada e sua distância estimada (ida e volta ao depósito)
+type PickRoute struct {
+	Strategy RouteStrategy `json:"strategy"`
+	Stops    []PickStop    `json:"stops"`
+	Distance float64       `json:"distance"`
+	Unplaced []Item        `json:"unplaced,omitempty"` // Linhas sem coordenada, fora da rota
+}
+
+// RouteComparison resume a distância obtida por cada estratégia
+type RouteComparison struct {
+	Strategy RouteStrategy `json:"strategy"`
+	Distance float64       `json:"distance"`
+}
+
+// PickPlan é a rota escolhida (menor distância) e a comparação entre estratégias
+type PickPlan struct {
+	Route      PickRoute         `json:"route"`
+	Comparison []RouteComparison `json:"comparison"`
+}
+
+// twoOptMaxPasses limita as passadas de melhoria sobre listas grandes
+const twoOptMaxPasses = 50
+
+// NewWarehouseLayout cria e valida o layout; sem corredores transversais usa as cabeceiras
+func NewWarehouseLayout(aisles []Aisle, crossAisles []float64, depot Point, bins []BinCoordinate) (*WarehouseLayout, error) {
+	layout := &WarehouseLayout{
+		Aisles:      aisles,
+		CrossAisles: crossAisles,
+		Depot:       depot,
+		Bins:        bins,
+		UpdatedAt:   time.Now(),
+	}
+	for idx := range layout.Aisles {
+		if layout.Aisles[idx].Direction == "" {
+			layout.Aisles[idx].Direction = AisleTwoWay
+		}
+	}
+	if len(layout.CrossAisles) == 0 && len(aisles) > 0 {
+		front, back := aisles[0].Start, aisles[0].End
+		for _, aisle := range aisles {
+			front, back = math.Min(front, aisle.Start), math.Max(back, aisle.End)
+		}
+		layout.CrossAisles = []float64{front, back}
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// Validate verifica corredores, corredores transversais, depósito e endereços
+func (l *WarehouseLayout) Validate() error {
+	if len(l.Aisles) == 0 {
+		return fmt.Errorf("%w: at least one aisle is required", ErrInvalidLayout)
+	}
+	ids, xs := make(map[string]bool), make(map[float64]bool)
+	for _, aisle := range l.Aisles {
+		switch {
+		case aisle.ID == "":
+			return fmt.Errorf("%w: aisle id is required", ErrInvalidLayout)
+		case ids[aisle.ID]:
+			return fmt.Errorf("%w: duplicate aisle %s", ErrInvalidLayout, aisle.ID)
+		case xs[aisle.X]:
+			return fmt.Errorf("%w: aisles share x=%g", ErrInvalidLayout, aisle.X)
+		case aisle.End <= aisle.Start:
+			return fmt.Errorf("%w: aisle %s must end after it starts", ErrInvalidLayout, aisle.ID)
+		case aisle.Direction != AisleTwoWay && aisle.Direction != AisleUp && aisle.Direction != AisleDown:
+			return fmt.Errorf("%w: aisle %s direction must be BOTH, UP or DOWN", ErrInvalidLayout, aisle.ID)
+		}
+		ids[aisle.ID], xs[aisle.X] = true, true
+	}
+
+	if len(l.CrossAisles) == 0 {
+		return fmt.Errorf("%w: at least one cross-aisle is required", ErrInvalidLayout)
+	}
+	onCrossAisle := false
+	for _, y := range l.CrossAisles {
+		onCrossAisle = onCrossAisle || y == l.Depot.Y
+	}
+	if !onCrossAisle {
+		return fmt.Errorf("%w: depot must lie on a cross-aisle", ErrInvalidLayout)
+	}
+
+	aisles := l.aisleIndex()
+	locations := make(map[string]bool)
+	for _, bin := range l.Bins {
+		aisle, ok := aisles[bin.Aisle]
+		switch {
+		case bin.Location == "":
+			return fmt.Errorf("%w: bin location is required", ErrInvalidLayout)
+		case locations[bin.Location]:
+			return fmt.Errorf("%w: duplicate bin %s", ErrInvalidLayout, bin.Location)
+		case !ok:
+			return fmt.Errorf("%w: bin %s references unknown aisle %s", ErrInvalidLayout, bin.Location, bin.Aisle)
+		case bin.Position < aisle.Start || bin.Position > aisle.End:
+			return fmt.Errorf("%w: bin %s is outside aisle %s", ErrInvalidLayout, bin.Location, bin.Aisle)
+		}
+		locations[bin.Location] = true
+	}
+	return nil
+}
+
+// MergeBins inclui ou substitui as coordenadas pelos endereços informados
+func (l *WarehouseLayout) MergeBins(bins []BinCoordinate) error {
+	byLocation := make(map[string]int, len(l.Bins))
+	merged := append([]BinCoordinate(nil), l.Bins...)
+	for idx, bin := range merged {
+		byLocation[bin.Location] = idx
+	}
+	for _, bin := range bins {
+		if idx, ok := byLocation[bin.Location]; ok {
+			merged[idx] = bin
+			continue
+		}
+		byLocation[bin.Location] = len(merged)
+		merged = append(merged, bin)
+	}
+
+	previous := l.Bins
+	l.Bins = merged
+	if err := l.Validate(); err != nil {
+		l.Bins = previous
+		return err
+	}
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+func (l *WarehouseLayout) aisleIndex() map[string]Aisle {
+	aisles := make(map[string]Aisle, len(l.Aisles))
+	for _, aisle := range l.Aisles {
+		aisles[aisle.ID] = aisle
+	}
+	return aisles
+}
+
+// RoundTripDistances estima a ida e volta do depósito a cada endereço com coordenada
+func (l *WarehouseLayout) RoundTripDistances() (map[string]float64, error) {
+	graph, targets := l.routeGraph(l.Bins)
+	outbound, inbound := graph.shortestFrom(targets[0]), graph.reversed().shortestFrom(targets[0])
+
+	distances := make(map[string]float64, len(l.Bins))
+	for idx, bin := range l.Bins {
+		node := targets[idx+1]
+		if math.IsInf(outbound[node], 1) || math.IsInf(inbound[node], 1) {
+			return nil, fmt.Errorf("%w: %s", ErrPickLocationUnreached, bin.Location)
+		}
+		distances[bin.Location] = outbound[node] + inbound[node]
+	}
+	return distances, nil
+}
+
+// PlanPickRoute sequencia as linhas pelas três estratégias e escolhe a de menor distância
+func (l *WarehouseLayout) PlanPickRoute(items []Item) (*PickPlan, error) {
+	bins := make(map[string]BinCoordinate, len(l.Bins))
+	for _, bin := range l.Bins {
+		bins[bin.Location] = bin
+	}
+
+	// Agrupa as linhas por endereço; sem coordenada ficam fora da rota
+	var stops []PickStop
+	var unplaced []Item
+	stopIndex := make(map[string]int)
+	for _, item := range items {
+		if _, ok := bins[item.Location]; !ok {
+			unplaced = append(unplaced, item)
+			continue
+		}
+		idx, ok := stopIndex[item.Location]
+		if !ok {
+			idx = len(stops)
+			stopIndex[item.Location] = idx
+			stops = append(stops, PickStop{Location: item.Location})
+		}
+		stops[idx].Items = append(stops[idx].Items, item)
+	}
+	sort.SliceStable(unplaced, func(i, j int) bool { return unplaced[i].Location < unplaced[j].Location })
+
+	points := make([]BinCoordinate, len(stops))
+	for idx, stop := range stops {
+		points[idx] = bins[stop.Location]
+	}
+	dist, err := l.distanceMatrix(points)
+	if err != nil {
+		return nil, err
+	}
+
+	byLocation := make([]int, len(stops))
+	for idx := range byLocation {
+		byLocation[idx] = idx
+	}
+	sort.SliceStable(byLocation, func(i, j int) bool { return stops[byLocation[i]].Location < stops[byLocation[j]].Location })
+	sShape := l.sShapeOrder(points)
+	twoOpt := improveTwoOpt(sShape, dist)
+
+	plan := &PickPlan{}
+	for _, candidate := range []struct {
+		strategy RouteStrategy
+		order    []int
+	}{{RouteByLocation, byLocation}, {RouteSShape, sShape}, {RouteTwoOpt, twoOpt}} {
+		route := buildRoute(candidate.strategy, candidate.order, stops, dist)
+		route.Unplaced = unplaced
+		plan.Comparison = append(plan.Comparison, RouteComparison{Strategy: route.Strategy, Distance: route.Distance})
+		if plan.Route.Strategy == "" || route.Distance < plan.Route.Distance {
+			plan.Route = route
+		}
+	}
+	return plan, nil
+}
+
+// sShapeOrder percorre os corredores com separação a partir do lado mais próximo do depósito,
+// alternando o sentido (corredores de mão única impõem o seu)
+func (l *WarehouseLayout) sShapeOrder(points []BinCoordinate) []int {
+	if len(points) == 0 {
+		return nil
+	}
+	aisles := l.aisleIndex()
+	byAisle := make(map[string][]int)
+	var visited []Aisle
+	for idx, point := range points {
+		if _, ok := byAisle[point.Aisle]; !ok {
+			visited = append(visited, aisles[point.Aisle])
+		}
+		byAisle[point.Aisle] = append(byAisle[point.Aisle], idx)
+	}
+	sort.Slice(visited, func(i, j int) bool { return visited[i].X < visited[j].X })
+	if len(visited) > 1 && math.Abs(l.Depot.X-visited[len(visited)-1].X) < math.Abs(l.Depot.X-visited[0].X) {
+		for i, j := 0, len(visited)-1; i < j; i, j = i+1, j-1 {
+			visited[i], visited[j] = visited[j], visited[i]
+		}
+	}
+
+	// Depósito na frente: o primeiro corredor é percorrido para o fundo
+	up := math.Abs(l.Depot.Y-visited[0].Start) <= math.Abs(l.Depot.Y-visited[0].End)
+	order := make([]int, 0, len(points))
+	for _, aisle := range visited {
+		switch aisle.Direction {
+		case AisleUp:
+			up = true
+		case AisleDown:
+			up = false
+		}
+		members := byAisle[aisle.ID]
+		ascending := up
+		sort.SliceStable(members, func(i, j int) bool {
+			if ascending {
+				return points[members[i]].Position < points[members[j]].Position
+			}
+			return points[members[i]].Position > points[members[j]].Position
+		})
+		order = append(order, members...)
+		up = !up
+	}
+	return order
+}
+
+// improveTwoOpt inverte trechos da sequência enquanto a distância total diminuir.
+// A matriz pode ser assimétrica (corredores de mão única), então cada troca é reavaliada por inteiro.
+func improveTwoOpt(order []int, dist [][]float64) []int {
+	best := append([]int(nil), order...)
+	bestCost := routeCost(best, dist)
+	for pass := 0; pass < twoOptMaxPasses; pass++ {
+		improved := false
+		for i := 0; i < len(best)-1; i++ {
+			for k := i + 1; k < len(best); k++ {
+				candidate := append([]int(nil), best...)
+				for a, b := i, k; a < b; a, b = a+1, b-1 {
+					candidate[a], candidate[b] = candidate[b], candidate[a]
+				}
+				if cost := routeCost(candidate, dist); cost < bestCost-1e-9 {
+					best, bestCost, improved = candidate, cost, true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return best
+}
+
+// routeCost soma depósito → paradas → depósito; o índice 0 da matriz é o depósito
+func routeCost(order []int, dist [][]float64) float64 {
+	cost, from := 0.0, 0
+	for _, stop := range order {
+		cost += dist[from][stop+1]
+		from = stop + 1
+	}
+	return cost + dist[from][0]
+}
+
+func buildRoute(strategy RouteStrategy, order []int, stops []PickStop, dist [][]float64) PickRoute {
+	route := PickRoute{Strategy: strategy, Stops: make([]PickStop, 0, len(order))}
+	from := 0
+	for _, idx := range order {
+		stop := stops[idx]
+		stop.Distance = dist[from][idx+1]
+		route.Stops = append(route.Stops, stop)
+		from = idx + 1
+	}
+	route.Distance = routeCost(order, dist)
+	return route
+}
+
+// distanceMatrix calcula os menores caminhos entre depósito (índice 0) e endereços (1..n)
+// sobre o grafo de corredores e corredores transversais
+func (l *WarehouseLayout) distanceMatrix(points []BinCoordinate) ([][]float64, error) {
+	graph, targets := l.routeGraph(points)
+	dist := make([][]float64, len(targets))
+	for i, source := range targets {
+		shortest := graph.shortestFrom(source)
+		dist[i] = make([]float64, len(targets))
+		for j, target := range targets {
+			if math.IsInf(shortest[target], 1) {
+				location := "depot"
+				if j > 0 {
+					location = points[j-1].Location
+				}
+				return nil, fmt.Errorf("%w: %s", ErrPickLocationUnreached, location)
+			}
+			dist[i][j] = shortest[target]
+		}
+	}
+	return dist, nil
+}
+
+// routeGraph monta o grafo de corredores e transversais; targets[0] é o depósito e targets[i+1] o endereço points[i]
+func (l *WarehouseLayout) routeGraph(points []BinCoordinate) (*routeGraph, []int) {
+	graph := newRouteGraph()
+	aisles := l.aisleIndex()
+
+	targets := []int{graph.node(l.Depot)}
+	pickYs := make(map[string][]float64)
+	for _, point := range points {
+		aisle := aisles[point.Aisle]
+		targets = append(targets, graph.node(Point{X: aisle.X, Y: point.Position}))
+		pickYs[aisle.ID] = append(pickYs[aisle.ID], point.Position)
+	}
+
+	// Ao longo de cada corredor, respeitando o sentido de circulação
+	crossXs := make(map[float64][]float64)
+	for _, aisle := range l.Aisles {
+		ys := append([]float64(nil), pickYs[aisle.ID]...)
+		for _, y := range l.CrossAisles {
+			if y >= aisle.Start && y <= aisle.End {
+				ys = append(ys, y)
+				crossXs[y] = append(crossXs[y], aisle.X)
+			}
+		}
+		ys = uniqueSorted(ys)
+		for idx := 1; idx < len(ys); idx++ {
+			low, high := graph.node(Point{X: aisle.X, Y: ys[idx-1]}), graph.node(Point{X: aisle.X, Y: ys[idx]})
+			length := ys[idx] - ys[idx-1]
+			if aisle.Direction != AisleDown {
+				graph.edge(low, high, length)
+			}
+			if aisle.Direction != AisleUp {
+				graph.edge(high, low, length)
+			}
+		}
+	}
+
+	// Corredores transversais são de mão dupla
+	crossXs[l.Depot.Y] = append(crossXs[l.Depot.Y], l.Depot.X)
+	for y, xs := range crossXs {
+		xs = uniqueSorted(xs)
+		for idx := 1; idx < len(xs); idx++ {
+			a, b := graph.node(Point{X: xs[idx-1], Y: y}), graph.node(Point{X: xs[idx], Y: y})
+			graph.edge(a, b, xs[idx]-xs[idx-1])
+			graph.edge(b, a, xs[idx]-xs[idx-1])
+		}
+	}
+	return graph, targets
+}
+
+func uniqueSorted(values []float64) []float64 {
+	sort.Float64s(values)
+	unique := values[:0]
+	for idx, value := range values {
+		if idx == 0 || value != values[idx-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// routeGraph é um grafo dirigido com nós nas interseções, endereços e depósito
+type routeGraph struct {
+	index map[Point]int
+	edges [][]routeEdge
+}
+
+type routeEdge struct {
+	to     int
+	length float64
+}
+
+func newRouteGraph() *routeGraph {
+	return &routeGraph{index: make(map[Point]int)}
+}
+
+func (g *routeGraph) node(p Point) int {
+	if idx, ok := g.index[p]; ok {
+		return idx
+	}
+	g.index[p] = len(g.edges)
+	g.edges = append(g.edges, nil)
+	return len(g.edges) - 1
+}
+
+func (g *routeGraph) edge(from, to int, length float64) {
+	g.edges[from] = append(g.edges[from], routeEdge{to: to, length: length})
+}
+
+// reversed inverte o sentido das arestas (distâncias até um nó em vez de a partir dele)
+func (g *routeGraph) reversed() *routeGraph {
+	reversed := &routeGraph{index: g.index, edges: make([][]routeEdge, len(g.edges))}
+	for from, edges := range g.edges {
+		for _, edge := range edges {
+			reversed.edge(edge.to, from, edge.length)
+		}
+	}
+	return reversed
+}
+
+// shortestFrom executa Dijkstra a partir de source
+func (g *routeGraph) shortestFrom(source int) []float64 {
+	dist := make([]float64, len(g.edges))
+	for idx := range dist {
+		dist[idx] = math.Inf(1)
+	}
+	dist[source] = 0
+	queue := &routeQueue{{node: source}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(routeQueueItem)
+		if current.dist > dist[current.node] {
+			continue
+		}
+		for _, edge := range g.edges[current.node] {
+			if next := current.dist + edge.length; next < dist[edge.to] {
+				dist[edge.to] = next
+				heap.Push(queue, routeQueueItem{node: edge.to, dist: next})
+			}
+		}
+	}
+	return dist
+}
+
+type routeQueueItem struct {
+	node int
+	dist float64
+}
+
+type routeQueue []routeQueueItem
+
+func (q routeQueue) Len() int           { return len(q) }
+func (q routeQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q routeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *routeQueue) Push(x any)        { *q = append(*q, x.(routeQueueItem)) }
+func (q *routeQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
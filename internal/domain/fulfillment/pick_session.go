@@ -0,0 +1,270 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PickStepStatus é a etapa da linha na separação guiada: endereço, item e quantidade
+type PickStepStatus string
+
+const (
+	PickStepAwaitingLocation PickStepStatus = "AWAITING_LOCATION" // Aguardando a leitura do endereço
+	PickStepAwaitingItem     PickStepStatus = "AWAITING_ITEM"     // Endereço confirmado, aguardando a leitura do item
+	PickStepAwaitingQuantity PickStepStatus = "AWAITING_QUANTITY" // Item confirmado, aguardando a quantidade
+	PickStepPicked           PickStepStatus = "PICKED"
+	PickStepShort            PickStepStatus = "SHORT" // Separação parcial, com motivo
+)
+
+var (
+	ErrPickSessionNotFound     = errors.New("pick session not found")
+	ErrPickSessionExists       = errors.New("order already has an active pick session")
+	ErrPickSessionFinished     = errors.New("pick session is finished")
+	ErrPickSessionConflict     = errors.New("pick session was modified concurrently")
+	ErrPickStepOutOfSequence   = errors.New("pick step is not awaiting this scan")
+	ErrWrongLocation           = errors.New("scanned location does not match the pick step")
+	ErrWrongSKU                = errors.New("scanned item does not match the pick step")
+	ErrWrongBatch              = errors.New("scanned batch does not match the pick step")
+	ErrOverPick                = errors.New("quantity exceeds the quantity to pick")
+	ErrInvalidPickQuantity     = errors.New("pick quantity must not be negative")
+	ErrShortPickReasonRequired = errors.New("short pick requires a reason")
+)
+
+// PickStep é uma linha da ordem a separar, na sequência da rota
+type PickStep struct {
+	Sequence    int            `json:"sequence"`
+	Line        int            `json:"line"` // Índice da linha em FulfillmentOrder.Items
+	SKU         string         `json:"sku"`
+	Batch       string         `json:"batch,omitempty"` // Lote exigido pela linha da ordem
+	Location    string         `json:"location,omitempty"`
+	Quantity    int            `json:"quantity"`               // Unidade base (EA)
+	PickedBatch string         `json:"picked_batch,omitempty"` // Lote lido (ou o exigido, se a leitura não trouxer lote)
+	Picked      int            `json:"picked"`
+	Status      PickStepStatus `json:"status"`
+	ShortReason string         `json:"short_reason,omitempty"`
+	PickedAt    *time.Time     `json:"picked_at,omitempty"`
+}
+
+// PickSession conduz o coletor pelas linhas de uma ordem, leitura a leitura. Cada linha exige o
+// endereço, o item e a quantidade, nessa ordem; a sessão termina na confirmação da última linha.
+type PickSession struct {
+	ID          string     `json:"id"`
+	OrderID     string     `json:"fulfillment_order_id"`
+	TaskID      string     `json:"task_id"` // Tarefa PICK da ordem, concluída ao final da sessão
+	Operator    string     `json:"operator"`
+	DeviceID    string     `json:"device_id,omitempty"`
+	Status      Status     `json:"status"` // IN_PROGRESS ou COMPLETED
+	Steps       []PickStep `json:"steps"`
+	Current     int        `json:"current_step"` // Índice do passo em andamento (len(Steps) ao final)
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Version     int64      `json:"version"` // Controle de concorrência otimista (leituras simultâneas)
+}
+
+// NewPickSession cria a sessão com um passo por linha da ordem. As linhas seguem a ordem dos endereços
+// em route (paradas da rota planejada); as demais vêm depois, em ordem alfabética do endereço.
+// Linhas sem endereço dispensam a leitura do endereço.
+func NewPickSession(order *FulfillmentOrder, taskID, operator, device string, route []string) (*PickSession, error) {
+	if operator == "" {
+		return nil, ErrTaskAssigneeRequired
+	}
+	rank := make(map[string]int, len(route))
+	for idx, location := range route {
+		if _, ok := rank[location]; !ok {
+			rank[location] = idx
+		}
+	}
+
+	lines := make([]int, 0, len(order.Items))
+	for idx, item := range order.Items {
+		if item.Quantity > 0 {
+			lines = append(lines, idx)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, ErrEmptyItems
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := order.Items[lines[i]].Location, order.Items[lines[j]].Location
+		rankA, routedA := rank[a]
+		rankB, routedB := rank[b]
+		switch {
+		case routedA && routedB:
+			return rankA < rankB
+		case routedA != routedB:
+			return routedA
+		default:
+			return a < b
+		}
+	})
+
+	now := time.Now()
+	session := &PickSession{
+		ID:        uuid.New().String(),
+		OrderID:   order.ID,
+		TaskID:    taskID,
+		Operator:  operator,
+		DeviceID:  device,
+		Status:    StatusInProgress,
+		Steps:     make([]PickStep, len(lines)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for seq, line := range lines {
+		item := order.Items[line]
+		status := PickStepAwaitingLocation
+		if item.Location == "" {
+			status = PickStepAwaitingItem
+		}
+		session.Steps[seq] = PickStep{
+			Sequence: seq + 1,
+			Line:     line,
+			SKU:      item.SKU,
+			Batch:    item.Batch,
+			Location: item.Location,
+			Quantity: item.Quantity,
+			Status:   status,
+		}
+	}
+	return session, nil
+}
+
+// CurrentStep retorna o passo em andamento (nil quando a sessão terminou)
+func (s *PickSession) CurrentStep() *PickStep {
+	if s.Current >= len(s.Steps) {
+		return nil
+	}
+	return &s.Steps[s.Current]
+}
+
+// Done indica se todas as linhas foram confirmadas
+func (s *PickSession) Done() bool {
+	return s.Status == StatusCompleted
+}
+
+// ScanLocation confirma o endereço do passo atual (nova leitura do mesmo endereço é aceita)
+func (s *PickSession) ScanLocation(location string) error {
+	step, err := s.step(PickStepAwaitingLocation, PickStepAwaitingItem)
+	if err != nil {
+		return err
+	}
+	if !sameLocation(step.Location, location) {
+		return fmt.Errorf("%w: expected %s, scanned %s", ErrWrongLocation, step.Location, location)
+	}
+	step.Status = PickStepAwaitingItem
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// ScanItem confirma o SKU (e o lote, quando a linha o exige) do passo atual; nova leitura substitui a anterior
+func (s *PickSession) ScanItem(item Item) error {
+	step, err := s.step(PickStepAwaitingItem, PickStepAwaitingQuantity)
+	if err != nil {
+		return err
+	}
+	if item.SKU != step.SKU {
+		return fmt.Errorf("%w: expected %s, scanned %s", ErrWrongSKU, step.SKU, item.SKU)
+	}
+	if step.Batch != "" && item.Batch != "" && item.Batch != step.Batch {
+		return fmt.Errorf("%w: expected %s, scanned %s", ErrWrongBatch, step.Batch, item.Batch)
+	}
+	step.PickedBatch = item.Batch
+	if step.PickedBatch == "" {
+		step.PickedBatch = step.Batch
+	}
+	step.Status = PickStepAwaitingQuantity
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// ConfirmQuantity registra a quantidade separada no passo atual e avança para o próximo.
+// Quantidade menor que a da linha exige motivo; maior é rejeitada.
+func (s *PickSession) ConfirmQuantity(quantity int, reason string) error {
+	step, err := s.step(PickStepAwaitingQuantity)
+	if err != nil {
+		return err
+	}
+	switch {
+	case quantity < 0:
+		return ErrInvalidPickQuantity
+	case quantity > step.Quantity:
+		return fmt.Errorf("%w: %d of %d %s", ErrOverPick, quantity, step.Quantity, step.SKU)
+	case quantity < step.Quantity && strings.TrimSpace(reason) == "":
+		return fmt.Errorf("%w: %d of %d %s", ErrShortPickReasonRequired, quantity, step.Quantity, step.SKU)
+	}
+
+	now := time.Now()
+	step.Picked, step.PickedAt, step.Status = quantity, &now, PickStepPicked
+	if quantity < step.Quantity {
+		step.Status, step.ShortReason = PickStepShort, strings.TrimSpace(reason)
+	}
+	s.Current++
+	s.UpdatedAt = now
+	if s.Current == len(s.Steps) {
+		s.Status, s.CompletedAt = StatusCompleted, &now
+	}
+	return nil
+}
+
+// ShortSteps retorna os passos separados parcialmente
+func (s *PickSession) ShortSteps() []PickStep {
+	var short []PickStep
+	for _, step := range s.Steps {
+		if step.Status == PickStepShort {
+			short = append(short, step)
+		}
+	}
+	return short
+}
+
+// PickedLines aplica às linhas da ordem as quantidades e lotes separados, removendo as linhas
+// não separadas. Se as linhas não correspondem mais aos passos (ordem já ajustada), são mantidas.
+func (s *PickSession) PickedLines(items []Item) []Item {
+	for _, step := range s.Steps {
+		if step.Line >= len(items) || items[step.Line].SKU != step.SKU || items[step.Line].Quantity != step.Quantity {
+			return items
+		}
+	}
+	steps := make(map[int]PickStep, len(s.Steps))
+	for _, step := range s.Steps {
+		steps[step.Line] = step
+	}
+	picked := make([]Item, 0, len(items))
+	for idx, item := range items {
+		step, ok := steps[idx]
+		if !ok || step.Picked == 0 {
+			continue
+		}
+		if step.Picked < item.Quantity {
+			item.PackUoM, item.PackQuantity = "", 0
+		}
+		item.Quantity, item.Batch = step.Picked, step.PickedBatch
+		picked = append(picked, item)
+	}
+	return picked
+}
+
+// step retorna o passo atual se ele estiver em uma das etapas esperadas
+func (s *PickSession) step(expected ...PickStepStatus) (*PickStep, error) {
+	step := s.CurrentStep()
+	if s.Status != StatusInProgress || step == nil {
+		return nil, ErrPickSessionFinished
+	}
+	for _, status := range expected {
+		if step.Status == status {
+			return step, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: step %d is %s", ErrPickStepOutOfSequence, step.Sequence, step.Status)
+}
+
+// sameLocation compara endereços sem diferenciar maiúsculas e espaços nas pontas
+func sameLocation(expected, scanned string) bool {
+	return strings.EqualFold(strings.TrimSpace(expected), strings.TrimSpace(scanned))
+}
@@ -10,6 +10,14 @@ type Policy struct {
 	MaxTransferDurationMinutes   int
 	MaxReturnDurationMinutes     int
 	MaxCycleCountDurationMinutes int
+
+	// Validade da reserva de estoque criada na entrada do pedido (em minutos)
+	ReservationTTLMinutes int
+
+	// Retry automático de falhas transitórias (backoff exponencial)
+	RetryMaxAttempts      int
+	RetryBaseDelaySeconds int
+	RetryMaxDelayMinutes  int
 }
 
 // DefaultPolicy retorna a política padrão
@@ -20,32 +28,56 @@ func DefaultPolicy() *Policy {
 		MaxTransferDurationMinutes:   180, // 3 horas para transferência
 		MaxReturnDurationMinutes:     90,  // 1.5 horas para devolução
 		MaxCycleCountDurationMinutes: 240, // 4 horas para contagem
+		ReservationTTLMinutes:        480, // 8 horas de reserva
+		RetryMaxAttempts:             5,
+		RetryBaseDelaySeconds:        30,
+		RetryMaxDelayMinutes:         15,
 	}
 }
 
-// ValidateStateTransition valida se uma transição de estado é válida
-func ValidateStateTransition(from, to Status) bool {
-	validTransitions := map[Status][]Status{
-		StatusPending:    {StatusInProgress, StatusCancelled},
-		StatusInProgress: {StatusCompleted, StatusFailed, StatusCancelled, StatusBlocked},
-		StatusBlocked:    {StatusInProgress, StatusCancelled},
-		StatusFailed:     {StatusPending, StatusCancelled},
-		StatusCancelled:  {}, // Estado final
-		StatusCompleted:  {}, // Estado final
-	}
+// ReservationTTL retorna a validade da reserva de estoque
+func (p *Policy) ReservationTTL() time.Duration {
+	return time.Duration(p.ReservationTTLMinutes) * time.Minute
+}
 
-	allowed, exists := validTransitions[from]
-	if !exists {
-		return false
+// RetryBackoff retorna a espera antes da próxima tentativa (base * 2^(attempt-1), com teto)
+func (p *Policy) RetryBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
 	}
+	delay := time.Duration(p.RetryBaseDelaySeconds) * time.Second
+	maxDelay := time.Duration(p.RetryMaxDelayMinutes) * time.Minute
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
 
-	for _, allowedStatus := range allowed {
-		if allowedStatus == to {
-			return true
-		}
+// MaxDurationMinutes retorna o SLA do tipo de operação
+func (p *Policy) MaxDurationMinutes(op OperationType) int {
+	switch op {
+	case OpInbound:
+		return p.MaxInboundDurationMinutes
+	case OpOutbound:
+		return p.MaxOutboundDurationMinutes
+	case OpTransfer:
+		return p.MaxTransferDurationMinutes
+	case OpReturn:
+		return p.MaxReturnDurationMinutes
+	case OpCycleCount:
+		return p.MaxCycleCountDurationMinutes
+	default:
+		return 0
 	}
+}
 
-	return false
+// ValidateStateTransition valida se uma transição de estado é válida.
+// Delega à máquina de estados; o workflow de status é o mesmo para todas as operações.
+func ValidateStateTransition(from, to Status) bool {
+	return stateMachines[OpOutbound].CanTransition(from, to)
 }
 
 // CheckSLA verifica se uma operação está dentro do SLA
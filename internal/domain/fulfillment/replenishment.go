@@ -0,0 +1,132 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReplenishmentMode define como a necessidade de reabastecimento é calculada
+type ReplenishmentMode string
+
+const (
+	ReplenishMinMax ReplenishmentMode = "MIN_MAX" // Abaixo do mínimo, completa até o máximo
+	ReplenishDemand ReplenishmentMode = "DEMAND"  // Cobre a demanda liberada mais o mínimo de segurança
+)
+
+// Prioridades das tarefas de reabastecimento (acima de ordens expressas quando há falta iminente)
+const (
+	ReplenishPriorityTopOff    = 0 // Reposição preventiva, sem demanda pendente
+	ReplenishPriorityDemand    = 2 // Há demanda liberada para o endereço
+	ReplenishPriorityShortPick = 3 // Saldo não cobre a demanda liberada: separação ficaria incompleta
+)
+
+var (
+	ErrReplenishmentRuleNotFound = errors.New("replenishment rule not found")
+	ErrInvalidReplenishmentRule  = errors.New("invalid replenishment rule")
+	ErrReplenishmentMoveChanged  = errors.New("replenishment rule move was changed concurrently")
+)
+
+// ReplenishmentRule é a regra de reabastecimento de um SKU em um endereço de picking
+type ReplenishmentRule struct {
+	ID              string            `json:"id"`
+	SKU             string            `json:"sku"`
+	PickLocation    string            `json:"pick_location"`
+	ReserveLocation string            `json:"reserve_location"` // Origem (pulmão)
+	Mode            ReplenishmentMode `json:"mode"`
+	Min             int               `json:"min"`                     // Ponto de reposição / estoque de segurança
+	Max             int               `json:"max"`                     // Capacidade do endereço (0 = sem limite no modo DEMAND)
+	CaseQuantity    int               `json:"case_quantity,omitempty"` // Arredonda a movimentação para caixas fechadas
+	Enabled         bool              `json:"enabled"`
+	OpenTransferID  string            `json:"open_transfer_id,omitempty"` // Movimentação em andamento
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// NewReplenishmentRule cria e valida uma regra de reabastecimento
+func NewReplenishmentRule(sku, pickLocation, reserveLocation string, mode ReplenishmentMode, min, max, caseQuantity int) (*ReplenishmentRule, error) {
+	now := time.Now()
+	rule := &ReplenishmentRule{
+		ID:              uuid.New().String(),
+		SKU:             sku,
+		PickLocation:    pickLocation,
+		ReserveLocation: reserveLocation,
+		Mode:            mode,
+		Min:             min,
+		Max:             max,
+		CaseQuantity:    caseQuantity,
+		Enabled:         true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Validate verifica a consistência da regra
+func (r *ReplenishmentRule) Validate() error {
+	switch {
+	case r.SKU == "" || r.PickLocation == "" || r.ReserveLocation == "":
+		return fmt.Errorf("%w: sku, pick_location and reserve_location are required", ErrInvalidReplenishmentRule)
+	case r.PickLocation == r.ReserveLocation:
+		return fmt.Errorf("%w: pick and reserve locations must differ", ErrInvalidReplenishmentRule)
+	case r.Mode != ReplenishMinMax && r.Mode != ReplenishDemand:
+		return fmt.Errorf("%w: mode must be MIN_MAX or DEMAND", ErrInvalidReplenishmentRule)
+	case r.Min < 0 || r.Max < 0 || r.CaseQuantity < 0:
+		return fmt.Errorf("%w: quantities must not be negative", ErrInvalidReplenishmentRule)
+	case r.Mode == ReplenishMinMax && r.Max <= r.Min:
+		return fmt.Errorf("%w: max must be greater than min", ErrInvalidReplenishmentRule)
+	}
+	return nil
+}
+
+// Evaluate calcula a quantidade a mover para o endereço de picking e a prioridade da movimentação.
+// onHand é o saldo no endereço, inTransit o que já está em reabastecimento e demand a demanda liberada.
+// Retorna quantidade 0 quando não há necessidade.
+func (r *ReplenishmentRule) Evaluate(onHand, inTransit, demand int) (int, int) {
+	if !r.Enabled {
+		return 0, 0
+	}
+	projected := onHand + inTransit
+
+	var quantity int
+	switch r.Mode {
+	case ReplenishMinMax:
+		if projected > r.Min && projected >= demand {
+			return 0, 0
+		}
+		quantity = r.Max - projected
+		if demand > r.Max {
+			quantity = demand - projected
+		}
+	case ReplenishDemand:
+		quantity = demand + r.Min - projected
+		if r.Max > 0 && projected+quantity > r.Max && demand <= r.Max {
+			quantity = r.Max - projected
+		}
+	}
+	if quantity <= 0 {
+		return 0, 0
+	}
+	if r.CaseQuantity > 0 && quantity%r.CaseQuantity != 0 {
+		quantity += r.CaseQuantity - quantity%r.CaseQuantity
+	}
+
+	return quantity, ReplenishmentPriority(projected, demand)
+}
+
+// ReplenishmentPriority prioriza a movimentação frente à demanda liberada para o endereço
+func ReplenishmentPriority(available, demand int) int {
+	switch {
+	case available < demand:
+		return ReplenishPriorityShortPick
+	case demand > 0:
+		return ReplenishPriorityDemand
+	default:
+		return ReplenishPriorityTopOff
+	}
+}
@@ -1,6 +1,9 @@
 package fulfillment
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository define a interface de persistência para entidades de fulfillment
 type Repository interface {
@@ -17,6 +20,11 @@ type Repository interface {
 	GetOrderByOrderID(ctx context.Context, orderID string) (*FulfillmentOrder, error)
 	UpdateOrderStatus(ctx context.Context, id string, status Status) error
 	UpdateOrder(ctx context.Context, order *FulfillmentOrder) error
+	// ListOrdersByReservationStatus lista até limit ordens no status de reserva com ID maior que afterID, em ordem de ID
+	ListOrdersByReservationStatus(ctx context.Context, status ReservationStatus, afterID string, limit int) ([]*FulfillmentOrder, error)
+	// ListOrphanedReservations lista as ordens com reserva ativa que não precisam mais dela:
+	// CANCELLED/FAILED ou PENDING com a reserva expirada em now
+	ListOrphanedReservations(ctx context.Context, now time.Time, limit int) ([]*FulfillmentOrder, error)
 
 	// Transfer
 	CreateTransfer(ctx context.Context, transfer *TransferOrder) error
@@ -36,3 +44,223 @@ type Repository interface {
 	UpdateCycleCountStatus(ctx context.Context, id string, status Status) error
 	UpdateCycleCount(ctx context.Context, task *CycleCountTask) error
 }
+
+// HistoryRepository reconstrói o estado de agregados event-sourced em um instante passado
+type HistoryRepository interface {
+	GetOrderAt(ctx context.Context, id string, at time.Time) (*FulfillmentOrder, error)
+	GetTransferAt(ctx context.Context, id string, at time.Time) (*TransferOrder, error)
+	GetReturnAt(ctx context.Context, id string, at time.Time) (*ReturnOrder, error)
+}
+
+// FailureRepository lista operações em FAILED para reexecução (entityType vazio = todas)
+type FailureRepository interface {
+	ListFailedOperations(ctx context.Context, entityType string, since time.Time, limit int) ([]FailedOperation, error)
+	// ListDueFailures lista, de todas as entidades, apenas as falhas transitórias com backoff expirado em now
+	// e criadas a partir do corte de SLA da operação (operações sem corte não têm limite)
+	ListDueFailures(ctx context.Context, since, now time.Time, createdAfter map[OperationType]time.Time, limit int) ([]FailedOperation, error)
+}
+
+// BlockRepository consulta operações bloqueadas e registra escalonamentos (entityType vazio = todas)
+type BlockRepository interface {
+	ListBlocked(ctx context.Context, entityType string, blockedBefore time.Time, limit int) ([]BlockedEntity, error)
+	// ListUnescalatedBlocked lista, de todas as entidades, apenas os bloqueios ainda não escalonados
+	ListUnescalatedBlocked(ctx context.Context, blockedBefore time.Time, limit int) ([]BlockedEntity, error)
+	MarkBlockEscalated(ctx context.Context, entityType, id string, at time.Time) error
+}
+
+// TaskRepository persiste tarefas de armazém. UpdateTask aplica controle otimista pela versão
+// e retorna ErrTaskConflict se a tarefa foi alterada por outro operador.
+type TaskRepository interface {
+	CreateTask(ctx context.Context, task *WarehouseTask) error
+	GetTaskByID(ctx context.Context, id string) (*WarehouseTask, error)
+	GetTaskByEntity(ctx context.Context, entityType, entityID string) (*WarehouseTask, error)
+	UpdateTask(ctx context.Context, task *WarehouseTask) error
+	// ListOpenTasks lista tarefas PENDING, ASSIGNED e IN_PROGRESS por prioridade
+	ListOpenTasks(ctx context.Context, limit int) ([]*WarehouseTask, error)
+	// ListTasksByAssignee lista as tarefas do operador, das mais recentes para as mais antigas
+	ListTasksByAssignee(ctx context.Context, assignee string, limit int) ([]*WarehouseTask, error)
+}
+
+// ReplenishmentRuleRepository persiste regras de reabastecimento (uma por SKU e endereço de picking)
+type ReplenishmentRuleRepository interface {
+	// SaveReplenishmentRule grava a configuração da regra; a movimentação em andamento de uma regra
+	// existente é preservada
+	SaveReplenishmentRule(ctx context.Context, rule *ReplenishmentRule) error
+	// UpdateReplenishmentTransfer troca a movimentação em andamento da regra apenas se ainda for from
+	// (vazio = nenhuma); ErrReplenishmentMoveChanged caso contrário
+	UpdateReplenishmentTransfer(ctx context.Context, id, from, to string) error
+	// ListReplenishmentRules lista as regras do endereço (pickLocation vazio = todas)
+	ListReplenishmentRules(ctx context.Context, pickLocation string) ([]*ReplenishmentRule, error)
+	DeleteReplenishmentRule(ctx context.Context, id string) error
+}
+
+// KitRepository persiste definições de kit e ordens de montagem/desmontagem
+type KitRepository interface {
+	SaveKit(ctx context.Context, kit *KitDefinition) error
+	GetKit(ctx context.Context, sku string) (*KitDefinition, error)
+	ListKits(ctx context.Context) ([]*KitDefinition, error)
+	DeleteKit(ctx context.Context, sku string) error
+	CreateAssemblyOrder(ctx context.Context, order *AssemblyOrder) error
+	GetAssemblyOrderByID(ctx context.Context, id string) (*AssemblyOrder, error)
+	UpdateAssemblyOrder(ctx context.Context, order *AssemblyOrder) error
+}
+
+// CrossDockRepository persiste políticas (uma por cliente e SKU) e alocações de cross-dock
+type CrossDockRepository interface {
+	SaveCrossDockPolicy(ctx context.Context, policy *CrossDockPolicy) error
+	ListCrossDockPolicies(ctx context.Context) ([]*CrossDockPolicy, error)
+	DeleteCrossDockPolicy(ctx context.Context, id string) error
+	CreateCrossDockAllocation(ctx context.Context, allocation *CrossDockAllocation) error
+	GetCrossDockAllocationByTransfer(ctx context.Context, transferID string) (*CrossDockAllocation, error)
+	// ListCrossDockAllocations lista as alocações do recebimento (shipmentID vazio = mais recentes)
+	ListCrossDockAllocations(ctx context.Context, shipmentID string, limit int) ([]*CrossDockAllocation, error)
+}
+
+// DockRepository persiste portas de doca e agendamentos
+type DockRepository interface {
+	SaveDockDoor(ctx context.Context, door *DockDoor) error
+	GetDockDoor(ctx context.Context, id string) (*DockDoor, error)
+	ListDockDoors(ctx context.Context) ([]*DockDoor, error)
+	// CreateAppointment grava o agendamento; retorna ErrAppointmentConflict se a porta já estiver reservada no intervalo
+	CreateAppointment(ctx context.Context, appointment *DockAppointment) error
+	GetAppointment(ctx context.Context, id string) (*DockAppointment, error)
+	// UpdateAppointment grava o agendamento apenas se ainda estiver no status from (ErrAppointmentChanged caso contrário)
+	UpdateAppointment(ctx context.Context, appointment *DockAppointment, from AppointmentStatus) error
+	// ListAppointments lista os agendamentos iniciados em [from, to) (doorID vazio = todas as portas)
+	ListAppointments(ctx context.Context, doorID string, from, to time.Time) ([]*DockAppointment, error)
+}
+
+// UoMRepository persiste a hierarquia de embalagens por SKU
+type UoMRepository interface {
+	SaveUoMHierarchy(ctx context.Context, hierarchy *UoMHierarchy) error
+	GetUoMHierarchy(ctx context.Context, sku string) (*UoMHierarchy, error)
+	ListUoMHierarchies(ctx context.Context) ([]*UoMHierarchy, error)
+	// FindUoMHierarchyByGTIN busca a hierarquia que tem o GTIN (14 dígitos) na unidade base ou em uma embalagem
+	FindUoMHierarchyByGTIN(ctx context.Context, gtin string) (*UoMHierarchy, error)
+	DeleteUoMHierarchy(ctx context.Context, sku string) error
+}
+
+// LPNRepository persiste contêineres (license plates) e seu histórico
+type LPNRepository interface {
+	// CreateLPN retorna ErrLPNExists se o código já estiver em uso
+	CreateLPN(ctx context.Context, lpn *LPN, event LPNEvent) error
+	GetLPN(ctx context.Context, code string) (*LPN, error)
+	ListLPNChildren(ctx context.Context, parentCode string) ([]*LPN, error)
+	// SaveLPNs grava os contêineres alterados e os registros de histórico na mesma transação
+	SaveLPNs(ctx context.Context, lpns []*LPN, events []LPNEvent) error
+	ListLPNEvents(ctx context.Context, code string) ([]LPNEvent, error)
+}
+
+// LayoutRepository persiste o layout do armazém (documento único) usado no roteamento da separação
+type LayoutRepository interface {
+	SaveLayout(ctx context.Context, layout *WarehouseLayout) error
+	// GetLayout retorna ErrLayoutNotFound enquanto nenhum layout foi definido
+	GetLayout(ctx context.Context) (*WarehouseLayout, error)
+}
+
+// ShipmentHistoryRepository fornece o histórico de expedição usado nas análises de giro
+type ShipmentHistoryRepository interface {
+	// ListShippedOrders lista as ordens expedidas desde since
+	ListShippedOrders(ctx context.Context, since time.Time) ([]*FulfillmentOrder, error)
+}
+
+// SlottingRepository fornece o histórico de expedição e persiste as recomendações de realocação
+type SlottingRepository interface {
+	ShipmentHistoryRepository
+	// ReplaceSlottingProposals substitui as recomendações ainda PROPOSED pelas da nova análise
+	ReplaceSlottingProposals(ctx context.Context, recommendations []*SlottingRecommendation) error
+	GetSlottingRecommendation(ctx context.Context, id string) (*SlottingRecommendation, error)
+	// UpdateSlottingRecommendation grava a recomendação apenas se ainda estiver no status from (ErrSlottingNotProposed caso contrário)
+	UpdateSlottingRecommendation(ctx context.Context, recommendation *SlottingRecommendation, from SlottingStatus) error
+	// ListSlottingRecommendations filtra pelo status (vazio = todas), maior economia primeiro
+	ListSlottingRecommendations(ctx context.Context, status SlottingStatus) ([]*SlottingRecommendation, error)
+}
+
+// CountProgramRepository persiste o programa de contagem cíclica e os gatilhos de contagem extra
+type CountProgramRepository interface {
+	SaveCountProgram(ctx context.Context, program *CountProgram) error
+	GetCountProgram(ctx context.Context) (*CountProgram, error)
+	// ListLocationCountHistory resume as contagens de cada endereço já contado
+	ListLocationCountHistory(ctx context.Context) ([]LocationCountHistory, error)
+	CreateCountTrigger(ctx context.Context, trigger *CountTrigger) error
+	// ListPendingCountTriggers lista os gatilhos ainda sem contagem gerada, mais antigos primeiro
+	ListPendingCountTriggers(ctx context.Context) ([]*CountTrigger, error)
+	MarkCountTriggersPlanned(ctx context.Context, ids []string, taskID string) error
+}
+
+// ManifestCloser fecha o manifesto com as expedições incluídas e retorna os documentos gerados
+type ManifestCloser func(manifest *CarrierManifest, shipments []*OutboundShipment) (map[ManifestFormat][]byte, error)
+
+// ManifestRepository persiste as expedições entregues às transportadoras e seus manifestos diários
+type ManifestRepository interface {
+	// AddManifestShipment grava a expedição e os totais do manifesto (criado se novo);
+	// retorna ErrManifestClosed se o manifesto foi fechado e ErrOutboundShipmentExists se a ordem já foi entregue
+	AddManifestShipment(ctx context.Context, manifest *CarrierManifest, shipment *OutboundShipment) error
+	GetManifest(ctx context.Context, id string) (*CarrierManifest, error)
+	// GetManifestFor busca o manifesto da transportadora no dia
+	GetManifestFor(ctx context.Context, carrier string, date time.Time) (*CarrierManifest, error)
+	// ListManifests filtra por transportadora e dia (vazios = todos), mais recentes primeiro
+	ListManifests(ctx context.Context, carrier string, date *time.Time) ([]*CarrierManifest, error)
+	ListManifestShipments(ctx context.Context, manifestID string) ([]*OutboundShipment, error)
+	// GetOutboundShipmentByOrder retorna ErrOutboundShipmentNotFound se a ordem não foi entregue à transportadora
+	GetOutboundShipmentByOrder(ctx context.Context, orderID string) (*OutboundShipment, error)
+	// CloseManifest trava o manifesto, lê suas expedições e o fecha numa única transação, de modo que
+	// nenhuma inclusão concorrente fique fora dos documentos. close aplica o fechamento ao manifesto
+	// travado e gera os documentos guardados com ele.
+	CloseManifest(ctx context.Context, id string, close ManifestCloser) (*CarrierManifest, []*OutboundShipment, error)
+	GetManifestDocument(ctx context.Context, id string, format ManifestFormat) ([]byte, error)
+}
+
+// DocumentRepository persiste os modelos de documento e os documentos gerados para reimpressão
+type DocumentRepository interface {
+	// SaveDocumentTemplate substitui o modelo do cliente para o tipo e formato
+	SaveDocumentTemplate(ctx context.Context, tmpl *DocumentTemplate) error
+	// GetDocumentTemplate retorna ErrDocumentTemplateNotFound se o cliente não tiver modelo próprio
+	GetDocumentTemplate(ctx context.Context, customer string, docType DocumentType, format DocumentFormat) (*DocumentTemplate, error)
+	ListDocumentTemplates(ctx context.Context) ([]*DocumentTemplate, error)
+	DeleteDocumentTemplate(ctx context.Context, id string) error
+	CreateRenderedDocument(ctx context.Context, document *RenderedDocument) error
+	GetRenderedDocument(ctx context.Context, id string) (*RenderedDocument, error)
+	// ListRenderedDocuments lista os documentos da ordem sem o conteúdo, mais recentes primeiro
+	ListRenderedDocuments(ctx context.Context, orderID string) ([]*RenderedDocument, error)
+}
+
+// PickSessionRepository persiste as sessões de separação guiada dos coletores
+type PickSessionRepository interface {
+	// CreatePickSession retorna ErrPickSessionExists se a ordem já tiver uma sessão em andamento
+	CreatePickSession(ctx context.Context, session *PickSession) error
+	GetPickSession(ctx context.Context, id string) (*PickSession, error)
+	// GetActivePickSession retorna a sessão em andamento da ordem (ErrPickSessionNotFound se não houver)
+	GetActivePickSession(ctx context.Context, orderID string) (*PickSession, error)
+	// UpdatePickSession grava a sessão se a versão não mudou desde a leitura (ErrPickSessionConflict)
+	UpdatePickSession(ctx context.Context, session *PickSession) error
+}
+
+// SyncRepository persiste o estado sincronizado das chaves alteradas pelos coletores e a fila de
+// conflitos para revisão do supervisor
+type SyncRepository interface {
+	// GetSyncState retorna ErrSyncStateNotFound se a chave nunca foi sincronizada
+	GetSyncState(ctx context.Context, key string) (*SyncState, error)
+	// SaveSyncState insere (Version 0) ou atualiza o estado se a versão não mudou (ErrSyncStateConflict)
+	SaveSyncState(ctx context.Context, state *SyncState) error
+	// DeleteSyncState remove o estado da chave se a versão não mudou (ErrSyncStateConflict)
+	DeleteSyncState(ctx context.Context, key string, version int64) error
+	// CreateSyncConflict é idempotente por coletor e operação: no reenvio, conflict.ID recebe o ID existente
+	CreateSyncConflict(ctx context.Context, conflict *SyncConflictRecord) error
+	GetSyncConflict(ctx context.Context, id string) (*SyncConflictRecord, error)
+	// ListSyncConflicts filtra pelo status (vazio = todos), mais antigos primeiro
+	ListSyncConflicts(ctx context.Context, status Status) ([]*SyncConflictRecord, error)
+	UpdateSyncConflict(ctx context.Context, conflict *SyncConflictRecord) error
+}
+
+// PriorityRepository persiste os calendários de corte das transportadoras e lista as ordens abertas
+// para a priorização
+type PriorityRepository interface {
+	// SaveCutoffCalendar inclui ou substitui o calendário da transportadora e serviço (calendar.ID recebe o ID gravado)
+	SaveCutoffCalendar(ctx context.Context, calendar *CutoffCalendar) error
+	// ListCutoffCalendars lista os calendários da transportadora (vazio = todos)
+	ListCutoffCalendars(ctx context.Context, carrier string) ([]*CutoffCalendar, error)
+	DeleteCutoffCalendar(ctx context.Context, id string) error
+	// ListOpenOrders lista até limit ordens PENDING e IN_PROGRESS com ID maior que afterID, em ordem de ID
+	ListOpenOrders(ctx context.Context, afterID string, limit int) ([]*FulfillmentOrder, error)
+}
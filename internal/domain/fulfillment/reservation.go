@@ -0,0 +1,62 @@
+package fulfillment
+
+import (
+	"errors"
+	"time"
+)
+
+// ReservationStatus representa o ciclo de vida da reserva de estoque de uma FulfillmentOrder
+type ReservationStatus string
+
+const (
+	ReservationNone        ReservationStatus = "NONE"        // Nenhuma reserva solicitada
+	ReservationReserved    ReservationStatus = "RESERVED"    // Estoque reservado no Core
+	ReservationBackordered ReservationStatus = "BACKORDERED" // Sem saldo, aguardando reposição
+	ReservationConfirmed   ReservationStatus = "CONFIRMED"   // Baixa definitiva na expedição
+	ReservationReleased    ReservationStatus = "RELEASED"    // Liberada (cancelamento/falha)
+	ReservationExpired     ReservationStatus = "EXPIRED"     // Expirada e liberada pelo reconciliador
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrOrderBackordered  = errors.New("fulfillment order is backordered")
+	ErrNotBackordered    = errors.New("fulfillment order is not backordered")
+)
+
+// HoldsReservation indica se a ordem mantém estoque reservado no Core
+func (f *FulfillmentOrder) HoldsReservation() bool {
+	return f.ReservationStatus == ReservationReserved
+}
+
+// ReservationExpiredAt indica se a reserva ativa já expirou no instante informado
+func (f *FulfillmentOrder) ReservationExpiredAt(now time.Time) bool {
+	return f.HoldsReservation() && f.ReservationExpiresAt != nil && now.After(*f.ReservationExpiresAt)
+}
+
+// MarkReserved registra a reserva de estoque com sua expiração
+func (f *FulfillmentOrder) MarkReserved(expiresAt time.Time) {
+	f.ReservationStatus = ReservationReserved
+	f.ReservationExpiresAt = &expiresAt
+	f.UpdatedAt = time.Now()
+}
+
+// MarkBackordered registra que não há saldo para reservar a ordem
+func (f *FulfillmentOrder) MarkBackordered() {
+	f.ReservationStatus = ReservationBackordered
+	f.ReservationExpiresAt = nil
+	f.UpdatedAt = time.Now()
+}
+
+// MarkReservationConfirmed registra a baixa definitiva da reserva
+func (f *FulfillmentOrder) MarkReservationConfirmed() {
+	f.ReservationStatus = ReservationConfirmed
+	f.ReservationExpiresAt = nil
+	f.UpdatedAt = time.Now()
+}
+
+// MarkReservationReleased registra a liberação da reserva (RELEASED ou EXPIRED)
+func (f *FulfillmentOrder) MarkReservationReleased(status ReservationStatus) {
+	f.ReservationStatus = status
+	f.ReservationExpiresAt = nil
+	f.UpdatedAt = time.Now()
+}
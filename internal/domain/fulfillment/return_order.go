@@ -11,12 +11,16 @@ type ReturnOrder struct {
 	ID              string     `json:"id"`
 	OriginalOrderID string     `json:"original_order_id"`
 	Reason          string     `json:"reason"`
+	Location        string     `json:"location,omitempty"` // Local de entrada do estoque devolvido
 	Status          Status     `json:"status"`
 	Items           []Item     `json:"items"`
 	IdempotencyKey  string     `json:"idempotency_key"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	Version         int64      `json:"version"`           // Versão do agregado no event store
+	Failure         *Failure   `json:"failure,omitempty"` // Última falha (etapa, causa, tentativa)
+	Blocked         *Block     `json:"blocked,omitempty"` // Bloqueio ativo (status BLOCKED)
 }
 
 // NewReturnOrder cria uma nova instância de ReturnOrder
@@ -40,32 +44,79 @@ func NewReturnOrder(originalOrderID, reason string, items []Item) (*ReturnOrder,
 
 // StartProcessing inicia o processamento da devolução
 func (r *ReturnOrder) StartProcessing() error {
-	if r.Status != StatusPending {
-		return ErrInvalidStateTransition
-	}
-	r.Status = StatusInProgress
-	r.UpdatedAt = time.Now()
-	return nil
+	return Fire(r, EventStart)
 }
 
 // Complete finaliza a devolução
 func (r *ReturnOrder) Complete() error {
-	if r.Status != StatusInProgress {
-		return ErrInvalidStateTransition
-	}
-	now := time.Now()
-	r.Status = StatusCompleted
-	r.UpdatedAt = now
-	r.CompletedAt = &now
-	return nil
+	return Fire(r, EventComplete)
 }
 
 // Cancel cancela a devolução
 func (r *ReturnOrder) Cancel() error {
-	if r.Status == StatusCompleted {
-		return ErrInvalidStateTransition
+	return Fire(r, EventCancel)
+}
+
+// Fail marca a devolução como falha, registrando etapa, item e causa
+func (r *ReturnOrder) Fail(step string, item int, cause error) error {
+	previous := r.Failure
+	r.Failure = NewFailure(step, item, cause, previous)
+	if err := Fire(r, EventFail); err != nil {
+		r.Failure = previous
+		return err
+	}
+	return nil
+}
+
+// Retry devolve a devolução para PENDING mantendo a falha como ponto de retomada
+func (r *ReturnOrder) Retry() error {
+	return Fire(r, EventRetry)
+}
+
+// Block bloqueia a devolução com um código de motivo (retirando-a das filas de trabalho)
+func (r *ReturnOrder) Block(reason BlockReason, note, actor string) error {
+	block, err := NewBlock(r.Status, reason, note, actor)
+	if err != nil {
+		return err
+	}
+	previous := r.Blocked
+	r.Blocked = block
+	if err := Fire(r, EventBlock); err != nil {
+		r.Blocked = previous
+		return err
 	}
-	r.Status = StatusCancelled
-	r.UpdatedAt = time.Now()
 	return nil
 }
+
+// Unblock devolve a devolução ao status anterior ao bloqueio
+func (r *ReturnOrder) Unblock() error {
+	return Fire(r, r.Blocked.unblockEvent())
+}
+
+// ReleaseHold libera a retenção (alfândega, qualidade) que mantém a devolução bloqueada
+func (r *ReturnOrder) ReleaseHold(actor string) error {
+	return r.Blocked.releaseHold(actor)
+}
+
+// HasActiveHolds indica retenção ainda não liberada (guard da máquina de estados)
+func (r *ReturnOrder) HasActiveHolds() bool { return r.Blocked.ActiveHold() }
+
+// CurrentStatus retorna o status atual (máquina de estados)
+func (r *ReturnOrder) CurrentStatus() Status { return r.Status }
+
+// Operation retorna o tipo de operação que governa as transições
+func (r *ReturnOrder) Operation() OperationType { return OpReturn }
+
+// OpenedAt retorna o início do SLA da operação
+func (r *ReturnOrder) OpenedAt() time.Time { return r.CreatedAt }
+
+func (r *ReturnOrder) applyStatus(to Status, at time.Time) {
+	r.Status = to
+	r.UpdatedAt = at
+	if to != StatusBlocked {
+		r.Blocked = nil
+	}
+	if to == StatusCompleted {
+		r.CompletedAt = &at
+	}
+}
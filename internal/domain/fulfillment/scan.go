@@ -0,0 +1,45 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+var (
+	ErrInvalidBarcode = errors.New("invalid barcode")
+	ErrUnknownGTIN    = errors.New("gtin is not registered for any sku")
+	ErrNoProductCode  = errors.New("barcode has no gtin")
+)
+
+// ItemFromBarcode converte o código GS1 na linha de item do SKU da hierarquia: a unidade é a do nível
+// identificado pelo GTIN e a quantidade vem do AI 30/37 (1 se ausente). Sem data de validade (AI 17),
+// usa a data de consumo preferencial (AI 15).
+func ItemFromBarcode(barcode *gs1.Barcode, hierarchy *UoMHierarchy) (Item, error) {
+	if barcode.GTIN == "" {
+		return Item{}, ErrNoProductCode
+	}
+	uom, ok := hierarchy.UoMForGTIN(barcode.GTIN)
+	if !ok {
+		return Item{}, fmt.Errorf("%w: %s", ErrUnknownGTIN, barcode.GTIN)
+	}
+	item := Item{
+		SKU:       hierarchy.SKU,
+		Quantity:  1,
+		Batch:     barcode.Batch,
+		UoM:       uom,
+		Serial:    barcode.Serial,
+		ExpiresAt: barcode.Expiry,
+	}
+	if barcode.Count > 0 {
+		item.Quantity = barcode.Count
+	}
+	if item.ExpiresAt == nil {
+		item.ExpiresAt = barcode.BestBefore
+	}
+	if item.UoM == UoMEach {
+		item.UoM = ""
+	}
+	return item, nil
+}
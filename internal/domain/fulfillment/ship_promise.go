@@ -0,0 +1,330 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomerTier é o nível de serviço contratado pelo cliente
+type CustomerTier string
+
+const (
+	TierStandard   CustomerTier = "STANDARD"
+	TierPremium    CustomerTier = "PREMIUM"
+	TierKeyAccount CustomerTier = "KEY_ACCOUNT"
+)
+
+// SLARisk classifica a ordem frente ao prazo de expedição prometido
+type SLARisk string
+
+const (
+	RiskNoPromise SLARisk = "NO_PROMISE" // Sem ship-by informado
+	RiskOnTrack   SLARisk = "ON_TRACK"
+	RiskAtRisk    SLARisk = "AT_RISK"   // Folga menor que AtRiskWindow
+	RiskWillMiss  SLARisk = "WILL_MISS" // Mesmo iniciada agora, não fica pronta até o corte
+	RiskLate      SLARisk = "LATE"      // O prazo já passou
+)
+
+var (
+	ErrInvalidShipPromise     = errors.New("invalid ship promise")
+	ErrInvalidCutoffCalendar  = errors.New("invalid carrier cutoff calendar")
+	ErrCutoffCalendarNotFound = errors.New("carrier cutoff calendar not found")
+	ErrInvalidWaveSize        = errors.New("wave size must be positive")
+)
+
+// Pesos da pontuação dinâmica de prioridade
+const (
+	PriorityScorePerLevel = 100           // Pontos por nível de prioridade (Priority da ordem e da tarefa)
+	AtRiskWindow          = 2 * time.Hour // Folga abaixo da qual a ordem está em risco
+	riskLateScore         = 400
+	riskWillMissScore     = 350
+	riskAtRiskScore       = 250
+	riskOnTrackMaxScore   = 200 // Decresce com a folga, até zero
+	riskOnTrackDecay      = 8   // Pontos por hora de folga
+	cutoffSearchDays      = 31  // Dias examinados na busca de um corte
+)
+
+// tierScore pontua o nível do cliente
+var tierScore = map[CustomerTier]int{
+	TierStandard:   0,
+	TierPremium:    50,
+	TierKeyAccount: 100,
+}
+
+// ShipPromise é o compromisso de expedição e entrega da ordem informado pelo OMS
+type ShipPromise struct {
+	ShipBy       *time.Time   `json:"ship_by,omitempty"`    // Saída do armazém
+	DeliverBy    *time.Time   `json:"deliver_by,omitempty"` // Entrega ao cliente
+	Carrier      string       `json:"carrier,omitempty"`
+	Service      string       `json:"service,omitempty"` // Serviço da transportadora (ex: EXPRESS)
+	CustomerTier CustomerTier `json:"customer_tier,omitempty"`
+}
+
+// Validate verifica a consistência dos prazos e do nível do cliente
+func (p ShipPromise) Validate() error {
+	if _, ok := tierScore[p.CustomerTier]; p.CustomerTier != "" && !ok {
+		return fmt.Errorf("%w: unknown customer tier %s", ErrInvalidShipPromise, p.CustomerTier)
+	}
+	if p.ShipBy != nil && p.DeliverBy != nil && p.DeliverBy.Before(*p.ShipBy) {
+		return fmt.Errorf("%w: deliver_by is before ship_by", ErrInvalidShipPromise)
+	}
+	if p.Service != "" && p.Carrier == "" {
+		return fmt.Errorf("%w: service requires a carrier", ErrInvalidShipPromise)
+	}
+	return nil
+}
+
+// CarrierCutoff é o horário limite de coleta em um dia da semana ("HH:MM", fuso do calendário)
+type CarrierCutoff struct {
+	Weekday time.Weekday `json:"weekday"`
+	Time    string       `json:"time"`
+}
+
+// CutoffCalendar define os cortes de coleta de uma transportadora. Service vazio vale para os serviços
+// sem calendário próprio; nos feriados não há coleta.
+type CutoffCalendar struct {
+	ID        string          `json:"id"`
+	Carrier   string          `json:"carrier"`
+	Service   string          `json:"service,omitempty"`
+	Cutoffs   []CarrierCutoff `json:"cutoffs"`
+	Holidays  []string        `json:"holidays,omitempty"` // Datas sem coleta (AAAA-MM-DD)
+	Timezone  string          `json:"timezone,omitempty"` // IANA; vazio = UTC
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// NewCutoffCalendar cria e valida o calendário de corte
+func NewCutoffCalendar(carrier, service string, cutoffs []CarrierCutoff, holidays []string, timezone string) (*CutoffCalendar, error) {
+	calendar := &CutoffCalendar{
+		ID:        uuid.New().String(),
+		Carrier:   carrier,
+		Service:   service,
+		Cutoffs:   cutoffs,
+		Holidays:  holidays,
+		Timezone:  timezone,
+		UpdatedAt: time.Now(),
+	}
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// Validate verifica transportadora, fuso, horários de corte e feriados
+func (c *CutoffCalendar) Validate() error {
+	if c.Carrier == "" {
+		return fmt.Errorf("%w: carrier is required", ErrInvalidCutoffCalendar)
+	}
+	if len(c.Cutoffs) == 0 {
+		return fmt.Errorf("%w: at least one cutoff is required", ErrInvalidCutoffCalendar)
+	}
+	if _, err := c.location(); err != nil {
+		return fmt.Errorf("%w: unknown timezone %s", ErrInvalidCutoffCalendar, c.Timezone)
+	}
+	for _, cutoff := range c.Cutoffs {
+		clock, err := parseClock(cutoff.Time)
+		if err != nil || clock >= 24*time.Hour || cutoff.Weekday < time.Sunday || cutoff.Weekday > time.Saturday {
+			return fmt.Errorf("%w: invalid cutoff %v %s", ErrInvalidCutoffCalendar, cutoff.Weekday, cutoff.Time)
+		}
+	}
+	for _, holiday := range c.Holidays {
+		if _, err := time.Parse(time.DateOnly, holiday); err != nil {
+			return fmt.Errorf("%w: invalid holiday %s", ErrInvalidCutoffCalendar, holiday)
+		}
+	}
+	return nil
+}
+
+// NextCutoff retorna o primeiro corte a partir de after
+func (c *CutoffCalendar) NextCutoff(after time.Time) (time.Time, bool) {
+	loc, err := c.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+	day := after.In(loc)
+	for i := 0; i < cutoffSearchDays; i++ {
+		for _, cutoff := range c.cutoffsOn(day.AddDate(0, 0, i)) {
+			if !cutoff.Before(after) {
+				return cutoff, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// LastCutoff retorna o último corte até before (inclusive)
+func (c *CutoffCalendar) LastCutoff(before time.Time) (time.Time, bool) {
+	loc, err := c.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+	day := before.In(loc)
+	for i := 0; i < cutoffSearchDays; i++ {
+		cutoffs := c.cutoffsOn(day.AddDate(0, 0, -i))
+		for j := len(cutoffs) - 1; j >= 0; j-- {
+			if !cutoffs[j].After(before) {
+				return cutoffs[j], true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// cutoffsOn retorna os cortes do dia, em ordem (nenhum em feriado)
+func (c *CutoffCalendar) cutoffsOn(day time.Time) []time.Time {
+	date := day.Format(time.DateOnly)
+	for _, holiday := range c.Holidays {
+		if holiday == date {
+			return nil
+		}
+	}
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	var cutoffs []time.Time
+	for _, cutoff := range c.Cutoffs {
+		if cutoff.Weekday != day.Weekday() {
+			continue
+		}
+		clock, _ := parseClock(cutoff.Time)
+		cutoffs = append(cutoffs, midnight.Add(clock))
+	}
+	sort.Slice(cutoffs, func(i, j int) bool { return cutoffs[i].Before(cutoffs[j]) })
+	return cutoffs
+}
+
+func (c *CutoffCalendar) location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// SelectCutoffCalendar escolhe o calendário do serviço da transportadora ou, na falta dele, o calendário geral
+func SelectCutoffCalendar(calendars []*CutoffCalendar, carrier, service string) *CutoffCalendar {
+	var fallback *CutoffCalendar
+	for _, calendar := range calendars {
+		if calendar.Carrier != carrier {
+			continue
+		}
+		if calendar.Service == service {
+			return calendar
+		}
+		if calendar.Service == "" {
+			fallback = calendar
+		}
+	}
+	return fallback
+}
+
+// OrderPriority é a pontuação dinâmica de uma ordem aberta: prioridade da ordem, nível do cliente e
+// risco de perder o prazo de expedição
+type OrderPriority struct {
+	FulfillmentOrderID string       `json:"fulfillment_order_id"`
+	OrderID            string       `json:"order_id"`
+	Customer           string       `json:"customer"`
+	CustomerTier       CustomerTier `json:"customer_tier,omitempty"`
+	Carrier            string       `json:"carrier,omitempty"`
+	Service            string       `json:"service,omitempty"`
+	Status             Status       `json:"status"`
+	ShipBy             *time.Time   `json:"ship_by,omitempty"`
+	Deadline           *time.Time   `json:"deadline,omitempty"`    // Último corte que ainda cumpre o ship-by (sem calendário, o ship-by)
+	NextCutoff         *time.Time   `json:"next_cutoff,omitempty"` // Primeiro corte alcançável se iniciada agora
+	SlackMinutes       int          `json:"slack_minutes"`         // Folga até o prazo, descontado o tempo de processamento
+	Risk               SLARisk      `json:"risk"`
+	Score              int          `json:"score"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+// ScoreOrder pontua a ordem em now. leadTime é o tempo de processamento (separação a expedição);
+// calendar pode ser nil quando a transportadora não tem calendário de corte.
+func ScoreOrder(order *FulfillmentOrder, calendar *CutoffCalendar, leadTime time.Duration, now time.Time) OrderPriority {
+	p := OrderPriority{
+		FulfillmentOrderID: order.ID,
+		OrderID:            order.OrderID,
+		Customer:           order.Customer,
+		CustomerTier:       order.CustomerTier,
+		Carrier:            order.Carrier,
+		Service:            order.Service,
+		Status:             order.Status,
+		ShipBy:             order.ShipBy,
+		Risk:               RiskNoPromise,
+		CreatedAt:          order.CreatedAt,
+	}
+	if calendar != nil {
+		if next, ok := calendar.NextCutoff(now.Add(leadTime)); ok {
+			p.NextCutoff = &next
+		}
+	}
+
+	risk := 0
+	if order.ShipBy != nil {
+		deadline := *order.ShipBy
+		if calendar != nil {
+			if last, ok := calendar.LastCutoff(deadline); ok {
+				deadline = last
+			}
+		}
+		slack := deadline.Sub(now) - leadTime
+		p.Deadline, p.SlackMinutes = &deadline, int(slack.Minutes())
+
+		switch {
+		case now.After(deadline):
+			p.Risk, risk = RiskLate, riskLateScore
+		case slack < 0:
+			p.Risk, risk = RiskWillMiss, riskWillMissScore
+		case slack < AtRiskWindow:
+			p.Risk, risk = RiskAtRisk, riskAtRiskScore
+		default:
+			p.Risk = RiskOnTrack
+			risk = riskOnTrackMaxScore - int(slack.Hours())*riskOnTrackDecay
+			if risk < 0 {
+				risk = 0
+			}
+		}
+	}
+
+	p.Score = order.Priority*PriorityScorePerLevel + tierScore[order.CustomerTier] + risk
+	return p
+}
+
+// WillMiss indica se a ordem não cumpre mais o prazo prometido
+func (p OrderPriority) WillMiss() bool {
+	return p.Risk == RiskWillMiss || p.Risk == RiskLate
+}
+
+// TaskPriority converte a pontuação na prioridade da tarefa de separação
+func (p OrderPriority) TaskPriority() int {
+	return p.Score / PriorityScorePerLevel
+}
+
+// SortOrderPriorities ordena pela maior pontuação; no empate, pelo prazo mais próximo e pela ordem mais antiga
+func SortOrderPriorities(priorities []OrderPriority) {
+	sort.SliceStable(priorities, func(i, j int) bool {
+		a, b := priorities[i], priorities[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if (a.Deadline == nil) != (b.Deadline == nil) {
+			return a.Deadline != nil
+		}
+		if a.Deadline != nil && !a.Deadline.Equal(*b.Deadline) {
+			return a.Deadline.Before(*b.Deadline)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+// WaveOrder é uma ordem liberada na onda com a tarefa de separação gerada
+type WaveOrder struct {
+	OrderPriority
+	TaskID       string `json:"task_id"`
+	TaskPriority int    `json:"task_priority"`
+}
+
+// Wave é um lote de ordens liberadas para separação na sequência da pontuação
+type Wave struct {
+	PlannedAt time.Time   `json:"planned_at"`
+	Orders    []WaveOrder `json:"orders"`
+}
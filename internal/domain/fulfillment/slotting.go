@@ -0,0 +1,240 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VelocityClass é a classe ABC do SKU pela frequência de separação
+type VelocityClass string
+
+const (
+	VelocityA VelocityClass = "A"
+	VelocityB VelocityClass = "B"
+	VelocityC VelocityClass = "C"
+)
+
+// SlottingStatus é o ciclo de vida de uma recomendação de realocação
+type SlottingStatus string
+
+const (
+	SlottingProposed SlottingStatus = "PROPOSED"
+	SlottingPartial  SlottingStatus = "PARTIAL" // SKU realocado, falta a transferência do SKU deslocado
+	SlottingApproved SlottingStatus = "APPROVED"
+	SlottingRejected SlottingStatus = "REJECTED"
+)
+
+// Limites acumulados de separações (curva ABC)
+const (
+	velocityClassAShare = 0.80
+	velocityClassBShare = 0.95
+)
+
+var (
+	ErrSlottingRecommendationNotFound = errors.New("slotting recommendation not found")
+	ErrSlottingNotProposed            = errors.New("slotting recommendation is no longer proposed")
+)
+
+// SKUVelocity é o giro de um SKU na janela analisada
+type SKUVelocity struct {
+	SKU         string        `json:"sku"`
+	Class       VelocityClass `json:"class"`
+	Picks       int           `json:"picks"` // Linhas separadas (visitas ao endereço)
+	Units       int           `json:"units"`
+	PicksPerDay float64       `json:"picks_per_day"`
+	CubeMoved   float64       `json:"cube_moved"`         // m³ expedidos (0 sem volume cadastrado)
+	Location    string        `json:"location,omitempty"` // Endereço de onde o SKU mais foi separado
+}
+
+// SlottingRecommendation propõe levar um SKU de giro rápido para um endereço da zona dourada.
+// Com DisplacedSKU, o ocupante mais lento troca de lugar com ele.
+type SlottingRecommendation struct {
+	ID           string         `json:"id"`
+	SKU          string         `json:"sku"`
+	Class        VelocityClass  `json:"class"`
+	FromLocation string         `json:"from_location"`
+	ToLocation   string         `json:"to_location"`
+	DisplacedSKU string         `json:"displaced_sku,omitempty"`
+	Picks        int            `json:"picks"`
+	Savings      float64        `json:"savings"` // Metros de deslocamento poupados na janela analisada
+	Status       SlottingStatus `json:"status"`
+	TransferIDs  []string       `json:"transfer_ids,omitempty"` // Movimentações geradas na aprovação
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// AnalyzeVelocity calcula o giro por SKU a partir das ordens expedidas em days dias e classifica em ABC.
+// unitCubes traz o volume da unidade base por SKU.
+func AnalyzeVelocity(orders []*FulfillmentOrder, unitCubes map[string]float64, days float64) []SKUVelocity {
+	type accumulator struct {
+		velocity  SKUVelocity
+		locations map[string]int
+	}
+	bySKU := make(map[string]*accumulator)
+	totalPicks := 0
+	for _, order := range orders {
+		for _, item := range order.Items {
+			acc, ok := bySKU[item.SKU]
+			if !ok {
+				acc = &accumulator{velocity: SKUVelocity{SKU: item.SKU}, locations: make(map[string]int)}
+				bySKU[item.SKU] = acc
+			}
+			acc.velocity.Picks++
+			acc.velocity.Units += item.Quantity
+			if item.Location != "" {
+				acc.locations[item.Location]++
+			}
+			totalPicks++
+		}
+	}
+
+	velocities := make([]SKUVelocity, 0, len(bySKU))
+	for _, acc := range bySKU {
+		velocity := acc.velocity
+		velocity.CubeMoved = float64(velocity.Units) * unitCubes[velocity.SKU]
+		if days > 0 {
+			velocity.PicksPerDay = float64(velocity.Picks) / days
+		}
+		for location, picks := range acc.locations {
+			if picks > acc.locations[velocity.Location] || (picks == acc.locations[velocity.Location] && location < velocity.Location) {
+				velocity.Location = location
+			}
+		}
+		velocities = append(velocities, velocity)
+	}
+	sort.Slice(velocities, func(i, j int) bool {
+		if velocities[i].Picks != velocities[j].Picks {
+			return velocities[i].Picks > velocities[j].Picks
+		}
+		if velocities[i].CubeMoved != velocities[j].CubeMoved {
+			return velocities[i].CubeMoved > velocities[j].CubeMoved
+		}
+		return velocities[i].SKU < velocities[j].SKU
+	})
+
+	// A classe considera a participação acumulada antes do SKU: o primeiro é sempre A
+	cumulative := 0
+	for idx := range velocities {
+		velocities[idx].Class = abcClass(float64(cumulative) / float64(totalPicks))
+		cumulative += velocities[idx].Picks
+	}
+	return velocities
+}
+
+// abcClass classifica pela participação acumulada antes do item na curva
+func abcClass(share float64) VelocityClass {
+	switch {
+	case share < velocityClassAShare:
+		return VelocityA
+	case share < velocityClassBShare:
+		return VelocityB
+	default:
+		return VelocityC
+	}
+}
+
+// RecommendSlotting propõe levar os SKUs classe A que estão fora da zona dourada para o endereço dourado
+// livre mais próximo do depósito; sem endereço livre, troca com o ocupante dourado mais lento.
+// Só são propostas realocações com economia positiva de deslocamento.
+func RecommendSlotting(velocities []SKUVelocity, layout *WarehouseLayout) ([]*SlottingRecommendation, error) {
+	distances, err := layout.RoundTripDistances()
+	if err != nil {
+		return nil, err
+	}
+
+	// Endereços dourados do mais próximo ao mais distante do depósito
+	var golden []string
+	isGolden := make(map[string]bool)
+	for _, bin := range layout.Bins {
+		if bin.Golden {
+			golden = append(golden, bin.Location)
+			isGolden[bin.Location] = true
+		}
+	}
+	sort.SliceStable(golden, func(i, j int) bool { return distances[golden[i]] < distances[golden[j]] })
+
+	occupant := make(map[string]SKUVelocity)
+	for _, velocity := range velocities {
+		if current, ok := occupant[velocity.Location]; velocity.Location != "" && (!ok || velocity.Picks > current.Picks) {
+			occupant[velocity.Location] = velocity
+		}
+	}
+
+	now := time.Now()
+	involved := make(map[string]bool) // Endereços já usados em uma recomendação
+	var recommendations []*SlottingRecommendation
+	for _, velocity := range velocities {
+		from := velocity.Location
+		fromDistance, placed := distances[from]
+		if velocity.Class != VelocityA || !placed || isGolden[from] || involved[from] {
+			continue
+		}
+
+		target, displaced := "", SKUVelocity{}
+		for _, location := range golden {
+			if _, occupied := occupant[location]; !occupied && !involved[location] {
+				target = location
+				break
+			}
+		}
+		if target == "" {
+			for _, location := range golden {
+				current := occupant[location]
+				if involved[location] || current.Class == VelocityA || current.Picks >= velocity.Picks {
+					continue
+				}
+				if target == "" || current.Picks < displaced.Picks {
+					target, displaced = location, current
+				}
+			}
+		}
+		if target == "" {
+			continue
+		}
+
+		savings := float64(velocity.Picks-displaced.Picks) * (fromDistance - distances[target])
+		if savings <= 0 {
+			continue
+		}
+		involved[from], involved[target] = true, true
+		recommendations = append(recommendations, &SlottingRecommendation{
+			ID:           uuid.New().String(),
+			SKU:          velocity.SKU,
+			Class:        velocity.Class,
+			FromLocation: from,
+			ToLocation:   target,
+			DisplacedSKU: displaced.SKU,
+			Picks:        velocity.Picks,
+			Savings:      savings,
+			Status:       SlottingProposed,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+	return recommendations, nil
+}
+
+// Approve aceita a recomendação (ou retoma uma aprovação parcial) com as movimentações geradas
+func (r *SlottingRecommendation) Approve(transferIDs []string) error {
+	if r.Status != SlottingProposed && r.Status != SlottingPartial {
+		return fmt.Errorf("%w: %s", ErrSlottingNotProposed, r.Status)
+	}
+	r.Status = SlottingApproved
+	r.TransferIDs = transferIDs
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Reject descarta a recomendação
+func (r *SlottingRecommendation) Reject() error {
+	if r.Status != SlottingProposed {
+		return fmt.Errorf("%w: %s", ErrSlottingNotProposed, r.Status)
+	}
+	r.Status = SlottingRejected
+	r.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,440 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Eventos (gatilhos) aceitos pela máquina de estados
+const (
+	EventStart     = "start"
+	EventComplete  = "complete"
+	EventCancel    = "cancel"
+	EventFail      = "fail"
+	EventBlock     = "block"
+	EventUnblock   = "unblock"
+	EventRetry     = "retry"
+	EventAutoRetry = "auto_retry" // Retry do agendador automático, limitado ao SLA
+	EventRequeue   = "requeue"    // Desbloqueio de operação bloqueada antes de iniciar
+	EventAccept    = "accept"     // Operador aceita a tarefa de armazém
+	EventAbandon   = "abandon"    // Operador devolve a tarefa à fila
+)
+
+var (
+	ErrGuardRejected     = errors.New("transition rejected by guard")
+	ErrActiveHolds       = errors.New("operation has active holds")
+	ErrPackingIncomplete = errors.New("packing is not complete")
+	ErrSLAExceeded       = errors.New("operation exceeded its SLA")
+	ErrUnknownOperation  = errors.New("unknown operation type")
+)
+
+// Stateful é implementado pelas entidades governadas pela máquina de estados
+type Stateful interface {
+	CurrentStatus() Status
+	Operation() OperationType
+
+	applyStatus(to Status, at time.Time)
+}
+
+// HoldAware é implementado por entidades que podem ter bloqueios (holds) ativos
+type HoldAware interface {
+	HasActiveHolds() bool
+}
+
+// PackingAware é implementado por entidades que controlam a conclusão do packing
+type PackingAware interface {
+	PackingComplete() bool
+}
+
+// SLATracked é implementado por entidades cujo SLA corre a partir da abertura da operação
+type SLATracked interface {
+	OpenedAt() time.Time
+}
+
+// Guard é uma pré-condição avaliada antes de aplicar a transição
+type Guard struct {
+	Name  string
+	Check func(subject Stateful) error
+}
+
+// Transition descreve uma aresta da máquina de estados
+type Transition struct {
+	Event  string
+	From   Status
+	To     Status
+	Guards []Guard
+}
+
+// TransitionContext é entregue aos hooks de uma transição
+type TransitionContext struct {
+	Operation OperationType
+	Event     string
+	From      Status
+	To        Status
+	Subject   Stateful
+	At        time.Time
+}
+
+// BeforeHook roda antes da transição e pode abortá-la retornando erro
+type BeforeHook func(tc TransitionContext) error
+
+// AfterHook roda depois que a transição foi aplicada à entidade em memória, antes da persistência
+// (eventos, métricas)
+type AfterHook func(tc TransitionContext)
+
+type beforeEntry struct {
+	id   int
+	hook BeforeHook
+}
+
+type afterEntry struct {
+	id   int
+	hook AfterHook
+}
+
+// StateMachine define, de forma declarativa, as transições de um tipo de operação
+type StateMachine struct {
+	operation   OperationType
+	initial     Status
+	transitions []Transition
+
+	mu     sync.RWMutex
+	hookID int
+	before []beforeEntry
+	after  []afterEntry
+}
+
+// NewStateMachine cria uma máquina de estados a partir da tabela de transições
+func NewStateMachine(operation OperationType, initial Status, transitions []Transition) *StateMachine {
+	return &StateMachine{operation: operation, initial: initial, transitions: transitions}
+}
+
+// Operation retorna o tipo de operação governado pela máquina
+func (m *StateMachine) Operation() OperationType { return m.operation }
+
+// Initial retorna o estado inicial
+func (m *StateMachine) Initial() Status { return m.initial }
+
+// Transitions retorna uma cópia da tabela de transições
+func (m *StateMachine) Transitions() []Transition {
+	return append([]Transition(nil), m.transitions...)
+}
+
+// CanTransition indica se existe alguma transição de from para to (ignorando guards)
+func (m *StateMachine) CanTransition(from, to Status) bool {
+	for _, t := range m.transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Can indica se o evento é aceito a partir de from (ignorando guards)
+func (m *StateMachine) Can(from Status, event string) bool {
+	_, ok := m.find(from, event)
+	return ok
+}
+
+// Events lista os eventos aceitos a partir de from
+func (m *StateMachine) Events(from Status) []string {
+	var events []string
+	for _, t := range m.transitions {
+		if t.From == from {
+			events = append(events, t.Event)
+		}
+	}
+	return events
+}
+
+// Before registra um hook executado antes de cada transição e retorna a função que o remove
+func (m *StateMachine) Before(hook BeforeHook) (remove func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hookID++
+	id := m.hookID
+	m.before = append(m.before, beforeEntry{id: id, hook: hook})
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.before = slices.DeleteFunc(m.before, func(e beforeEntry) bool { return e.id == id })
+	}
+}
+
+// After registra um hook executado depois de cada transição e retorna a função que o remove
+func (m *StateMachine) After(hook AfterHook) (remove func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hookID++
+	id := m.hookID
+	m.after = append(m.after, afterEntry{id: id, hook: hook})
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.after = slices.DeleteFunc(m.after, func(e afterEntry) bool { return e.id == id })
+	}
+}
+
+// Fire aplica o evento ao subject: valida a transição, avalia guards, roda hooks
+func (m *StateMachine) Fire(subject Stateful, event string) error {
+	from := subject.CurrentStatus()
+	transition, ok := m.find(from, event)
+	if !ok {
+		return fmt.Errorf("%w: %s %q from %s", ErrInvalidStateTransition, m.operation, event, from)
+	}
+
+	for _, guard := range transition.Guards {
+		if err := guard.Check(subject); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrGuardRejected, guard.Name, err)
+		}
+	}
+
+	tc := TransitionContext{
+		Operation: m.operation,
+		Event:     event,
+		From:      from,
+		To:        transition.To,
+		Subject:   subject,
+		At:        time.Now(),
+	}
+
+	m.mu.RLock()
+	before := append([]beforeEntry(nil), m.before...)
+	after := append([]afterEntry(nil), m.after...)
+	m.mu.RUnlock()
+
+	for _, entry := range before {
+		if err := entry.hook(tc); err != nil {
+			return err
+		}
+	}
+
+	subject.applyStatus(transition.To, tc.At)
+
+	for _, entry := range after {
+		entry.hook(tc)
+	}
+	return nil
+}
+
+func (m *StateMachine) find(from Status, event string) (Transition, bool) {
+	for _, t := range m.transitions {
+		if t.From == from && t.Event == event {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// states retorna os estados na ordem em que aparecem na tabela
+func (m *StateMachine) states() []Status {
+	seen := map[Status]bool{m.initial: true}
+	states := []Status{m.initial}
+	for _, t := range m.transitions {
+		for _, s := range []Status{t.From, t.To} {
+			if !seen[s] {
+				seen[s] = true
+				states = append(states, s)
+			}
+		}
+	}
+	return states
+}
+
+func (t Transition) label() string {
+	if len(t.Guards) == 0 {
+		return t.Event
+	}
+	names := make([]string, len(t.Guards))
+	for i, g := range t.Guards {
+		names[i] = g.Name
+	}
+	return t.Event + " [" + strings.Join(names, ", ") + "]"
+}
+
+// Mermaid renderiza a máquina como stateDiagram-v2
+func (m *StateMachine) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    %%%% %s\n", m.operation)
+	fmt.Fprintf(&b, "    [*] --> %s\n", m.initial)
+	for _, t := range m.transitions {
+		fmt.Fprintf(&b, "    %s --> %s: %s\n", t.From, t.To, t.label())
+	}
+	for _, s := range m.states() {
+		if s.IsTerminal() {
+			fmt.Fprintf(&b, "    %s --> [*]\n", s)
+		}
+	}
+	return b.String()
+}
+
+// DOT renderiza a máquina no formato Graphviz
+func (m *StateMachine) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", strings.ToLower(string(m.operation)))
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    __start [shape=point];\n")
+	for _, s := range m.states() {
+		shape := "ellipse"
+		if s.IsTerminal() {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&b, "    %q [shape=%s];\n", s, shape)
+	}
+	fmt.Fprintf(&b, "    __start -> %q;\n", m.initial)
+	for _, t := range m.transitions {
+		fmt.Fprintf(&b, "    %q -> %q [label=%q];\n", t.From, t.To, t.label())
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Guards padrão
+
+// GuardNotBackordered impede iniciar a separação de pedidos sem reserva de estoque
+func GuardNotBackordered() Guard {
+	return Guard{Name: "not_backordered", Check: func(s Stateful) error {
+		if order, ok := s.(*FulfillmentOrder); ok && order.ReservationStatus == ReservationBackordered {
+			return ErrOrderBackordered
+		}
+		return nil
+	}}
+}
+
+// GuardNoActiveHolds impede a transição enquanto houver holds ativos
+func GuardNoActiveHolds() Guard {
+	return Guard{Name: "no_active_holds", Check: func(s Stateful) error {
+		if h, ok := s.(HoldAware); ok && h.HasActiveHolds() {
+			return ErrActiveHolds
+		}
+		return nil
+	}}
+}
+
+// GuardPackingComplete exige packing concluído antes de expedir
+func GuardPackingComplete() Guard {
+	return Guard{Name: "packing_complete", Check: func(s Stateful) error {
+		if p, ok := s.(PackingAware); ok && !p.PackingComplete() {
+			return ErrPackingIncomplete
+		}
+		return nil
+	}}
+}
+
+// GuardWithinSLA impede a transição depois de vencido o SLA do tipo de operação (DefaultPolicy).
+// Operações sem SLA definido não são limitadas.
+func GuardWithinSLA() Guard {
+	return Guard{Name: "within_sla", Check: func(s Stateful) error {
+		maxDuration := DefaultPolicy().MaxDurationMinutes(s.Operation())
+		if t, ok := s.(SLATracked); ok && maxDuration > 0 && !CheckSLA(t.OpenedAt(), maxDuration) {
+			return ErrSLAExceeded
+		}
+		return nil
+	}}
+}
+
+// workflowTransitions monta o workflow comum a todas as operações,
+// acrescentando os guards específicos de cada uma. O retry manual (FAILED -> PENDING)
+// não tem guard: o operador pode reexecutar a qualquer momento. O retry do agendador
+// automático (auto_retry) só é aceito dentro do SLA.
+func workflowTransitions(startGuards, completeGuards []Guard) []Transition {
+	return []Transition{
+		{Event: EventStart, From: StatusPending, To: StatusInProgress, Guards: startGuards},
+		{Event: EventCancel, From: StatusPending, To: StatusCancelled},
+		{Event: EventBlock, From: StatusPending, To: StatusBlocked},
+		{Event: EventComplete, From: StatusInProgress, To: StatusCompleted, Guards: completeGuards},
+		{Event: EventFail, From: StatusInProgress, To: StatusFailed},
+		{Event: EventBlock, From: StatusInProgress, To: StatusBlocked},
+		{Event: EventCancel, From: StatusInProgress, To: StatusCancelled},
+		{Event: EventUnblock, From: StatusBlocked, To: StatusInProgress, Guards: []Guard{GuardNoActiveHolds()}},
+		{Event: EventRequeue, From: StatusBlocked, To: StatusPending, Guards: []Guard{GuardNoActiveHolds()}},
+		{Event: EventCancel, From: StatusBlocked, To: StatusCancelled},
+		{Event: EventRetry, From: StatusFailed, To: StatusPending},
+		{Event: EventAutoRetry, From: StatusFailed, To: StatusPending, Guards: []Guard{GuardWithinSLA()}},
+		{Event: EventCancel, From: StatusFailed, To: StatusCancelled},
+	}
+}
+
+// taskTransitions define o ciclo de vida das tarefas de armazém (aceite, execução e abandono)
+func taskTransitions() []Transition {
+	return []Transition{
+		{Event: EventAccept, From: StatusPending, To: StatusAssigned},
+		{Event: EventCancel, From: StatusPending, To: StatusCancelled},
+		{Event: EventStart, From: StatusAssigned, To: StatusInProgress},
+		{Event: EventAbandon, From: StatusAssigned, To: StatusPending},
+		{Event: EventCancel, From: StatusAssigned, To: StatusCancelled},
+		{Event: EventComplete, From: StatusInProgress, To: StatusCompleted},
+		{Event: EventAbandon, From: StatusInProgress, To: StatusPending},
+		{Event: EventCancel, From: StatusInProgress, To: StatusCancelled},
+	}
+}
+
+var stateMachines = map[OperationType]*StateMachine{
+	OpInbound: NewStateMachine(OpInbound, StatusPending,
+		workflowTransitions(nil, []Guard{GuardNoActiveHolds()})),
+	OpOutbound: NewStateMachine(OpOutbound, StatusPending,
+		workflowTransitions(
+			[]Guard{GuardNotBackordered(), GuardNoActiveHolds()},
+			[]Guard{GuardNoActiveHolds(), GuardPackingComplete()})),
+	OpTransfer: NewStateMachine(OpTransfer, StatusPending,
+		workflowTransitions([]Guard{GuardNoActiveHolds()}, []Guard{GuardNoActiveHolds()})),
+	OpReturn: NewStateMachine(OpReturn, StatusPending,
+		workflowTransitions(nil, []Guard{GuardNoActiveHolds()})),
+	OpCycleCount: NewStateMachine(OpCycleCount, StatusPending,
+		workflowTransitions(nil, nil)),
+	OpWarehouseTask: NewStateMachine(OpWarehouseTask, StatusPending, taskTransitions()),
+	OpAssembly:      NewStateMachine(OpAssembly, StatusPending, workflowTransitions(nil, nil)),
+}
+
+// StateMachineFor retorna a máquina de estados do tipo de operação
+func StateMachineFor(op OperationType) (*StateMachine, error) {
+	machine, ok := stateMachines[op]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOperation, op)
+	}
+	return machine, nil
+}
+
+// StateMachines retorna todas as máquinas ordenadas por tipo de operação
+func StateMachines() []*StateMachine {
+	list := make([]*StateMachine, 0, len(stateMachines))
+	for _, machine := range stateMachines {
+		list = append(list, machine)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].operation < list[j].operation })
+	return list
+}
+
+// OnTransition registra um after hook em todas as máquinas (ex: métricas) e retorna a função que o remove
+func OnTransition(hook AfterHook) (remove func()) {
+	removers := make([]func(), 0, len(stateMachines))
+	for _, machine := range stateMachines {
+		removers = append(removers, machine.After(hook))
+	}
+	return func() {
+		for _, remove := range removers {
+			remove()
+		}
+	}
+}
+
+// Can indica se o evento é aceito no status atual do subject (ignorando guards)
+func Can(subject Stateful, event string) bool {
+	machine, err := StateMachineFor(subject.Operation())
+	return err == nil && machine.Can(subject.CurrentStatus(), event)
+}
+
+// Fire aplica o evento usando a máquina do tipo de operação do subject
+func Fire(subject Stateful, event string) error {
+	machine, err := StateMachineFor(subject.Operation())
+	if err != nil {
+		return err
+	}
+	return machine.Fire(subject, event)
+}
@@ -0,0 +1,84 @@
+package fulfillment
+
+import (
+	"context"
+	"time"
+)
+
+// Tipos de entidade registrados no histórico de status
+const (
+	EntityInboundShipment  = "inbound_shipment"
+	EntityFulfillmentOrder = AggregateFulfillmentOrder
+	EntityTransferOrder    = AggregateTransferOrder
+	EntityReturnOrder      = AggregateReturnOrder
+	EntityCycleCountTask   = "cycle_count_task"
+	EntityWarehouseTask    = "warehouse_task"
+	EntityAssemblyOrder    = "assembly_order"
+)
+
+// StatusTransition é um registro imutável de mudança de status de uma entidade
+type StatusTransition struct {
+	EntityType    string    `json:"entity_type"`
+	EntityID      string    `json:"entity_id"`
+	FromStatus    Status    `json:"from_status,omitempty"` // Vazio na criação
+	ToStatus      Status    `json:"to_status"`
+	Actor         string    `json:"actor,omitempty"`
+	Source        string    `json:"source,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// NewStatusTransition cria o registro de transição com ator, origem, motivo e correlação do contexto
+func NewStatusTransition(ctx context.Context, entityType, entityID string, from, to Status) StatusTransition {
+	return StatusTransition{
+		EntityType:    entityType,
+		EntityID:      entityID,
+		FromStatus:    from,
+		ToStatus:      to,
+		Actor:         ActorFromContext(ctx),
+		Source:        SourceFromContext(ctx),
+		Reason:        ReasonFromContext(ctx),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		OccurredAt:    time.Now().UTC(),
+	}
+}
+
+// IsTerminal indica se o status encerra o ciclo de vida da entidade
+func (s Status) IsTerminal() bool {
+	return s == StatusCompleted || s == StatusCancelled
+}
+
+// StatusDwell calcula quanto tempo a entidade permaneceu em cada status.
+// O status corrente conta até "now", exceto quando é terminal.
+func StatusDwell(transitions []StatusTransition, now time.Time) map[Status]time.Duration {
+	dwell := make(map[Status]time.Duration)
+	for i, t := range transitions {
+		end := now
+		if i+1 < len(transitions) {
+			end = transitions[i+1].OccurredAt
+		} else if t.ToStatus.IsTerminal() {
+			continue
+		}
+		if end.After(t.OccurredAt) {
+			dwell[t.ToStatus] += end.Sub(t.OccurredAt)
+		}
+	}
+	return dwell
+}
+
+// DwellStat agrega o tempo de permanência em um status para um tipo de entidade
+type DwellStat struct {
+	EntityType string        `json:"entity_type"`
+	Status     Status        `json:"status"`
+	Count      int64         `json:"count"`
+	Average    time.Duration `json:"average_ns"`
+	P95        time.Duration `json:"p95_ns"`
+	Max        time.Duration `json:"max_ns"`
+}
+
+// StatusHistoryRepository consulta o histórico append-only de status
+type StatusHistoryRepository interface {
+	ListStatusHistory(ctx context.Context, entityType, entityID string) ([]StatusTransition, error)
+	StatusDwellStats(ctx context.Context, entityType string, since time.Time) ([]DwellStat, error)
+}
@@ -17,6 +17,9 @@ type TransferOrder struct {
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	Version        int64      `json:"version"`           // Versão do agregado no event store
+	Failure        *Failure   `json:"failure,omitempty"` // Última falha (etapa, causa, tentativa)
+	Blocked        *Block     `json:"blocked,omitempty"` // Bloqueio ativo (status BLOCKED)
 }
 
 // NewTransferOrder cria uma nova instância de TransferOrder
@@ -40,32 +43,79 @@ func NewTransferOrder(locationFrom, locationTo string, items []Item) (*TransferO
 
 // StartTransfer inicia o processo de transferência
 func (t *TransferOrder) StartTransfer() error {
-	if t.Status != StatusPending {
-		return ErrInvalidStateTransition
-	}
-	t.Status = StatusInProgress
-	t.UpdatedAt = time.Now()
-	return nil
+	return Fire(t, EventStart)
 }
 
 // Complete finaliza a transferência
 func (t *TransferOrder) Complete() error {
-	if t.Status != StatusInProgress {
-		return ErrInvalidStateTransition
-	}
-	now := time.Now()
-	t.Status = StatusCompleted
-	t.UpdatedAt = now
-	t.CompletedAt = &now
-	return nil
+	return Fire(t, EventComplete)
 }
 
 // Cancel cancela a transferência
 func (t *TransferOrder) Cancel() error {
-	if t.Status == StatusCompleted {
-		return ErrInvalidStateTransition
+	return Fire(t, EventCancel)
+}
+
+// Fail marca a transferência como falha, registrando etapa, item e causa
+func (t *TransferOrder) Fail(step string, item int, cause error) error {
+	previous := t.Failure
+	t.Failure = NewFailure(step, item, cause, previous)
+	if err := Fire(t, EventFail); err != nil {
+		t.Failure = previous
+		return err
+	}
+	return nil
+}
+
+// Retry devolve a transferência para PENDING mantendo a falha como ponto de retomada
+func (t *TransferOrder) Retry() error {
+	return Fire(t, EventRetry)
+}
+
+// Block bloqueia a transferência com um código de motivo (retirando-a das filas de trabalho)
+func (t *TransferOrder) Block(reason BlockReason, note, actor string) error {
+	block, err := NewBlock(t.Status, reason, note, actor)
+	if err != nil {
+		return err
+	}
+	previous := t.Blocked
+	t.Blocked = block
+	if err := Fire(t, EventBlock); err != nil {
+		t.Blocked = previous
+		return err
 	}
-	t.Status = StatusCancelled
-	t.UpdatedAt = time.Now()
 	return nil
 }
+
+// Unblock devolve a transferência ao status anterior ao bloqueio
+func (t *TransferOrder) Unblock() error {
+	return Fire(t, t.Blocked.unblockEvent())
+}
+
+// ReleaseHold libera a retenção (alfândega, qualidade) que mantém a transferência bloqueada
+func (t *TransferOrder) ReleaseHold(actor string) error {
+	return t.Blocked.releaseHold(actor)
+}
+
+// HasActiveHolds indica retenção ainda não liberada (guard da máquina de estados)
+func (t *TransferOrder) HasActiveHolds() bool { return t.Blocked.ActiveHold() }
+
+// CurrentStatus retorna o status atual (máquina de estados)
+func (t *TransferOrder) CurrentStatus() Status { return t.Status }
+
+// Operation retorna o tipo de operação que governa as transições
+func (t *TransferOrder) Operation() OperationType { return OpTransfer }
+
+// OpenedAt retorna o início do SLA da operação
+func (t *TransferOrder) OpenedAt() time.Time { return t.CreatedAt }
+
+func (t *TransferOrder) applyStatus(to Status, at time.Time) {
+	t.Status = to
+	t.UpdatedAt = at
+	if to != StatusBlocked {
+		t.Blocked = nil
+	}
+	if to == StatusCompleted {
+		t.CompletedAt = &at
+	}
+}
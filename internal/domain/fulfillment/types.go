@@ -6,11 +6,13 @@ import "errors"
 type OperationType string
 
 const (
-	OpInbound    OperationType = "INBOUND"
-	OpOutbound   OperationType = "OUTBOUND"
-	OpTransfer   OperationType = "TRANSFER"
-	OpReturn     OperationType = "RETURN"
-	OpCycleCount OperationType = "CYCLE_COUNT"
+	OpInbound       OperationType = "INBOUND"
+	OpOutbound      OperationType = "OUTBOUND"
+	OpTransfer      OperationType = "TRANSFER"
+	OpReturn        OperationType = "RETURN"
+	OpCycleCount    OperationType = "CYCLE_COUNT"
+	OpWarehouseTask OperationType = "WAREHOUSE_TASK"
+	OpAssembly      OperationType = "ASSEMBLY"
 )
 
 // Status do Workflow (Máquina de Estados)
@@ -18,6 +20,7 @@ type Status string
 
 const (
 	StatusPending    Status = "PENDING"     // Criado, aguardando início
+	StatusAssigned   Status = "ASSIGNED"    // Tarefa aceita por um operador, ainda não iniciada
 	StatusInProgress Status = "IN_PROGRESS" // Sendo bipado/separado
 	StatusCompleted  Status = "COMPLETED"   // Finalizado com sucesso
 	StatusCancelled  Status = "CANCELLED"   // Cancelado
@@ -0,0 +1,220 @@
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+// Unidades de medida usuais da hierarquia de embalagens
+const (
+	UoMEach   = "EA" // Unidade base
+	UoMInner  = "IN"
+	UoMCase   = "CS"
+	UoMPallet = "PL"
+)
+
+var (
+	ErrUoMHierarchyNotFound = errors.New("uom hierarchy not found")
+	ErrInvalidUoMHierarchy  = errors.New("invalid uom hierarchy")
+	ErrUnknownUoM           = errors.New("unknown unit of measure for sku")
+	ErrUoMNotWholeMultiple  = errors.New("quantity is not a whole multiple of the unit of measure")
+	ErrGTINInUse            = errors.New("gtin is already assigned to another sku")
+)
+
+// PackLevel é um nível da hierarquia: quantas unidades base cabem em uma embalagem
+type PackLevel struct {
+	UoM    string `json:"uom"`
+	Factor int    `json:"factor"`
+	GTIN   string `json:"gtin,omitempty"` // Código GS1 da embalagem (ex: GTIN-14 da caixa)
+}
+
+// UoMHierarchy define as embalagens de um SKU (ex: IN = 6 EA, CS = 12 EA, PL = 480 EA).
+// A unidade base EA (fator 1) é implícita.
+type UoMHierarchy struct {
+	SKU       string      `json:"sku"`
+	Levels    []PackLevel `json:"levels"`
+	UnitCube  float64     `json:"unit_cube,omitempty"`  // Volume da unidade base (m³), usado no slotting
+	UnitValue float64     `json:"unit_value,omitempty"` // Valor da unidade base, usado na curva ABC da contagem cíclica
+	GTIN      string      `json:"gtin,omitempty"`       // Código GS1 da unidade base
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// NewUoMHierarchy cria e valida a hierarquia, ordenando os níveis do menor para o maior
+func NewUoMHierarchy(sku string, levels []PackLevel) (*UoMHierarchy, error) {
+	now := time.Now()
+	hierarchy := &UoMHierarchy{
+		SKU:       sku,
+		Levels:    append([]PackLevel(nil), levels...),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	sort.SliceStable(hierarchy.Levels, func(i, j int) bool { return hierarchy.Levels[i].Factor < hierarchy.Levels[j].Factor })
+	if err := hierarchy.Validate(); err != nil {
+		return nil, err
+	}
+	return hierarchy, nil
+}
+
+// Validate exige fatores crescentes em que cada embalagem contém um número inteiro da anterior
+func (h *UoMHierarchy) Validate() error {
+	if h.SKU == "" {
+		return fmt.Errorf("%w: sku is required", ErrInvalidUoMHierarchy)
+	}
+	if h.UnitCube < 0 {
+		return fmt.Errorf("%w: unit cube must not be negative", ErrInvalidUoMHierarchy)
+	}
+	if h.UnitValue < 0 {
+		return fmt.Errorf("%w: unit value must not be negative", ErrInvalidUoMHierarchy)
+	}
+	if err := h.normalizeGTINs(); err != nil {
+		return err
+	}
+	seen := map[string]bool{UoMEach: true}
+	previous := 1
+	for _, level := range h.Levels {
+		switch {
+		case level.UoM == "":
+			return fmt.Errorf("%w: uom is required", ErrInvalidUoMHierarchy)
+		case seen[level.UoM]:
+			return fmt.Errorf("%w: duplicate uom %s", ErrInvalidUoMHierarchy, level.UoM)
+		case level.Factor <= previous:
+			return fmt.Errorf("%w: factor of %s must be greater than %d", ErrInvalidUoMHierarchy, level.UoM, previous)
+		case level.Factor%previous != 0:
+			return fmt.Errorf("%w: %s (%d) is not a whole multiple of the previous level (%d)", ErrInvalidUoMHierarchy, level.UoM, level.Factor, previous)
+		}
+		seen[level.UoM] = true
+		previous = level.Factor
+	}
+	return nil
+}
+
+// normalizeGTINs valida os GTINs da hierarquia e os completa para 14 dígitos; um GTIN identifica um único nível
+func (h *UoMHierarchy) normalizeGTINs() error {
+	seen := make(map[string]bool)
+	normalize := func(gtin *string) error {
+		if *gtin == "" {
+			return nil
+		}
+		normalized, err := gs1.NormalizeGTIN(*gtin)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidUoMHierarchy, err)
+		}
+		if seen[normalized] {
+			return fmt.Errorf("%w: duplicate gtin %s", ErrInvalidUoMHierarchy, normalized)
+		}
+		seen[normalized] = true
+		*gtin = normalized
+		return nil
+	}
+	if err := normalize(&h.GTIN); err != nil {
+		return err
+	}
+	for idx := range h.Levels {
+		if err := normalize(&h.Levels[idx].GTIN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GTINs lista os GTINs cadastrados na hierarquia
+func (h *UoMHierarchy) GTINs() []string {
+	var gtins []string
+	if h.GTIN != "" {
+		gtins = append(gtins, h.GTIN)
+	}
+	for _, level := range h.Levels {
+		if level.GTIN != "" {
+			gtins = append(gtins, level.GTIN)
+		}
+	}
+	return gtins
+}
+
+// UoMForGTIN retorna a unidade identificada pelo GTIN (EA para o GTIN da unidade base)
+func (h *UoMHierarchy) UoMForGTIN(gtin string) (string, bool) {
+	if h.GTIN != "" && h.GTIN == gtin {
+		return UoMEach, true
+	}
+	for _, level := range h.Levels {
+		if level.GTIN != "" && level.GTIN == gtin {
+			return level.UoM, true
+		}
+	}
+	return "", false
+}
+
+// Factor retorna quantas unidades base cabem na unidade (vazio ou EA = 1)
+func (h *UoMHierarchy) Factor(uom string) (int, error) {
+	if uom == "" || uom == UoMEach {
+		return 1, nil
+	}
+	if h == nil {
+		return 0, fmt.Errorf("%w: %s (no hierarchy defined)", ErrUnknownUoM, uom)
+	}
+	for _, level := range h.Levels {
+		if level.UoM == uom {
+			return level.Factor, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s %s", ErrUnknownUoM, h.SKU, uom)
+}
+
+// ToBase converte a quantidade na unidade para unidades base
+func (h *UoMHierarchy) ToBase(uom string, quantity int) (int, error) {
+	factor, err := h.Factor(uom)
+	if err != nil {
+		return 0, err
+	}
+	return quantity * factor, nil
+}
+
+// FromBase converte unidades base para a unidade; rejeita quantidades que não formam embalagens inteiras
+func (h *UoMHierarchy) FromBase(uom string, base int) (int, error) {
+	factor, err := h.Factor(uom)
+	if err != nil {
+		return 0, err
+	}
+	if base%factor != 0 {
+		return 0, fmt.Errorf("%w: %d EA in %s of %d", ErrUoMNotWholeMultiple, base, uom, factor)
+	}
+	return base / factor, nil
+}
+
+// NeedsUoM indica se a linha declara unidade ou embalagem diferente da base
+func (i Item) NeedsUoM() bool {
+	return (i.UoM != "" && i.UoM != UoMEach) || (i.PackUoM != "" && i.PackUoM != UoMEach)
+}
+
+// NormalizeItem expressa a linha em unidades base (EA; UoM vazia permanece vazia), preservando a embalagem declarada.
+// Uma linha em CS passa a ter PackUoM CS; a quantidade base deve formar embalagens inteiras.
+// É idempotente: normalizar uma linha já normalizada não a altera. h nil aceita apenas EA.
+func NormalizeItem(item Item, h *UoMHierarchy) (Item, error) {
+	base, err := h.ToBase(item.UoM, item.Quantity)
+	if err != nil {
+		return Item{}, err
+	}
+
+	pack := item.PackUoM
+	if pack == "" && item.UoM != UoMEach {
+		pack = item.UoM
+	}
+	item.Quantity = base
+	if item.UoM != "" {
+		item.UoM = UoMEach
+	}
+	item.PackUoM, item.PackQuantity = "", 0
+	if pack != "" && pack != UoMEach {
+		packs, err := h.FromBase(pack, base)
+		if err != nil {
+			return Item{}, fmt.Errorf("%w (sku %s)", err, item.SKU)
+		}
+		item.PackUoM, item.PackQuantity = pack, packs
+	}
+	return item, nil
+}
@@ -0,0 +1,280 @@
+package fulfillment
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskType é o tipo de trabalho físico de uma tarefa de armazém
+type TaskType string
+
+const (
+	TaskPick      TaskType = "PICK"       // Separação de ordem de fulfillment
+	TaskPutaway   TaskType = "PUTAWAY"    // Armazenagem de recebimento
+	TaskCount     TaskType = "COUNT"      // Contagem cíclica
+	TaskReplenish TaskType = "REPLENISH"  // Reabastecimento/transferência interna
+	TaskCrossDock TaskType = "CROSS_DOCK" // Doca -> área de expedição, sem armazenagem
+	TaskSlotMove  TaskType = "SLOT_MOVE"  // Realocação de SKU recomendada pelo slotting
+)
+
+var (
+	ErrTaskNotFound          = errors.New("warehouse task not found")
+	ErrInvalidTaskType       = errors.New("invalid warehouse task type")
+	ErrTaskAssigneeRequired  = errors.New("warehouse task requires an assignee")
+	ErrTaskAssignedToOther   = errors.New("warehouse task is assigned to another operator")
+	ErrTaskConflict          = errors.New("warehouse task was modified concurrently")
+	ErrNoTaskAvailable       = errors.New("no warehouse task available")
+	ErrTaskEntityUnsupported = errors.New("entity type does not generate warehouse tasks")
+)
+
+// IsValid indica se o tipo de tarefa é conhecido
+func (t TaskType) IsValid() bool {
+	switch t {
+	case TaskPick, TaskPutaway, TaskCount, TaskReplenish, TaskCrossDock, TaskSlotMove:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskTypeFor retorna o tipo de tarefa padrão gerado por uma entidade de fulfillment
+func TaskTypeFor(entityType string) (TaskType, error) {
+	switch entityType {
+	case EntityFulfillmentOrder:
+		return TaskPick, nil
+	case EntityInboundShipment:
+		return TaskPutaway, nil
+	case EntityCycleCountTask:
+		return TaskCount, nil
+	case EntityTransferOrder:
+		return TaskReplenish, nil
+	default:
+		return "", ErrTaskEntityUnsupported
+	}
+}
+
+// WarehouseTask: unidade de trabalho atribuível a um operador/dispositivo,
+// ligada à operação de fulfillment que ela executa
+type WarehouseTask struct {
+	ID                  string     `json:"id"`
+	Type                TaskType   `json:"type"`
+	Priority            int        `json:"priority"` // Maior = mais urgente
+	Status              Status     `json:"status"`
+	SourceLocation      string     `json:"source_location"`
+	DestinationLocation string     `json:"destination_location,omitempty"`
+	EntityType          string     `json:"entity_type"` // Operação executada pela tarefa
+	EntityID            string     `json:"entity_id"`
+	AssignedTo          string     `json:"assigned_to,omitempty"`
+	DeviceID            string     `json:"device_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	AcceptedAt          *time.Time `json:"accepted_at,omitempty"`
+	StartedAt           *time.Time `json:"started_at,omitempty"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	Version             int64      `json:"version"` // Controle de concorrência otimista (aceite simultâneo)
+}
+
+// NewWarehouseTask cria uma nova tarefa pendente
+func NewWarehouseTask(taskType TaskType, entityType, entityID, source, destination string, priority int) (*WarehouseTask, error) {
+	if !taskType.IsValid() {
+		return nil, ErrInvalidTaskType
+	}
+	now := time.Now()
+	return &WarehouseTask{
+		ID:                  uuid.New().String(),
+		Type:                taskType,
+		Priority:            priority,
+		Status:              StatusPending,
+		SourceLocation:      source,
+		DestinationLocation: destination,
+		EntityType:          entityType,
+		EntityID:            entityID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}, nil
+}
+
+// Accept atribui a tarefa ao operador (e dispositivo)
+func (t *WarehouseTask) Accept(assignee, device string) error {
+	if assignee == "" {
+		return ErrTaskAssigneeRequired
+	}
+	if err := Fire(t, EventAccept); err != nil {
+		return err
+	}
+	now := time.Now()
+	t.AssignedTo, t.DeviceID, t.AcceptedAt = assignee, device, &now
+	return nil
+}
+
+// Start inicia a execução pelo operador atribuído
+func (t *WarehouseTask) Start(assignee string) error {
+	if err := t.checkAssignee(assignee); err != nil {
+		return err
+	}
+	return Fire(t, EventStart)
+}
+
+// Complete finaliza a tarefa pelo operador atribuído
+func (t *WarehouseTask) Complete(assignee string) error {
+	if err := t.checkAssignee(assignee); err != nil {
+		return err
+	}
+	return Fire(t, EventComplete)
+}
+
+// Abandon devolve a tarefa à fila, liberando operador e dispositivo
+func (t *WarehouseTask) Abandon(assignee string) error {
+	if err := t.checkAssignee(assignee); err != nil {
+		return err
+	}
+	return Fire(t, EventAbandon)
+}
+
+// Cancel cancela a tarefa
+func (t *WarehouseTask) Cancel() error {
+	return Fire(t, EventCancel)
+}
+
+// IsActive indica se a tarefa está com um operador (aceita ou em execução)
+func (t *WarehouseTask) IsActive() bool {
+	return t.Status == StatusAssigned || t.Status == StatusInProgress
+}
+
+func (t *WarehouseTask) checkAssignee(assignee string) error {
+	if assignee == "" {
+		return ErrTaskAssigneeRequired
+	}
+	if t.AssignedTo != "" && t.AssignedTo != assignee {
+		return ErrTaskAssignedToOther
+	}
+	return nil
+}
+
+// CurrentStatus implementa Stateful
+func (t *WarehouseTask) CurrentStatus() Status { return t.Status }
+
+// Operation implementa Stateful
+func (t *WarehouseTask) Operation() OperationType { return OpWarehouseTask }
+
+func (t *WarehouseTask) applyStatus(to Status, at time.Time) {
+	t.Status = to
+	t.UpdatedAt = at
+	switch to {
+	case StatusInProgress:
+		t.StartedAt = &at
+	case StatusCompleted:
+		t.CompletedAt = &at
+	case StatusPending:
+		// Abandono: a tarefa volta para a fila sem dono
+		t.AssignedTo, t.DeviceID = "", ""
+		t.AcceptedAt, t.StartedAt = nil, nil
+	}
+}
+
+// Pesos do ranqueamento da próxima tarefa
+const (
+	taskPriorityWeight  = 100 // Por nível de prioridade
+	taskInterleaveBonus = 40  // Tipo diferente do último trabalho (intercalação)
+	taskAgingMaxBonus   = 60  // Minutos de espera contados, evitando inanição
+)
+
+// TaskCandidate é uma tarefa ranqueada para um operador
+type TaskCandidate struct {
+	Task     *WarehouseTask `json:"task"`
+	Distance int            `json:"distance"`
+	Score    int            `json:"score"`
+}
+
+// RankTasks ordena as tarefas pendentes para um operador em location cujo último trabalho foi lastType.
+// A pontuação combina prioridade, deslocamento vazio até a origem, intercalação de tipos e tempo de espera.
+func RankTasks(tasks []*WarehouseTask, location string, lastType TaskType, now time.Time) []TaskCandidate {
+	candidates := make([]TaskCandidate, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status != StatusPending {
+			continue
+		}
+		distance := TravelDistance(location, task.SourceLocation)
+		score := task.Priority*taskPriorityWeight - distance
+		if lastType != "" && task.Type != lastType {
+			score += taskInterleaveBonus
+		}
+		waited := int(now.Sub(task.CreatedAt).Minutes())
+		if waited > taskAgingMaxBonus {
+			waited = taskAgingMaxBonus
+		}
+		if waited > 0 {
+			score += waited
+		}
+		candidates = append(candidates, TaskCandidate{Task: task, Distance: distance, Score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Task.CreatedAt.Before(candidates[j].Task.CreatedAt)
+	})
+	return candidates
+}
+
+// Distâncias estimadas entre endereços no formato ZONA-CORREDOR-POSIÇÃO[-NÍVEL]
+const (
+	travelUnknown     = 50  // Origem ou destino desconhecido
+	travelAisleChange = 20  // Por corredor atravessado
+	travelZoneChange  = 200 // Troca de zona
+)
+
+// TravelDistance estima o deslocamento entre dois endereços (mesmo corredor: diferença de posições;
+// outro corredor: saída pela cabeceira; outra zona: custo fixo alto)
+func TravelDistance(from, to string) int {
+	if from == "" || to == "" {
+		return travelUnknown
+	}
+	if from == to {
+		return 0
+	}
+
+	a, b := strings.Split(from, "-"), strings.Split(to, "-")
+	if a[0] != b[0] || len(a) < 2 || len(b) < 2 {
+		return travelZoneChange
+	}
+
+	aisleA, errA := strconv.Atoi(a[1])
+	aisleB, errB := strconv.Atoi(b[1])
+	if errA != nil || errB != nil {
+		if a[1] != b[1] {
+			return travelAisleChange
+		}
+		aisleA, aisleB = 0, 0
+	}
+
+	posA, posB := locationSegment(a, 2), locationSegment(b, 2)
+	if aisleA == aisleB {
+		return absInt(posA - posB)
+	}
+	return absInt(aisleA-aisleB)*travelAisleChange + posA + posB
+}
+
+func locationSegment(parts []string, idx int) int {
+	if idx >= len(parts) {
+		return 0
+	}
+	n, err := strconv.Atoi(parts[idx])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type blockRequest struct {
+	ReasonCode fulfillment.BlockReason `json:"reason_code" binding:"required"`
+	Note       string                  `json:"note"`
+}
+
+type unblockRequest struct {
+	Note string `json:"note"`
+}
+
+func blockError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidBlockReason),
+		errors.Is(err, fulfillment.ErrBlockNoteRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, app.ErrNotBlocked),
+		errors.Is(err, fulfillment.ErrNoActiveHold),
+		errors.Is(err, fulfillment.ErrActiveHolds):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		retryError(c, err)
+	}
+}
+
+// handleBlock responde POST /v1/block/:entity/:id
+func handleBlock(uc *app.BlockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req blockRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		entityType := timelineEntities[c.Param("entity")]
+		if err := uc.Block(c.Request.Context(), entityType, c.Param("id"), req.ReasonCode, req.Note); err != nil {
+			blockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": fulfillment.StatusBlocked})
+	}
+}
+
+// handleUnblock responde POST /v1/unblock/:entity/:id
+func handleUnblock(uc *app.BlockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req unblockRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		entityType := timelineEntities[c.Param("entity")]
+		if err := uc.Unblock(c.Request.Context(), entityType, c.Param("id"), req.Note); err != nil {
+			blockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "unblocked"})
+	}
+}
+
+// handleReleaseHold responde POST /v1/release_hold/:entity/:id (liberação da alfândega ou da qualidade)
+func handleReleaseHold(uc *app.BlockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityType := timelineEntities[c.Param("entity")]
+		if err := uc.ReleaseHold(c.Request.Context(), entityType, c.Param("id")); err != nil {
+			blockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "released"})
+	}
+}
+
+// handleListBlocked responde GET /v1/blocked?entity=
+func handleListBlocked(uc *app.BlockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var entityType string
+		if raw := c.Query("entity"); raw != "" {
+			var ok bool
+			if entityType, ok = timelineEntities[raw]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown entity " + raw})
+				return
+			}
+		}
+
+		blocked, err := uc.ListBlocked(c.Request.Context(), entityType)
+		if err != nil {
+			blockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"blocked": blocked})
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type CountProgramRequest struct {
+	Frequencies   map[fulfillment.VelocityClass]int `json:"frequencies" binding:"required"` // Dias entre contagens: {"A":30,"B":90,"C":365}
+	LookbackDays  int                               `json:"lookback_days"`                  // Padrão: 90
+	DailyCapacity int                               `json:"daily_capacity"`                 // 0 = derivada das frequências
+}
+
+func countProgramError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidCountProgram):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleGetCountProgram responde GET /v1/cycle_count/program
+func handleGetCountProgram(uc *app.CountPlannerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		program, err := uc.GetProgram(c.Request.Context())
+		if err != nil {
+			countProgramError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, program)
+	}
+}
+
+// handleDefineCountProgram responde POST /v1/cycle_count/program (substitui o programa)
+func handleDefineCountProgram(uc *app.CountPlannerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CountProgramRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		program := &fulfillment.CountProgram{Frequencies: req.Frequencies, LookbackDays: req.LookbackDays, DailyCapacity: req.DailyCapacity}
+		if program.LookbackDays == 0 {
+			program.LookbackDays = fulfillment.DefaultCountProgram().LookbackDays
+		}
+		if err := uc.DefineProgram(c.Request.Context(), program); err != nil {
+			countProgramError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, program)
+	}
+}
+
+// handleCountClassification responde GET /v1/cycle_count/classification
+func handleCountClassification(uc *app.CountPlannerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		candidates, err := uc.Classify(c.Request.Context(), time.Now())
+		if err != nil {
+			countProgramError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"locations": candidates})
+	}
+}
+
+// handlePlanCycleCounts responde POST /v1/cycle_count/plan (gera as contagens do dia)
+func handlePlanCycleCounts(uc *app.CountPlannerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plan, err := uc.PlanDay(c.Request.Context(), time.Now())
+		if err != nil {
+			countProgramError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+// handleListCountTriggers responde GET /v1/cycle_count/triggers (gatilhos ainda sem contagem)
+func handleListCountTriggers(uc *app.CountPlannerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		triggers, err := uc.ListTriggers(c.Request.Context())
+		if err != nil {
+			countProgramError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"triggers": triggers})
+	}
+}
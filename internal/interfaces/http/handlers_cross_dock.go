@@ -0,0 +1,92 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type CrossDockPolicyRequest struct {
+	Customer        string `json:"customer"` // Vazio = todos os clientes
+	SKU             string `json:"sku"`      // Vazio = todos os SKUs
+	Enabled         *bool  `json:"enabled"`  // Padrão: true
+	StagingLocation string `json:"staging_location"`
+	IncludePending  bool   `json:"include_pending"`
+}
+
+func crossDockError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidCrossDockPolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrCrossDockPolicyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineCrossDockPolicy responde POST /v1/crossdock/policies (cria ou substitui por cliente e SKU)
+func handleDefineCrossDockPolicy(uc *app.CrossDockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CrossDockPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		enabled := req.Enabled == nil || *req.Enabled
+		policy, err := fulfillment.NewCrossDockPolicy(req.Customer, req.SKU, req.StagingLocation, enabled, req.IncludePending)
+		if err != nil {
+			crossDockError(c, err)
+			return
+		}
+
+		if err := uc.DefinePolicy(c.Request.Context(), policy); err != nil {
+			crossDockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// handleListCrossDockPolicies responde GET /v1/crossdock/policies
+func handleListCrossDockPolicies(uc *app.CrossDockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies, err := uc.ListPolicies(c.Request.Context())
+		if err != nil {
+			crossDockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"policies": policies})
+	}
+}
+
+// handleDeleteCrossDockPolicy responde DELETE /v1/crossdock/policies/:id
+func handleDeleteCrossDockPolicy(uc *app.CrossDockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := uc.DeletePolicy(c.Request.Context(), c.Param("id")); err != nil {
+			crossDockError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleListCrossDockAllocations responde GET /v1/crossdock/allocations?shipment_id=
+func handleListCrossDockAllocations(uc *app.CrossDockUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allocations, err := uc.ListAllocations(c.Request.Context(), c.Query("shipment_id"))
+		if err != nil {
+			crossDockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"allocations": allocations})
+	}
+}
@@ -45,7 +45,7 @@ func handleSubmitCycleCount(uc *app.SubmitCycleCountUseCase) gin.HandlerFunc {
 		}
 
 		if err := uc.SubmitCycleCount(c.Request.Context(), req.TaskID, req.CountedItems); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			uomError(c, err)
 			return
 		}
 
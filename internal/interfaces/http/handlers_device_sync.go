@@ -0,0 +1,102 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type PushSyncRequest struct {
+	DeviceID   string                      `json:"device_id" binding:"required"`
+	Operations []fulfillment.SyncOperation `json:"operations" binding:"required"` // Na ordem em que foram registradas
+}
+
+type ResolveSyncConflictRequest struct {
+	Choice fulfillment.SyncChoice `json:"choice" binding:"required"` // SERVER ou DEVICE
+}
+
+// syncError trata os erros da sincronização; os da operação aplicada (ex: leitura de separação)
+// seguem o mapeamento da separação guiada
+func syncError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidSyncOperation),
+		errors.Is(err, fulfillment.ErrUnsupportedSyncKind),
+		errors.Is(err, fulfillment.ErrInvalidSyncChoice):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrSyncConflictNotFound),
+		errors.Is(err, fulfillment.ErrSyncStateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrSyncConflictResolved),
+		errors.Is(err, fulfillment.ErrSyncStateConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		pickSessionError(c, err)
+	}
+}
+
+// handlePushSync responde POST /v1/sync com o resultado de cada operação enviada pelo coletor
+func handlePushSync(uc *app.DeviceSyncUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PushSyncRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		results, err := uc.Push(c.Request.Context(), req.DeviceID, req.Operations)
+		if err != nil {
+			syncError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"results": results})
+	}
+}
+
+// handleListSyncConflicts responde GET /v1/sync/conflicts?status=
+func handleListSyncConflicts(uc *app.DeviceSyncUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conflicts, err := uc.ListConflicts(c.Request.Context(), fulfillment.Status(c.Query("status")))
+		if err != nil {
+			syncError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"conflicts": conflicts})
+	}
+}
+
+// handleGetSyncConflict responde GET /v1/sync/conflicts/:id
+func handleGetSyncConflict(uc *app.DeviceSyncUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conflict, err := uc.GetConflict(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			syncError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, conflict)
+	}
+}
+
+// handleResolveSyncConflict responde POST /v1/sync/conflicts/:id/resolve (supervisor em X-Actor)
+func handleResolveSyncConflict(uc *app.DeviceSyncUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ResolveSyncConflictRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		conflict, err := uc.ResolveConflict(c.Request.Context(), c.Param("id"), req.Choice)
+		if err != nil {
+			syncError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, conflict)
+	}
+}
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type DockDoorRequest struct {
+	Name         string                  `json:"name" binding:"required"`
+	Direction    string                  `json:"direction" binding:"required"` // INBOUND | OUTBOUND | BOTH
+	Capabilities []string                `json:"capabilities"`
+	Hours        []fulfillment.DockHours `json:"hours"`
+	SlotMinutes  int                     `json:"slot_minutes"` // Padrão: 60
+	Timezone     string                  `json:"timezone"`     // IANA; vazio = UTC
+	Active       *bool                   `json:"active"`       // Padrão: true
+}
+
+type BookAppointmentRequest struct {
+	DoorID       string    `json:"door_id" binding:"required"`
+	Direction    string    `json:"direction" binding:"required"`   // INBOUND | OUTBOUND
+	ShipmentID   string    `json:"shipment_id" binding:"required"` // InboundShipment ou OutboundShipment
+	Supplier     string    `json:"supplier"`
+	Carrier      string    `json:"carrier"`
+	Start        time.Time `json:"start" binding:"required"`
+	End          time.Time `json:"end"` // Padrão: início + slot da porta
+	Requirements []string  `json:"requirements"`
+}
+
+func dockError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidDockDoor),
+		errors.Is(err, fulfillment.ErrInvalidAppointment),
+		errors.Is(err, fulfillment.ErrDockCapabilityMissing):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrDockDoorNotFound),
+		errors.Is(err, fulfillment.ErrAppointmentNotFound),
+		errors.Is(err, fulfillment.ErrShipmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrAppointmentConflict),
+		errors.Is(err, fulfillment.ErrAppointmentChanged),
+		errors.Is(err, fulfillment.ErrDockClosed),
+		errors.Is(err, fulfillment.ErrInvalidStateTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineDockDoor responde POST /v1/docks (cria ou substitui pelo nome)
+func handleDefineDockDoor(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DockDoorRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		door, err := fulfillment.NewDockDoor(req.Name, fulfillment.DockDirection(req.Direction), req.Capabilities, req.Hours, req.SlotMinutes, req.Timezone)
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+		door.Active = req.Active == nil || *req.Active
+
+		if err := uc.DefineDoor(c.Request.Context(), door); err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, door)
+	}
+}
+
+// handleListDockDoors responde GET /v1/docks
+func handleListDockDoors(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		doors, err := uc.ListDoors(c.Request.Context())
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"doors": doors})
+	}
+}
+
+// handleDockSlots responde GET /v1/docks/:id/slots?date=YYYY-MM-DD (padrão: hoje)
+func handleDockSlots(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		day := time.Now()
+		if raw := c.Query("date"); raw != "" {
+			parsed, err := time.Parse(time.DateOnly, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+				return
+			}
+			day = parsed
+		}
+
+		slots, err := uc.AvailableSlots(c.Request.Context(), c.Param("id"), day)
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"door_id": c.Param("id"), "date": day.Format(time.DateOnly), "slots": slots})
+	}
+}
+
+// handleBookAppointment responde POST /v1/appointments
+func handleBookAppointment(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req BookAppointmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		appointment, err := uc.Book(c.Request.Context(), app.BookingRequest{
+			DoorID:       req.DoorID,
+			Direction:    fulfillment.DockDirection(req.Direction),
+			ShipmentID:   req.ShipmentID,
+			Supplier:     req.Supplier,
+			Carrier:      req.Carrier,
+			Start:        req.Start,
+			End:          req.End,
+			Requirements: req.Requirements,
+		})
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, appointment)
+	}
+}
+
+// handleListAppointments responde GET /v1/appointments?door_id=&from=&to= (RFC3339; padrão: próximos 7 dias)
+func handleListAppointments(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from := time.Now()
+		to := from.Add(7 * 24 * time.Hour)
+		for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+			if raw := c.Query(param); raw != "" {
+				parsed, err := time.Parse(time.RFC3339, raw)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC3339 timestamp"})
+					return
+				}
+				*target = parsed
+			}
+		}
+
+		appointments, err := uc.ListAppointments(c.Request.Context(), c.Query("door_id"), from, to)
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"appointments": appointments})
+	}
+}
+
+// handleGetAppointment responde GET /v1/appointments/:id
+func handleGetAppointment(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appointment, err := uc.GetAppointment(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, appointment)
+	}
+}
+
+// handleAppointmentLifecycle responde POST /v1/appointments/:id/{check_in,complete,cancel}
+func handleAppointmentLifecycle(uc *app.DockSchedulingUseCase, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, id := c.Request.Context(), c.Param("id")
+
+		var appointment *fulfillment.DockAppointment
+		var err error
+		switch action {
+		case "check_in":
+			appointment, err = uc.CheckIn(ctx, id)
+		case "complete":
+			appointment, err = uc.Complete(ctx, id)
+		case "cancel":
+			appointment, err = uc.Cancel(ctx, id)
+		}
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, appointment)
+	}
+}
+
+// handleSupplierScorecards responde GET /v1/scorecards/suppliers?since=RFC3339 (padrão: últimos 30 dias)
+func handleSupplierScorecards(uc *app.DockSchedulingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since := time.Now().Add(-30 * 24 * time.Hour)
+		if raw := c.Query("since"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+				return
+			}
+			since = parsed
+		}
+
+		scorecards, err := uc.Scorecards(c.Request.Context(), since)
+		if err != nil {
+			dockError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"since": since, "scorecards": scorecards})
+	}
+}
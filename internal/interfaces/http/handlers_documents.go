@@ -0,0 +1,135 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type DocumentTemplateRequest struct {
+	Customer string                     `json:"customer"` // Vazio = modelo padrão da operação
+	Type     fulfillment.DocumentType   `json:"type" binding:"required"`
+	Format   fulfillment.DocumentFormat `json:"format" binding:"required"`
+	Body     string                     `json:"body" binding:"required"` // text/template do Go
+}
+
+type RenderDocumentRequest struct {
+	OrderID string                     `json:"order_id" binding:"required"`
+	Type    fulfillment.DocumentType   `json:"type" binding:"required"`
+	Format  fulfillment.DocumentFormat `json:"format" binding:"required"`
+}
+
+func documentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidDocumentTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrDocumentTemplateNotFound),
+		errors.Is(err, fulfillment.ErrDocumentNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineDocumentTemplate responde POST /v1/documents/templates (cria ou substitui por cliente, tipo e formato)
+func handleDefineDocumentTemplate(uc *app.DocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DocumentTemplateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tmpl, err := uc.DefineTemplate(c.Request.Context(), req.Customer, req.Type, req.Format, req.Body)
+		if err != nil {
+			documentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, tmpl)
+	}
+}
+
+// handleListDocumentTemplates responde GET /v1/documents/templates
+func handleListDocumentTemplates(uc *app.DocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		templates, err := uc.ListTemplates(c.Request.Context())
+		if err != nil {
+			documentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"templates": templates})
+	}
+}
+
+// handleDeleteDocumentTemplate responde DELETE /v1/documents/templates/:id
+func handleDeleteDocumentTemplate(uc *app.DocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := uc.DeleteTemplate(c.Request.Context(), c.Param("id")); err != nil {
+			documentError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleRenderDocument responde POST /v1/documents (gera e guarda o documento da ordem)
+func handleRenderDocument(uc *app.DocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RenderDocumentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		document, err := uc.Render(c.Request.Context(), req.OrderID, req.Type, req.Format)
+		if err != nil {
+			documentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, document)
+	}
+}
+
+// handleListDocuments responde GET /v1/documents?order_id=
+func handleListDocuments(uc *app.DocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Query("order_id")
+		if orderID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order_id is required"})
+			return
+		}
+
+		documents, err := uc.ListDocuments(c.Request.Context(), orderID)
+		if err != nil {
+			documentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"documents": documents})
+	}
+}
+
+// handleDownloadDocument responde GET /v1/documents/:id (conteúdo guardado, para reimpressão)
+func handleDownloadDocument(uc *app.DocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		document, err := uc.GetDocument(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			documentError(c, err)
+			return
+		}
+
+		if document.Format != fulfillment.DocumentHTML {
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, document.Type, document.ID, document.Format))
+		}
+		c.Data(http.StatusOK, document.Format.ContentType(), document.Content)
+	}
+}
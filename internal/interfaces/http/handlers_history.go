@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// parseAt lê o parâmetro ?at= (RFC3339); ausente significa "agora"
+func parseAt(c *gin.Context) (time.Time, bool) {
+	raw := c.Query("at")
+	if raw == "" {
+		return time.Now(), true
+	}
+	at, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at must be an RFC3339 timestamp"})
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+func historyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, app.ErrHistoryUnavailable):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrAggregateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrHistoryPruned):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func handleOrderHistory(uc *app.QueryHistoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at, ok := parseAt(c)
+		if !ok {
+			return
+		}
+
+		order, err := uc.GetOrderAt(c.Request.Context(), c.Param("id"), at)
+		if err != nil {
+			historyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func handleTransferHistory(uc *app.QueryHistoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at, ok := parseAt(c)
+		if !ok {
+			return
+		}
+
+		transfer, err := uc.GetTransferAt(c.Request.Context(), c.Param("id"), at)
+		if err != nil {
+			historyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, transfer)
+	}
+}
+
+func handleReturnHistory(uc *app.QueryHistoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at, ok := parseAt(c)
+		if !ok {
+			return
+		}
+
+		returnOrder, err := uc.GetReturnAt(c.Request.Context(), c.Param("id"), at)
+		if err != nil {
+			historyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, returnOrder)
+	}
+}
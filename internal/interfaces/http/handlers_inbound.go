@@ -29,7 +29,7 @@ func handleStartInbound(uc *app.ReceiveGoodsUseCase) gin.HandlerFunc {
 
 		shipment, err := uc.StartInbound(c.Request.Context(), req.ReferenceID, req.Origin, req.Destination, req.Items)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			uomError(c, err)
 			return
 		}
 
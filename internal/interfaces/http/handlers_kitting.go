@@ -0,0 +1,171 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type KitRequest struct {
+	SKU        string                     `json:"sku" binding:"required"`
+	Name       string                     `json:"name"`
+	Components []fulfillment.KitComponent `json:"components" binding:"required"`
+}
+
+type AssemblyOrderRequest struct {
+	KitSKU    string                        `json:"kit_sku" binding:"required"`
+	Direction fulfillment.AssemblyDirection `json:"direction"` // Padrão: ASSEMBLE
+	Quantity  int                           `json:"quantity" binding:"required"`
+	Location  string                        `json:"location" binding:"required"`
+}
+
+type AssembleForOrderRequest struct {
+	OrderID string `json:"order_id" binding:"required"`
+}
+
+func kittingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidKit), errors.Is(err, fulfillment.ErrInvalidAssembly):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrKitNotFound),
+		errors.Is(err, fulfillment.ErrAssemblyOrderNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrInvalidStateTransition), errors.Is(err, fulfillment.ErrAssemblyUnreconciled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineKit responde POST /v1/kits (cria ou substitui a lista de materiais)
+func handleDefineKit(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req KitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		kit, err := uc.DefineKit(c.Request.Context(), req.SKU, req.Name, req.Components)
+		if err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, kit)
+	}
+}
+
+// handleListKits responde GET /v1/kits
+func handleListKits(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kits, err := uc.ListKits(c.Request.Context())
+		if err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"kits": kits})
+	}
+}
+
+// handleGetKit responde GET /v1/kits/:sku
+func handleGetKit(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kit, err := uc.GetKit(c.Request.Context(), c.Param("sku"))
+		if err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, kit)
+	}
+}
+
+// handleDeleteKit responde DELETE /v1/kits/:sku
+func handleDeleteKit(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := uc.DeleteKit(c.Request.Context(), c.Param("sku")); err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleCreateAssemblyOrder responde POST /v1/assembly (montagem ou desmontagem)
+func handleCreateAssemblyOrder(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AssemblyOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Direction == "" {
+			req.Direction = fulfillment.AssemblyAssemble
+		}
+
+		order, err := uc.CreateOrder(c.Request.Context(), req.KitSKU, req.Direction, req.Quantity, req.Location, "")
+		if err != nil {
+			// A ordem falhou após ser criada: retorna o estado compensado
+			if order != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "assembly_order": order})
+				return
+			}
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, order)
+	}
+}
+
+// handleGetAssemblyOrder responde GET /v1/assembly/:id
+func handleGetAssemblyOrder(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, err := uc.GetOrder(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+// handleExecuteAssemblyOrder responde POST /v1/assembly/:id/execute (reexecuta ordens que falharam)
+func handleExecuteAssemblyOrder(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, err := uc.Execute(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+// handleAssembleForOrder responde POST /v1/outbound/assemble_kits montando os kits que faltam para a ordem
+func handleAssembleForOrder(uc *app.AssemblyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AssembleForOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		orders, err := uc.AssembleForOrder(c.Request.Context(), req.OrderID)
+		if err != nil {
+			kittingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"assembly_orders": orders})
+	}
+}
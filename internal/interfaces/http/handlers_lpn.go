@@ -0,0 +1,209 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type CreateLPNRequest struct {
+	Code     string             `json:"code" binding:"required"`
+	Type     string             `json:"type" binding:"required"` // PALLET | CASE | TOTE
+	Location string             `json:"location"`                // Vazio = anunciado, aguardando recebimento
+	Parent   string             `json:"parent"`                  // Cria já aninhado no contêiner
+	Contents []fulfillment.Item `json:"contents"`
+}
+
+type LPNItemsRequest struct {
+	Items []fulfillment.Item `json:"items" binding:"required"`
+}
+
+type NestLPNRequest struct {
+	Child string `json:"child" binding:"required"`
+}
+
+type ReceiveLPNRequest struct {
+	ReferenceID string `json:"reference_id"` // Padrão: LPN-<código>
+	Origin      string `json:"origin"`
+	Dock        string `json:"dock" binding:"required"`
+}
+
+type MoveLPNRequest struct {
+	To string `json:"to" binding:"required"`
+}
+
+type ShipLPNRequest struct {
+	OrderID string `json:"order_id" binding:"required"` // ID da FulfillmentOrder
+}
+
+func lpnError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidLPN),
+		errors.Is(err, fulfillment.ErrLPNNesting),
+		errors.Is(err, fulfillment.ErrUnknownUoM),
+		errors.Is(err, fulfillment.ErrUoMNotWholeMultiple):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrLPNNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrLPNExists),
+		errors.Is(err, fulfillment.ErrLPNNotHandleable),
+		errors.Is(err, fulfillment.ErrLPNContentsMismatch),
+		errors.Is(err, fulfillment.ErrOrderBackordered),
+		errors.Is(err, fulfillment.ErrInvalidStateTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleCreateLPN responde POST /v1/lpns
+func handleCreateLPN(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateLPNRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		lpn, err := uc.Create(c.Request.Context(), req.Code, fulfillment.LPNType(req.Type), req.Location, req.Parent, req.Contents)
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, lpn)
+	}
+}
+
+// handleLPNInquiry responde GET /v1/lpns/:code com conteúdo, localização e histórico
+func handleLPNInquiry(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inquiry, err := uc.Inquiry(c.Request.Context(), c.Param("code"))
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, inquiry)
+	}
+}
+
+// handleLPNContents responde POST /v1/lpns/:code/{pack,unpack}
+func handleLPNContents(uc *app.LPNUseCase, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LPNItemsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, code := c.Request.Context(), c.Param("code")
+		var lpn *fulfillment.LPN
+		var err error
+		switch action {
+		case "pack":
+			lpn, err = uc.Pack(ctx, code, req.Items)
+		case "unpack":
+			lpn, err = uc.Unpack(ctx, code, req.Items)
+		}
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, lpn)
+	}
+}
+
+// handleNestLPN responde POST /v1/lpns/:code/nest (coloca child dentro de :code)
+func handleNestLPN(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req NestLPNRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		lpn, err := uc.Nest(c.Request.Context(), c.Param("code"), req.Child)
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, lpn)
+	}
+}
+
+// handleUnnestLPN responde POST /v1/lpns/:code/unnest
+func handleUnnestLPN(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lpn, err := uc.Unnest(c.Request.Context(), c.Param("code"))
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, lpn)
+	}
+}
+
+// handleReceiveLPN responde POST /v1/lpns/:code/receive
+func handleReceiveLPN(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReceiveLPNRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		lpn, err := uc.Receive(c.Request.Context(), c.Param("code"), req.ReferenceID, req.Origin, req.Dock)
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, lpn)
+	}
+}
+
+// handleMoveLPN responde POST /v1/lpns/:code/move (armazenagem ou transferência)
+func handleMoveLPN(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req MoveLPNRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		lpn, err := uc.Move(c.Request.Context(), c.Param("code"), req.To)
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, lpn)
+	}
+}
+
+// handleShipLPN responde POST /v1/lpns/:code/ship
+func handleShipLPN(uc *app.LPNUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ShipLPNRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		lpn, err := uc.Ship(c.Request.Context(), c.Param("code"), req.OrderID)
+		if err != nil {
+			lpnError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, lpn)
+	}
+}
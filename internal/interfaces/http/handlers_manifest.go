@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type HandOverShipmentRequest struct {
+	OrderID        string  `json:"order_id" binding:"required"`
+	Carrier        string  `json:"carrier" binding:"required"`
+	TrackingNumber string  `json:"tracking_number"`
+	Pieces         int     `json:"pieces" binding:"required"` // Volumes entregues
+	Weight         float64 `json:"weight"`                    // kg
+}
+
+func manifestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidOutboundShipment),
+		errors.Is(err, fulfillment.ErrUnsupportedManifestFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrManifestNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrManifestClosed),
+		errors.Is(err, fulfillment.ErrManifestNotClosed),
+		errors.Is(err, fulfillment.ErrOutboundShipmentExists),
+		errors.Is(err, fulfillment.ErrOrderNotShipped):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleHandOverShipment responde POST /v1/outbound/shipments (entrega da ordem expedida à transportadora)
+func handleHandOverShipment(uc *app.ManifestUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req HandOverShipmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		shipment, err := uc.HandOver(c.Request.Context(), req.OrderID, req.Carrier, req.TrackingNumber, req.Pieces, req.Weight)
+		if err != nil {
+			manifestError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, shipment)
+	}
+}
+
+// handleListManifests responde GET /v1/manifests?carrier=&date=YYYY-MM-DD
+func handleListManifests(uc *app.ManifestUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var date *time.Time
+		if raw := c.Query("date"); raw != "" {
+			parsed, err := time.Parse(time.DateOnly, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+				return
+			}
+			date = &parsed
+		}
+
+		manifests, err := uc.ListManifests(c.Request.Context(), c.Query("carrier"), date)
+		if err != nil {
+			manifestError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"manifests": manifests})
+	}
+}
+
+// handleGetManifest responde GET /v1/manifests/:id
+func handleGetManifest(uc *app.ManifestUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		view, err := uc.GetManifest(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			manifestError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, view)
+	}
+}
+
+// handleCloseManifest responde POST /v1/manifests/:id/close
+func handleCloseManifest(uc *app.ManifestUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		manifest, err := uc.Close(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			manifestError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, manifest)
+	}
+}
+
+// handleManifestDocument responde GET /v1/manifests/:id/document?format=csv|html (padrão: html)
+func handleManifestDocument(uc *app.ManifestUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := fulfillment.ManifestFormat(c.DefaultQuery("format", string(fulfillment.ManifestHTML)))
+		document, err := uc.Document(c.Request.Context(), c.Param("id"), format)
+		if err != nil {
+			manifestError(c, err)
+			return
+		}
+
+		contentType := "text/html; charset=utf-8"
+		if format == fulfillment.ManifestCSV {
+			contentType = "text/csv; charset=utf-8"
+			c.Header("Content-Disposition", `attachment; filename="manifest-`+c.Param("id")+`.csv"`)
+		}
+		c.Data(http.StatusOK, contentType, document)
+	}
+}
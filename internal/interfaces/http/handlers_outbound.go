@@ -1,10 +1,12 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
 
 type StartPickingRequest struct {
@@ -15,6 +17,15 @@ type ShipOrderRequest struct {
 	OrderID string `json:"order_id" binding:"required"`
 }
 
+type CancelOrderRequest struct {
+	OrderID string `json:"order_id" binding:"required"`
+	Reason  string `json:"reason"`
+}
+
+type ReleaseBackorderRequest struct {
+	OrderID string `json:"order_id" binding:"required"`
+}
+
 func handleStartPicking(uc *app.ShipOrderUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req StartPickingRequest
@@ -48,3 +59,41 @@ func handleShipOrder(uc *app.ShipOrderUseCase) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"status": "shipped"})
 	}
 }
+
+func handleCancelOrder(uc *app.ShipOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CancelOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := uc.CancelOrder(c.Request.Context(), req.OrderID, req.Reason); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "cancelled"})
+	}
+}
+
+func handleReleaseBackorder(uc *app.ShipOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReleaseBackorderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := uc.ReleaseBackorder(c.Request.Context(), req.OrderID); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, fulfillment.ErrOrderBackordered) || errors.Is(err, fulfillment.ErrNotBackordered) {
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "reserved"})
+	}
+}
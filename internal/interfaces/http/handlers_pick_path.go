@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type WarehouseLayoutRequest struct {
+	Aisles      []fulfillment.Aisle         `json:"aisles" binding:"required"`
+	CrossAisles []float64                   `json:"cross_aisles"` // Vazio = cabeceiras frontal e de fundo
+	Depot       fulfillment.Point           `json:"depot"`
+	Bins        []fulfillment.BinCoordinate `json:"bins"`
+}
+
+type LayoutBinsRequest struct {
+	Bins []fulfillment.BinCoordinate `json:"bins" binding:"required"`
+}
+
+type PickRouteRequest struct {
+	OrderIDs []string           `json:"order_ids"` // IDs de FulfillmentOrder separadas em lote
+	Items    []fulfillment.Item `json:"items"`     // Linhas avulsas (Location obrigatória para entrar na rota)
+}
+
+func pickPathError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidLayout):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrLayoutNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrPickLocationUnreached):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineLayout responde POST /v1/layout (substitui o layout)
+func handleDefineLayout(uc *app.PickPathUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req WarehouseLayoutRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		layout, err := fulfillment.NewWarehouseLayout(req.Aisles, req.CrossAisles, req.Depot, req.Bins)
+		if err != nil {
+			pickPathError(c, err)
+			return
+		}
+
+		if err := uc.DefineLayout(c.Request.Context(), layout); err != nil {
+			pickPathError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, layout)
+	}
+}
+
+// handleGetLayout responde GET /v1/layout
+func handleGetLayout(uc *app.PickPathUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		layout, err := uc.GetLayout(c.Request.Context())
+		if err != nil {
+			pickPathError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, layout)
+	}
+}
+
+// handleSaveLayoutBins responde POST /v1/layout/bins (inclui ou atualiza coordenadas)
+func handleSaveLayoutBins(uc *app.PickPathUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LayoutBinsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		layout, err := uc.SaveBins(c.Request.Context(), req.Bins)
+		if err != nil {
+			pickPathError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"bins": len(layout.Bins), "updated_at": layout.UpdatedAt})
+	}
+}
+
+// handlePlanPickRoute responde POST /v1/pick_routes com a rota escolhida e a distância de cada estratégia
+func handlePlanPickRoute(uc *app.PickPathUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PickRouteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if len(req.OrderIDs) == 0 && len(req.Items) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order_ids or items are required"})
+			return
+		}
+
+		plan, err := uc.PlanRoute(c.Request.Context(), req.OrderIDs, req.Items)
+		if err != nil {
+			pickPathError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
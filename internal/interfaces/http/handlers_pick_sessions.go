@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+type StartPickSessionRequest struct {
+	OrderID  string `json:"order_id" binding:"required"` // ID da FulfillmentOrder
+	DeviceID string `json:"device_id"`
+}
+
+type PickLocationScanRequest struct {
+	Location string `json:"location" binding:"required"`
+}
+
+type PickItemScanRequest struct {
+	Barcode string `json:"barcode"` // Código GS1 lido; alternativa a sku/batch
+	SKU     string `json:"sku"`
+	Batch   string `json:"batch"`
+}
+
+type PickQuantityRequest struct {
+	Quantity *int   `json:"quantity" binding:"required"` // Unidade base (EA)
+	Reason   string `json:"reason"`                      // Obrigatório em separação parcial
+}
+
+// pickSessionError rejeita leituras divergentes com o passo atual (400) e leituras fora de sequência (409)
+func pickSessionError(c *gin.Context, err error) {
+	var parseErr *gs1.ParseError
+	switch {
+	case errors.As(err, &parseErr),
+		errors.Is(err, fulfillment.ErrInvalidBarcode),
+		errors.Is(err, fulfillment.ErrNoProductCode),
+		errors.Is(err, fulfillment.ErrUnknownGTIN):
+		scanError(c, err)
+	case errors.Is(err, fulfillment.ErrWrongLocation),
+		errors.Is(err, fulfillment.ErrWrongSKU),
+		errors.Is(err, fulfillment.ErrWrongBatch),
+		errors.Is(err, fulfillment.ErrOverPick),
+		errors.Is(err, fulfillment.ErrInvalidPickQuantity),
+		errors.Is(err, fulfillment.ErrShortPickReasonRequired),
+		errors.Is(err, fulfillment.ErrEmptyItems):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrPickSessionNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrPickSessionExists),
+		errors.Is(err, fulfillment.ErrPickSessionFinished),
+		errors.Is(err, fulfillment.ErrPickSessionConflict),
+		errors.Is(err, fulfillment.ErrPickStepOutOfSequence),
+		errors.Is(err, fulfillment.ErrOrderBackordered),
+		errors.Is(err, fulfillment.ErrInvalidStateTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		taskError(c, err)
+	}
+}
+
+// handleStartPickSession responde POST /v1/pick_sessions (operador em X-Actor; retoma a sessão em andamento)
+func handleStartPickSession(uc *app.PickSessionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StartPickSessionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		session, err := uc.Start(c.Request.Context(), req.OrderID, req.DeviceID)
+		if err != nil {
+			pickSessionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, session)
+	}
+}
+
+// handleGetPickSession responde GET /v1/pick_sessions/:id
+func handleGetPickSession(uc *app.PickSessionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, err := uc.Get(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			pickSessionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+// handleScanPickLocation responde POST /v1/pick_sessions/:id/location
+func handleScanPickLocation(uc *app.PickSessionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PickLocationScanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		session, err := uc.ScanLocation(c.Request.Context(), c.Param("id"), req.Location)
+		if err != nil {
+			pickSessionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+// handleScanPickItem responde POST /v1/pick_sessions/:id/item
+func handleScanPickItem(uc *app.PickSessionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PickItemScanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Barcode == "" && req.SKU == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "barcode or sku is required"})
+			return
+		}
+
+		session, err := uc.ScanItem(c.Request.Context(), c.Param("id"), req.Barcode, req.SKU, req.Batch)
+		if err != nil {
+			pickSessionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+// handleConfirmPickQuantity responde POST /v1/pick_sessions/:id/quantity (a última linha conclui a separação)
+func handleConfirmPickQuantity(uc *app.PickSessionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PickQuantityRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		session, err := uc.ConfirmQuantity(c.Request.Context(), c.Param("id"), *req.Quantity, req.Reason)
+		if err != nil {
+			pickSessionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, session)
+	}
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type CutoffCalendarRequest struct {
+	Carrier  string                      `json:"carrier" binding:"required"`
+	Service  string                      `json:"service"` // Vazio = todos os serviços sem calendário próprio
+	Cutoffs  []fulfillment.CarrierCutoff `json:"cutoffs" binding:"required"`
+	Holidays []string                    `json:"holidays"` // AAAA-MM-DD
+	Timezone string                      `json:"timezone"` // IANA; vazio = UTC
+}
+
+type PlanWaveRequest struct {
+	Size int `json:"size" binding:"required"`
+}
+
+func priorityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidCutoffCalendar),
+		errors.Is(err, fulfillment.ErrInvalidWaveSize):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrCutoffCalendarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleSaveCutoffCalendar responde POST /v1/cutoff_calendars (cria ou substitui por transportadora e serviço)
+func handleSaveCutoffCalendar(uc *app.PrioritizationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CutoffCalendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		calendar, err := fulfillment.NewCutoffCalendar(req.Carrier, req.Service, req.Cutoffs, req.Holidays, req.Timezone)
+		if err != nil {
+			priorityError(c, err)
+			return
+		}
+		if err := uc.SaveCalendar(c.Request.Context(), calendar); err != nil {
+			priorityError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, calendar)
+	}
+}
+
+// handleListCutoffCalendars responde GET /v1/cutoff_calendars?carrier=
+func handleListCutoffCalendars(uc *app.PrioritizationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		calendars, err := uc.ListCalendars(c.Request.Context(), c.Query("carrier"))
+		if err != nil {
+			priorityError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"calendars": calendars})
+	}
+}
+
+// handleDeleteCutoffCalendar responde DELETE /v1/cutoff_calendars/:id
+func handleDeleteCutoffCalendar(uc *app.PrioritizationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := uc.DeleteCalendar(c.Request.Context(), c.Param("id")); err != nil {
+			priorityError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleRankPriorities responde GET /v1/priorities com as ordens abertas da mais para a menos urgente
+func handleRankPriorities(uc *app.PrioritizationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		priorities, err := uc.Rank(c.Request.Context(), time.Now())
+		if err != nil {
+			priorityError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"priorities": priorities})
+	}
+}
+
+// handleWillMissReport responde GET /v1/priorities/will_miss com as ordens que perderão o prazo
+func handleWillMissReport(uc *app.PrioritizationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		priorities, err := uc.WillMissReport(c.Request.Context(), time.Now())
+		if err != nil {
+			priorityError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"priorities": priorities})
+	}
+}
+
+// handlePlanWave responde POST /v1/waves liberando as ordens mais urgentes para separação
+func handlePlanWave(uc *app.PrioritizationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PlanWaveRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		wave, err := uc.PlanWave(c.Request.Context(), req.Size, time.Now())
+		if err != nil {
+			priorityError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, wave)
+	}
+}
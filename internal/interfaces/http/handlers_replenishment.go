@@ -0,0 +1,97 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type ReplenishmentRuleRequest struct {
+	SKU             string                        `json:"sku" binding:"required"`
+	PickLocation    string                        `json:"pick_location" binding:"required"`
+	ReserveLocation string                        `json:"reserve_location" binding:"required"`
+	Mode            fulfillment.ReplenishmentMode `json:"mode" binding:"required"`
+	Min             int                           `json:"min"`
+	Max             int                           `json:"max"`
+	CaseQuantity    int                           `json:"case_quantity"`
+	Enabled         *bool                         `json:"enabled"` // Padrão: true
+}
+
+func replenishmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidReplenishmentRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrReplenishmentRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineReplenishmentRule responde POST /v1/replenishment/rules (cria ou substitui por SKU e endereço)
+func handleDefineReplenishmentRule(uc *app.ReplenishmentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReplenishmentRuleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rule, err := fulfillment.NewReplenishmentRule(req.SKU, req.PickLocation, req.ReserveLocation, req.Mode, req.Min, req.Max, req.CaseQuantity)
+		if err != nil {
+			replenishmentError(c, err)
+			return
+		}
+		if req.Enabled != nil {
+			rule.Enabled = *req.Enabled
+		}
+
+		if err := uc.DefineRule(c.Request.Context(), rule); err != nil {
+			replenishmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, rule)
+	}
+}
+
+// handleListReplenishmentRules responde GET /v1/replenishment/rules?pick_location=
+func handleListReplenishmentRules(uc *app.ReplenishmentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rules, err := uc.ListRules(c.Request.Context(), c.Query("pick_location"))
+		if err != nil {
+			replenishmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"rules": rules})
+	}
+}
+
+// handleDeleteReplenishmentRule responde DELETE /v1/replenishment/rules/:id
+func handleDeleteReplenishmentRule(uc *app.ReplenishmentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := uc.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+			replenishmentError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleEvaluateReplenishment responde POST /v1/replenishment/evaluate avaliando todas as regras
+func handleEvaluateReplenishment(uc *app.ReplenishmentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report, err := uc.EvaluateAll(c.Request.Context())
+		if err != nil {
+			replenishmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func retryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, app.ErrUnknownEntityType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrShipmentNotFound),
+		errors.Is(err, fulfillment.ErrOrderNotFound),
+		errors.Is(err, fulfillment.ErrTransferNotFound),
+		errors.Is(err, fulfillment.ErrReturnNotFound),
+		errors.Is(err, fulfillment.ErrCycleCountNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrInvalidStateTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// failureFilter lê ?entity= (opcional) e ?since=RFC3339 (padrão: últimas 24h)
+func failureFilter(c *gin.Context) (string, time.Time, bool) {
+	var entityType string
+	if raw := c.Query("entity"); raw != "" {
+		var ok bool
+		if entityType, ok = timelineEntities[raw]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown entity " + raw})
+			return "", time.Time{}, false
+		}
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+			return "", time.Time{}, false
+		}
+		since = parsed
+	}
+	return entityType, since, true
+}
+
+// handleListFailures responde GET /v1/failures?entity=&since=
+func handleListFailures(uc *app.RetryFailedUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityType, since, ok := failureFilter(c)
+		if !ok {
+			return
+		}
+
+		operations, err := uc.ListFailed(c.Request.Context(), entityType, since)
+		if err != nil {
+			retryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"since": since, "failures": operations})
+	}
+}
+
+// handleRetry responde POST /v1/retry/:entity/:id
+func handleRetry(uc *app.RetryFailedUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityType := timelineEntities[c.Param("entity")]
+
+		if err := uc.Retry(c.Request.Context(), entityType, c.Param("id")); err != nil {
+			retryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "retried"})
+	}
+}
+
+// handleRetryAll responde POST /v1/retry?entity=&since= reexecutando todas as falhas do período
+func handleRetryAll(uc *app.RetryFailedUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("since") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since is required for bulk retry"})
+			return
+		}
+		entityType, since, ok := failureFilter(c)
+		if !ok {
+			return
+		}
+
+		report, err := uc.RetryAllSince(c.Request.Context(), entityType, since)
+		if err != nil {
+			retryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -30,7 +30,7 @@ func handleRegisterReturn(uc *app.RegisterReturnUseCase) gin.HandlerFunc {
 
 		returnOrder, err := uc.RegisterReturn(c.Request.Context(), req.OriginalOrderID, req.Reason, req.Location, req.Items)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			uomError(c, err)
 			return
 		}
 
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+type ScanRequest struct {
+	Barcode string `json:"barcode" binding:"required"` // Cadeia lida pelo coletor (FNC1 como \u001d) ou forma legível (01)...(10)...
+}
+
+// scanError detalha o AI e a posição do elemento inválido
+func scanError(c *gin.Context, err error) {
+	var parseErr *gs1.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "ai": parseErr.AI, "position": parseErr.Position, "value": parseErr.Value})
+	case errors.Is(err, fulfillment.ErrInvalidBarcode),
+		errors.Is(err, fulfillment.ErrNoProductCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrUnknownGTIN):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleParseBarcode responde POST /v1/scan/parse (elementos GS1, sem consultar o cadastro)
+func handleParseBarcode(uc *app.ScanUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ScanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		barcode, err := uc.Parse(req.Barcode)
+		if err != nil {
+			scanError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, barcode)
+	}
+}
+
+// handleResolveBarcode responde POST /v1/scan/resolve (linha de item do SKU pelo GTIN lido)
+func handleResolveBarcode(uc *app.ScanUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ScanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := uc.Resolve(c.Request.Context(), req.Barcode)
+		if err != nil {
+			scanError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func slottingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrSlottingRecommendationNotFound),
+		errors.Is(err, fulfillment.ErrLayoutNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrSlottingNotProposed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleAnalyzeSlotting responde POST /v1/slotting/analyze?since=RFC3339 (padrão: últimos 30 dias)
+func handleAnalyzeSlotting(uc *app.SlottingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since := time.Now().Add(-30 * 24 * time.Hour)
+		if raw := c.Query("since"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+				return
+			}
+			since = parsed
+		}
+
+		analysis, err := uc.Analyze(c.Request.Context(), since)
+		if err != nil {
+			slottingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, analysis)
+	}
+}
+
+// handleListSlottingRecommendations responde GET /v1/slotting/recommendations?status=
+func handleListSlottingRecommendations(uc *app.SlottingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recommendations, err := uc.ListRecommendations(c.Request.Context(), fulfillment.SlottingStatus(c.Query("status")))
+		if err != nil {
+			slottingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"recommendations": recommendations})
+	}
+}
+
+// handleDecideSlotting responde POST /v1/slotting/recommendations/:id/{approve,reject}
+func handleDecideSlotting(uc *app.SlottingUseCase, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, id := c.Request.Context(), c.Param("id")
+		var rec *fulfillment.SlottingRecommendation
+		var err error
+		if approve {
+			rec, err = uc.Approve(ctx, id)
+		} else {
+			rec, err = uc.Reject(ctx, id)
+		}
+		if err != nil {
+			slottingError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, rec)
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type transitionResponse struct {
+	Event  string             `json:"event"`
+	From   fulfillment.Status `json:"from"`
+	To     fulfillment.Status `json:"to"`
+	Guards []string           `json:"guards,omitempty"`
+}
+
+// handleStateMachine responde GET /v1/state_machines/:operation?format=json|mermaid|dot
+func handleStateMachine() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := fulfillment.OperationType(strings.ToUpper(c.Param("operation")))
+		machine, err := fulfillment.StateMachineFor(op)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		switch c.DefaultQuery("format", "json") {
+		case "mermaid":
+			c.String(http.StatusOK, machine.Mermaid())
+		case "dot":
+			c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(machine.DOT()))
+		case "json":
+			transitions := machine.Transitions()
+			response := make([]transitionResponse, 0, len(transitions))
+			for _, t := range transitions {
+				item := transitionResponse{Event: t.Event, From: t.From, To: t.To}
+				for _, g := range t.Guards {
+					item.Guards = append(item.Guards, g.Name)
+				}
+				response = append(response, item)
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"operation":   machine.Operation(),
+				"initial":     machine.Initial(),
+				"transitions": response,
+			})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, mermaid or dot"})
+		}
+	}
+}
+
+func handleTransitionMetrics(metrics *app.TransitionMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"transitions": metrics.Snapshot()})
+	}
+}
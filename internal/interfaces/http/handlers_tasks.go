@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type CreateTaskRequest struct {
+	Entity   string `json:"entity" binding:"required"` // inbound|orders|transfers|cycle_counts
+	EntityID string `json:"entity_id" binding:"required"`
+	Priority *int   `json:"priority"` // Padrão: prioridade da operação
+}
+
+type NextTaskRequest struct {
+	Location string                 `json:"location"` // Posição atual do operador
+	DeviceID string                 `json:"device_id"`
+	Types    []fulfillment.TaskType `json:"types"` // Vazio = todos os tipos
+}
+
+type TaskLifecycleRequest struct {
+	DeviceID     string             `json:"device_id"`
+	Reason       string             `json:"reason"`        // Abandono
+	CountedItems []fulfillment.Item `json:"counted_items"` // Conclusão de contagem
+}
+
+func taskError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrNoTaskAvailable):
+		c.JSON(http.StatusNoContent, nil)
+	case errors.Is(err, fulfillment.ErrTaskAssigneeRequired),
+		errors.Is(err, fulfillment.ErrInvalidTaskType),
+		errors.Is(err, fulfillment.ErrTaskEntityUnsupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrTaskAssignedToOther),
+		errors.Is(err, fulfillment.ErrTaskConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		retryError(c, err)
+	}
+}
+
+// bindOptionalJSON aceita corpo vazio nas chamadas de ciclo de vida
+func bindOptionalJSON(c *gin.Context, req interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// handleCreateTask responde POST /v1/tasks gerando a tarefa da operação
+func handleCreateTask(uc *app.WarehouseTaskUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateTaskRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		entityType, ok := timelineEntities[req.Entity]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown entity " + req.Entity})
+			return
+		}
+		priority := -1
+		if req.Priority != nil {
+			priority = *req.Priority
+		}
+
+		task, err := uc.Enqueue(c.Request.Context(), entityType, req.EntityID, priority)
+		if err != nil {
+			taskError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, task)
+	}
+}
+
+// handleListTasks responde GET /v1/tasks?type=
+func handleListTasks(uc *app.WarehouseTaskUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, err := uc.ListOpen(c.Request.Context())
+		if err != nil {
+			taskError(c, err)
+			return
+		}
+
+		if raw := c.Query("type"); raw != "" {
+			filtered := make([]*fulfillment.WarehouseTask, 0, len(tasks))
+			for _, task := range tasks {
+				if string(task.Type) == strings.ToUpper(raw) {
+					filtered = append(filtered, task)
+				}
+			}
+			tasks = filtered
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+	}
+}
+
+// handleNextTask responde POST /v1/tasks/next com a próxima melhor tarefa do operador (X-Actor)
+func handleNextTask(uc *app.WarehouseTaskUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req NextTaskRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		task, err := uc.NextTask(c.Request.Context(), req.Location, req.DeviceID, req.Types)
+		if err != nil {
+			taskError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// handleTaskLifecycle responde POST /v1/tasks/:id/{accept|start|complete|abandon}
+func handleTaskLifecycle(uc *app.WarehouseTaskUseCase, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req TaskLifecycleRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		ctx, id := c.Request.Context(), c.Param("id")
+		var task *fulfillment.WarehouseTask
+		var err error
+		switch action {
+		case "accept":
+			task, err = uc.Accept(ctx, id, req.DeviceID)
+		case "start":
+			task, err = uc.Start(ctx, id)
+		case "complete":
+			task, err = uc.Complete(ctx, id, req.CountedItems)
+		case "abandon":
+			task, err = uc.Abandon(ctx, id, req.Reason)
+		}
+		if err != nil {
+			taskError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, task)
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// timelineEntities mapeia o segmento da rota para o tipo de entidade do histórico
+var timelineEntities = map[string]string{
+	"inbound":      fulfillment.EntityInboundShipment,
+	"orders":       fulfillment.EntityFulfillmentOrder,
+	"transfers":    fulfillment.EntityTransferOrder,
+	"returns":      fulfillment.EntityReturnOrder,
+	"cycle_counts": fulfillment.EntityCycleCountTask,
+}
+
+func timelineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, app.ErrUnknownEntityType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, app.ErrTimelineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func handleTimeline(uc *app.StatusTimelineUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityType := timelineEntities[c.Param("entity")]
+
+		timeline, err := uc.GetTimeline(c.Request.Context(), entityType, c.Param("id"))
+		if err != nil {
+			timelineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, timeline)
+	}
+}
+
+// handleDwellStats responde GET /v1/metrics/dwell/:entity?since=RFC3339 (padrão: últimas 24h)
+func handleDwellStats(uc *app.StatusTimelineUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityType := timelineEntities[c.Param("entity")]
+
+		since := time.Now().Add(-24 * time.Hour)
+		if raw := c.Query("since"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+				return
+			}
+			since = parsed
+		}
+
+		stats, err := uc.DwellStats(c.Request.Context(), entityType, since)
+		if err != nil {
+			timelineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"since": since, "stats": stats})
+	}
+}
@@ -28,7 +28,7 @@ func handleCreateTransfer(uc *app.CompleteTransferUseCase) gin.HandlerFunc {
 
 		transfer, err := uc.CreateTransfer(c.Request.Context(), req.LocationFrom, req.LocationTo, req.Items)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			uomError(c, err)
 			return
 		}
 
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type UoMHierarchyRequest struct {
+	SKU       string                  `json:"sku" binding:"required"`
+	Levels    []fulfillment.PackLevel `json:"levels" binding:"required"` // Fatores em unidades base (EA)
+	UnitCube  float64                 `json:"unit_cube"`                 // m³ por unidade base
+	UnitValue float64                 `json:"unit_value"`                // Valor da unidade base
+	GTIN      string                  `json:"gtin"`                      // GTIN da unidade base (os das embalagens vão nos níveis)
+}
+
+func uomError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fulfillment.ErrInvalidUoMHierarchy),
+		errors.Is(err, fulfillment.ErrUnknownUoM),
+		errors.Is(err, fulfillment.ErrUoMNotWholeMultiple):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrUoMHierarchyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, fulfillment.ErrGTINInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// handleDefineUoMHierarchy responde POST /v1/uom (cria ou substitui a hierarquia do SKU)
+func handleDefineUoMHierarchy(uc *app.UnitsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UoMHierarchyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		hierarchy, err := fulfillment.NewUoMHierarchy(req.SKU, req.Levels)
+		if err != nil {
+			uomError(c, err)
+			return
+		}
+		hierarchy.UnitCube, hierarchy.UnitValue, hierarchy.GTIN = req.UnitCube, req.UnitValue, req.GTIN
+
+		if err := uc.DefineHierarchy(c.Request.Context(), hierarchy); err != nil {
+			uomError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, hierarchy)
+	}
+}
+
+// handleListUoMHierarchies responde GET /v1/uom
+func handleListUoMHierarchies(uc *app.UnitsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hierarchies, err := uc.ListHierarchies(c.Request.Context())
+		if err != nil {
+			uomError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"hierarchies": hierarchies})
+	}
+}
+
+// handleGetUoMHierarchy responde GET /v1/uom/:sku
+func handleGetUoMHierarchy(uc *app.UnitsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hierarchy, err := uc.GetHierarchy(c.Request.Context(), c.Param("sku"))
+		if err != nil {
+			uomError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, hierarchy)
+	}
+}
+
+// handleDeleteUoMHierarchy responde DELETE /v1/uom/:sku
+func handleDeleteUoMHierarchy(uc *app.UnitsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := uc.DeleteHierarchy(c.Request.Context(), c.Param("sku")); err != nil {
+			uomError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleConvertUoM responde GET /v1/uom/:sku/convert?quantity=&from=&to= (padrão: EA)
+func handleConvertUoM(uc *app.UnitsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		quantity, err := strconv.Atoi(c.Query("quantity"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "quantity must be an integer"})
+			return
+		}
+		from, to := c.DefaultQuery("from", fulfillment.UoMEach), c.DefaultQuery("to", fulfillment.UoMEach)
+
+		converted, err := uc.Convert(c.Request.Context(), c.Param("sku"), quantity, from, to)
+		if err != nil {
+			uomError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"sku": c.Param("sku"), "quantity": converted, "uom": to})
+	}
+}
@@ -3,17 +3,47 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
 )
 
+const (
+	headerCorrelationID = "X-Correlation-ID"
+	headerActor         = "X-Actor" // Usuário/serviço que executa a operação
+)
+
+// Deps reúne os casos de uso expostos pelas rotas HTTP
+type Deps struct {
+	ReceiveGoods      *app.ReceiveGoodsUseCase
+	ShipOrder         *app.ShipOrderUseCase
+	RegisterReturn    *app.RegisterReturnUseCase
+	CompleteTransfer  *app.CompleteTransferUseCase
+	OpenCycleCount    *app.OpenCycleCountUseCase
+	SubmitCycleCount  *app.SubmitCycleCountUseCase
+	QueryHistory      *app.QueryHistoryUseCase
+	StatusTimeline    *app.StatusTimelineUseCase
+	TransitionMetrics *app.TransitionMetrics
+	RetryFailed       *app.RetryFailedUseCase
+	Block             *app.BlockUseCase
+	WarehouseTasks    *app.WarehouseTaskUseCase
+	Replenishment     *app.ReplenishmentUseCase
+	Assembly          *app.AssemblyUseCase
+	CrossDock         *app.CrossDockUseCase
+	Dock              *app.DockSchedulingUseCase
+	Units             *app.UnitsUseCase
+	LPN               *app.LPNUseCase
+	PickPath          *app.PickPathUseCase
+	Slotting          *app.SlottingUseCase
+	CountPlanner      *app.CountPlannerUseCase
+	Manifest          *app.ManifestUseCase
+	Documents         *app.DocumentUseCase
+	Scan              *app.ScanUseCase
+	PickSession       *app.PickSessionUseCase
+	DeviceSync        *app.DeviceSyncUseCase
+	Prioritization    *app.PrioritizationUseCase
+}
+
 // Router configura as rotas HTTP do fulfillment-ops
-func Router(
-	receiveGoodsUC *app.ReceiveGoodsUseCase,
-	shipOrderUC *app.ShipOrderUseCase,
-	registerReturnUC *app.RegisterReturnUseCase,
-	completeTransferUC *app.CompleteTransferUseCase,
-	openCycleCountUC *app.OpenCycleCountUseCase,
-	submitCycleCountUC *app.SubmitCycleCountUseCase,
-) *gin.Engine {
+func Router(deps Deps) *gin.Engine {
 	r := gin.Default()
 
 	// Middleware de observabilidade
@@ -25,37 +55,235 @@ func Router(
 	// Inbound (Entrada)
 	inbound := v1.Group("/inbound")
 	{
-		inbound.POST("/start", handleStartInbound(receiveGoodsUC))
-		inbound.POST("/confirm", handleConfirmInbound(receiveGoodsUC))
+		inbound.POST("/start", handleStartInbound(deps.ReceiveGoods))
+		inbound.POST("/confirm", handleConfirmInbound(deps.ReceiveGoods))
 	}
 
 	// Outbound (Saída)
 	outbound := v1.Group("/outbound")
 	{
-		outbound.POST("/start_picking", handleStartPicking(shipOrderUC))
-		outbound.POST("/ship", handleShipOrder(shipOrderUC))
+		outbound.POST("/start_picking", handleStartPicking(deps.ShipOrder))
+		outbound.POST("/ship", handleShipOrder(deps.ShipOrder))
+		outbound.POST("/cancel", handleCancelOrder(deps.ShipOrder))
+		outbound.POST("/release_backorder", handleReleaseBackorder(deps.ShipOrder))
+		outbound.POST("/assemble_kits", handleAssembleForOrder(deps.Assembly))
+		outbound.POST("/shipments", handleHandOverShipment(deps.Manifest))
 	}
 
 	// Transferências
 	transfer := v1.Group("/transfer")
 	{
-		transfer.POST("/create", handleCreateTransfer(completeTransferUC))
-		transfer.POST("/complete", handleCompleteTransfer(completeTransferUC))
+		transfer.POST("/create", handleCreateTransfer(deps.CompleteTransfer))
+		transfer.POST("/complete", handleCompleteTransfer(deps.CompleteTransfer))
 	}
 
 	// Devoluções
 	returns := v1.Group("/returns")
 	{
-		returns.POST("/register", handleRegisterReturn(registerReturnUC))
-		returns.POST("/complete", handleCompleteReturn(registerReturnUC))
+		returns.POST("/register", handleRegisterReturn(deps.RegisterReturn))
+		returns.POST("/complete", handleCompleteReturn(deps.RegisterReturn))
 	}
 
 	// Contagem Cíclica
 	cycleCount := v1.Group("/cycle_count")
 	{
-		cycleCount.POST("/open", handleOpenCycleCount(openCycleCountUC))
-		cycleCount.POST("/submit", handleSubmitCycleCount(submitCycleCountUC))
+		cycleCount.POST("/open", handleOpenCycleCount(deps.OpenCycleCount))
+		cycleCount.POST("/submit", handleSubmitCycleCount(deps.SubmitCycleCount))
+		cycleCount.GET("/program", handleGetCountProgram(deps.CountPlanner))
+		cycleCount.POST("/program", handleDefineCountProgram(deps.CountPlanner))
+		cycleCount.GET("/classification", handleCountClassification(deps.CountPlanner))
+		cycleCount.POST("/plan", handlePlanCycleCounts(deps.CountPlanner))
+		cycleCount.GET("/triggers", handleListCountTriggers(deps.CountPlanner))
+	}
+
+	// Histórico de agregados (modo event-sourced), ?at=RFC3339
+	history := v1.Group("/history")
+	{
+		history.GET("/orders/:id", handleOrderHistory(deps.QueryHistory))
+		history.GET("/transfers/:id", handleTransferHistory(deps.QueryHistory))
+		history.GET("/returns/:id", handleReturnHistory(deps.QueryHistory))
+	}
+
+	// Linha do tempo de status e métricas de permanência
+	v1.GET("/timeline/:entity/:id", handleTimeline(deps.StatusTimeline))
+	v1.GET("/metrics/dwell/:entity", handleDwellStats(deps.StatusTimeline))
+
+	// Máquinas de estados (diagramas) e contadores de transição
+	v1.GET("/state_machines/:operation", handleStateMachine())
+	v1.GET("/metrics/transitions", handleTransitionMetrics(deps.TransitionMetrics))
+
+	// Operações FAILED: consulta e reexecução (individual ou em lote)
+	v1.GET("/failures", handleListFailures(deps.RetryFailed))
+	v1.POST("/retry", handleRetryAll(deps.RetryFailed))
+	v1.POST("/retry/:entity/:id", handleRetry(deps.RetryFailed))
+
+	// Bloqueio com código de motivo (operações bloqueadas saem das filas de trabalho)
+	v1.GET("/blocked", handleListBlocked(deps.Block))
+	v1.POST("/block/:entity/:id", handleBlock(deps.Block))
+	v1.POST("/unblock/:entity/:id", handleUnblock(deps.Block))
+	v1.POST("/release_hold/:entity/:id", handleReleaseHold(deps.Block))
+
+	// Fila unificada de tarefas de armazém (operador identificado por X-Actor)
+	tasks := v1.Group("/tasks")
+	{
+		tasks.GET("", handleListTasks(deps.WarehouseTasks))
+		tasks.POST("", handleCreateTask(deps.WarehouseTasks))
+		tasks.POST("/next", handleNextTask(deps.WarehouseTasks))
+		tasks.POST("/:id/accept", handleTaskLifecycle(deps.WarehouseTasks, "accept"))
+		tasks.POST("/:id/start", handleTaskLifecycle(deps.WarehouseTasks, "start"))
+		tasks.POST("/:id/complete", handleTaskLifecycle(deps.WarehouseTasks, "complete"))
+		tasks.POST("/:id/abandon", handleTaskLifecycle(deps.WarehouseTasks, "abandon"))
+	}
+
+	// Reabastecimento de endereços de picking
+	replenishment := v1.Group("/replenishment")
+	{
+		replenishment.GET("/rules", handleListReplenishmentRules(deps.Replenishment))
+		replenishment.POST("/rules", handleDefineReplenishmentRule(deps.Replenishment))
+		replenishment.DELETE("/rules/:id", handleDeleteReplenishmentRule(deps.Replenishment))
+		replenishment.POST("/evaluate", handleEvaluateReplenishment(deps.Replenishment))
+	}
+
+	// Kits (lista de materiais) e ordens de montagem/desmontagem
+	kits := v1.Group("/kits")
+	{
+		kits.GET("", handleListKits(deps.Assembly))
+		kits.POST("", handleDefineKit(deps.Assembly))
+		kits.GET("/:sku", handleGetKit(deps.Assembly))
+		kits.DELETE("/:sku", handleDeleteKit(deps.Assembly))
+	}
+	assembly := v1.Group("/assembly")
+	{
+		assembly.POST("", handleCreateAssemblyOrder(deps.Assembly))
+		assembly.GET("/:id", handleGetAssemblyOrder(deps.Assembly))
+		assembly.POST("/:id/execute", handleExecuteAssemblyOrder(deps.Assembly))
+	}
+
+	// Cross-dock de recebimentos para ordens aguardando os SKUs
+	crossDock := v1.Group("/crossdock")
+	{
+		crossDock.GET("/policies", handleListCrossDockPolicies(deps.CrossDock))
+		crossDock.POST("/policies", handleDefineCrossDockPolicy(deps.CrossDock))
+		crossDock.DELETE("/policies/:id", handleDeleteCrossDockPolicy(deps.CrossDock))
+		crossDock.GET("/allocations", handleListCrossDockAllocations(deps.CrossDock))
+	}
+
+	// Portas de doca e agendamentos de recebimento/expedição
+	docks := v1.Group("/docks")
+	{
+		docks.GET("", handleListDockDoors(deps.Dock))
+		docks.POST("", handleDefineDockDoor(deps.Dock))
+		docks.GET("/:id/slots", handleDockSlots(deps.Dock))
+	}
+	appointments := v1.Group("/appointments")
+	{
+		appointments.GET("", handleListAppointments(deps.Dock))
+		appointments.POST("", handleBookAppointment(deps.Dock))
+		appointments.GET("/:id", handleGetAppointment(deps.Dock))
+		appointments.POST("/:id/check_in", handleAppointmentLifecycle(deps.Dock, "check_in"))
+		appointments.POST("/:id/complete", handleAppointmentLifecycle(deps.Dock, "complete"))
+		appointments.POST("/:id/cancel", handleAppointmentLifecycle(deps.Dock, "cancel"))
+	}
+	v1.GET("/scorecards/suppliers", handleSupplierScorecards(deps.Dock))
+
+	// Unidades de medida: hierarquia de embalagens por SKU
+	uom := v1.Group("/uom")
+	{
+		uom.GET("", handleListUoMHierarchies(deps.Units))
+		uom.POST("", handleDefineUoMHierarchy(deps.Units))
+		uom.GET("/:sku", handleGetUoMHierarchy(deps.Units))
+		uom.DELETE("/:sku", handleDeleteUoMHierarchy(deps.Units))
+		uom.GET("/:sku/convert", handleConvertUoM(deps.Units))
+	}
+
+	// Contêineres (LPN): operações sobre o palete/caixa inteiro
+	lpns := v1.Group("/lpns")
+	{
+		lpns.POST("", handleCreateLPN(deps.LPN))
+		lpns.GET("/:code", handleLPNInquiry(deps.LPN))
+		lpns.POST("/:code/pack", handleLPNContents(deps.LPN, "pack"))
+		lpns.POST("/:code/unpack", handleLPNContents(deps.LPN, "unpack"))
+		lpns.POST("/:code/nest", handleNestLPN(deps.LPN))
+		lpns.POST("/:code/unnest", handleUnnestLPN(deps.LPN))
+		lpns.POST("/:code/receive", handleReceiveLPN(deps.LPN))
+		lpns.POST("/:code/move", handleMoveLPN(deps.LPN))
+		lpns.POST("/:code/ship", handleShipLPN(deps.LPN))
+	}
+
+	// Layout do armazém e roteamento da separação
+	layout := v1.Group("/layout")
+	{
+		layout.GET("", handleGetLayout(deps.PickPath))
+		layout.POST("", handleDefineLayout(deps.PickPath))
+		layout.POST("/bins", handleSaveLayoutBins(deps.PickPath))
+	}
+	v1.POST("/pick_routes", handlePlanPickRoute(deps.PickPath))
+
+	// Slotting por giro (curva ABC e zona dourada)
+	slotting := v1.Group("/slotting")
+	{
+		slotting.POST("/analyze", handleAnalyzeSlotting(deps.Slotting))
+		slotting.GET("/recommendations", handleListSlottingRecommendations(deps.Slotting))
+		slotting.POST("/recommendations/:id/approve", handleDecideSlotting(deps.Slotting, true))
+		slotting.POST("/recommendations/:id/reject", handleDecideSlotting(deps.Slotting, false))
+	}
+
+	// Manifestos diários por transportadora
+	manifests := v1.Group("/manifests")
+	{
+		manifests.GET("", handleListManifests(deps.Manifest))
+		manifests.GET("/:id", handleGetManifest(deps.Manifest))
+		manifests.POST("/:id/close", handleCloseManifest(deps.Manifest))
+		manifests.GET("/:id/document", handleManifestDocument(deps.Manifest))
+	}
+
+	// Documentos de expedição (romaneio, etiqueta de devolução, fatura comercial)
+	documents := v1.Group("/documents")
+	{
+		documents.GET("/templates", handleListDocumentTemplates(deps.Documents))
+		documents.POST("/templates", handleDefineDocumentTemplate(deps.Documents))
+		documents.DELETE("/templates/:id", handleDeleteDocumentTemplate(deps.Documents))
+		documents.POST("", handleRenderDocument(deps.Documents))
+		documents.GET("", handleListDocuments(deps.Documents))
+		documents.GET("/:id", handleDownloadDocument(deps.Documents))
+	}
+
+	// Leitura de códigos GS1 dos coletores
+	scan := v1.Group("/scan")
+	{
+		scan.POST("/parse", handleParseBarcode(deps.Scan))
+		scan.POST("/resolve", handleResolveBarcode(deps.Scan))
+	}
+
+	// Separação guiada no coletor: endereço, item e quantidade por linha (operador em X-Actor)
+	pickSessions := v1.Group("/pick_sessions")
+	{
+		pickSessions.POST("", handleStartPickSession(deps.PickSession))
+		pickSessions.GET("/:id", handleGetPickSession(deps.PickSession))
+		pickSessions.POST("/:id/location", handleScanPickLocation(deps.PickSession))
+		pickSessions.POST("/:id/item", handleScanPickItem(deps.PickSession))
+		pickSessions.POST("/:id/quantity", handleConfirmPickQuantity(deps.PickSession))
+	}
+
+	// Sincronização dos coletores offline e fila de revisão de conflitos do supervisor
+	sync := v1.Group("/sync")
+	{
+		sync.POST("", handlePushSync(deps.DeviceSync))
+		sync.GET("/conflicts", handleListSyncConflicts(deps.DeviceSync))
+		sync.GET("/conflicts/:id", handleGetSyncConflict(deps.DeviceSync))
+		sync.POST("/conflicts/:id/resolve", handleResolveSyncConflict(deps.DeviceSync))
+	}
+
+	// Calendários de corte das transportadoras, prioridade dinâmica das ordens e ondas
+	cutoffCalendars := v1.Group("/cutoff_calendars")
+	{
+		cutoffCalendars.POST("", handleSaveCutoffCalendar(deps.Prioritization))
+		cutoffCalendars.GET("", handleListCutoffCalendars(deps.Prioritization))
+		cutoffCalendars.DELETE("/:id", handleDeleteCutoffCalendar(deps.Prioritization))
 	}
+	v1.GET("/priorities", handleRankPriorities(deps.Prioritization))
+	v1.GET("/priorities/will_miss", handleWillMissReport(deps.Prioritization))
+	v1.POST("/waves", handlePlanWave(deps.Prioritization))
 
 	// Health check
 	r.GET("/health", handleHealth())
@@ -66,6 +294,11 @@ func Router(
 func observabilityMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// TODO: Implementar middleware de observabilidade (logs, métricas, trace)
+		// Propaga o ID de correlação para os casos de uso e adapters
+		ctx, correlationID := fulfillment.EnsureCorrelationID(fulfillment.WithCorrelationID(c.Request.Context(), c.GetHeader(headerCorrelationID)))
+		ctx = fulfillment.WithActor(fulfillment.WithSource(ctx, fulfillment.SourceHTTP), c.GetHeader(headerActor))
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(headerCorrelationID, correlationID)
 		c.Next()
 	}
 }
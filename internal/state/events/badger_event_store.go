@@ -0,0 +1,670 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/logger"
+)
+
+// Key layout of the Badger event store
+const (
+	badgerEventPrefix     = "es/evt/"  // es/evt/<aggregate>/<version>  -> badgerStoredEvent
+	badgerPositionPrefix  = "es/pos/"  // es/pos/<position>             -> event key
+	badgerAggregatePrefix = "es/agg/"  // es/agg/<aggregate>            -> AggregateInfo
+	badgerSnapshotPrefix  = "es/snap/" // es/snap/<aggregate>           -> badgerStoredSnapshot
+	badgerPositionSeqKey  = "es/seq/position"
+
+	badgerPollInterval = time.Second
+)
+
+// badgerStoredEvent keeps the payload as raw JSON plus its global position
+type badgerStoredEvent struct {
+	Event
+	Data     json.RawMessage `json:"data"`
+	Position uint64          `json:"position"`
+}
+
+type badgerStoredSnapshot struct {
+	Snapshot
+	Data json.RawMessage `json:"data"`
+}
+
+// BadgerEventStore is a durable single-node EventStore backed by BadgerDB.
+// Appends are serialized in-process, so global positions increase in append order.
+// Positions are leased from a badger.Sequence in blocks and are not gapless: a restart
+// discards the unused part of the lease and an aborted append consumes its position.
+type BadgerEventStore struct {
+	db        *badger.DB
+	seq       *badger.Sequence
+	config    *EventStoreConfig
+	logger    *zap.Logger
+	startedAt time.Time
+
+	appendMu sync.Mutex
+	mu       sync.Mutex
+	wake     chan struct{}
+	done     chan struct{}
+}
+
+var _ EventStore = (*BadgerEventStore)(nil)
+
+// NewBadgerEventStore opens (or creates) a Badger event store at config.StoragePath
+func NewBadgerEventStore(config *EventStoreConfig) (*BadgerEventStore, error) {
+	if config == nil {
+		config = DefaultEventStoreConfig()
+	}
+	cfg := *config
+	cfg.StoreType = "badger"
+
+	opts := badger.DefaultOptions(cfg.StoragePath)
+	opts.Logger = nil // Disable Badger's default logger
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open badger event store: %w", err)
+	}
+
+	seq, err := db.GetSequence([]byte(badgerPositionSeqKey), 100)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open position sequence: %w", err)
+	}
+
+	return &BadgerEventStore{
+		db:        db,
+		seq:       seq,
+		config:    &cfg,
+		logger:    logger.Get(),
+		startedAt: time.Now(),
+		wake:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Close releases the sequence, stops open streams and closes the database
+func (es *BadgerEventStore) Close() error {
+	es.mu.Lock()
+	select {
+	case <-es.done:
+		es.mu.Unlock()
+		return nil
+	default:
+	}
+	close(es.done)
+	es.mu.Unlock()
+
+	if err := es.seq.Release(); err != nil {
+		es.logger.Warn("Failed to release position sequence", zap.Error(err))
+	}
+	return es.db.Close()
+}
+
+// SaveEvent saves a single event
+func (es *BadgerEventStore) SaveEvent(ctx context.Context, event *Event) error {
+	return es.SaveEvents(ctx, []*Event{event})
+}
+
+// SaveEvents appends events atomically; each one must continue its aggregate stream
+func (es *BadgerEventStore) SaveEvents(ctx context.Context, events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, event := range events {
+		if err := ValidateEvent(event, es.config.MaxEventSize); err != nil {
+			return fmt.Errorf("event validation failed: %w", err)
+		}
+	}
+
+	es.appendMu.Lock()
+	defer es.appendMu.Unlock()
+
+	err := es.db.Update(func(txn *badger.Txn) error {
+		infos := make(map[string]*AggregateInfo)
+
+		for _, event := range events {
+			info, ok := infos[event.AggregateID]
+			if !ok {
+				var err error
+				info, err = getBadgerJSON[AggregateInfo](txn, badgerAggregatePrefix+event.AggregateID)
+				if err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+					return err
+				}
+				if info == nil {
+					ts := event.Timestamp
+					info = &AggregateInfo{AggregateID: event.AggregateID, AggregateType: event.AggregateType, FirstEvent: &ts}
+				}
+				infos[event.AggregateID] = info
+			}
+
+			if event.Version != info.Version+1 {
+				return fmt.Errorf("%w: aggregate %s expected version %d, got %d",
+					ErrVersionConflict, event.AggregateID, info.Version+1, event.Version)
+			}
+
+			next, err := es.seq.Next()
+			if err != nil {
+				return fmt.Errorf("failed to allocate position: %w", err)
+			}
+			position := next + 1 // positions start at 1
+
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				return fmt.Errorf("failed to marshal event data: %w", err)
+			}
+
+			stored := badgerStoredEvent{Event: *event, Data: data, Position: position}
+			value, err := json.Marshal(stored)
+			if err != nil {
+				return fmt.Errorf("failed to marshal event: %w", err)
+			}
+
+			eventKey := badgerEventKey(event.AggregateID, event.Version)
+			if err := txn.Set(eventKey, value); err != nil {
+				return err
+			}
+			if err := txn.Set(badgerPositionKey(position), eventKey); err != nil {
+				return err
+			}
+
+			ts := event.Timestamp
+			info.Version = event.Version
+			info.EventCount++
+			info.LastEvent = &ts
+			info.Size += int64(len(value))
+		}
+
+		for id, info := range infos {
+			if err := setBadgerJSON(txn, badgerAggregatePrefix+id, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, badger.ErrConflict) {
+			return fmt.Errorf("%w: %v", ErrVersionConflict, err)
+		}
+		return err
+	}
+
+	es.broadcast()
+	return nil
+}
+
+// GetEvents retrieves events for an aggregate within a version range
+func (es *BadgerEventStore) GetEvents(ctx context.Context, aggregateID string, fromVersion int64, toVersion int64) ([]*Event, error) {
+	result := []*Event{}
+	err := es.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(badgerEventPrefix + aggregateID + "/")
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek(badgerEventKey(aggregateID, fromVersion)); it.ValidForPrefix(prefix); it.Next() {
+			stored, err := decodeBadgerEvent(it.Item())
+			if err != nil {
+				return err
+			}
+			if stored.AggregateID != aggregateID {
+				continue
+			}
+			if stored.Version > toVersion {
+				break
+			}
+			result = append(result, stored.toEvent())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return result, nil
+}
+
+// GetAllEvents retrieves all retained events for an aggregate
+func (es *BadgerEventStore) GetAllEvents(ctx context.Context, aggregateID string) ([]*Event, error) {
+	return es.GetEvents(ctx, aggregateID, 1, 1<<62)
+}
+
+// GetEventsByType retrieves events by type in global order
+func (es *BadgerEventStore) GetEventsByType(ctx context.Context, eventType EventType, limit int) ([]*Event, error) {
+	return es.scanGlobal(0, limit, func(event *Event) bool { return event.Type == eventType })
+}
+
+// GetEventsByTimeRange retrieves events within a time range in global order
+func (es *BadgerEventStore) GetEventsByTimeRange(ctx context.Context, startTime, endTime time.Time, limit int) ([]*Event, error) {
+	return es.scanGlobal(0, limit, func(event *Event) bool {
+		return event.Timestamp.After(startTime) && event.Timestamp.Before(endTime)
+	})
+}
+
+// StreamEvents streams events for an aggregate from a version and follows new appends
+func (es *BadgerEventStore) StreamEvents(ctx context.Context, aggregateID string, fromVersion int64) (<-chan *Event, error) {
+	next := fromVersion
+	return es.stream(ctx, func() ([]*Event, error) {
+		batch, err := es.GetEvents(ctx, aggregateID, next, 1<<62)
+		if len(batch) > 0 {
+			next = batch[len(batch)-1].Version + 1
+		}
+		return batch, err
+	}), nil
+}
+
+// StreamAllEvents streams all events from a time in global order and follows new appends
+func (es *BadgerEventStore) StreamAllEvents(ctx context.Context, fromTime time.Time) (<-chan *Event, error) {
+	var after uint64
+	return es.stream(ctx, func() ([]*Event, error) {
+		var last uint64
+		batch, err := es.scanGlobalFrom(after, 500, &last, func(event *Event) bool {
+			return !event.Timestamp.Before(fromTime)
+		})
+		if last > after {
+			after = last
+		}
+		return batch, err
+	}), nil
+}
+
+// GetAggregateInfo returns information about an aggregate
+func (es *BadgerEventStore) GetAggregateInfo(ctx context.Context, aggregateID string) (*AggregateInfo, error) {
+	var info *AggregateInfo
+	err := es.db.View(func(txn *badger.Txn) error {
+		var err error
+		info, err = getBadgerJSON[AggregateInfo](txn, badgerAggregatePrefix+aggregateID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
+		}
+		return nil, fmt.Errorf("failed to read aggregate info: %w", err)
+	}
+	return info, nil
+}
+
+// GetEventStats returns event store statistics
+func (es *BadgerEventStore) GetEventStats(ctx context.Context) (*EventStoreStats, error) {
+	stats := &EventStoreStats{
+		EventsByType:    make(map[string]int64),
+		CompactionStats: &CompactionStats{},
+	}
+
+	err := es.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte(badgerEventPrefix)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			stored, err := decodeBadgerEvent(it.Item())
+			if err != nil {
+				return err
+			}
+			stats.TotalEvents++
+			stats.StoreSize += it.Item().ValueSize()
+			stats.EventsByType[string(stored.Type)]++
+			if stats.LastEvent == nil || stored.Timestamp.After(*stats.LastEvent) {
+				ts := stored.Timestamp
+				stats.LastEvent = &ts
+			}
+		}
+
+		stats.TotalAggregates = countBadgerKeys(txn, badgerAggregatePrefix)
+		stats.SnapshotCount = countBadgerKeys(txn, badgerSnapshotPrefix)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event stats: %w", err)
+	}
+
+	if stats.TotalEvents > 0 {
+		stats.AverageEventSize = float64(stats.StoreSize) / float64(stats.TotalEvents)
+	}
+	return stats, nil
+}
+
+// GetStoreInfo returns information about the event store
+func (es *BadgerEventStore) GetStoreInfo(ctx context.Context) (*EventStoreInfo, error) {
+	return &EventStoreInfo{
+		StoreType: es.config.StoreType,
+		Version:   "1.0.0",
+		NodeID:    es.config.NodeID,
+		StartTime: es.startedAt,
+		SupportedFeatures: []string{
+			"save_events",
+			"get_events",
+			"stream_events",
+			"create_snapshots",
+			"compaction",
+			"global_ordering",
+			"optimistic_concurrency",
+		},
+		Configuration: map[string]interface{}{
+			"storage_path":   es.config.StoragePath,
+			"max_event_size": es.config.MaxEventSize,
+			"event_ttl":      es.config.EventTTL,
+		},
+	}, nil
+}
+
+// CreateSnapshot stores (or replaces) the aggregate snapshot at the given version
+func (es *BadgerEventStore) CreateSnapshot(ctx context.Context, aggregateID string, version int64, snapshotData interface{}) error {
+	data, err := json.Marshal(snapshotData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	return es.db.Update(func(txn *badger.Txn) error {
+		info, err := getBadgerJSON[AggregateInfo](txn, badgerAggregatePrefix+aggregateID)
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
+			}
+			return err
+		}
+		if version <= 0 || version > info.Version {
+			return fmt.Errorf("version %d not found for aggregate %s", version, aggregateID)
+		}
+
+		now := time.Now().UTC()
+		snapshot := badgerStoredSnapshot{
+			Snapshot: Snapshot{
+				AggregateID:   aggregateID,
+				AggregateType: info.AggregateType,
+				Version:       version,
+				CreatedAt:     now,
+				CreatedBy:     es.config.NodeID,
+				Size:          int64(len(data)),
+			},
+			Data: data,
+		}
+		if err := setBadgerJSON(txn, badgerSnapshotPrefix+aggregateID, snapshot); err != nil {
+			return err
+		}
+
+		info.LastSnapshot = &now
+		return setBadgerJSON(txn, badgerAggregatePrefix+aggregateID, info)
+	})
+}
+
+// GetSnapshot retrieves the latest snapshot for an aggregate
+func (es *BadgerEventStore) GetSnapshot(ctx context.Context, aggregateID string) (*Snapshot, error) {
+	var stored *badgerStoredSnapshot
+	err := es.db.View(func(txn *badger.Txn) error {
+		var err error
+		stored, err = getBadgerJSON[badgerStoredSnapshot](txn, badgerSnapshotPrefix+aggregateID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, fmt.Errorf("snapshot not found for aggregate: %s", aggregateID)
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	snapshot := stored.Snapshot
+	snapshot.Data = stored.Data
+	return &snapshot, nil
+}
+
+// Health returns the health status of the event store
+func (es *BadgerEventStore) Health(ctx context.Context) (EventStoreHealth, error) {
+	health := EventStoreHealth{
+		Status:    "healthy",
+		StoreType: es.config.StoreType,
+		NodeID:    es.config.NodeID,
+		Timestamp: time.Now(),
+		Metadata:  map[string]interface{}{},
+	}
+
+	if es.db.IsClosed() {
+		msg := "database is closed"
+		health.Status = "unhealthy"
+		health.LastError = &msg
+		return health, errors.New(msg)
+	}
+
+	lsm, vlog := es.db.Size()
+	health.StoreSize = lsm + vlog
+	return health, nil
+}
+
+// CompactEvents removes events up to targetVersion. A snapshot covering
+// targetVersion is required so that the aggregate can still be rebuilt.
+func (es *BadgerEventStore) CompactEvents(ctx context.Context, aggregateID string, targetVersion int64) error {
+	snapshot, err := es.GetSnapshot(ctx, aggregateID)
+	if err != nil || snapshot.Version < targetVersion {
+		return fmt.Errorf("cannot compact aggregate %s to version %d: no snapshot covers it", aggregateID, targetVersion)
+	}
+
+	stream, err := es.GetEvents(ctx, aggregateID, 1, targetVersion)
+	if err != nil {
+		return err
+	}
+	return es.deleteEvents(stream)
+}
+
+// PruneEvents removes events at or before beforeTime; stream versions are preserved
+func (es *BadgerEventStore) PruneEvents(ctx context.Context, beforeTime time.Time) error {
+	stream, err := es.scanGlobal(0, 0, func(event *Event) bool { return !event.Timestamp.After(beforeTime) })
+	if err != nil {
+		return err
+	}
+	if err := es.deleteEvents(stream); err != nil {
+		return err
+	}
+
+	if len(stream) > 0 {
+		es.logger.Info("Events pruned",
+			zap.Time("before_time", beforeTime),
+			zap.Int("events_pruned", len(stream)))
+	}
+	return nil
+}
+
+// Private helper methods
+
+func (es *BadgerEventStore) deleteEvents(stream []*Event) error {
+	if len(stream) == 0 {
+		return nil
+	}
+
+	keys := make([][]byte, 0, len(stream)*2)
+	err := es.db.View(func(txn *badger.Txn) error {
+		for _, event := range stream {
+			key := badgerEventKey(event.AggregateID, event.Version)
+			item, err := txn.Get(key)
+			if err != nil {
+				continue
+			}
+			stored, err := decodeBadgerEvent(item)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key, badgerPositionKey(stored.Position))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to collect events: %w", err)
+	}
+
+	wb := es.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range keys {
+		if err := wb.Delete(key); err != nil {
+			return fmt.Errorf("failed to delete events: %w", err)
+		}
+	}
+	return wb.Flush()
+}
+
+func (es *BadgerEventStore) scanGlobal(after uint64, limit int, match func(*Event) bool) ([]*Event, error) {
+	var last uint64
+	return es.scanGlobalFrom(after, limit, &last, match)
+}
+
+// scanGlobalFrom walks the position index after "after", reporting the last position visited
+func (es *BadgerEventStore) scanGlobalFrom(after uint64, limit int, last *uint64, match func(*Event) bool) ([]*Event, error) {
+	result := []*Event{}
+	err := es.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte(badgerPositionPrefix)
+		for it.Seek(badgerPositionKey(after + 1)); it.ValidForPrefix(prefix); it.Next() {
+			eventKey, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			item, err := txn.Get(eventKey)
+			if err != nil {
+				continue // event removed by compaction
+			}
+			stored, err := decodeBadgerEvent(item)
+			if err != nil {
+				return err
+			}
+			*last = stored.Position
+
+			if match(&stored.Event) {
+				result = append(result, stored.toEvent())
+				if limit > 0 && len(result) >= limit {
+					return nil
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan events: %w", err)
+	}
+	return result, nil
+}
+
+// stream runs fetch until it is drained, then waits for an append or the poll interval
+func (es *BadgerEventStore) stream(ctx context.Context, fetch func() ([]*Event, error)) <-chan *Event {
+	out := make(chan *Event, es.config.StreamBufferSize)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(badgerPollInterval)
+		defer ticker.Stop()
+
+		for {
+			wake := es.waitChannel()
+
+			batch, err := fetch()
+			if err != nil {
+				es.logger.Warn("Event stream fetch failed", zap.Error(err))
+				batch = nil
+			}
+
+			for _, event := range batch {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				case <-es.done:
+					return
+				}
+			}
+
+			if len(batch) > 0 {
+				continue
+			}
+
+			select {
+			case <-wake:
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			case <-es.done:
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (es *BadgerEventStore) waitChannel() <-chan struct{} {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.wake
+}
+
+func (es *BadgerEventStore) broadcast() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	close(es.wake)
+	es.wake = make(chan struct{})
+}
+
+func (s *badgerStoredEvent) toEvent() *Event {
+	event := s.Event
+	event.Data = s.Data
+	return &event
+}
+
+func badgerEventKey(aggregateID string, version int64) []byte {
+	return []byte(fmt.Sprintf("%s%s/%020d", badgerEventPrefix, aggregateID, version))
+}
+
+func badgerPositionKey(position uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", badgerPositionPrefix, position))
+}
+
+func decodeBadgerEvent(item *badger.Item) (*badgerStoredEvent, error) {
+	var stored badgerStoredEvent
+	err := item.Value(func(val []byte) error {
+		return json.NewDecoder(bytes.NewReader(val)).Decode(&stored)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", item.Key(), err)
+	}
+	return &stored, nil
+}
+
+func getBadgerJSON[T any](txn *badger.Txn, key string) (*T, error) {
+	item, err := txn.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	value := new(T)
+	if err := item.Value(func(val []byte) error { return json.Unmarshal(val, value) }); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func setBadgerJSON(txn *badger.Txn, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	return txn.Set([]byte(key), data)
+}
+
+func countBadgerKeys(txn *badger.Txn, prefix string) int64 {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	var count int64
+	p := []byte(prefix)
+	for it.Seek(p); it.ValidForPrefix(p); it.Next() {
+		count++
+	}
+	return count
+}
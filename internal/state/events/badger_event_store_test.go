@@ -0,0 +1,244 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestBadgerEventStore(t *testing.T, path string) *BadgerEventStore {
+	t.Helper()
+	config := DefaultEventStoreConfig()
+	config.StoragePath = path
+	store, err := NewBadgerEventStore(config)
+	if err != nil {
+		t.Fatalf("NewBadgerEventStore() error = %v", err)
+	}
+	return store
+}
+
+func testEvent(aggregateID string, version int64, payload string) *Event {
+	return &Event{
+		ID:            fmt.Sprintf("%s-%d", aggregateID, version),
+		Type:          EventTypeUpdate,
+		AggregateID:   aggregateID,
+		AggregateType: "test",
+		Version:       version,
+		Data:          map[string]string{"value": payload},
+		Timestamp:     time.Now(),
+		NodeID:        "node-1",
+	}
+}
+
+func TestBadgerEventStore_PersistsAcrossRestart(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	store := newTestBadgerEventStore(t, path)
+	if err := store.SaveEvents(ctx, []*Event{testEvent("agg-1", 1, "a"), testEvent("agg-1", 2, "b")}); err != nil {
+		t.Fatalf("SaveEvents() error = %v", err)
+	}
+	if err := store.CreateSnapshot(ctx, "agg-1", 2, map[string]string{"value": "b"}); err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	store = newTestBadgerEventStore(t, path)
+	defer store.Close()
+
+	events, err := store.GetAllEvents(ctx, "agg-1")
+	if err != nil {
+		t.Fatalf("GetAllEvents() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	var data map[string]string
+	if err := json.Unmarshal(events[1].Data.(json.RawMessage), &data); err != nil || data["value"] != "b" {
+		t.Errorf("unexpected event data %v (err %v)", events[1].Data, err)
+	}
+
+	info, err := store.GetAggregateInfo(ctx, "agg-1")
+	if err != nil || info.Version != 2 {
+		t.Errorf("GetAggregateInfo() = %+v, %v; want version 2", info, err)
+	}
+
+	snapshot, err := store.GetSnapshot(ctx, "agg-1")
+	if err != nil || snapshot.Version != 2 {
+		t.Errorf("GetSnapshot() = %+v, %v; want version 2", snapshot, err)
+	}
+
+	// Continues the global order after the restart
+	if err := store.SaveEvent(ctx, testEvent("agg-2", 1, "c")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+	all, err := store.GetEventsByType(ctx, EventTypeUpdate, 0)
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+	if len(all) != 3 || all[2].AggregateID != "agg-2" {
+		t.Errorf("expected agg-2 last in global order, got %d events", len(all))
+	}
+}
+
+func TestBadgerEventStore_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+	store := newTestBadgerEventStore(t, t.TempDir())
+	defer store.Close()
+
+	if err := store.SaveEvent(ctx, testEvent("agg-1", 1, "a")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+
+	err := store.SaveEvent(ctx, testEvent("agg-1", 1, "stale"))
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("SaveEvent() error = %v, want ErrVersionConflict", err)
+	}
+
+	// A failing batch must not be partially applied
+	err = store.SaveEvents(ctx, []*Event{testEvent("agg-1", 2, "b"), testEvent("agg-1", 4, "gap")})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("SaveEvents() error = %v, want ErrVersionConflict", err)
+	}
+	events, _ := store.GetAllEvents(ctx, "agg-1")
+	if len(events) != 1 {
+		t.Errorf("expected 1 event after rejected batch, got %d", len(events))
+	}
+}
+
+func TestBadgerEventStore_StreamAllEvents(t *testing.T) {
+	store := newTestBadgerEventStore(t, t.TempDir())
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := store.SaveEvent(ctx, testEvent("agg-1", 1, "a")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+
+	stream, err := store.StreamAllEvents(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("StreamAllEvents() error = %v", err)
+	}
+
+	if event := <-stream; event == nil || event.ID != "agg-1-1" {
+		t.Fatalf("expected existing event first, got %+v", event)
+	}
+
+	if err := store.SaveEvent(ctx, testEvent("agg-2", 1, "b")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+
+	select {
+	case event := <-stream:
+		if event == nil || event.ID != "agg-2-1" {
+			t.Fatalf("expected appended event, got %+v", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for streamed event")
+	}
+}
+
+func TestBadgerEventStore_CompactRequiresSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := newTestBadgerEventStore(t, t.TempDir())
+	defer store.Close()
+
+	for v := int64(1); v <= 3; v++ {
+		if err := store.SaveEvent(ctx, testEvent("agg-1", v, "x")); err != nil {
+			t.Fatalf("SaveEvent() error = %v", err)
+		}
+	}
+
+	if err := store.CompactEvents(ctx, "agg-1", 2); err == nil {
+		t.Error("CompactEvents() should fail without a covering snapshot")
+	}
+
+	if err := store.CreateSnapshot(ctx, "agg-1", 2, map[string]string{"value": "x"}); err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if err := store.CompactEvents(ctx, "agg-1", 2); err != nil {
+		t.Fatalf("CompactEvents() error = %v", err)
+	}
+
+	events, _ := store.GetAllEvents(ctx, "agg-1")
+	if len(events) != 1 || events[0].Version != 3 {
+		t.Errorf("expected only version 3 after compaction, got %d events", len(events))
+	}
+
+	// The stream keeps its version so appends continue after compaction
+	if err := store.SaveEvent(ctx, testEvent("agg-1", 4, "y")); err != nil {
+		t.Errorf("SaveEvent() after compaction error = %v", err)
+	}
+}
+
+func TestBadgerEventStore_PruneKeepsStreamVersion(t *testing.T) {
+	ctx := context.Background()
+	store := newTestBadgerEventStore(t, t.TempDir())
+	defer store.Close()
+
+	old := testEvent("agg-1", 1, "a")
+	old.Timestamp = time.Now().Add(-time.Hour)
+	if err := store.SaveEvents(ctx, []*Event{old, testEvent("agg-1", 2, "b")}); err != nil {
+		t.Fatalf("SaveEvents() error = %v", err)
+	}
+
+	if err := store.PruneEvents(ctx, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("PruneEvents() error = %v", err)
+	}
+
+	events, _ := store.GetAllEvents(ctx, "agg-1")
+	if len(events) != 1 || events[0].Version != 2 {
+		t.Errorf("expected only version 2 after pruning, got %d events", len(events))
+	}
+	info, err := store.GetAggregateInfo(ctx, "agg-1")
+	if err != nil || info.Version != 2 || info.FirstEvent == nil || !info.FirstEvent.Equal(old.Timestamp) {
+		t.Errorf("GetAggregateInfo() = %+v, %v; want version 2 and the original first event", info, err)
+	}
+	if err := store.SaveEvent(ctx, testEvent("agg-1", 3, "c")); err != nil {
+		t.Errorf("SaveEvent() after pruning error = %v", err)
+	}
+
+	if _, err := store.GetAggregateInfo(ctx, "missing"); !errors.Is(err, ErrAggregateNotFound) {
+		t.Errorf("GetAggregateInfo() error = %v, want ErrAggregateNotFound", err)
+	}
+}
+
+func TestBadgerEventStore_PositionsIncreaseAcrossRestart(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	store := newTestBadgerEventStore(t, path)
+	if err := store.SaveEvent(ctx, testEvent("agg-1", 1, "a")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	store = newTestBadgerEventStore(t, path)
+	defer store.Close()
+	if err := store.SaveEvent(ctx, testEvent("agg-2", 1, "b")); err != nil {
+		t.Fatalf("SaveEvent() error = %v", err)
+	}
+
+	// The unused part of the leased sequence is lost on restart: positions increase but may skip
+	var last uint64
+	var positions []uint64
+	if _, err := store.scanGlobalFrom(0, 0, &last, func(event *Event) bool {
+		positions = append(positions, last)
+		return true
+	}); err != nil {
+		t.Fatalf("scanGlobalFrom() error = %v", err)
+	}
+	if len(positions) != 2 || positions[1] <= positions[0] {
+		t.Errorf("expected two increasing positions, got %v", positions)
+	}
+}
@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -23,6 +24,12 @@ const (
 	EventTypeCustom   EventType = "custom"
 )
 
+// ErrVersionConflict is returned when an append does not continue the aggregate stream
+var ErrVersionConflict = errors.New("event stream version conflict")
+
+// ErrAggregateNotFound is returned when the aggregate has no events in the store
+var ErrAggregateNotFound = errors.New("aggregate not found")
+
 // Event represents a domain event
 type Event struct {
 	ID            string                 `json:"id"`
@@ -255,14 +262,14 @@ func (es *InMemoryEventStore) SaveEvents(ctx context.Context, events []*Event) e
 		if len(aggregateEvents) > 0 {
 			lastEvent := aggregateEvents[len(aggregateEvents)-1]
 			if event.Version != lastEvent.Version+1 {
-				return fmt.Errorf("version gap detected for aggregate %s: expected %d, got %d",
-					event.AggregateID, lastEvent.Version+1, event.Version)
+				return fmt.Errorf("%w: version gap detected for aggregate %s: expected %d, got %d",
+					ErrVersionConflict, event.AggregateID, lastEvent.Version+1, event.Version)
 			}
 		} else {
 			// First event should have version 1
 			if event.Version != 1 {
-				return fmt.Errorf("first event version should be 1 for aggregate %s, got %d",
-					event.AggregateID, event.Version)
+				return fmt.Errorf("%w: first event version should be 1 for aggregate %s, got %d",
+					ErrVersionConflict, event.AggregateID, event.Version)
 			}
 		}
 
@@ -526,7 +533,7 @@ func (es *InMemoryEventStore) GetAggregateInfo(ctx context.Context, aggregateID
 
 	info, exists := es.metadata[aggregateID]
 	if !exists {
-		return nil, fmt.Errorf("aggregate not found: %s", aggregateID)
+		return nil, fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
 	}
 
 	// Return a copy
@@ -584,7 +591,7 @@ func (es *InMemoryEventStore) CreateSnapshot(ctx context.Context, aggregateID st
 	// Verify aggregate exists and version is valid
 	aggregateEvents, exists := es.events[aggregateID]
 	if !exists {
-		return fmt.Errorf("aggregate not found: %s", aggregateID)
+		return fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
 	}
 
 	// Find the event at the specified version
@@ -669,7 +676,7 @@ func (es *InMemoryEventStore) CompactEvents(ctx context.Context, aggregateID str
 
 	aggregateEvents, exists := es.events[aggregateID]
 	if !exists {
-		return fmt.Errorf("aggregate not found: %s", aggregateID)
+		return fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
 	}
 
 	// Count events to compact
@@ -740,6 +747,11 @@ func (es *InMemoryEventStore) PruneEvents(ctx context.Context, beforeTime time.T
 // Private helper methods
 
 func (es *InMemoryEventStore) validateEvent(event *Event) error {
+	return ValidateEvent(event, es.config.MaxEventSize)
+}
+
+// ValidateEvent checks the required fields and the encoded size of an event
+func ValidateEvent(event *Event, maxEventSize int64) error {
 	if event.ID == "" {
 		return fmt.Errorf("event ID is required")
 	}
@@ -762,7 +774,7 @@ func (es *InMemoryEventStore) validateEvent(event *Event) error {
 		return fmt.Errorf("failed to marshal event for validation: %w", err)
 	}
 
-	if int64(len(eventData)) > es.config.MaxEventSize {
+	if maxEventSize > 0 && int64(len(eventData)) > maxEventSize {
 		return fmt.Errorf("event size exceeds maximum allowed size")
 	}
 
@@ -310,7 +310,7 @@ func (ev *EventVersioningImpl) AddVersionHistory(ctx context.Context, aggregateI
 
 	versionInfo, exists := ev.versions[aggregateID]
 	if !exists {
-		return fmt.Errorf("aggregate not found: %s", aggregateID)
+		return fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
 	}
 
 	if !ev.config.EnableHistory {
@@ -0,0 +1,76 @@
+package gs1
+
+import "fmt"
+
+// Tipos de conteúdo dos identificadores de aplicação
+const (
+	kindText     = iota // Texto livre no conjunto de caracteres GS1 (82 caracteres)
+	kindNumber          // Somente dígitos
+	kindDate            // YYMMDD (DD 00 = último dia do mês)
+	kindDateTime        // YYMMDDHHMM
+	kindDecimal         // Dígitos com casas decimais indicadas pelo último dígito do AI
+)
+
+// aiSpec descreve um identificador de aplicação (AI)
+type aiSpec struct {
+	title      string
+	kind       int
+	length     int  // Tamanho exato (fixo) ou máximo (variável)
+	variable   bool // Termina em FNC1 ou no fim do código; os de tamanho fixo dispensam o FNC1
+	checkDigit bool // Último dígito é dígito verificador GS1 (módulo 10)
+	decimals   int
+}
+
+// aiTable contém os AIs usados nas operações de armazém
+var aiTable = map[string]aiSpec{
+	"00":   {title: "SSCC", kind: kindNumber, length: 18, checkDigit: true},
+	"01":   {title: "GTIN", kind: kindNumber, length: 14, checkDigit: true},
+	"02":   {title: "CONTENT", kind: kindNumber, length: 14, checkDigit: true},
+	"10":   {title: "BATCH/LOT", kind: kindText, length: 20, variable: true},
+	"11":   {title: "PROD DATE", kind: kindDate, length: 6},
+	"12":   {title: "DUE DATE", kind: kindDate, length: 6},
+	"13":   {title: "PACK DATE", kind: kindDate, length: 6},
+	"15":   {title: "BEST BEFORE", kind: kindDate, length: 6},
+	"16":   {title: "SELL BY", kind: kindDate, length: 6},
+	"17":   {title: "USE BY", kind: kindDate, length: 6},
+	"20":   {title: "VARIANT", kind: kindNumber, length: 2},
+	"21":   {title: "SERIAL", kind: kindText, length: 20, variable: true},
+	"22":   {title: "CPV", kind: kindText, length: 20, variable: true},
+	"30":   {title: "VAR. COUNT", kind: kindNumber, length: 8, variable: true},
+	"37":   {title: "COUNT", kind: kindNumber, length: 8, variable: true},
+	"240":  {title: "ADDITIONAL ID", kind: kindText, length: 30, variable: true},
+	"241":  {title: "CUST. PART No.", kind: kindText, length: 30, variable: true},
+	"250":  {title: "SECONDARY SERIAL", kind: kindText, length: 30, variable: true},
+	"400":  {title: "ORDER NUMBER", kind: kindText, length: 30, variable: true},
+	"401":  {title: "GINC", kind: kindText, length: 30, variable: true},
+	"402":  {title: "GSIN", kind: kindNumber, length: 17, checkDigit: true},
+	"410":  {title: "SHIP TO LOC", kind: kindNumber, length: 13, checkDigit: true},
+	"413":  {title: "SHIP FOR LOC", kind: kindNumber, length: 13, checkDigit: true},
+	"414":  {title: "LOC No.", kind: kindNumber, length: 13, checkDigit: true},
+	"420":  {title: "SHIP TO POST", kind: kindText, length: 20, variable: true},
+	"422":  {title: "ORIGIN", kind: kindNumber, length: 3},
+	"7003": {title: "EXPIRY TIME", kind: kindDateTime, length: 10},
+	"90":   {title: "INTERNAL", kind: kindText, length: 30, variable: true},
+}
+
+func init() {
+	// Medidas com a posição da vírgula no quarto dígito do AI (ex: 3102 = peso líquido com 2 casas)
+	for decimals := 0; decimals <= 5; decimals++ {
+		aiTable[fmt.Sprintf("310%d", decimals)] = aiSpec{title: "NET WEIGHT (kg)", kind: kindDecimal, length: 6, decimals: decimals}
+		aiTable[fmt.Sprintf("330%d", decimals)] = aiSpec{title: "GROSS WEIGHT (kg)", kind: kindDecimal, length: 6, decimals: decimals}
+	}
+	// 91 a 99: informações internas da empresa
+	for ai := 91; ai <= 99; ai++ {
+		aiTable[fmt.Sprint(ai)] = aiSpec{title: "INTERNAL", kind: kindText, length: 90, variable: true}
+	}
+}
+
+// lookupAI identifica o AI no início de data (2, 3 ou 4 dígitos)
+func lookupAI(data string) (string, aiSpec, bool) {
+	for size := 2; size <= 4 && size <= len(data); size++ {
+		if spec, ok := aiTable[data[:size]]; ok {
+			return data[:size], spec, true
+		}
+	}
+	return "", aiSpec{}, false
+}
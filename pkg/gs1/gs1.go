@@ -0,0 +1,326 @@
+// Package gs1 interpreta códigos de barras GS1-128 e GS1 DataMatrix com identificadores de aplicação (AIs).
+//
+// Aceita a cadeia transmitida pelo leitor, com o identificador de simbologia opcional (]C1, ]d2, ]Q3, ]e0)
+// e o FNC1 como separador de grupo (ASCII 29), e a forma legível com os AIs entre parênteses,
+// como em (01)07891234567895(17)251231(10)L001.
+package gs1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FNC1 é o separador de grupo (GS) transmitido pelos leitores no lugar do FNC1
+const FNC1 = '\x1d'
+
+var (
+	ErrEmpty            = errors.New("gs1: empty barcode")
+	ErrUnknownAI        = errors.New("gs1: unknown application identifier")
+	ErrInvalidLength    = errors.New("gs1: invalid element length")
+	ErrInvalidCharacter = errors.New("gs1: invalid character")
+	ErrCheckDigit       = errors.New("gs1: invalid check digit")
+	ErrInvalidDate      = errors.New("gs1: invalid date")
+	ErrDuplicateAI      = errors.New("gs1: application identifier repeated with a different value")
+)
+
+// ParseError indica o elemento inválido e sua posição na cadeia lida
+type ParseError struct {
+	AI       string
+	Position int
+	Value    string
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	if e.AI == "" {
+		return fmt.Sprintf("%v at position %d: %q", e.Err, e.Position, e.Value)
+	}
+	return fmt.Sprintf("%v: (%s) at position %d: %q", e.Err, e.AI, e.Position, e.Value)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Element é um par AI e valor do código
+type Element struct {
+	AI    string `json:"ai"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Barcode é o código interpretado, com os campos usados nas operações já convertidos
+type Barcode struct {
+	Elements       []Element  `json:"elements"`
+	GTIN           string     `json:"gtin,omitempty"` // AI 01, ou AI 02 (conteúdo de uma unidade logística)
+	SSCC           string     `json:"sscc,omitempty"`
+	Batch          string     `json:"batch,omitempty"`
+	Serial         string     `json:"serial,omitempty"`
+	Count          int        `json:"count,omitempty"` // AI 30 ou 37
+	ProductionDate *time.Time `json:"production_date,omitempty"`
+	BestBefore     *time.Time `json:"best_before,omitempty"`
+	Expiry         *time.Time `json:"expiry,omitempty"` // AI 17 ou 7003
+	NetWeight      float64    `json:"net_weight,omitempty"`
+}
+
+// Value retorna o valor do AI no código
+func (b *Barcode) Value(ai string) (string, bool) {
+	for _, element := range b.Elements {
+		if element.AI == ai {
+			return element.Value, true
+		}
+	}
+	return "", false
+}
+
+// Parse interpreta o código; datas com ano de dois dígitos são resolvidas em relação ao instante atual
+func Parse(raw string) (*Barcode, error) {
+	return ParseAt(raw, time.Now())
+}
+
+// ParseAt interpreta o código resolvendo o século das datas em relação a now
+func ParseAt(raw string, now time.Time) (*Barcode, error) {
+	data := strings.TrimSpace(raw)
+	if len(data) >= 3 && data[0] == ']' {
+		data = data[3:]
+	}
+	data = strings.TrimLeft(data, string(FNC1))
+	if data == "" {
+		return nil, ErrEmpty
+	}
+
+	barcode := &Barcode{}
+	add := func(ai string, spec aiSpec, value string, position int) error {
+		if err := barcode.add(ai, spec, value, now); err != nil {
+			return &ParseError{AI: ai, Position: position, Value: value, Err: err}
+		}
+		return nil
+	}
+
+	if data[0] == '(' {
+		if err := parseBracketed(data, add); err != nil {
+			return nil, err
+		}
+		return barcode, nil
+	}
+
+	for pos := 0; pos < len(data); {
+		if data[pos] == FNC1 {
+			pos++
+			continue
+		}
+		ai, spec, ok := lookupAI(data[pos:])
+		if !ok {
+			return nil, &ParseError{Position: pos, Value: data[pos:min(pos+4, len(data))], Err: ErrUnknownAI}
+		}
+		start := pos + len(ai)
+		end := start + spec.length
+		if spec.variable {
+			end = len(data)
+			if idx := strings.IndexByte(data[start:], FNC1); idx >= 0 {
+				end = start + idx
+			}
+		} else if end > len(data) || strings.IndexByte(data[start:end], FNC1) >= 0 {
+			end = len(data)
+			if idx := strings.IndexByte(data[start:], FNC1); idx >= 0 {
+				end = start + idx
+			}
+		}
+		if err := add(ai, spec, data[start:end], start); err != nil {
+			return nil, err
+		}
+		pos = end
+	}
+	return barcode, nil
+}
+
+// parseBracketed interpreta a forma legível, com cada AI entre parênteses
+func parseBracketed(data string, add func(string, aiSpec, string, int) error) error {
+	for pos := 0; pos < len(data); {
+		closing := strings.IndexByte(data[pos:], ')')
+		if data[pos] != '(' || closing < 0 {
+			return &ParseError{Position: pos, Value: data[pos:], Err: ErrInvalidCharacter}
+		}
+		ai := data[pos+1 : pos+closing]
+		spec, ok := aiTable[ai]
+		if !ok {
+			return &ParseError{Position: pos + 1, Value: ai, Err: ErrUnknownAI}
+		}
+		start := pos + closing + 1
+		end := len(data)
+		if idx := strings.IndexByte(data[start:], '('); idx >= 0 {
+			end = start + idx
+		}
+		if err := add(ai, spec, data[start:end], start); err != nil {
+			return err
+		}
+		pos = end
+	}
+	return nil
+}
+
+// add valida o valor do elemento e preenche o campo correspondente
+func (b *Barcode) add(ai string, spec aiSpec, value string, now time.Time) error {
+	if len(value) == 0 || len(value) > spec.length || (!spec.variable && len(value) != spec.length) {
+		if spec.variable {
+			return fmt.Errorf("%w: expected 1 to %d characters, got %d", ErrInvalidLength, spec.length, len(value))
+		}
+		return fmt.Errorf("%w: expected %d characters, got %d", ErrInvalidLength, spec.length, len(value))
+	}
+	if spec.kind == kindText {
+		for idx := 0; idx < len(value); idx++ {
+			if !isGS1Character(value[idx]) {
+				return fmt.Errorf("%w: %q", ErrInvalidCharacter, value[idx])
+			}
+		}
+	} else if !isDigits(value) {
+		return fmt.Errorf("%w: expected digits only", ErrInvalidCharacter)
+	}
+	if spec.checkDigit && !ValidCheckDigit(value) {
+		return ErrCheckDigit
+	}
+
+	if current, ok := b.Value(ai); ok {
+		if current != value {
+			return fmt.Errorf("%w: %q", ErrDuplicateAI, current)
+		}
+		return nil
+	}
+
+	switch spec.kind {
+	case kindDate, kindDateTime:
+		at, err := parseDate(value, now)
+		if err != nil {
+			return err
+		}
+		switch ai {
+		case "11":
+			b.ProductionDate = &at
+		case "15":
+			b.BestBefore = &at
+		case "17", "7003":
+			if b.Expiry == nil || ai == "7003" {
+				b.Expiry = &at
+			}
+		}
+	case kindDecimal:
+		number, _ := strconv.Atoi(value)
+		if strings.HasPrefix(ai, "310") {
+			b.NetWeight = float64(number) / math.Pow10(spec.decimals)
+		}
+	}
+	switch ai {
+	case "00":
+		b.SSCC = value
+	case "01":
+		b.GTIN = value
+	case "02":
+		if b.GTIN == "" {
+			b.GTIN = value
+		}
+	case "10":
+		b.Batch = value
+	case "21":
+		b.Serial = value
+	case "30", "37":
+		b.Count, _ = strconv.Atoi(value)
+	}
+	b.Elements = append(b.Elements, Element{AI: ai, Title: spec.title, Value: value})
+	return nil
+}
+
+// parseDate converte YYMMDD ou YYMMDDHHMM (UTC). DD 00 é o último dia do mês e o século segue a
+// janela deslizante das GS1 General Specifications: até 49 anos à frente ou 50 anos para trás de now.
+func parseDate(value string, now time.Time) (time.Time, error) {
+	field := func(from int) int {
+		number, _ := strconv.Atoi(value[from : from+2])
+		return number
+	}
+	yy, month, day := field(0), field(2), field(4)
+	year := now.Year()/100*100 + yy
+	switch diff := yy - now.Year()%100; {
+	case diff >= 51:
+		year -= 100
+	case diff <= -50:
+		year += 100
+	}
+	if month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("%w: month %02d", ErrInvalidDate, month)
+	}
+	lastDay := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day == 0 {
+		day = lastDay
+	}
+	if day > lastDay {
+		return time.Time{}, fmt.Errorf("%w: day %02d of %04d-%02d", ErrInvalidDate, day, year, month)
+	}
+	hour, minute := 0, 0
+	if len(value) == 10 {
+		hour, minute = field(6), field(8)
+		if hour > 23 || minute > 59 {
+			return time.Time{}, fmt.Errorf("%w: time %02d:%02d", ErrInvalidDate, hour, minute)
+		}
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.UTC), nil
+}
+
+// CheckDigit calcula o dígito verificador GS1 (módulo 10) dos dígitos informados
+func CheckDigit(digits string) (byte, error) {
+	if digits == "" || !isDigits(digits) {
+		return 0, fmt.Errorf("%w: expected digits only", ErrInvalidCharacter)
+	}
+	sum := 0
+	for idx := len(digits) - 1; idx >= 0; idx-- {
+		weight := 1
+		if (len(digits)-1-idx)%2 == 0 {
+			weight = 3
+		}
+		sum += int(digits[idx]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// ValidCheckDigit verifica o último dígito de um número GS1 (GTIN, SSCC, GLN)
+func ValidCheckDigit(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+	digit, err := CheckDigit(number[:len(number)-1])
+	return err == nil && digit == number[len(number)-1]
+}
+
+// NormalizeGTIN valida um GTIN-8, 12, 13 ou 14 e o completa com zeros à esquerda até 14 dígitos
+func NormalizeGTIN(gtin string) (string, error) {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("%w: gtin must have 8, 12, 13 or 14 digits, got %d", ErrInvalidLength, len(gtin))
+	}
+	if !isDigits(gtin) {
+		return "", fmt.Errorf("%w: gtin must have digits only", ErrInvalidCharacter)
+	}
+	if !ValidCheckDigit(gtin) {
+		return "", fmt.Errorf("%w: gtin %s", ErrCheckDigit, gtin)
+	}
+	return strings.Repeat("0", 14-len(gtin)) + gtin, nil
+}
+
+func isDigits(value string) bool {
+	for idx := 0; idx < len(value); idx++ {
+		if value[idx] < '0' || value[idx] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isGS1Character verifica o conjunto de 82 caracteres permitido nos AIs alfanuméricos
+func isGS1Character(c byte) bool {
+	switch {
+	case c >= '0' && c <= '9', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		return true
+	}
+	return strings.IndexByte(`!"%&'()*+,-./:;<=>?_`, c) >= 0
+}
@@ -0,0 +1,112 @@
+package gs1
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var reference = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+func TestParseAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		gtin   string
+		batch  string
+		serial string
+		expiry string
+		count  int
+	}{
+		{"gs1-128 with fnc1", "]C10104006381333931" + "17270331" + "10L001\x1d" + "21SN42", "04006381333931", "L001", "SN42", "2027-03-31", 0},
+		{"datamatrix fixed before variable", "]d2\x1d0104006381333931" + "10ABC-1/2\x1d" + "17270300", "04006381333931", "ABC-1/2", "", "2027-03-31", 0},
+		{"human readable", "(02)14006381333938(37)12(10)L9(7003)2701151230", "14006381333938", "L9", "", "2027-01-15", 12},
+		{"century window", "010400638133393117750101", "04006381333931", "", "", "2075-01-01", 0},
+		{"previous century", "010400638133393117800101", "04006381333931", "", "", "1980-01-01", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barcode, err := ParseAt(tt.raw, reference)
+			if err != nil {
+				t.Fatalf("ParseAt() error = %v", err)
+			}
+			if barcode.GTIN != tt.gtin || barcode.Batch != tt.batch || barcode.Serial != tt.serial || barcode.Count != tt.count {
+				t.Errorf("ParseAt() = %+v", barcode)
+			}
+			if barcode.Expiry == nil || barcode.Expiry.Format(time.DateOnly) != tt.expiry {
+				t.Errorf("Expiry = %v, want %s", barcode.Expiry, tt.expiry)
+			}
+		})
+	}
+}
+
+func TestParseAtMeasuresAndSSCC(t *testing.T) {
+	barcode, err := ParseAt("00106141411234567897"+"3102001250"+"400PO-77", reference)
+	if err != nil {
+		t.Fatalf("ParseAt() error = %v", err)
+	}
+	if barcode.SSCC != "106141411234567897" || barcode.NetWeight != 12.5 {
+		t.Errorf("ParseAt() = %+v", barcode)
+	}
+	if value, ok := barcode.Value("400"); !ok || value != "PO-77" {
+		t.Errorf("Value(400) = %q, %v", value, ok)
+	}
+	if len(barcode.Elements) != 3 || barcode.Elements[1].Title != "NET WEIGHT (kg)" {
+		t.Errorf("Elements = %+v", barcode.Elements)
+	}
+}
+
+func TestParseAtErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		want     error
+		ai       string
+		position int
+	}{
+		{"empty", "  ", ErrEmpty, "", 0},
+		{"unknown ai", "0104006381333931" + "99X\x1d" + "88123", ErrUnknownAI, "", 20},
+		{"check digit", "0104006381333932", ErrCheckDigit, "01", 2},
+		{"short fixed", "01040063813339\x1d10L1", ErrInvalidLength, "01", 2},
+		{"batch too long", "10ABCDEFGHIJKLMNOPQRSTU", ErrInvalidLength, "10", 2},
+		{"invalid month", "0104006381333931" + "17271301", ErrInvalidDate, "17", 18},
+		{"invalid day", "17270230", ErrInvalidDate, "17", 2},
+		{"non numeric count", "37AB", ErrInvalidCharacter, "37", 2},
+		{"invalid character", "10L#1", ErrInvalidCharacter, "10", 2},
+		{"conflicting repeat", "(10)L1(10)L2", ErrDuplicateAI, "10", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAt(tt.raw, reference)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ParseAt() error = %v, want %v", err, tt.want)
+			}
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) && (parseErr.AI != tt.ai || parseErr.Position != tt.position) {
+				t.Errorf("ParseError = %+v, want ai %q at %d", parseErr, tt.ai, tt.position)
+			}
+		})
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		gtin    string
+		want    string
+		wantErr error
+	}{
+		{"4006381333931", "04006381333931", nil},
+		{"14006381333938", "14006381333938", nil},
+		{"4006381333932", "", ErrCheckDigit},
+		{"400638133", "", ErrInvalidLength},
+		{"400638133393A", "", ErrInvalidCharacter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.gtin, func(t *testing.T) {
+			got, err := NormalizeGTIN(tt.gtin)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("NormalizeGTIN() = %q, %v; want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestBlock_ReasonValidation(t *testing.T) {
+	order, err := fulfillment.NewFulfillmentOrder("ORD-1", "CUST-1", "Rua A", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}}, 1)
+	if err != nil {
+		t.Fatalf("NewFulfillmentOrder() error = %v", err)
+	}
+
+	if err := order.Block("LUNCH_BREAK", "", "operador"); !errors.Is(err, fulfillment.ErrInvalidBlockReason) {
+		t.Errorf("Block() error = %v, want ErrInvalidBlockReason", err)
+	}
+	if err := order.Block(fulfillment.BlockOther, "", "operador"); !errors.Is(err, fulfillment.ErrBlockNoteRequired) {
+		t.Errorf("Block() error = %v, want ErrBlockNoteRequired", err)
+	}
+	if order.Status != fulfillment.StatusPending || order.Blocked != nil {
+		t.Fatalf("rejected block must not change the order, got %s", order.Status)
+	}
+}
+
+func TestBlock_UnblockReturnsToPreviousStatus(t *testing.T) {
+	order, err := fulfillment.NewFulfillmentOrder("ORD-2", "CUST-1", "Rua A", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}}, 1)
+	if err != nil {
+		t.Fatalf("NewFulfillmentOrder() error = %v", err)
+	}
+	if err := order.StartPicking(); err != nil {
+		t.Fatalf("StartPicking() error = %v", err)
+	}
+
+	if err := order.Block(fulfillment.BlockMissingStock, "", "operador"); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	if order.Status != fulfillment.StatusBlocked || order.Blocked.PreviousStatus != fulfillment.StatusInProgress {
+		t.Fatalf("unexpected block state: %s, %+v", order.Status, order.Blocked)
+	}
+	if order.Status.IsWorkable() {
+		t.Error("blocked orders must not be workable")
+	}
+
+	if err := order.Unblock(); err != nil {
+		t.Fatalf("Unblock() error = %v", err)
+	}
+	if order.Status != fulfillment.StatusInProgress || order.Blocked != nil {
+		t.Errorf("Unblock() status = %s, blocked = %+v", order.Status, order.Blocked)
+	}
+
+	if err := order.Ship(); err != nil {
+		t.Fatalf("Ship() error = %v", err)
+	}
+	if err := order.Block(fulfillment.BlockQualityHold, "", "operador"); !errors.Is(err, fulfillment.ErrInvalidStateTransition) {
+		t.Errorf("Block() on completed order error = %v, want ErrInvalidStateTransition", err)
+	}
+}
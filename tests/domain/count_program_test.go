@@ -0,0 +1,167 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// countHistory: SKU-001 concentra as separações, SKU-003 é lento mas de alto valor
+func countHistory() []*fulfillment.FulfillmentOrder {
+	var orders []*fulfillment.FulfillmentOrder
+	add := func(sku, location string, picks int) {
+		for i := 0; i < picks; i++ {
+			orders = append(orders, &fulfillment.FulfillmentOrder{Items: []fulfillment.Item{{SKU: sku, Quantity: 1, Location: location}}})
+		}
+	}
+	add("SKU-001", "A-01", 17)
+	add("SKU-002", "B-01", 2)
+	add("SKU-003", "C-01", 1)
+	add("SKU-004", "D-01", 1)
+	return orders
+}
+
+func TestClassifyCountLocations_VelocityAndValue(t *testing.T) {
+	candidates := fulfillment.ClassifyCountLocations(countHistory(), map[string]float64{"SKU-001": 1, "SKU-003": 1000}, 90)
+
+	want := map[string]fulfillment.VelocityClass{
+		"A-01": fulfillment.VelocityA,
+		"B-01": fulfillment.VelocityB,
+		"C-01": fulfillment.VelocityA, // Giro C, valor A
+		"D-01": fulfillment.VelocityC,
+	}
+	if len(candidates) != len(want) {
+		t.Fatalf("ClassifyCountLocations() returned %d locations, want %d", len(candidates), len(want))
+	}
+	for _, candidate := range candidates {
+		if candidate.Class != want[candidate.Location] {
+			t.Errorf("location %s class = %s, want %s", candidate.Location, candidate.Class, want[candidate.Location])
+		}
+	}
+	if candidates[0].Picks != 17 || candidates[2].Value != 1000 {
+		t.Errorf("unexpected picks/value: %+v", candidates)
+	}
+}
+
+func TestCountProgram_PlanBalancesDueLocations(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	ago := func(days int) *time.Time {
+		at := now.AddDate(0, 0, -days)
+		return &at
+	}
+	candidates := fulfillment.ClassifyCountLocations(countHistory(), map[string]float64{"SKU-003": 1000}, 90)
+	program := fulfillment.DefaultCountProgram()
+
+	tests := []struct {
+		name     string
+		capacity int
+		history  []fulfillment.LocationCountHistory
+		triggers []*fulfillment.CountTrigger
+		want     []string
+	}{
+		{
+			name: "never counted locations first, best class and value first",
+			want: []string{"C-01"},
+		},
+		{
+			name:     "capacity limits scheduled counts",
+			capacity: 3,
+			want:     []string{"C-01", "A-01", "B-01"},
+		},
+		{
+			name:     "most overdue first, open and not due locations skipped",
+			capacity: 3,
+			history: []fulfillment.LocationCountHistory{
+				{Location: "A-01", LastCountedAt: ago(40)},
+				{Location: "B-01", LastCountedAt: ago(10)},
+				{Location: "C-01", Open: true},
+				{Location: "D-01", LastCountedAt: ago(400)},
+			},
+			want: []string{"D-01", "A-01"},
+		},
+		{
+			name: "counts opened today consume the quota",
+			history: []fulfillment.LocationCountHistory{
+				{Location: "Z-99", LastOpenedAt: &now, Open: true},
+			},
+			want: nil,
+		},
+		{
+			name: "triggers are planned beyond the quota",
+			triggers: []*fulfillment.CountTrigger{
+				{ID: "t-1", Location: "B-01", SKU: "SKU-002", Reason: fulfillment.CountZeroStockPick},
+				{ID: "t-2", Location: "B-01", SKU: "SKU-009", Reason: fulfillment.CountNegativeBalance},
+			},
+			want: []string{"B-01", "C-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program.DailyCapacity = tt.capacity
+			plan := program.Plan(candidates, tt.history, tt.triggers, now)
+			var got []string
+			for _, entry := range plan.Entries {
+				got = append(got, entry.Location)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Plan() locations = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Plan() locations = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	plan := program.Plan(candidates, nil, []*fulfillment.CountTrigger{
+		{ID: "t-1", Location: "B-01", SKU: "SKU-002", Reason: fulfillment.CountZeroStockPick},
+		{ID: "t-2", Location: "B-01", SKU: "SKU-009", Reason: fulfillment.CountNegativeBalance},
+	}, now)
+	merged := plan.Entries[0]
+	if merged.Reason != fulfillment.CountNegativeBalance || len(merged.SKUs) != 2 || len(merged.TriggerIDs) != 2 {
+		t.Errorf("merged trigger entry = %+v, want NEGATIVE_BALANCE with both SKUs and triggers", merged)
+	}
+}
+
+func TestCountProgram_DailyQuotaFromFrequencies(t *testing.T) {
+	program := fulfillment.DefaultCountProgram()
+	var candidates []fulfillment.CountCandidate
+	for i := 0; i < 30; i++ {
+		candidates = append(candidates, fulfillment.CountCandidate{Class: fulfillment.VelocityA})
+	}
+	for i := 0; i < 91; i++ {
+		candidates = append(candidates, fulfillment.CountCandidate{Class: fulfillment.VelocityB})
+	}
+	// 30/30 + 91/90 = 2.01 contagens por dia
+	if got := program.DailyQuota(candidates); got != 3 {
+		t.Errorf("DailyQuota() = %d, want 3", got)
+	}
+}
+
+func TestCountProgram_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		program fulfillment.CountProgram
+	}{
+		{"missing class", fulfillment.CountProgram{Frequencies: map[fulfillment.VelocityClass]int{"A": 30, "B": 90}, LookbackDays: 90}},
+		{"zero lookback", fulfillment.CountProgram{Frequencies: map[fulfillment.VelocityClass]int{"A": 30, "B": 90, "C": 365}}},
+		{"negative capacity", fulfillment.CountProgram{Frequencies: map[fulfillment.VelocityClass]int{"A": 30, "B": 90, "C": 365}, LookbackDays: 90, DailyCapacity: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.program.Validate(); !errors.Is(err, fulfillment.ErrInvalidCountProgram) {
+				t.Errorf("Validate() error = %v, want ErrInvalidCountProgram", err)
+			}
+		})
+	}
+	if err := fulfillment.DefaultCountProgram().Validate(); err != nil {
+		t.Errorf("DefaultCountProgram().Validate() error = %v", err)
+	}
+	if _, err := fulfillment.NewCountTrigger("A-01", "SKU-001", fulfillment.CountScheduled, 0); !errors.Is(err, fulfillment.ErrInvalidCountTrigger) {
+		t.Errorf("NewCountTrigger(SCHEDULED) error = %v, want ErrInvalidCountTrigger", err)
+	}
+}
@@ -0,0 +1,72 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestResolveCrossDockPolicy_MostSpecificWins(t *testing.T) {
+	fallback, _ := fulfillment.NewCrossDockPolicy("", "", "STAGE-01", true, false)
+	bySKU, _ := fulfillment.NewCrossDockPolicy("", "SKU-1", "STAGE-02", true, false)
+	byCustomer, _ := fulfillment.NewCrossDockPolicy("ACME", "", "STAGE-03", false, false)
+	exact, _ := fulfillment.NewCrossDockPolicy("ACME", "SKU-1", "STAGE-04", true, false)
+	policies := []*fulfillment.CrossDockPolicy{exact, fallback, byCustomer, bySKU}
+
+	tests := []struct {
+		customer, sku string
+		want          *fulfillment.CrossDockPolicy
+	}{
+		{"ACME", "SKU-1", exact},
+		{"ACME", "SKU-2", byCustomer},
+		{"Outro", "SKU-1", bySKU},
+		{"Outro", "SKU-2", fallback},
+	}
+	for _, tt := range tests {
+		if got := fulfillment.ResolveCrossDockPolicy(policies, tt.customer, tt.sku); got != tt.want {
+			t.Errorf("ResolveCrossDockPolicy(%s, %s) = %+v, want %+v", tt.customer, tt.sku, got, tt.want)
+		}
+	}
+
+	if _, err := fulfillment.NewCrossDockPolicy("", "", "", true, false); !errors.Is(err, fulfillment.ErrInvalidCrossDockPolicy) {
+		t.Errorf("enabled policy without staging error = %v, want ErrInvalidCrossDockPolicy", err)
+	}
+}
+
+func TestPlanCrossDock_AllocatesByPriorityAndSplitsLines(t *testing.T) {
+	policy, _ := fulfillment.NewCrossDockPolicy("", "", "STAGE-01", true, false)
+	shipment, _ := fulfillment.NewInboundShipment("ASN-1", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-1", Quantity: 5}})
+
+	older, _ := fulfillment.NewFulfillmentOrder("OMS-1", "A", "Rua A", []fulfillment.Item{{SKU: "SKU-1", Quantity: 4, Location: "A-01-01"}}, 0)
+	older.MarkBackordered()
+	older.CreatedAt = time.Now().Add(-time.Hour)
+	express, _ := fulfillment.NewFulfillmentOrder("OMS-2", "B", "Rua B", []fulfillment.Item{{SKU: "SKU-1", Quantity: 3, Location: "A-01-01"}}, 1)
+	express.MarkBackordered()
+	pending, _ := fulfillment.NewFulfillmentOrder("OMS-3", "C", "Rua C", []fulfillment.Item{{SKU: "SKU-1", Quantity: 1}}, 1)
+
+	plan := fulfillment.PlanCrossDock(shipment, []*fulfillment.FulfillmentOrder{older, pending, express}, []*fulfillment.CrossDockPolicy{policy})
+	if len(plan) != 2 {
+		t.Fatalf("PlanCrossDock() = %d allocations, want 2 (pending orders need include_pending)", len(plan))
+	}
+	if plan[0].OrderID != express.ID || plan[0].Quantity != 3 {
+		t.Errorf("first allocation = %s x%d, want express order x3", plan[0].OrderID, plan[0].Quantity)
+	}
+	if plan[1].OrderID != older.ID || plan[1].Quantity != 2 || plan[1].From != "DOCK-1" || plan[1].To != "STAGE-01" {
+		t.Errorf("second allocation = %+v, want older order x2 DOCK-1 -> STAGE-01", plan[1])
+	}
+
+	if err := older.StageCrossDock(plan[1].Line, plan[1].Quantity, plan[1].To, ""); err != nil {
+		t.Fatalf("StageCrossDock() error = %v", err)
+	}
+	if len(older.Items) != 2 || older.Items[0].Quantity != 2 || older.Items[0].Location != "A-01-01" ||
+		older.Items[1].Quantity != 2 || older.Items[1].Location != "STAGE-01" {
+		t.Errorf("items after partial StageCrossDock() = %+v", older.Items)
+	}
+
+	older.MarkReserved(time.Now().Add(time.Hour))
+	if err := older.StageCrossDock(0, 1, "STAGE-01", ""); !errors.Is(err, fulfillment.ErrCrossDockNotAllowed) {
+		t.Errorf("StageCrossDock() on reserved order error = %v, want ErrCrossDockNotAllowed", err)
+	}
+}
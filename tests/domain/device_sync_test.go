@@ -0,0 +1,97 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestVectorClockMerge(t *testing.T) {
+	local := fulfillment.VectorClock{"HH-01": 3, "HH-02": 1}
+	remote := fulfillment.VectorClock{"HH-02": 4, "HH-03": 2}
+
+	got := local.Merge(remote)
+	want := fulfillment.VectorClock{"HH-01": 3, "HH-02": 4, "HH-03": 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+	if local["HH-02"] != 1 {
+		t.Errorf("Merge() changed the receiver: %v", local)
+	}
+}
+
+func TestSyncOperationValidate(t *testing.T) {
+	valid := func() fulfillment.SyncOperation {
+		return fulfillment.SyncOperation{
+			ID:       "op-1",
+			DeviceID: "HH-01",
+			Key:      "pick_session/S-1/1/location",
+			Kind:     "PICK_SCAN",
+			Clock:    fulfillment.VectorClock{"HH-01": 1},
+		}
+	}
+	tests := []struct {
+		name    string
+		change  func(op *fulfillment.SyncOperation)
+		wantErr error
+	}{
+		{"valid", func(op *fulfillment.SyncOperation) {}, nil},
+		{"missing id", func(op *fulfillment.SyncOperation) { op.ID = "" }, fulfillment.ErrInvalidSyncOperation},
+		{"missing key", func(op *fulfillment.SyncOperation) { op.Key = "" }, fulfillment.ErrInvalidSyncOperation},
+		{"missing kind", func(op *fulfillment.SyncOperation) { op.Kind = "" }, fulfillment.ErrInvalidSyncOperation},
+		{"clock without own counter", func(op *fulfillment.SyncOperation) {
+			op.Clock = fulfillment.VectorClock{"HH-02": 1}
+		}, fulfillment.ErrInvalidSyncOperation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := valid()
+			tt.change(&op)
+			if err := op.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDivergentFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		server map[string]interface{}
+		device map[string]interface{}
+		want   []string
+	}{
+		{"disjoint fields", map[string]interface{}{"quantity": 3}, map[string]interface{}{"reason": "avaria"}, nil},
+		{"same values", map[string]interface{}{"quantity": 3}, map[string]interface{}{"quantity": 3}, nil},
+		{"changed fields sorted", map[string]interface{}{"quantity": 3, "batch": "L-1", "sku": "A"},
+			map[string]interface{}{"quantity": 2, "batch": "L-2", "sku": "A"}, []string{"batch", "quantity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fulfillment.DivergentFields(tt.server, tt.device); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DivergentFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncConflictRecordResolve(t *testing.T) {
+	op := &fulfillment.SyncOperation{ID: "op-2", DeviceID: "HH-02", Key: "k", Kind: "PICK_SCAN", Clock: fulfillment.VectorClock{"HH-02": 1}}
+	server := fulfillment.NewSyncState(&fulfillment.SyncOperation{ID: "op-1", DeviceID: "HH-01", Key: "k", Kind: "PICK_SCAN", Clock: fulfillment.VectorClock{"HH-01": 1}})
+	record := fulfillment.NewSyncConflictRecord(server, op, []string{"quantity"}, "divergent")
+
+	if err := record.Resolve("BOTH", "supervisor"); !errors.Is(err, fulfillment.ErrInvalidSyncChoice) {
+		t.Fatalf("Resolve(BOTH) error = %v, want %v", err, fulfillment.ErrInvalidSyncChoice)
+	}
+	if err := record.Resolve(fulfillment.SyncTakeDevice, "supervisor"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if record.Status != fulfillment.StatusCompleted || record.ResolvedBy != "supervisor" || record.ResolvedAt == nil {
+		t.Errorf("resolved record = %+v", record)
+	}
+	if err := record.Resolve(fulfillment.SyncKeepServer, "supervisor"); !errors.Is(err, fulfillment.ErrSyncConflictResolved) {
+		t.Errorf("second Resolve() error = %v, want %v", err, fulfillment.ErrSyncConflictResolved)
+	}
+}
@@ -0,0 +1,129 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestNewDockDoor_Validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction fulfillment.DockDirection
+		hours     []fulfillment.DockHours
+		timezone  string
+	}{
+		{"", fulfillment.DockInbound, nil, ""},
+		{"DOCA-01", "SIDEWAYS", nil, ""},
+		{"DOCA-01", fulfillment.DockBoth, []fulfillment.DockHours{{Weekday: time.Monday, Open: "18:00", Close: "08:00"}}, ""},
+		{"DOCA-01", fulfillment.DockBoth, []fulfillment.DockHours{{Weekday: time.Monday, Open: "8h", Close: "18:00"}}, ""},
+		{"DOCA-01", fulfillment.DockBoth, nil, "Nowhere/Invalid"},
+	}
+	for _, tt := range tests {
+		if _, err := fulfillment.NewDockDoor(tt.name, tt.direction, nil, tt.hours, 0, tt.timezone); !errors.Is(err, fulfillment.ErrInvalidDockDoor) {
+			t.Errorf("NewDockDoor(%q, %s, %v, %q) error = %v, want ErrInvalidDockDoor", tt.name, tt.direction, tt.hours, tt.timezone, err)
+		}
+	}
+
+	door, err := fulfillment.NewDockDoor("DOCA-01", fulfillment.DockBoth, nil, nil, 0, "")
+	if err != nil {
+		t.Fatalf("NewDockDoor() error = %v", err)
+	}
+	if door.SlotMinutes != 60 || !door.Active {
+		t.Errorf("defaults = slot %d, active %v; want 60, true", door.SlotMinutes, door.Active)
+	}
+}
+
+func TestDockDoor_SlotsSkipBookedAndClosedTimes(t *testing.T) {
+	monday := time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC)
+	door, _ := fulfillment.NewDockDoor("DOCA-01", fulfillment.DockInbound, nil, []fulfillment.DockHours{
+		{Weekday: time.Monday, Open: "13:00", Close: "15:00"},
+		{Weekday: time.Monday, Open: "08:00", Close: "10:30"},
+	}, 60, "")
+
+	if !door.IsOpen(monday.Add(8*time.Hour), monday.Add(9*time.Hour)) {
+		t.Error("IsOpen(08:00-09:00) = false, want true")
+	}
+	if door.IsOpen(monday.Add(10*time.Hour), monday.Add(11*time.Hour)) {
+		t.Error("IsOpen(10:00-11:00) = true, want false (closes 10:30)")
+	}
+	if door.IsOpen(monday.Add(32*time.Hour), monday.Add(33*time.Hour)) {
+		t.Error("IsOpen(tuesday 08:00) = true, want false")
+	}
+
+	booked, _ := fulfillment.NewDockAppointment(door.ID, fulfillment.DockInbound, fulfillment.EntityInboundShipment, "ASN-1", "F", "",
+		monday.Add(8*time.Hour+30*time.Minute), monday.Add(9*time.Hour+30*time.Minute))
+	cancelled, _ := fulfillment.NewDockAppointment(door.ID, fulfillment.DockInbound, fulfillment.EntityInboundShipment, "ASN-2", "F", "",
+		monday.Add(13*time.Hour), monday.Add(14*time.Hour))
+	_ = cancelled.Cancel(time.Now())
+
+	slots := door.Slots(monday.Add(20*time.Hour), []*fulfillment.DockAppointment{booked, cancelled})
+	want := []time.Duration{13 * time.Hour, 14 * time.Hour}
+	if len(slots) != len(want) {
+		t.Fatalf("Slots() = %v, want starts %v", slots, want)
+	}
+	for i, start := range want {
+		if !slots[i].Start.Equal(monday.Add(start)) {
+			t.Errorf("slot %d start = %v, want %v", i, slots[i].Start, monday.Add(start))
+		}
+	}
+}
+
+func TestDockAppointment_LifecycleAndLateness(t *testing.T) {
+	start := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+	appointment, err := fulfillment.NewDockAppointment("door", fulfillment.DockInbound, fulfillment.EntityInboundShipment, "ASN-1", "F", "", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewDockAppointment() error = %v", err)
+	}
+
+	if appointment.MarkNoShow(start.Add(time.Hour), 2*time.Hour) {
+		t.Error("MarkNoShow() before deadline = true, want false")
+	}
+	if err := appointment.CheckIn(start.Add(40*time.Minute), 15*time.Minute); err != nil {
+		t.Fatalf("CheckIn() error = %v", err)
+	}
+	if appointment.LateMinutes != 25 {
+		t.Errorf("LateMinutes = %d, want 25", appointment.LateMinutes)
+	}
+	if err := appointment.Cancel(start); !errors.Is(err, fulfillment.ErrInvalidStateTransition) {
+		t.Errorf("Cancel() after arrival error = %v, want ErrInvalidStateTransition", err)
+	}
+	if err := appointment.Complete(start.Add(time.Hour)); err != nil || appointment.IsActive() {
+		t.Errorf("Complete() error = %v, active = %v", err, appointment.IsActive())
+	}
+
+	if _, err := fulfillment.NewDockAppointment("door", fulfillment.DockInbound, "", "ASN-1", "", "", start, start); !errors.Is(err, fulfillment.ErrInvalidAppointment) {
+		t.Errorf("NewDockAppointment(empty interval) error = %v, want ErrInvalidAppointment", err)
+	}
+}
+
+func TestBuildScorecards_AggregatesBySupplier(t *testing.T) {
+	start := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+	newAppointment := func(supplier string) *fulfillment.DockAppointment {
+		a, _ := fulfillment.NewDockAppointment("door", fulfillment.DockInbound, fulfillment.EntityInboundShipment, "ASN", supplier, "", start, start.Add(time.Hour))
+		return a
+	}
+
+	onTime, late, veryLate, missed, pending := newAppointment("A"), newAppointment("A"), newAppointment("A"), newAppointment("A"), newAppointment("B")
+	_ = onTime.CheckIn(start, 15*time.Minute)
+	_ = late.CheckIn(start.Add(25*time.Minute), 15*time.Minute)
+	_ = veryLate.CheckIn(start.Add(45*time.Minute), 15*time.Minute)
+	missed.MarkNoShow(start.Add(3*time.Hour), 2*time.Hour)
+
+	scorecards := fulfillment.BuildScorecards([]*fulfillment.DockAppointment{onTime, late, veryLate, missed, pending})
+	if len(scorecards) != 2 {
+		t.Fatalf("BuildScorecards() = %+v, want 2 suppliers", scorecards)
+	}
+	a := scorecards[0]
+	if a.Supplier != "A" || a.Appointments != 4 || a.OnTime != 1 || a.Late != 2 || a.NoShows != 1 {
+		t.Errorf("scorecard A = %+v", a)
+	}
+	if a.AverageLateMinutes != 20 || a.OnTimeRate != 0.25 {
+		t.Errorf("scorecard A average = %v, rate = %v; want 20, 0.25", a.AverageLateMinutes, a.OnTimeRate)
+	}
+	if b := scorecards[1]; b.Appointments != 0 {
+		t.Errorf("scorecard B = %+v, want no closed appointments", b)
+	}
+}
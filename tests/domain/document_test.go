@@ -0,0 +1,142 @@
+package domain_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func documentOrder() *fulfillment.FulfillmentOrder {
+	return &fulfillment.FulfillmentOrder{
+		ID:          "FO-1",
+		OrderID:     "OMS-1",
+		Customer:    "ACME <Ltda>",
+		Destination: "Rua A, 100",
+		Items: []fulfillment.Item{
+			{SKU: "SKU-001", Quantity: 2},
+			{SKU: "SKU-002", Quantity: 3, Batch: "L1"},
+		},
+	}
+}
+
+func TestNewDocumentTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		docType fulfillment.DocumentType
+		format  fulfillment.DocumentFormat
+		body    string
+		wantErr bool
+	}{
+		{"html", fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML, "<p>{{.Order.OrderID}}</p>", false},
+		{"zpl with helper", fulfillment.DocumentReturnLabel, fulfillment.DocumentZPL, "^XA^FD{{zpl .Order.Customer}}^FS^XZ", false},
+		{"unknown type", "LABEL", fulfillment.DocumentHTML, "x", true},
+		{"unknown format", fulfillment.DocumentPackingSlip, "docx", "x", true},
+		{"empty body", fulfillment.DocumentPackingSlip, fulfillment.DocumentPDF, "  ", true},
+		{"syntax error", fulfillment.DocumentPackingSlip, fulfillment.DocumentPDF, "{{.Order", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fulfillment.NewDocumentTemplate("ACME", tt.docType, tt.format, tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDocumentTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, fulfillment.ErrInvalidDocumentTemplate) {
+				t.Errorf("NewDocumentTemplate() error = %v, want ErrInvalidDocumentTemplate", err)
+			}
+		})
+	}
+}
+
+func TestNewDocumentData(t *testing.T) {
+	data := fulfillment.NewDocumentData(documentOrder(), nil, map[string]float64{"SKU-001": 10})
+	if data.TotalUnits != 5 {
+		t.Errorf("TotalUnits = %d, want 5", data.TotalUnits)
+	}
+	if data.TotalValue != 20 {
+		t.Errorf("TotalValue = %v, want 20", data.TotalValue)
+	}
+	if len(data.Lines) != 2 || data.Lines[1].Batch != "L1" || data.Lines[1].Value != 0 {
+		t.Errorf("Lines = %+v", data.Lines)
+	}
+}
+
+func TestDefaultDocumentTemplatesRender(t *testing.T) {
+	shipment := fulfillment.NewOutboundShipment("FO-1", "TRK-1", "TRANSP-X")
+	shipment.Pieces, shipment.Weight = 2, 3.5
+	data := fulfillment.NewDocumentData(documentOrder(), shipment, map[string]float64{"SKU-001": 10})
+
+	for _, docType := range []fulfillment.DocumentType{fulfillment.DocumentPackingSlip, fulfillment.DocumentReturnLabel, fulfillment.DocumentCommercialInvoice} {
+		for _, format := range []fulfillment.DocumentFormat{fulfillment.DocumentHTML, fulfillment.DocumentPDF, fulfillment.DocumentZPL} {
+			t.Run(string(docType)+"/"+string(format), func(t *testing.T) {
+				tmpl, err := fulfillment.DefaultDocumentTemplate(docType, format)
+				if err != nil {
+					t.Fatalf("DefaultDocumentTemplate() error = %v", err)
+				}
+				document, err := fulfillment.NewRenderedDocument(tmpl, data, "expedicao-1")
+				if err != nil {
+					t.Fatalf("NewRenderedDocument() error = %v", err)
+				}
+				if document.FulfillmentOrderID != "FO-1" || document.Size != len(document.Content) {
+					t.Errorf("document = %+v", document)
+				}
+				content := string(document.Content)
+				switch format {
+				case fulfillment.DocumentHTML:
+					if !strings.Contains(content, "ACME &lt;Ltda&gt;") || !strings.Contains(content, "TRK-1") {
+						t.Errorf("HTML content not escaped or missing shipment: %s", content)
+					}
+				case fulfillment.DocumentPDF:
+					if !strings.HasPrefix(content, "%PDF-1.4") || !strings.HasSuffix(content, "%%EOF\n") || !strings.Contains(content, "(Cliente: ACME <Ltda>)") {
+						t.Errorf("PDF content = %s", content)
+					}
+				case fulfillment.DocumentZPL:
+					if !strings.HasPrefix(content, "^XA") || !strings.Contains(content, "^FDTRK-1^FS") {
+						t.Errorf("ZPL content = %s", content)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDocumentTemplateCannotChangeOrder(t *testing.T) {
+	tmpl, err := fulfillment.NewDocumentTemplate("", fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML, "{{.Order.Cancel}}")
+	if err != nil {
+		t.Fatalf("NewDocumentTemplate() error = %v", err)
+	}
+	order := documentOrder()
+	order.Status = fulfillment.StatusPending
+	if _, err := tmpl.Render(fulfillment.NewDocumentData(order, nil, nil)); err == nil {
+		t.Error("Render() should reject a template calling an order method")
+	}
+	if order.Status != fulfillment.StatusPending {
+		t.Errorf("order.Status = %s after render, want PENDING", order.Status)
+	}
+}
+
+func TestPDFDocumentPaginatesAndEscapes(t *testing.T) {
+	tmpl, err := fulfillment.NewDocumentTemplate("", fulfillment.DocumentPackingSlip, fulfillment.DocumentPDF,
+		`{{range .Lines}}{{.SKU}} (ação)
+{{end}}`)
+	if err != nil {
+		t.Fatalf("NewDocumentTemplate() error = %v", err)
+	}
+	order := documentOrder()
+	order.Items = nil
+	for i := 0; i < 100; i++ {
+		order.Items = append(order.Items, fulfillment.Item{SKU: "SKU", Quantity: 1})
+	}
+	content, err := tmpl.Render(fulfillment.NewDocumentData(order, nil, nil))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !bytes.Contains(content, []byte("/Count 2")) {
+		t.Errorf("PDF should have 2 pages")
+	}
+	if !bytes.Contains(content, []byte(`(SKU \(a\347\343o\)) Tj`)) {
+		t.Errorf("PDF line not escaped as Latin-1")
+	}
+}
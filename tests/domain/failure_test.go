@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want fulfillment.FailureClass
+	}{
+		{"transient marker", fmt.Errorf("%w: timeout", fulfillment.ErrTransientFailure), fulfillment.FailureTransient},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), fulfillment.FailureTransient},
+		{"business error", fulfillment.ErrInsufficientStock, fulfillment.FailurePermanent},
+		{"unknown", errors.New("boom"), fulfillment.FailurePermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fulfillment.ClassifyFailure(tt.err); got != tt.want {
+				t.Errorf("ClassifyFailure() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFailure_BackoffAndAttempts(t *testing.T) {
+	policy := fulfillment.DefaultPolicy()
+	if policy.RetryBackoff(1) != 30*time.Second || policy.RetryBackoff(2) != time.Minute {
+		t.Errorf("unexpected backoff: %v, %v", policy.RetryBackoff(1), policy.RetryBackoff(2))
+	}
+	if got := policy.RetryBackoff(100); got != 15*time.Minute {
+		t.Errorf("RetryBackoff(100) = %v, want capped at 15m", got)
+	}
+
+	transient := fmt.Errorf("%w: timeout", fulfillment.ErrTransientFailure)
+	var failure *fulfillment.Failure
+	for i := 0; i < policy.RetryMaxAttempts; i++ {
+		failure = fulfillment.NewFailure(fulfillment.StepAdjustStock, 2, transient, failure)
+	}
+	if failure.Attempt != policy.RetryMaxAttempts {
+		t.Errorf("Attempt = %d, want %d", failure.Attempt, policy.RetryMaxAttempts)
+	}
+	if failure.NextRetryAt != nil {
+		t.Error("no automatic retry expected after max attempts")
+	}
+
+	permanent := fulfillment.NewFailure(fulfillment.StepAdjustStock, 0, errors.New("invalid sku"), nil)
+	if permanent.NextRetryAt != nil || permanent.DueForRetry(time.Now().Add(time.Hour)) {
+		t.Error("permanent failures must not be scheduled for retry")
+	}
+
+	if got := failure.ResumeFrom(fulfillment.StepAdjustStock); got != 2 {
+		t.Errorf("ResumeFrom() = %d, want 2", got)
+	}
+	var none *fulfillment.Failure
+	if got := none.ResumeFrom(fulfillment.StepAdjustStock); got != 0 {
+		t.Errorf("nil ResumeFrom() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestNewKitDefinition_ValidatesBillOfMaterials(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []fulfillment.KitComponent
+	}{
+		{"empty", nil},
+		{"zero quantity", []fulfillment.KitComponent{{SKU: "PART-1", Quantity: 0}}},
+		{"self reference", []fulfillment.KitComponent{{SKU: "KIT-1", Quantity: 1}}},
+		{"duplicated", []fulfillment.KitComponent{{SKU: "PART-1", Quantity: 1}, {SKU: "PART-1", Quantity: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fulfillment.NewKitDefinition("KIT-1", "Kit", tt.components); !errors.Is(err, fulfillment.ErrInvalidKit) {
+				t.Errorf("NewKitDefinition() error = %v, want ErrInvalidKit", err)
+			}
+		})
+	}
+}
+
+func TestAssemblyOrder_MovementsConsumeBeforeProducing(t *testing.T) {
+	kit, err := fulfillment.NewKitDefinition("KIT-1", "Kit", []fulfillment.KitComponent{
+		{SKU: "PART-1", Quantity: 2},
+		{SKU: "PART-2", Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("NewKitDefinition() error = %v", err)
+	}
+
+	assemble, err := fulfillment.NewAssemblyOrder(kit, fulfillment.AssemblyAssemble, 3, "K-01-01", "")
+	if err != nil {
+		t.Fatalf("NewAssemblyOrder() error = %v", err)
+	}
+	want := []fulfillment.Item{
+		{SKU: "PART-1", Quantity: -6, Location: "K-01-01"},
+		{SKU: "PART-2", Quantity: -3, Location: "K-01-01"},
+		{SKU: "KIT-1", Quantity: 3, Location: "K-01-01"},
+	}
+	if got := assemble.Movements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("assemble Movements() = %+v, want %+v", got, want)
+	}
+
+	disassemble, err := fulfillment.NewAssemblyOrder(kit, fulfillment.AssemblyDisassemble, 1, "K-01-01", "")
+	if err != nil {
+		t.Fatalf("NewAssemblyOrder() error = %v", err)
+	}
+	want = []fulfillment.Item{
+		{SKU: "KIT-1", Quantity: -1, Location: "K-01-01"},
+		{SKU: "PART-1", Quantity: 2, Location: "K-01-01"},
+		{SKU: "PART-2", Quantity: 1, Location: "K-01-01"},
+	}
+	if got := disassemble.Movements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("disassemble Movements() = %+v, want %+v", got, want)
+	}
+
+	if _, err := fulfillment.NewAssemblyOrder(kit, fulfillment.AssemblyAssemble, 0, "K-01-01", ""); !errors.Is(err, fulfillment.ErrInvalidAssembly) {
+		t.Errorf("zero quantity error = %v, want ErrInvalidAssembly", err)
+	}
+}
+
+func TestAssemblyOrder_FailAndRetry(t *testing.T) {
+	kit, _ := fulfillment.NewKitDefinition("KIT-1", "", []fulfillment.KitComponent{{SKU: "PART-1", Quantity: 1}})
+	order, _ := fulfillment.NewAssemblyOrder(kit, fulfillment.AssemblyAssemble, 1, "K-01-01", "")
+
+	if err := order.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := order.Fail(fulfillment.StepAdjustStock, 1, errors.New("insufficient stock")); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if order.Status != fulfillment.StatusFailed || order.Failure == nil || order.Failure.Item != 1 {
+		t.Fatalf("order after Fail() = %s %+v", order.Status, order.Failure)
+	}
+	if err := order.Retry(); err != nil || order.Status != fulfillment.StatusPending {
+		t.Errorf("Retry() = %v, status %s", err, order.Status)
+	}
+}
@@ -0,0 +1,112 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestNewLPN(t *testing.T) {
+	expected, err := fulfillment.NewLPN("PLT-1", fulfillment.LPNPallet, "", []fulfillment.Item{
+		{SKU: "SKU-2", Quantity: 1}, {SKU: "SKU-1", Quantity: 2}, {SKU: "SKU-2", Quantity: 3},
+	})
+	if err != nil {
+		t.Fatalf("NewLPN() error = %v", err)
+	}
+	if expected.Status != fulfillment.LPNExpected {
+		t.Errorf("status = %s, want EXPECTED without location", expected.Status)
+	}
+	want := []fulfillment.Item{{SKU: "SKU-1", Quantity: 2}, {SKU: "SKU-2", Quantity: 4}}
+	if len(expected.Contents) != 2 || expected.Contents[0] != want[0] || expected.Contents[1] != want[1] {
+		t.Errorf("contents = %v, want %v", expected.Contents, want)
+	}
+
+	active, _ := fulfillment.NewLPN("CS-1", fulfillment.LPNCase, "A-01-01", nil)
+	if active.Status != fulfillment.LPNActive {
+		t.Errorf("status = %s, want ACTIVE with location", active.Status)
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lpnType  fulfillment.LPNType
+		contents []fulfillment.Item
+	}{
+		{"missing code", "", fulfillment.LPNCase, nil},
+		{"unknown type", "X-1", "BAG", nil},
+		{"non-positive quantity", "CS-2", fulfillment.LPNCase, []fulfillment.Item{{SKU: "SKU-1", Quantity: 0}}},
+	}
+	for _, tt := range tests {
+		if _, err := fulfillment.NewLPN(tt.code, tt.lpnType, "", tt.contents); !errors.Is(err, fulfillment.ErrInvalidLPN) {
+			t.Errorf("%s: error = %v, want ErrInvalidLPN", tt.name, err)
+		}
+	}
+}
+
+func TestLPN_CanNest(t *testing.T) {
+	pallet, _ := fulfillment.NewLPN("PLT-1", fulfillment.LPNPallet, "DOCK-1", nil)
+	shipped, _ := fulfillment.NewLPN("PLT-2", fulfillment.LPNPallet, "DOCK-1", nil)
+	shipped.Status = fulfillment.LPNShipped
+	caseHere, _ := fulfillment.NewLPN("CS-1", fulfillment.LPNCase, "DOCK-1", nil)
+	caseElsewhere, _ := fulfillment.NewLPN("CS-2", fulfillment.LPNCase, "A-01-01", nil)
+	tote, _ := fulfillment.NewLPN("TT-1", fulfillment.LPNTote, "DOCK-1", nil)
+
+	tests := []struct {
+		name   string
+		parent *fulfillment.LPN
+		child  *fulfillment.LPN
+		want   error
+	}{
+		{"case on pallet", pallet, caseHere, nil},
+		{"tote on pallet", pallet, tote, nil},
+		{"pallet in case", caseHere, pallet, fulfillment.ErrLPNNesting},
+		{"case in tote", tote, caseHere, fulfillment.ErrLPNNesting},
+		{"itself", pallet, pallet, fulfillment.ErrLPNNesting},
+		{"different location", pallet, caseElsewhere, fulfillment.ErrLPNNesting},
+		{"shipped parent", shipped, caseHere, fulfillment.ErrLPNNotHandleable},
+	}
+	for _, tt := range tests {
+		err := tt.parent.CanNest(tt.child)
+		if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("%s: CanNest() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLPN_PackUnpack(t *testing.T) {
+	lpn, _ := fulfillment.NewLPN("CS-1", fulfillment.LPNCase, "A-01-01", []fulfillment.Item{{SKU: "SKU-1", Quantity: 5, Batch: "L1"}})
+
+	if err := lpn.Pack([]fulfillment.Item{{SKU: "SKU-1", Quantity: 3, Batch: "L2"}}); err != nil {
+		t.Fatalf("Pack() error = %v", err)
+	}
+	if err := lpn.Unpack([]fulfillment.Item{{SKU: "SKU-1", Quantity: 6}}); err != nil {
+		t.Fatalf("Unpack() error = %v", err)
+	}
+	if len(lpn.Contents) != 1 || lpn.Contents[0].Batch != "L2" || lpn.Contents[0].Quantity != 2 {
+		t.Errorf("contents = %v, want 2 of batch L2", lpn.Contents)
+	}
+
+	if err := lpn.Unpack([]fulfillment.Item{{SKU: "SKU-1", Quantity: 3}}); !errors.Is(err, fulfillment.ErrInvalidLPN) {
+		t.Errorf("Unpack() beyond contents error = %v, want ErrInvalidLPN", err)
+	}
+	if lpn.Contents[0].Quantity != 2 {
+		t.Errorf("failed unpack changed contents to %v", lpn.Contents)
+	}
+
+	lpn.Status = fulfillment.LPNShipped
+	if err := lpn.Pack([]fulfillment.Item{{SKU: "SKU-1", Quantity: 1}}); !errors.Is(err, fulfillment.ErrLPNNotHandleable) {
+		t.Errorf("Pack() on shipped error = %v, want ErrLPNNotHandleable", err)
+	}
+}
+
+func TestSameSKUTotals(t *testing.T) {
+	lpn := []fulfillment.Item{{SKU: "SKU-1", Quantity: 2, Batch: "L1"}, {SKU: "SKU-1", Quantity: 3, Batch: "L2"}, {SKU: "SKU-2", Quantity: 1}}
+
+	if !fulfillment.SameSKUTotals(lpn, []fulfillment.Item{{SKU: "SKU-2", Quantity: 1}, {SKU: "SKU-1", Quantity: 5}}) {
+		t.Error("SameSKUTotals() = false, want true when batches add up")
+	}
+	if fulfillment.SameSKUTotals(lpn, []fulfillment.Item{{SKU: "SKU-1", Quantity: 5}}) {
+		t.Error("SameSKUTotals() = true, want false with an extra SKU")
+	}
+}
@@ -0,0 +1,142 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func shippedOutbound(t *testing.T, orderID, carrier string, pieces int, weight float64) *fulfillment.OutboundShipment {
+	t.Helper()
+	shipment := fulfillment.NewOutboundShipment(orderID, "TRK-"+orderID, carrier)
+	shipment.Pieces, shipment.Weight = pieces, weight
+	if err := shipment.Ship(); err != nil {
+		t.Fatalf("Ship() error = %v", err)
+	}
+	return shipment
+}
+
+func TestOutboundShipmentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		carrier string
+		pieces  int
+		weight  float64
+		wantErr bool
+	}{
+		{"valid", "TRANSP-X", 2, 3.5, false},
+		{"missing carrier", "", 2, 3.5, true},
+		{"no pieces", "TRANSP-X", 0, 3.5, true},
+		{"negative weight", "TRANSP-X", 1, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shipment := fulfillment.NewOutboundShipment("FO-1", "", tt.carrier)
+			shipment.Pieces, shipment.Weight = tt.pieces, tt.weight
+			err := shipment.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, fulfillment.ErrInvalidOutboundShipment) {
+				t.Errorf("Validate() error = %v, want ErrInvalidOutboundShipment", err)
+			}
+		})
+	}
+}
+
+func TestCarrierManifestAdd(t *testing.T) {
+	today := time.Now()
+	tests := []struct {
+		name     string
+		shipment func(t *testing.T) *fulfillment.OutboundShipment
+		closed   bool
+		wantErr  error
+	}{
+		{"shipped same carrier", func(t *testing.T) *fulfillment.OutboundShipment {
+			return shippedOutbound(t, "FO-1", "TRANSP-X", 2, 3)
+		}, false, nil},
+		{"not shipped", func(t *testing.T) *fulfillment.OutboundShipment {
+			return fulfillment.NewOutboundShipment("FO-1", "", "TRANSP-X")
+		}, false, fulfillment.ErrInvalidOutboundShipment},
+		{"other carrier", func(t *testing.T) *fulfillment.OutboundShipment {
+			return shippedOutbound(t, "FO-1", "TRANSP-Y", 1, 1)
+		}, false, fulfillment.ErrInvalidOutboundShipment},
+		{"other day", func(t *testing.T) *fulfillment.OutboundShipment {
+			shipment := shippedOutbound(t, "FO-1", "TRANSP-X", 1, 1)
+			yesterday := shipment.ShippedAt.AddDate(0, 0, -1)
+			shipment.ShippedAt = &yesterday
+			return shipment
+		}, false, fulfillment.ErrInvalidOutboundShipment},
+		{"closed manifest", func(t *testing.T) *fulfillment.OutboundShipment {
+			return shippedOutbound(t, "FO-1", "TRANSP-X", 1, 1)
+		}, true, fulfillment.ErrManifestClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shipment := tt.shipment(t)
+			manifest, err := fulfillment.NewCarrierManifest("TRANSP-X", today)
+			if err != nil {
+				t.Fatalf("NewCarrierManifest() error = %v", err)
+			}
+			if tt.closed {
+				if err := manifest.Close("expedicao"); err != nil {
+					t.Fatalf("Close() error = %v", err)
+				}
+			}
+			err = manifest.Add(shipment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (manifest.Shipments != 1 || shipment.ManifestID != manifest.ID) {
+				t.Errorf("Add() manifest = %+v, shipment manifest = %q", manifest, shipment.ManifestID)
+			}
+		})
+	}
+}
+
+func TestCarrierManifestDocuments(t *testing.T) {
+	manifest, err := fulfillment.NewCarrierManifest("TRANSP-X", time.Now())
+	if err != nil {
+		t.Fatalf("NewCarrierManifest() error = %v", err)
+	}
+	shipments := []*fulfillment.OutboundShipment{
+		shippedOutbound(t, "FO-1", "TRANSP-X", 2, 3.5),
+		shippedOutbound(t, "FO-2", "TRANSP-X", 1, 1.25),
+	}
+	for _, shipment := range shipments {
+		if err := manifest.Add(shipment); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := manifest.Close("expedicao-1"); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := manifest.Close("expedicao-1"); !errors.Is(err, fulfillment.ErrManifestClosed) {
+		t.Errorf("Close() twice error = %v, want ErrManifestClosed", err)
+	}
+
+	csvDocument, err := manifest.CSV(shipments)
+	if err != nil {
+		t.Fatalf("CSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(csvDocument)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("CSV() lines = %d, want 4", len(lines))
+	}
+	if !strings.HasSuffix(lines[3], "TOTAL,2,,3,4.75") {
+		t.Errorf("CSV() total line = %q", lines[3])
+	}
+
+	htmlDocument, err := manifest.HTML(shipments)
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	for _, want := range []string{"TRK-FO-1", "TRK-FO-2", "4.75", "expedicao-1"} {
+		if !strings.Contains(string(htmlDocument), want) {
+			t.Errorf("HTML() missing %q", want)
+		}
+	}
+}
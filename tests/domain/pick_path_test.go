@@ -0,0 +1,133 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// twoAisleLayout: corredores em x=0 e x=10 (y de 0 a 20), transversais na frente e no fundo, depósito na frente do primeiro
+func twoAisleLayout(t *testing.T, secondDirection fulfillment.AisleDirection) *fulfillment.WarehouseLayout {
+	t.Helper()
+	layout, err := fulfillment.NewWarehouseLayout(
+		[]fulfillment.Aisle{
+			{ID: "01", X: 0, Start: 0, End: 20},
+			{ID: "02", X: 10, Start: 0, End: 20, Direction: secondDirection},
+		},
+		nil,
+		fulfillment.Point{X: 0, Y: 0},
+		[]fulfillment.BinCoordinate{
+			{Location: "A-2", Aisle: "02", Position: 18},
+			{Location: "B-1", Aisle: "01", Position: 18},
+			{Location: "C-3", Aisle: "02", Position: 2},
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewWarehouseLayout() error = %v", err)
+	}
+	return layout
+}
+
+func pickLines() []fulfillment.Item {
+	return []fulfillment.Item{
+		{SKU: "SKU-1", Quantity: 1, Location: "C-3"},
+		{SKU: "SKU-2", Quantity: 1, Location: "A-2"},
+		{SKU: "SKU-3", Quantity: 1, Location: "B-1"},
+		{SKU: "SKU-4", Quantity: 2, Location: "A-2"},
+		{SKU: "SKU-5", Quantity: 1, Location: "Z-9"},
+	}
+}
+
+func routeByStrategy(plan *fulfillment.PickPlan, strategy fulfillment.RouteStrategy) float64 {
+	for _, c := range plan.Comparison {
+		if c.Strategy == strategy {
+			return c.Distance
+		}
+	}
+	return -1
+}
+
+func stopLocations(route fulfillment.PickRoute) []string {
+	var locations []string
+	for _, stop := range route.Stops {
+		locations = append(locations, stop.Location)
+	}
+	return locations
+}
+
+func TestPlanPickRoute_SShapeBeatsAlphabetical(t *testing.T) {
+	plan, err := twoAisleLayout(t, fulfillment.AisleTwoWay).PlanPickRoute(pickLines())
+	if err != nil {
+		t.Fatalf("PlanPickRoute() error = %v", err)
+	}
+
+	// Alfabética: A-2 (fundo do 02), B-1, C-3 (frente do 02) = 28 + 14 + 30 + 12
+	if got := routeByStrategy(plan, fulfillment.RouteByLocation); got != 84 {
+		t.Errorf("LOCATION distance = %v, want 84", got)
+	}
+	// Serpentina: sobe o 01 até B-1, desce o 02 por A-2 e C-3 = 18 + 14 + 16 + 12
+	if got := routeByStrategy(plan, fulfillment.RouteSShape); got != 60 {
+		t.Errorf("S_SHAPE distance = %v, want 60", got)
+	}
+	if plan.Route.Distance != 60 {
+		t.Errorf("best distance = %v, want 60", plan.Route.Distance)
+	}
+	if got := stopLocations(plan.Route); len(got) != 3 || got[0] != "B-1" || got[1] != "A-2" || got[2] != "C-3" {
+		t.Errorf("stops = %v, want [B-1 A-2 C-3]", got)
+	}
+	if len(plan.Route.Stops[1].Items) != 2 {
+		t.Errorf("A-2 lines = %d, want both lines grouped in one stop", len(plan.Route.Stops[1].Items))
+	}
+	if len(plan.Route.Unplaced) != 1 || plan.Route.Unplaced[0].Location != "Z-9" {
+		t.Errorf("unplaced = %v, want Z-9", plan.Route.Unplaced)
+	}
+}
+
+func TestPlanPickRoute_OneWayAisle(t *testing.T) {
+	plan, err := twoAisleLayout(t, fulfillment.AisleUp).PlanPickRoute(pickLines())
+	if err != nil {
+		t.Fatalf("PlanPickRoute() error = %v", err)
+	}
+
+	// O corredor 02 só sobe: a serpentina percorre C-3 antes de A-2 e volta pelo fundo
+	if got := routeByStrategy(plan, fulfillment.RouteSShape); got != 96 {
+		t.Errorf("S_SHAPE distance = %v, want 96", got)
+	}
+	if got := routeByStrategy(plan, fulfillment.RouteTwoOpt); got > 96 {
+		t.Errorf("2-opt distance = %v, must not exceed S_SHAPE", got)
+	}
+	if plan.Route.Distance > routeByStrategy(plan, fulfillment.RouteByLocation) {
+		t.Errorf("best distance = %v is worse than LOCATION", plan.Route.Distance)
+	}
+	var total float64
+	for _, stop := range plan.Route.Stops {
+		total += stop.Distance
+	}
+	if total > plan.Route.Distance {
+		t.Errorf("stop distances %v exceed route distance %v", total, plan.Route.Distance)
+	}
+}
+
+func TestNewWarehouseLayout_Validation(t *testing.T) {
+	aisle := fulfillment.Aisle{ID: "01", X: 0, Start: 0, End: 20}
+	tests := []struct {
+		name   string
+		aisles []fulfillment.Aisle
+		depot  fulfillment.Point
+		bins   []fulfillment.BinCoordinate
+	}{
+		{"no aisles", nil, fulfillment.Point{}, nil},
+		{"duplicate aisle", []fulfillment.Aisle{aisle, {ID: "01", X: 5, Start: 0, End: 20}}, fulfillment.Point{}, nil},
+		{"inverted aisle", []fulfillment.Aisle{{ID: "01", Start: 20, End: 0}}, fulfillment.Point{}, nil},
+		{"bad direction", []fulfillment.Aisle{{ID: "01", Start: 0, End: 20, Direction: "LEFT"}}, fulfillment.Point{}, nil},
+		{"depot off cross-aisle", []fulfillment.Aisle{aisle}, fulfillment.Point{Y: 7}, nil},
+		{"bin on unknown aisle", []fulfillment.Aisle{aisle}, fulfillment.Point{}, []fulfillment.BinCoordinate{{Location: "X", Aisle: "99"}}},
+		{"bin outside aisle", []fulfillment.Aisle{aisle}, fulfillment.Point{}, []fulfillment.BinCoordinate{{Location: "X", Aisle: "01", Position: 25}}},
+	}
+	for _, tt := range tests {
+		if _, err := fulfillment.NewWarehouseLayout(tt.aisles, nil, tt.depot, tt.bins); !errors.Is(err, fulfillment.ErrInvalidLayout) {
+			t.Errorf("%s: error = %v, want ErrInvalidLayout", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func newPickSession(t *testing.T, route []string, items ...fulfillment.Item) *fulfillment.PickSession {
+	t.Helper()
+	order, err := fulfillment.NewFulfillmentOrder("OMS-1", "Cliente", "Rua A", items, 0)
+	if err != nil {
+		t.Fatalf("NewFulfillmentOrder() error = %v", err)
+	}
+	session, err := fulfillment.NewPickSession(order, "TASK-1", "operador-1", "HH-01", route)
+	if err != nil {
+		t.Fatalf("NewPickSession() error = %v", err)
+	}
+	return session
+}
+
+func TestNewPickSessionSequence(t *testing.T) {
+	items := []fulfillment.Item{
+		{SKU: "SKU-A", Quantity: 1, Location: "C-01"},
+		{SKU: "SKU-B", Quantity: 1},
+		{SKU: "SKU-C", Quantity: 1, Location: "A-01"},
+		{SKU: "SKU-D", Quantity: 1, Location: "B-01"},
+	}
+	tests := []struct {
+		name  string
+		route []string
+		want  []string
+	}{
+		{"location order without route", nil, []string{"SKU-B", "SKU-C", "SKU-D", "SKU-A"}},
+		{"route first, then remaining by location", []string{"C-01", "B-01"}, []string{"SKU-A", "SKU-D", "SKU-B", "SKU-C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newPickSession(t, tt.route, items...)
+			for idx, sku := range tt.want {
+				if got := session.Steps[idx].SKU; got != sku {
+					t.Errorf("step %d SKU = %s, want %s", idx+1, got, sku)
+				}
+			}
+		})
+	}
+
+	session := newPickSession(t, nil, items...)
+	if got := session.Steps[0].Status; got != fulfillment.PickStepAwaitingItem {
+		t.Errorf("step without location status = %s, want %s", got, fulfillment.PickStepAwaitingItem)
+	}
+}
+
+func TestPickSessionScans(t *testing.T) {
+	tests := []struct {
+		name    string
+		scan    func(s *fulfillment.PickSession) error
+		wantErr error
+	}{
+		{"item before location", func(s *fulfillment.PickSession) error {
+			return s.ScanItem(fulfillment.Item{SKU: "SKU-A"})
+		}, fulfillment.ErrPickStepOutOfSequence},
+		{"wrong location", func(s *fulfillment.PickSession) error {
+			return s.ScanLocation("A-02")
+		}, fulfillment.ErrWrongLocation},
+		{"wrong sku", func(s *fulfillment.PickSession) error {
+			s.ScanLocation(" a-01 ")
+			return s.ScanItem(fulfillment.Item{SKU: "SKU-B"})
+		}, fulfillment.ErrWrongSKU},
+		{"wrong batch", func(s *fulfillment.PickSession) error {
+			s.ScanLocation("A-01")
+			return s.ScanItem(fulfillment.Item{SKU: "SKU-A", Batch: "L-2"})
+		}, fulfillment.ErrWrongBatch},
+		{"quantity before item", func(s *fulfillment.PickSession) error {
+			s.ScanLocation("A-01")
+			return s.ConfirmQuantity(5, "")
+		}, fulfillment.ErrPickStepOutOfSequence},
+		{"over pick", func(s *fulfillment.PickSession) error {
+			s.ScanLocation("A-01")
+			s.ScanItem(fulfillment.Item{SKU: "SKU-A"})
+			return s.ConfirmQuantity(6, "")
+		}, fulfillment.ErrOverPick},
+		{"short pick without reason", func(s *fulfillment.PickSession) error {
+			s.ScanLocation("A-01")
+			s.ScanItem(fulfillment.Item{SKU: "SKU-A", Batch: "L-1"})
+			return s.ConfirmQuantity(4, " ")
+		}, fulfillment.ErrShortPickReasonRequired},
+		{"negative quantity", func(s *fulfillment.PickSession) error {
+			s.ScanLocation("A-01")
+			s.ScanItem(fulfillment.Item{SKU: "SKU-A"})
+			return s.ConfirmQuantity(-1, "")
+		}, fulfillment.ErrInvalidPickQuantity},
+		{"short pick with reason", func(s *fulfillment.PickSession) error {
+			s.ScanLocation("A-01")
+			s.ScanItem(fulfillment.Item{SKU: "SKU-A"})
+			return s.ConfirmQuantity(4, "avaria")
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newPickSession(t, nil, fulfillment.Item{SKU: "SKU-A", Quantity: 5, Batch: "L-1", Location: "A-01"})
+			err := tt.scan(session)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("scan error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !session.Done() {
+				t.Errorf("Done() = false after last step")
+			}
+		})
+	}
+}
+
+func TestPickSessionPickedLines(t *testing.T) {
+	items := []fulfillment.Item{
+		{SKU: "SKU-A", Quantity: 5, Location: "A-01", PackUoM: fulfillment.UoMCase, PackQuantity: 1},
+		{SKU: "SKU-B", Quantity: 2, Location: "B-01"},
+		{SKU: "SKU-C", Quantity: 3, Location: "C-01"},
+	}
+	session := newPickSession(t, nil, items...)
+	for _, picked := range []struct {
+		location, batch string
+		quantity        int
+	}{{"A-01", "L-7", 3}, {"B-01", "", 0}, {"C-01", "", 3}} {
+		step := session.CurrentStep()
+		if err := session.ScanLocation(picked.location); err != nil {
+			t.Fatalf("ScanLocation() error = %v", err)
+		}
+		if err := session.ScanItem(fulfillment.Item{SKU: step.SKU, Batch: picked.batch}); err != nil {
+			t.Fatalf("ScanItem() error = %v", err)
+		}
+		if err := session.ConfirmQuantity(picked.quantity, "falta"); err != nil {
+			t.Fatalf("ConfirmQuantity() error = %v", err)
+		}
+	}
+
+	if got := len(session.ShortSteps()); got != 2 {
+		t.Errorf("ShortSteps() = %d, want 2", got)
+	}
+	lines := session.PickedLines(items)
+	if len(lines) != 2 {
+		t.Fatalf("PickedLines() = %d lines, want 2", len(lines))
+	}
+	if lines[0].Quantity != 3 || lines[0].Batch != "L-7" || lines[0].PackUoM != "" {
+		t.Errorf("short line = %+v, want 3 units of batch L-7 without pack", lines[0])
+	}
+	if lines[1].SKU != "SKU-C" || lines[1].Quantity != 3 {
+		t.Errorf("full line = %+v, want 3 SKU-C", lines[1])
+	}
+
+	// Ordem já ajustada por uma tentativa anterior não é alterada de novo
+	if again := session.PickedLines(lines); len(again) != 2 || again[0].Quantity != 3 {
+		t.Errorf("PickedLines() on adjusted order = %+v", again)
+	}
+}
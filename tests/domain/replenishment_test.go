@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestReplenishmentRule_Evaluate(t *testing.T) {
+	minMax, err := fulfillment.NewReplenishmentRule("SKU-1", "A-01-01", "R-01-01", fulfillment.ReplenishMinMax, 5, 20, 0)
+	if err != nil {
+		t.Fatalf("NewReplenishmentRule() error = %v", err)
+	}
+	cases, err := fulfillment.NewReplenishmentRule("SKU-1", "A-01-02", "R-01-01", fulfillment.ReplenishMinMax, 5, 20, 12)
+	if err != nil {
+		t.Fatalf("NewReplenishmentRule() error = %v", err)
+	}
+	demand, err := fulfillment.NewReplenishmentRule("SKU-1", "A-01-03", "R-01-01", fulfillment.ReplenishDemand, 2, 0, 0)
+	if err != nil {
+		t.Fatalf("NewReplenishmentRule() error = %v", err)
+	}
+
+	tests := []struct {
+		name                       string
+		rule                       *fulfillment.ReplenishmentRule
+		onHand, inTransit, dem     int
+		wantQuantity, wantPriority int
+	}{
+		{"above min", minMax, 8, 0, 0, 0, 0},
+		{"at min tops off to max", minMax, 5, 0, 0, 15, fulfillment.ReplenishPriorityTopOff},
+		{"in transit counts", minMax, 2, 10, 0, 0, 0},
+		{"demand above stock", minMax, 8, 0, 10, 12, fulfillment.ReplenishPriorityShortPick},
+		{"rounds up to full cases", cases, 4, 0, 3, 24, fulfillment.ReplenishPriorityDemand},
+		{"demand plus safety", demand, 3, 0, 7, 6, fulfillment.ReplenishPriorityShortPick},
+		{"demand covered", demand, 10, 0, 7, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quantity, priority := tt.rule.Evaluate(tt.onHand, tt.inTransit, tt.dem)
+			if quantity != tt.wantQuantity || priority != tt.wantPriority {
+				t.Errorf("Evaluate() = (%d, %d), want (%d, %d)", quantity, priority, tt.wantQuantity, tt.wantPriority)
+			}
+		})
+	}
+
+	if _, err := fulfillment.NewReplenishmentRule("SKU-1", "A-01-01", "A-01-01", fulfillment.ReplenishMinMax, 5, 20, 0); !errors.Is(err, fulfillment.ErrInvalidReplenishmentRule) {
+		t.Errorf("same pick and reserve location error = %v, want ErrInvalidReplenishmentRule", err)
+	}
+}
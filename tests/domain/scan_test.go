@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+func gtinHierarchy(t *testing.T) *fulfillment.UoMHierarchy {
+	t.Helper()
+	hierarchy, err := fulfillment.NewUoMHierarchy("SKU-001", []fulfillment.PackLevel{
+		{UoM: fulfillment.UoMCase, Factor: 12, GTIN: "14006381333938"},
+	})
+	if err != nil {
+		t.Fatalf("NewUoMHierarchy() error = %v", err)
+	}
+	hierarchy.GTIN = "4006381333931"
+	if err := hierarchy.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return hierarchy
+}
+
+func TestUoMHierarchyGTINs(t *testing.T) {
+	hierarchy := gtinHierarchy(t)
+	if hierarchy.GTIN != "04006381333931" {
+		t.Errorf("GTIN = %q, want normalized to 14 digits", hierarchy.GTIN)
+	}
+	if uom, ok := hierarchy.UoMForGTIN("14006381333938"); !ok || uom != fulfillment.UoMCase {
+		t.Errorf("UoMForGTIN(case) = %q, %v", uom, ok)
+	}
+
+	tests := []struct {
+		name   string
+		base   string
+		levels []fulfillment.PackLevel
+	}{
+		{"invalid check digit", "4006381333932", nil},
+		{"duplicate gtin", "14006381333938", []fulfillment.PackLevel{{UoM: fulfillment.UoMCase, Factor: 12, GTIN: "14006381333938"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hierarchy := &fulfillment.UoMHierarchy{SKU: "SKU-001", GTIN: tt.base, Levels: tt.levels}
+			if err := hierarchy.Validate(); !errors.Is(err, fulfillment.ErrInvalidUoMHierarchy) {
+				t.Errorf("Validate() error = %v, want ErrInvalidUoMHierarchy", err)
+			}
+		})
+	}
+}
+
+func TestItemFromBarcode(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		raw      string
+		uom      string
+		quantity int
+		batch    string
+		serial   string
+		expires  string
+		wantErr  error
+	}{
+		{"each with expiry and serial", "0104006381333931" + "17270331" + "10L1\x1d" + "21S9", "", 1, "L1", "S9", "2027-03-31", nil},
+		{"case with count and best before", "(02)14006381333938(37)3(15)270100", fulfillment.UoMCase, 3, "", "", "2027-01-31", nil},
+		{"unregistered gtin", "0107891234567895", "", 0, "", "", "", fulfillment.ErrUnknownGTIN},
+		{"sscc only", "00106141411234567897", "", 0, "", "", "", fulfillment.ErrNoProductCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barcode, err := gs1.ParseAt(tt.raw, now)
+			if err != nil {
+				t.Fatalf("ParseAt() error = %v", err)
+			}
+			item, err := fulfillment.ItemFromBarcode(barcode, gtinHierarchy(t))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ItemFromBarcode() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if item.SKU != "SKU-001" || item.UoM != tt.uom || item.Quantity != tt.quantity || item.Batch != tt.batch || item.Serial != tt.serial {
+				t.Errorf("ItemFromBarcode() = %+v", item)
+			}
+			if item.ExpiresAt == nil || item.ExpiresAt.Format(time.DateOnly) != tt.expires {
+				t.Errorf("ExpiresAt = %v, want %s", item.ExpiresAt, tt.expires)
+			}
+		})
+	}
+}
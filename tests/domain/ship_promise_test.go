@@ -0,0 +1,201 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// saoPaulo é o fuso dos calendários de teste (UTC-3, sem horário de verão)
+var saoPaulo = time.FixedZone("BRT", -3*60*60)
+
+func brt(day, hour, minute int) time.Time {
+	return time.Date(2026, time.October, day, hour, minute, 0, 0, saoPaulo)
+}
+
+// weekdayCalendar coleta às 17:00 de segunda a sexta e às 12:00 no sábado; 20/10 é feriado
+func weekdayCalendar(t *testing.T) *fulfillment.CutoffCalendar {
+	t.Helper()
+	cutoffs := []fulfillment.CarrierCutoff{{Weekday: time.Saturday, Time: "12:00"}}
+	for day := time.Monday; day <= time.Friday; day++ {
+		cutoffs = append(cutoffs, fulfillment.CarrierCutoff{Weekday: day, Time: "17:00"})
+	}
+	calendar, err := fulfillment.NewCutoffCalendar("CORREIOS", "", cutoffs, []string{"2026-10-20"}, "America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("NewCutoffCalendar() error = %v", err)
+	}
+	return calendar
+}
+
+func TestCutoffCalendarValidate(t *testing.T) {
+	valid := []fulfillment.CarrierCutoff{{Weekday: time.Monday, Time: "17:00"}}
+	tests := []struct {
+		name     string
+		carrier  string
+		cutoffs  []fulfillment.CarrierCutoff
+		holidays []string
+		timezone string
+		wantErr  error
+	}{
+		{"valid", "CORREIOS", valid, []string{"2026-12-25"}, "America/Sao_Paulo", nil},
+		{"missing carrier", "", valid, nil, "", fulfillment.ErrInvalidCutoffCalendar},
+		{"no cutoffs", "CORREIOS", nil, nil, "", fulfillment.ErrInvalidCutoffCalendar},
+		{"invalid time", "CORREIOS", []fulfillment.CarrierCutoff{{Weekday: time.Monday, Time: "25:00"}}, nil, "", fulfillment.ErrInvalidCutoffCalendar},
+		{"invalid weekday", "CORREIOS", []fulfillment.CarrierCutoff{{Weekday: 7, Time: "17:00"}}, nil, "", fulfillment.ErrInvalidCutoffCalendar},
+		{"invalid holiday", "CORREIOS", valid, []string{"25/12/2026"}, "", fulfillment.ErrInvalidCutoffCalendar},
+		{"unknown timezone", "CORREIOS", valid, nil, "Mars/Olympus", fulfillment.ErrInvalidCutoffCalendar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fulfillment.NewCutoffCalendar(tt.carrier, "", tt.cutoffs, tt.holidays, tt.timezone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewCutoffCalendar() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCutoffCalendarNextAndLastCutoff(t *testing.T) {
+	calendar := weekdayCalendar(t)
+	tests := []struct {
+		name string
+		find func(time.Time) (time.Time, bool)
+		at   time.Time
+		want time.Time
+	}{
+		{"next same day", calendar.NextCutoff, brt(19, 10, 0), brt(19, 17, 0)},
+		{"next at the cutoff", calendar.NextCutoff, brt(19, 17, 0), brt(19, 17, 0)},
+		{"next skips holiday", calendar.NextCutoff, brt(19, 18, 0), brt(21, 17, 0)},
+		{"next saturday", calendar.NextCutoff, brt(23, 17, 30), brt(24, 12, 0)},
+		{"next from utc", calendar.NextCutoff, time.Date(2026, time.October, 19, 19, 0, 0, 0, time.UTC), brt(19, 17, 0)},
+		{"last at the cutoff", calendar.LastCutoff, brt(19, 17, 0), brt(19, 17, 0)},
+		{"last skips holiday", calendar.LastCutoff, brt(21, 10, 0), brt(19, 17, 0)},
+		{"last sunday", calendar.LastCutoff, brt(25, 9, 0), brt(24, 12, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.find(tt.at)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("cutoff(%v) = %v, %v, want %v", tt.at, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestShipPromiseValidate(t *testing.T) {
+	shipBy, deliverBy := brt(19, 17, 0), brt(21, 18, 0)
+	tests := []struct {
+		name    string
+		promise fulfillment.ShipPromise
+		wantErr error
+	}{
+		{"empty", fulfillment.ShipPromise{}, nil},
+		{"valid", fulfillment.ShipPromise{ShipBy: &shipBy, DeliverBy: &deliverBy, Carrier: "CORREIOS", Service: "SEDEX", CustomerTier: fulfillment.TierPremium}, nil},
+		{"unknown tier", fulfillment.ShipPromise{CustomerTier: "GOLD"}, fulfillment.ErrInvalidShipPromise},
+		{"deliver before ship", fulfillment.ShipPromise{ShipBy: &deliverBy, DeliverBy: &shipBy}, fulfillment.ErrInvalidShipPromise},
+		{"service without carrier", fulfillment.ShipPromise{Service: "SEDEX"}, fulfillment.ErrInvalidShipPromise},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.promise.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectCutoffCalendar(t *testing.T) {
+	general := &fulfillment.CutoffCalendar{ID: "general", Carrier: "CORREIOS"}
+	sedex := &fulfillment.CutoffCalendar{ID: "sedex", Carrier: "CORREIOS", Service: "SEDEX"}
+	other := &fulfillment.CutoffCalendar{ID: "other", Carrier: "JADLOG"}
+	calendars := []*fulfillment.CutoffCalendar{sedex, general, other}
+
+	tests := []struct {
+		carrier, service string
+		want             *fulfillment.CutoffCalendar
+	}{
+		{"CORREIOS", "SEDEX", sedex},
+		{"CORREIOS", "PAC", general},
+		{"CORREIOS", "", general},
+		{"JADLOG", "EXPRESSO", other},
+		{"LOGGI", "", nil},
+	}
+	for _, tt := range tests {
+		if got := fulfillment.SelectCutoffCalendar(calendars, tt.carrier, tt.service); got != tt.want {
+			t.Errorf("SelectCutoffCalendar(%s, %s) = %v, want %v", tt.carrier, tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestScoreOrder(t *testing.T) {
+	now := brt(19, 10, 0)
+	lead := 30 * time.Minute
+	calendar := weekdayCalendar(t)
+	order := func(priority int, tier fulfillment.CustomerTier, shipBy *time.Time) *fulfillment.FulfillmentOrder {
+		return &fulfillment.FulfillmentOrder{ID: "FO-1", Priority: priority, ShipPromise: fulfillment.ShipPromise{ShipBy: shipBy, CustomerTier: tier}}
+	}
+	at := func(tm time.Time) *time.Time { return &tm }
+
+	tests := []struct {
+		name         string
+		order        *fulfillment.FulfillmentOrder
+		calendar     *fulfillment.CutoffCalendar
+		wantRisk     fulfillment.SLARisk
+		wantScore    int
+		wantDeadline *time.Time
+	}{
+		{"no promise", order(0, "", nil), calendar, fulfillment.RiskNoPromise, 0, nil},
+		{"no promise key account", order(1, fulfillment.TierKeyAccount, nil), nil, fulfillment.RiskNoPromise, 200, nil},
+		{"on track until today's cutoff", order(0, "", at(brt(19, 23, 0))), calendar, fulfillment.RiskOnTrack, 200 - 6*8, at(brt(19, 17, 0))},
+		{"on track far away", order(0, "", at(brt(23, 18, 0))), calendar, fulfillment.RiskOnTrack, 0, at(brt(23, 17, 0))},
+		{"at risk premium", order(0, fulfillment.TierPremium, at(brt(19, 11, 0))), nil, fulfillment.RiskAtRisk, 250 + 50, at(brt(19, 11, 0))},
+		{"will miss", order(0, "", at(brt(19, 10, 15))), nil, fulfillment.RiskWillMiss, 350, at(brt(19, 10, 15))},
+		{"late express", order(1, "", at(brt(19, 9, 0))), nil, fulfillment.RiskLate, 500, at(brt(19, 9, 0))},
+		{"last cutoff before ship-by already gone", order(0, "", at(brt(19, 12, 0))), calendar, fulfillment.RiskLate, 400, at(brt(17, 12, 0))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fulfillment.ScoreOrder(tt.order, tt.calendar, lead, now)
+			if got.Risk != tt.wantRisk || got.Score != tt.wantScore {
+				t.Errorf("ScoreOrder() = %s/%d, want %s/%d", got.Risk, got.Score, tt.wantRisk, tt.wantScore)
+			}
+			if (got.Deadline == nil) != (tt.wantDeadline == nil) || (got.Deadline != nil && !got.Deadline.Equal(*tt.wantDeadline)) {
+				t.Errorf("ScoreOrder() deadline = %v, want %v", got.Deadline, tt.wantDeadline)
+			}
+			if got.WillMiss() != (tt.wantRisk == fulfillment.RiskWillMiss || tt.wantRisk == fulfillment.RiskLate) {
+				t.Errorf("WillMiss() = %v for %s", got.WillMiss(), got.Risk)
+			}
+			if got.TaskPriority() != tt.wantScore/fulfillment.PriorityScorePerLevel {
+				t.Errorf("TaskPriority() = %d, want %d", got.TaskPriority(), tt.wantScore/fulfillment.PriorityScorePerLevel)
+			}
+		})
+	}
+
+	// Com calendário, o próximo corte alcançável considera o tempo de processamento
+	got := fulfillment.ScoreOrder(order(0, "", nil), calendar, 8*time.Hour, now)
+	if got.NextCutoff == nil || !got.NextCutoff.Equal(brt(21, 17, 0)) {
+		t.Errorf("ScoreOrder() next cutoff = %v, want %v", got.NextCutoff, brt(21, 17, 0))
+	}
+}
+
+func TestSortOrderPriorities(t *testing.T) {
+	early, late := brt(19, 12, 0), brt(19, 16, 0)
+	created := brt(18, 8, 0)
+	priorities := []fulfillment.OrderPriority{
+		{FulfillmentOrderID: "no-deadline", Score: 300, CreatedAt: created},
+		{FulfillmentOrderID: "late-deadline", Score: 300, Deadline: &late, CreatedAt: created},
+		{FulfillmentOrderID: "low", Score: 100, Deadline: &early, CreatedAt: created},
+		{FulfillmentOrderID: "early-deadline", Score: 300, Deadline: &early, CreatedAt: created.Add(time.Hour)},
+		{FulfillmentOrderID: "top", Score: 450, CreatedAt: created},
+	}
+	fulfillment.SortOrderPriorities(priorities)
+
+	want := []string{"top", "early-deadline", "late-deadline", "no-deadline", "low"}
+	for i, id := range want {
+		if priorities[i].FulfillmentOrderID != id {
+			t.Errorf("position %d = %s, want %s", i, priorities[i].FulfillmentOrderID, id)
+		}
+	}
+}
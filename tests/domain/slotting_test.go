@@ -0,0 +1,130 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// slottingHistory: SKU-A (6 separações) e SKU-D (3) no fundo do corredor, SKU-B (1) ocupando um endereço dourado
+func slottingHistory() []*fulfillment.FulfillmentOrder {
+	var orders []*fulfillment.FulfillmentOrder
+	add := func(sku, location string, picks, quantity int) {
+		for i := 0; i < picks; i++ {
+			orders = append(orders, &fulfillment.FulfillmentOrder{Items: []fulfillment.Item{{SKU: sku, Quantity: quantity, Location: location}}})
+		}
+	}
+	add("SKU-A", "F-1", 6, 2)
+	add("SKU-D", "F-2", 3, 1)
+	add("SKU-B", "G-1", 1, 5)
+	return orders
+}
+
+func slottingLayout(t *testing.T) *fulfillment.WarehouseLayout {
+	t.Helper()
+	layout, err := fulfillment.NewWarehouseLayout(
+		[]fulfillment.Aisle{{ID: "01", X: 0, Start: 0, End: 20}},
+		nil,
+		fulfillment.Point{X: 0, Y: 0},
+		[]fulfillment.BinCoordinate{
+			{Location: "G-1", Aisle: "01", Position: 2, Golden: true},
+			{Location: "G-2", Aisle: "01", Position: 4, Golden: true},
+			{Location: "F-2", Aisle: "01", Position: 16},
+			{Location: "F-1", Aisle: "01", Position: 18},
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewWarehouseLayout() error = %v", err)
+	}
+	return layout
+}
+
+func TestAnalyzeVelocity_ClassifiesByCumulativePicks(t *testing.T) {
+	velocities := fulfillment.AnalyzeVelocity(slottingHistory(), map[string]float64{"SKU-A": 0.01}, 30)
+
+	tests := []struct {
+		sku      string
+		class    fulfillment.VelocityClass
+		picks    int
+		units    int
+		location string
+	}{
+		{"SKU-A", fulfillment.VelocityA, 6, 12, "F-1"},
+		{"SKU-D", fulfillment.VelocityA, 3, 3, "F-2"},
+		{"SKU-B", fulfillment.VelocityB, 1, 5, "G-1"},
+	}
+	if len(velocities) != len(tests) {
+		t.Fatalf("AnalyzeVelocity() returned %d SKUs, want %d", len(velocities), len(tests))
+	}
+	for i, tt := range tests {
+		got := velocities[i]
+		if got.SKU != tt.sku || got.Class != tt.class || got.Picks != tt.picks || got.Units != tt.units || got.Location != tt.location {
+			t.Errorf("velocity[%d] = %+v, want %s class %s picks %d units %d at %s", i, got, tt.sku, tt.class, tt.picks, tt.units, tt.location)
+		}
+	}
+	if velocities[0].PicksPerDay != 0.2 {
+		t.Errorf("PicksPerDay = %v, want 0.2", velocities[0].PicksPerDay)
+	}
+	if velocities[0].CubeMoved != 0.12 {
+		t.Errorf("CubeMoved = %v, want 0.12", velocities[0].CubeMoved)
+	}
+}
+
+func TestRecommendSlotting_FreeGoldenBinThenSwap(t *testing.T) {
+	velocities := fulfillment.AnalyzeVelocity(slottingHistory(), nil, 30)
+	recommendations, err := fulfillment.RecommendSlotting(velocities, slottingLayout(t))
+	if err != nil {
+		t.Fatalf("RecommendSlotting() error = %v", err)
+	}
+
+	tests := []struct {
+		sku, from, to, displaced string
+		savings                  float64
+	}{
+		// Ida e volta: 36m -> 8m em 6 separações
+		{"SKU-A", "F-1", "G-2", "", 168},
+		// Sem endereço dourado livre: troca com SKU-B, descontando as separações dele
+		{"SKU-D", "F-2", "G-1", "SKU-B", 56},
+	}
+	if len(recommendations) != len(tests) {
+		t.Fatalf("RecommendSlotting() returned %d recommendations, want %d", len(recommendations), len(tests))
+	}
+	for i, tt := range tests {
+		got := recommendations[i]
+		if got.SKU != tt.sku || got.FromLocation != tt.from || got.ToLocation != tt.to || got.DisplacedSKU != tt.displaced || got.Savings != tt.savings {
+			t.Errorf("recommendation[%d] = %+v, want %s %s -> %s (displacing %q) saving %v", i, got, tt.sku, tt.from, tt.to, tt.displaced, tt.savings)
+		}
+		if got.Status != fulfillment.SlottingProposed {
+			t.Errorf("recommendation[%d].Status = %s, want PROPOSED", i, got.Status)
+		}
+	}
+}
+
+func TestRecommendSlotting_KeepsGoldenAndUnmappedSKUs(t *testing.T) {
+	velocities := []fulfillment.SKUVelocity{
+		{SKU: "SKU-G", Class: fulfillment.VelocityA, Picks: 9, Location: "G-1"},
+		{SKU: "SKU-X", Class: fulfillment.VelocityA, Picks: 5, Location: "Z-9"},
+		{SKU: "SKU-C", Class: fulfillment.VelocityC, Picks: 1, Location: "F-1"},
+	}
+	recommendations, err := fulfillment.RecommendSlotting(velocities, slottingLayout(t))
+	if err != nil {
+		t.Fatalf("RecommendSlotting() error = %v", err)
+	}
+	if len(recommendations) != 0 {
+		t.Errorf("RecommendSlotting() = %+v, want none", recommendations)
+	}
+}
+
+func TestSlottingRecommendation_Decisions(t *testing.T) {
+	rec := &fulfillment.SlottingRecommendation{Status: fulfillment.SlottingProposed}
+	if err := rec.Approve([]string{"t-1"}); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if rec.Status != fulfillment.SlottingApproved || len(rec.TransferIDs) != 1 {
+		t.Errorf("after Approve() = %+v", rec)
+	}
+	if err := rec.Reject(); !errors.Is(err, fulfillment.ErrSlottingNotProposed) {
+		t.Errorf("Reject() after approval error = %v, want ErrSlottingNotProposed", err)
+	}
+}
@@ -0,0 +1,177 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestStateMachine_EntitiesFollowMachine(t *testing.T) {
+	order, _ := fulfillment.NewFulfillmentOrder("ORD-1", "c", "d", []fulfillment.Item{{SKU: "A", Quantity: 1}}, 0)
+
+	if err := order.Ship(); !errors.Is(err, fulfillment.ErrInvalidStateTransition) {
+		t.Errorf("Ship() from PENDING error = %v, want ErrInvalidStateTransition", err)
+	}
+	if err := order.StartPicking(); err != nil {
+		t.Fatalf("StartPicking() error = %v", err)
+	}
+	if err := order.Fail(fulfillment.StepConfirmReservation, 0, errors.New("boom")); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+
+	// FAILED -> PENDING é permitido pela máquina (retry dentro do SLA)
+	if !fulfillment.ValidateStateTransition(fulfillment.StatusFailed, fulfillment.StatusPending) {
+		t.Error("FAILED -> PENDING should be allowed")
+	}
+	if err := fulfillment.Fire(order, fulfillment.EventRetry); err != nil || order.Status != fulfillment.StatusPending {
+		t.Errorf("retry error = %v, status = %s", err, order.Status)
+	}
+
+	if err := order.Cancel(); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if err := order.Cancel(); !errors.Is(err, fulfillment.ErrInvalidStateTransition) {
+		t.Errorf("Cancel() twice error = %v, want ErrInvalidStateTransition", err)
+	}
+}
+
+func TestStateMachine_Guards(t *testing.T) {
+	order, _ := fulfillment.NewFulfillmentOrder("ORD-2", "c", "d", []fulfillment.Item{{SKU: "A", Quantity: 1}}, 0)
+	order.ReservationStatus = fulfillment.ReservationBackordered
+
+	err := order.StartPicking()
+	if !errors.Is(err, fulfillment.ErrGuardRejected) || !errors.Is(err, fulfillment.ErrOrderBackordered) {
+		t.Errorf("StartPicking() error = %v, want backorder guard rejection", err)
+	}
+	if order.Status != fulfillment.StatusPending {
+		t.Errorf("status changed to %s after rejected transition", order.Status)
+	}
+
+	// Retry manual não depende do SLA; o retry automático só é aceito dentro dele
+	task, _ := fulfillment.NewCycleCountTask("A-01", []string{"SKU-1"})
+	task.CreatedAt = time.Now().Add(-48 * time.Hour)
+	task.Status = fulfillment.StatusFailed
+	err = fulfillment.Fire(task, fulfillment.EventAutoRetry)
+	if !errors.Is(err, fulfillment.ErrGuardRejected) || !errors.Is(err, fulfillment.ErrSLAExceeded) {
+		t.Errorf("auto_retry past SLA error = %v, want SLA guard rejection", err)
+	}
+	if task.Status != fulfillment.StatusFailed {
+		t.Errorf("status changed to %s after rejected auto_retry", task.Status)
+	}
+	if err := task.Retry(); err != nil || task.Status != fulfillment.StatusPending {
+		t.Errorf("Retry() error = %v, status = %s", err, task.Status)
+	}
+
+	recent, _ := fulfillment.NewCycleCountTask("A-02", []string{"SKU-1"})
+	recent.Status = fulfillment.StatusFailed
+	if err := fulfillment.Fire(recent, fulfillment.EventAutoRetry); err != nil || recent.Status != fulfillment.StatusPending {
+		t.Errorf("auto_retry within SLA error = %v, status = %s", err, recent.Status)
+	}
+}
+
+func TestStateMachine_HoldGuardRejectsUnblock(t *testing.T) {
+	order, _ := fulfillment.NewFulfillmentOrder("ORD-4", "c", "d", []fulfillment.Item{{SKU: "A", Quantity: 1}}, 0)
+	if err := order.Block(fulfillment.BlockCustomsHold, "DI pendente", "supervisor"); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	if !order.HasActiveHolds() {
+		t.Fatal("HasActiveHolds() = false for a customs hold")
+	}
+
+	err := order.Unblock()
+	if !errors.Is(err, fulfillment.ErrGuardRejected) || !errors.Is(err, fulfillment.ErrActiveHolds) {
+		t.Errorf("Unblock() error = %v, want hold guard rejection", err)
+	}
+	if order.Status != fulfillment.StatusBlocked {
+		t.Errorf("status changed to %s after rejected unblock", order.Status)
+	}
+
+	if err := order.ReleaseHold("alfandega"); err != nil {
+		t.Fatalf("ReleaseHold() error = %v", err)
+	}
+	if err := order.ReleaseHold("alfandega"); !errors.Is(err, fulfillment.ErrNoActiveHold) {
+		t.Errorf("ReleaseHold() twice error = %v, want ErrNoActiveHold", err)
+	}
+	if err := order.Unblock(); err != nil || order.Status != fulfillment.StatusPending {
+		t.Errorf("Unblock() after release error = %v, status = %s", err, order.Status)
+	}
+
+	// Bloqueios que não são retenção dispensam a liberação
+	transfer, _ := fulfillment.NewTransferOrder("A-01", "B-01", []fulfillment.Item{{SKU: "A", Quantity: 1}})
+	if err := transfer.Block(fulfillment.BlockDamagedLocation, "", "operador"); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	if err := transfer.ReleaseHold("operador"); !errors.Is(err, fulfillment.ErrNoActiveHold) {
+		t.Errorf("ReleaseHold() error = %v, want ErrNoActiveHold", err)
+	}
+	if err := transfer.Unblock(); err != nil {
+		t.Errorf("Unblock() error = %v", err)
+	}
+}
+
+func TestStateMachine_PackingGuardRejectsShip(t *testing.T) {
+	shipment := fulfillment.NewOutboundShipment("FO-1", "BR123", "CORREIOS")
+	err := shipment.Ship()
+	if !errors.Is(err, fulfillment.ErrGuardRejected) || !errors.Is(err, fulfillment.ErrPackingIncomplete) {
+		t.Errorf("Ship() without pieces error = %v, want packing guard rejection", err)
+	}
+	if shipment.ShippedAt != nil {
+		t.Error("ShippedAt set after rejected ship")
+	}
+
+	shipment.Pieces = 2
+	if err := shipment.Ship(); err != nil || shipment.Status != fulfillment.StatusCompleted {
+		t.Errorf("Ship() error = %v, status = %s", err, shipment.Status)
+	}
+}
+
+func TestStateMachine_HooksAndDiagrams(t *testing.T) {
+	machine, err := fulfillment.StateMachineFor(fulfillment.OpReturn)
+	if err != nil {
+		t.Fatalf("StateMachineFor() error = %v", err)
+	}
+
+	var seen []string
+	remove := machine.After(func(tc fulfillment.TransitionContext) {
+		seen = append(seen, string(tc.From)+">"+string(tc.To))
+	})
+	defer remove()
+
+	ret, _ := fulfillment.NewReturnOrder("ORD-3", "damaged", []fulfillment.Item{{SKU: "A", Quantity: 1}})
+	if err := ret.StartProcessing(); err != nil {
+		t.Fatalf("StartProcessing() error = %v", err)
+	}
+	if err := ret.Complete(); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(seen) != 2 || seen[1] != "IN_PROGRESS>COMPLETED" || ret.CompletedAt == nil {
+		t.Errorf("after hooks saw %v, completed_at %v", seen, ret.CompletedAt)
+	}
+
+	// Hook removido não é mais chamado (as máquinas são globais)
+	remove()
+	other, _ := fulfillment.NewReturnOrder("ORD-5", "damaged", []fulfillment.Item{{SKU: "A", Quantity: 1}})
+	if err := other.StartProcessing(); err != nil {
+		t.Fatalf("StartProcessing() error = %v", err)
+	}
+	if len(seen) != 2 {
+		t.Errorf("removed hook still called: %v", seen)
+	}
+
+	mermaid := machine.Mermaid()
+	for _, edge := range []string{
+		"IN_PROGRESS --> COMPLETED: complete [no_active_holds]",
+		"FAILED --> PENDING: retry\n",
+		"FAILED --> PENDING: auto_retry [within_sla]",
+	} {
+		if !strings.Contains(mermaid, edge) {
+			t.Errorf("Mermaid() missing edge %q:\n%s", edge, mermaid)
+		}
+	}
+	if dot := machine.DOT(); !strings.Contains(dot, `"PENDING" -> "IN_PROGRESS" [label="start"]`) {
+		t.Errorf("DOT() missing start edge:\n%s", dot)
+	}
+}
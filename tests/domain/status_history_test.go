@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestNewStatusTransition_CapturesContext(t *testing.T) {
+	ctx := fulfillment.WithCorrelationID(context.Background(), "corr-1")
+	ctx = fulfillment.WithActor(ctx, "operador-42")
+	ctx = fulfillment.WithSource(ctx, fulfillment.SourceHTTP)
+	ctx = fulfillment.WithReason(ctx, "cliente desistiu")
+
+	tr := fulfillment.NewStatusTransition(ctx, fulfillment.EntityFulfillmentOrder, "ord-1", fulfillment.StatusPending, fulfillment.StatusCancelled)
+
+	if tr.Actor != "operador-42" || tr.Source != fulfillment.SourceHTTP || tr.Reason != "cliente desistiu" || tr.CorrelationID != "corr-1" {
+		t.Errorf("context not captured: %+v", tr)
+	}
+	if tr.OccurredAt.IsZero() {
+		t.Error("OccurredAt should be set")
+	}
+}
+
+func TestStatusDwell(t *testing.T) {
+	start := time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	transitions := []fulfillment.StatusTransition{
+		{ToStatus: fulfillment.StatusPending, OccurredAt: at(0)},
+		{FromStatus: fulfillment.StatusPending, ToStatus: fulfillment.StatusInProgress, OccurredAt: at(10)},
+		{FromStatus: fulfillment.StatusInProgress, ToStatus: fulfillment.StatusBlocked, OccurredAt: at(25)},
+		{FromStatus: fulfillment.StatusBlocked, ToStatus: fulfillment.StatusInProgress, OccurredAt: at(30)},
+	}
+
+	dwell := fulfillment.StatusDwell(transitions, at(40))
+
+	if dwell[fulfillment.StatusPending] != 10*time.Minute {
+		t.Errorf("PENDING dwell = %v, want 10m", dwell[fulfillment.StatusPending])
+	}
+	if dwell[fulfillment.StatusInProgress] != 25*time.Minute {
+		t.Errorf("IN_PROGRESS dwell = %v, want 25m (15m + 10m corrente)", dwell[fulfillment.StatusInProgress])
+	}
+	if dwell[fulfillment.StatusBlocked] != 5*time.Minute {
+		t.Errorf("BLOCKED dwell = %v, want 5m", dwell[fulfillment.StatusBlocked])
+	}
+
+	completed := append(transitions, fulfillment.StatusTransition{
+		FromStatus: fulfillment.StatusInProgress, ToStatus: fulfillment.StatusCompleted, OccurredAt: at(40),
+	})
+	dwell = fulfillment.StatusDwell(completed, at(120))
+	if _, ok := dwell[fulfillment.StatusCompleted]; ok {
+		t.Error("terminal status should not accumulate dwell time")
+	}
+	if dwell[fulfillment.StatusInProgress] != 25*time.Minute {
+		t.Errorf("IN_PROGRESS dwell = %v, want 25m", dwell[fulfillment.StatusInProgress])
+	}
+}
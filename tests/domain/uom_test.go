@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestNewUoMHierarchy_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []fulfillment.PackLevel
+	}{
+		{"not a multiple", []fulfillment.PackLevel{{UoM: "IN", Factor: 5}, {UoM: "CS", Factor: 12}}},
+		{"duplicate", []fulfillment.PackLevel{{UoM: "CS", Factor: 12}, {UoM: "CS", Factor: 24}}},
+		{"base redefined", []fulfillment.PackLevel{{UoM: "EA", Factor: 2}}},
+		{"non-positive", []fulfillment.PackLevel{{UoM: "CS", Factor: 0}}},
+		{"empty uom", []fulfillment.PackLevel{{UoM: "", Factor: 12}}},
+	}
+	for _, tt := range tests {
+		if _, err := fulfillment.NewUoMHierarchy("SKU-1", tt.levels); !errors.Is(err, fulfillment.ErrInvalidUoMHierarchy) {
+			t.Errorf("%s: error = %v, want ErrInvalidUoMHierarchy", tt.name, err)
+		}
+	}
+
+	h, err := fulfillment.NewUoMHierarchy("SKU-1", []fulfillment.PackLevel{{UoM: "PL", Factor: 480}, {UoM: "CS", Factor: 12}})
+	if err != nil {
+		t.Fatalf("NewUoMHierarchy() error = %v", err)
+	}
+	if h.Levels[0].UoM != "CS" {
+		t.Errorf("levels = %v, want sorted by factor", h.Levels)
+	}
+}
+
+func TestNormalizeItem(t *testing.T) {
+	h, _ := fulfillment.NewUoMHierarchy("SKU-1", []fulfillment.PackLevel{{UoM: "CS", Factor: 12}})
+
+	tests := []struct {
+		name    string
+		in      fulfillment.Item
+		h       *fulfillment.UoMHierarchy
+		want    fulfillment.Item
+		wantErr error
+	}{
+		{"cases to base", fulfillment.Item{SKU: "SKU-1", Quantity: 10, UoM: "CS"}, h,
+			fulfillment.Item{SKU: "SKU-1", Quantity: 120, UoM: "EA", PackUoM: "CS", PackQuantity: 10}, nil},
+		{"idempotent", fulfillment.Item{SKU: "SKU-1", Quantity: 120, UoM: "EA", PackUoM: "CS", PackQuantity: 10}, h,
+			fulfillment.Item{SKU: "SKU-1", Quantity: 120, UoM: "EA", PackUoM: "CS", PackQuantity: 10}, nil},
+		{"base without uom", fulfillment.Item{SKU: "SKU-1", Quantity: 7}, nil,
+			fulfillment.Item{SKU: "SKU-1", Quantity: 7}, nil},
+		{"partial case", fulfillment.Item{SKU: "SKU-1", Quantity: 13, PackUoM: "CS"}, h, fulfillment.Item{}, fulfillment.ErrUoMNotWholeMultiple},
+		{"unknown uom", fulfillment.Item{SKU: "SKU-1", Quantity: 1, UoM: "PL"}, h, fulfillment.Item{}, fulfillment.ErrUnknownUoM},
+		{"no hierarchy", fulfillment.Item{SKU: "SKU-2", Quantity: 1, UoM: "CS"}, nil, fulfillment.Item{}, fulfillment.ErrUnknownUoM},
+	}
+	for _, tt := range tests {
+		got, err := fulfillment.NormalizeItem(tt.in, tt.h)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: NormalizeItem() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
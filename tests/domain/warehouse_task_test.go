@@ -0,0 +1,77 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestWarehouseTask_Lifecycle(t *testing.T) {
+	task, err := fulfillment.NewWarehouseTask(fulfillment.TaskPick, fulfillment.EntityFulfillmentOrder, "ORD-1", "A-01-03", "", 1)
+	if err != nil {
+		t.Fatalf("NewWarehouseTask() error = %v", err)
+	}
+
+	if err := task.Start("ana"); !errors.Is(err, fulfillment.ErrInvalidStateTransition) {
+		t.Errorf("Start() before accept error = %v, want ErrInvalidStateTransition", err)
+	}
+	if err := task.Accept("ana", "RF-01"); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if err := task.Start("bruno"); !errors.Is(err, fulfillment.ErrTaskAssignedToOther) {
+		t.Errorf("Start() by other operator error = %v, want ErrTaskAssignedToOther", err)
+	}
+	if err := task.Start("ana"); err != nil || task.StartedAt == nil {
+		t.Fatalf("Start() error = %v, started_at = %v", err, task.StartedAt)
+	}
+
+	if err := task.Abandon("ana"); err != nil {
+		t.Fatalf("Abandon() error = %v", err)
+	}
+	if task.Status != fulfillment.StatusPending || task.AssignedTo != "" || task.StartedAt != nil {
+		t.Errorf("abandoned task = %s assigned to %q, want PENDING without assignee", task.Status, task.AssignedTo)
+	}
+
+	if _, err := fulfillment.NewWarehouseTask("DANCE", "", "", "", "", 0); !errors.Is(err, fulfillment.ErrInvalidTaskType) {
+		t.Errorf("NewWarehouseTask() error = %v, want ErrInvalidTaskType", err)
+	}
+}
+
+func TestRankTasks_PrefersNearbyAndInterleaves(t *testing.T) {
+	now := time.Now()
+	newTask := func(taskType fulfillment.TaskType, source string, priority int) *fulfillment.WarehouseTask {
+		task, err := fulfillment.NewWarehouseTask(taskType, "", "", source, "", priority)
+		if err != nil {
+			t.Fatalf("NewWarehouseTask() error = %v", err)
+		}
+		return task
+	}
+
+	near := newTask(fulfillment.TaskPick, "A-01-04", 0)
+	far := newTask(fulfillment.TaskPick, "A-05-10", 0)
+	otherZone := newTask(fulfillment.TaskPick, "B-01-01", 0)
+	urgent := newTask(fulfillment.TaskPick, "B-09-01", 3)
+
+	ranked := fulfillment.RankTasks([]*fulfillment.WarehouseTask{otherZone, far, near, urgent}, "A-01-01", "", now)
+	got := []string{ranked[0].Task.SourceLocation, ranked[1].Task.SourceLocation, ranked[2].Task.SourceLocation, ranked[3].Task.SourceLocation}
+	want := []string{"B-09-01", "A-01-04", "A-05-10", "B-01-01"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("RankTasks() order = %v, want %v", got, want)
+		}
+	}
+
+	// Mesma distância: intercalação favorece outro tipo de trabalho
+	pick := newTask(fulfillment.TaskPick, "A-01-02", 0)
+	count := newTask(fulfillment.TaskCount, "A-01-02", 0)
+	ranked = fulfillment.RankTasks([]*fulfillment.WarehouseTask{pick, count}, "A-01-01", fulfillment.TaskPick, now)
+	if ranked[0].Task.Type != fulfillment.TaskCount {
+		t.Errorf("RankTasks() first = %s, want COUNT after PICK", ranked[0].Task.Type)
+	}
+
+	if d := fulfillment.TravelDistance("A-01-01", "A-03-02"); d != 43 {
+		t.Errorf("TravelDistance() = %d, want 43", d)
+	}
+}
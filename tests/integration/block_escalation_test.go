@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// recordingNotifier registra as notificações de escalonamento
+type recordingNotifier struct {
+	mu        sync.Mutex
+	escalated []fulfillment.BlockedEntity
+}
+
+func (n *recordingNotifier) PublishBlockEscalated(ctx context.Context, entity fulfillment.BlockedEntity) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.escalated = append(n.escalated, entity)
+	return nil
+}
+
+func TestBlock_UnblockRestoresPreviousStatus(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "supervisor")
+	repo := newMemoryRepository()
+	uc := app.NewBlockUseCase(repo, repo, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	shipment, err := fulfillment.NewInboundShipment("ASN-1", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateInbound(ctx, shipment))
+
+	require.NoError(t, uc.Block(ctx, fulfillment.EntityInboundShipment, shipment.ID, fulfillment.BlockCustomsHold, "DI pendente"))
+
+	blocked, err := repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusBlocked, blocked.Status)
+	require.NotNil(t, blocked.Blocked)
+	assert.Equal(t, "supervisor", blocked.Blocked.BlockedBy)
+	assert.False(t, blocked.Status.IsWorkable())
+
+	// A retenção alfandegária precisa ser liberada antes do desbloqueio
+	err = uc.Unblock(ctx, fulfillment.EntityInboundShipment, shipment.ID, "")
+	assert.ErrorIs(t, err, fulfillment.ErrActiveHolds)
+	require.NoError(t, uc.ReleaseHold(fulfillment.WithActor(ctx, "alfandega"), fulfillment.EntityInboundShipment, shipment.ID))
+	assert.ErrorIs(t, uc.ReleaseHold(ctx, fulfillment.EntityInboundShipment, shipment.ID), fulfillment.ErrNoActiveHold)
+	released, err := repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusBlocked, released.Status)
+	assert.Equal(t, "alfandega", released.Blocked.ReleasedBy)
+
+	require.NoError(t, uc.Unblock(ctx, fulfillment.EntityInboundShipment, shipment.ID, "liberado"))
+	restored, err := repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusPending, restored.Status)
+	assert.Nil(t, restored.Blocked)
+
+	err = uc.Unblock(ctx, fulfillment.EntityInboundShipment, shipment.ID, "")
+	assert.ErrorIs(t, err, app.ErrNotBlocked)
+}
+
+func TestBlockEscalator_NotifiesOncePastThreshold(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	notifier := &recordingNotifier{}
+	escalator := app.NewBlockEscalator(repo, notifier, time.Hour, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	shipment, err := fulfillment.NewInboundShipment("ASN-2", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}})
+	require.NoError(t, err)
+	require.NoError(t, shipment.Block(fulfillment.BlockDamagedLocation, "", "operador"))
+	require.NoError(t, repo.CreateInbound(ctx, shipment))
+
+	// Ainda dentro do limite
+	escalated, err := escalator.Escalate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, escalated)
+
+	stale, err := repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	stale.Blocked.BlockedAt = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, repo.UpdateInbound(ctx, stale))
+
+	escalated, err = escalator.Escalate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, escalated)
+	require.Len(t, notifier.escalated, 1)
+	assert.Equal(t, fulfillment.BlockDamagedLocation, notifier.escalated[0].Block.Reason)
+
+	// Já escalonado: não notifica novamente
+	escalated, err = escalator.Escalate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, escalated)
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestCountPlanner_DailyPlanWithTriggers(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	open := app.NewOpenCycleCountUseCase(f.repo, &noopPublisher{}, appLogger)
+	submit := app.NewSubmitCycleCountUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	planner := app.NewCountPlannerUseCase(f.repo, f.repo, f.repo, f.repo, f.client, open, appLogger)
+	f.tasks.AfterComplete(planner.OnTaskCompleted)
+	submit.AfterVariance(planner.OnVariance)
+
+	program, err := planner.GetProgram(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 30, program.Frequencies[fulfillment.VelocityA], "default program applies until one is defined")
+
+	f.responder.SetStock("A-01", "SKU-001", 2)
+	f.responder.SetStock("B-01", "SKU-002", 5)
+
+	// Separação que zera o endereço gera gatilho de contagem
+	emptied, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2, Location: "A-01"}}, 0)
+	require.NoError(t, err)
+	pick, err := f.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, emptied.ID, -1)
+	require.NoError(t, err)
+	_, err = f.tasks.Accept(ctx, pick.ID, "RF-01")
+	require.NoError(t, err)
+	_, err = f.tasks.Start(ctx, pick.ID)
+	require.NoError(t, err)
+	_, err = f.tasks.Complete(ctx, pick.ID, nil)
+	require.NoError(t, err)
+
+	partial, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua B", []fulfillment.Item{{SKU: "SKU-002", Quantity: 1, Location: "B-01"}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, f.ship.StartPicking(ctx, partial.ID))
+	require.NoError(t, f.ship.Ship(ctx, partial.ID))
+
+	triggers, err := planner.ListTriggers(ctx)
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+	assert.Equal(t, fulfillment.CountZeroStockPick, triggers[0].Reason)
+	assert.Equal(t, "A-01", triggers[0].Location)
+
+	// Gatilho fora da cota; a cota diária (1) leva o endereço nunca contado restante
+	plan, err := planner.PlanDay(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Quota)
+	require.Len(t, plan.Entries, 2)
+	assert.Equal(t, fulfillment.CountZeroStockPick, plan.Entries[0].Reason)
+	assert.Equal(t, "A-01", plan.Entries[0].Location)
+	assert.Equal(t, fulfillment.CountScheduled, plan.Entries[1].Reason)
+	assert.Equal(t, "B-01", plan.Entries[1].Location)
+	for _, entry := range plan.Entries {
+		assert.NotEmpty(t, entry.TaskID)
+	}
+	triggers, err = planner.ListTriggers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, triggers)
+
+	// Repetir no mesmo dia não gera contagens novas
+	again, err := planner.PlanDay(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, again.Entries)
+
+	// Divergência na contagem pede recontagem no próximo plano, mesmo com a cota esgotada
+	require.NoError(t, submit.SubmitCycleCount(ctx, plan.Entries[1].TaskID, []fulfillment.Item{{SKU: "SKU-002", Quantity: 3}}))
+	assert.Equal(t, 3, f.responder.Stock("B-01", "SKU-002"))
+
+	recount, err := planner.PlanDay(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, recount.Entries, 1)
+	assert.Equal(t, "B-01", recount.Entries[0].Location)
+	assert.Equal(t, fulfillment.CountRecentVariance, recount.Entries[0].Reason)
+}
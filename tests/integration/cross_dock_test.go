@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func newCrossDockFixture(f *taskFixture) *app.CrossDockUseCase {
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfers := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	f.tasks = app.NewWarehouseTaskUseCase(f.repo, f.repo, f.receive, f.ship, transfers, nil, appLogger)
+
+	crossDock := app.NewCrossDockUseCase(f.repo, f.repo, transfers, f.ship, f.tasks, appLogger)
+	f.receive.AfterReceipt(crossDock.OnReceived)
+	f.tasks.AfterComplete(crossDock.OnTaskCompleted)
+	return crossDock
+}
+
+func TestCrossDock_ReceiptServesWaitingOrdersByPolicy(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	uc := newCrossDockFixture(f)
+
+	policy, err := fulfillment.NewCrossDockPolicy("ACME", "", "STAGE-01", true, false)
+	require.NoError(t, err)
+	require.NoError(t, uc.DefinePolicy(ctx, policy))
+
+	acme, err := f.ship.CreateOrder(ctx, "OMS-1", "ACME", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 4, Location: "A-01-01"}}, 0)
+	require.NoError(t, err)
+	require.Equal(t, fulfillment.ReservationBackordered, acme.ReservationStatus)
+	other, err := f.ship.CreateOrder(ctx, "OMS-2", "Outro", "Rua B", []fulfillment.Item{{SKU: "SKU-001", Quantity: 3, Location: "A-01-01"}}, 1)
+	require.NoError(t, err)
+
+	shipment, err := f.receive.StartInbound(ctx, "ASN-1", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-001", Quantity: 10}})
+	require.NoError(t, err)
+	require.NoError(t, f.receive.ConfirmReceipt(ctx, shipment.ID))
+
+	// Apenas a ordem do cliente com política é atendida; o restante fica para armazenagem
+	allocations, err := uc.ListAllocations(ctx, shipment.ID)
+	require.NoError(t, err)
+	require.Len(t, allocations, 1)
+	assert.Equal(t, acme.ID, allocations[0].OrderID)
+	assert.Equal(t, 4, allocations[0].Quantity)
+	assert.Equal(t, 10, f.responder.Stock("DOCK-1", "SKU-001"))
+
+	staged, err := f.repo.GetOrderByID(ctx, acme.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "STAGE-01", staged.Items[0].Location)
+
+	task, err := f.repo.GetTaskByEntity(ctx, fulfillment.EntityTransferOrder, allocations[0].TransferID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.TaskCrossDock, task.Type)
+	assert.Equal(t, fulfillment.CrossDockPriorityBoost, task.Priority)
+
+	next, err := f.tasks.NextTask(ctx, "DOCK-1", "RF-01", []fulfillment.TaskType{fulfillment.TaskCrossDock})
+	require.NoError(t, err)
+	require.Equal(t, task.ID, next.ID)
+	_, err = f.tasks.Start(ctx, task.ID)
+	require.NoError(t, err)
+	_, err = f.tasks.Complete(ctx, task.ID, nil)
+	require.NoError(t, err)
+
+	// Mercadoria na área de expedição: a ordem é reservada
+	assert.Equal(t, 6, f.responder.Stock("DOCK-1", "SKU-001"))
+	assert.Equal(t, 4, f.responder.Stock("STAGE-01", "SKU-001"))
+	reserved, err := f.repo.GetOrderByID(ctx, acme.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationReserved, reserved.ReservationStatus)
+	waiting, err := f.repo.GetOrderByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationBackordered, waiting.ReservationStatus)
+}
+
+// failingOrderUpdate falha a gravação das linhas preparadas da ordem
+type failingOrderUpdate struct {
+	*memoryRepository
+}
+
+func (r *failingOrderUpdate) UpdateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	return errors.New("connection reset")
+}
+
+func TestCrossDock_FailedOrderUpdateCancelsTransfer(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	newCrossDockFixture(f)
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfers := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	uc := app.NewCrossDockUseCase(f.repo, &failingOrderUpdate{f.repo}, transfers, f.ship, f.tasks, appLogger)
+
+	policy, err := fulfillment.NewCrossDockPolicy("ACME", "", "STAGE-01", true, false)
+	require.NoError(t, err)
+	require.NoError(t, uc.DefinePolicy(ctx, policy))
+	_, err = f.ship.CreateOrder(ctx, "OMS-1", "ACME", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 4, Location: "A-01-01"}}, 0)
+	require.NoError(t, err)
+	shipment, err := f.receive.StartInbound(ctx, "ASN-1", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-001", Quantity: 10}})
+	require.NoError(t, err)
+
+	allocated, err := uc.Allocate(ctx, shipment)
+	require.NoError(t, err)
+	assert.Empty(t, allocated)
+
+	require.Len(t, f.repo.transfers, 1)
+	for _, transfer := range f.repo.transfers {
+		assert.Equal(t, fulfillment.StatusCancelled, transfer.Status, "transfer without staged order lines must be cancelled")
+	}
+	open, err := f.tasks.ListOpen(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
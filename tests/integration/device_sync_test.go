@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/state/store"
+)
+
+const syncKindBinNote = "BIN_NOTE"
+
+func syncOp(id, device, key, kind string, clock fulfillment.VectorClock, payload map[string]interface{}) fulfillment.SyncOperation {
+	return fulfillment.SyncOperation{ID: id, DeviceID: device, Key: key, Kind: kind, Payload: payload, Clock: clock}
+}
+
+func outcomes(results []fulfillment.SyncResult) []fulfillment.SyncOutcome {
+	got := make([]fulfillment.SyncOutcome, 0, len(results))
+	for _, result := range results {
+		got = append(got, result.Outcome)
+	}
+	return got
+}
+
+func TestDeviceSync_AppliesOfflinePickScans(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f, picks := newPickSessionFixture(t)
+	f.responder.SetStock("A-01-01", "SKU-002", 5)
+	f.responder.SetStock("A-01-03", "SKU-001", 10)
+	sync := app.NewDeviceSyncUseCase(f.repo, store.NewConflictResolver(nil), app.NewZapLoggerAdapter(zap.NewNop()))
+	sync.Handle(app.SyncKindPickScan, picks.ApplySyncedScan)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 4, Location: "A-01-03"},
+		{SKU: "SKU-002", Quantity: 3, Location: "A-01-01"},
+	}, 0)
+	require.NoError(t, err)
+	session, err := picks.Start(ctx, order.ID, "HH-07")
+	require.NoError(t, err)
+
+	scan := func(id string, sequence int, stage string, fields map[string]interface{}) fulfillment.SyncOperation {
+		payload := map[string]interface{}{"session_id": session.ID, "sequence": float64(sequence), "stage": stage}
+		for field, value := range fields {
+			payload[field] = value
+		}
+		key := fmt.Sprintf("pick_session/%s/%d/%s", session.ID, sequence, stage)
+		return syncOp(id, "HH-07", key, app.SyncKindPickScan, fulfillment.VectorClock{"HH-07": 1}, payload)
+	}
+	offline := []fulfillment.SyncOperation{
+		scan("op-1", 1, "location", map[string]interface{}{"location": "A-01-01"}),
+		scan("op-2", 1, "item", map[string]interface{}{"sku": "SKU-002"}),
+		scan("op-3", 1, "quantity", map[string]interface{}{"quantity": float64(3)}),
+		scan("op-4", 2, "location", map[string]interface{}{"location": "A-01-01"}),
+	}
+
+	results, err := sync.Push(ctx, "HH-07", offline)
+	require.NoError(t, err)
+	assert.Equal(t, []fulfillment.SyncOutcome{fulfillment.SyncApplied, fulfillment.SyncApplied, fulfillment.SyncApplied, fulfillment.SyncRejected}, outcomes(results))
+	assert.Contains(t, results[3].Error, fulfillment.ErrWrongLocation.Error())
+	assert.Equal(t, fulfillment.VectorClock{"HH-07": 1}, results[0].Clock)
+
+	session, err = picks.Get(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.PickStepPicked, session.Steps[0].Status)
+	assert.Equal(t, fulfillment.PickStepAwaitingLocation, session.CurrentStep().Status)
+
+	// Reenvio após perda da resposta: operações aplicadas não são repetidas
+	results, err = sync.Push(ctx, "HH-07", offline[:3])
+	require.NoError(t, err)
+	assert.Equal(t, []fulfillment.SyncOutcome{fulfillment.SyncDuplicate, fulfillment.SyncDuplicate, fulfillment.SyncDuplicate}, outcomes(results))
+
+	// Leitura de um passo já concluído é recusada pela sessão
+	results, err = sync.Push(ctx, "HH-07", []fulfillment.SyncOperation{
+		syncOp("op-5", "HH-07", "pick_session/x/1/location", app.SyncKindPickScan, fulfillment.VectorClock{"HH-07": 1},
+			map[string]interface{}{"session_id": session.ID, "sequence": float64(1), "stage": "location", "location": "A-01-01"}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.SyncRejected, results[0].Outcome)
+	assert.Contains(t, results[0].Error, fulfillment.ErrPickStepOutOfSequence.Error())
+
+	// O lote inteiro é recusado se uma operação for inválida
+	_, err = sync.Push(ctx, "HH-07", []fulfillment.SyncOperation{
+		syncOp("op-6", "HH-07", "k", "UNKNOWN", fulfillment.VectorClock{"HH-07": 1}, nil),
+	})
+	assert.ErrorIs(t, err, fulfillment.ErrUnsupportedSyncKind)
+	_, err = sync.Push(ctx, "HH-07", []fulfillment.SyncOperation{
+		syncOp("op-7", "HH-08", "k", app.SyncKindPickScan, fulfillment.VectorClock{"HH-08": 1}, nil),
+	})
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidSyncOperation)
+}
+
+func TestDeviceSync_ConcurrentOperationsMergeOrQueueForReview(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "supervisor-1")
+	repo := newMemoryRepository()
+	sync := app.NewDeviceSyncUseCase(repo, store.NewConflictResolver(nil), app.NewZapLoggerAdapter(zap.NewNop()))
+	var applied []map[string]interface{}
+	sync.Handle(syncKindBinNote, func(ctx context.Context, value map[string]interface{}) error {
+		applied = append(applied, value)
+		return nil
+	})
+
+	const key = "bin/A-01-01"
+	push := func(op fulfillment.SyncOperation) fulfillment.SyncResult {
+		t.Helper()
+		results, err := sync.Push(ctx, op.DeviceID, []fulfillment.SyncOperation{op})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	assert.Equal(t, fulfillment.SyncApplied, push(syncOp("a-1", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 1}, map[string]interface{}{"quantity": 5})).Outcome)
+	assert.Equal(t, fulfillment.SyncApplied, push(syncOp("a-2", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 2}, map[string]interface{}{"quantity": 6})).Outcome)
+	assert.Equal(t, fulfillment.SyncStale, push(syncOp("a-1", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 1}, map[string]interface{}{"quantity": 5})).Outcome)
+
+	// HH-02 viu só a primeira alteração de HH-01: campos diferentes são combinados
+	merged := push(syncOp("b-1", "HH-02", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 1, "HH-02": 1}, map[string]interface{}{"note": "avaria"}))
+	assert.Equal(t, fulfillment.SyncMerged, merged.Outcome)
+	assert.Equal(t, fulfillment.VectorClock{"HH-01": 2, "HH-02": 1}, merged.Clock)
+	assert.Equal(t, map[string]interface{}{"quantity": 6, "note": "avaria"}, applied[len(applied)-1])
+
+	// O mesmo campo alterado nos dois coletores vai para a revisão do supervisor
+	divergent := syncOp("b-2", "HH-02", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 1, "HH-02": 2}, map[string]interface{}{"quantity": 7})
+	first := push(divergent)
+	assert.Equal(t, fulfillment.SyncConflict, first.Outcome)
+	require.NotEmpty(t, first.ConflictID)
+	assert.Equal(t, first.ConflictID, push(divergent).ConflictID, "re-pushed conflicts keep their ID")
+	calls := len(applied)
+
+	pending, err := sync.ListConflicts(ctx, fulfillment.StatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, []string{"quantity"}, pending[0].Fields)
+	assert.Equal(t, 6, pending[0].Server.Value["quantity"])
+
+	_, err = sync.ResolveConflict(ctx, first.ConflictID, "BOTH")
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidSyncChoice)
+	resolved, err := sync.ResolveConflict(ctx, first.ConflictID, fulfillment.SyncTakeDevice)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, resolved.Status)
+	assert.Equal(t, "supervisor-1", resolved.ResolvedBy)
+	require.Len(t, applied, calls+1)
+	assert.Equal(t, map[string]interface{}{"quantity": 7}, applied[calls])
+	_, err = sync.ResolveConflict(ctx, first.ConflictID, fulfillment.SyncKeepServer)
+	assert.ErrorIs(t, err, fulfillment.ErrSyncConflictResolved)
+
+	// Resolvido o conflito, o relógio da chave cobre a operação do coletor
+	assert.Equal(t, fulfillment.SyncDuplicate, push(syncOp("b-3", "HH-02", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 2, "HH-02": 2}, map[string]interface{}{"quantity": 7})).Outcome)
+
+	// SERVER mantém o valor sincronizado sem reaplicar
+	other := push(syncOp("c-1", "HH-03", key, syncKindBinNote, fulfillment.VectorClock{"HH-03": 1}, map[string]interface{}{"quantity": 1}))
+	require.Equal(t, fulfillment.SyncConflict, other.Outcome)
+	calls = len(applied)
+	_, err = sync.ResolveConflict(ctx, other.ConflictID, fulfillment.SyncKeepServer)
+	require.NoError(t, err)
+	assert.Len(t, applied, calls)
+	state, err := repo.GetSyncState(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"quantity": 7}, state.Value)
+	assert.Equal(t, fulfillment.VectorClock{"HH-01": 2, "HH-02": 2, "HH-03": 1}, state.Clock)
+
+	pending, err = sync.ListConflicts(ctx, fulfillment.StatusPending)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+// staleSyncReads devolve o estado lido antes de uma sincronização concorrente
+type staleSyncReads struct {
+	*memoryRepository
+	stale *fulfillment.SyncState
+}
+
+func (r *staleSyncReads) GetSyncState(ctx context.Context, key string) (*fulfillment.SyncState, error) {
+	copied := *r.stale
+	return &copied, nil
+}
+
+func TestDeviceSync_ClaimsKeyBeforeApplying(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	var applied []string
+	handler := func(ctx context.Context, value map[string]interface{}) error {
+		if value["quantity"] == -1 {
+			return fulfillment.ErrInvalidPickQuantity
+		}
+		applied = append(applied, fmt.Sprint(value["quantity"]))
+		return nil
+	}
+	newSync := func(syncs fulfillment.SyncRepository) *app.DeviceSyncUseCase {
+		sync := app.NewDeviceSyncUseCase(syncs, store.NewConflictResolver(nil), app.NewZapLoggerAdapter(zap.NewNop()))
+		sync.Handle(syncKindBinNote, handler)
+		return sync
+	}
+	sync := newSync(repo)
+
+	const key = "bin/A-01-02"
+	_, err := sync.Push(ctx, "HH-01", []fulfillment.SyncOperation{syncOp("a-1", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 1}, map[string]interface{}{"quantity": 5})})
+	require.NoError(t, err)
+	stale, err := repo.GetSyncState(ctx, key)
+	require.NoError(t, err)
+	_, err = sync.Push(ctx, "HH-01", []fulfillment.SyncOperation{syncOp("a-2", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 2}, map[string]interface{}{"quantity": 6})})
+	require.NoError(t, err)
+
+	// A sincronização que leu o estado antes de a-2 perde a gravação e não aplica o efeito
+	_, err = newSync(&staleSyncReads{memoryRepository: repo, stale: stale}).Push(ctx, "HH-01",
+		[]fulfillment.SyncOperation{syncOp("a-3", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 3}, map[string]interface{}{"quantity": 7})})
+	assert.ErrorIs(t, err, fulfillment.ErrSyncStateConflict)
+	assert.Equal(t, []string{"5", "6"}, applied)
+
+	// Recusada a operação, o estado da chave volta ao anterior (ou deixa de existir)
+	results, err := sync.Push(ctx, "HH-01", []fulfillment.SyncOperation{syncOp("a-4", "HH-01", key, syncKindBinNote, fulfillment.VectorClock{"HH-01": 4}, map[string]interface{}{"quantity": -1})})
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.SyncRejected, results[0].Outcome)
+	assert.Equal(t, fulfillment.VectorClock{"HH-01": 2}, results[0].Clock)
+	state, err := repo.GetSyncState(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"quantity": 6}, state.Value)
+	assert.Equal(t, fulfillment.VectorClock{"HH-01": 2}, state.Clock)
+
+	results, err = sync.Push(ctx, "HH-01", []fulfillment.SyncOperation{syncOp("n-1", "HH-01", "bin/A-01-03", syncKindBinNote, fulfillment.VectorClock{"HH-01": 1}, map[string]interface{}{"quantity": -1})})
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.SyncRejected, results[0].Outcome)
+	_, err = repo.GetSyncState(ctx, "bin/A-01-03")
+	assert.ErrorIs(t, err, fulfillment.ErrSyncStateNotFound)
+}
@@ -0,0 +1,221 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// recordingDockPublisher registra os eventos de agendamento por tipo
+type recordingDockPublisher struct {
+	mu     sync.Mutex
+	events map[string][]string
+}
+
+func (p *recordingDockPublisher) record(event string, appointment *fulfillment.DockAppointment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.events == nil {
+		p.events = make(map[string][]string)
+	}
+	p.events[event] = append(p.events[event], appointment.ID)
+	return nil
+}
+
+func (p *recordingDockPublisher) PublishAppointmentScheduled(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.record("scheduled", appointment)
+}
+
+func (p *recordingDockPublisher) PublishAppointmentArrived(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.record("arrived", appointment)
+}
+
+func (p *recordingDockPublisher) PublishAppointmentCompleted(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.record("completed", appointment)
+}
+
+func (p *recordingDockPublisher) PublishAppointmentCancelled(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.record("cancelled", appointment)
+}
+
+func (p *recordingDockPublisher) PublishAppointmentNoShow(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	return p.record("no_show", appointment)
+}
+
+func newDockFixture(t *testing.T, hours []fulfillment.DockHours) (*app.DockSchedulingUseCase, *memoryRepository, *recordingDockPublisher, *fulfillment.DockDoor) {
+	repo := newMemoryRepository()
+	publisher := &recordingDockPublisher{}
+	uc := app.NewDockSchedulingUseCase(repo, repo, publisher, 15*time.Minute, 2*time.Hour, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	door, err := fulfillment.NewDockDoor("DOCA-01", fulfillment.DockInbound, []string{"REFRIGERATED"}, hours, 60, "")
+	require.NoError(t, err)
+	require.NoError(t, uc.DefineDoor(context.Background(), door))
+	return uc, repo, publisher, door
+}
+
+// newAlwaysOpenDockFixture cria uma porta aberta o dia todo, no fuso em que agora é meio-dia: os
+// agendamentos relativos ao horário atual nunca atravessam a meia-noite local
+func newAlwaysOpenDockFixture(t *testing.T) (*app.DockSchedulingUseCase, *memoryRepository, *recordingDockPublisher, *fulfillment.DockDoor) {
+	var always []fulfillment.DockHours
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		always = append(always, fulfillment.DockHours{Weekday: day, Open: "00:00", Close: "24:00"})
+	}
+	uc, repo, publisher, door := newDockFixture(t, always)
+	door.Timezone = fmt.Sprintf("Etc/GMT%+d", time.Now().UTC().Hour()-12)
+	require.NoError(t, uc.DefineDoor(context.Background(), door))
+	return uc, repo, publisher, door
+}
+
+func createInbound(t *testing.T, repo *memoryRepository, origin string) *fulfillment.InboundShipment {
+	shipment, err := fulfillment.NewInboundShipment("ASN-"+origin, origin, "DOCK-1", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateInbound(context.Background(), shipment))
+	return shipment
+}
+
+func TestDockScheduling_RejectsConflictsAndClosedHours(t *testing.T) {
+	ctx := context.Background()
+	monday := time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC)
+	uc, repo, publisher, door := newDockFixture(t, []fulfillment.DockHours{{Weekday: time.Monday, Open: "08:00", Close: "12:00"}})
+	shipment := createInbound(t, repo, "Fornecedor A")
+
+	booked, err := uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID,
+		Start: monday.Add(9 * time.Hour), Requirements: []string{"REFRIGERATED"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.EntityInboundShipment, booked.ShipmentType)
+	assert.Equal(t, "Fornecedor A", booked.Supplier)
+	assert.Equal(t, monday.Add(10*time.Hour), booked.ScheduledEnd)
+	assert.Equal(t, []string{booked.ID}, publisher.events["scheduled"])
+
+	_, err = uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID, Start: monday.Add(9*time.Hour + 30*time.Minute),
+	})
+	assert.ErrorIs(t, err, fulfillment.ErrAppointmentConflict)
+
+	_, err = uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID, Start: monday.Add(11*time.Hour + 30*time.Minute),
+	})
+	assert.ErrorIs(t, err, fulfillment.ErrDockClosed)
+
+	_, err = uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockOutbound, ShipmentID: "OUT-1", Start: monday.Add(10 * time.Hour),
+	})
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidAppointment)
+
+	_, err = uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID, Start: monday.Add(10 * time.Hour),
+		Requirements: []string{"HAZMAT"},
+	})
+	assert.ErrorIs(t, err, fulfillment.ErrDockCapabilityMissing)
+
+	slots, err := uc.AvailableSlots(ctx, door.ID, monday)
+	require.NoError(t, err)
+	require.Len(t, slots, 3)
+	assert.Equal(t, monday.Add(8*time.Hour), slots[0].Start)
+	assert.Equal(t, monday.Add(10*time.Hour), slots[1].Start)
+
+	// Cancelamento libera a porta
+	_, err = uc.Cancel(ctx, booked.ID)
+	require.NoError(t, err)
+	_, err = uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID, Start: monday.Add(9*time.Hour + 30*time.Minute),
+	})
+	assert.NoError(t, err)
+}
+
+func TestDockScheduling_LateArrivalsAndNoShowsFeedScorecards(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, publisher, door := newAlwaysOpenDockFixture(t)
+	shipment := createInbound(t, repo, "Fornecedor A")
+	now := time.Now().Truncate(time.Minute)
+
+	book := func(start time.Time) *fulfillment.DockAppointment {
+		appointment, err := uc.Book(ctx, app.BookingRequest{
+			DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID,
+			Start: start, End: start.Add(30 * time.Minute),
+		})
+		require.NoError(t, err)
+		return appointment
+	}
+	late := book(now.Add(-time.Hour))
+	missed := book(now.Add(-3 * time.Hour))
+	onTime := book(now.Add(10 * time.Minute))
+
+	arrived, err := uc.CheckIn(ctx, late.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 45, arrived.LateMinutes, 1)
+	arrived, err = uc.CheckIn(ctx, onTime.ID)
+	require.NoError(t, err)
+	assert.Zero(t, arrived.LateMinutes)
+
+	marked, err := uc.DetectNoShows(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	assert.Equal(t, []string{missed.ID}, publisher.events["no_show"])
+
+	noShow, err := uc.GetAppointment(ctx, missed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.AppointmentNoShow, noShow.Status)
+	_, err = uc.CheckIn(ctx, missed.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidStateTransition)
+
+	_, err = uc.Complete(ctx, late.ID)
+	require.NoError(t, err)
+
+	scorecards, err := uc.Scorecards(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	// O agendamento futuro fica fora da janela consultada (até agora)
+	require.Len(t, scorecards, 1)
+	assert.Equal(t, "Fornecedor A", scorecards[0].Supplier)
+	assert.Equal(t, 2, scorecards[0].Appointments)
+	assert.Equal(t, 1, scorecards[0].Late)
+	assert.Equal(t, 1, scorecards[0].NoShows)
+	assert.Zero(t, scorecards[0].OnTime)
+}
+
+// staleAppointments devolve a listagem lida antes da chegada registrada por outro operador
+type staleAppointments struct {
+	*memoryRepository
+	listed []*fulfillment.DockAppointment
+}
+
+func (r *staleAppointments) ListAppointments(ctx context.Context, doorID string, from, to time.Time) ([]*fulfillment.DockAppointment, error) {
+	return r.listed, nil
+}
+
+func TestDockScheduling_NoShowDetectionKeepsConcurrentCheckIn(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, publisher, door := newAlwaysOpenDockFixture(t)
+	shipment := createInbound(t, repo, "Fornecedor A")
+	start := time.Now().Truncate(time.Minute).Add(-3 * time.Hour)
+
+	missed, err := uc.Book(ctx, app.BookingRequest{
+		DoorID: door.ID, Direction: fulfillment.DockInbound, ShipmentID: shipment.ID,
+		Start: start, End: start.Add(30 * time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = uc.CheckIn(ctx, missed.ID)
+	require.NoError(t, err)
+
+	detector := app.NewDockSchedulingUseCase(&staleAppointments{memoryRepository: repo, listed: []*fulfillment.DockAppointment{missed}}, repo,
+		publisher, 15*time.Minute, 2*time.Hour, app.NewZapLoggerAdapter(zap.NewNop()))
+	marked, err := detector.DetectNoShows(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, marked)
+
+	arrived, err := uc.GetAppointment(ctx, missed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.AppointmentArrived, arrived.Status, "no-show detection must not overwrite a check-in")
+	assert.Empty(t, publisher.events["no_show"])
+}
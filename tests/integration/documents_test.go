@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestDocuments_RenderWithCustomerTemplateAndReprint(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "expedicao-1")
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	manifests := app.NewManifestUseCase(f.repo, f.repo, &recordingManifestPublisher{}, appLogger)
+	uc := app.NewDocumentUseCase(f.repo, f.repo, f.repo, f.repo, appLogger)
+
+	hierarchy, err := fulfillment.NewUoMHierarchy("SKU-001", []fulfillment.PackLevel{{UoM: fulfillment.UoMCase, Factor: 12}})
+	require.NoError(t, err)
+	hierarchy.UnitValue = 12.5
+	require.NoError(t, f.repo.SaveUoMHierarchy(ctx, hierarchy))
+
+	f.responder.SetStock("A-01", "SKU-001", 10)
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "ACME", "Rua A, 100", []fulfillment.Item{{SKU: "SKU-001", Quantity: 4, Location: "A-01"}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, f.ship.StartPicking(ctx, order.ID))
+	require.NoError(t, f.ship.Ship(ctx, order.ID))
+	_, err = manifests.HandOver(ctx, order.ID, "TRANSP-X", "TRK-1", 2, 3.5)
+	require.NoError(t, err)
+
+	// Sem modelo cadastrado usa o embutido, com valores da hierarquia e volumes da entrega
+	invoice, err := uc.Render(ctx, order.ID, fulfillment.DocumentCommercialInvoice, fulfillment.DocumentHTML)
+	require.NoError(t, err)
+	assert.Empty(t, invoice.TemplateID)
+	assert.Contains(t, string(invoice.Content), "50.00")
+	assert.Contains(t, string(invoice.Content), "TRK-1")
+
+	label, err := uc.Render(ctx, order.ID, fulfillment.DocumentReturnLabel, fulfillment.DocumentZPL)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(label.Content, []byte("^XA")))
+
+	pdf, err := uc.Render(ctx, order.ID, fulfillment.DocumentPackingSlip, fulfillment.DocumentPDF)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf.Content, []byte("%PDF-1.4")))
+
+	// O modelo do cliente tem precedência sobre o padrão cadastrado
+	_, err = uc.DefineTemplate(ctx, "", fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML, "<p>padrão {{.Order.OrderID}}</p>")
+	require.NoError(t, err)
+	custom, err := uc.DefineTemplate(ctx, "ACME", fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML, "<p>ACME {{.Order.OrderID}} {{.TotalUnits}}</p>")
+	require.NoError(t, err)
+	_, err = uc.DefineTemplate(ctx, "ACME", fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML, "{{.Order.OrderID")
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidDocumentTemplate)
+
+	slip, err := uc.Render(ctx, order.ID, fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML)
+	require.NoError(t, err)
+	assert.Equal(t, custom.ID, slip.TemplateID)
+	assert.Equal(t, "<p>ACME OMS-1 4</p>", string(slip.Content))
+	assert.Equal(t, "expedicao-1", slip.CreatedBy)
+
+	require.NoError(t, uc.DeleteTemplate(ctx, custom.ID))
+	slip, err = uc.Render(ctx, order.ID, fulfillment.DocumentPackingSlip, fulfillment.DocumentHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>padrão OMS-1</p>", string(slip.Content))
+
+	// Documentos guardados para reimpressão
+	documents, err := uc.ListDocuments(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, documents, 5)
+	assert.Equal(t, slip.ID, documents[0].ID)
+	reprint, err := uc.GetDocument(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, invoice.Content, reprint.Content)
+
+	_, err = uc.GetDocument(ctx, "missing")
+	assert.ErrorIs(t, err, fulfillment.ErrDocumentNotFound)
+	_, err = uc.Render(ctx, order.ID, fulfillment.DocumentPackingSlip, "docx")
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidDocumentTemplate)
+}
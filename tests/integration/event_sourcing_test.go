@@ -0,0 +1,266 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/adapters/eventsourcing"
+	natsAdapter "github.com/vertikon/mcp-fulfillment-ops/internal/adapters/nats"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/state/events"
+)
+
+func TestEventSourcing_RebuildsOrderAtPointInTime(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	natsLogger := natsAdapter.NewZapLoggerAdapter(logger)
+
+	responder := natsAdapter.NewLocalInventoryResponder(natsLogger)
+	responder.SetStock("", "SKU-001", 10)
+	client := natsAdapter.NewInventoryRequestClient(natsAdapter.NewLocalRequester(responder), time.Second, natsLogger)
+
+	store := events.NewInMemoryEventStore(nil)
+	defer store.Close()
+	repo := eventsourcing.NewEventSourcedRepository(newMemoryRepository(), store, "test-node")
+	uc := app.NewShipOrderUseCase(repo, client, &noopPublisher{}, app.NewZapLoggerAdapter(logger))
+
+	order, err := uc.CreateOrder(ctx, "OMS-ES-1", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2}}, 0)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	beforePicking := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	require.NoError(t, uc.StartPicking(ctx, order.ID))
+
+	projected, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusInProgress, projected.Status)
+
+	past, err := repo.GetOrderAt(ctx, order.ID, beforePicking)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusPending, past.Status)
+	assert.Equal(t, fulfillment.ReservationReserved, past.ReservationStatus)
+
+	stream, err := store.GetAllEvents(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, stream)
+	assert.Equal(t, "fulfillment_order.created", stream[0].Metadata[eventsourcing.MetadataEventName])
+	assert.Equal(t, "fulfillment_order.started", stream[len(stream)-1].Metadata[eventsourcing.MetadataEventName])
+
+	_, err = repo.GetOrderAt(ctx, order.ID, order.CreatedAt.Add(-time.Hour))
+	assert.ErrorIs(t, err, fulfillment.ErrAggregateNotFound)
+}
+
+func TestEventSourcing_RejectsStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewInMemoryEventStore(nil)
+	defer store.Close()
+	repo := eventsourcing.NewEventSourcedRepository(newMemoryRepository(), store, "test-node")
+
+	order, err := fulfillment.NewFulfillmentOrder("OMS-ES-2", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, order))
+	assert.Equal(t, int64(1), order.Version)
+
+	stale := *order
+
+	require.NoError(t, order.StartPicking())
+	require.NoError(t, repo.UpdateOrder(ctx, order))
+	assert.Equal(t, int64(2), order.Version)
+
+	require.NoError(t, stale.Cancel())
+	err = repo.UpdateOrder(ctx, &stale)
+	assert.ErrorIs(t, err, fulfillment.ErrConcurrencyConflict)
+
+	stored, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusInProgress, stored.Status)
+}
+
+// failingProjection falha a próxima escrita (ou criação) de ordem na projeção
+type failingProjection struct {
+	*memoryRepository
+	failNext   bool
+	failCreate bool
+}
+
+func (p *failingProjection) CreateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	if p.failCreate {
+		p.failCreate = false
+		return errors.New("projection unavailable")
+	}
+	return p.memoryRepository.CreateOrder(ctx, order)
+}
+
+func (p *failingProjection) UpdateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	if p.failNext {
+		p.failNext = false
+		return errors.New("projection unavailable")
+	}
+	return p.memoryRepository.UpdateOrder(ctx, order)
+}
+
+// unavailableStore falha a leitura das informações do agregado
+type unavailableStore struct {
+	events.EventStore
+}
+
+func (unavailableStore) GetAggregateInfo(ctx context.Context, aggregateID string) (*events.AggregateInfo, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestEventSourcing_RebuildsProjectionBehindStream(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewInMemoryEventStore(nil)
+	defer store.Close()
+	projection := &failingProjection{memoryRepository: newMemoryRepository()}
+	repo := eventsourcing.NewEventSourcedRepository(projection, store, "test-node")
+
+	order, err := fulfillment.NewFulfillmentOrder("OMS-ES-3", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, order))
+
+	// O evento é gravado, mas a linha fica na versão anterior
+	projection.failNext = true
+	require.NoError(t, order.StartPicking())
+	require.Error(t, repo.UpdateOrder(ctx, order))
+
+	lagging, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), lagging.Version)
+
+	// A atualização sobre a linha defasada é rejeitada e a projeção é regravada a partir do stream
+	require.NoError(t, lagging.Cancel())
+	assert.ErrorIs(t, repo.UpdateOrder(ctx, lagging), fulfillment.ErrConcurrencyConflict)
+
+	current, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), current.Version)
+	assert.Equal(t, fulfillment.StatusInProgress, current.Status)
+
+	require.NoError(t, current.Cancel())
+	require.NoError(t, repo.UpdateOrder(ctx, current))
+	assert.Equal(t, int64(3), current.Version)
+}
+
+// racingStore informa uma vez a versão anterior do agregado, como se outro escritor gravasse entre a
+// leitura da versão e o append
+type racingStore struct {
+	events.EventStore
+	stale bool
+}
+
+func (s *racingStore) GetAggregateInfo(ctx context.Context, aggregateID string) (*events.AggregateInfo, error) {
+	info, err := s.EventStore.GetAggregateInfo(ctx, aggregateID)
+	if err == nil && s.stale {
+		s.stale = false
+		previous := *info
+		previous.Version--
+		return &previous, nil
+	}
+	return info, err
+}
+
+func TestEventSourcing_RestoresMissingProjectionOnCreateRetry(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewInMemoryEventStore(nil)
+	defer store.Close()
+	projection := &failingProjection{memoryRepository: newMemoryRepository()}
+	repo := eventsourcing.NewEventSourcedRepository(projection, store, "test-node")
+
+	order, err := fulfillment.NewFulfillmentOrder("OMS-ES-6", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+
+	// O evento de criação é gravado, mas a linha não
+	projection.failCreate = true
+	require.Error(t, repo.CreateOrder(ctx, order))
+	_, err = repo.GetOrderByID(ctx, order.ID)
+	require.ErrorIs(t, err, fulfillment.ErrOrderNotFound)
+
+	retried := *order
+	retried.Version = 0
+	require.NoError(t, repo.CreateOrder(ctx, &retried))
+	restored, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), restored.Version)
+	assert.Equal(t, fulfillment.StatusPending, restored.Status)
+}
+
+func TestEventSourcing_StoreVersionConflictIsConcurrencyConflict(t *testing.T) {
+	ctx := context.Background()
+	memory := events.NewInMemoryEventStore(nil)
+	defer memory.Close()
+	store := &racingStore{EventStore: memory}
+	repo := eventsourcing.NewEventSourcedRepository(newMemoryRepository(), store, "test-node")
+
+	order, err := fulfillment.NewFulfillmentOrder("OMS-ES-7", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, order))
+	require.NoError(t, order.StartPicking())
+	require.NoError(t, repo.UpdateOrder(ctx, order))
+
+	// O escritor atrasado passa pela verificação de versão, mas o append é rejeitado pelo store
+	stale, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	stale.Version--
+	require.NoError(t, stale.Cancel())
+	store.stale = true
+	err = repo.UpdateOrder(ctx, stale)
+	assert.ErrorIs(t, err, fulfillment.ErrConcurrencyConflict)
+	assert.ErrorIs(t, err, events.ErrVersionConflict)
+	assert.Equal(t, int64(1), stale.Version)
+}
+
+func TestEventSourcing_StoreErrorsAreNotTreatedAsNewAggregate(t *testing.T) {
+	ctx := context.Background()
+	store := events.NewInMemoryEventStore(nil)
+	defer store.Close()
+	repo := eventsourcing.NewEventSourcedRepository(newMemoryRepository(), unavailableStore{store}, "test-node")
+
+	order, err := fulfillment.NewFulfillmentOrder("OMS-ES-4", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+	assert.Error(t, repo.CreateOrder(ctx, order))
+
+	stream, err := store.GetAllEvents(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stream)
+}
+
+func TestEventSourcing_RejectsHistoryRemovedFromStream(t *testing.T) {
+	ctx := context.Background()
+	config := events.DefaultEventStoreConfig()
+	config.StoragePath = t.TempDir()
+	store, err := events.NewBadgerEventStore(config)
+	require.NoError(t, err)
+	defer store.Close()
+	repo := eventsourcing.NewEventSourcedRepository(newMemoryRepository(), store, "test-node")
+
+	order, err := fulfillment.NewFulfillmentOrder("OMS-ES-5", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, order))
+	time.Sleep(5 * time.Millisecond)
+	beforePicking := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, order.StartPicking())
+	require.NoError(t, repo.UpdateOrder(ctx, order))
+
+	// A retenção remove o evento de criação: o estado anterior à separação não pode mais ser reconstruído
+	require.NoError(t, store.PruneEvents(ctx, beforePicking))
+
+	_, err = repo.GetOrderAt(ctx, order.ID, beforePicking)
+	assert.ErrorIs(t, err, fulfillment.ErrHistoryPruned)
+	_, err = repo.GetOrderAt(ctx, order.ID, order.CreatedAt.Add(-time.Hour))
+	assert.ErrorIs(t, err, fulfillment.ErrAggregateNotFound)
+
+	current, err := repo.GetOrderAt(ctx, order.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusInProgress, current.Status)
+}
@@ -0,0 +1,1433 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// memoryRepository é um fulfillment.Repository em memória para testes offline.
+// Métodos não sobrescritos delegam para a interface embutida (nil) e causam panic.
+type memoryRepository struct {
+	fulfillment.Repository
+
+	mu        sync.Mutex
+	inbounds  map[string]*fulfillment.InboundShipment
+	orders    map[string]*fulfillment.FulfillmentOrder
+	tasks     map[string]*fulfillment.WarehouseTask
+	transfers map[string]*fulfillment.TransferOrder
+	rules     map[string]*fulfillment.ReplenishmentRule
+	kits      map[string]*fulfillment.KitDefinition
+	assembly  map[string]*fulfillment.AssemblyOrder
+	policies  map[string]*fulfillment.CrossDockPolicy
+	crossDock []*fulfillment.CrossDockAllocation
+	doors     map[string]*fulfillment.DockDoor
+	bookings  map[string]*fulfillment.DockAppointment
+	uoms      map[string]*fulfillment.UoMHierarchy
+	lpns      map[string]*fulfillment.LPN
+	lpnEvents []fulfillment.LPNEvent
+	layout    *fulfillment.WarehouseLayout
+	slotting  map[string]*fulfillment.SlottingRecommendation
+	counts    map[string]*fulfillment.CycleCountTask
+	program   *fulfillment.CountProgram
+	triggers  []*fulfillment.CountTrigger
+	manifests map[string]*fulfillment.CarrierManifest
+	outbound  map[string]*fulfillment.OutboundShipment
+	documents map[string]map[fulfillment.ManifestFormat][]byte
+	templates map[string]*fulfillment.DocumentTemplate
+	rendered  []*fulfillment.RenderedDocument
+	picking   map[string]*fulfillment.PickSession
+	syncState map[string]*fulfillment.SyncState
+	conflicts []*fulfillment.SyncConflictRecord
+	calendars map[string]*fulfillment.CutoffCalendar
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		inbounds:  make(map[string]*fulfillment.InboundShipment),
+		orders:    make(map[string]*fulfillment.FulfillmentOrder),
+		tasks:     make(map[string]*fulfillment.WarehouseTask),
+		transfers: make(map[string]*fulfillment.TransferOrder),
+		rules:     make(map[string]*fulfillment.ReplenishmentRule),
+		kits:      make(map[string]*fulfillment.KitDefinition),
+		assembly:  make(map[string]*fulfillment.AssemblyOrder),
+		policies:  make(map[string]*fulfillment.CrossDockPolicy),
+		doors:     make(map[string]*fulfillment.DockDoor),
+		bookings:  make(map[string]*fulfillment.DockAppointment),
+		uoms:      make(map[string]*fulfillment.UoMHierarchy),
+		lpns:      make(map[string]*fulfillment.LPN),
+		slotting:  make(map[string]*fulfillment.SlottingRecommendation),
+		counts:    make(map[string]*fulfillment.CycleCountTask),
+		manifests: make(map[string]*fulfillment.CarrierManifest),
+		outbound:  make(map[string]*fulfillment.OutboundShipment),
+		documents: make(map[string]map[fulfillment.ManifestFormat][]byte),
+		templates: make(map[string]*fulfillment.DocumentTemplate),
+		picking:   make(map[string]*fulfillment.PickSession),
+		syncState: make(map[string]*fulfillment.SyncState),
+		calendars: make(map[string]*fulfillment.CutoffCalendar),
+	}
+}
+
+func (r *memoryRepository) CreateInbound(ctx context.Context, shipment *fulfillment.InboundShipment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *shipment
+	r.inbounds[shipment.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetInboundByID(ctx context.Context, id string) (*fulfillment.InboundShipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	shipment, ok := r.inbounds[id]
+	if !ok {
+		return nil, fulfillment.ErrShipmentNotFound
+	}
+	copied := *shipment
+	return &copied, nil
+}
+
+func (r *memoryRepository) GetInboundByReferenceID(ctx context.Context, referenceID string) (*fulfillment.InboundShipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, shipment := range r.inbounds {
+		if shipment.ReferenceID == referenceID {
+			copied := *shipment
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrShipmentNotFound
+}
+
+func (r *memoryRepository) UpdateInbound(ctx context.Context, shipment *fulfillment.InboundShipment) error {
+	return r.CreateInbound(ctx, shipment)
+}
+
+func (r *memoryRepository) CreateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[order.ID] = copyOrder(order)
+	return nil
+}
+
+// copyOrder copia a ordem incluindo os itens, que podem ser alterados (ex: cross-dock)
+func copyOrder(order *fulfillment.FulfillmentOrder) *fulfillment.FulfillmentOrder {
+	copied := *order
+	copied.Items = append([]fulfillment.Item(nil), order.Items...)
+	return &copied
+}
+
+func (r *memoryRepository) GetOrderByID(ctx context.Context, id string) (*fulfillment.FulfillmentOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, fulfillment.ErrOrderNotFound
+	}
+	return copyOrder(order), nil
+}
+
+func (r *memoryRepository) GetOrderByOrderID(ctx context.Context, orderID string) (*fulfillment.FulfillmentOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.OrderID == orderID {
+			return copyOrder(order), nil
+		}
+	}
+	return nil, fulfillment.ErrOrderNotFound
+}
+
+func (r *memoryRepository) UpdateOrder(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	return r.CreateOrder(ctx, order)
+}
+
+func (r *memoryRepository) ListOrdersByReservationStatus(ctx context.Context, status fulfillment.ReservationStatus, afterID string, limit int) ([]*fulfillment.FulfillmentOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*fulfillment.FulfillmentOrder
+	for _, order := range r.orders {
+		if order.ReservationStatus == status && order.ID > afterID {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+func (r *memoryRepository) ListOrphanedReservations(ctx context.Context, now time.Time, limit int) ([]*fulfillment.FulfillmentOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*fulfillment.FulfillmentOrder
+	for _, order := range r.orders {
+		if len(orders) == limit {
+			break
+		}
+		if order.ReservationStatus != fulfillment.ReservationReserved {
+			continue
+		}
+		if order.Status == fulfillment.StatusCancelled || order.Status == fulfillment.StatusFailed ||
+			(order.Status == fulfillment.StatusPending && order.ReservationExpiredAt(now)) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	return orders, nil
+}
+
+// ListFailedOperations implementa fulfillment.FailureRepository para inbounds, ordens e montagens,
+// da falha mais antiga para a mais recente, como a consulta SQL
+func (r *memoryRepository) ListFailedOperations(ctx context.Context, entityType string, since time.Time, limit int) ([]fulfillment.FailedOperation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var operations []fulfillment.FailedOperation
+	updatedAt := make(map[string]time.Time)
+	add := func(op fulfillment.FailedOperation, updated time.Time) {
+		operations = append(operations, op)
+		updatedAt[op.EntityID] = updated
+	}
+	if entityType == "" || entityType == fulfillment.EntityInboundShipment {
+		for _, shipment := range r.inbounds {
+			if shipment.Status == fulfillment.StatusFailed && !shipment.UpdatedAt.Before(since) {
+				add(fulfillment.FailedOperation{
+					EntityType: fulfillment.EntityInboundShipment, EntityID: shipment.ID,
+					Operation: fulfillment.OpInbound, CreatedAt: shipment.CreatedAt, Failure: shipment.Failure,
+				}, shipment.UpdatedAt)
+			}
+		}
+	}
+	if entityType == "" || entityType == fulfillment.EntityFulfillmentOrder {
+		for _, order := range r.orders {
+			if order.Status == fulfillment.StatusFailed && !order.UpdatedAt.Before(since) {
+				add(fulfillment.FailedOperation{
+					EntityType: fulfillment.EntityFulfillmentOrder, EntityID: order.ID,
+					Operation: fulfillment.OpOutbound, CreatedAt: order.CreatedAt, Failure: order.Failure,
+				}, order.UpdatedAt)
+			}
+		}
+	}
+	if entityType == "" || entityType == fulfillment.EntityAssemblyOrder {
+		for _, order := range r.assembly {
+			if order.Status == fulfillment.StatusFailed && !order.UpdatedAt.Before(since) {
+				add(fulfillment.FailedOperation{
+					EntityType: fulfillment.EntityAssemblyOrder, EntityID: order.ID,
+					Operation: fulfillment.OpAssembly, CreatedAt: order.CreatedAt, Failure: order.Failure,
+				}, order.UpdatedAt)
+			}
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return updatedAt[operations[i].EntityID].Before(updatedAt[operations[j].EntityID])
+	})
+	return operations, nil
+}
+
+// ListDueFailures implementa fulfillment.FailureRepository aplicando o limite após o filtro, como a consulta SQL
+func (r *memoryRepository) ListDueFailures(ctx context.Context, since, now time.Time, createdAfter map[fulfillment.OperationType]time.Time, limit int) ([]fulfillment.FailedOperation, error) {
+	all, err := r.ListFailedOperations(ctx, "", since, 0)
+	if err != nil {
+		return nil, err
+	}
+	var due []fulfillment.FailedOperation
+	for _, op := range all {
+		if len(due) == limit {
+			break
+		}
+		if cutoff, ok := createdAfter[op.Operation]; ok && op.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if op.Failure != nil && op.Failure.Class == fulfillment.FailureTransient && op.Failure.DueForRetry(now) {
+			due = append(due, op)
+		}
+	}
+	return due, nil
+}
+
+// ListBlocked implementa fulfillment.BlockRepository para inbounds
+func (r *memoryRepository) ListBlocked(ctx context.Context, entityType string, blockedBefore time.Time, limit int) ([]fulfillment.BlockedEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var blocked []fulfillment.BlockedEntity
+	if entityType == "" || entityType == fulfillment.EntityInboundShipment {
+		for _, shipment := range r.inbounds {
+			if shipment.Status == fulfillment.StatusBlocked && !shipment.Blocked.BlockedAt.After(blockedBefore) {
+				block := *shipment.Blocked
+				blocked = append(blocked, fulfillment.BlockedEntity{
+					EntityType: fulfillment.EntityInboundShipment, EntityID: shipment.ID, Block: &block,
+				})
+			}
+		}
+	}
+	return blocked, nil
+}
+
+// ListUnescalatedBlocked implementa fulfillment.BlockRepository para inbounds
+func (r *memoryRepository) ListUnescalatedBlocked(ctx context.Context, blockedBefore time.Time, limit int) ([]fulfillment.BlockedEntity, error) {
+	all, err := r.ListBlocked(ctx, "", blockedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	var blocked []fulfillment.BlockedEntity
+	for _, entity := range all {
+		if entity.Block.EscalatedAt == nil {
+			blocked = append(blocked, entity)
+		}
+	}
+	return blocked, nil
+}
+
+// MarkBlockEscalated implementa fulfillment.BlockRepository para inbounds
+func (r *memoryRepository) MarkBlockEscalated(ctx context.Context, entityType, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if shipment, ok := r.inbounds[id]; ok && shipment.Blocked != nil {
+		block := *shipment.Blocked
+		block.EscalatedAt = &at
+		shipment.Blocked = &block
+	}
+	return nil
+}
+
+// CreateTask implementa fulfillment.TaskRepository com o índice único de tarefa aberta por operação
+func (r *memoryRepository) CreateTask(ctx context.Context, task *fulfillment.WarehouseTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.tasks {
+		if stored.EntityType == task.EntityType && stored.EntityID == task.EntityID &&
+			(stored.Status == fulfillment.StatusPending || stored.IsActive()) {
+			return fmt.Errorf("%w: open task already exists for %s %s", fulfillment.ErrTaskConflict, task.EntityType, task.EntityID)
+		}
+	}
+	copied := *task
+	r.tasks[task.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetTaskByID(ctx context.Context, id string) (*fulfillment.WarehouseTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, fulfillment.ErrTaskNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+// GetTaskByEntity retorna a tarefa mais recente da operação, como o repositório Postgres
+func (r *memoryRepository) GetTaskByEntity(ctx context.Context, entityType, entityID string) (*fulfillment.WarehouseTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *fulfillment.WarehouseTask
+	for _, task := range r.tasks {
+		if task.EntityType == entityType && task.EntityID == entityID &&
+			(latest == nil || task.CreatedAt.After(latest.CreatedAt)) {
+			latest = task
+		}
+	}
+	if latest == nil {
+		return nil, fulfillment.ErrTaskNotFound
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+// UpdateTask aplica o mesmo controle otimista por versão do repositório Postgres
+func (r *memoryRepository) UpdateTask(ctx context.Context, task *fulfillment.WarehouseTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.tasks[task.ID]
+	if !ok {
+		return fulfillment.ErrTaskNotFound
+	}
+	if stored.Version != task.Version {
+		return fulfillment.ErrTaskConflict
+	}
+	task.Version++
+	copied := *task
+	r.tasks[task.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) ListOpenTasks(ctx context.Context, limit int) ([]*fulfillment.WarehouseTask, error) {
+	return r.listTasks(func(task *fulfillment.WarehouseTask) bool {
+		return task.Status == fulfillment.StatusPending || task.IsActive()
+	}), nil
+}
+
+func (r *memoryRepository) ListTasksByAssignee(ctx context.Context, assignee string, limit int) ([]*fulfillment.WarehouseTask, error) {
+	tasks := r.listTasks(func(task *fulfillment.WarehouseTask) bool { return task.AssignedTo == assignee })
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].UpdatedAt.After(tasks[j].UpdatedAt) })
+	return tasks, nil
+}
+
+func (r *memoryRepository) listTasks(match func(*fulfillment.WarehouseTask) bool) []*fulfillment.WarehouseTask {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tasks []*fulfillment.WarehouseTask
+	for _, task := range r.tasks {
+		if match(task) {
+			copied := *task
+			tasks = append(tasks, &copied)
+		}
+	}
+	return tasks
+}
+
+func (r *memoryRepository) CreateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *transfer
+	r.transfers[transfer.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetTransferByID(ctx context.Context, id string) (*fulfillment.TransferOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transfer, ok := r.transfers[id]
+	if !ok {
+		return nil, fulfillment.ErrTransferNotFound
+	}
+	copied := *transfer
+	return &copied, nil
+}
+
+func (r *memoryRepository) UpdateTransfer(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	return r.CreateTransfer(ctx, transfer)
+}
+
+// SaveReplenishmentRule implementa fulfillment.ReplenishmentRuleRepository (única por SKU e endereço)
+func (r *memoryRepository) SaveReplenishmentRule(ctx context.Context, rule *fulfillment.ReplenishmentRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, existing := range r.rules {
+		if existing.SKU == rule.SKU && existing.PickLocation == rule.PickLocation {
+			rule.ID, rule.OpenTransferID = id, existing.OpenTransferID
+		}
+	}
+	copied := *rule
+	r.rules[rule.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) UpdateReplenishmentTransfer(ctx context.Context, id, from, to string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules[id]
+	if !ok || rule.OpenTransferID != from {
+		return fulfillment.ErrReplenishmentMoveChanged
+	}
+	rule.OpenTransferID = to
+	return nil
+}
+
+func (r *memoryRepository) ListReplenishmentRules(ctx context.Context, pickLocation string) ([]*fulfillment.ReplenishmentRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rules []*fulfillment.ReplenishmentRule
+	for _, rule := range r.rules {
+		if pickLocation == "" || rule.PickLocation == pickLocation {
+			copied := *rule
+			rules = append(rules, &copied)
+		}
+	}
+	return rules, nil
+}
+
+func (r *memoryRepository) DeleteReplenishmentRule(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[id]; !ok {
+		return fulfillment.ErrReplenishmentRuleNotFound
+	}
+	delete(r.rules, id)
+	return nil
+}
+
+// SaveKit implementa fulfillment.KitRepository
+func (r *memoryRepository) SaveKit(ctx context.Context, kit *fulfillment.KitDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *kit
+	r.kits[kit.SKU] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetKit(ctx context.Context, sku string) (*fulfillment.KitDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kit, ok := r.kits[sku]
+	if !ok {
+		return nil, fulfillment.ErrKitNotFound
+	}
+	copied := *kit
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListKits(ctx context.Context) ([]*fulfillment.KitDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kits []*fulfillment.KitDefinition
+	for _, kit := range r.kits {
+		copied := *kit
+		kits = append(kits, &copied)
+	}
+	sort.Slice(kits, func(i, j int) bool { return kits[i].SKU < kits[j].SKU })
+	return kits, nil
+}
+
+func (r *memoryRepository) DeleteKit(ctx context.Context, sku string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.kits[sku]; !ok {
+		return fulfillment.ErrKitNotFound
+	}
+	delete(r.kits, sku)
+	return nil
+}
+
+func (r *memoryRepository) CreateAssemblyOrder(ctx context.Context, order *fulfillment.AssemblyOrder) error {
+	return r.UpdateAssemblyOrder(ctx, order)
+}
+
+func (r *memoryRepository) GetAssemblyOrderByID(ctx context.Context, id string) (*fulfillment.AssemblyOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.assembly[id]
+	if !ok {
+		return nil, fulfillment.ErrAssemblyOrderNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (r *memoryRepository) UpdateAssemblyOrder(ctx context.Context, order *fulfillment.AssemblyOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *order
+	r.assembly[order.ID] = &copied
+	return nil
+}
+
+// SaveCrossDockPolicy implementa fulfillment.CrossDockRepository (única por cliente e SKU)
+func (r *memoryRepository) SaveCrossDockPolicy(ctx context.Context, policy *fulfillment.CrossDockPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, existing := range r.policies {
+		if existing.Customer == policy.Customer && existing.SKU == policy.SKU {
+			policy.ID = id
+		}
+	}
+	copied := *policy
+	r.policies[policy.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) ListCrossDockPolicies(ctx context.Context) ([]*fulfillment.CrossDockPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var policies []*fulfillment.CrossDockPolicy
+	for _, policy := range r.policies {
+		copied := *policy
+		policies = append(policies, &copied)
+	}
+	return policies, nil
+}
+
+func (r *memoryRepository) DeleteCrossDockPolicy(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.policies[id]; !ok {
+		return fulfillment.ErrCrossDockPolicyNotFound
+	}
+	delete(r.policies, id)
+	return nil
+}
+
+func (r *memoryRepository) CreateCrossDockAllocation(ctx context.Context, allocation *fulfillment.CrossDockAllocation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *allocation
+	r.crossDock = append(r.crossDock, &copied)
+	return nil
+}
+
+func (r *memoryRepository) GetCrossDockAllocationByTransfer(ctx context.Context, transferID string) (*fulfillment.CrossDockAllocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, allocation := range r.crossDock {
+		if allocation.TransferID == transferID {
+			copied := *allocation
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrCrossDockAllocationNotFound
+}
+
+func (r *memoryRepository) ListCrossDockAllocations(ctx context.Context, shipmentID string, limit int) ([]*fulfillment.CrossDockAllocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var allocations []*fulfillment.CrossDockAllocation
+	for _, allocation := range r.crossDock {
+		if shipmentID == "" || allocation.ShipmentID == shipmentID {
+			copied := *allocation
+			allocations = append(allocations, &copied)
+		}
+	}
+	return allocations, nil
+}
+
+// SaveDockDoor implementa fulfillment.DockRepository (única por nome)
+func (r *memoryRepository) SaveDockDoor(ctx context.Context, door *fulfillment.DockDoor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, existing := range r.doors {
+		if existing.Name == door.Name {
+			door.ID = id
+		}
+	}
+	copied := *door
+	r.doors[door.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetDockDoor(ctx context.Context, id string) (*fulfillment.DockDoor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	door, ok := r.doors[id]
+	if !ok {
+		return nil, fulfillment.ErrDockDoorNotFound
+	}
+	copied := *door
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListDockDoors(ctx context.Context) ([]*fulfillment.DockDoor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var doors []*fulfillment.DockDoor
+	for _, door := range r.doors {
+		copied := *door
+		doors = append(doors, &copied)
+	}
+	return doors, nil
+}
+
+func (r *memoryRepository) CreateAppointment(ctx context.Context, appointment *fulfillment.DockAppointment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.doors[appointment.DoorID]; !ok {
+		return fulfillment.ErrDockDoorNotFound
+	}
+	for _, existing := range r.bookings {
+		if existing.DoorID == appointment.DoorID && existing.IsActive() &&
+			existing.Overlaps(appointment.ScheduledStart, appointment.ScheduledEnd) {
+			return fulfillment.ErrAppointmentConflict
+		}
+	}
+	copied := *appointment
+	r.bookings[appointment.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetAppointment(ctx context.Context, id string) (*fulfillment.DockAppointment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	appointment, ok := r.bookings[id]
+	if !ok {
+		return nil, fulfillment.ErrAppointmentNotFound
+	}
+	copied := *appointment
+	return &copied, nil
+}
+
+func (r *memoryRepository) UpdateAppointment(ctx context.Context, appointment *fulfillment.DockAppointment, from fulfillment.AppointmentStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.bookings[appointment.ID]
+	if !ok {
+		return fulfillment.ErrAppointmentNotFound
+	}
+	if current.Status != from {
+		return fmt.Errorf("%w: %s, expected %s", fulfillment.ErrAppointmentChanged, current.Status, from)
+	}
+	copied := *appointment
+	r.bookings[appointment.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) ListAppointments(ctx context.Context, doorID string, from, to time.Time) ([]*fulfillment.DockAppointment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var appointments []*fulfillment.DockAppointment
+	for _, appointment := range r.bookings {
+		if (doorID == "" || appointment.DoorID == doorID) &&
+			!appointment.ScheduledStart.Before(from) && appointment.ScheduledStart.Before(to) {
+			copied := *appointment
+			appointments = append(appointments, &copied)
+		}
+	}
+	return appointments, nil
+}
+
+// SaveUoMHierarchy implementa fulfillment.UoMRepository
+func (r *memoryRepository) SaveUoMHierarchy(ctx context.Context, hierarchy *fulfillment.UoMHierarchy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *hierarchy
+	r.uoms[hierarchy.SKU] = &copied
+	return nil
+}
+
+func (r *memoryRepository) FindUoMHierarchyByGTIN(ctx context.Context, gtin string) (*fulfillment.UoMHierarchy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, hierarchy := range r.uoms {
+		if _, ok := hierarchy.UoMForGTIN(gtin); ok {
+			copied := *hierarchy
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrUoMHierarchyNotFound
+}
+
+func (r *memoryRepository) GetUoMHierarchy(ctx context.Context, sku string) (*fulfillment.UoMHierarchy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hierarchy, ok := r.uoms[sku]
+	if !ok {
+		return nil, fulfillment.ErrUoMHierarchyNotFound
+	}
+	copied := *hierarchy
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListUoMHierarchies(ctx context.Context) ([]*fulfillment.UoMHierarchy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var hierarchies []*fulfillment.UoMHierarchy
+	for _, hierarchy := range r.uoms {
+		copied := *hierarchy
+		hierarchies = append(hierarchies, &copied)
+	}
+	return hierarchies, nil
+}
+
+func (r *memoryRepository) DeleteUoMHierarchy(ctx context.Context, sku string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.uoms[sku]; !ok {
+		return fulfillment.ErrUoMHierarchyNotFound
+	}
+	delete(r.uoms, sku)
+	return nil
+}
+
+// CreateLPN implementa fulfillment.LPNRepository
+func (r *memoryRepository) CreateLPN(ctx context.Context, lpn *fulfillment.LPN, event fulfillment.LPNEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.lpns[lpn.Code]; ok {
+		return fulfillment.ErrLPNExists
+	}
+	copied := *lpn
+	r.lpns[lpn.Code] = &copied
+	r.lpnEvents = append(r.lpnEvents, event)
+	return nil
+}
+
+func (r *memoryRepository) GetLPN(ctx context.Context, code string) (*fulfillment.LPN, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lpn, ok := r.lpns[code]
+	if !ok {
+		return nil, fulfillment.ErrLPNNotFound
+	}
+	copied := *lpn
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListLPNChildren(ctx context.Context, parentCode string) ([]*fulfillment.LPN, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var children []*fulfillment.LPN
+	for _, lpn := range r.lpns {
+		if lpn.Parent == parentCode {
+			copied := *lpn
+			children = append(children, &copied)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Code < children[j].Code })
+	return children, nil
+}
+
+func (r *memoryRepository) SaveLPNs(ctx context.Context, lpns []*fulfillment.LPN, events []fulfillment.LPNEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, lpn := range lpns {
+		if _, ok := r.lpns[lpn.Code]; !ok {
+			return fulfillment.ErrLPNNotFound
+		}
+	}
+	for _, lpn := range lpns {
+		copied := *lpn
+		r.lpns[lpn.Code] = &copied
+	}
+	r.lpnEvents = append(r.lpnEvents, events...)
+	return nil
+}
+
+func (r *memoryRepository) ListLPNEvents(ctx context.Context, code string) ([]fulfillment.LPNEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []fulfillment.LPNEvent
+	for _, event := range r.lpnEvents {
+		if event.LPNCode == code {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// SaveLayout implementa fulfillment.LayoutRepository
+func (r *memoryRepository) SaveLayout(ctx context.Context, layout *fulfillment.WarehouseLayout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *layout
+	copied.Bins = append([]fulfillment.BinCoordinate(nil), layout.Bins...)
+	r.layout = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetLayout(ctx context.Context) (*fulfillment.WarehouseLayout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.layout == nil {
+		return nil, fulfillment.ErrLayoutNotFound
+	}
+	copied := *r.layout
+	copied.Bins = append([]fulfillment.BinCoordinate(nil), r.layout.Bins...)
+	return &copied, nil
+}
+
+// ListShippedOrders implementa fulfillment.SlottingRepository
+func (r *memoryRepository) ListShippedOrders(ctx context.Context, since time.Time) ([]*fulfillment.FulfillmentOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*fulfillment.FulfillmentOrder
+	for _, order := range r.orders {
+		if order.Status == fulfillment.StatusCompleted && order.ShippedAt != nil && !order.ShippedAt.Before(since) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	return orders, nil
+}
+
+func (r *memoryRepository) ReplaceSlottingProposals(ctx context.Context, recommendations []*fulfillment.SlottingRecommendation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, rec := range r.slotting {
+		if rec.Status == fulfillment.SlottingProposed {
+			delete(r.slotting, id)
+		}
+	}
+	for _, rec := range recommendations {
+		copied := *rec
+		r.slotting[rec.ID] = &copied
+	}
+	return nil
+}
+
+func (r *memoryRepository) GetSlottingRecommendation(ctx context.Context, id string) (*fulfillment.SlottingRecommendation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec, ok := r.slotting[id]
+	if !ok {
+		return nil, fulfillment.ErrSlottingRecommendationNotFound
+	}
+	copied := *rec
+	return &copied, nil
+}
+
+func (r *memoryRepository) UpdateSlottingRecommendation(ctx context.Context, rec *fulfillment.SlottingRecommendation, from fulfillment.SlottingStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.slotting[rec.ID]
+	if !ok {
+		return fulfillment.ErrSlottingRecommendationNotFound
+	}
+	if current.Status != from {
+		return fmt.Errorf("%w: %s, expected %s", fulfillment.ErrSlottingNotProposed, current.Status, from)
+	}
+	copied := *rec
+	r.slotting[rec.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) ListSlottingRecommendations(ctx context.Context, status fulfillment.SlottingStatus) ([]*fulfillment.SlottingRecommendation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var recommendations []*fulfillment.SlottingRecommendation
+	for _, rec := range r.slotting {
+		if status == "" || rec.Status == status {
+			copied := *rec
+			recommendations = append(recommendations, &copied)
+		}
+	}
+	sort.Slice(recommendations, func(i, j int) bool { return recommendations[i].Savings > recommendations[j].Savings })
+	return recommendations, nil
+}
+
+func (r *memoryRepository) CreateCycleCount(ctx context.Context, task *fulfillment.CycleCountTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *task
+	r.counts[task.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetCycleCountByID(ctx context.Context, id string) (*fulfillment.CycleCountTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.counts[id]
+	if !ok {
+		return nil, fulfillment.ErrCycleCountNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (r *memoryRepository) UpdateCycleCount(ctx context.Context, task *fulfillment.CycleCountTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.counts[task.ID]; !ok {
+		return fulfillment.ErrCycleCountNotFound
+	}
+	copied := *task
+	r.counts[task.ID] = &copied
+	return nil
+}
+
+// SaveCountProgram implementa fulfillment.CountProgramRepository
+func (r *memoryRepository) SaveCountProgram(ctx context.Context, program *fulfillment.CountProgram) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *program
+	r.program = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetCountProgram(ctx context.Context) (*fulfillment.CountProgram, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.program == nil {
+		return nil, fulfillment.ErrCountProgramNotFound
+	}
+	copied := *r.program
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListLocationCountHistory(ctx context.Context) ([]fulfillment.LocationCountHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byLocation := make(map[string]*fulfillment.LocationCountHistory)
+	for _, task := range r.counts {
+		entry, ok := byLocation[task.Location]
+		if !ok {
+			entry = &fulfillment.LocationCountHistory{Location: task.Location}
+			byLocation[task.Location] = entry
+		}
+		if task.CompletedAt != nil && (entry.LastCountedAt == nil || task.CompletedAt.After(*entry.LastCountedAt)) {
+			entry.LastCountedAt = task.CompletedAt
+		}
+		if createdAt := task.CreatedAt; entry.LastOpenedAt == nil || createdAt.After(*entry.LastOpenedAt) {
+			entry.LastOpenedAt = &createdAt
+		}
+		if task.Status != fulfillment.StatusCompleted && task.Status != fulfillment.StatusCancelled {
+			entry.Open = true
+		}
+	}
+	var history []fulfillment.LocationCountHistory
+	for _, entry := range byLocation {
+		history = append(history, *entry)
+	}
+	return history, nil
+}
+
+func (r *memoryRepository) CreateCountTrigger(ctx context.Context, trigger *fulfillment.CountTrigger) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *trigger
+	r.triggers = append(r.triggers, &copied)
+	return nil
+}
+
+func (r *memoryRepository) ListPendingCountTriggers(ctx context.Context) ([]*fulfillment.CountTrigger, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var triggers []*fulfillment.CountTrigger
+	for _, trigger := range r.triggers {
+		if trigger.TaskID == "" {
+			copied := *trigger
+			triggers = append(triggers, &copied)
+		}
+	}
+	return triggers, nil
+}
+
+func (r *memoryRepository) MarkCountTriggersPlanned(ctx context.Context, ids []string, taskID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, trigger := range r.triggers {
+		for _, id := range ids {
+			if trigger.ID == id {
+				trigger.TaskID = taskID
+			}
+		}
+	}
+	return nil
+}
+
+// noopPublisher descarta todos os eventos publicados
+type noopPublisher struct{}
+
+func (p *noopPublisher) PublishInboundReceived(ctx context.Context, shipment *fulfillment.InboundShipment) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishOutboundShipped(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishPickingStarted(ctx context.Context, order *fulfillment.FulfillmentOrder) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishReturnRegistered(ctx context.Context, returnOrder *fulfillment.ReturnOrder) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishReturnCompleted(ctx context.Context, returnOrder *fulfillment.ReturnOrder) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishTransferCreated(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishTransferCompleted(ctx context.Context, transfer *fulfillment.TransferOrder) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishCycleCountOpened(ctx context.Context, task *fulfillment.CycleCountTask) error {
+	return nil
+}
+
+func (p *noopPublisher) PublishCycleCountCompleted(ctx context.Context, task *fulfillment.CycleCountTask) error {
+	return nil
+}
+
+func (r *memoryRepository) AddManifestShipment(ctx context.Context, manifest *fulfillment.CarrierManifest, shipment *fulfillment.OutboundShipment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.outbound {
+		if existing.FulfillmentOrderID == shipment.FulfillmentOrderID {
+			return fulfillment.ErrOutboundShipmentExists
+		}
+	}
+	if stored, ok := r.manifests[manifest.ID]; ok && stored.Status != fulfillment.ManifestOpen {
+		return fulfillment.ErrManifestClosed
+	}
+	copiedManifest, copiedShipment := *manifest, *shipment
+	r.manifests[manifest.ID] = &copiedManifest
+	r.outbound[shipment.ID] = &copiedShipment
+	return nil
+}
+
+func (r *memoryRepository) GetManifest(ctx context.Context, id string) (*fulfillment.CarrierManifest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	manifest, ok := r.manifests[id]
+	if !ok {
+		return nil, fulfillment.ErrManifestNotFound
+	}
+	copied := *manifest
+	return &copied, nil
+}
+
+func (r *memoryRepository) GetManifestFor(ctx context.Context, carrier string, date time.Time) (*fulfillment.CarrierManifest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, manifest := range r.manifests {
+		if manifest.Carrier == carrier && manifest.Date.Equal(fulfillment.ManifestDate(date)) {
+			copied := *manifest
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrManifestNotFound
+}
+
+func (r *memoryRepository) ListManifests(ctx context.Context, carrier string, date *time.Time) ([]*fulfillment.CarrierManifest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var manifests []*fulfillment.CarrierManifest
+	for _, manifest := range r.manifests {
+		if (carrier == "" || manifest.Carrier == carrier) && (date == nil || manifest.Date.Equal(fulfillment.ManifestDate(*date))) {
+			copied := *manifest
+			manifests = append(manifests, &copied)
+		}
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].Carrier < manifests[j].Carrier })
+	return manifests, nil
+}
+
+func (r *memoryRepository) ListManifestShipments(ctx context.Context, manifestID string) ([]*fulfillment.OutboundShipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.manifestShipments(manifestID), nil
+}
+
+func (r *memoryRepository) manifestShipments(manifestID string) []*fulfillment.OutboundShipment {
+	var shipments []*fulfillment.OutboundShipment
+	for _, shipment := range r.outbound {
+		if shipment.ManifestID == manifestID {
+			copied := *shipment
+			shipments = append(shipments, &copied)
+		}
+	}
+	sort.Slice(shipments, func(i, j int) bool { return shipments[i].FulfillmentOrderID < shipments[j].FulfillmentOrderID })
+	return shipments
+}
+
+func (r *memoryRepository) GetOutboundShipmentByOrder(ctx context.Context, orderID string) (*fulfillment.OutboundShipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, shipment := range r.outbound {
+		if shipment.FulfillmentOrderID == orderID {
+			copied := *shipment
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrOutboundShipmentNotFound
+}
+
+func (r *memoryRepository) CloseManifest(ctx context.Context, id string, close fulfillment.ManifestCloser) (*fulfillment.CarrierManifest, []*fulfillment.OutboundShipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.manifests[id]
+	if !ok {
+		return nil, nil, fulfillment.ErrManifestNotFound
+	}
+	manifest := *stored
+	shipments := r.manifestShipments(id)
+	documents, err := close(&manifest, shipments)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.manifests[id] = &manifest
+	r.documents[id] = documents
+	return &manifest, shipments, nil
+}
+
+func (r *memoryRepository) GetManifestDocument(ctx context.Context, id string, format fulfillment.ManifestFormat) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.manifests[id]; !ok {
+		return nil, fulfillment.ErrManifestNotFound
+	}
+	document, ok := r.documents[id][format]
+	if !ok {
+		return nil, fulfillment.ErrManifestNotClosed
+	}
+	return document, nil
+}
+
+func (r *memoryRepository) SaveDocumentTemplate(ctx context.Context, tmpl *fulfillment.DocumentTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, existing := range r.templates {
+		if existing.Customer == tmpl.Customer && existing.Type == tmpl.Type && existing.Format == tmpl.Format {
+			tmpl.ID = id
+		}
+	}
+	copied := *tmpl
+	r.templates[tmpl.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) GetDocumentTemplate(ctx context.Context, customer string, docType fulfillment.DocumentType, format fulfillment.DocumentFormat) (*fulfillment.DocumentTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tmpl := range r.templates {
+		if tmpl.Customer == customer && tmpl.Type == docType && tmpl.Format == format {
+			copied := *tmpl
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrDocumentTemplateNotFound
+}
+
+func (r *memoryRepository) ListDocumentTemplates(ctx context.Context) ([]*fulfillment.DocumentTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	templates := make([]*fulfillment.DocumentTemplate, 0, len(r.templates))
+	for _, tmpl := range r.templates {
+		copied := *tmpl
+		templates = append(templates, &copied)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Customer != templates[j].Customer {
+			return templates[i].Customer < templates[j].Customer
+		}
+		if templates[i].Type != templates[j].Type {
+			return templates[i].Type < templates[j].Type
+		}
+		return templates[i].Format < templates[j].Format
+	})
+	return templates, nil
+}
+
+func (r *memoryRepository) DeleteDocumentTemplate(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.templates[id]; !ok {
+		return fulfillment.ErrDocumentTemplateNotFound
+	}
+	delete(r.templates, id)
+	return nil
+}
+
+func (r *memoryRepository) CreateRenderedDocument(ctx context.Context, document *fulfillment.RenderedDocument) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *document
+	r.rendered = append(r.rendered, &copied)
+	return nil
+}
+
+func (r *memoryRepository) GetRenderedDocument(ctx context.Context, id string) (*fulfillment.RenderedDocument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, document := range r.rendered {
+		if document.ID == id {
+			copied := *document
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrDocumentNotFound
+}
+
+func (r *memoryRepository) ListRenderedDocuments(ctx context.Context, orderID string) ([]*fulfillment.RenderedDocument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var documents []*fulfillment.RenderedDocument
+	for idx := len(r.rendered) - 1; idx >= 0; idx-- {
+		if r.rendered[idx].FulfillmentOrderID == orderID {
+			copied := *r.rendered[idx]
+			copied.Content = nil
+			documents = append(documents, &copied)
+		}
+	}
+	return documents, nil
+}
+
+func (r *memoryRepository) CreatePickSession(ctx context.Context, session *fulfillment.PickSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.picking {
+		if stored.OrderID == session.OrderID && stored.Status == fulfillment.StatusInProgress {
+			return fulfillment.ErrPickSessionExists
+		}
+	}
+	r.picking[session.ID] = copyPickSession(session)
+	return nil
+}
+
+func (r *memoryRepository) GetPickSession(ctx context.Context, id string) (*fulfillment.PickSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.picking[id]
+	if !ok {
+		return nil, fulfillment.ErrPickSessionNotFound
+	}
+	return copyPickSession(session), nil
+}
+
+func (r *memoryRepository) GetActivePickSession(ctx context.Context, orderID string) (*fulfillment.PickSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.picking {
+		if session.OrderID == orderID && session.Status == fulfillment.StatusInProgress {
+			return copyPickSession(session), nil
+		}
+	}
+	return nil, fulfillment.ErrPickSessionNotFound
+}
+
+func (r *memoryRepository) UpdatePickSession(ctx context.Context, session *fulfillment.PickSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.picking[session.ID]
+	if !ok {
+		return fulfillment.ErrPickSessionNotFound
+	}
+	if stored.Version != session.Version {
+		return fulfillment.ErrPickSessionConflict
+	}
+	session.Version++
+	r.picking[session.ID] = copyPickSession(session)
+	return nil
+}
+
+func copyPickSession(session *fulfillment.PickSession) *fulfillment.PickSession {
+	copied := *session
+	copied.Steps = append([]fulfillment.PickStep(nil), session.Steps...)
+	return &copied
+}
+
+func (r *memoryRepository) GetSyncState(ctx context.Context, key string) (*fulfillment.SyncState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.syncState[key]
+	if !ok {
+		return nil, fulfillment.ErrSyncStateNotFound
+	}
+	copied := *state
+	return &copied, nil
+}
+
+func (r *memoryRepository) SaveSyncState(ctx context.Context, state *fulfillment.SyncState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.syncState[state.Key]
+	if ok != (state.Version > 0) || (ok && stored.Version != state.Version) {
+		return fulfillment.ErrSyncStateConflict
+	}
+	state.Version++
+	copied := *state
+	r.syncState[state.Key] = &copied
+	return nil
+}
+
+func (r *memoryRepository) DeleteSyncState(ctx context.Context, key string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.syncState[key]
+	if !ok || stored.Version != version {
+		return fulfillment.ErrSyncStateConflict
+	}
+	delete(r.syncState, key)
+	return nil
+}
+
+func (r *memoryRepository) CreateSyncConflict(ctx context.Context, conflict *fulfillment.SyncConflictRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.conflicts {
+		if stored.Device.DeviceID == conflict.Device.DeviceID && stored.Device.ID == conflict.Device.ID {
+			conflict.ID = stored.ID
+			return nil
+		}
+	}
+	copied := *conflict
+	r.conflicts = append(r.conflicts, &copied)
+	return nil
+}
+
+func (r *memoryRepository) GetSyncConflict(ctx context.Context, id string) (*fulfillment.SyncConflictRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.conflicts {
+		if stored.ID == id {
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	return nil, fulfillment.ErrSyncConflictNotFound
+}
+
+func (r *memoryRepository) ListSyncConflicts(ctx context.Context, status fulfillment.Status) ([]*fulfillment.SyncConflictRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var conflicts []*fulfillment.SyncConflictRecord
+	for _, stored := range r.conflicts {
+		if status == "" || stored.Status == status {
+			copied := *stored
+			conflicts = append(conflicts, &copied)
+		}
+	}
+	return conflicts, nil
+}
+
+func (r *memoryRepository) UpdateSyncConflict(ctx context.Context, conflict *fulfillment.SyncConflictRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for idx, stored := range r.conflicts {
+		if stored.ID == conflict.ID {
+			copied := *conflict
+			r.conflicts[idx] = &copied
+			return nil
+		}
+	}
+	return fulfillment.ErrSyncConflictNotFound
+}
+
+// SaveCutoffCalendar implementa fulfillment.PriorityRepository (único por transportadora e serviço)
+func (r *memoryRepository) SaveCutoffCalendar(ctx context.Context, calendar *fulfillment.CutoffCalendar) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, existing := range r.calendars {
+		if existing.Carrier == calendar.Carrier && existing.Service == calendar.Service {
+			calendar.ID = id
+		}
+	}
+	copied := *calendar
+	r.calendars[calendar.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) ListCutoffCalendars(ctx context.Context, carrier string) ([]*fulfillment.CutoffCalendar, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calendars []*fulfillment.CutoffCalendar
+	for _, calendar := range r.calendars {
+		if carrier == "" || calendar.Carrier == carrier {
+			copied := *calendar
+			calendars = append(calendars, &copied)
+		}
+	}
+	return calendars, nil
+}
+
+func (r *memoryRepository) DeleteCutoffCalendar(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.calendars[id]; !ok {
+		return fulfillment.ErrCutoffCalendarNotFound
+	}
+	delete(r.calendars, id)
+	return nil
+}
+
+func (r *memoryRepository) ListOpenOrders(ctx context.Context, afterID string, limit int) ([]*fulfillment.FulfillmentOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*fulfillment.FulfillmentOrder
+	for _, order := range r.orders {
+		if (order.Status == fulfillment.StatusPending || order.Status == fulfillment.StatusInProgress) && order.ID > afterID {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	natsAdapter "github.com/vertikon/mcp-fulfillment-ops/internal/adapters/nats"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// TestInventoryRequestClientOffline valida o fluxo de recebimento usando o
+// InventoryClient NATS contra o responder local (sem servidor NATS nem Core)
+func TestInventoryRequestClientOffline(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+
+	responder := natsAdapter.NewLocalInventoryResponder(natsAdapter.NewZapLoggerAdapter(logger))
+	client := natsAdapter.NewInventoryRequestClient(natsAdapter.NewLocalRequester(responder), time.Second, natsAdapter.NewZapLoggerAdapter(logger))
+
+	repo := newMemoryRepository()
+	uc := app.NewReceiveGoodsUseCase(repo, client, &noopPublisher{}, app.NewZapLoggerAdapter(logger))
+
+	shipment, err := uc.StartInbound(ctx, "PO-001", "Fornecedor A", "CD-SP", []fulfillment.Item{{SKU: "SKU-001", Quantity: 10}})
+	require.NoError(t, err)
+
+	require.NoError(t, uc.ConfirmReceipt(ctx, shipment.ID))
+	assert.Equal(t, 10, responder.Stock("CD-SP", "SKU-001"))
+
+	available, err := client.GetAvailableStock(ctx, "CD-SP", "SKU-001")
+	require.NoError(t, err)
+	assert.Equal(t, 10, available)
+
+	// Saída maior que o saldo é rejeitada pelo responder
+	err = client.AdjustStock(ctx, "CD-SP", "SKU-001", -20, "")
+	assert.Error(t, err)
+	assert.Equal(t, 10, responder.Stock("CD-SP", "SKU-001"))
+}
+
+func TestInventoryRequestClientOffline_ContextCancelled(t *testing.T) {
+	logger := natsAdapter.NewZapLoggerAdapter(zap.NewNop())
+	responder := natsAdapter.NewLocalInventoryResponder(logger)
+	client := natsAdapter.NewInventoryRequestClient(natsAdapter.NewLocalRequester(responder), time.Second, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetAvailableStock(ctx, "CD-SP", "SKU-001")
+	assert.Error(t, err)
+}
+
+// recordingJetStream registra as mensagens publicadas; métodos não sobrescritos causam panic
+type recordingJetStream struct {
+	jetstream.JetStream
+	published []*nats.Msg
+}
+
+func (j *recordingJetStream) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	j.published = append(j.published, msg)
+	return &jetstream.PubAck{Sequence: uint64(len(j.published))}, nil
+}
+
+// Cada comando de uma mesma operação (mesma correlação) tem seu próprio ID de deduplicação
+func TestInventoryJetStreamClient_CommandIDsPerCommand(t *testing.T) {
+	logger := natsAdapter.NewZapLoggerAdapter(zap.NewNop())
+	js := &recordingJetStream{}
+	client := natsAdapter.NewInventoryJetStreamClient(natsAdapter.NewLocalRequester(natsAdapter.NewLocalInventoryResponder(logger)), js, time.Second, logger)
+	ctx := fulfillment.WithCorrelationID(context.Background(), "corr-1")
+
+	require.NoError(t, client.AdjustStock(ctx, "CD-SP", "SKU-001", 5, ""))
+	require.NoError(t, client.AdjustStock(ctx, "CD-SP", "SKU-002", 3, ""))
+	require.NoError(t, client.AdjustStock(ctx, "CD-SP", "SKU-001", 5, ""))
+	require.Len(t, js.published, 3)
+
+	ids := map[string]bool{}
+	for _, msg := range js.published {
+		var cmd natsAdapter.InventoryCommand
+		require.NoError(t, json.Unmarshal(msg.Data, &cmd))
+		assert.Equal(t, "corr-1", msg.Header.Get(natsAdapter.HeaderCorrelationID))
+		assert.Equal(t, "corr-1", cmd.CorrelationID)
+		require.NotEmpty(t, cmd.CommandID)
+		ids[cmd.CommandID] = true
+	}
+	assert.Len(t, ids, 3)
+}
+
+// lostReplyRequester entrega o comando ao responder, mas perde as próximas n respostas (timeout)
+type lostReplyRequester struct {
+	natsAdapter.Requester
+	lost int
+}
+
+func (r *lostReplyRequester) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	resp, err := r.Requester.RequestMsgWithContext(ctx, msg)
+	if err == nil && r.lost > 0 {
+		r.lost--
+		return nil, context.DeadlineExceeded
+	}
+	return resp, err
+}
+
+// Um ajuste aplicado pelo Core cuja resposta se perdeu não é aplicado de novo na reexecução
+func TestInventoryRequestClient_RetryAfterLostReplyIsDeduplicated(t *testing.T) {
+	ctx := context.Background()
+	logger := natsAdapter.NewZapLoggerAdapter(zap.NewNop())
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	responder := natsAdapter.NewLocalInventoryResponder(logger)
+	requester := &lostReplyRequester{Requester: natsAdapter.NewLocalRequester(responder)}
+	client := natsAdapter.NewInventoryRequestClient(requester, time.Second, logger)
+
+	repo := newMemoryRepository()
+	receive := app.NewReceiveGoodsUseCase(repo, client, &noopPublisher{}, appLogger)
+	retry := app.NewRetryFailedUseCase(repo, repo, receive, nil, nil, nil, nil, appLogger)
+
+	shipment, err := receive.StartInbound(ctx, "PO-002", "Fornecedor A", "CD-SP", []fulfillment.Item{{SKU: "SKU-001", Quantity: 10}})
+	require.NoError(t, err)
+
+	requester.lost = 1
+	err = receive.ConfirmReceipt(ctx, shipment.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrTransientFailure)
+	assert.Equal(t, 10, responder.Stock("CD-SP", "SKU-001"), "the core applied the adjustment")
+
+	require.NoError(t, retry.Retry(ctx, fulfillment.EntityInboundShipment, shipment.ID))
+	assert.Equal(t, 10, responder.Stock("CD-SP", "SKU-001"))
+
+	// Comandos de outras etapas continuam sendo aplicados
+	require.NoError(t, client.AdjustStock(ctx, "CD-SP", "SKU-001", 5, ""))
+	assert.Equal(t, 15, responder.Stock("CD-SP", "SKU-001"))
+}
+
+// A reexecução da mesma etapa publica o mesmo ID de deduplicação
+func TestInventoryJetStreamClient_StableCommandIDForStep(t *testing.T) {
+	logger := natsAdapter.NewZapLoggerAdapter(zap.NewNop())
+	js := &recordingJetStream{}
+	client := natsAdapter.NewInventoryJetStreamClient(natsAdapter.NewLocalRequester(natsAdapter.NewLocalInventoryResponder(logger)), js, time.Second, logger)
+	ctx := fulfillment.WithCommandID(context.Background(), fulfillment.CommandID("IN-1", fulfillment.StepAdjustStock, 0))
+
+	require.NoError(t, client.AdjustStock(ctx, "CD-SP", "SKU-001", 5, ""))
+	require.NoError(t, client.AdjustStock(fulfillment.WithCorrelationID(ctx, "retry"), "CD-SP", "SKU-001", 5, ""))
+	require.Len(t, js.published, 2)
+	var first, retried natsAdapter.InventoryCommand
+	require.NoError(t, json.Unmarshal(js.published[0].Data, &first))
+	require.NoError(t, json.Unmarshal(js.published[1].Data, &retried))
+	assert.NotEmpty(t, first.CommandID)
+	assert.Equal(t, first.CommandID, retried.CommandID)
+}
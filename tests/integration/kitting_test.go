@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+var kitComponents = []fulfillment.KitComponent{{SKU: "PART-1", Quantity: 2}, {SKU: "PART-2", Quantity: 1}}
+
+func newAssemblyFixture(f *taskFixture) *app.AssemblyUseCase {
+	assembly := app.NewAssemblyUseCase(f.repo, f.repo, f.client, f.ship, app.NewZapLoggerAdapter(zap.NewNop()))
+	f.ship.OnBackordered(assembly.OnBackordered)
+	return assembly
+}
+
+func TestAssembly_AssembleAndDisassemble(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	uc := newAssemblyFixture(f)
+	f.responder.SetStock("K-01-01", "PART-1", 10)
+	f.responder.SetStock("K-01-01", "PART-2", 5)
+
+	_, err := uc.DefineKit(ctx, "KIT-1", "Kit presente", kitComponents)
+	require.NoError(t, err)
+
+	order, err := uc.CreateOrder(ctx, "KIT-1", fulfillment.AssemblyAssemble, 3, "K-01-01", "")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, order.Status)
+	assert.Equal(t, 4, f.responder.Stock("K-01-01", "PART-1"))
+	assert.Equal(t, 2, f.responder.Stock("K-01-01", "PART-2"))
+	assert.Equal(t, 3, f.responder.Stock("K-01-01", "KIT-1"))
+
+	_, err = uc.CreateOrder(ctx, "KIT-1", fulfillment.AssemblyDisassemble, 1, "K-01-01", "")
+	require.NoError(t, err)
+	assert.Equal(t, 6, f.responder.Stock("K-01-01", "PART-1"))
+	assert.Equal(t, 3, f.responder.Stock("K-01-01", "PART-2"))
+	assert.Equal(t, 2, f.responder.Stock("K-01-01", "KIT-1"))
+
+	_, err = uc.DefineKit(ctx, "KIT-2", "", []fulfillment.KitComponent{{SKU: "KIT-1", Quantity: 1}})
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidKit, "nested kits are rejected")
+}
+
+func TestAssembly_CompensatesPartialMovements(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	uc := newAssemblyFixture(f)
+	f.responder.SetStock("K-01-01", "PART-1", 10)
+	f.responder.SetStock("K-01-01", "PART-2", 1)
+
+	_, err := uc.DefineKit(ctx, "KIT-1", "", kitComponents)
+	require.NoError(t, err)
+
+	// PART-1 é consumido, PART-2 não tem saldo: a baixa de PART-1 é revertida
+	order, err := uc.CreateOrder(ctx, "KIT-1", fulfillment.AssemblyAssemble, 2, "K-01-01", "")
+	require.Error(t, err)
+	require.NotNil(t, order)
+	assert.Equal(t, fulfillment.StatusFailed, order.Status)
+	assert.Equal(t, 1, order.Failure.Item)
+	assert.Empty(t, order.Unreconciled)
+	assert.Equal(t, 10, f.responder.Stock("K-01-01", "PART-1"))
+	assert.Equal(t, 0, f.responder.Stock("K-01-01", "KIT-1"))
+
+	f.responder.SetStock("K-01-01", "PART-2", 4)
+	order, err = uc.Execute(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, order.Status)
+	assert.Equal(t, 6, f.responder.Stock("K-01-01", "PART-1"))
+	assert.Equal(t, 2, f.responder.Stock("K-01-01", "KIT-1"))
+}
+
+func TestAssembly_BulkRetryIncludesFailedAssemblyOrders(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	uc := newAssemblyFixture(f)
+	retry := app.NewRetryFailedUseCase(f.repo, f.repo, f.receive, f.ship, nil, nil, nil, app.NewZapLoggerAdapter(zap.NewNop()))
+	retry.UseAssembly(uc)
+	f.responder.SetStock("K-01-01", "PART-1", 10)
+
+	_, err := uc.DefineKit(ctx, "KIT-1", "", kitComponents)
+	require.NoError(t, err)
+	order, err := uc.CreateOrder(ctx, "KIT-1", fulfillment.AssemblyAssemble, 2, "K-01-01", "")
+	require.Error(t, err)
+
+	since := time.Now().Add(-time.Hour)
+	failed, err := retry.ListFailed(ctx, fulfillment.EntityAssemblyOrder, since)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, order.ID, failed[0].EntityID)
+
+	f.responder.SetStock("K-01-01", "PART-2", 4)
+	report, err := retry.RetryAllSince(ctx, "", since)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Succeeded)
+
+	completed, err := uc.GetOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, completed.Status)
+	assert.Equal(t, 2, f.responder.Stock("K-01-01", "KIT-1"))
+}
+
+func TestAssembly_OnDemandForBackorderedKitOrder(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	uc := newAssemblyFixture(f)
+	f.responder.SetStock("K-01-01", "PART-1", 10)
+	f.responder.SetStock("K-01-01", "PART-2", 10)
+	f.responder.SetStock("K-01-01", "KIT-1", 1)
+
+	_, err := uc.DefineKit(ctx, "KIT-1", "", kitComponents)
+	require.NoError(t, err)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{{SKU: "KIT-1", Quantity: 3, Location: "K-01-01"}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationReserved, order.ReservationStatus, "missing kits assembled and backorder released")
+	assert.Equal(t, 6, f.responder.Stock("K-01-01", "PART-1"), "only the shortfall is assembled")
+	assert.Equal(t, 3, f.responder.Stock("K-01-01", "KIT-1"))
+
+	// Componentes insuficientes: a ordem permanece em backorder sem alterar o estoque
+	backordered, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua B", []fulfillment.Item{{SKU: "KIT-1", Quantity: 5, Location: "K-01-01"}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationBackordered, backordered.ReservationStatus)
+	assert.Equal(t, 6, f.responder.Stock("K-01-01", "PART-1"))
+}
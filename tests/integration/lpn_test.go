@@ -0,0 +1,208 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func newLPNFixture() (*taskFixture, *app.LPNUseCase) {
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfer := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	return f, app.NewLPNUseCase(f.repo, f.repo, f.receive, transfer, f.ship, appLogger)
+}
+
+// flakyLPNs falha a próxima gravação de LPNs (depois de deixar passar skip gravações)
+type flakyLPNs struct {
+	fulfillment.LPNRepository
+	failNext bool
+	skip     int
+}
+
+func (r *flakyLPNs) SaveLPNs(ctx context.Context, lpns []*fulfillment.LPN, events []fulfillment.LPNEvent) error {
+	if r.failNext && r.skip > 0 {
+		r.skip--
+	} else if r.failNext {
+		r.failNext = false
+		return errors.New("connection reset")
+	}
+	return r.LPNRepository.SaveLPNs(ctx, lpns, events)
+}
+
+// mixedPallet anuncia um palete com duas caixas e itens soltos
+func mixedPallet(t *testing.T, ctx context.Context, lpns *app.LPNUseCase) {
+	_, err := lpns.Create(ctx, "PLT-1", fulfillment.LPNPallet, "", "", []fulfillment.Item{{SKU: "SKU-003", Quantity: 4}})
+	require.NoError(t, err)
+	_, err = lpns.Create(ctx, "CS-1", fulfillment.LPNCase, "", "PLT-1", []fulfillment.Item{{SKU: "SKU-001", Quantity: 12}})
+	require.NoError(t, err)
+	_, err = lpns.Create(ctx, "CS-2", fulfillment.LPNCase, "", "PLT-1", []fulfillment.Item{{SKU: "SKU-001", Quantity: 6}, {SKU: "SKU-002", Quantity: 6}})
+	require.NoError(t, err)
+}
+
+func TestLPN_ReceiveMoveAndShipWholePallet(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f, lpns := newLPNFixture()
+	mixedPallet(t, ctx, lpns)
+
+	// Recebimento: um único InboundShipment com o conteúdo de todo o palete
+	received, err := lpns.Receive(ctx, "PLT-1", "", "Fornecedor", "DOCK-1")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.LPNActive, received.Status)
+	assert.Equal(t, 18, f.responder.Stock("DOCK-1", "SKU-001"))
+	assert.Equal(t, 6, f.responder.Stock("DOCK-1", "SKU-002"))
+	assert.Equal(t, 4, f.responder.Stock("DOCK-1", "SKU-003"))
+
+	_, err = lpns.Receive(ctx, "PLT-1", "", "Fornecedor", "DOCK-1")
+	assert.ErrorIs(t, err, fulfillment.ErrLPNNotHandleable, "already received")
+
+	// Armazenagem: o palete leva as caixas junto
+	_, err = lpns.Move(ctx, "PLT-1", "STAGE-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, f.responder.Stock("DOCK-1", "SKU-001"))
+	assert.Equal(t, 18, f.responder.Stock("STAGE-1", "SKU-001"))
+
+	inquiry, err := lpns.Inquiry(ctx, "CS-2")
+	require.NoError(t, err)
+	assert.Equal(t, "STAGE-1", inquiry.LPN.Location)
+	assert.Equal(t, "PLT-1", inquiry.LPN.Parent)
+
+	// Caixa aninhada não é expedida sozinha
+	_, err = lpns.Ship(ctx, "CS-2", "any")
+	assert.ErrorIs(t, err, fulfillment.ErrLPNNotHandleable)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 18, Location: "STAGE-1"},
+		{SKU: "SKU-002", Quantity: 6, Location: "STAGE-1"},
+		{SKU: "SKU-003", Quantity: 4, Location: "STAGE-1"},
+	}, 0)
+	require.NoError(t, err)
+
+	shipped, err := lpns.Ship(ctx, "PLT-1", order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.LPNShipped, shipped.Status)
+	assert.Equal(t, 0, f.responder.Stock("STAGE-1", "SKU-001"))
+
+	stored, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, stored.Status)
+
+	inquiry, err = lpns.Inquiry(ctx, "PLT-1")
+	require.NoError(t, err)
+	assert.Len(t, inquiry.Children, 2)
+	assert.Equal(t, []fulfillment.Item{{SKU: "SKU-001", Quantity: 18}, {SKU: "SKU-002", Quantity: 6}, {SKU: "SKU-003", Quantity: 4}}, inquiry.Totals)
+	var actions []fulfillment.LPNAction
+	for _, event := range inquiry.History {
+		actions = append(actions, event.Action)
+		assert.Equal(t, "operador-1", event.Actor)
+	}
+	assert.Equal(t, []fulfillment.LPNAction{fulfillment.LPNCreated, fulfillment.LPNReceived, fulfillment.LPNMoved, fulfillment.LPNShip}, actions)
+}
+
+func TestLPN_MoveNestedCaseDetachesAndShipChecksContents(t *testing.T) {
+	ctx := context.Background()
+	f, lpns := newLPNFixture()
+	mixedPallet(t, ctx, lpns)
+	_, err := lpns.Receive(ctx, "PLT-1", "ASN-9", "Fornecedor", "DOCK-1")
+	require.NoError(t, err)
+
+	// Transferir uma caixa retira-a do palete; o restante fica na doca
+	moved, err := lpns.Move(ctx, "CS-1", "A-01-01")
+	require.NoError(t, err)
+	assert.Empty(t, moved.Parent)
+	assert.Equal(t, 12, f.responder.Stock("A-01-01", "SKU-001"))
+	assert.Equal(t, 6, f.responder.Stock("DOCK-1", "SKU-001"))
+
+	pallet, err := lpns.Inquiry(ctx, "PLT-1")
+	require.NoError(t, err)
+	assert.Len(t, pallet.Children, 1)
+
+	// Conteúdo diferente da ordem não é expedido
+	order, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua B", []fulfillment.Item{{SKU: "SKU-001", Quantity: 10, Location: "A-01-01"}}, 0)
+	require.NoError(t, err)
+	_, err = lpns.Ship(ctx, "CS-1", order.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrLPNContentsMismatch)
+
+	// Caixa não cabe em caixa; duplicidade de código é rejeitada
+	_, err = lpns.Nest(ctx, "CS-2", "CS-1")
+	assert.ErrorIs(t, err, fulfillment.ErrLPNNesting)
+	_, err = lpns.Create(ctx, "CS-1", fulfillment.LPNCase, "A-01-01", "", nil)
+	assert.ErrorIs(t, err, fulfillment.ErrLPNExists)
+}
+
+func TestLPN_ReceiveRetryAfterSaveFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	store := &flakyLPNs{LPNRepository: f.repo}
+	transfer := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	lpns := app.NewLPNUseCase(store, f.repo, f.receive, transfer, f.ship, appLogger)
+	mixedPallet(t, ctx, lpns)
+
+	// O recebimento é concluído no Core, mas o estado do palete não é salvo
+	store.failNext = true
+	_, err := lpns.Receive(ctx, "PLT-1", "", "Fornecedor", "DOCK-1")
+	require.Error(t, err)
+	assert.Equal(t, 18, f.responder.Stock("DOCK-1", "SKU-001"))
+
+	// A repetição apenas salva o palete, sem reaplicar o estoque
+	received, err := lpns.Receive(ctx, "PLT-1", "", "Fornecedor", "DOCK-1")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.LPNActive, received.Status)
+	assert.Equal(t, 18, f.responder.Stock("DOCK-1", "SKU-001"))
+
+	inquiry, err := lpns.Inquiry(ctx, "CS-1")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.LPNActive, inquiry.LPN.Status)
+	assert.Equal(t, "DOCK-1", inquiry.LPN.Location)
+}
+
+func TestLPN_MoveAndShipRetryAfterSaveFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	store := &flakyLPNs{LPNRepository: f.repo}
+	transfer := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	lpns := app.NewLPNUseCase(store, f.repo, f.receive, transfer, f.ship, appLogger)
+	mixedPallet(t, ctx, lpns)
+	_, err := lpns.Receive(ctx, "PLT-1", "", "Fornecedor", "DOCK-1")
+	require.NoError(t, err)
+
+	// A transferência é concluída no Core, mas o estado do palete não é salvo
+	store.failNext, store.skip = true, 1
+	_, err = lpns.Move(ctx, "PLT-1", "STAGE-1")
+	require.Error(t, err)
+	assert.Equal(t, 18, f.responder.Stock("STAGE-1", "SKU-001"))
+
+	// A repetição retoma a mesma transferência em vez de criar outra a partir da doca
+	moved, err := lpns.Move(ctx, "PLT-1", "STAGE-1")
+	require.NoError(t, err)
+	assert.Equal(t, "STAGE-1", moved.Location)
+	assert.Empty(t, moved.PendingTransfer)
+	assert.Equal(t, 0, f.responder.Stock("DOCK-1", "SKU-001"))
+	assert.Equal(t, 18, f.responder.Stock("STAGE-1", "SKU-001"))
+	assert.Len(t, f.repo.transfers, 1)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 18, Location: "STAGE-1"},
+		{SKU: "SKU-002", Quantity: 6, Location: "STAGE-1"},
+		{SKU: "SKU-003", Quantity: 4, Location: "STAGE-1"},
+	}, 0)
+	require.NoError(t, err)
+
+	// A ordem é expedida, mas o estado do palete não é salvo; a repetição só salva o palete
+	store.failNext = true
+	_, err = lpns.Ship(ctx, "PLT-1", order.ID)
+	require.Error(t, err)
+	shipped, err := lpns.Ship(ctx, "PLT-1", order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.LPNShipped, shipped.Status)
+	assert.Equal(t, 0, f.responder.Stock("STAGE-1", "SKU-001"))
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// recordingManifestPublisher registra os manifestos fechados e seus rastreios
+type recordingManifestPublisher struct {
+	mu       sync.Mutex
+	closed   []string
+	tracking map[string][]string
+}
+
+func (p *recordingManifestPublisher) PublishManifestClosed(ctx context.Context, manifest *fulfillment.CarrierManifest, trackingNumbers []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tracking == nil {
+		p.tracking = make(map[string][]string)
+	}
+	p.closed = append(p.closed, manifest.ID)
+	p.tracking[manifest.ID] = trackingNumbers
+	return nil
+}
+
+func TestManifest_HandOverCloseAndDocuments(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "expedicao-1")
+	f := newTaskFixture()
+	publisher := &recordingManifestPublisher{}
+	uc := app.NewManifestUseCase(f.repo, f.repo, publisher, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	f.responder.SetStock("A-01", "SKU-001", 10)
+	var orderIDs []string
+	for _, id := range []string{"OMS-1", "OMS-2", "OMS-3"} {
+		order, err := f.ship.CreateOrder(ctx, id, "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1, Location: "A-01"}}, 0)
+		require.NoError(t, err)
+		orderIDs = append(orderIDs, order.ID)
+	}
+	for _, id := range orderIDs[:2] {
+		require.NoError(t, f.ship.StartPicking(ctx, id))
+		require.NoError(t, f.ship.Ship(ctx, id))
+	}
+
+	// Ordem ainda não expedida não entra no manifesto
+	_, err := uc.HandOver(ctx, orderIDs[2], "TRANSP-X", "TRK-3", 1, 1)
+	assert.ErrorIs(t, err, fulfillment.ErrOrderNotShipped)
+
+	first, err := uc.HandOver(ctx, orderIDs[0], "TRANSP-X", "TRK-1", 2, 3.5)
+	require.NoError(t, err)
+	second, err := uc.HandOver(ctx, orderIDs[1], "TRANSP-X", "TRK-2", 1, 1.25)
+	require.NoError(t, err)
+	assert.Equal(t, first.ManifestID, second.ManifestID)
+
+	_, err = uc.HandOver(ctx, orderIDs[0], "TRANSP-X", "TRK-1", 2, 3.5)
+	assert.ErrorIs(t, err, fulfillment.ErrOutboundShipmentExists)
+
+	_, err = uc.Document(ctx, first.ManifestID, fulfillment.ManifestCSV)
+	assert.ErrorIs(t, err, fulfillment.ErrManifestNotClosed)
+
+	manifest, err := uc.Close(ctx, first.ManifestID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ManifestClosed, manifest.Status)
+	assert.Equal(t, 2, manifest.Shipments)
+	assert.Equal(t, 3, manifest.Pieces)
+	assert.InDelta(t, 4.75, manifest.Weight, 1e-9)
+	assert.Equal(t, "expedicao-1", manifest.ClosedBy)
+	assert.Equal(t, []string{first.ManifestID}, publisher.closed)
+	assert.ElementsMatch(t, []string{"TRK-1", "TRK-2"}, publisher.tracking[first.ManifestID])
+
+	csvDocument, err := uc.Document(ctx, first.ManifestID, fulfillment.ManifestCSV)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(csvDocument)), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[3], "TOTAL,2,,3,4.75")
+
+	htmlDocument, err := uc.Document(ctx, first.ManifestID, fulfillment.ManifestHTML)
+	require.NoError(t, err)
+	assert.Contains(t, string(htmlDocument), "TRK-2")
+
+	_, err = uc.Document(ctx, first.ManifestID, "pdf")
+	assert.ErrorIs(t, err, fulfillment.ErrUnsupportedManifestFormat)
+
+	// Manifesto fechado não aceita novas expedições nem novo fechamento
+	require.NoError(t, f.ship.StartPicking(ctx, orderIDs[2]))
+	require.NoError(t, f.ship.Ship(ctx, orderIDs[2]))
+	_, err = uc.HandOver(ctx, orderIDs[2], "TRANSP-X", "TRK-3", 1, 1)
+	assert.ErrorIs(t, err, fulfillment.ErrManifestClosed)
+	_, err = uc.Close(ctx, first.ManifestID)
+	assert.ErrorIs(t, err, fulfillment.ErrManifestClosed)
+
+	// Outra transportadora abre seu próprio manifesto
+	other, err := uc.HandOver(ctx, orderIDs[2], "TRANSP-Y", "TRK-3", 1, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ManifestID, other.ManifestID)
+
+	manifests, err := uc.ListManifests(ctx, "", nil)
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	view, err := uc.GetManifest(ctx, other.ManifestID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ManifestOpen, view.Manifest.Status)
+	require.Len(t, view.Shipments, 1)
+	assert.Equal(t, orderIDs[2], view.Shipments[0].FulfillmentOrderID)
+}
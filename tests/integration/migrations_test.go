@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/adapters/postgres"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/adapters/postgres/migrations"
+)
+
+func TestEmbeddedMigrations_AreContiguousAndReversible(t *testing.T) {
+	list, err := postgres.LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+
+	for i, m := range list {
+		assert.Equal(t, i+1, m.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, m.Down, "migration %04d_%s has no down script", m.Version, m.Name)
+		assert.Len(t, m.Checksum, 64)
+	}
+}
+
+func TestLoadMigrations_PairsUpAndDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_second.sql":      {Data: []byte("CREATE TABLE b ();")},
+		"0001_first.sql":       {Data: []byte("CREATE TABLE a ();")},
+		"0001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"0002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+
+	list, err := postgres.LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "first", list[0].Name)
+	assert.Equal(t, "DROP TABLE a;", list[0].Down)
+	assert.NotEqual(t, list[0].Checksum, list[1].Checksum)
+}
+
+func TestLoadMigrations_RejectsInvalidFiles(t *testing.T) {
+	_, err := postgres.LoadMigrations(fstest.MapFS{"first.sql": {Data: []byte("SELECT 1;")}})
+	assert.Error(t, err)
+
+	_, err = postgres.LoadMigrations(fstest.MapFS{"0001_first.down.sql": {Data: []byte("SELECT 1;")}})
+	assert.Error(t, err, "down without up must be rejected")
+
+	_, err = postgres.LoadMigrations(fstest.MapFS{
+		"0001_first.sql":  {Data: []byte("SELECT 1;")},
+		"0001_second.sql": {Data: []byte("SELECT 2;")},
+	})
+	assert.Error(t, err, "duplicate versions must be rejected")
+}
@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestPickPath_PlansBatchOfOrders(t *testing.T) {
+	ctx := context.Background()
+	f := newTaskFixture()
+	paths := app.NewPickPathUseCase(f.repo, f.repo, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	_, err := paths.PlanRoute(ctx, nil, []fulfillment.Item{{SKU: "SKU-001", Quantity: 1, Location: "A-01-18"}})
+	assert.ErrorIs(t, err, fulfillment.ErrLayoutNotFound)
+
+	layout, err := fulfillment.NewWarehouseLayout([]fulfillment.Aisle{
+		{ID: "01", X: 0, Start: 0, End: 20},
+		{ID: "02", X: 10, Start: 0, End: 20},
+	}, nil, fulfillment.Point{}, []fulfillment.BinCoordinate{{Location: "A-01-18", Aisle: "01", Position: 18}})
+	require.NoError(t, err)
+	require.NoError(t, paths.DefineLayout(ctx, layout))
+
+	// Coordenadas cadastradas depois do layout
+	_, err = paths.SaveBins(ctx, []fulfillment.BinCoordinate{{Location: "A-02-18", Aisle: "02", Position: 18}, {Location: "A-02-02", Aisle: "02", Position: 2}})
+	require.NoError(t, err)
+	_, err = paths.SaveBins(ctx, []fulfillment.BinCoordinate{{Location: "A-03-01", Aisle: "03", Position: 1}})
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidLayout)
+
+	for loc, qty := range map[string]int{"A-01-18": 5, "A-02-18": 5, "A-02-02": 5} {
+		f.responder.SetStock(loc, "SKU-001", qty)
+	}
+	first, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 1, Location: "A-02-02"},
+		{SKU: "SKU-001", Quantity: 1, Location: "A-01-18"},
+	}, 0)
+	require.NoError(t, err)
+	second, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua B", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1, Location: "A-02-18"}}, 0)
+	require.NoError(t, err)
+
+	plan, err := paths.PlanRoute(ctx, []string{first.ID, second.ID}, nil)
+	require.NoError(t, err)
+	var stops []string
+	for _, stop := range plan.Route.Stops {
+		stops = append(stops, stop.Location)
+	}
+	assert.Equal(t, []string{"A-01-18", "A-02-18", "A-02-02"}, stops)
+	assert.Equal(t, 60.0, plan.Route.Distance)
+	require.Len(t, plan.Comparison, 3)
+	assert.Equal(t, fulfillment.RouteByLocation, plan.Comparison[0].Strategy)
+	assert.Greater(t, plan.Comparison[0].Distance, plan.Route.Distance)
+
+	_, err = paths.PlanRoute(ctx, []string{"missing"}, nil)
+	assert.ErrorIs(t, err, fulfillment.ErrOrderNotFound)
+}
@@ -0,0 +1,187 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func newPickSessionFixture(t *testing.T) (*taskFixture, *app.PickSessionUseCase) {
+	f, units := newUnitsFixture(t)
+	logger := app.NewZapLoggerAdapter(zap.NewNop())
+
+	hierarchy, err := fulfillment.NewUoMHierarchy("SKU-001", []fulfillment.PackLevel{
+		{UoM: fulfillment.UoMCase, Factor: 12, GTIN: "14006381333938"},
+	})
+	require.NoError(t, err)
+	hierarchy.GTIN = "4006381333931"
+	require.NoError(t, units.DefineHierarchy(context.Background(), hierarchy))
+
+	paths := app.NewPickPathUseCase(f.repo, f.repo, logger)
+	scan := app.NewScanUseCase(f.repo, logger)
+	return f, app.NewPickSessionUseCase(f.repo, f.repo, f.tasks, paths, scan, logger)
+}
+
+func TestPickSession_GuidesScansAndShipsOrder(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f, uc := newPickSessionFixture(t)
+	f.responder.SetStock("A-01-03", "SKU-001", 10)
+	f.responder.SetStock("A-01-01", "SKU-002", 5)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 4, Location: "A-01-03"},
+		{SKU: "SKU-002", Quantity: 3, Location: "A-01-01"},
+	}, 0)
+	require.NoError(t, err)
+
+	session, err := uc.Start(ctx, order.ID, "HH-07")
+	require.NoError(t, err)
+	require.Len(t, session.Steps, 2)
+	assert.Equal(t, "A-01-01", session.CurrentStep().Location, "without a layout steps follow location order")
+
+	picking, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusInProgress, picking.Status)
+	task, err := f.repo.GetTaskByID(ctx, session.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusInProgress, task.Status)
+	assert.Equal(t, "HH-07", task.DeviceID)
+
+	// Outro operador não assume a ordem; o mesmo operador retoma a sessão
+	_, err = uc.Start(fulfillment.WithActor(context.Background(), "operador-2"), order.ID, "HH-08")
+	assert.ErrorIs(t, err, fulfillment.ErrPickSessionExists)
+	resumed, err := uc.Start(ctx, order.ID, "HH-07")
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, resumed.ID)
+
+	// Leituras divergentes são rejeitadas sem alterar a sessão
+	_, err = uc.ScanItem(ctx, session.ID, "", "SKU-002", "")
+	assert.ErrorIs(t, err, fulfillment.ErrPickStepOutOfSequence)
+	_, err = uc.ScanLocation(ctx, session.ID, "A-01-03")
+	assert.ErrorIs(t, err, fulfillment.ErrWrongLocation)
+	_, err = uc.ScanLocation(ctx, session.ID, "a-01-01")
+	require.NoError(t, err)
+	_, err = uc.ScanItem(ctx, session.ID, "", "SKU-001", "")
+	assert.ErrorIs(t, err, fulfillment.ErrWrongSKU)
+	_, err = uc.ScanItem(ctx, session.ID, "", "SKU-002", "")
+	require.NoError(t, err)
+	_, err = uc.ConfirmQuantity(ctx, session.ID, 4, "")
+	assert.ErrorIs(t, err, fulfillment.ErrOverPick)
+	session, err = uc.ConfirmQuantity(ctx, session.ID, 3, "")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.PickStepPicked, session.Steps[0].Status)
+
+	// Segunda linha: item lido pelo código GS1 da unidade, separação parcial exige motivo
+	_, err = uc.ScanLocation(ctx, session.ID, "A-01-03")
+	require.NoError(t, err)
+	session, err = uc.ScanItem(ctx, session.ID, "0104006381333931"+"10L-9", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "L-9", session.CurrentStep().PickedBatch)
+	_, err = uc.ConfirmQuantity(ctx, session.ID, 3, "")
+	assert.ErrorIs(t, err, fulfillment.ErrShortPickReasonRequired)
+	session, err = uc.ConfirmQuantity(ctx, session.ID, 3, "caixa avariada")
+	require.NoError(t, err)
+	assert.True(t, session.Done())
+	assert.Nil(t, session.CurrentStep())
+
+	_, err = uc.ScanLocation(ctx, session.ID, "A-01-03")
+	assert.ErrorIs(t, err, fulfillment.ErrPickSessionFinished)
+
+	// A última confirmação conclui a tarefa e expede a ordem com as quantidades separadas
+	shipped, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, shipped.Status)
+	require.Len(t, shipped.Items, 2)
+	assert.Equal(t, 3, shipped.Items[0].Quantity)
+	assert.Equal(t, "L-9", shipped.Items[0].Batch)
+	assert.Equal(t, 3, shipped.Items[1].Quantity)
+	assert.Equal(t, 7, f.responder.Stock("A-01-03", "SKU-001"), "only the picked units leave stock")
+	assert.False(t, f.responder.HasReservation(order.OrderID), "the short quantity is released")
+	task, err = f.repo.GetTaskByID(ctx, session.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, task.Status)
+}
+
+func TestPickSession_NothingPickedBlocksOrder(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f, uc := newPickSessionFixture(t)
+	f.responder.SetStock("A-01-03", "SKU-001", 10)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2, Location: "A-01-03"}}, 0)
+	require.NoError(t, err)
+	session, err := uc.Start(ctx, order.ID, "HH-07")
+	require.NoError(t, err)
+
+	_, err = uc.ScanLocation(fulfillment.WithActor(context.Background(), "operador-2"), session.ID, "A-01-03")
+	assert.ErrorIs(t, err, fulfillment.ErrTaskAssignedToOther)
+
+	_, err = uc.ScanLocation(ctx, session.ID, "A-01-03")
+	require.NoError(t, err)
+	_, err = uc.ScanItem(ctx, session.ID, "", "SKU-001", "")
+	require.NoError(t, err)
+	session, err = uc.ConfirmQuantity(ctx, session.ID, 0, "endereço vazio")
+	require.NoError(t, err)
+	assert.True(t, session.Done())
+
+	blocked, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusBlocked, blocked.Status)
+	require.NotNil(t, blocked.Blocked)
+	assert.Equal(t, fulfillment.BlockMissingStock, blocked.Blocked.Reason)
+	assert.Len(t, blocked.Items, 1, "order lines are kept for a new pick after unblocking")
+
+	task, err := f.repo.GetTaskByID(ctx, session.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusPending, task.Status)
+}
+
+// failingSessionSave falha a primeira gravação da sessão concluída
+type failingSessionSave struct {
+	*memoryRepository
+	failed bool
+}
+
+func (r *failingSessionSave) UpdatePickSession(ctx context.Context, session *fulfillment.PickSession) error {
+	if session.Done() && !r.failed {
+		r.failed = true
+		return errors.New("connection reset")
+	}
+	return r.memoryRepository.UpdatePickSession(ctx, session)
+}
+
+func TestPickSession_LastConfirmationRetryAfterSaveFailure(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f, _ := newPickSessionFixture(t)
+	logger := app.NewZapLoggerAdapter(zap.NewNop())
+	uc := app.NewPickSessionUseCase(&failingSessionSave{memoryRepository: f.repo}, f.repo, f.tasks,
+		app.NewPickPathUseCase(f.repo, f.repo, logger), app.NewScanUseCase(f.repo, logger), logger)
+	f.responder.SetStock("A-01-03", "SKU-001", 10)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-3", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2, Location: "A-01-03"}}, 0)
+	require.NoError(t, err)
+	session, err := uc.Start(ctx, order.ID, "HH-07")
+	require.NoError(t, err)
+	_, err = uc.ScanLocation(ctx, session.ID, "A-01-03")
+	require.NoError(t, err)
+	_, err = uc.ScanItem(ctx, session.ID, "", "SKU-001", "")
+	require.NoError(t, err)
+
+	// A tarefa é concluída e a ordem expedida, mas a sessão não é salva
+	_, err = uc.ConfirmQuantity(ctx, session.ID, 2, "")
+	require.Error(t, err)
+
+	session, err = uc.ConfirmQuantity(ctx, session.ID, 2, "")
+	require.NoError(t, err, "the repeated confirmation only persists the session")
+	assert.True(t, session.Done())
+	shipped, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, shipped.Status)
+	assert.Equal(t, 8, f.responder.Stock("A-01-03", "SKU-001"))
+}
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func orderIDs(priorities []fulfillment.OrderPriority) []string {
+	ids := make([]string, 0, len(priorities))
+	for _, p := range priorities {
+		ids = append(ids, p.OrderID)
+	}
+	return ids
+}
+
+func TestPrioritization_WavesAndQueueFollowShipPromises(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "planejador-1")
+	f := newTaskFixture()
+	f.responder.SetStock("A-01-03", "SKU-001", 100)
+	prioritization := app.NewPrioritizationUseCase(f.repo, f.tasks, 30*time.Minute, app.NewZapLoggerAdapter(zap.NewNop()))
+	f.tasks.UsePriorities(prioritization)
+
+	// Coleta da transportadora às 10:15, de segunda a sexta
+	brt := time.FixedZone("BRT", -3*60*60)
+	at := func(hour, minute int) *time.Time {
+		tm := time.Date(2026, time.October, 19, hour, minute, 0, 0, brt)
+		return &tm
+	}
+	var cutoffs []fulfillment.CarrierCutoff
+	for day := time.Monday; day <= time.Friday; day++ {
+		cutoffs = append(cutoffs, fulfillment.CarrierCutoff{Weekday: day, Time: "10:15"})
+	}
+	calendar, err := fulfillment.NewCutoffCalendar("CORREIOS", "", cutoffs, nil, "America/Sao_Paulo")
+	require.NoError(t, err)
+	require.NoError(t, prioritization.SaveCalendar(ctx, calendar))
+
+	create := func(orderID, sku string, promise fulfillment.ShipPromise) *fulfillment.FulfillmentOrder {
+		t.Helper()
+		order, err := f.ship.CreateOrderWithPromise(ctx, orderID, "Cliente", "Rua A", []fulfillment.Item{{SKU: sku, Quantity: 1, Location: "A-01-03"}}, 0, promise)
+		require.NoError(t, err)
+		return order
+	}
+	create("SEM-PRAZO", "SKU-001", fulfillment.ShipPromise{})
+	create("EM-RISCO", "SKU-001", fulfillment.ShipPromise{ShipBy: at(11, 0)})
+	late := create("ATRASADA", "SKU-001", fulfillment.ShipPromise{ShipBy: at(9, 0)})
+	create("PREMIUM", "SKU-001", fulfillment.ShipPromise{ShipBy: at(23, 0), CustomerTier: fulfillment.TierPremium})
+	create("CORTE", "SKU-001", fulfillment.ShipPromise{ShipBy: at(16, 0), Carrier: "CORREIOS", Service: "PAC"})
+	create("SEM-ESTOQUE", "SKU-999", fulfillment.ShipPromise{ShipBy: at(8, 0)})
+
+	_, err = f.ship.CreateOrderWithPromise(ctx, "INVALIDA", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0,
+		fulfillment.ShipPromise{CustomerTier: "GOLD"})
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidShipPromise)
+
+	now := *at(10, 0)
+	ranked, err := prioritization.Rank(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SEM-ESTOQUE", "ATRASADA", "CORTE", "EM-RISCO", "PREMIUM", "SEM-PRAZO"}, orderIDs(ranked))
+	assert.Equal(t, fulfillment.RiskWillMiss, ranked[2].Risk, "the 10:15 pickup is the deadline, not the 16:00 ship-by")
+
+	report, err := prioritization.WillMissReport(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SEM-ESTOQUE", "ATRASADA", "CORTE"}, orderIDs(report))
+
+	// A onda libera as ordens mais urgentes com estoque; a fila de trabalho segue a pontuação
+	_, err = prioritization.PlanWave(ctx, 0, now)
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidWaveSize)
+	wave, err := prioritization.PlanWave(ctx, 3, now)
+	require.NoError(t, err)
+	require.Len(t, wave.Orders, 3)
+	assert.Equal(t, []string{"ATRASADA", "CORTE", "EM-RISCO"}, []string{wave.Orders[0].OrderID, wave.Orders[1].OrderID, wave.Orders[2].OrderID})
+	assert.Equal(t, []int{4, 3, 2}, []int{wave.Orders[0].TaskPriority, wave.Orders[1].TaskPriority, wave.Orders[2].TaskPriority})
+
+	next, err := f.tasks.NextTask(ctx, "A-01-03", "HH-01", nil)
+	require.NoError(t, err)
+	assert.Equal(t, late.ID, next.EntityID)
+
+	// Com o passar do tempo a ordem em risco perde o corte e sobe na fila (a tarefa aceita não muda)
+	missing, err := prioritization.Refresh(ctx, now.Add(45*time.Minute))
+	require.NoError(t, err)
+	assert.Contains(t, orderIDs(missing), "EM-RISCO")
+	atRisk, err := f.repo.GetTaskByEntity(ctx, fulfillment.EntityFulfillmentOrder, wave.Orders[2].FulfillmentOrderID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, atRisk.Priority)
+	accepted, err := f.repo.GetTaskByEntity(ctx, fulfillment.EntityFulfillmentOrder, late.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusAssigned, accepted.Status)
+
+	// Tarefas geradas fora da onda também são priorizadas pela pontuação (no horário atual)
+	shipBy := time.Now().Add(-time.Hour)
+	overdue := create("VENCIDA", "SKU-001", fulfillment.ShipPromise{ShipBy: &shipBy})
+	task, err := f.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, overdue.ID, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, task.Priority)
+}
+
+func TestPrioritization_CutoffCalendars(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	prioritization := app.NewPrioritizationUseCase(repo, nil, 30*time.Minute, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	weekday := []fulfillment.CarrierCutoff{{Weekday: time.Monday, Time: "17:00"}}
+	first, err := fulfillment.NewCutoffCalendar("CORREIOS", "SEDEX", weekday, nil, "")
+	require.NoError(t, err)
+	require.NoError(t, prioritization.SaveCalendar(ctx, first))
+
+	// Reenvio da mesma transportadora e serviço substitui o calendário mantendo o ID
+	replaced, err := fulfillment.NewCutoffCalendar("CORREIOS", "SEDEX", weekday, []string{"2026-12-25"}, "")
+	require.NoError(t, err)
+	require.NoError(t, prioritization.SaveCalendar(ctx, replaced))
+	assert.Equal(t, first.ID, replaced.ID)
+
+	other, err := fulfillment.NewCutoffCalendar("JADLOG", "", weekday, nil, "")
+	require.NoError(t, err)
+	require.NoError(t, prioritization.SaveCalendar(ctx, other))
+	assert.ErrorIs(t, prioritization.SaveCalendar(ctx, &fulfillment.CutoffCalendar{Carrier: "LOGGI"}), fulfillment.ErrInvalidCutoffCalendar)
+
+	calendars, err := prioritization.ListCalendars(ctx, "CORREIOS")
+	require.NoError(t, err)
+	require.Len(t, calendars, 1)
+	assert.Equal(t, []string{"2026-12-25"}, calendars[0].Holidays)
+
+	require.NoError(t, prioritization.DeleteCalendar(ctx, first.ID))
+	assert.ErrorIs(t, prioritization.DeleteCalendar(ctx, first.ID), fulfillment.ErrCutoffCalendarNotFound)
+	calendars, err = prioritization.ListCalendars(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, calendars, 1)
+}
+
+func TestPrioritization_RanksAllOpenOrders(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	prioritization := app.NewPrioritizationUseCase(repo, nil, 30*time.Minute, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	// Mais ordens abertas que uma página; a última criada é a mais urgente
+	const total = 1001
+	var urgent *fulfillment.FulfillmentOrder
+	for i := 0; i < total; i++ {
+		order, err := fulfillment.NewFulfillmentOrder(fmt.Sprintf("OMS-%04d", i), "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+		require.NoError(t, err)
+		urgent = order
+		require.NoError(t, repo.CreateOrder(ctx, order))
+	}
+	shipBy := time.Now().Add(-time.Hour)
+	urgent.ShipBy = &shipBy
+	require.NoError(t, repo.UpdateOrder(ctx, urgent))
+
+	ranked, err := prioritization.Rank(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, ranked, total)
+	assert.Equal(t, urgent.ID, ranked[0].FulfillmentOrderID)
+}
@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func newReplenishmentFixture(f *taskFixture) *app.ReplenishmentUseCase {
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfers := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	replenishment := app.NewReplenishmentUseCase(f.repo, f.repo, f.client, transfers, f.tasks, appLogger)
+	f.tasks.AfterComplete(replenishment.OnTaskCompleted)
+	return replenishment
+}
+
+func TestReplenishment_PrioritizesAgainstReleasedDemand(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "supervisor")
+	f := newTaskFixture()
+	uc := newReplenishmentFixture(f)
+	f.responder.SetStock("A-01-01", "SKU-001", 3)
+
+	rule, err := fulfillment.NewReplenishmentRule("SKU-001", "A-01-01", "R-09-01", fulfillment.ReplenishMinMax, 5, 20, 0)
+	require.NoError(t, err)
+	require.NoError(t, uc.DefineRule(ctx, rule))
+
+	_, err = f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2, Location: "A-01-01"}}, 0)
+	require.NoError(t, err)
+
+	report, err := uc.EvaluateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Moves, 1)
+	move := report.Moves[0]
+	assert.Equal(t, 17, move.Quantity, "fills the pick face up to max")
+	assert.Equal(t, fulfillment.ReplenishPriorityDemand, move.Priority)
+	assert.Equal(t, "R-09-01", move.From)
+
+	transfer, err := f.repo.GetTransferByID(ctx, move.TransferID)
+	require.NoError(t, err)
+	assert.Equal(t, "A-01-01", transfer.LocationTo)
+	task, err := f.repo.GetTaskByID(ctx, move.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.TaskReplenish, task.Type)
+
+	// Ordem sem saldo (backorder): nenhuma nova movimentação, mas a tarefa aberta passa à frente
+	backordered, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua B", []fulfillment.Item{{SKU: "SKU-001", Quantity: 5, Location: "A-01-01"}}, 1)
+	require.NoError(t, err)
+	require.Equal(t, fulfillment.ReservationBackordered, backordered.ReservationStatus)
+
+	report, err = uc.EvaluateAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Moves)
+	task, err = f.repo.GetTaskByID(ctx, move.TaskID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReplenishPriorityShortPick, task.Priority)
+}
+
+func TestReplenishment_TriggeredOnPickCompletion(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	uc := newReplenishmentFixture(f)
+	f.responder.SetStock("A-02-01", "SKU-002", 6)
+
+	rule, err := fulfillment.NewReplenishmentRule("SKU-002", "A-02-01", "R-09-02", fulfillment.ReplenishMinMax, 5, 10, 0)
+	require.NoError(t, err)
+	require.NoError(t, uc.DefineRule(ctx, rule))
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-3", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-002", Quantity: 2, Location: "A-02-01"}}, 0)
+	require.NoError(t, err)
+
+	report, err := uc.EvaluateAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Moves, "pick face still above min")
+
+	pick, err := f.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, order.ID, -1)
+	require.NoError(t, err)
+	_, err = f.tasks.Accept(ctx, pick.ID, "")
+	require.NoError(t, err)
+	_, err = f.tasks.Start(ctx, pick.ID)
+	require.NoError(t, err)
+	_, err = f.tasks.Complete(ctx, pick.ID, nil)
+	require.NoError(t, err)
+
+	rules, err := uc.ListRules(ctx, "A-02-01")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.NotEmpty(t, rules[0].OpenTransferID, "pick completion triggers replenishment")
+
+	transfer, err := f.repo.GetTransferByID(ctx, rules[0].OpenTransferID)
+	require.NoError(t, err)
+	assert.Equal(t, []fulfillment.Item{{SKU: "SKU-002", Quantity: 6}}, transfer.Items)
+	task, err := f.repo.GetTaskByEntity(ctx, fulfillment.EntityTransferOrder, transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReplenishPriorityTopOff, task.Priority)
+}
+
+// staleRules simula uma avaliação concorrente que leu a regra antes de a outra reivindicá-la
+type staleRules struct {
+	*memoryRepository
+}
+
+func (r *staleRules) ListReplenishmentRules(ctx context.Context, pickLocation string) ([]*fulfillment.ReplenishmentRule, error) {
+	rules, err := r.memoryRepository.ListReplenishmentRules(ctx, pickLocation)
+	for _, rule := range rules {
+		rule.OpenTransferID = ""
+	}
+	return rules, err
+}
+
+func TestReplenishment_ConcurrentEvaluationClaimsRuleOnce(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "supervisor")
+	f := newTaskFixture()
+	uc := newReplenishmentFixture(f)
+	f.responder.SetStock("A-03-01", "SKU-003", 1)
+
+	rule, err := fulfillment.NewReplenishmentRule("SKU-003", "A-03-01", "R-09-03", fulfillment.ReplenishMinMax, 5, 20, 0)
+	require.NoError(t, err)
+	require.NoError(t, uc.DefineRule(ctx, rule))
+
+	report, err := uc.EvaluateAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Moves, 1)
+
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfers := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	racing := app.NewReplenishmentUseCase(&staleRules{f.repo}, f.repo, f.client, transfers, f.tasks, appLogger)
+	report, err = racing.EvaluateAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Moves, "the rule already holds a move")
+
+	rules, err := uc.ListRules(ctx, "A-03-01")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	open, err := f.tasks.ListOpen(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, rules[0].OpenTransferID, open[0].EntityID)
+}
@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	natsAdapter "github.com/vertikon/mcp-fulfillment-ops/internal/adapters/nats"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type reservationFixture struct {
+	responder *natsAdapter.LocalInventoryResponder
+	client    *natsAdapter.InventoryRequestClient
+	repo      *memoryRepository
+	uc        *app.ShipOrderUseCase
+}
+
+func newReservationFixture() *reservationFixture {
+	logger := zap.NewNop()
+	natsLogger := natsAdapter.NewZapLoggerAdapter(logger)
+
+	responder := natsAdapter.NewLocalInventoryResponder(natsLogger)
+	client := natsAdapter.NewInventoryRequestClient(natsAdapter.NewLocalRequester(responder), time.Second, natsLogger)
+	repo := newMemoryRepository()
+
+	return &reservationFixture{
+		responder: responder,
+		client:    client,
+		repo:      repo,
+		uc:        app.NewShipOrderUseCase(repo, client, &noopPublisher{}, app.NewZapLoggerAdapter(logger)),
+	}
+}
+
+func TestReservationLifecycle_ReserveOnCreateAndReleaseOnCancel(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+	f.responder.SetStock("", "SKU-001", 10)
+
+	order, err := f.uc.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 4}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationReserved, order.ReservationStatus)
+	assert.True(t, f.responder.HasReservation("OMS-1"))
+
+	available, err := f.client.GetAvailableStock(ctx, "", "SKU-001")
+	require.NoError(t, err)
+	assert.Equal(t, 6, available)
+
+	require.NoError(t, f.uc.CancelOrder(ctx, order.ID, "customer request"))
+	assert.False(t, f.responder.HasReservation("OMS-1"))
+
+	stored, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCancelled, stored.Status)
+	assert.Equal(t, fulfillment.ReservationReleased, stored.ReservationStatus)
+
+	// Ordem cancelada não volta a reservar estoque ao tentar iniciar a separação
+	err = f.uc.StartPicking(ctx, order.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidStateTransition)
+	assert.False(t, f.responder.HasReservation("OMS-1"))
+	available, err = f.client.GetAvailableStock(ctx, "", "SKU-001")
+	require.NoError(t, err)
+	assert.Equal(t, 10, available)
+	stored, err = f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationReleased, stored.ReservationStatus)
+}
+
+func TestReservationLifecycle_BackorderReReserve(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+
+	order, err := f.uc.CreateOrder(ctx, "OMS-2", "Cliente", "Rua B", []fulfillment.Item{{SKU: "SKU-002", Quantity: 5}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationBackordered, order.ReservationStatus)
+
+	assert.ErrorIs(t, f.uc.StartPicking(ctx, order.ID), fulfillment.ErrOrderBackordered)
+
+	f.responder.SetStock("", "SKU-002", 5)
+	require.NoError(t, f.uc.ReleaseBackorder(ctx, order.ID))
+	require.NoError(t, f.uc.StartPicking(ctx, order.ID))
+	require.NoError(t, f.uc.Ship(ctx, order.ID))
+
+	stored, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationConfirmed, stored.ReservationStatus)
+	assert.Equal(t, 0, f.responder.Stock("", "SKU-002"))
+}
+
+func TestReservationReconciler_ReleasesOrphans(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+	f.responder.SetStock("", "SKU-003", 10)
+
+	order, err := f.uc.CreateOrder(ctx, "OMS-3", "Cliente", "Rua C", []fulfillment.Item{{SKU: "SKU-003", Quantity: 2}}, 0)
+	require.NoError(t, err)
+
+	// Simula reserva expirada
+	stored, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	stored.MarkReserved(time.Now().Add(-time.Minute))
+	require.NoError(t, f.repo.UpdateOrder(ctx, stored))
+
+	reconciler := app.NewReservationReconciler(f.repo, f.client, app.NewZapLoggerAdapter(zap.NewNop()))
+	released, err := reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.False(t, f.responder.HasReservation("OMS-3"))
+
+	stored, err = f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationExpired, stored.ReservationStatus)
+}
+
+func TestReservationReconciler_OrphansNotCrowdedOutByOrdersInProgress(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+	expired := time.Now().Add(-time.Minute)
+
+	// Ordens em separação com a reserva vencida, além do lote do reconciliador, não são órfãs
+	for i := 0; i < 600; i++ {
+		order, err := fulfillment.NewFulfillmentOrder(fmt.Sprintf("OMS-P%d", i), "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+		require.NoError(t, err)
+		require.NoError(t, order.StartPicking())
+		order.MarkReserved(expired)
+		require.NoError(t, f.repo.CreateOrder(ctx, order))
+	}
+	cancelled, err := fulfillment.NewFulfillmentOrder("OMS-C", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, cancelled.Cancel())
+	cancelled.MarkReserved(time.Now().Add(time.Hour))
+	require.NoError(t, f.repo.CreateOrder(ctx, cancelled))
+
+	reconciler := app.NewReservationReconciler(f.repo, f.client, app.NewZapLoggerAdapter(zap.NewNop()))
+	released, err := reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	stored, err := f.repo.GetOrderByID(ctx, cancelled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.ReservationReleased, stored.ReservationStatus)
+}
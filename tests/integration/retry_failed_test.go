@@ -0,0 +1,193 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	natsAdapter "github.com/vertikon/mcp-fulfillment-ops/internal/adapters/nats"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// flakyInventory falha AdjustStock de um SKU as próximas n vezes com erro transitório
+type flakyInventory struct {
+	app.InventoryClient
+
+	mu       sync.Mutex
+	failSKU  string
+	failures int
+	adjusted []string
+}
+
+func (f *flakyInventory) AdjustStock(ctx context.Context, location, sku string, quantity int, batch string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if sku == f.failSKU && f.failures > 0 {
+		f.failures--
+		return fmt.Errorf("%w: core inventory unavailable", fulfillment.ErrTransientFailure)
+	}
+	f.adjusted = append(f.adjusted, sku)
+	return nil
+}
+
+type retryFixture struct {
+	inventory *flakyInventory
+	repo      *memoryRepository
+	receive   *app.ReceiveGoodsUseCase
+	retry     *app.RetryFailedUseCase
+	scheduler *app.RetryScheduler
+}
+
+func newRetryFixture() *retryFixture {
+	logger := zap.NewNop()
+	natsLogger := natsAdapter.NewZapLoggerAdapter(logger)
+	appLogger := app.NewZapLoggerAdapter(logger)
+
+	responder := natsAdapter.NewLocalInventoryResponder(natsLogger)
+	client := natsAdapter.NewInventoryRequestClient(natsAdapter.NewLocalRequester(responder), time.Second, natsLogger)
+	inventory := &flakyInventory{InventoryClient: client}
+	repo := newMemoryRepository()
+
+	receive := app.NewReceiveGoodsUseCase(repo, inventory, &noopPublisher{}, appLogger)
+	ship := app.NewShipOrderUseCase(repo, inventory, &noopPublisher{}, appLogger)
+	retry := app.NewRetryFailedUseCase(repo, repo, receive, ship, nil, nil, nil, appLogger)
+
+	return &retryFixture{
+		inventory: inventory,
+		repo:      repo,
+		receive:   receive,
+		retry:     retry,
+		scheduler: app.NewRetryScheduler(retry, appLogger),
+	}
+}
+
+func TestRetryFailed_ResumesFromFailedItem(t *testing.T) {
+	ctx := context.Background()
+	f := newRetryFixture()
+	f.inventory.failSKU, f.inventory.failures = "SKU-B", 1
+
+	items := []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}, {SKU: "SKU-B", Quantity: 2}, {SKU: "SKU-C", Quantity: 3}}
+	shipment, err := f.receive.StartInbound(ctx, "ASN-1", "Fornecedor", "DOCK-1", items)
+	require.NoError(t, err)
+
+	require.Error(t, f.receive.ConfirmReceipt(ctx, shipment.ID))
+
+	failed, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusFailed, failed.Status)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, fulfillment.FailureTransient, failed.Failure.Class)
+	assert.Equal(t, fulfillment.StepAdjustStock, failed.Failure.Step)
+	assert.Equal(t, 1, failed.Failure.Item)
+	assert.Equal(t, 1, failed.Failure.Attempt)
+	assert.NotNil(t, failed.Failure.NextRetryAt)
+
+	report, err := f.retry.RetryAllSince(ctx, "", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Succeeded)
+
+	completed, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, completed.Status)
+	// SKU-A não é reaplicado na retomada
+	assert.Equal(t, []string{"SKU-A", "SKU-B", "SKU-C"}, f.inventory.adjusted)
+}
+
+func TestRetryScheduler_OnlyRetriesDueTransientFailures(t *testing.T) {
+	ctx := context.Background()
+	f := newRetryFixture()
+	f.inventory.failSKU, f.inventory.failures = "SKU-A", 1
+
+	shipment, err := f.receive.StartInbound(ctx, "ASN-2", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}})
+	require.NoError(t, err)
+	require.Error(t, f.receive.ConfirmReceipt(ctx, shipment.ID))
+
+	// Backoff ainda não expirou
+	report, err := f.scheduler.RetryDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Attempted)
+
+	failed, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Second)
+	failed.Failure.NextRetryAt = &past
+	require.NoError(t, f.repo.UpdateInbound(ctx, failed))
+
+	report, err = f.scheduler.RetryDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Succeeded)
+
+	completed, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, completed.Status)
+}
+
+func TestRetryScheduler_DueFailuresNotCrowdedOut(t *testing.T) {
+	ctx := context.Background()
+	f := newRetryFixture()
+
+	// Falhas permanentes além do lote do agendador não impedem a reexecução da falha transitória vencida
+	for i := 0; i < 600; i++ {
+		shipment, err := fulfillment.NewInboundShipment(fmt.Sprintf("ASN-P%d", i), "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}})
+		require.NoError(t, err)
+		shipment.Status = fulfillment.StatusFailed
+		shipment.Failure = fulfillment.NewFailure(fulfillment.StepAdjustStock, 0, fmt.Errorf("unknown sku"), nil)
+		require.NoError(t, f.repo.CreateInbound(ctx, shipment))
+	}
+
+	f.inventory.failSKU, f.inventory.failures = "SKU-B", 1
+	shipment, err := f.receive.StartInbound(ctx, "ASN-T", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-B", Quantity: 1}})
+	require.NoError(t, err)
+	require.Error(t, f.receive.ConfirmReceipt(ctx, shipment.ID))
+
+	failed, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Second)
+	failed.Failure.NextRetryAt = &past
+	require.NoError(t, f.repo.UpdateInbound(ctx, failed))
+
+	report, err := f.scheduler.RetryDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Attempted)
+	assert.Equal(t, 1, report.Succeeded)
+}
+
+func TestRetryScheduler_OutOfSLAFailuresNotCrowdedOut(t *testing.T) {
+	ctx := context.Background()
+	f := newRetryFixture()
+
+	// Falhas transitórias vencidas, mas fora do SLA, não ocupam o lote do agendador
+	past := time.Now().Add(-time.Second)
+	for i := 0; i < 600; i++ {
+		shipment, err := fulfillment.NewInboundShipment(fmt.Sprintf("ASN-S%d", i), "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-A", Quantity: 1}})
+		require.NoError(t, err)
+		shipment.Status = fulfillment.StatusFailed
+		shipment.CreatedAt = time.Now().Add(-48 * time.Hour)
+		shipment.UpdatedAt = time.Now().Add(-time.Hour)
+		shipment.Failure = fulfillment.NewFailure(fulfillment.StepAdjustStock, 0, fulfillment.ErrTransientFailure, nil)
+		shipment.Failure.NextRetryAt = &past
+		require.NoError(t, f.repo.CreateInbound(ctx, shipment))
+	}
+
+	f.inventory.failSKU, f.inventory.failures = "SKU-B", 1
+	shipment, err := f.receive.StartInbound(ctx, "ASN-T", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-B", Quantity: 1}})
+	require.NoError(t, err)
+	require.Error(t, f.receive.ConfirmReceipt(ctx, shipment.ID))
+
+	failed, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	failed.Failure.NextRetryAt = &past
+	require.NoError(t, f.repo.UpdateInbound(ctx, failed))
+
+	report, err := f.scheduler.RetryDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Attempted)
+	assert.Equal(t, 1, report.Succeeded)
+}
@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+	"github.com/vertikon/mcp-fulfillment-ops/pkg/gs1"
+)
+
+func TestScan_ResolveBarcodeToItemAndNormalize(t *testing.T) {
+	ctx := context.Background()
+	f, units := newUnitsFixture(t)
+	uc := app.NewScanUseCase(f.repo, app.NewZapLoggerAdapter(zap.NewNop()))
+
+	hierarchy, err := fulfillment.NewUoMHierarchy("SKU-001", []fulfillment.PackLevel{
+		{UoM: fulfillment.UoMCase, Factor: 12, GTIN: "14006381333938"},
+	})
+	require.NoError(t, err)
+	hierarchy.GTIN = "4006381333931"
+	require.NoError(t, units.DefineHierarchy(ctx, hierarchy))
+
+	// O mesmo GTIN não pode identificar outro SKU
+	other, err := fulfillment.NewUoMHierarchy("SKU-002", []fulfillment.PackLevel{{UoM: fulfillment.UoMCase, Factor: 6, GTIN: "4006381333931"}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, units.DefineHierarchy(ctx, other), fulfillment.ErrGTINInUse)
+
+	result, err := uc.Resolve(ctx, "]C1"+"0114006381333938"+"3705\x1d"+"10L-77\x1d"+"17991231")
+	require.NoError(t, err)
+	require.NotNil(t, result.Item)
+	assert.Equal(t, "SKU-001", result.Item.SKU)
+	assert.Equal(t, fulfillment.UoMCase, result.Item.UoM)
+	assert.Equal(t, 5, result.Item.Quantity)
+	assert.Equal(t, "L-77", result.Item.Batch)
+	assert.True(t, result.Expired)
+
+	// A linha lida segue a normalização de unidades dos demais casos de uso
+	normalized, err := units.Normalize(ctx, []fulfillment.Item{*result.Item})
+	require.NoError(t, err)
+	assert.Equal(t, 60, normalized[0].Quantity)
+
+	pallet, err := uc.Resolve(ctx, "00106141411234567897")
+	require.NoError(t, err)
+	assert.Nil(t, pallet.Item)
+	assert.Equal(t, "106141411234567897", pallet.SSCC)
+
+	_, err = uc.Resolve(ctx, "0107891234567895")
+	assert.ErrorIs(t, err, fulfillment.ErrUnknownGTIN)
+
+	_, err = uc.Resolve(ctx, "0114006381333939")
+	assert.ErrorIs(t, err, fulfillment.ErrInvalidBarcode)
+	assert.ErrorIs(t, err, gs1.ErrCheckDigit)
+}
@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+// staleSlotting devolve a recomendação como foi lida antes de outra aprovação
+type staleSlotting struct {
+	fulfillment.SlottingRepository
+	rec *fulfillment.SlottingRecommendation
+}
+
+func (r *staleSlotting) GetSlottingRecommendation(ctx context.Context, id string) (*fulfillment.SlottingRecommendation, error) {
+	copied := *r.rec
+	return &copied, nil
+}
+
+// failingStockLookup falha a consulta de saldo de um SKU as próximas n vezes
+type failingStockLookup struct {
+	app.InventoryClient
+	sku      string
+	failures int
+}
+
+func (f *failingStockLookup) GetAvailableStock(ctx context.Context, location, sku string) (int, error) {
+	if sku == f.sku && f.failures > 0 {
+		f.failures--
+		return 0, errors.New("core inventory unavailable")
+	}
+	return f.InventoryClient.GetAvailableStock(ctx, location, sku)
+}
+
+func TestSlotting_ApprovedRecommendationBecomesMoveTask(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfers := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	f.tasks = app.NewWarehouseTaskUseCase(f.repo, f.repo, f.receive, f.ship, transfers, nil, appLogger)
+	uc := app.NewSlottingUseCase(f.repo, f.repo, f.repo, f.client, transfers, f.tasks, appLogger)
+
+	since := time.Now().Add(-24 * time.Hour)
+	_, err := uc.Analyze(ctx, since)
+	assert.ErrorIs(t, err, fulfillment.ErrLayoutNotFound)
+
+	layout, err := fulfillment.NewWarehouseLayout([]fulfillment.Aisle{{ID: "01", X: 0, Start: 0, End: 20}}, nil, fulfillment.Point{},
+		[]fulfillment.BinCoordinate{
+			{Location: "G-1", Aisle: "01", Position: 2, Golden: true},
+			{Location: "F-1", Aisle: "01", Position: 18},
+		})
+	require.NoError(t, err)
+	require.NoError(t, f.repo.SaveLayout(ctx, layout))
+
+	f.responder.SetStock("F-1", "SKU-001", 20)
+	for _, id := range []string{"OMS-1", "OMS-2", "OMS-3"} {
+		order, err := f.ship.CreateOrder(ctx, id, "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1, Location: "F-1"}}, 0)
+		require.NoError(t, err)
+		require.NoError(t, f.ship.StartPicking(ctx, order.ID))
+		require.NoError(t, f.ship.Ship(ctx, order.ID))
+	}
+
+	analysis, err := uc.Analyze(ctx, since)
+	require.NoError(t, err)
+	require.Len(t, analysis.Velocities, 1)
+	assert.Equal(t, fulfillment.VelocityA, analysis.Velocities[0].Class)
+	require.Len(t, analysis.Recommendations, 1)
+	rec := analysis.Recommendations[0]
+	assert.Equal(t, "G-1", rec.ToLocation)
+	assert.Equal(t, 3*(36.0-4.0), rec.Savings)
+
+	// Nova análise substitui as propostas pendentes
+	again, err := uc.Analyze(ctx, since)
+	require.NoError(t, err)
+	proposed, err := uc.ListRecommendations(ctx, fulfillment.SlottingProposed)
+	require.NoError(t, err)
+	require.Len(t, proposed, 1)
+	assert.Equal(t, again.Recommendations[0].ID, proposed[0].ID)
+	rec = proposed[0]
+
+	approved, err := uc.Approve(ctx, rec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.SlottingApproved, approved.Status)
+	require.Len(t, approved.TransferIDs, 1)
+
+	task, err := f.repo.GetTaskByEntity(ctx, fulfillment.EntityTransferOrder, approved.TransferIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.TaskSlotMove, task.Type)
+	assert.Equal(t, "F-1", task.SourceLocation)
+	assert.Equal(t, "G-1", task.DestinationLocation)
+
+	_, err = f.tasks.Accept(ctx, task.ID, "RF-01")
+	require.NoError(t, err)
+	_, err = f.tasks.Start(ctx, task.ID)
+	require.NoError(t, err)
+	_, err = f.tasks.Complete(ctx, task.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, f.responder.Stock("F-1", "SKU-001"))
+	assert.Equal(t, 17, f.responder.Stock("G-1", "SKU-001"))
+
+	_, err = uc.Approve(ctx, rec.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrSlottingNotProposed)
+
+	// Uma aprovação concorrente que leu a recomendação ainda PROPOSED não cria outra transferência
+	stale := &staleSlotting{SlottingRepository: f.repo, rec: rec}
+	concurrent := app.NewSlottingUseCase(stale, f.repo, f.repo, f.client, transfers, f.tasks, appLogger)
+	f.responder.SetStock("F-1", "SKU-001", 5)
+	_, err = concurrent.Approve(ctx, rec.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrSlottingNotProposed)
+	assert.Len(t, f.repo.transfers, 1)
+	_, err = uc.Reject(ctx, "missing")
+	assert.ErrorIs(t, err, fulfillment.ErrSlottingRecommendationNotFound)
+}
+
+func TestSlotting_FailedSwapLegResumesFromPartialApproval(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	appLogger := app.NewZapLoggerAdapter(zap.NewNop())
+	transfers := app.NewCompleteTransferUseCase(f.repo, f.client, &noopPublisher{}, appLogger)
+	f.tasks = app.NewWarehouseTaskUseCase(f.repo, f.repo, f.receive, f.ship, transfers, nil, appLogger)
+	inventory := &failingStockLookup{InventoryClient: f.client, sku: "SKU-002", failures: 1}
+	uc := app.NewSlottingUseCase(f.repo, f.repo, f.repo, inventory, transfers, f.tasks, appLogger)
+
+	f.responder.SetStock("F-1", "SKU-001", 20)
+	f.responder.SetStock("G-1", "SKU-002", 4)
+	require.NoError(t, f.repo.ReplaceSlottingProposals(ctx, []*fulfillment.SlottingRecommendation{{
+		ID: "REC-1", SKU: "SKU-001", Class: fulfillment.VelocityA, FromLocation: "F-1", ToLocation: "G-1",
+		DisplacedSKU: "SKU-002", Status: fulfillment.SlottingProposed,
+	}}))
+
+	// A realocação do SKU foi criada e a troca falhou: a recomendação fica PARTIAL com a transferência criada
+	_, err := uc.Approve(ctx, "REC-1")
+	require.Error(t, err)
+	partial, err := f.repo.GetSlottingRecommendation(ctx, "REC-1")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.SlottingPartial, partial.Status)
+	require.Len(t, partial.TransferIDs, 1)
+
+	// A nova aprovação cria apenas a transferência do SKU deslocado
+	approved, err := uc.Approve(ctx, "REC-1")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.SlottingApproved, approved.Status)
+	require.Len(t, approved.TransferIDs, 2)
+	assert.Equal(t, partial.TransferIDs[0], approved.TransferIDs[0])
+	assert.Len(t, f.repo.transfers, 2)
+
+	swap, err := f.repo.GetTransferByID(ctx, approved.TransferIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, "G-1", swap.LocationFrom)
+	assert.Equal(t, "SKU-002", swap.Items[0].SKU)
+}
@@ -0,0 +1,27 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func TestTransitionMetrics_CloseRemovesHook(t *testing.T) {
+	metrics := app.NewTransitionMetrics()
+
+	first, err := fulfillment.NewTransferOrder("A-01-01", "B-01-01", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}})
+	require.NoError(t, err)
+	require.NoError(t, first.StartTransfer())
+	require.Len(t, metrics.Snapshot(), 1)
+	assert.Equal(t, int64(1), metrics.Snapshot()[0].Count)
+
+	metrics.Close()
+	second, err := fulfillment.NewTransferOrder("A-01-01", "B-01-01", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1}})
+	require.NoError(t, err)
+	require.NoError(t, second.StartTransfer())
+	assert.Equal(t, int64(1), metrics.Snapshot()[0].Count, "a closed counter no longer observes transitions")
+}
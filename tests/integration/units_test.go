@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+func newUnitsFixture(t *testing.T) (*taskFixture, *app.UnitsUseCase) {
+	f := newTaskFixture()
+	units := app.NewUnitsUseCase(f.repo, app.NewZapLoggerAdapter(zap.NewNop()))
+	f.receive.UseUnits(units)
+	f.ship.UseUnits(units)
+
+	hierarchy, err := fulfillment.NewUoMHierarchy("SKU-001", []fulfillment.PackLevel{
+		{UoM: fulfillment.UoMCase, Factor: 12},
+		{UoM: fulfillment.UoMInner, Factor: 6},
+		{UoM: fulfillment.UoMPallet, Factor: 480},
+	})
+	require.NoError(t, err)
+	require.NoError(t, units.DefineHierarchy(context.Background(), hierarchy))
+	return f, units
+}
+
+func TestUnits_ReceiptInCasesRecordsBaseUnits(t *testing.T) {
+	ctx := context.Background()
+	f, _ := newUnitsFixture(t)
+
+	shipment, err := f.receive.StartInbound(ctx, "ASN-1", "Fornecedor", "DOCK-1", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 10, UoM: fulfillment.UoMCase},
+		{SKU: "SKU-002", Quantity: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.Item{SKU: "SKU-001", Quantity: 120, UoM: fulfillment.UoMEach, PackUoM: fulfillment.UoMCase, PackQuantity: 10}, shipment.Items[0])
+	assert.Equal(t, fulfillment.Item{SKU: "SKU-002", Quantity: 3}, shipment.Items[1])
+
+	require.NoError(t, f.receive.ConfirmReceipt(ctx, shipment.ID))
+	assert.Equal(t, 120, f.responder.Stock("DOCK-1", "SKU-001"))
+	assert.Equal(t, 3, f.responder.Stock("DOCK-1", "SKU-002"))
+}
+
+func TestUnits_RejectsPartialPacksAndUnknownUnits(t *testing.T) {
+	ctx := context.Background()
+	f, units := newUnitsFixture(t)
+	f.responder.SetStock("A-01-01", "SKU-001", 100)
+
+	// 30 EA declarados em caixas de 12 não formam caixas inteiras
+	_, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 30, UoM: fulfillment.UoMEach, PackUoM: fulfillment.UoMCase, Location: "A-01-01"},
+	}, 0)
+	assert.ErrorIs(t, err, fulfillment.ErrUoMNotWholeMultiple)
+
+	// SKU sem hierarquia aceita apenas a unidade base
+	_, err = f.receive.StartInbound(ctx, "ASN-2", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-002", Quantity: 1, UoM: fulfillment.UoMCase}})
+	assert.ErrorIs(t, err, fulfillment.ErrUnknownUoM)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-2", "Cliente", "Rua A", []fulfillment.Item{
+		{SKU: "SKU-001", Quantity: 2, UoM: fulfillment.UoMInner, Location: "A-01-01"},
+	}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 12, order.Items[0].Quantity)
+	assert.Equal(t, fulfillment.ReservationReserved, order.ReservationStatus)
+
+	converted, err := units.Convert(ctx, "SKU-001", 1, fulfillment.UoMPallet, fulfillment.UoMCase)
+	require.NoError(t, err)
+	assert.Equal(t, 40, converted)
+	_, err = units.Convert(ctx, "SKU-001", 18, fulfillment.UoMEach, fulfillment.UoMCase)
+	assert.ErrorIs(t, err, fulfillment.ErrUoMNotWholeMultiple)
+}
@@ -0,0 +1,215 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	natsAdapter "github.com/vertikon/mcp-fulfillment-ops/internal/adapters/nats"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/app"
+	"github.com/vertikon/mcp-fulfillment-ops/internal/domain/fulfillment"
+)
+
+type taskFixture struct {
+	responder *natsAdapter.LocalInventoryResponder
+	client    *natsAdapter.InventoryRequestClient
+	repo      *memoryRepository
+	receive   *app.ReceiveGoodsUseCase
+	ship      *app.ShipOrderUseCase
+	tasks     *app.WarehouseTaskUseCase
+}
+
+func newTaskFixture() *taskFixture {
+	logger := zap.NewNop()
+	natsLogger := natsAdapter.NewZapLoggerAdapter(logger)
+	appLogger := app.NewZapLoggerAdapter(logger)
+
+	responder := natsAdapter.NewLocalInventoryResponder(natsLogger)
+	client := natsAdapter.NewInventoryRequestClient(natsAdapter.NewLocalRequester(responder), time.Second, natsLogger)
+	repo := newMemoryRepository()
+
+	receive := app.NewReceiveGoodsUseCase(repo, client, &noopPublisher{}, appLogger)
+	ship := app.NewShipOrderUseCase(repo, client, &noopPublisher{}, appLogger)
+	return &taskFixture{
+		responder: responder,
+		client:    client,
+		repo:      repo,
+		receive:   receive,
+		ship:      ship,
+		tasks:     app.NewWarehouseTaskUseCase(repo, repo, receive, ship, nil, nil, appLogger),
+	}
+}
+
+func TestWarehouseTasks_NextTaskDrivesUseCases(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	f.responder.SetStock("A-01-03", "SKU-001", 10)
+
+	shipment, err := f.receive.StartInbound(ctx, "ASN-1", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-002", Quantity: 5, Location: "B-02-01"}})
+	require.NoError(t, err)
+	order, err := f.ship.CreateOrder(ctx, "OMS-1", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2, Location: "A-01-03"}}, 0)
+	require.NoError(t, err)
+
+	putaway, err := f.tasks.Enqueue(ctx, fulfillment.EntityInboundShipment, shipment.ID, -1)
+	require.NoError(t, err)
+	pick, err := f.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, order.ID, -1)
+	require.NoError(t, err)
+
+	again, err := f.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, order.ID, -1)
+	require.NoError(t, err)
+	assert.Equal(t, pick.ID, again.ID, "enqueue must be idempotent per operation")
+
+	// Operador no corredor A: a separação próxima vence a armazenagem na doca
+	next, err := f.tasks.NextTask(ctx, "A-01-01", "RF-01", nil)
+	require.NoError(t, err)
+	assert.Equal(t, pick.ID, next.ID)
+	assert.Equal(t, fulfillment.StatusAssigned, next.Status)
+	assert.Equal(t, "operador-1", next.AssignedTo)
+
+	// Com tarefa ativa, next devolve a mesma tarefa
+	same, err := f.tasks.NextTask(ctx, "DOCK-1", "RF-01", nil)
+	require.NoError(t, err)
+	assert.Equal(t, pick.ID, same.ID)
+
+	_, err = f.tasks.Start(fulfillment.WithActor(context.Background(), "operador-2"), pick.ID)
+	assert.ErrorIs(t, err, fulfillment.ErrTaskAssignedToOther)
+
+	_, err = f.tasks.Start(ctx, pick.ID)
+	require.NoError(t, err)
+	picking, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusInProgress, picking.Status)
+
+	done, err := f.tasks.Complete(ctx, pick.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, done.Status)
+	shipped, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, shipped.Status)
+
+	// Próxima tarefa: a armazenagem, concluída pelo recebimento
+	next, err = f.tasks.NextTask(ctx, "", "RF-01", nil)
+	require.NoError(t, err)
+	assert.Equal(t, putaway.ID, next.ID)
+	_, err = f.tasks.Start(ctx, putaway.ID)
+	require.NoError(t, err)
+	_, err = f.tasks.Complete(ctx, putaway.ID, nil)
+	require.NoError(t, err)
+	received, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusCompleted, received.Status)
+}
+
+func TestWarehouseTasks_SkipsBlockedOperationsAndRequeuesAbandoned(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+
+	shipment, err := f.receive.StartInbound(ctx, "ASN-2", "Fornecedor", "DOCK-1", []fulfillment.Item{{SKU: "SKU-002", Quantity: 5}})
+	require.NoError(t, err)
+	task, err := f.tasks.Enqueue(ctx, fulfillment.EntityInboundShipment, shipment.ID, 1)
+	require.NoError(t, err)
+
+	blocked, err := f.repo.GetInboundByID(ctx, shipment.ID)
+	require.NoError(t, err)
+	require.NoError(t, blocked.Block(fulfillment.BlockCustomsHold, "", "supervisor"))
+	require.NoError(t, f.repo.UpdateInbound(ctx, blocked))
+
+	_, err = f.tasks.NextTask(ctx, "DOCK-1", "", nil)
+	assert.ErrorIs(t, err, fulfillment.ErrNoTaskAvailable)
+
+	require.NoError(t, blocked.ReleaseHold("alfandega"))
+	require.NoError(t, blocked.Unblock())
+	require.NoError(t, f.repo.UpdateInbound(ctx, blocked))
+
+	next, err := f.tasks.NextTask(ctx, "DOCK-1", "", []fulfillment.TaskType{fulfillment.TaskPutaway})
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, next.ID)
+
+	abandoned, err := f.tasks.Abandon(ctx, task.ID, "empilhadeira indisponível")
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatusPending, abandoned.Status)
+	assert.Empty(t, abandoned.AssignedTo)
+
+	other, err := f.tasks.NextTask(fulfillment.WithActor(context.Background(), "operador-2"), "DOCK-1", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, other.ID)
+	assert.Equal(t, "operador-2", other.AssignedTo)
+}
+
+// racingTaskLookup simula um enqueue concorrente: a primeira leitura não vê a tarefa já criada
+type racingTaskLookup struct {
+	*memoryRepository
+	missed bool
+}
+
+func (r *racingTaskLookup) GetTaskByEntity(ctx context.Context, entityType, entityID string) (*fulfillment.WarehouseTask, error) {
+	if !r.missed {
+		r.missed = true
+		return nil, fulfillment.ErrTaskNotFound
+	}
+	return r.memoryRepository.GetTaskByEntity(ctx, entityType, entityID)
+}
+
+func TestWarehouseTasks_ConcurrentEnqueueReturnsOpenTask(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-9", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 1, Location: "A-01-03"}}, 0)
+	require.NoError(t, err)
+	first, err := f.tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, order.ID, -1)
+	require.NoError(t, err)
+
+	racing := app.NewWarehouseTaskUseCase(f.repo, &racingTaskLookup{memoryRepository: f.repo}, f.receive, f.ship, nil, nil, app.NewZapLoggerAdapter(zap.NewNop()))
+	second, err := racing.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, order.ID, -1)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID, "the open-task unique index must keep a single task per operation")
+
+	open, err := f.tasks.ListOpen(ctx)
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+}
+
+// failingTaskSave falha a primeira persistência de uma tarefa concluída
+type failingTaskSave struct {
+	*memoryRepository
+	failed bool
+}
+
+func (r *failingTaskSave) UpdateTask(ctx context.Context, task *fulfillment.WarehouseTask) error {
+	if task.Status == fulfillment.StatusCompleted && !r.failed {
+		r.failed = true
+		return errors.New("connection reset")
+	}
+	return r.memoryRepository.UpdateTask(ctx, task)
+}
+
+func TestWarehouseTasks_CompleteRetryAfterFailedSaveFinishesTask(t *testing.T) {
+	ctx := fulfillment.WithActor(context.Background(), "operador-1")
+	f := newTaskFixture()
+	f.responder.SetStock("A-01-03", "SKU-001", 10)
+
+	order, err := f.ship.CreateOrder(ctx, "OMS-10", "Cliente", "Rua A", []fulfillment.Item{{SKU: "SKU-001", Quantity: 2, Location: "A-01-03"}}, 0)
+	require.NoError(t, err)
+	tasks := app.NewWarehouseTaskUseCase(f.repo, &failingTaskSave{memoryRepository: f.repo}, f.receive, f.ship, nil, nil, app.NewZapLoggerAdapter(zap.NewNop()))
+	pick, err := tasks.Enqueue(ctx, fulfillment.EntityFulfillmentOrder, order.ID, -1)
+	require.NoError(t, err)
+	_, err = tasks.Accept(ctx, pick.ID, "RF-01")
+	require.NoError(t, err)
+	_, err = tasks.Start(ctx, pick.ID)
+	require.NoError(t, err)
+
+	_, err = tasks.Complete(ctx, pick.ID, nil)
+	require.Error(t, err)
+	shipped, err := f.repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.Equal(t, fulfillment.StatusCompleted, shipped.Status)
+
+	done, err := tasks.Complete(ctx, pick.ID, nil)
+	require.NoError(t, err, "retry must finish the task instead of shipping the order again")
+	assert.Equal(t, fulfillment.StatusCompleted, done.Status)
+}